@@ -28,6 +28,7 @@ import (
 	"bitbucket.org/cpchain/chain/accounts/abi/bind"
 	"bitbucket.org/cpchain/chain/api/rpc"
 	"bitbucket.org/cpchain/chain/configs"
+	"bitbucket.org/cpchain/chain/consensus"
 	"bitbucket.org/cpchain/chain/consensus/dpor"
 	"bitbucket.org/cpchain/chain/core"
 	"bitbucket.org/cpchain/chain/core/bloombits"
@@ -60,7 +61,10 @@ type SimulatedBackend struct {
 
 	events *filters.EventSystem // Event system for filtering log events live
 
-	config *configs.ChainConfig
+	config   *configs.ChainConfig
+	engine   consensus.Engine        // Engine generating pending blocks (nil = a fresh faker per block)
+	remoteDB database.RemoteDatabase // Remote database holding private transaction payloads
+	signer   types.Signer            // Signer recovering the sender of sent transactions
 }
 
 // NewDporSimulatedBackend creates a new binding backend using a simulated blockchain
@@ -81,6 +85,7 @@ func NewDporSimulatedBackend(alloc core.GenesisAlloc) *SimulatedBackend {
 		database:   db,
 		blockchain: blockchain,
 		config:     genesis.Config,
+		signer:     types.NewCep1Signer(big.NewInt(42)),
 		events:     filters.NewEventSystem(new(event.TypeMux), &filterBackend{db, blockchain}, false),
 	}
 	backend.rollback()
@@ -94,6 +99,7 @@ func NewDporSimulatedBackendWithExistsBlockchain(db *database.MemDatabase, block
 		database:   db,
 		blockchain: blockchain,
 		config:     config,
+		signer:     types.NewCep1Signer(big.NewInt(42)),
 		events:     filters.NewEventSystem(new(event.TypeMux), &filterBackend{db, blockchain}, false),
 	}
 	backend.rollback()
@@ -121,16 +127,21 @@ func (b *SimulatedBackend) Rollback() {
 }
 
 func (b *SimulatedBackend) rollback() {
-	config := configs.ChainConfigInfo().Dpor
-	d := dpor.NewFaker(config, b.database)
-
-	blocks, _ := core.GenerateChain(b.config, b.blockchain.CurrentBlock(), d, b.database, nil, 1, func(int, *core.BlockGen) {})
+	blocks, _ := core.GenerateChain(b.config, b.blockchain.CurrentBlock(), b.pendingEngine(), b.database, b.remoteDB, 1, func(int, *core.BlockGen) {})
 	statedb, _ := b.blockchain.State()
 
 	b.pendingBlock = blocks[0]
 	b.pendingState, _ = state.New(b.pendingBlock.StateRoot(), statedb.Database())
 }
 
+// pendingEngine returns the consensus engine used to generate the pending block.
+func (b *SimulatedBackend) pendingEngine() consensus.Engine {
+	if b.engine != nil {
+		return b.engine
+	}
+	return dpor.NewFaker(configs.ChainConfigInfo().Dpor, b.database)
+}
+
 // CodeAt returns the code associated with a certain account in the blockchain.
 func (b *SimulatedBackend) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
 	b.mu.Lock()
@@ -319,7 +330,7 @@ func (b *SimulatedBackend) SendTransaction(ctx context.Context, tx *types.Transa
 	b.mu.Lock()
 	defer b.mu.Unlock()
 
-	sender, err := types.Sender(types.NewCep1Signer(big.NewInt(42)), tx)
+	sender, err := types.Sender(b.signer, tx)
 	if err != nil {
 		panic(fmt.Errorf("invalid transaction: %v", err))
 	}
@@ -328,10 +339,7 @@ func (b *SimulatedBackend) SendTransaction(ctx context.Context, tx *types.Transa
 		panic(fmt.Errorf("invalid transaction nonce: got %d, want %d", tx.Nonce(), nonce))
 	}
 
-	config := configs.ChainConfigInfo().Dpor
-	d := dpor.NewFaker(config, b.database)
-
-	blocks, _ := core.GenerateChain(b.config, b.blockchain.CurrentBlock(), d, b.database, nil, 1, func(number int, block *core.BlockGen) {
+	blocks, _ := core.GenerateChain(b.config, b.blockchain.CurrentBlock(), b.pendingEngine(), b.database, b.remoteDB, 1, func(number int, block *core.BlockGen) {
 		for _, tx := range b.pendingBlock.Transactions() {
 			block.AddTxWithChain(b.blockchain, tx)
 		}
@@ -411,12 +419,9 @@ func (b *SimulatedBackend) AdjustTime(adjustment time.Duration) error {
 	b.mu.Lock()
 	defer b.mu.Unlock()
 
-	config := configs.ChainConfigInfo().Dpor
-	d := dpor.New(config, b.database)
-
-	blocks, _ := core.GenerateChain(b.config, b.blockchain.CurrentBlock(), d, b.database, nil, 1, func(number int, block *core.BlockGen) {
+	blocks, _ := core.GenerateChain(b.config, b.blockchain.CurrentBlock(), b.pendingEngine(), b.database, b.remoteDB, 1, func(number int, block *core.BlockGen) {
 		for _, tx := range b.pendingBlock.Transactions() {
-			block.AddTx(tx)
+			block.AddTxWithChain(b.blockchain, tx)
 		}
 		block.OffsetTime(int64(adjustment.Seconds()))
 	})
@@ -0,0 +1,280 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package backends
+
+import (
+	"context"
+	"errors"
+
+	cpchain "bitbucket.org/cpchain/chain"
+	"bitbucket.org/cpchain/chain/accounts"
+	"bitbucket.org/cpchain/chain/api/rpc"
+	"bitbucket.org/cpchain/chain/configs"
+	"bitbucket.org/cpchain/chain/consensus/dpor"
+	"bitbucket.org/cpchain/chain/contracts/dpor/primitive_register"
+	"bitbucket.org/cpchain/chain/contracts/dpor/rpt_backend_holder"
+	"bitbucket.org/cpchain/chain/core"
+	"bitbucket.org/cpchain/chain/core/state"
+	"bitbucket.org/cpchain/chain/core/vm"
+	"bitbucket.org/cpchain/chain/database"
+	"bitbucket.org/cpchain/chain/internal/cpcapi"
+	"bitbucket.org/cpchain/chain/private"
+	"bitbucket.org/cpchain/chain/protocols/cpc/filters"
+	"bitbucket.org/cpchain/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+var errHeaderNotFound = errors.New("header not found")
+
+// DporSimulatedConfig is the configuration of a simulated backend running the
+// full Dpor state transition.
+type DporSimulatedConfig struct {
+	Alloc       core.GenesisAlloc    // Genesis allocation of the simulated chain
+	ChainConfig *configs.ChainConfig // Chain rules, copied before use (nil = configs.ChainConfigInfo())
+
+	// Accounts decrypts the payloads of private transactions addressed to its
+	// wallets. Private transactions are only executed in the private state if
+	// it is set (nil = private payloads are skipped).
+	Accounts *accounts.Manager
+
+	// ProxyContractRegister is the proxy contract register the EVM consults to
+	// redirect calls to proxy contracts (zero = no redirection).
+	ProxyContractRegister common.Address
+
+	// Primitives registers the RPT and PoW primitive contracts, evaluated
+	// against the simulated chain.
+	Primitives bool
+}
+
+// NewDporSimulatedBackendWithConfig creates a new binding backend whose blocks are
+// imported by the real StateProcessor of a Dpor faker, with private payloads
+// stored in a fake IPFS database.
+//
+// Primitive contracts and private transaction support are process wide, so
+// the most recently created backend takes them over.
+func NewDporSimulatedBackendWithConfig(cfg DporSimulatedConfig) *SimulatedBackend {
+	chainConfig := cfg.ChainConfig
+	if chainConfig == nil {
+		chainConfig = configs.ChainConfigInfo()
+	}
+	// copy the config, it is adjusted by the backend
+	config := *chainConfig
+	dporConfig := *chainConfig.Dpor
+	dporConfig.ProxyContractRegister = cfg.ProxyContractRegister
+	config.Dpor = &dporConfig
+
+	db := database.NewMemDatabase()
+	genesis := core.DefaultGenesisBlock()
+	genesis.Config = &config
+	genesis.Alloc = cfg.Alloc
+	genesis.MustCommit(db)
+
+	if cfg.Accounts != nil {
+		private.SupportPrivateTx = "true"
+	}
+
+	remoteDB := database.NewIpfsDbWithAdapter(database.NewFakeIpfsAdapter())
+	d := dpor.NewFaker(config.Dpor, db)
+
+	blockchain, err := core.NewBlockChain(db, nil, &config, d, vm.Config{}, remoteDB, cfg.Accounts)
+	if err != nil {
+		panic(err)
+	}
+
+	backend := &SimulatedBackend{
+		database:   db,
+		blockchain: blockchain,
+		config:     &config,
+		engine:     d,
+		remoteDB:   remoteDB,
+		signer:     types.MakeSigner(&config),
+		events:     filters.NewEventSystem(new(event.TypeMux), &filterBackend{db, blockchain}, false),
+	}
+	if cfg.Primitives {
+		backend.registerPrimitiveContracts()
+	}
+	backend.rollback()
+	return backend
+}
+
+// registerPrimitiveContracts binds the RPT and PoW primitive contracts to the
+// simulated chain, replacing the ones registered before.
+func (b *SimulatedBackend) registerPrimitiveContracts() {
+	chainAPI := &simulatedChainAPI{b}
+	client := &rpt_backend_holder.RptApiClient{ChainBackend: chainAPI, ContractBackend: chainAPI}
+	for addr, c := range primitive_register.MakePrimitiveContracts(client, client) {
+		vm.PrimitiveContracts[addr] = c
+	}
+}
+
+// RemoteDB returns the remote database holding the private transaction payloads,
+// it is nil unless the backend is created by NewDporSimulatedBackendWithConfig.
+func (b *SimulatedBackend) RemoteDB() database.RemoteDatabase {
+	return b.remoteDB
+}
+
+// SetProxyContractRegister changes the proxy contract register the EVM consults
+// to redirect calls to proxy contracts.
+func (b *SimulatedBackend) SetProxyContractRegister(register common.Address) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.config.Dpor.ProxyContractRegister = register
+}
+
+// CommitBlocks imports the pending block followed by n-1 empty blocks.
+func (b *SimulatedBackend) CommitBlocks(n int) {
+	for i := 0; i < n; i++ {
+		b.Commit()
+	}
+}
+
+// Term returns the term of the pending block.
+func (b *SimulatedBackend) Term() uint64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.termOf(b.pendingBlock.NumberU64())
+}
+
+// AdvanceTerm imports the pending block and as many empty blocks as needed for
+// the next pending block to open a new term, and returns the new term.
+func (b *SimulatedBackend) AdvanceTerm() uint64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	term := b.termOf(b.pendingBlock.NumberU64()) + 1
+	for b.termOf(b.pendingBlock.NumberU64()) < term {
+		if _, err := b.blockchain.InsertChain([]*types.Block{b.pendingBlock}); err != nil {
+			panic(err) // This cannot happen unless the simulator is wrong, fail in that case
+		}
+		b.rollback()
+	}
+	return term
+}
+
+// termOf returns the term of the given block number, following DporSnapshot.TermOf.
+func (b *SimulatedBackend) termOf(number uint64) uint64 {
+	termLen := b.config.Dpor.TermLen * b.config.Dpor.ViewLen
+	if number == 0 || termLen == 0 {
+		return 0
+	}
+	return (number - 1) / termLen
+}
+
+// PrivateCodeAt returns the code associated with a certain account in the private
+// state of the latest block.
+func (b *SimulatedBackend) PrivateCodeAt(ctx context.Context, contract common.Address) ([]byte, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	statedb, err := b.blockchain.StatePriv()
+	if err != nil {
+		return nil, err
+	}
+	return statedb.GetCode(contract), nil
+}
+
+// PrivateStorageAt returns the value of key in the storage of an account in the
+// private state of the latest block.
+func (b *SimulatedBackend) PrivateStorageAt(ctx context.Context, contract common.Address, key common.Hash) ([]byte, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	statedb, err := b.blockchain.StatePriv()
+	if err != nil {
+		return nil, err
+	}
+	val := statedb.GetState(contract, key)
+	return val[:], nil
+}
+
+// PrivateCallContract executes a contract call on the private state of the latest block.
+func (b *SimulatedBackend) PrivateCallContract(ctx context.Context, call cpchain.CallMsg) ([]byte, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	statedb, err := b.blockchain.StatePriv()
+	if err != nil {
+		return nil, err
+	}
+	rval, _, _, err := b.callContract(ctx, call, b.blockchain.CurrentBlock(), statedb)
+	return rval, err
+}
+
+// PrivateTransactionReceipt returns the receipt of a private transaction executed
+// in the private state.
+func (b *SimulatedBackend) PrivateTransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	return core.ReadPrivateReceipt(txHash, b.database)
+}
+
+// simulatedChainAPI implements the chain and contract APIs the RPT primitive
+// contracts read from. It does not take the backend lock, since primitive
+// contracts are evaluated while a block is imported.
+type simulatedChainAPI struct {
+	b *SimulatedBackend
+}
+
+func (api *simulatedChainAPI) HeaderByNumber(ctx context.Context, number rpc.BlockNumber) (*types.Header, error) {
+	if number < 0 {
+		return api.b.blockchain.CurrentHeader(), nil
+	}
+	return api.b.blockchain.GetHeaderByNumber(uint64(number)), nil
+}
+
+func (api *simulatedChainAPI) BlockByNumber(ctx context.Context, number rpc.BlockNumber) (*types.Block, error) {
+	if number < 0 {
+		return api.b.blockchain.CurrentBlock(), nil
+	}
+	return api.b.blockchain.GetBlockByNumber(uint64(number)), nil
+}
+
+func (api *simulatedChainAPI) StateAndHeaderByNumber(ctx context.Context, number rpc.BlockNumber, isPrivate bool) (*state.StateDB, *types.Header, error) {
+	header, _ := api.HeaderByNumber(ctx, number)
+	if header == nil {
+		return nil, nil, errHeaderNotFound
+	}
+	if isPrivate {
+		statedb, err := api.b.blockchain.StatePrivAt(header.StateRoot)
+		return statedb, header, err
+	}
+	statedb, err := api.b.blockchain.StateAt(header.StateRoot)
+	return statedb, header, err
+}
+
+func (api *simulatedChainAPI) Call(ctx context.Context, args cpcapi.CallArgs, number rpc.BlockNumber) (hexutil.Bytes, error) {
+	block, _ := api.BlockByNumber(ctx, number)
+	if block == nil {
+		return nil, errHeaderNotFound
+	}
+	statedb, _, err := api.StateAndHeaderByNumber(ctx, rpc.BlockNumber(block.NumberU64()), args.IsPrivate)
+	if err != nil {
+		return nil, err
+	}
+	call := cpchain.CallMsg{
+		From:     args.From,
+		To:       args.To,
+		Gas:      uint64(args.Gas),
+		GasPrice: args.GasPrice.ToInt(),
+		Value:    args.Value.ToInt(),
+		Data:     args.Data,
+	}
+	rval, _, _, err := api.b.callContract(ctx, call, block, statedb)
+	return rval, err
+}
@@ -0,0 +1,61 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package backends
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"bitbucket.org/cpchain/chain/core"
+	"bitbucket.org/cpchain/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestDporSimulatedBackendAdvanceTerm(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+
+	sim := NewDporSimulatedBackendWithConfig(DporSimulatedConfig{
+		Alloc: core.GenesisAlloc{addr: {Balance: big.NewInt(1000000000000)}},
+	})
+	if term := sim.Term(); term != 0 {
+		t.Fatalf("term mismatch: have %d, want %d", term, 0)
+	}
+
+	to := common.HexToAddress("0x0102030405060708091011121314151617181920")
+	tx, _ := types.SignTx(types.NewTransaction(0, to, big.NewInt(1000), 21000, big.NewInt(1), nil), sim.signer, key)
+	if err := sim.SendTransaction(context.Background(), tx); err != nil {
+		t.Fatalf("failed to send transaction: %v", err)
+	}
+
+	if term := sim.AdvanceTerm(); term != 1 {
+		t.Fatalf("term mismatch: have %d, want %d", term, 1)
+	}
+	termLen := sim.config.Dpor.TermLen * sim.config.Dpor.ViewLen
+	if head := sim.Blockchain().CurrentBlock().NumberU64(); head != termLen {
+		t.Fatalf("head mismatch: have %d, want %d", head, termLen)
+	}
+	balance, _ := sim.BalanceAt(context.Background(), to, nil)
+	if balance.Cmp(big.NewInt(1000)) != 0 {
+		t.Fatalf("balance mismatch: have %v, want %v", balance, 1000)
+	}
+	if receipt, _ := sim.TransactionReceipt(context.Background(), tx.Hash()); receipt == nil {
+		t.Fatalf("receipt of committed transaction missing")
+	}
+}
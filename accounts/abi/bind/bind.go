@@ -24,6 +24,7 @@ import (
 	"bytes"
 	"fmt"
 	"regexp"
+	"sort"
 	"strings"
 	"text/template"
 	"unicode"
@@ -45,7 +46,10 @@ const (
 // manually maintain hard coded strings that break on runtime.
 func Bind(types []string, abis []string, bytecodes []string, pkg string, lang Lang) (string, error) {
 	// Process each individual contract requested binding
-	contracts := make(map[string]*tmplContract)
+	var (
+		contracts = make(map[string]*tmplContract)
+		structs   = make(map[string]*tmplStruct)
+	)
 
 	for i := 0; i < len(types); i++ {
 		// Parse the actual ABI to generate the binding for
@@ -86,6 +90,10 @@ func Bind(types []string, abis []string, bytecodes []string, pkg string, lang La
 					normalized.Outputs[j].Name = capitalise(output.Name)
 				}
 			}
+			// Collect the tuples used as parameters or results
+			for _, arg := range append(original.Inputs, original.Outputs...) {
+				collectStructs(arg.Type, structs)
+			}
 			// Append the methods to the call or transact lists
 			if original.Const {
 				calls[original.Name] = &tmplMethod{Original: original, Normalized: normalized, Structured: structured(original.Outputs)}
@@ -112,9 +120,17 @@ func Bind(types []string, abis []string, bytecodes []string, pkg string, lang La
 					}
 				}
 			}
+			for _, input := range original.Inputs {
+				if !input.Indexed {
+					collectStructs(input.Type, structs)
+				}
+			}
 			// Append the event to the accumulator list
 			events[original.Name] = &tmplEvent{Original: original, Normalized: normalized}
 		}
+		for _, input := range evmABI.Constructor.Inputs {
+			collectStructs(input.Type, structs)
+		}
 		contracts[types[i]] = &tmplContract{
 			Type:        capitalise(types[i]),
 			InputABI:    strings.Replace(strippedABI, "\"", "\\\"", -1),
@@ -125,10 +141,13 @@ func Bind(types []string, abis []string, bytecodes []string, pkg string, lang La
 			Events:      events,
 		}
 	}
+	nameStructs(structs, lang)
+
 	// Generate the contract template data content and render it
 	data := &tmplData{
 		Package:   pkg,
 		Contracts: contracts,
+		Structs:   structs,
 	}
 	buffer := new(bytes.Buffer)
 
@@ -157,99 +176,139 @@ func Bind(types []string, abis []string, bytecodes []string, pkg string, lang La
 
 // bindType is a set of type binders that convert Solidity types to some supported
 // programming language types.
-var bindType = map[Lang]func(kind abi.Type) string{
+var bindType = map[Lang]func(kind abi.Type, structs map[string]*tmplStruct) string{
 	LangGo: bindTypeGo,
 }
 
-// Helper function for the binding generators.
-// It reads the unmatched characters after the inner type-match,
-//  (since the inner type is a prefix of the total type declaration),
-//  looks for valid arrays (possibly a dynamic one) wrapping the inner type,
-//  and returns the sizes of these arrays.
-//
-// Returned array sizes are in the same order as solidity signatures; inner array size first.
-// Array sizes may also be "", indicating a dynamic array.
-func wrapArray(stringKind string, innerLen int, innerMapping string) (string, []string) {
-	remainder := stringKind[innerLen:]
-	//find all the sizes
-	matches := regexp.MustCompile(`\[(\d*)\]`).FindAllStringSubmatch(remainder, -1)
-	parts := make([]string, 0, len(matches))
-	for _, match := range matches {
-		//get group 1 from the regex match
-		parts = append(parts, match[1])
-	}
-	return innerMapping, parts
-}
-
-// Translates the array sizes to a Go-lang declaration of a (nested) array of the inner type.
-// Simply returns the inner type if arraySizes is empty.
-func arrayBindingGo(inner string, arraySizes []string) string {
-	out := ""
-	//prepend all array sizes, from outer (end arraySizes) to inner (start arraySizes)
-	for i := len(arraySizes) - 1; i >= 0; i-- {
-		out += "[" + arraySizes[i] + "]"
+// bindBasicTypeGo converts a non-composite Solidity type to a Go one.
+func bindBasicTypeGo(kind abi.Type) string {
+	switch kind.T {
+	case abi.AddressTy:
+		return "common.Address"
+	case abi.IntTy, abi.UintTy:
+		parts := regexp.MustCompile(`(u)?int([0-9]*)`).FindStringSubmatch(kind.String())
+		switch parts[2] {
+		case "8", "16", "32", "64":
+			return fmt.Sprintf("%sint%s", parts[1], parts[2])
+		}
+		return "*big.Int"
+	case abi.FixedBytesTy:
+		return fmt.Sprintf("[%d]byte", kind.Size)
+	case abi.BytesTy:
+		return "[]byte"
+	case abi.FunctionTy:
+		return "[24]byte"
+	default:
+		// string and bool map to themselves
+		return kind.String()
 	}
-	out += inner
-	return out
 }
 
 // bindTypeGo converts a Solidity type to a Go one. Since there is no clear mapping
 // from all Solidity types to Go ones (e.g. uint17), those that cannot be exactly
-// mapped will use an upscaled type (e.g. *big.Int).
-func bindTypeGo(kind abi.Type) string {
-	stringKind := kind.String()
-	innerLen, innerMapping := bindUnnestedTypeGo(stringKind)
-	return arrayBindingGo(wrapArray(stringKind, innerLen, innerMapping))
-}
-
-// The inner function of bindTypeGo, this finds the inner type of stringKind.
-// (Or just the type itself if it is not an array or slice)
-// The length of the matched part is returned, with the the translated type.
-func bindUnnestedTypeGo(stringKind string) (int, string) {
-
-	switch {
-	case strings.HasPrefix(stringKind, "address"):
-		return len("address"), "common.Address"
-
-	case strings.HasPrefix(stringKind, "bytes"):
-		parts := regexp.MustCompile(`bytes([0-9]*)`).FindStringSubmatch(stringKind)
-		return len(parts[0]), fmt.Sprintf("[%s]byte", parts[1])
-
-	case strings.HasPrefix(stringKind, "int") || strings.HasPrefix(stringKind, "uint"):
-		parts := regexp.MustCompile(`(u)?int([0-9]*)`).FindStringSubmatch(stringKind)
-		switch parts[2] {
-		case "8", "16", "32", "64":
-			return len(parts[0]), fmt.Sprintf("%sint%s", parts[1], parts[2])
-		}
-		return len(parts[0]), "*big.Int"
-
-	case strings.HasPrefix(stringKind, "bool"):
-		return len("bool"), "bool"
-
-	case strings.HasPrefix(stringKind, "string"):
-		return len("string"), "string"
-
+// mapped will use an upscaled type (e.g. *big.Int). Tuples map to the structs
+// collected by collectStructs.
+func bindTypeGo(kind abi.Type, structs map[string]*tmplStruct) string {
+	switch kind.T {
+	case abi.TupleTy:
+		return structs[structKey(kind)].Name
+	case abi.ArrayTy:
+		return fmt.Sprintf("[%d]", kind.Size) + bindTypeGo(*kind.Elem, structs)
+	case abi.SliceTy:
+		return "[]" + bindTypeGo(*kind.Elem, structs)
 	default:
-		return len(stringKind), stringKind
+		return bindBasicTypeGo(kind)
 	}
 }
 
 // bindTopicType is a set of type binders that convert Solidity types to some
 // supported programming language topic types.
-var bindTopicType = map[Lang]func(kind abi.Type) string{
+var bindTopicType = map[Lang]func(kind abi.Type, structs map[string]*tmplStruct) string{
 	LangGo: bindTopicTypeGo,
 }
 
 // bindTypeGo converts a Solidity topic type to a Go one. It is almost the same
 // funcionality as for simple types, but dynamic types get converted to hashes.
-func bindTopicTypeGo(kind abi.Type) string {
-	bound := bindTypeGo(kind)
+func bindTopicTypeGo(kind abi.Type, structs map[string]*tmplStruct) string {
+	if kind.T == abi.TupleTy {
+		return "common.Hash"
+	}
+	bound := bindTypeGo(kind, structs)
 	if bound == "string" || bound == "[]byte" {
 		bound = "common.Hash"
 	}
 	return bound
 }
 
+// structKey identifies a tuple by its component names and types, tuples with
+// the same key share a generated struct.
+func structKey(kind abi.Type) string {
+	fields := make([]string, len(kind.TupleElems))
+	for i, elem := range kind.TupleElems {
+		fields[i] = kind.TupleRawNames[i] + " " + typeKey(*elem)
+	}
+	return "(" + strings.Join(fields, ",") + ")"
+}
+
+// typeKey is the canonical type of a tuple component, including the names of
+// nested tuple components.
+func typeKey(kind abi.Type) string {
+	switch kind.T {
+	case abi.TupleTy:
+		return structKey(kind)
+	case abi.SliceTy:
+		return typeKey(*kind.Elem) + "[]"
+	case abi.ArrayTy:
+		return fmt.Sprintf("%s[%d]", typeKey(*kind.Elem), kind.Size)
+	default:
+		return kind.String()
+	}
+}
+
+// collectStructs records every tuple, including nested ones, reachable from
+// the given type.
+func collectStructs(kind abi.Type, structs map[string]*tmplStruct) {
+	switch kind.T {
+	case abi.SliceTy, abi.ArrayTy:
+		collectStructs(*kind.Elem, structs)
+	case abi.TupleTy:
+		key := structKey(kind)
+		if _, ok := structs[key]; ok {
+			return
+		}
+		structs[key] = &tmplStruct{kind: kind}
+		for _, elem := range kind.TupleElems {
+			collectStructs(*elem, structs)
+		}
+	}
+}
+
+// nameStructs assigns names to the collected structs in a deterministic order,
+// the ABI doesn't carry the original struct names, and binds their fields.
+func nameStructs(structs map[string]*tmplStruct, lang Lang) {
+	keys := make([]string, 0, len(structs))
+	for key := range structs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for i, key := range keys {
+		structs[key].Name = fmt.Sprintf("Struct%d", i)
+	}
+	for _, key := range keys {
+		s := structs[key]
+		for i, elem := range s.kind.TupleElems {
+			raw := s.kind.TupleRawNames[i]
+			field := &tmplField{Name: capitalise(raw), Type: bindType[lang](*elem, structs)}
+			// the abi package matches fields by capitalised name only, so
+			// camel-cased names need an explicit tag
+			if strings.Contains(strings.TrimLeft(raw, "_"), "_") {
+				field.Tag = raw
+			}
+			s.Fields = append(s.Fields, field)
+		}
+	}
+}
+
 // namedType is a set of functions that transform language specific types to
 // named versions that my be used inside method names.
 var namedType = map[Lang]func(string, abi.Type) string{
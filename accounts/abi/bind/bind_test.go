@@ -148,6 +148,49 @@ var bindTests = []struct {
 			 fmt.Println(str1, str2, res.Str1, res.Str2, err)
 		 }`,
 	},
+	// Tests that tuples are bound to generated structs
+	{
+		`Tupler`, ``, ``,
+		`
+			[
+				{"type":"function","name":"getOrder","constant":true,"inputs":[],"outputs":[{"name":"order","type":"tuple","components":[{"name":"maker","type":"address"},{"name":"amounts","type":"uint256[]"},{"name":"fill_info","type":"tuple","components":[{"name":"filled","type":"uint256"},{"name":"note","type":"string"}]}]}]},
+				{"type":"function","name":"setOrders","constant":false,"inputs":[{"name":"orders","type":"tuple[]","components":[{"name":"maker","type":"address"},{"name":"amounts","type":"uint256[]"},{"name":"fill_info","type":"tuple","components":[{"name":"filled","type":"uint256"},{"name":"note","type":"string"}]}]}],"outputs":[]},
+				{"type":"event","name":"filled","inputs":[{"name":"maker","type":"address","indexed":true},{"name":"info","type":"tuple","indexed":false,"components":[{"name":"filled","type":"uint256"},{"name":"note","type":"string"}]}]}
+			]
+		`,
+		`if b, err := NewTupler(common.Address{}, nil); b == nil || err != nil {
+			 t.Fatalf("binding (%v) nil or error (%v) not nil", b, nil)
+		 } else if false { // Don't run, just compile and test types
+			 var (
+				 order Struct1
+				 fill  Struct0
+				 err   error
+			 )
+			 order, err = b.GetOrder(nil)
+			 _, err     = b.SetOrders(nil, []Struct1{order})
+			 fill       = order.FillInfo
+
+			 event := new(TuplerFilled)
+			 fill = event.Info
+
+			 fmt.Println(order.Maker, order.Amounts, fill.Filled, fill.Note, err)
+		 }
+		 // Generated structs are matched to the unpacked tuples
+		 parsed, _ := abi.JSON(strings.NewReader(TuplerABI))
+		 method := parsed.Methods["getOrder"]
+		 order := Struct1{Maker: common.Address{1}, Amounts: []*big.Int{big.NewInt(2)}, FillInfo: Struct0{Filled: big.NewInt(3), Note: "note"}}
+		 data, err := method.Outputs.Pack(order)
+		 if err != nil {
+			 t.Fatalf("failed to pack order: %v", err)
+		 }
+		 var out Struct1
+		 if err := parsed.Unpack(&out, "getOrder", data); err != nil {
+			 t.Fatalf("failed to unpack order: %v", err)
+		 }
+		 if out.Maker != order.Maker || out.FillInfo.Note != "note" || out.FillInfo.Filled.Int64() != 3 || out.Amounts[0].Int64() != 2 {
+			 t.Fatalf("order mismatch: have %+v, want %+v", out, order)
+		 }`,
+	},
 	// Tests that named, anonymous and indexed events are handled correctly
 	{
 		`EventChecker`, ``, ``,
@@ -740,6 +783,47 @@ var bindTests = []struct {
 				t.Fatalf("unsubscribed simple event arrived: %v", event)
 			case <-time.After(250 * time.Millisecond):
 			}
+			// Test streaming the events, the past ones are backfilled before the new ones
+			stream := make(chan *EventerSimpleEvent, 16)
+			cursor := bind.NewMemoryCursorStore()
+			ssub, err := eventer.StreamSimpleEvent(&bind.StreamOpts{Cursor: cursor}, stream, nil, nil, nil)
+			if err != nil {
+				t.Fatalf("failed to stream simple events: %v", err)
+			}
+			for want := uint64(254); ; {
+				select {
+				case event := <-stream:
+					if event.Raw.Removed {
+						t.Fatalf("streamed event removed: %v", event)
+					}
+					if event.Value.Uint64() != want {
+						continue
+					}
+				case <-time.After(time.Second):
+					t.Fatalf("streamed simple event %d didn't arrive", want)
+				}
+				if want == 253 {
+					break
+				}
+				if _, err := eventer.RaiseSimpleEvent(auth, common.Address{253}, [32]byte{253}, true, big.NewInt(253)); err != nil {
+					t.Fatalf("failed to raise streamed simple event: %v", err)
+				}
+				sim.Commit()
+				want = 253
+			}
+			ssub.Unsubscribe()
+
+			// A resumed stream starts after the delivered events
+			ssub, err = eventer.StreamSimpleEvent(&bind.StreamOpts{Cursor: cursor}, stream, nil, nil, nil)
+			if err != nil {
+				t.Fatalf("failed to resume simple event stream: %v", err)
+			}
+			defer ssub.Unsubscribe()
+			select {
+			case event := <-stream:
+				t.Fatalf("delivered simple event streamed again: %v", event)
+			case <-time.After(250 * time.Millisecond):
+			}
 		`,
 	},
 	{
@@ -0,0 +1,373 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package bind
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"bitbucket.org/cpchain/chain"
+	"bitbucket.org/cpchain/chain/commons/log"
+	"bitbucket.org/cpchain/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+const (
+	// DefaultReorgDepth is the number of blocks whose delivered logs are
+	// remembered to be reverted on reorg.
+	DefaultReorgDepth = 64
+
+	// DefaultRetryDelay is the delay before an interrupted stream resumes.
+	DefaultRetryDelay = 5 * time.Second
+)
+
+var (
+	errStreamClosed = errors.New("event stream closed")
+	errSubClosed    = errors.New("log subscription closed")
+)
+
+// StreamOpts is the collection of options to fine tune streaming events of a
+// bound contract.
+type StreamOpts struct {
+	Start      uint64        // First block streamed if the cursor is empty
+	Cursor     CursorStore   // Persisted position of the stream (nil = in memory)
+	ReorgDepth uint64        // Blocks whose logs can be reverted (0 = DefaultReorgDepth)
+	RetryDelay time.Duration // Delay before resuming after an error (0 = DefaultRetryDelay)
+
+	Context context.Context // Network context to support cancellation and timeouts (nil = no timeout)
+}
+
+// Cursor is the position of an event stream. Logs from (Block, Index) on are
+// not delivered yet, Recent are the delivered logs which may still be reverted.
+type Cursor struct {
+	Block  uint64      `json:"block"`
+	Index  uint        `json:"index"`
+	Recent []types.Log `json:"recent"`
+}
+
+// CursorStore persists the cursor of an event stream, so that it resumes
+// where it stopped.
+type CursorStore interface {
+	// LoadCursor returns the saved cursor, or nil if there is none.
+	LoadCursor() (*Cursor, error)
+
+	// SaveCursor saves the cursor, it is called after every delivered log.
+	SaveCursor(cursor *Cursor) error
+}
+
+type memoryCursorStore struct {
+	mu     sync.Mutex
+	cursor *Cursor
+}
+
+// NewMemoryCursorStore returns a cursor store living as long as the process.
+func NewMemoryCursorStore() CursorStore {
+	return new(memoryCursorStore)
+}
+
+func (s *memoryCursorStore) LoadCursor() (*Cursor, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cursor == nil {
+		return nil, nil
+	}
+	return s.cursor.copy(), nil
+}
+
+func (s *memoryCursorStore) SaveCursor(cursor *Cursor) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.cursor = cursor.copy()
+	return nil
+}
+
+type fileCursorStore struct {
+	path string
+}
+
+// NewFileCursorStore returns a cursor store keeping the cursor as JSON in the
+// file at path.
+func NewFileCursorStore(path string) CursorStore {
+	return &fileCursorStore{path: path}
+}
+
+func (s *fileCursorStore) LoadCursor() (*Cursor, error) {
+	blob, err := ioutil.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	cursor := new(Cursor)
+	if err := json.Unmarshal(blob, cursor); err != nil {
+		return nil, err
+	}
+	return cursor, nil
+}
+
+func (s *fileCursorStore) SaveCursor(cursor *Cursor) error {
+	// logs without topics must be stored as [], not null
+	cursor = cursor.copy()
+	for i := range cursor.Recent {
+		if cursor.Recent[i].Topics == nil {
+			cursor.Recent[i].Topics = []common.Hash{}
+		}
+	}
+	blob, err := json.Marshal(cursor)
+	if err != nil {
+		return err
+	}
+	// write a temporary file first, a crash must not leave a partial cursor
+	tmp, err := ioutil.TempFile(filepath.Dir(s.path), "."+filepath.Base(s.path)+".tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(blob); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	tmp.Close()
+	return os.Rename(tmp.Name(), s.path)
+}
+
+func (c *Cursor) copy() *Cursor {
+	cpy := *c
+	cpy.Recent = append([]types.Log(nil), c.Recent...)
+	return &cpy
+}
+
+// before reports whether the log comes before the cursor.
+func (c *Cursor) before(l *types.Log) bool {
+	return l.BlockNumber < c.Block || (l.BlockNumber == c.Block && l.Index < c.Index)
+}
+
+// delivered returns the position of l in the recent logs, or -1.
+func (c *Cursor) delivered(l *types.Log) int {
+	for i := range c.Recent {
+		if c.Recent[i].BlockHash == l.BlockHash && c.Recent[i].Index == l.Index {
+			return i
+		}
+	}
+	return -1
+}
+
+// streamError is an error the stream can't recover from by resubscribing.
+type streamError struct {
+	err error
+}
+
+func (e *streamError) Error() string {
+	return e.err.Error()
+}
+
+// EventStream delivers the logs matching a filter query exactly once and in
+// order. Past logs are backfilled before the new ones, the stream resumes from
+// its cursor after errors or restarts, and a delivered log reverted by a
+// reorg is delivered again with Removed set.
+type EventStream struct {
+	filterer ContractFilterer
+	query    cpchain.FilterQuery
+	opts     StreamOpts
+	store    CursorStore
+	cursor   *Cursor
+	sink     chan<- types.Log
+}
+
+// NewEventStream starts streaming the logs matching query into sink, the
+// block range of query is ignored. The subscription ends with an error only
+// if the cursor can't be saved.
+func NewEventStream(filterer ContractFilterer, query cpchain.FilterQuery, opts *StreamOpts, sink chan<- types.Log) (event.Subscription, error) {
+	if opts == nil {
+		opts = new(StreamOpts)
+	}
+	s := &EventStream{
+		filterer: filterer,
+		query:    query,
+		opts:     *opts,
+		store:    opts.Cursor,
+		sink:     sink,
+	}
+	if s.opts.ReorgDepth == 0 {
+		s.opts.ReorgDepth = DefaultReorgDepth
+	}
+	if s.opts.RetryDelay == 0 {
+		s.opts.RetryDelay = DefaultRetryDelay
+	}
+	if s.store == nil {
+		s.store = NewMemoryCursorStore()
+	}
+	cursor, err := s.store.LoadCursor()
+	if err != nil {
+		return nil, err
+	}
+	if cursor == nil {
+		cursor = &Cursor{Block: opts.Start}
+	}
+	s.cursor = cursor
+	return event.NewSubscription(s.loop), nil
+}
+
+func (s *EventStream) loop(quit <-chan struct{}) error {
+	for {
+		err := s.stream(quit)
+		if err == errStreamClosed {
+			return nil
+		}
+		if err, ok := err.(*streamError); ok {
+			return err.err
+		}
+		log.Warn("Event stream interrupted", "block", s.cursor.Block, "err", err, "retry", s.opts.RetryDelay)
+		select {
+		case <-time.After(s.opts.RetryDelay):
+		case <-quit:
+			return nil
+		}
+	}
+}
+
+// stream subscribes to new logs, backfills the past ones since the cursor
+// and forwards the new ones until the subscription fails.
+func (s *EventStream) stream(quit <-chan struct{}) error {
+	ctx, cancel := context.WithCancel(ensureContext(s.opts.Context))
+	defer cancel()
+
+	// subscribe first, logs of blocks arriving during the backfill are not lost
+	query := s.query
+	query.FromBlock, query.ToBlock = nil, nil
+	live := make(chan types.Log, 128)
+	sub, err := s.filterer.SubscribeFilterLogs(ctx, query, live)
+	if err != nil {
+		return err
+	}
+	defer sub.Unsubscribe()
+
+	from := s.cursor.Block
+	if len(s.cursor.Recent) > 0 && s.cursor.Recent[0].BlockNumber < from {
+		from = s.cursor.Recent[0].BlockNumber
+	}
+	query.FromBlock = new(big.Int).SetUint64(from)
+	past, err := s.filterer.FilterLogs(ctx, query)
+	if err != nil {
+		return err
+	}
+	sort.Slice(past, func(i, j int) bool {
+		return past[i].BlockNumber < past[j].BlockNumber || (past[i].BlockNumber == past[j].BlockNumber && past[i].Index < past[j].Index)
+	})
+	// revert the delivered logs reorged while not subscribed
+	canonical := make(map[common.Hash]map[uint]bool)
+	for _, l := range past {
+		if canonical[l.BlockHash] == nil {
+			canonical[l.BlockHash] = make(map[uint]bool)
+		}
+		canonical[l.BlockHash][l.Index] = true
+	}
+	for i := len(s.cursor.Recent) - 1; i >= 0; i-- {
+		if l := s.cursor.Recent[i]; !canonical[l.BlockHash][l.Index] {
+			if err := s.revert(l, quit); err != nil {
+				return err
+			}
+		}
+	}
+	for i := range past {
+		if err := s.deliver(past[i], quit); err != nil {
+			return err
+		}
+	}
+	for {
+		select {
+		case l := <-live:
+			if l.Removed {
+				err = s.revert(l, quit)
+			} else {
+				err = s.deliver(l, quit)
+			}
+			if err != nil {
+				return err
+			}
+		case err := <-sub.Err():
+			if err == nil {
+				err = errSubClosed
+			}
+			return err
+		case <-quit:
+			return errStreamClosed
+		}
+	}
+}
+
+// deliver forwards l unless it was already delivered.
+func (s *EventStream) deliver(l types.Log, quit <-chan struct{}) error {
+	if s.cursor.before(&l) || s.cursor.delivered(&l) >= 0 {
+		return nil
+	}
+	select {
+	case s.sink <- l:
+	case <-quit:
+		return errStreamClosed
+	}
+	s.cursor.Block, s.cursor.Index = l.BlockNumber, l.Index+1
+	s.cursor.Recent = append(s.cursor.Recent, l)
+
+	// forget the logs too deep to be reorged
+	drop := 0
+	for drop < len(s.cursor.Recent) && s.cursor.Recent[drop].BlockNumber+s.opts.ReorgDepth <= l.BlockNumber {
+		drop++
+	}
+	s.cursor.Recent = s.cursor.Recent[drop:]
+	return s.save()
+}
+
+// revert forwards l with Removed set if it was delivered, the stream then
+// resumes from its position.
+func (s *EventStream) revert(l types.Log, quit <-chan struct{}) error {
+	i := s.cursor.delivered(&l)
+	if i < 0 {
+		return nil
+	}
+	removed := s.cursor.Recent[i]
+	removed.Removed = true
+	select {
+	case s.sink <- removed:
+	case <-quit:
+		return errStreamClosed
+	}
+	s.cursor.Recent = append(s.cursor.Recent[:i], s.cursor.Recent[i+1:]...)
+	if s.cursor.before(&l) {
+		s.cursor.Block, s.cursor.Index = l.BlockNumber, l.Index
+	}
+	return s.save()
+}
+
+func (s *EventStream) save() error {
+	if err := s.store.SaveCursor(s.cursor); err != nil {
+		return &streamError{err}
+	}
+	return nil
+}
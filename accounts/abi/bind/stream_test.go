@@ -0,0 +1,268 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package bind
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"bitbucket.org/cpchain/chain"
+	"bitbucket.org/cpchain/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// testLogSub is a log subscription failing on demand.
+type testLogSub struct {
+	ch   chan<- types.Log
+	err  chan error
+	once sync.Once
+}
+
+func (s *testLogSub) Unsubscribe() {
+	s.once.Do(func() { close(s.err) })
+}
+
+func (s *testLogSub) Err() <-chan error {
+	return s.err
+}
+
+// testLogChain is a chain of one log per block, new logs and reorgs are
+// pushed to the subscribers.
+type testLogChain struct {
+	mu   sync.Mutex
+	logs []types.Log
+	subs []*testLogSub
+}
+
+func (c *testLogChain) FilterLogs(ctx context.Context, query cpchain.FilterQuery) ([]types.Log, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var logs []types.Log
+	for _, l := range c.logs {
+		if query.FromBlock == nil || l.BlockNumber >= query.FromBlock.Uint64() {
+			logs = append(logs, l)
+		}
+	}
+	return logs, nil
+}
+
+func (c *testLogChain) SubscribeFilterLogs(ctx context.Context, query cpchain.FilterQuery, ch chan<- types.Log) (cpchain.Subscription, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	sub := &testLogSub{ch: ch, err: make(chan error, 1)}
+	c.subs = append(c.subs, sub)
+	return sub, nil
+}
+
+// add appends a log with the given fork marker, pushing it if live.
+func (c *testLogChain) add(fork byte, live bool) types.Log {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	number := uint64(len(c.logs) + 1)
+	l := types.Log{BlockNumber: number, BlockHash: common.Hash{fork, byte(number)}, Data: []byte{fork}}
+	c.logs = append(c.logs, l)
+	if live {
+		for _, sub := range c.subs {
+			sub.ch <- l
+		}
+	}
+	return l
+}
+
+// reorg drops the last n logs, pushing their removal if live.
+func (c *testLogChain) reorg(n int, live bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for i := len(c.logs) - 1; i >= len(c.logs)-n; i-- {
+		l := c.logs[i]
+		l.Removed = true
+		if live {
+			for _, sub := range c.subs {
+				sub.ch <- l
+			}
+		}
+	}
+	c.logs = c.logs[:len(c.logs)-n]
+}
+
+// disconnect fails the subscriptions.
+func (c *testLogChain) disconnect() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, sub := range c.subs {
+		sub.err <- context.DeadlineExceeded
+	}
+	c.subs = nil
+}
+
+func (c *testLogChain) connected() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.subs) > 0
+}
+
+func expectLogs(t *testing.T, ch <-chan types.Log, want ...types.Log) {
+	t.Helper()
+	for i, w := range want {
+		select {
+		case have := <-ch:
+			if have.BlockHash != w.BlockHash || have.Removed != w.Removed {
+				t.Fatalf("log %d mismatch: have %x (removed %v), want %x (removed %v)", i, have.BlockHash, have.Removed, w.BlockHash, w.Removed)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("log %d missing, want %x", i, w.BlockHash)
+		}
+	}
+	select {
+	case have := <-ch:
+		t.Fatalf("unexpected log %x (removed %v)", have.BlockHash, have.Removed)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func waitConnected(t *testing.T, chain *testLogChain) {
+	t.Helper()
+	for i := 0; i < 100 && !chain.connected(); i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if !chain.connected() {
+		t.Fatal("stream not subscribed")
+	}
+}
+
+func removed(l types.Log) types.Log {
+	l.Removed = true
+	return l
+}
+
+func TestEventStreamBackfill(t *testing.T) {
+	chain := new(testLogChain)
+	chain.add(0, false)
+	l2 := chain.add(0, false)
+
+	logs := make(chan types.Log)
+	sub, err := NewEventStream(chain, cpchain.FilterQuery{}, &StreamOpts{Start: 2}, logs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sub.Unsubscribe()
+
+	// logs before the start block are skipped
+	expectLogs(t, logs, l2)
+	waitConnected(t, chain)
+	l3 := chain.add(0, true)
+	expectLogs(t, logs, l3)
+
+	// a live reorg reverts the delivered log
+	chain.reorg(1, true)
+	l3b := chain.add(1, true)
+	expectLogs(t, logs, removed(l3), l3b)
+}
+
+func TestEventStreamResume(t *testing.T) {
+	chain := new(testLogChain)
+	l1, l2 := chain.add(0, false), chain.add(0, false)
+
+	logs := make(chan types.Log)
+	sub, err := NewEventStream(chain, cpchain.FilterQuery{}, &StreamOpts{RetryDelay: 10 * time.Millisecond}, logs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sub.Unsubscribe()
+	expectLogs(t, logs, l1, l2)
+	waitConnected(t, chain)
+
+	// the chain reorgs and grows while the stream is disconnected
+	chain.disconnect()
+	chain.reorg(1, false)
+	l2b, l3 := chain.add(1, false), chain.add(1, false)
+
+	expectLogs(t, logs, removed(l2), l2b, l3)
+	waitConnected(t, chain)
+	l4 := chain.add(1, true)
+	expectLogs(t, logs, l4)
+}
+
+func TestEventStreamCursor(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cpchain-stream-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	store := NewFileCursorStore(filepath.Join(dir, "cursor.json"))
+
+	chain := new(testLogChain)
+	l1 := chain.add(0, false)
+
+	logs := make(chan types.Log)
+	sub, err := NewEventStream(chain, cpchain.FilterQuery{}, &StreamOpts{Cursor: store}, logs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectLogs(t, logs, l1)
+	sub.Unsubscribe()
+
+	cursor, err := store.LoadCursor()
+	if err != nil || cursor == nil {
+		t.Fatalf("cursor not saved: %v", err)
+	}
+	if cursor.Block != 1 || cursor.Index != 1 || len(cursor.Recent) != 1 || cursor.Recent[0].BlockHash != l1.BlockHash {
+		t.Errorf("cursor mismatch: %+v", cursor)
+	}
+
+	// a restarted stream resumes after the delivered logs, and still reverts them
+	chain.reorg(1, false)
+	l1b, l2 := chain.add(1, false), chain.add(1, false)
+	sub, err = NewEventStream(chain, cpchain.FilterQuery{}, &StreamOpts{Cursor: store}, logs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sub.Unsubscribe()
+	expectLogs(t, logs, removed(l1), l1b, l2)
+}
+
+func TestEventStreamReorgDepth(t *testing.T) {
+	chain := new(testLogChain)
+	for i := 0; i < 5; i++ {
+		chain.add(0, false)
+	}
+	store := NewMemoryCursorStore()
+	logs := make(chan types.Log, 5)
+	sub, err := NewEventStream(chain, cpchain.FilterQuery{}, &StreamOpts{Cursor: store, ReorgDepth: 2}, logs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sub.Unsubscribe()
+	for i := 0; i < 5; i++ {
+		<-logs
+	}
+	time.Sleep(50 * time.Millisecond)
+	cursor, _ := store.LoadCursor()
+	if len(cursor.Recent) != 2 || cursor.Recent[0].BlockNumber != 4 {
+		t.Errorf("recent logs mismatch: %v", cursor.Recent)
+	}
+}
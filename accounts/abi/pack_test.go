@@ -319,13 +319,28 @@ func TestPack(t *testing.T) {
 			[24]byte{1},
 			common.Hex2Bytes("0100000000000000000000000000000000000000000000000000000000000000"),
 		},
+		{
+			"string[]",
+			[]string{"hello", "world"},
+			common.Hex2Bytes("000000000000000000000000000000000000000000000000000000000000000200000000000000000000000000000000000000000000000000000000000000400000000000000000000000000000000000000000000000000000000000000080000000000000000000000000000000000000000000000000000000000000000568656c6c6f0000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000005776f726c64000000000000000000000000000000000000000000000000000000"),
+		},
+		{
+			"uint8[][]",
+			[][]uint8{{1}, {2, 3}},
+			common.Hex2Bytes("00000000000000000000000000000000000000000000000000000000000000020000000000000000000000000000000000000000000000000000000000000040000000000000000000000000000000000000000000000000000000000000008000000000000000000000000000000000000000000000000000000000000000010000000000000000000000000000000000000000000000000000000000000001000000000000000000000000000000000000000000000000000000000000000200000000000000000000000000000000000000000000000000000000000000020000000000000000000000000000000000000000000000000000000000000003"),
+		},
+		{
+			"bytes[2]",
+			[2][]byte{{0xaa}, {0xbb}},
+			common.Hex2Bytes("000000000000000000000000000000000000000000000000000000000000004000000000000000000000000000000000000000000000000000000000000000800000000000000000000000000000000000000000000000000000000000000001aa000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000001bb00000000000000000000000000000000000000000000000000000000000000"),
+		},
 		{
 			"string",
 			"foobar",
 			common.Hex2Bytes("0000000000000000000000000000000000000000000000000000000000000006666f6f6261720000000000000000000000000000000000000000000000000000"),
 		},
 	} {
-		typ, err := NewType(test.typ)
+		typ, err := NewType(test.typ, nil)
 		if err != nil {
 			t.Fatalf("%v failed. Unexpected parse error: %v", i, err)
 		}
@@ -441,3 +456,40 @@ func TestPackNumber(t *testing.T) {
 		}
 	}
 }
+
+const tupleABI = `[{"name":"set","type":"function","inputs":[
+	{"name":"s","type":"tuple","components":[{"name":"a","type":"uint256"},{"name":"b","type":"uint256[]"}]},
+	{"name":"c","type":"tuple","components":[{"name":"x","type":"uint256"},{"name":"y","type":"bool"}]}
+]}]`
+
+func TestPackTuple(t *testing.T) {
+	abi, err := JSON(strings.NewReader(tupleABI))
+	if err != nil {
+		t.Fatal(err)
+	}
+	method := abi.Methods["set"]
+	if sig := method.Sig(); sig != "set((uint256,uint256[]),(uint256,bool))" {
+		t.Fatalf("wrong signature: %s", sig)
+	}
+	s := struct {
+		A *big.Int
+		B []*big.Int
+	}{big.NewInt(1), []*big.Int{big.NewInt(2), big.NewInt(3)}}
+	c := &struct {
+		X *big.Int
+		Y bool
+	}{big.NewInt(7), true}
+
+	packed, err := abi.Pack("set", s, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the dynamic tuple is referenced from the head, the static one is inlined
+	want := common.Hex2Bytes("00000000000000000000000000000000000000000000000000000000000000600000000000000000000000000000000000000000000000000000000000000007000000000000000000000000000000000000000000000000000000000000000100000000000000000000000000000000000000000000000000000000000000010000000000000000000000000000000000000000000000000000000000000040000000000000000000000000000000000000000000000000000000000000000200000000000000000000000000000000000000000000000000000000000000020000000000000000000000000000000000000000000000000000000000000003")
+	if !bytes.Equal(packed[4:], want) {
+		t.Fatalf("pack mismatch:\nhave %x\nwant %x", packed[4:], want)
+	}
+	if _, err := abi.Pack("set", s, struct{ X *big.Int }{big.NewInt(7)}); err == nil {
+		t.Fatal("expected error for missing tuple field")
+	}
+}
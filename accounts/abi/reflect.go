@@ -70,8 +70,9 @@ func mustArrayToByteSlice(value reflect.Value) reflect.Value {
 // set attempts to assign src to dst by either setting, copying or otherwise.
 //
 // set is a bit more lenient when it comes to assignment and doesn't force an as
-// strict ruleset as bare `reflect` does.
-func set(dst, src reflect.Value, output Argument) error {
+// strict ruleset as bare `reflect` does. Tuples, which are unpacked into
+// anonymous structs, are copied field by field into the destination struct.
+func set(dst, src reflect.Value, t Type) error {
 	dstType := dst.Type()
 	srcType := src.Type()
 	switch {
@@ -80,13 +81,55 @@ func set(dst, src reflect.Value, output Argument) error {
 	case dstType.Kind() == reflect.Interface:
 		dst.Set(src)
 	case dstType.Kind() == reflect.Ptr:
-		return set(dst.Elem(), src, output)
+		if dst.IsNil() {
+			dst.Set(reflect.New(dstType.Elem()))
+		}
+		return set(dst.Elem(), src, t)
+	case t.T == TupleTy && dstType.Kind() == reflect.Struct:
+		return setStruct(dst, src, t)
+	case (t.T == SliceTy || t.T == ArrayTy) && (dstType.Kind() == reflect.Slice || dstType.Kind() == reflect.Array):
+		return setSlice(dst, src, t)
 	default:
 		return fmt.Errorf("abi: cannot unmarshal %v in to %v", src.Type(), dst.Type())
 	}
 	return nil
 }
 
+// setStruct assigns the fields of an unpacked tuple to the matching fields of
+// dst, see mapArgNamesToStructFields for how they are matched.
+func setStruct(dst, src reflect.Value, t Type) error {
+	fields, err := mapArgNamesToStructFields(t.TupleRawNames, dst)
+	if err != nil {
+		return err
+	}
+	for i, name := range t.TupleRawNames {
+		field, ok := fields[name]
+		if !ok {
+			continue
+		}
+		if err := set(dst.FieldByName(field), src.Field(i), *t.TupleElems[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setSlice assigns the elements of an unpacked slice or array to dst element
+// by element, which allows the elements to be tuples.
+func setSlice(dst, src reflect.Value, t Type) error {
+	if dst.Kind() == reflect.Slice {
+		dst.Set(reflect.MakeSlice(dst.Type(), src.Len(), src.Len()))
+	} else if dst.Len() != src.Len() {
+		return fmt.Errorf("abi: cannot unmarshal %v in to %v", src.Type(), dst.Type())
+	}
+	for i := 0; i < src.Len(); i++ {
+		if err := set(dst.Index(i), src.Index(i), *t.Elem); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // requireAssignable assures that `dest` is a pointer and it's not an interface.
 func requireAssignable(dst, src reflect.Value) error {
 	if dst.Kind() != reflect.Ptr && dst.Kind() != reflect.Interface {
@@ -112,13 +155,22 @@ func requireUnpackKind(v reflect.Value, t reflect.Type, k reflect.Kind,
 	return nil
 }
 
-// mapAbiToStringField maps abi to struct fields.
+// mapAbiToStructFields maps the non-indexed arguments to struct fields.
+func mapAbiToStructFields(args Arguments, value reflect.Value) (map[string]string, error) {
+	var names []string
+	for _, arg := range args.NonIndexed() {
+		names = append(names, arg.Name)
+	}
+	return mapArgNamesToStructFields(names, value)
+}
+
+// mapArgNamesToStructFields maps argument names to struct fields.
 // first round: for each Exportable field that contains a `abi:""` tag
 //   and this field name exists in the arguments, pair them together.
 // second round: for each argument field that has not been already linked,
 //   find what variable is expected to be mapped into, if it exists and has not been
 //   used, pair them.
-func mapAbiToStructFields(args Arguments, value reflect.Value) (map[string]string, error) {
+func mapArgNamesToStructFields(argNames []string, value reflect.Value) (map[string]string, error) {
 
 	typ := value.Type()
 
@@ -148,14 +200,14 @@ func mapAbiToStructFields(args Arguments, value reflect.Value) (map[string]strin
 
 		// check which argument field matches with the abi tag.
 		found := false
-		for _, abiField := range args.NonIndexed() {
-			if abiField.Name == tagName {
-				if abi2struct[abiField.Name] != "" {
+		for _, argName := range argNames {
+			if argName == tagName {
+				if abi2struct[argName] != "" {
 					return nil, fmt.Errorf("struct: abi tag in '%s' already mapped", structFieldName)
 				}
 				// pair them
-				abi2struct[abiField.Name] = structFieldName
-				struct2abi[structFieldName] = abiField.Name
+				abi2struct[argName] = structFieldName
+				struct2abi[structFieldName] = argName
 				found = true
 			}
 		}
@@ -168,9 +220,8 @@ func mapAbiToStructFields(args Arguments, value reflect.Value) (map[string]strin
 	}
 
 	// second round ~~~
-	for _, arg := range args {
+	for _, abiFieldName := range argNames {
 
-		abiFieldName := arg.Name
 		structFieldName := capitalise(abiFieldName)
 
 		if structFieldName == "" {
@@ -19,6 +19,7 @@ package abi
 import (
 	"bytes"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"math/big"
 	"reflect"
@@ -168,7 +169,7 @@ var unpackTests = []unpackTest{
 	// multi dimensional, if these pass, all types that don't require length prefix should pass
 	{
 		def:  `[{"type": "uint8[][]"}]`,
-		enc:  "00000000000000000000000000000000000000000000000000000000000000200000000000000000000000000000000000000000000000000000000000000002000000000000000000000000000000000000000000000000000000000000008000000000000000000000000000000000000000000000000000000000000000E0000000000000000000000000000000000000000000000000000000000000000200000000000000000000000000000000000000000000000000000000000000010000000000000000000000000000000000000000000000000000000000000002000000000000000000000000000000000000000000000000000000000000000200000000000000000000000000000000000000000000000000000000000000010000000000000000000000000000000000000000000000000000000000000002",
+		enc:  "00000000000000000000000000000000000000000000000000000000000000200000000000000000000000000000000000000000000000000000000000000002000000000000000000000000000000000000000000000000000000000000004000000000000000000000000000000000000000000000000000000000000000a0000000000000000000000000000000000000000000000000000000000000000200000000000000000000000000000000000000000000000000000000000000010000000000000000000000000000000000000000000000000000000000000002000000000000000000000000000000000000000000000000000000000000000200000000000000000000000000000000000000000000000000000000000000010000000000000000000000000000000000000000000000000000000000000002",
 		want: [][]uint8{{1, 2}, {1, 2}},
 	},
 	{
@@ -178,7 +179,7 @@ var unpackTests = []unpackTest{
 	},
 	{
 		def:  `[{"type": "uint8[][2]"}]`,
-		enc:  "000000000000000000000000000000000000000000000000000000000000004000000000000000000000000000000000000000000000000000000000000000800000000000000000000000000000000000000000000000000000000000000001000000000000000000000000000000000000000000000000000000000000000100000000000000000000000000000000000000000000000000000000000000010000000000000000000000000000000000000000000000000000000000000001",
+		enc:  "0000000000000000000000000000000000000000000000000000000000000020000000000000000000000000000000000000000000000000000000000000004000000000000000000000000000000000000000000000000000000000000000800000000000000000000000000000000000000000000000000000000000000001000000000000000000000000000000000000000000000000000000000000000100000000000000000000000000000000000000000000000000000000000000010000000000000000000000000000000000000000000000000000000000000001",
 		want: [2][]uint8{{1}, {1}},
 	},
 	{
@@ -815,3 +816,65 @@ func TestOOMMaliciousInput(t *testing.T) {
 		}
 	}
 }
+
+func TestUnpackTuple(t *testing.T) {
+	abi, err := JSON(strings.NewReader(tupleABI))
+	if err != nil {
+		t.Fatal(err)
+	}
+	inputs := abi.Methods["set"].Inputs
+	data, err := inputs.Pack(struct {
+		A *big.Int
+		B []*big.Int
+	}{big.NewInt(1), []*big.Int{big.NewInt(2), big.NewInt(3)}}, struct {
+		X *big.Int
+		Y bool
+	}{big.NewInt(7), true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// fields are matched by name or abi tag, nested tuples may be pointers
+	type inner struct {
+		A    *big.Int
+		List []*big.Int `abi:"b"`
+	}
+	var out struct {
+		S inner
+		C *struct {
+			X *big.Int
+			Y bool
+		}
+	}
+	if err := inputs.Unpack(&out, data); err != nil {
+		t.Fatal(err)
+	}
+	if out.S.A.Int64() != 1 || len(out.S.List) != 2 || out.S.List[1].Int64() != 3 {
+		t.Errorf("wrong dynamic tuple: %+v", out.S)
+	}
+	if out.C == nil || out.C.X.Int64() != 7 || !out.C.Y {
+		t.Errorf("wrong static tuple: %+v", out.C)
+	}
+
+	// slices of tuples with dynamic components
+	def := `[{"name":"items","type":"tuple[]","components":[{"name":"name","type":"string"},{"name":"value","type":"uint64"}]}]`
+	var args Arguments
+	if err := json.Unmarshal([]byte(def), &args); err != nil {
+		t.Fatal(err)
+	}
+	type item struct {
+		Name  string
+		Value uint64
+	}
+	want := []item{{"foo", 1}, {"barbaz", 2}}
+	if data, err = args.Pack(want); err != nil {
+		t.Fatal(err)
+	}
+	var have []item
+	if err := args.Unpack(&have, data); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(have, want) {
+		t.Errorf("wrong tuple slice: have %+v, want %+v", have, want)
+	}
+}
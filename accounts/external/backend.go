@@ -0,0 +1,297 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package external implements an accounts.Backend whose keys are held by a
+// separate signer process, reached over IPC or HTTP. The node never sees the
+// private keys, every signing request is subject to the rules of the signer.
+package external
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"sync"
+	"time"
+
+	"bitbucket.org/cpchain/chain"
+	"bitbucket.org/cpchain/chain/accounts"
+	"bitbucket.org/cpchain/chain/api/rpc"
+	"bitbucket.org/cpchain/chain/commons/log"
+	"bitbucket.org/cpchain/chain/consensus"
+	"bitbucket.org/cpchain/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// Scheme is the URL scheme of external signer wallets.
+const Scheme = "extapi"
+
+// requestTimeout bounds the time a signer may take to answer, requests that
+// need manual approval on the signer side have to fit in as well.
+const requestTimeout = 30 * time.Second
+
+// BackendType is the reflect type of an external signer backend.
+var BackendType = reflect.TypeOf(&ExternalBackend{})
+
+// ExternalBackend is an accounts.Backend with a single wallet, the external
+// signer.
+type ExternalBackend struct {
+	signers []accounts.Wallet
+}
+
+// NewExternalBackend connects to the signer listening on endpoint, which is
+// an IPC path or an http(s) URL.
+func NewExternalBackend(endpoint string) (*ExternalBackend, error) {
+	signer, err := NewExternalSigner(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	return &ExternalBackend{signers: []accounts.Wallet{signer}}, nil
+}
+
+// Wallets implements accounts.Backend.
+func (eb *ExternalBackend) Wallets() []accounts.Wallet {
+	return eb.signers
+}
+
+// Subscribe implements accounts.Backend. The signer is connected for the
+// lifetime of the backend, so no wallet events are ever sent.
+func (eb *ExternalBackend) Subscribe(sink chan<- accounts.WalletEvent) event.Subscription {
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		<-quit
+		return nil
+	})
+}
+
+// ExternalSigner implements accounts.Wallet on top of the signer's RPC API.
+type ExternalSigner struct {
+	client   *rpc.Client
+	endpoint string
+
+	cacheMu sync.RWMutex
+	cache   []accounts.Account // accounts of the signer, nil until listed
+}
+
+// NewExternalSigner dials the signer and checks that it answers.
+func NewExternalSigner(endpoint string) (*ExternalSigner, error) {
+	client, err := rpc.Dial(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	signer := &ExternalSigner{client: client, endpoint: endpoint}
+
+	var version string
+	if err := signer.call(&version, "account_version"); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("external signer at %s unavailable: %v", endpoint, err)
+	}
+	log.Info("Connected to external signer", "endpoint", endpoint, "version", version)
+	return signer, nil
+}
+
+func (s *ExternalSigner) call(result interface{}, method string, args ...interface{}) error {
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+	return s.client.CallContext(ctx, result, method, args...)
+}
+
+// URL implements accounts.Wallet.
+func (s *ExternalSigner) URL() accounts.URL {
+	return accounts.URL{Scheme: Scheme, Path: s.endpoint}
+}
+
+// Status implements accounts.Wallet, returning the version of the signer.
+func (s *ExternalSigner) Status() (string, error) {
+	var version string
+	if err := s.call(&version, "account_version"); err != nil {
+		return "Unreachable", err
+	}
+	return fmt.Sprintf("Signer %s", version), nil
+}
+
+// Open implements accounts.Wallet, it is a noop as the connection is
+// established by NewExternalSigner.
+func (s *ExternalSigner) Open(passphrase string) error { return nil }
+
+// Close implements accounts.Wallet, it is a noop so that the wallet can be
+// reopened, the connection lives as long as the process.
+func (s *ExternalSigner) Close() error { return nil }
+
+// Accounts implements accounts.Wallet, returning the accounts the signer is
+// willing to sign for. The list is fetched once and cached.
+func (s *ExternalSigner) Accounts() []accounts.Account {
+	s.cacheMu.RLock()
+	cached := s.cache
+	s.cacheMu.RUnlock()
+	if cached != nil {
+		return cached
+	}
+
+	var addresses []common.Address
+	if err := s.call(&addresses, "account_list"); err != nil {
+		log.Warn("Failed to list accounts of external signer", "endpoint", s.endpoint, "err", err)
+		return nil
+	}
+	accs := make([]accounts.Account, 0, len(addresses))
+	for _, addr := range addresses {
+		accs = append(accs, accounts.Account{Address: addr, URL: s.URL()})
+	}
+	s.cacheMu.Lock()
+	s.cache = accs
+	s.cacheMu.Unlock()
+	return accs
+}
+
+// Contains implements accounts.Wallet.
+func (s *ExternalSigner) Contains(account accounts.Account) bool {
+	for _, acc := range s.Accounts() {
+		if acc.Address == account.Address && (account.URL == (accounts.URL{}) || account.URL == acc.URL) {
+			return true
+		}
+	}
+	return false
+}
+
+// Derive implements accounts.Wallet, but is a noop as external signers have
+// no notion of hierarchical account derivation.
+func (s *ExternalSigner) Derive(path accounts.DerivationPath, pin bool) (accounts.Account, error) {
+	return accounts.Account{}, accounts.ErrNotSupported
+}
+
+// SelfDerive implements accounts.Wallet, but is a noop as external signers
+// have no notion of hierarchical account derivation.
+func (s *ExternalSigner) SelfDerive(base accounts.DerivationPath, chain cpchain.ChainStateReader) {}
+
+// SignHash implements accounts.Wallet. The signer only signs raw hashes for
+// accounts it explicitly allows to, Dpor keys sign with the SignDpor methods.
+func (s *ExternalSigner) SignHash(account accounts.Account, hash []byte) ([]byte, error) {
+	if !s.Contains(account) {
+		return nil, accounts.ErrUnknownAccount
+	}
+	var sig hexutil.Bytes
+	if err := s.call(&sig, "account_signHash", account.Address, hexutil.Bytes(hash)); err != nil {
+		return nil, err
+	}
+	return sig, nil
+}
+
+// SignTx implements accounts.Wallet. The unsigned transaction is sent to the
+// signer, which checks it against its rules and returns it signed.
+func (s *ExternalSigner) SignTx(account accounts.Account, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	if !s.Contains(account) {
+		return nil, accounts.ErrUnknownAccount
+	}
+	raw, err := rlp.EncodeToBytes(tx)
+	if err != nil {
+		return nil, err
+	}
+	var signed hexutil.Bytes
+	if err := s.call(&signed, "account_signTransaction", account.Address, hexutil.Bytes(raw), (*hexutil.Big)(chainID)); err != nil {
+		return nil, err
+	}
+	res := new(types.Transaction)
+	if err := rlp.DecodeBytes(signed, res); err != nil {
+		return nil, err
+	}
+	// make sure the signer signed exactly the requested transaction
+	var signer types.Signer = types.HomesteadSigner{}
+	if chainID != nil {
+		signer = types.NewCep1Signer(chainID)
+	}
+	if signer.Hash(res) != signer.Hash(tx) {
+		return nil, errors.New("external signer returned a different transaction")
+	}
+	if from, err := types.Sender(signer, res); err != nil || from != account.Address {
+		return nil, errors.New("external signer returned a transaction signed by another account")
+	}
+	return res, nil
+}
+
+// SignDporHeader signs header in state. The header itself is sent, so the
+// signer computes the hash and knows which block it signs.
+func (s *ExternalSigner) SignDporHeader(account accounts.Account, header *types.Header, state consensus.State) ([]byte, error) {
+	if !s.Contains(account) {
+		return nil, accounts.ErrUnknownAccount
+	}
+	raw, err := rlp.EncodeToBytes(header)
+	if err != nil {
+		return nil, err
+	}
+	var sig hexutil.Bytes
+	if err := s.call(&sig, "account_signDporHeader", account.Address, hexutil.Bytes(raw), uint8(state)); err != nil {
+		return nil, err
+	}
+	return sig, nil
+}
+
+// SignDporMac signs a mac of the Dpor handshake.
+func (s *ExternalSigner) SignDporMac(account accounts.Account, mac string) ([]byte, error) {
+	if !s.Contains(account) {
+		return nil, accounts.ErrUnknownAccount
+	}
+	var sig hexutil.Bytes
+	if err := s.call(&sig, "account_signDporMac", account.Address, mac); err != nil {
+		return nil, err
+	}
+	return sig, nil
+}
+
+// SignDporMaintenance signs a maintenance notice skipping blocks from to to.
+func (s *ExternalSigner) SignDporMaintenance(account accounts.Account, from, to uint64) ([]byte, error) {
+	if !s.Contains(account) {
+		return nil, accounts.ErrUnknownAccount
+	}
+	var sig hexutil.Bytes
+	if err := s.call(&sig, "account_signDporMaintenance", account.Address, hexutil.Uint64(from), hexutil.Uint64(to)); err != nil {
+		return nil, err
+	}
+	return sig, nil
+}
+
+// SignHashWithPassphrase implements accounts.Wallet, passphrases are managed
+// by the signer so it is not supported.
+func (s *ExternalSigner) SignHashWithPassphrase(account accounts.Account, passphrase string, hash []byte) ([]byte, error) {
+	return nil, accounts.ErrNotSupported
+}
+
+// SignTxWithPassphrase implements accounts.Wallet, passphrases are managed by
+// the signer so it is not supported.
+func (s *ExternalSigner) SignTxWithPassphrase(account accounts.Account, passphrase string, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return nil, accounts.ErrNotSupported
+}
+
+// DecryptWithEcies implements accounts.Wallet, the signer doesn't decrypt
+// data on behalf of the node.
+func (s *ExternalSigner) DecryptWithEcies(account accounts.Account, cipherText []byte) ([]byte, error) {
+	return nil, accounts.ErrNotSupported
+}
+
+// PublicKey implements accounts.Wallet, returning the uncompressed public key
+// of the account.
+func (s *ExternalSigner) PublicKey(account accounts.Account) ([]byte, error) {
+	if !s.Contains(account) {
+		return nil, accounts.ErrUnknownAccount
+	}
+	var pub hexutil.Bytes
+	if err := s.call(&pub, "account_publicKey", account.Address); err != nil {
+		return nil, err
+	}
+	return pub, nil
+}
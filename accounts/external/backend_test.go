@@ -0,0 +1,152 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package external
+
+import (
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"bitbucket.org/cpchain/chain/accounts"
+	"bitbucket.org/cpchain/chain/accounts/keystore"
+	"bitbucket.org/cpchain/chain/api/rpc"
+	"bitbucket.org/cpchain/chain/consensus"
+	"bitbucket.org/cpchain/chain/consensus/dpor"
+	"bitbucket.org/cpchain/chain/tools/signer/core"
+	"bitbucket.org/cpchain/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// newTestSigner starts a signer over HTTP with a single account, allowed to
+// sign hashes, Dpor msgs and transfers of at most 100 wei.
+func newTestSigner(t *testing.T) (*ExternalBackend, accounts.Account, func()) {
+	dir, err := ioutil.TempDir("", "cpchain-external-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ks := keystore.NewPlaintextKeyStore(dir)
+	acc, err := ks.NewAccount("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ks.Unlock(acc, "")
+	rules, err := core.ParseRules([]byte(fmt.Sprintf(`{"accounts": {"%s": {"allowHashSigning": true, "allowDporSigning": true, "maxValue": "100"}}}`, acc.Address.Hex())))
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := rpc.NewServer()
+	if err := server.RegisterName(core.Namespace, core.NewSignerAPI(ks, rules, nil)); err != nil {
+		t.Fatal(err)
+	}
+	httpServer := httptest.NewServer(server)
+
+	backend, err := NewExternalBackend(httpServer.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cleanup := func() {
+		httpServer.Close()
+		server.Stop()
+		os.RemoveAll(dir)
+	}
+	return backend, accounts.Account{Address: acc.Address}, cleanup
+}
+
+func TestExternalSigner(t *testing.T) {
+	backend, account, cleanup := newTestSigner(t)
+	defer cleanup()
+
+	manager := accounts.NewManager(backend)
+	defer manager.Close()
+	wallet, err := manager.Find(account)
+	if err != nil {
+		t.Fatalf("signer account not found: %v", err)
+	}
+	if wallet.URL().Scheme != Scheme {
+		t.Errorf("wallet scheme mismatch: have %s, want %s", wallet.URL().Scheme, Scheme)
+	}
+
+	// hashes are signed, e.g. for sealing headers
+	hash := crypto.Keccak256([]byte("header"))
+	sig, err := wallet.SignHash(account, hash)
+	if err != nil {
+		t.Fatalf("failed to sign hash: %v", err)
+	}
+	if pub, err := crypto.SigToPub(hash, sig); err != nil || crypto.PubkeyToAddress(*pub) != account.Address {
+		t.Errorf("hash signed by the wrong key")
+	}
+
+	// Dpor headers are sent to be hashed by the signer
+	dporSigner, ok := wallet.(*ExternalSigner)
+	if !ok {
+		t.Fatalf("wallet type mismatch: have %T, want *ExternalSigner", wallet)
+	}
+	header := &types.Header{Number: big.NewInt(1), Time: big.NewInt(1)}
+	sig, err = dporSigner.SignDporHeader(account, header, consensus.Prepare)
+	if err != nil {
+		t.Fatalf("failed to sign header: %v", err)
+	}
+	headerHash, _ := dpor.HeaderSigHash(header, consensus.Prepare)
+	if pub, err := crypto.SigToPub(headerHash.Bytes(), sig); err != nil || crypto.PubkeyToAddress(*pub) != account.Address {
+		t.Errorf("header signed by the wrong key")
+	}
+	if _, err := dporSigner.SignDporMaintenance(account, 2, 1); err == nil {
+		t.Error("signed an empty maintenance notice")
+	}
+
+	// transactions within the rules are signed
+	chainID := big.NewInt(42)
+	tx := types.NewTransaction(1, common.Address{1}, big.NewInt(100), 21000, big.NewInt(1), nil)
+	signed, err := wallet.SignTx(account, tx, chainID)
+	if err != nil {
+		t.Fatalf("failed to sign transaction: %v", err)
+	}
+	if from, err := types.Sender(types.NewCep1Signer(chainID), signed); err != nil || from != account.Address {
+		t.Errorf("sender mismatch: have %x (%v), want %x", from, err, account.Address)
+	}
+	if signed.Hash() == tx.Hash() || signed.Nonce() != tx.Nonce() {
+		t.Errorf("signed transaction mismatch")
+	}
+
+	// the others are rejected
+	tx = types.NewTransaction(1, common.Address{1}, big.NewInt(101), 21000, big.NewInt(1), nil)
+	if _, err := wallet.SignTx(account, tx, chainID); err == nil {
+		t.Error("signed a transaction exceeding the value cap")
+	}
+
+	// unknown accounts are refused locally
+	if _, err := wallet.SignHash(accounts.Account{Address: common.Address{1}}, hash); err != accounts.ErrUnknownAccount {
+		t.Errorf("error mismatch: have %v, want %v", err, accounts.ErrUnknownAccount)
+	}
+	if _, err := wallet.SignTxWithPassphrase(account, "", tx, chainID); err != accounts.ErrNotSupported {
+		t.Errorf("error mismatch: have %v, want %v", err, accounts.ErrNotSupported)
+	}
+}
+
+func TestExternalSignerUnavailable(t *testing.T) {
+	server := httptest.NewServer(rpc.NewServer())
+	defer server.Close()
+
+	// no signer API registered
+	if _, err := NewExternalBackend(server.URL); err == nil {
+		t.Error("connected to a server without signer API")
+	}
+}
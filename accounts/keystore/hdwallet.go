@@ -0,0 +1,554 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package keystore
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"bitbucket.org/cpchain/chain"
+	"bitbucket.org/cpchain/chain/accounts"
+	"bitbucket.org/cpchain/chain/commons/crypto/ecieskey"
+	"bitbucket.org/cpchain/chain/commons/log"
+	"bitbucket.org/cpchain/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/crypto/ecies"
+	"github.com/pborman/uuid"
+)
+
+const (
+	// hdWalletDir is the subdirectory of the keystore holding the seed files of
+	// HD wallets. The account cache doesn't descend into it.
+	hdWalletDir = "hd"
+
+	hdWalletVersion = 1
+
+	// selfDeriveTimeout bounds a single state lookup during self-derivation.
+	selfDeriveTimeout = 10 * time.Second
+)
+
+var ErrHDWalletExists = errors.New("HD wallet already exists")
+
+type hdAccountJSON struct {
+	Address string `json:"address"`
+	Path    string `json:"path"`
+}
+
+// hdWalletJSON is the content of a seed file. Only the seed is encrypted, the
+// derived accounts are listed in clear so that they are known while the wallet
+// is locked.
+type hdWalletJSON struct {
+	Address  string          `json:"address"` // first account, identifying the wallet
+	Crypto   cryptoJSON      `json:"crypto"`
+	Id       string          `json:"id"`
+	Version  int             `json:"version"`
+	Next     string          `json:"next"` // path of the next account derived in sequence
+	Accounts []hdAccountJSON `json:"accounts"`
+}
+
+// HDWallet is a BIP-32 hierarchical deterministic wallet whose BIP-39 seed is
+// stored encrypted in the keystore directory. Accounts are derived on demand
+// and pinned to the seed file, signing requires the wallet to be opened with
+// its passphrase or the account to be unlocked in the keystore.
+type HDWallet struct {
+	url      accounts.URL
+	keystore *KeyStore
+
+	id       uuid.UUID
+	address  common.Address // first account
+	crypto   cryptoJSON     // encrypted seed
+	accounts []accounts.Account
+	paths    map[common.Address]accounts.DerivationPath
+	next     accounts.DerivationPath
+
+	seed []byte // decrypted seed, nil while the wallet is locked
+
+	deriveBase  accounts.DerivationPath  // base path to self-derive from
+	deriveChain cpchain.ChainStateReader // state reader to discover used accounts with
+
+	mu sync.RWMutex
+}
+
+// newHDWallet creates a wallet from a seed, with the first account derived at
+// accounts.DefaultBaseDerivationPath.
+func newHDWallet(ks *KeyStore, seed []byte, passphrase string) (*HDWallet, error) {
+	scryptN, scryptP := ks.scryptParams()
+	encrypted, err := encryptData(seed, passphrase, scryptN, scryptP)
+	if err != nil {
+		return nil, err
+	}
+	first, err := derivePrivateKey(seed, accounts.DefaultBaseDerivationPath)
+	if err != nil {
+		return nil, err
+	}
+	address := crypto.PubkeyToAddress(first.PublicKey)
+	zeroKey(first)
+
+	w := &HDWallet{
+		url:      accounts.URL{Scheme: KeyStoreScheme, Path: filepath.Join(ks.cache.keydir, hdWalletDir, keyFileName(address))},
+		keystore: ks,
+		id:       uuid.NewRandom(),
+		address:  address,
+		crypto:   encrypted,
+		paths:    make(map[common.Address]accounts.DerivationPath),
+		next:     append(accounts.DerivationPath{}, accounts.DefaultBaseDerivationPath...),
+	}
+	w.pin(address, accounts.DefaultBaseDerivationPath)
+	return w, nil
+}
+
+// loadHDWallets loads the seed files of the keystore directory.
+func loadHDWallets(ks *KeyStore) []*HDWallet {
+	dir := filepath.Join(ks.cache.keydir, hdWalletDir)
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	var wallets []*HDWallet
+	for _, fi := range files {
+		if skipKeyFile(fi) {
+			continue
+		}
+		path := filepath.Join(dir, fi.Name())
+		w, err := loadHDWallet(ks, path)
+		if err != nil {
+			log.Warn("Failed to load HD wallet", "path", path, "err", err)
+			continue
+		}
+		wallets = append(wallets, w)
+	}
+	return wallets
+}
+
+func loadHDWallet(ks *KeyStore, path string) (*HDWallet, error) {
+	blob, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var enc hdWalletJSON
+	if err := json.Unmarshal(blob, &enc); err != nil {
+		return nil, err
+	}
+	if enc.Version != hdWalletVersion {
+		return nil, fmt.Errorf("version not supported: %v", enc.Version)
+	}
+	next, err := accounts.ParseDerivationPath(enc.Next)
+	if err != nil {
+		return nil, err
+	}
+	w := &HDWallet{
+		url:      accounts.URL{Scheme: KeyStoreScheme, Path: path},
+		keystore: ks,
+		id:       uuid.Parse(enc.Id),
+		address:  common.HexToAddress(enc.Address),
+		crypto:   enc.Crypto,
+		paths:    make(map[common.Address]accounts.DerivationPath),
+		next:     next,
+	}
+	for _, acc := range enc.Accounts {
+		path, err := accounts.ParseDerivationPath(acc.Path)
+		if err != nil {
+			return nil, err
+		}
+		w.pin(common.HexToAddress(acc.Address), path)
+	}
+	return w, nil
+}
+
+// store writes the seed file, the lock must be held.
+func (w *HDWallet) store() error {
+	enc := hdWalletJSON{
+		Address:  hex.EncodeToString(w.address[:]),
+		Crypto:   w.crypto,
+		Id:       w.id.String(),
+		Version:  hdWalletVersion,
+		Next:     w.next.String(),
+		Accounts: make([]hdAccountJSON, len(w.accounts)),
+	}
+	for i, acc := range w.accounts {
+		enc.Accounts[i] = hdAccountJSON{Address: hex.EncodeToString(acc.Address[:]), Path: w.paths[acc.Address].String()}
+	}
+	blob, err := json.Marshal(enc)
+	if err != nil {
+		return err
+	}
+	return writeKeyFile(w.url.Path, blob)
+}
+
+// pin adds an account to the wallet and moves the next account of the
+// sequence past it, the lock must be held. Already pinned accounts are
+// ignored.
+func (w *HDWallet) pin(address common.Address, path accounts.DerivationPath) accounts.Account {
+	account := accounts.Account{
+		Address: address,
+		URL:     accounts.URL{Scheme: w.url.Scheme, Path: fmt.Sprintf("%s/%s", w.url.Path, path)},
+	}
+	if _, ok := w.paths[address]; ok {
+		return account
+	}
+	w.accounts = append(w.accounts, account)
+	w.paths[address] = append(accounts.DerivationPath{}, path...)
+
+	if n := len(w.next); len(path) == n && path[n-1] >= w.next[n-1] {
+		for i := 0; i < n-1; i++ {
+			if path[i] != w.next[i] {
+				return account
+			}
+		}
+		w.next[n-1] = path[n-1] + 1
+	}
+	return account
+}
+
+// URL implements accounts.Wallet, returning the URL of the seed file.
+func (w *HDWallet) URL() accounts.URL {
+	return w.url
+}
+
+// Status implements accounts.Wallet, returning whether the seed is decrypted.
+func (w *HDWallet) Status() (string, error) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	if w.seed != nil {
+		return "Unlocked", nil
+	}
+	return "Locked", nil
+}
+
+// Open implements accounts.Wallet, decrypting the seed with the passphrase so
+// that accounts can be derived and used for signing until the wallet is
+// closed.
+func (w *HDWallet) Open(passphrase string) error {
+	seed, err := decryptData(w.crypto, passphrase)
+	if err != nil {
+		return err
+	}
+	w.mu.Lock()
+	w.seed = seed
+	selfDerive := w.deriveChain != nil
+	w.mu.Unlock()
+
+	w.keystore.updateFeed.Send(accounts.WalletEvent{Wallet: w, Kind: accounts.WalletOpened})
+	if selfDerive {
+		go w.selfDerive()
+	}
+	return nil
+}
+
+// Close implements accounts.Wallet, dropping the decrypted seed.
+func (w *HDWallet) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for i := range w.seed {
+		w.seed[i] = 0
+	}
+	w.seed = nil
+	return nil
+}
+
+// Accounts implements accounts.Wallet, returning the pinned accounts.
+func (w *HDWallet) Accounts() []accounts.Account {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	cpy := make([]accounts.Account, len(w.accounts))
+	copy(cpy, w.accounts)
+	return cpy
+}
+
+// Contains implements accounts.Wallet, returning whether a particular account
+// is pinned to this wallet.
+func (w *HDWallet) Contains(account accounts.Account) bool {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	return w.contains(account)
+}
+
+func (w *HDWallet) contains(account accounts.Account) bool {
+	path, ok := w.paths[account.Address]
+	if !ok {
+		return false
+	}
+	return account.URL == (accounts.URL{}) || account.URL.Path == fmt.Sprintf("%s/%s", w.url.Path, path)
+}
+
+// Derive implements accounts.Wallet, deriving the account at path. The wallet
+// has to be open. Pinned accounts are stored in the seed file.
+func (w *HDWallet) Derive(path accounts.DerivationPath, pin bool) (accounts.Account, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.seed == nil {
+		return accounts.Account{}, ErrLocked
+	}
+	key, err := derivePrivateKey(w.seed, path)
+	if err != nil {
+		return accounts.Account{}, err
+	}
+	address := crypto.PubkeyToAddress(key.PublicKey)
+	zeroKey(key)
+
+	if !pin {
+		return accounts.Account{
+			Address: address,
+			URL:     accounts.URL{Scheme: w.url.Scheme, Path: fmt.Sprintf("%s/%s", w.url.Path, path)},
+		}, nil
+	}
+	account := w.pin(address, path)
+	return account, w.store()
+}
+
+// DeriveAccounts derives and pins the next n accounts of the sequence started
+// at accounts.DefaultBaseDerivationPath. The wallet has to be open.
+func (w *HDWallet) DeriveAccounts(n int) ([]accounts.Account, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.seed == nil {
+		return nil, ErrLocked
+	}
+	// derive the parent once, the accounts differ in the last index only
+	base := w.next[:len(w.next)-1]
+	parent, err := deriveExtendedKey(w.seed, base)
+	if err != nil {
+		return nil, err
+	}
+	derived := make([]accounts.Account, 0, n)
+	for index := w.next[len(w.next)-1]; len(derived) < n; index++ {
+		child, err := parent.child(index)
+		if err == errInvalidChild {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		key, err := crypto.ToECDSA(child.key)
+		if err != nil {
+			return nil, err
+		}
+		path := append(append(accounts.DerivationPath{}, base...), index)
+		derived = append(derived, w.pin(crypto.PubkeyToAddress(key.PublicKey), path))
+		zeroKey(key)
+	}
+	return derived, w.store()
+}
+
+// SelfDerive implements accounts.Wallet. Once the wallet is open, accounts are
+// derived from base on and pinned as long as they have a nonce or balance on
+// chain, together with the first unused one.
+func (w *HDWallet) SelfDerive(base accounts.DerivationPath, chain cpchain.ChainStateReader) {
+	w.mu.Lock()
+	w.deriveBase = append(accounts.DerivationPath{}, base...)
+	w.deriveChain = chain
+	open := w.seed != nil
+	w.mu.Unlock()
+
+	if open && chain != nil {
+		go w.selfDerive()
+	}
+}
+
+// selfDerive discovers the used accounts of the wallet.
+func (w *HDWallet) selfDerive() {
+	w.mu.RLock()
+	if w.seed == nil || w.deriveChain == nil {
+		w.mu.RUnlock()
+		return
+	}
+	seed := append([]byte{}, w.seed...)
+	path := append(accounts.DerivationPath{}, w.deriveBase...)
+	chain := w.deriveChain
+	w.mu.RUnlock()
+
+	defer func() {
+		for i := range seed {
+			seed[i] = 0
+		}
+	}()
+
+	type found struct {
+		address common.Address
+		path    accounts.DerivationPath
+	}
+	var used []found
+	for {
+		key, err := derivePrivateKey(seed, path)
+		if err != nil {
+			log.Warn("HD wallet self-derivation failed", "url", w.url, "path", path, "err", err)
+			return
+		}
+		address := crypto.PubkeyToAddress(key.PublicKey)
+		zeroKey(key)
+
+		ctx, cancel := context.WithTimeout(context.Background(), selfDeriveTimeout)
+		nonce, err := chain.NonceAt(ctx, address, nil)
+		var balance *big.Int
+		if err == nil {
+			balance, err = chain.BalanceAt(ctx, address, nil)
+		}
+		cancel()
+		if err != nil {
+			log.Warn("HD wallet self-derivation failed", "url", w.url, "err", err)
+			return
+		}
+		used = append(used, found{address, append(accounts.DerivationPath{}, path...)})
+		if nonce == 0 && balance.Sign() == 0 {
+			break
+		}
+		path[len(path)-1]++
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	pinned := len(w.accounts)
+	for _, acc := range used {
+		w.pin(acc.address, acc.path)
+	}
+	if len(w.accounts) == pinned {
+		return
+	}
+	log.Info("HD wallet self-derived accounts", "url", w.url, "count", len(w.accounts)-pinned)
+	if err := w.store(); err != nil {
+		log.Warn("Failed to store HD wallet", "url", w.url, "err", err)
+	}
+}
+
+// privateKey returns the key of an account, derived from the seed if the
+// wallet is open or taken from the keystore if the account is unlocked there.
+// The caller has to zero it.
+func (w *HDWallet) privateKey(account accounts.Account) (*ecdsa.PrivateKey, error) {
+	w.mu.RLock()
+	if !w.contains(account) {
+		w.mu.RUnlock()
+		return nil, accounts.ErrUnknownAccount
+	}
+	if w.seed != nil {
+		defer w.mu.RUnlock()
+		return derivePrivateKey(w.seed, w.paths[account.Address])
+	}
+	w.mu.RUnlock()
+
+	w.keystore.mu.RLock()
+	defer w.keystore.mu.RUnlock()
+
+	if unlocked, ok := w.keystore.unlocked[account.Address]; ok {
+		return crypto.ToECDSA(crypto.FromECDSA(unlocked.PrivateKey))
+	}
+	return nil, ErrLocked
+}
+
+// decryptKey derives the key of an account with the passphrase of the wallet.
+func (w *HDWallet) decryptKey(account accounts.Account, passphrase string) (*Key, error) {
+	w.mu.RLock()
+	path, ok := w.paths[account.Address]
+	w.mu.RUnlock()
+	if !ok {
+		return nil, accounts.ErrUnknownAccount
+	}
+	seed, err := decryptData(w.crypto, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		for i := range seed {
+			seed[i] = 0
+		}
+	}()
+	priv, err := derivePrivateKey(seed, path)
+	if err != nil {
+		return nil, err
+	}
+	key := newKeyFromECDSA(priv)
+	key.EciesPrivateKey = ecies.ImportECDSA(priv)
+	return key, nil
+}
+
+// SignHash implements accounts.Wallet.
+func (w *HDWallet) SignHash(account accounts.Account, hash []byte) ([]byte, error) {
+	key, err := w.privateKey(account)
+	if err != nil {
+		return nil, err
+	}
+	defer zeroKey(key)
+	return crypto.Sign(hash, key)
+}
+
+// SignTx implements accounts.Wallet.
+func (w *HDWallet) SignTx(account accounts.Account, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	key, err := w.privateKey(account)
+	if err != nil {
+		return nil, err
+	}
+	defer zeroKey(key)
+	return signTx(tx, chainID, key)
+}
+
+// SignHashWithPassphrase implements accounts.Wallet, decrypting the seed with
+// the passphrase for the single signature.
+func (w *HDWallet) SignHashWithPassphrase(account accounts.Account, passphrase string, hash []byte) ([]byte, error) {
+	key, err := w.decryptKey(account, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	defer zeroKey(key.PrivateKey)
+	return crypto.Sign(hash, key.PrivateKey)
+}
+
+// SignTxWithPassphrase implements accounts.Wallet, decrypting the seed with the
+// passphrase for the single signature.
+func (w *HDWallet) SignTxWithPassphrase(account accounts.Account, passphrase string, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	key, err := w.decryptKey(account, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	defer zeroKey(key.PrivateKey)
+	return signTx(tx, chainID, key.PrivateKey)
+}
+
+// DecryptWithEcies implements accounts.Wallet.
+func (w *HDWallet) DecryptWithEcies(account accounts.Account, cipherText []byte) ([]byte, error) {
+	key, err := w.privateKey(account)
+	if err != nil {
+		return nil, err
+	}
+	defer zeroKey(key)
+	return ecieskey.Decrypt(ecies.ImportECDSA(key), cipherText)
+}
+
+// PublicKey implements accounts.Wallet.
+func (w *HDWallet) PublicKey(account accounts.Account) ([]byte, error) {
+	key, err := w.privateKey(account)
+	if err != nil {
+		return nil, err
+	}
+	defer zeroKey(key)
+	return ecieskey.EncodeEcdsaPubKey(&key.PublicKey), nil
+}
@@ -0,0 +1,263 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package keystore
+
+import (
+	"context"
+	"encoding/hex"
+	"math/big"
+	"os"
+	"testing"
+	"time"
+
+	"bitbucket.org/cpchain/chain/accounts"
+	"bitbucket.org/cpchain/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const testMnemonic = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+
+// Tests the private keys of BIP-32 test vector 1.
+func TestBIP32Vector(t *testing.T) {
+	seed, _ := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	tests := []struct {
+		path string
+		key  string
+	}{
+		{"m/0'", "edb2e14f9ee77d26dd93b4ecede8d16ed408ce149b6cd80b0715a2d911a0afea"},
+		{"m/0'/1", "3c6cb8d0f6a264c91ea8b5030fadaa8e538b020f0a387421a12de9319dc93368"},
+		{"m/0'/1/2'", "cbce0d719ecf7431d88e6a89fa1483e02e35092af60c042b1df2ff59fa424dca"},
+		{"m/0'/1/2'/2", "0f479245fb19a38a1954c5c7c0ebab2f9bdfd96a17563ef28a6a4b1a2a764ef4"},
+		{"m/0'/1/2'/2/1000000000", "471b76e389e528d6de6d816857e012c5455051cad6660850e58372a6c3e6e7c8"},
+	}
+	master, err := newMasterKey(seed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if have := hex.EncodeToString(master.key); have != "e8f32e723decf4051aefac8e2c93c9c5b214313817cdb01a1494b917c8436b35" {
+		t.Errorf("master key mismatch: have %s", have)
+	}
+	for _, test := range tests {
+		path, err := accounts.ParseDerivationPath(test.path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		key, err := derivePrivateKey(seed, path)
+		if err != nil {
+			t.Fatalf("%s: %v", test.path, err)
+		}
+		if have := hex.EncodeToString(crypto.FromECDSA(key)); have != test.key {
+			t.Errorf("%s: key mismatch: have %s, want %s", test.path, have, test.key)
+		}
+	}
+}
+
+func TestImportMnemonic(t *testing.T) {
+	dir, ks := tmpKeyStore(t, true)
+	defer os.RemoveAll(dir)
+
+	if _, err := ks.ImportMnemonic("abandon abandon", "pass"); err != ErrInvalidMnemonic {
+		t.Fatalf("error mismatch: have %v, want %v", err, ErrInvalidMnemonic)
+	}
+	w, err := ks.ImportMnemonic(testMnemonic, "pass")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := common.HexToAddress("0x9858EfFD232B4033E47d90003D41EC34EcaEda94")
+	if accs := w.Accounts(); len(accs) != 1 || accs[0].Address != want {
+		t.Fatalf("account mismatch: have %v, want [%x]", accs, want)
+	}
+	if _, err := ks.ImportMnemonic(testMnemonic, "other"); err != ErrHDWalletExists {
+		t.Errorf("error mismatch: have %v, want %v", err, ErrHDWalletExists)
+	}
+	if len(ks.Wallets()) != 1 || ks.Wallets()[0] != accounts.Wallet(w) {
+		t.Errorf("HD wallet not listed by the keystore")
+	}
+	// the key files are untouched
+	if len(ks.Accounts()) != 0 {
+		t.Errorf("HD account listed as key file")
+	}
+}
+
+func TestHDWalletDerive(t *testing.T) {
+	dir, ks := tmpKeyStore(t, true)
+	defer os.RemoveAll(dir)
+
+	w, err := ks.ImportMnemonic(testMnemonic, "pass")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.DeriveAccounts(3); err != ErrLocked {
+		t.Fatalf("derived while locked: %v", err)
+	}
+	if err := w.Open("wrong"); err != ErrDecrypt {
+		t.Fatalf("error mismatch: have %v, want %v", err, ErrDecrypt)
+	}
+	if err := w.Open("pass"); err != nil {
+		t.Fatal(err)
+	}
+	derived, err := w.DeriveAccounts(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, acc := range derived {
+		path := append(accounts.DerivationPath{}, accounts.DefaultBaseDerivationPath...)
+		path[len(path)-1] = uint32(i + 1)
+		single, err := w.Derive(path, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if single.Address != acc.Address || single.URL != acc.URL {
+			t.Errorf("account %d mismatch: have %v, want %v", i+1, acc, single)
+		}
+	}
+	custom, err := accounts.ParseDerivationPath("m/44'/60'/1'/0/0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Derive(custom, true); err != nil {
+		t.Fatal(err)
+	}
+	// the seed file keeps the accounts and the sequence
+	reloaded := NewKeyStore(dir, veryLightScryptN, veryLightScryptP).HDWallets()
+	if len(reloaded) != 1 {
+		t.Fatalf("HD wallet count mismatch: have %d, want 1", len(reloaded))
+	}
+	if have, want := reloaded[0].Accounts(), w.Accounts(); len(have) != 5 || len(want) != 5 {
+		t.Fatalf("account count mismatch: have %d, want 5", len(have))
+	} else {
+		for i := range want {
+			if have[i] != want[i] {
+				t.Errorf("account %d mismatch: have %v, want %v", i, have[i], want[i])
+			}
+		}
+	}
+	if next := reloaded[0].next.String(); next != "m/44'/60'/0'/0/4" {
+		t.Errorf("next path mismatch: have %s", next)
+	}
+}
+
+func TestHDWalletSign(t *testing.T) {
+	dir, ks := tmpKeyStore(t, true)
+	defer os.RemoveAll(dir)
+
+	w, err := ks.ImportMnemonic(testMnemonic, "pass")
+	if err != nil {
+		t.Fatal(err)
+	}
+	account := w.Accounts()[0]
+	hash := crypto.Keccak256([]byte("hash"))
+
+	if _, err := w.SignHash(account, hash); err != ErrLocked {
+		t.Fatalf("signed while locked: %v", err)
+	}
+	check := func(sig []byte) {
+		pub, err := crypto.SigToPub(hash, sig)
+		if err != nil || crypto.PubkeyToAddress(*pub) != account.Address {
+			t.Errorf("signature not made by %x", account.Address)
+		}
+	}
+	sig, err := w.SignHashWithPassphrase(account, "pass", hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	check(sig)
+
+	// accounts can be unlocked like key files, e.g. by --unlock
+	if err := ks.Unlock(accounts.Account{Address: account.Address}, "pass"); err != nil {
+		t.Fatal(err)
+	}
+	if sig, err = ks.SignHash(account, hash); err != nil {
+		t.Fatal(err)
+	}
+	check(sig)
+	if sig, err = w.SignHash(account, hash); err != nil {
+		t.Fatal(err)
+	}
+	check(sig)
+
+	tx := types.NewTransaction(0, common.Address{1}, big.NewInt(1), 21000, big.NewInt(1), nil)
+	signed, err := w.SignTxWithPassphrase(account, "pass", tx, big.NewInt(42))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if from, err := types.Sender(types.NewCep1Signer(big.NewInt(42)), signed); err != nil || from != account.Address {
+		t.Errorf("sender mismatch: have %x (%v), want %x", from, err, account.Address)
+	}
+}
+
+// testChainState reports the accounts with a nonce.
+type testChainState map[common.Address]uint64
+
+func (s testChainState) BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error) {
+	return new(big.Int), nil
+}
+
+func (s testChainState) StorageAt(ctx context.Context, account common.Address, key common.Hash, blockNumber *big.Int) ([]byte, error) {
+	return nil, nil
+}
+
+func (s testChainState) CodeAt(ctx context.Context, account common.Address, blockNumber *big.Int) ([]byte, error) {
+	return nil, nil
+}
+
+func (s testChainState) NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error) {
+	return s[account], nil
+}
+
+func TestHDWalletSelfDerive(t *testing.T) {
+	dir, ks := tmpKeyStore(t, true)
+	defer os.RemoveAll(dir)
+
+	w, err := ks.ImportMnemonic(testMnemonic, "pass")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Open("pass"); err != nil {
+		t.Fatal(err)
+	}
+	// the first three accounts have been used
+	state := make(testChainState)
+	for i := 0; i < 3; i++ {
+		path := append(accounts.DerivationPath{}, accounts.DefaultBaseDerivationPath...)
+		path[len(path)-1] = uint32(i)
+		acc, err := w.Derive(path, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		state[acc.Address] = 1
+	}
+	w.SelfDerive(accounts.DefaultBaseDerivationPath, state)
+
+	// used accounts and the first unused one are pinned
+	for i := 0; i < 100 && len(w.Accounts()) < 4; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	accs := w.Accounts()
+	if len(accs) != 4 {
+		t.Fatalf("account count mismatch: have %d, want 4", len(accs))
+	}
+	for _, acc := range accs[:3] {
+		if state[acc.Address] != 1 {
+			t.Errorf("unused account %x pinned", acc.Address)
+		}
+	}
+	if state[accs[3].Address] != 0 {
+		t.Errorf("first unused account mismatch")
+	}
+}
@@ -64,6 +64,7 @@ type KeyStore struct {
 	unlocked map[common.Address]*unlocked // Currently unlocked account (decrypted private keys)
 
 	wallets     []accounts.Wallet       // Wallet wrappers around the individual key files
+	hdWallets   []*HDWallet             // HD wallets of the seed files
 	updateFeed  event.Feed              // Event feed to notify wallet additions/removals
 	updateScope event.SubscriptionScope // Subscription scope tracking current live listeners
 	updating    bool                    // Whether the event notification loop is running
@@ -114,10 +115,19 @@ func (ks *KeyStore) init(keydir string) {
 	for i := 0; i < len(accs); i++ {
 		ks.wallets[i] = &keystoreWallet{account: accs[i], keystore: ks}
 	}
+	ks.hdWallets = loadHDWallets(ks)
+}
+
+// scryptParams returns the scrypt parameters new files are encrypted with.
+func (ks *KeyStore) scryptParams() (int, int) {
+	if storage, ok := ks.storage.(*keyStorePassphrase); ok {
+		return storage.scryptN, storage.scryptP
+	}
+	return LightScryptN, LightScryptP
 }
 
 // Wallets implements accounts.Backend, returning all single-key wallets from the
-// keystore directory.
+// keystore directory, followed by the HD wallets.
 func (ks *KeyStore) Wallets() []accounts.Wallet {
 	// Make sure the list of wallets is in sync with the account cache
 	ks.refreshWallets()
@@ -125,11 +135,64 @@ func (ks *KeyStore) Wallets() []accounts.Wallet {
 	ks.mu.RLock()
 	defer ks.mu.RUnlock()
 
-	cpy := make([]accounts.Wallet, len(ks.wallets))
+	cpy := make([]accounts.Wallet, len(ks.wallets), len(ks.wallets)+len(ks.hdWallets))
 	copy(cpy, ks.wallets)
+	for _, w := range ks.hdWallets {
+		cpy = append(cpy, w)
+	}
+	return cpy
+}
+
+// HDWallets returns the HD wallets of the keystore directory.
+func (ks *KeyStore) HDWallets() []*HDWallet {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	cpy := make([]*HDWallet, len(ks.hdWallets))
+	copy(cpy, ks.hdWallets)
 	return cpy
 }
 
+// ImportMnemonic creates an HD wallet from a BIP-39 mnemonic, storing its
+// seed encrypted with passphrase. The wallet holds the account at
+// accounts.DefaultBaseDerivationPath, more can be derived once it is open.
+func (ks *KeyStore) ImportMnemonic(mnemonic, passphrase string) (*HDWallet, error) {
+	seed, err := mnemonicToSeed(mnemonic)
+	if err != nil {
+		return nil, err
+	}
+	w, err := newHDWallet(ks, seed, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	ks.mu.Lock()
+	for _, existing := range ks.hdWallets {
+		if existing.address == w.address {
+			ks.mu.Unlock()
+			return nil, ErrHDWalletExists
+		}
+	}
+	if err := w.store(); err != nil {
+		ks.mu.Unlock()
+		return nil, err
+	}
+	ks.hdWallets = append(ks.hdWallets, w)
+	ks.mu.Unlock()
+
+	ks.updateFeed.Send(accounts.WalletEvent{Wallet: w, Kind: accounts.WalletArrived})
+	return w, nil
+}
+
+// hdWallet returns the HD wallet holding an account, if any.
+func (ks *KeyStore) hdWallet(a accounts.Account) *HDWallet {
+	for _, w := range ks.HDWallets() {
+		if w.Contains(a) {
+			return w
+		}
+	}
+	return nil
+}
+
 // refreshWallets retrieves the current account list and based on that does any
 // necessary wallet refreshes.
 func (ks *KeyStore) refreshWallets() {
@@ -278,18 +341,23 @@ func (ks *KeyStore) SignTx(a accounts.Account, tx *types.Transaction, chainID *b
 	if !found {
 		return nil, ErrLocked
 	}
-	// Depending on the presence of the chain ID, sign with cep1 or homestead
+	return signTx(tx, chainID, unlockedKey.PrivateKey)
+}
+
+// signTx signs tx with key, depending on the presence of the chain ID with
+// cep1 or homestead.
+func signTx(tx *types.Transaction, chainID *big.Int, key *ecdsa.PrivateKey) (*types.Transaction, error) {
 	if chainID != nil {
-		return types.SignTx(tx, types.NewCep1Signer(chainID), unlockedKey.PrivateKey)
+		return types.SignTx(tx, types.NewCep1Signer(chainID), key)
 	}
-	return types.SignTx(tx, types.HomesteadSigner{}, unlockedKey.PrivateKey)
+	return types.SignTx(tx, types.HomesteadSigner{}, key)
 }
 
 // SignHashWithPassphrase signs hash if the private key matching the given address
 // can be decrypted with the given passphrase. The produced signature is in the
 // [R || S || V] format where V is 0 or 1.
 func (ks *KeyStore) SignHashWithPassphrase(a accounts.Account, passphrase string, hash []byte) (signature []byte, err error) {
-	_, key, err := ks.getDecryptedKey(a, passphrase)
+	_, key, err := ks.getDecryptedAccountKey(a, passphrase)
 	if err != nil {
 		return nil, err
 	}
@@ -300,17 +368,12 @@ func (ks *KeyStore) SignHashWithPassphrase(a accounts.Account, passphrase string
 // SignTxWithPassphrase signs the transaction if the private key matching the
 // given address can be decrypted with the given passphrase.
 func (ks *KeyStore) SignTxWithPassphrase(a accounts.Account, passphrase string, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
-	_, key, err := ks.getDecryptedKey(a, passphrase)
+	_, key, err := ks.getDecryptedAccountKey(a, passphrase)
 	if err != nil {
 		return nil, err
 	}
 	defer zeroKey(key.PrivateKey)
-
-	// Depending on the presence of the chain ID, sign with cep1 or homestead
-	if chainID != nil {
-		return types.SignTx(tx, types.NewCep1Signer(chainID), key.PrivateKey)
-	}
-	return types.SignTx(tx, types.HomesteadSigner{}, key.PrivateKey)
+	return signTx(tx, chainID, key.PrivateKey)
 }
 
 // Unlock unlocks the given account indefinitely.
@@ -338,7 +401,7 @@ func (ks *KeyStore) Lock(addr common.Address) error {
 // shortens the active unlock timeout. If the address was previously unlocked
 // indefinitely the timeout is not altered.
 func (ks *KeyStore) TimedUnlock(a accounts.Account, passphrase string, timeout time.Duration) error {
-	a, key, err := ks.getDecryptedKey(a, passphrase)
+	a, key, err := ks.getDecryptedAccountKey(a, passphrase)
 	if err != nil {
 		return err
 	}
@@ -384,9 +447,25 @@ func (ks *KeyStore) getDecryptedKey(a accounts.Account, auth string) (accounts.A
 	return a, key, err
 }
 
-// GetDecryptedKey returns private key of account with given passphrase.
+// getDecryptedAccountKey is getDecryptedKey falling back on the accounts of
+// the HD wallets.
+func (ks *KeyStore) getDecryptedAccountKey(a accounts.Account, auth string) (accounts.Account, *Key, error) {
+	found, key, err := ks.getDecryptedKey(a, auth)
+	if err != ErrNoMatch {
+		return found, key, err
+	}
+	w := ks.hdWallet(a)
+	if w == nil {
+		return found, key, err
+	}
+	key, err = w.decryptKey(a, auth)
+	return a, key, err
+}
+
+// GetDecryptedKey returns private key of account with given passphrase, the
+// account may belong to a key file or an HD wallet.
 func (ks *KeyStore) GetDecryptedKey(a accounts.Account, passphrase string) (accounts.Account, *Key, error) {
-	a, key, err := ks.getDecryptedKey(a, passphrase)
+	a, key, err := ks.getDecryptedAccountKey(a, passphrase)
 	return a, key, err
 }
 
@@ -0,0 +1,272 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package pkcs11 implements an accounts.Backend whose secp256k1 keys are held
+// by a PKCS#11 token, such as a hardware security module. The node signs
+// transactions and Dpor headers and unwraps private transaction keys on the
+// token, the private keys never enter the process.
+package pkcs11
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"reflect"
+	"strings"
+	"sync"
+
+	"bitbucket.org/cpchain/chain"
+	"bitbucket.org/cpchain/chain/accounts"
+	"bitbucket.org/cpchain/chain/commons/log"
+	"bitbucket.org/cpchain/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+// Scheme is the URL scheme of PKCS#11 wallets.
+const Scheme = "pkcs11"
+
+// BackendType is the reflect type of a PKCS#11 backend.
+var BackendType = reflect.TypeOf(&Backend{})
+
+// Config selects the token holding the keys.
+type Config struct {
+	// Module is the path of the PKCS#11 library of the token, e.g.
+	// /usr/lib/softhsm/libsofthsm2.so.
+	Module string `toml:",omitempty"`
+
+	// Token is the label of the token, the first token of the module if empty.
+	Token string `toml:",omitempty"`
+
+	// PinFile is the file holding the user PIN of the token.
+	PinFile string `toml:",omitempty"`
+}
+
+// Backend is an accounts.Backend with a single wallet, the token.
+type Backend struct {
+	wallets []accounts.Wallet
+}
+
+// NewBackend loads the module of the config and logs into its token. A wrong
+// PIN or a missing token fail here rather than at the first signature.
+func NewBackend(conf Config) (*Backend, error) {
+	pin := ""
+	if conf.PinFile != "" {
+		data, err := ioutil.ReadFile(conf.PinFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read PIN file: %v", err)
+		}
+		pin = strings.TrimRight(string(data), "\r\n")
+	}
+	tok, err := openToken(conf.Module, conf.Token, pin)
+	if err != nil {
+		return nil, err
+	}
+	wallet, err := newWallet(tok)
+	if err != nil {
+		tok.close()
+		return nil, err
+	}
+	log.Info("Opened PKCS#11 token", "module", conf.Module, "token", tok.name(), "accounts", len(wallet.accounts))
+	return &Backend{wallets: []accounts.Wallet{wallet}}, nil
+}
+
+// Wallets implements accounts.Backend.
+func (b *Backend) Wallets() []accounts.Wallet {
+	return b.wallets
+}
+
+// Subscribe implements accounts.Backend. The token stays logged in for the
+// lifetime of the backend, so no wallet events are ever sent.
+func (b *Backend) Subscribe(sink chan<- accounts.WalletEvent) event.Subscription {
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		<-quit
+		return nil
+	})
+}
+
+// Wallet implements accounts.Wallet on top of a PKCS#11 token.
+type Wallet struct {
+	token token
+	url   accounts.URL
+
+	mu       sync.RWMutex
+	accounts []accounts.Account
+	keys     map[common.Address]tokenKey
+}
+
+func newWallet(tok token) (*Wallet, error) {
+	w := &Wallet{token: tok, url: accounts.URL{Scheme: Scheme, Path: tok.name()}}
+	if err := w.refresh(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// refresh lists the keys of the token.
+func (w *Wallet) refresh() error {
+	keys, err := w.token.keys()
+	if err != nil {
+		return err
+	}
+	accs := make([]accounts.Account, 0, len(keys))
+	byAddr := make(map[common.Address]tokenKey, len(keys))
+	for _, key := range keys {
+		addr := crypto.PubkeyToAddress(*key.pub)
+		if _, ok := byAddr[addr]; ok {
+			continue
+		}
+		byAddr[addr] = key
+		accs = append(accs, accounts.Account{
+			Address: addr,
+			URL:     accounts.URL{Scheme: Scheme, Path: fmt.Sprintf("%s/%s", w.url.Path, key.label)},
+		})
+	}
+	w.mu.Lock()
+	w.accounts, w.keys = accs, byAddr
+	w.mu.Unlock()
+	return nil
+}
+
+func (w *Wallet) key(account accounts.Account) (tokenKey, error) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	key, ok := w.keys[account.Address]
+	if !ok {
+		return tokenKey{}, accounts.ErrUnknownAccount
+	}
+	return key, nil
+}
+
+// URL implements accounts.Wallet.
+func (w *Wallet) URL() accounts.URL {
+	return w.url
+}
+
+// Status implements accounts.Wallet.
+func (w *Wallet) Status() (string, error) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	return fmt.Sprintf("Token %s, %d keys", w.token.name(), len(w.accounts)), nil
+}
+
+// Open implements accounts.Wallet, it lists the keys of the token again as
+// the login happens in NewBackend.
+func (w *Wallet) Open(passphrase string) error {
+	return w.refresh()
+}
+
+// Close implements accounts.Wallet, it is a noop so that the wallet can be
+// reopened, the session lives as long as the process.
+func (w *Wallet) Close() error { return nil }
+
+// Accounts implements accounts.Wallet, returning the secp256k1 keys of the
+// token.
+func (w *Wallet) Accounts() []accounts.Account {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	cpy := make([]accounts.Account, len(w.accounts))
+	copy(cpy, w.accounts)
+	return cpy
+}
+
+// Contains implements accounts.Wallet.
+func (w *Wallet) Contains(account accounts.Account) bool {
+	for _, acc := range w.Accounts() {
+		if acc.Address == account.Address && (account.URL == (accounts.URL{}) || account.URL == acc.URL) {
+			return true
+		}
+	}
+	return false
+}
+
+// Derive implements accounts.Wallet, but is a noop as tokens have no notion
+// of hierarchical account derivation.
+func (w *Wallet) Derive(path accounts.DerivationPath, pin bool) (accounts.Account, error) {
+	return accounts.Account{}, accounts.ErrNotSupported
+}
+
+// SelfDerive implements accounts.Wallet, but is a noop as tokens have no
+// notion of hierarchical account derivation.
+func (w *Wallet) SelfDerive(base accounts.DerivationPath, chain cpchain.ChainStateReader) {}
+
+// SignHash implements accounts.Wallet. The produced signature is in the
+// [R || S || V] format where V is 0 or 1.
+func (w *Wallet) SignHash(account accounts.Account, hash []byte) ([]byte, error) {
+	key, err := w.key(account)
+	if err != nil {
+		return nil, err
+	}
+	rs, err := w.token.sign(key.handle, hash)
+	if err != nil {
+		return nil, err
+	}
+	return recoverableSignature(rs, hash, key.pub)
+}
+
+// SignTx implements accounts.Wallet, depending on the presence of the chain
+// ID the transaction is signed with cep1 or homestead.
+func (w *Wallet) SignTx(account accounts.Account, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	var signer types.Signer = types.HomesteadSigner{}
+	if chainID != nil {
+		signer = types.NewCep1Signer(chainID)
+	}
+	h := signer.Hash(tx)
+	sig, err := w.SignHash(account, h[:])
+	if err != nil {
+		return nil, err
+	}
+	return tx.WithSignature(signer, sig)
+}
+
+// SignHashWithPassphrase implements accounts.Wallet, the token is unlocked by
+// its PIN so it is not supported.
+func (w *Wallet) SignHashWithPassphrase(account accounts.Account, passphrase string, hash []byte) ([]byte, error) {
+	return nil, accounts.ErrNotSupported
+}
+
+// SignTxWithPassphrase implements accounts.Wallet, the token is unlocked by
+// its PIN so it is not supported.
+func (w *Wallet) SignTxWithPassphrase(account accounts.Account, passphrase string, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return nil, accounts.ErrNotSupported
+}
+
+// DecryptWithEcies implements accounts.Wallet, the ECDH key agreement runs on
+// the token.
+func (w *Wallet) DecryptWithEcies(account accounts.Account, cipherText []byte) ([]byte, error) {
+	key, err := w.key(account)
+	if err != nil {
+		return nil, err
+	}
+	return decryptEcies(func(pub *ecdsa.PublicKey) ([]byte, error) {
+		return w.token.deriveShared(key.handle, pub)
+	}, cipherText)
+}
+
+// PublicKey implements accounts.Wallet, returning the uncompressed public key
+// of the account.
+func (w *Wallet) PublicKey(account accounts.Account) ([]byte, error) {
+	key, err := w.key(account)
+	if err != nil {
+		return nil, err
+	}
+	return crypto.FromECDSAPub(key.pub), nil
+}
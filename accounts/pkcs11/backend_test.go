@@ -0,0 +1,225 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package pkcs11
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"os"
+	"testing"
+
+	"bitbucket.org/cpchain/chain/accounts"
+	"bitbucket.org/cpchain/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/crypto/ecies"
+)
+
+// softToken is a token holding its keys in memory. It returns high s values
+// half of the time, as hardware tokens do.
+type softToken struct {
+	privs []*ecdsa.PrivateKey
+	signs int
+}
+
+func newSoftToken(t *testing.T, n int) *softToken {
+	tok := new(softToken)
+	for i := 0; i < n; i++ {
+		key, err := crypto.GenerateKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		tok.privs = append(tok.privs, key)
+	}
+	return tok
+}
+
+func (tok *softToken) name() string { return "soft" }
+
+func (tok *softToken) keys() ([]tokenKey, error) {
+	var keys []tokenKey
+	for i, key := range tok.privs {
+		keys = append(keys, tokenKey{handle: uint64(i), label: fmt.Sprintf("key%d", i), pub: &key.PublicKey})
+	}
+	return keys, nil
+}
+
+func (tok *softToken) sign(handle uint64, hash []byte) ([]byte, error) {
+	sig, err := crypto.Sign(hash, tok.privs[handle])
+	if err != nil {
+		return nil, err
+	}
+	tok.signs++
+	if tok.signs%2 == 0 {
+		s := new(big.Int).Sub(secp256k1N, new(big.Int).SetBytes(sig[32:64]))
+		copy(sig[32:64], common.LeftPadBytes(s.Bytes(), 32))
+	}
+	return sig[:64], nil
+}
+
+func (tok *softToken) deriveShared(handle uint64, pub *ecdsa.PublicKey) ([]byte, error) {
+	x, _ := pub.Curve.ScalarMult(pub.X, pub.Y, tok.privs[handle].D.Bytes())
+	return x.Bytes(), nil
+}
+
+func (tok *softToken) close() error { return nil }
+
+func TestWalletSignHash(t *testing.T) {
+	tok := newSoftToken(t, 2)
+	w, err := newWallet(tok)
+	if err != nil {
+		t.Fatal(err)
+	}
+	accs := w.Accounts()
+	if len(accs) != 2 {
+		t.Fatalf("accounts mismatch: have %d, want 2", len(accs))
+	}
+	hash := crypto.Keccak256([]byte("header"))
+	for i, acc := range accs {
+		if acc.Address != crypto.PubkeyToAddress(tok.privs[i].PublicKey) {
+			t.Fatalf("account %d: address mismatch", i)
+		}
+		// both the low and the high s signature of the token
+		for j := 0; j < 2; j++ {
+			sig, err := w.SignHash(acc, hash)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if new(big.Int).SetBytes(sig[32:64]).Cmp(secp256k1HalfN) > 0 {
+				t.Fatal("signature s not normalized")
+			}
+			pub, err := crypto.SigToPub(hash, sig)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if crypto.PubkeyToAddress(*pub) != acc.Address {
+				t.Fatalf("account %d: signer mismatch", i)
+			}
+		}
+	}
+	if _, err := w.SignHash(accounts.Account{Address: common.Address{1}}, hash); err != accounts.ErrUnknownAccount {
+		t.Fatalf("unknown account: have %v, want %v", err, accounts.ErrUnknownAccount)
+	}
+}
+
+func TestWalletSignTx(t *testing.T) {
+	w, err := newWallet(newSoftToken(t, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	acc := w.Accounts()[0]
+	chainID := big.NewInt(42)
+	tx := types.NewTransaction(0, common.Address{2}, big.NewInt(1), 21000, big.NewInt(1), nil)
+	signed, err := w.SignTx(acc, tx, chainID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	from, err := types.Sender(types.NewCep1Signer(chainID), signed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if from != acc.Address {
+		t.Fatalf("sender mismatch: have %x, want %x", from, acc.Address)
+	}
+}
+
+func TestWalletDecryptWithEcies(t *testing.T) {
+	tok := newSoftToken(t, 1)
+	w, err := newWallet(tok)
+	if err != nil {
+		t.Fatal(err)
+	}
+	acc := w.Accounts()[0]
+	pub, err := w.PublicKey(acc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecdsaPub, err := crypto.UnmarshalPubkey(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg := []byte("symmetric key of a private transaction")
+	ct, err := ecies.Encrypt(rand.Reader, ecies.ImportECDSAPublic(ecdsaPub), msg, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := w.DecryptWithEcies(acc, ct)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(m, msg) {
+		t.Fatalf("plaintext mismatch: have %q, want %q", m, msg)
+	}
+	// tampered messages are rejected
+	ct[len(ct)-1] ^= 1
+	if _, err := w.DecryptWithEcies(acc, ct); err != errInvalidMessage {
+		t.Fatalf("tampered message: have %v, want %v", err, errInvalidMessage)
+	}
+}
+
+// TestSoftHSM runs against a token of SoftHSM or another module, e.g.
+//
+//	softhsm2-util --init-token --free --label cpchain --pin 1234 --so-pin 1234
+//	pkcs11-tool --module $MODULE --login --pin 1234 --token-label cpchain \
+//	  --keypairgen --key-type EC:secp256k1 --id 01 --label validator
+//	CPCHAIN_PKCS11_MODULE=$MODULE CPCHAIN_PKCS11_TOKEN=cpchain CPCHAIN_PKCS11_PIN=/path/to/pin go test
+func TestSoftHSM(t *testing.T) {
+	module := os.Getenv("CPCHAIN_PKCS11_MODULE")
+	if module == "" {
+		t.Skip("CPCHAIN_PKCS11_MODULE not set")
+	}
+	backend, err := NewBackend(Config{
+		Module:  module,
+		Token:   os.Getenv("CPCHAIN_PKCS11_TOKEN"),
+		PinFile: os.Getenv("CPCHAIN_PKCS11_PIN"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := backend.Wallets()[0]
+	accs := w.Accounts()
+	if len(accs) == 0 {
+		t.Fatal("no secp256k1 keys on the token")
+	}
+	acc := accs[0]
+
+	hash := crypto.Keccak256([]byte("header"))
+	sig, err := w.SignHash(acc, hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub, err := crypto.SigToPub(hash, sig)
+	if err != nil || crypto.PubkeyToAddress(*pub) != acc.Address {
+		t.Fatalf("signer mismatch: %v", err)
+	}
+
+	msg := []byte("symmetric key of a private transaction")
+	ct, err := ecies.Encrypt(rand.Reader, ecies.ImportECDSAPublic(pub), msg, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := w.DecryptWithEcies(acc, ct)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(m, msg) {
+		t.Fatalf("plaintext mismatch: have %q, want %q", m, msg)
+	}
+}
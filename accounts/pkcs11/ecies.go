@@ -0,0 +1,96 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package pkcs11
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/binary"
+	"errors"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// ECIES parameters of secp256k1 keys in the go-ethereum ecies package, i.e.
+// ECIES_AES128_SHA256, which KeyStore.DecryptWithEcies decrypts with.
+const (
+	eciesKeyLen   = 16 // AES-128
+	eciesPointLen = 65 // uncompressed ephemeral public key
+)
+
+var errInvalidMessage = errors.New("pkcs11: invalid ECIES message")
+
+// decryptEcies decrypts an ECIES message encrypted to the public key of the
+// key pair whose ECDH secrets derive returns. The token only takes part in the
+// key agreement, the rest of ecies.PrivateKey.Decrypt is replayed here.
+func decryptEcies(derive func(*ecdsa.PublicKey) ([]byte, error), c []byte) ([]byte, error) {
+	if len(c) < eciesPointLen+sha256.Size+aes.BlockSize || c[0] != 0x04 {
+		return nil, errInvalidMessage
+	}
+	ephemeral, err := crypto.UnmarshalPubkey(c[:eciesPointLen])
+	if err != nil {
+		return nil, errInvalidMessage
+	}
+	secret, err := derive(ephemeral)
+	if err != nil {
+		return nil, err
+	}
+	// the shared secret is the left padded x coordinate of the shared point
+	z := make([]byte, 2*eciesKeyLen)
+	if len(secret) > len(z) {
+		return nil, errInvalidMessage
+	}
+	copy(z[len(z)-len(secret):], secret)
+
+	k := concatKDF(z, 2*eciesKeyLen)
+	ke := k[:eciesKeyLen]
+	km := sha256.Sum256(k[eciesKeyLen:])
+
+	em := c[eciesPointLen : len(c)-sha256.Size]
+	mac := hmac.New(sha256.New, km[:])
+	mac.Write(em)
+	if subtle.ConstantTimeCompare(c[len(c)-sha256.Size:], mac.Sum(nil)) != 1 {
+		return nil, errInvalidMessage
+	}
+
+	block, err := aes.NewCipher(ke)
+	if err != nil {
+		return nil, err
+	}
+	m := make([]byte, len(em)-aes.BlockSize)
+	cipher.NewCTR(block, em[:aes.BlockSize]).XORKeyStream(m, em[aes.BlockSize:])
+	return m, nil
+}
+
+// concatKDF is the NIST SP 800-56 concatenation key derivation function with
+// SHA-256 and no shared info.
+func concatKDF(z []byte, length int) []byte {
+	var k []byte
+	counter := make([]byte, 4)
+	for i := uint32(1); len(k) < length; i++ {
+		binary.BigEndian.PutUint32(counter, i)
+		h := sha256.New()
+		h.Write(counter)
+		h.Write(z)
+		k = h.Sum(k)
+	}
+	return k[:length]
+}
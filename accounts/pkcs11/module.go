@@ -0,0 +1,577 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+// +build cgo,!windows
+
+package pkcs11
+
+/*
+#cgo linux LDFLAGS: -ldl
+
+#include <stdlib.h>
+#include <string.h>
+#include <dlfcn.h>
+
+// The subset of the PKCS#11 v2.20 header used by the wallet, see
+// http://docs.oasis-open.org/pkcs11/pkcs11-base/v2.40/pkcs11-base-v2.40.html
+
+typedef unsigned char CK_BYTE;
+typedef unsigned long CK_ULONG;
+typedef CK_ULONG CK_RV;
+typedef CK_ULONG CK_SLOT_ID;
+typedef CK_ULONG CK_SESSION_HANDLE;
+typedef CK_ULONG CK_OBJECT_HANDLE;
+typedef CK_ULONG CK_FLAGS;
+
+typedef struct { CK_BYTE major; CK_BYTE minor; } CK_VERSION;
+
+typedef struct {
+	CK_ULONG type;
+	void *pValue;
+	CK_ULONG ulValueLen;
+} CK_ATTRIBUTE;
+
+typedef struct {
+	CK_ULONG mechanism;
+	void *pParameter;
+	CK_ULONG ulParameterLen;
+} CK_MECHANISM;
+
+typedef struct {
+	CK_ULONG kdf;
+	CK_ULONG ulSharedDataLen;
+	CK_BYTE *pSharedData;
+	CK_ULONG ulPublicDataLen;
+	CK_BYTE *pPublicData;
+} CK_ECDH1_DERIVE_PARAMS;
+
+typedef struct {
+	void *CreateMutex;
+	void *DestroyMutex;
+	void *LockMutex;
+	void *UnlockMutex;
+	CK_FLAGS flags;
+	void *pReserved;
+} CK_C_INITIALIZE_ARGS;
+
+typedef struct {
+	CK_BYTE label[32];
+	CK_BYTE manufacturerID[32];
+	CK_BYTE model[16];
+	CK_BYTE serialNumber[16];
+	CK_FLAGS flags;
+	CK_ULONG ulMaxSessionCount;
+	CK_ULONG ulSessionCount;
+	CK_ULONG ulMaxRwSessionCount;
+	CK_ULONG ulRwSessionCount;
+	CK_ULONG ulMaxPinLen;
+	CK_ULONG ulMinPinLen;
+	CK_ULONG ulTotalPublicMemory;
+	CK_ULONG ulFreePublicMemory;
+	CK_ULONG ulTotalPrivateMemory;
+	CK_ULONG ulFreePrivateMemory;
+	CK_VERSION hardwareVersion;
+	CK_VERSION firmwareVersion;
+	CK_BYTE utcTime[16];
+} CK_TOKEN_INFO;
+
+typedef struct {
+	CK_VERSION version;
+	CK_RV (*C_Initialize)(void *);
+	CK_RV (*C_Finalize)(void *);
+	void *C_GetInfo;
+	void *C_GetFunctionList;
+	CK_RV (*C_GetSlotList)(CK_BYTE, CK_SLOT_ID *, CK_ULONG *);
+	void *C_GetSlotInfo;
+	CK_RV (*C_GetTokenInfo)(CK_SLOT_ID, CK_TOKEN_INFO *);
+	void *C_GetMechanismList;
+	void *C_GetMechanismInfo;
+	void *C_InitToken;
+	void *C_InitPIN;
+	void *C_SetPIN;
+	CK_RV (*C_OpenSession)(CK_SLOT_ID, CK_FLAGS, void *, void *, CK_SESSION_HANDLE *);
+	CK_RV (*C_CloseSession)(CK_SESSION_HANDLE);
+	void *C_CloseAllSessions;
+	void *C_GetSessionInfo;
+	void *C_GetOperationState;
+	void *C_SetOperationState;
+	CK_RV (*C_Login)(CK_SESSION_HANDLE, CK_ULONG, CK_BYTE *, CK_ULONG);
+	CK_RV (*C_Logout)(CK_SESSION_HANDLE);
+	void *C_CreateObject;
+	void *C_CopyObject;
+	CK_RV (*C_DestroyObject)(CK_SESSION_HANDLE, CK_OBJECT_HANDLE);
+	void *C_GetObjectSize;
+	CK_RV (*C_GetAttributeValue)(CK_SESSION_HANDLE, CK_OBJECT_HANDLE, CK_ATTRIBUTE *, CK_ULONG);
+	void *C_SetAttributeValue;
+	CK_RV (*C_FindObjectsInit)(CK_SESSION_HANDLE, CK_ATTRIBUTE *, CK_ULONG);
+	CK_RV (*C_FindObjects)(CK_SESSION_HANDLE, CK_OBJECT_HANDLE *, CK_ULONG, CK_ULONG *);
+	CK_RV (*C_FindObjectsFinal)(CK_SESSION_HANDLE);
+	void *C_EncryptInit;
+	void *C_Encrypt;
+	void *C_EncryptUpdate;
+	void *C_EncryptFinal;
+	void *C_DecryptInit;
+	void *C_Decrypt;
+	void *C_DecryptUpdate;
+	void *C_DecryptFinal;
+	void *C_DigestInit;
+	void *C_Digest;
+	void *C_DigestUpdate;
+	void *C_DigestKey;
+	void *C_DigestFinal;
+	CK_RV (*C_SignInit)(CK_SESSION_HANDLE, CK_MECHANISM *, CK_OBJECT_HANDLE);
+	CK_RV (*C_Sign)(CK_SESSION_HANDLE, CK_BYTE *, CK_ULONG, CK_BYTE *, CK_ULONG *);
+	void *C_SignUpdate;
+	void *C_SignFinal;
+	void *C_SignRecoverInit;
+	void *C_SignRecover;
+	void *C_VerifyInit;
+	void *C_Verify;
+	void *C_VerifyUpdate;
+	void *C_VerifyFinal;
+	void *C_VerifyRecoverInit;
+	void *C_VerifyRecover;
+	void *C_DigestEncryptUpdate;
+	void *C_DecryptDigestUpdate;
+	void *C_SignEncryptUpdate;
+	void *C_DecryptVerifyUpdate;
+	void *C_GenerateKey;
+	void *C_GenerateKeyPair;
+	void *C_WrapKey;
+	void *C_UnwrapKey;
+	CK_RV (*C_DeriveKey)(CK_SESSION_HANDLE, CK_MECHANISM *, CK_OBJECT_HANDLE, CK_ATTRIBUTE *, CK_ULONG, CK_OBJECT_HANDLE *);
+	void *C_SeedRandom;
+	void *C_GenerateRandom;
+	void *C_GetFunctionStatus;
+	void *C_CancelFunction;
+	void *C_WaitForSlotEvent;
+} CK_FUNCTION_LIST;
+
+#define CKR_OK                            0x000
+#define CKR_GENERAL_ERROR                 0x005
+#define CKR_TOKEN_NOT_PRESENT             0x0E0
+#define CKR_USER_ALREADY_LOGGED_IN        0x100
+#define CKR_CRYPTOKI_ALREADY_INITIALIZED  0x191
+
+#define CKF_OS_LOCKING_OK  0x2
+#define CKF_RW_SESSION     0x2
+#define CKF_SERIAL_SESSION 0x4
+
+#define CKU_USER 1
+
+#define CKA_CLASS       0x000
+#define CKA_TOKEN       0x001
+#define CKA_VALUE       0x011
+#define CKA_KEY_TYPE    0x100
+#define CKA_SENSITIVE   0x103
+#define CKA_VALUE_LEN   0x161
+#define CKA_EXTRACTABLE 0x162
+
+#define CKO_SECRET_KEY      4
+#define CKK_GENERIC_SECRET  0x10
+
+#define CKM_ECDSA        0x1041
+#define CKM_ECDH1_DERIVE 0x1050
+#define CKD_NULL         1
+
+typedef CK_RV (*get_function_list_fn)(CK_FUNCTION_LIST **);
+
+static CK_RV ck_load(const char *path, void **handle, CK_FUNCTION_LIST **fl) {
+	get_function_list_fn get;
+	CK_C_INITIALIZE_ARGS args;
+	CK_RV rv;
+
+	*handle = dlopen(path, RTLD_NOW);
+	if (*handle == NULL) {
+		return CKR_GENERAL_ERROR;
+	}
+	get = (get_function_list_fn)dlsym(*handle, "C_GetFunctionList");
+	if (get == NULL || get(fl) != CKR_OK) {
+		dlclose(*handle);
+		return CKR_GENERAL_ERROR;
+	}
+	memset(&args, 0, sizeof(args));
+	args.flags = CKF_OS_LOCKING_OK;
+	rv = (*fl)->C_Initialize(&args);
+	if (rv != CKR_OK && rv != CKR_CRYPTOKI_ALREADY_INITIALIZED) {
+		dlclose(*handle);
+		return rv;
+	}
+	return CKR_OK;
+}
+
+static void ck_unload(void *handle, CK_FUNCTION_LIST *fl) {
+	fl->C_Finalize(NULL);
+	dlclose(handle);
+}
+
+// ck_find_slot looks up the slot of the token with the label, the first
+// token if the label is empty. The label is padded with blanks to 32 bytes.
+static CK_RV ck_find_slot(CK_FUNCTION_LIST *fl, CK_BYTE *label, CK_SLOT_ID *slot, CK_BYTE *found) {
+	CK_SLOT_ID *slots;
+	CK_ULONG n = 0, i;
+	CK_TOKEN_INFO info;
+	CK_RV rv;
+
+	if ((rv = fl->C_GetSlotList(1, NULL, &n)) != CKR_OK) {
+		return rv;
+	}
+	if (n == 0) {
+		return CKR_TOKEN_NOT_PRESENT;
+	}
+	slots = calloc(n, sizeof(CK_SLOT_ID));
+	if ((rv = fl->C_GetSlotList(1, slots, &n)) != CKR_OK) {
+		free(slots);
+		return rv;
+	}
+	rv = CKR_TOKEN_NOT_PRESENT;
+	for (i = 0; i < n; i++) {
+		if (fl->C_GetTokenInfo(slots[i], &info) != CKR_OK) {
+			continue;
+		}
+		if (label[0] == ' ' || memcmp(info.label, label, 32) == 0) {
+			*slot = slots[i];
+			memcpy(found, info.label, 32);
+			rv = CKR_OK;
+			break;
+		}
+	}
+	free(slots);
+	return rv;
+}
+
+static CK_RV ck_open(CK_FUNCTION_LIST *fl, CK_SLOT_ID slot, CK_BYTE *pin, CK_ULONG pinLen, CK_SESSION_HANDLE *session) {
+	CK_RV rv;
+
+	if ((rv = fl->C_OpenSession(slot, CKF_SERIAL_SESSION | CKF_RW_SESSION, NULL, NULL, session)) != CKR_OK) {
+		return rv;
+	}
+	rv = fl->C_Login(*session, CKU_USER, pin, pinLen);
+	if (rv != CKR_OK && rv != CKR_USER_ALREADY_LOGGED_IN) {
+		fl->C_CloseSession(*session);
+		return rv;
+	}
+	return CKR_OK;
+}
+
+static void ck_close(CK_FUNCTION_LIST *fl, CK_SESSION_HANDLE session) {
+	fl->C_Logout(session);
+	fl->C_CloseSession(session);
+}
+
+static CK_RV ck_find(CK_FUNCTION_LIST *fl, CK_SESSION_HANDLE session, CK_ATTRIBUTE *tmpl, CK_ULONG n,
+	CK_OBJECT_HANDLE *objs, CK_ULONG max, CK_ULONG *count) {
+	CK_RV rv;
+
+	if ((rv = fl->C_FindObjectsInit(session, tmpl, n)) != CKR_OK) {
+		return rv;
+	}
+	rv = fl->C_FindObjects(session, objs, max, count);
+	fl->C_FindObjectsFinal(session);
+	return rv;
+}
+
+// ck_attribute reads an attribute into value, or only its length if value
+// is NULL.
+static CK_RV ck_attribute(CK_FUNCTION_LIST *fl, CK_SESSION_HANDLE session, CK_OBJECT_HANDLE obj,
+	CK_ULONG type, CK_BYTE *value, CK_ULONG *len) {
+	CK_ATTRIBUTE attr = { type, value, *len };
+	CK_RV rv;
+
+	rv = fl->C_GetAttributeValue(session, obj, &attr, 1);
+	*len = attr.ulValueLen;
+	return rv;
+}
+
+static CK_RV ck_sign(CK_FUNCTION_LIST *fl, CK_SESSION_HANDLE session, CK_OBJECT_HANDLE key,
+	CK_BYTE *hash, CK_ULONG hashLen, CK_BYTE *sig, CK_ULONG *sigLen) {
+	CK_MECHANISM mech = { CKM_ECDSA, NULL, 0 };
+	CK_RV rv;
+
+	if ((rv = fl->C_SignInit(session, &mech, key)) != CKR_OK) {
+		return rv;
+	}
+	return fl->C_Sign(session, hash, hashLen, sig, sigLen);
+}
+
+// ck_ecdh derives the raw ECDH shared secret of key and the peer point as a
+// session object, reads it out and destroys it.
+static CK_RV ck_ecdh(CK_FUNCTION_LIST *fl, CK_SESSION_HANDLE session, CK_OBJECT_HANDLE key,
+	CK_BYTE *point, CK_ULONG pointLen, CK_BYTE *secret, CK_ULONG *secretLen) {
+	CK_ECDH1_DERIVE_PARAMS params = { CKD_NULL, 0, NULL, pointLen, point };
+	CK_MECHANISM mech = { CKM_ECDH1_DERIVE, &params, sizeof(params) };
+	CK_ULONG class = CKO_SECRET_KEY, keyType = CKK_GENERIC_SECRET, valueLen = *secretLen;
+	CK_BYTE no = 0, yes = 1;
+	CK_ATTRIBUTE tmpl[] = {
+		{ CKA_CLASS, &class, sizeof(class) },
+		{ CKA_KEY_TYPE, &keyType, sizeof(keyType) },
+		{ CKA_VALUE_LEN, &valueLen, sizeof(valueLen) },
+		{ CKA_TOKEN, &no, sizeof(no) },
+		{ CKA_SENSITIVE, &no, sizeof(no) },
+		{ CKA_EXTRACTABLE, &yes, sizeof(yes) },
+	};
+	CK_OBJECT_HANDLE derived;
+	CK_RV rv;
+
+	if ((rv = fl->C_DeriveKey(session, &mech, key, tmpl, sizeof(tmpl) / sizeof(tmpl[0]), &derived)) != CKR_OK) {
+		return rv;
+	}
+	rv = ck_attribute(fl, session, derived, CKA_VALUE, secret, secretLen);
+	fl->C_DestroyObject(session, derived);
+	return rv;
+}
+*/
+import "C"
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"sync"
+	"unsafe"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// PKCS#11 constants used from Go.
+const (
+	ckaClass   = 0x000
+	ckaLabel   = 0x003
+	ckaKeyType = 0x100
+	ckaID      = 0x102
+	ckaECParam = 0x180
+	ckaECPoint = 0x181
+
+	ckoPublicKey  = 2
+	ckoPrivateKey = 3
+	ckkEC         = 3
+
+	ckUnavailableInformation = ^C.CK_ULONG(0)
+
+	// maxKeys bounds the number of keys listed on a token
+	maxKeys = 64
+)
+
+// ckError is a PKCS#11 return value other than CKR_OK.
+type ckError C.CK_RV
+
+var ckErrorNames = map[ckError]string{
+	0x005: "CKR_GENERAL_ERROR",
+	0x006: "CKR_FUNCTION_FAILED",
+	0x030: "CKR_DEVICE_ERROR",
+	0x032: "CKR_DEVICE_REMOVED",
+	0x068: "CKR_KEY_FUNCTION_NOT_PERMITTED",
+	0x070: "CKR_MECHANISM_INVALID",
+	0x0A0: "CKR_PIN_INCORRECT",
+	0x0A4: "CKR_PIN_LOCKED",
+	0x0B3: "CKR_SESSION_HANDLE_INVALID",
+	0x0E0: "CKR_TOKEN_NOT_PRESENT",
+	0x101: "CKR_USER_NOT_LOGGED_IN",
+}
+
+func (e ckError) Error() string {
+	if name, ok := ckErrorNames[e]; ok {
+		return "pkcs11: " + name
+	}
+	return fmt.Sprintf("pkcs11: error 0x%x", uint64(e))
+}
+
+// module is a token session of a PKCS#11 module loaded into the process.
+// PKCS#11 sessions are not safe for concurrent use, all calls are serialized.
+type module struct {
+	mu      sync.Mutex
+	handle  unsafe.Pointer
+	fl      *C.CK_FUNCTION_LIST
+	session C.CK_SESSION_HANDLE
+	label   string
+}
+
+// openToken loads the PKCS#11 library at path and logs into the token with
+// the label, the first token of the module if label is empty.
+func openToken(path, label, pin string) (token, error) {
+	cpath := C.CString(path)
+	defer C.free(unsafe.Pointer(cpath))
+
+	m := new(module)
+	if rv := C.ck_load(cpath, &m.handle, &m.fl); rv != 0 {
+		return nil, fmt.Errorf("failed to load PKCS#11 module %s: %v", path, ckError(rv))
+	}
+
+	padded := make([]byte, 32)
+	copy(padded, bytes.Repeat([]byte{' '}, 32))
+	copy(padded, label)
+	found := make([]byte, 32)
+	var slot C.CK_SLOT_ID
+	if rv := C.ck_find_slot(m.fl, (*C.CK_BYTE)(&padded[0]), &slot, (*C.CK_BYTE)(&found[0])); rv != 0 {
+		C.ck_unload(m.handle, m.fl)
+		return nil, fmt.Errorf("token %q not found: %v", label, ckError(rv))
+	}
+	m.label = string(bytes.TrimRight(found, " "))
+
+	cpin := []byte(pin)
+	var pinPtr *C.CK_BYTE
+	if len(cpin) > 0 {
+		pinPtr = (*C.CK_BYTE)(&cpin[0])
+	}
+	if rv := C.ck_open(m.fl, slot, pinPtr, C.CK_ULONG(len(cpin)), &m.session); rv != 0 {
+		C.ck_unload(m.handle, m.fl)
+		return nil, fmt.Errorf("failed to log into token %q: %v", m.label, ckError(rv))
+	}
+	return m, nil
+}
+
+func (m *module) name() string {
+	return m.label
+}
+
+// template is a PKCS#11 attribute template in C memory, as it holds pointers.
+type template struct {
+	attrs *C.CK_ATTRIBUTE
+	n     int
+	mem   []unsafe.Pointer
+}
+
+func newTemplate(types []C.CK_ULONG, values [][]byte) *template {
+	t := &template{n: len(types)}
+	t.attrs = (*C.CK_ATTRIBUTE)(C.calloc(C.size_t(len(types)), C.size_t(unsafe.Sizeof(C.CK_ATTRIBUTE{}))))
+	attrs := (*[1 << 16]C.CK_ATTRIBUTE)(unsafe.Pointer(t.attrs))[:len(types):len(types)]
+	for i := range types {
+		value := C.CBytes(values[i])
+		t.mem = append(t.mem, value)
+		attrs[i] = C.CK_ATTRIBUTE{_type: types[i], pValue: value, ulValueLen: C.CK_ULONG(len(values[i]))}
+	}
+	return t
+}
+
+func (t *template) free() {
+	for _, p := range t.mem {
+		C.free(p)
+	}
+	C.free(unsafe.Pointer(t.attrs))
+}
+
+// ulong encodes a CK_ULONG attribute value.
+func ulong(v C.CK_ULONG) []byte {
+	b := make([]byte, unsafe.Sizeof(v))
+	*(*C.CK_ULONG)(unsafe.Pointer(&b[0])) = v
+	return b
+}
+
+func (m *module) find(types []C.CK_ULONG, values [][]byte) ([]C.CK_OBJECT_HANDLE, error) {
+	tmpl := newTemplate(types, values)
+	defer tmpl.free()
+
+	objs := make([]C.CK_OBJECT_HANDLE, maxKeys)
+	var count C.CK_ULONG
+	if rv := C.ck_find(m.fl, m.session, tmpl.attrs, C.CK_ULONG(tmpl.n), &objs[0], maxKeys, &count); rv != 0 {
+		return nil, ckError(rv)
+	}
+	return objs[:count], nil
+}
+
+func (m *module) attribute(obj C.CK_OBJECT_HANDLE, typ C.CK_ULONG) ([]byte, error) {
+	var n C.CK_ULONG
+	if rv := C.ck_attribute(m.fl, m.session, obj, typ, nil, &n); rv != 0 {
+		return nil, ckError(rv)
+	}
+	if n == ckUnavailableInformation {
+		return nil, errors.New("pkcs11: attribute unavailable")
+	}
+	if n == 0 {
+		return nil, nil
+	}
+	value := make([]byte, n)
+	if rv := C.ck_attribute(m.fl, m.session, obj, typ, (*C.CK_BYTE)(&value[0]), &n); rv != 0 {
+		return nil, ckError(rv)
+	}
+	return value[:n], nil
+}
+
+// keys lists the secp256k1 key pairs of the token. A private key is matched
+// with the public key of the same CKA_ID, whose point gives the address.
+func (m *module) keys() ([]tokenKey, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	privs, err := m.find([]C.CK_ULONG{ckaClass, ckaKeyType}, [][]byte{ulong(ckoPrivateKey), ulong(ckkEC)})
+	if err != nil {
+		return nil, err
+	}
+	var keys []tokenKey
+	for _, priv := range privs {
+		id, err := m.attribute(priv, ckaID)
+		if err != nil {
+			return nil, err
+		}
+		label, _ := m.attribute(priv, ckaLabel)
+		pubs, err := m.find([]C.CK_ULONG{ckaClass, ckaKeyType, ckaID}, [][]byte{ulong(ckoPublicKey), ulong(ckkEC), id})
+		if err != nil {
+			return nil, err
+		}
+		if len(pubs) == 0 {
+			continue
+		}
+		params, err := m.attribute(pubs[0], ckaECParam)
+		if err != nil || !bytes.Equal(params, secp256k1OID) {
+			continue
+		}
+		point, err := m.attribute(pubs[0], ckaECPoint)
+		if err != nil {
+			return nil, err
+		}
+		pub, err := unmarshalECPoint(point)
+		if err != nil {
+			continue
+		}
+		keys = append(keys, tokenKey{handle: uint64(priv), label: string(label), pub: pub})
+	}
+	return keys, nil
+}
+
+func (m *module) sign(handle uint64, hash []byte) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	sig := make([]byte, 2*32)
+	n := C.CK_ULONG(len(sig))
+	if rv := C.ck_sign(m.fl, m.session, C.CK_OBJECT_HANDLE(handle), (*C.CK_BYTE)(&hash[0]), C.CK_ULONG(len(hash)), (*C.CK_BYTE)(&sig[0]), &n); rv != 0 {
+		return nil, ckError(rv)
+	}
+	return sig[:n], nil
+}
+
+func (m *module) deriveShared(handle uint64, pub *ecdsa.PublicKey) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	point := crypto.FromECDSAPub(pub)
+	secret := make([]byte, 32)
+	n := C.CK_ULONG(len(secret))
+	if rv := C.ck_ecdh(m.fl, m.session, C.CK_OBJECT_HANDLE(handle), (*C.CK_BYTE)(&point[0]), C.CK_ULONG(len(point)), (*C.CK_BYTE)(&secret[0]), &n); rv != 0 {
+		return nil, ckError(rv)
+	}
+	return secret[:n], nil
+}
+
+func (m *module) close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	C.ck_close(m.fl, m.session)
+	C.ck_unload(m.handle, m.fl)
+	return nil
+}
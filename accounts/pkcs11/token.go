@@ -0,0 +1,100 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package pkcs11
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// secp256k1OID is the DER encoded CKA_EC_PARAMS of secp256k1 keys, the
+// named curve 1.3.132.0.10.
+var secp256k1OID = []byte{0x06, 0x05, 0x2b, 0x81, 0x04, 0x00, 0x0a}
+
+var (
+	secp256k1N     = crypto.S256().Params().N
+	secp256k1HalfN = new(big.Int).Rsh(secp256k1N, 1)
+)
+
+var errSignatureMismatch = errors.New("pkcs11: signature does not match the public key")
+
+// tokenKey is a secp256k1 key pair held by a token.
+type tokenKey struct {
+	handle uint64 // object handle of the private key
+	label  string // CKA_LABEL of the private key
+	pub    *ecdsa.PublicKey
+}
+
+// token is a logged in session of a PKCS#11 token. The private keys never
+// leave it, the wallet only asks it for raw ECDSA signatures and ECDH secrets.
+type token interface {
+	// name returns the label of the token.
+	name() string
+
+	// keys lists the secp256k1 key pairs on the token.
+	keys() ([]tokenKey, error)
+
+	// sign returns the CKM_ECDSA signature of hash, r || s.
+	sign(handle uint64, hash []byte) ([]byte, error)
+
+	// deriveShared returns the CKM_ECDH1_DERIVE secret of the private key and
+	// pub, the x coordinate of the shared point.
+	deriveShared(handle uint64, pub *ecdsa.PublicKey) ([]byte, error)
+
+	// close logs out of the token and unloads the module.
+	close() error
+}
+
+// unmarshalECPoint decodes CKA_EC_POINT, an uncompressed point wrapped in a
+// DER octet string. Some modules omit the wrapping.
+func unmarshalECPoint(point []byte) (*ecdsa.PublicKey, error) {
+	if len(point) == 67 && point[0] == 0x04 && point[1] == 65 {
+		point = point[2:]
+	}
+	return crypto.UnmarshalPubkey(point)
+}
+
+// recoverableSignature turns the r || s signature of a token into the
+// [R || S || V] format of crypto.Sign: s is normalized to the lower half of
+// the curve order as required by transaction validation, and the recovery
+// id is found by recovering the public key.
+func recoverableSignature(rs []byte, hash []byte, pub *ecdsa.PublicKey) ([]byte, error) {
+	if len(rs) != 64 {
+		return nil, errSignatureMismatch
+	}
+	sig := make([]byte, 65)
+	copy(sig, rs[:32])
+	s := new(big.Int).SetBytes(rs[32:])
+	if s.Cmp(secp256k1HalfN) > 0 {
+		s.Sub(secp256k1N, s)
+	}
+	sb := s.Bytes()
+	copy(sig[64-len(sb):64], sb)
+
+	want := crypto.FromECDSAPub(pub)
+	for v := byte(0); v < 2; v++ {
+		sig[64] = v
+		if got, err := crypto.Ecrecover(hash, sig); err == nil && bytes.Equal(got, want) {
+			return sig, nil
+		}
+	}
+	return nil, errSignatureMismatch
+}
@@ -16,7 +16,6 @@ import (
 	"bitbucket.org/cpchain/chain/commons/log"
 	"bitbucket.org/cpchain/chain/configs"
 	"bitbucket.org/cpchain/chain/consensus"
-	"bitbucket.org/cpchain/chain/contracts/dpor/admission"
 	"bitbucket.org/cpchain/chain/contracts/dpor/campaign3"
 	"bitbucket.org/cpchain/chain/contracts/dpor/rnode"
 	"github.com/ethereum/go-ethereum/common"
@@ -43,8 +42,9 @@ const (
 	maxNumOfCampaignTerms = 10
 	minNumOfCampaignTerms = 1
 
-	Cpu    = "cpu"
-	Memory = "memory"
+	Cpu     = "cpu"
+	Memory  = "memory"
+	Storage = "storage"
 )
 
 var (
@@ -59,27 +59,33 @@ type AdmissionControl struct {
 	address               common.Address
 	chain                 consensus.ChainReader
 	key                   *keystore.Key
+	transactor            *bind.TransactOpts
 	contractBackend       contracts.Backend
 	admissionContractAddr common.Address
 	campaignContractAddr  common.Address
 	rNodeContractAddr     common.Address
 
-	mutex      sync.RWMutex
-	wg         *sync.WaitGroup
-	cpuWork    ProofWork
-	memoryWork ProofWork
-	status     workStatus
-	err        error
-	abort      chan interface{}
-	done       chan interface{}
+	plotDir               string
+
+	mutex    sync.RWMutex
+	wg       *sync.WaitGroup
+	works    map[string]ProofWork
+	required map[string]bool
+	status   workStatus
+	err      error
+	abort    chan interface{}
+	done     chan interface{}
 
 	sendingFund int32
+	plotting    int32
+
+	manager *campaignManager
 }
 
 // NewAdmissionControl returns a new Control instance.
 func NewAdmissionControl(chain consensus.ChainReader, address common.Address, admissionContractAddr common.Address,
 	campaignContractAddr common.Address, rNodeContractAddr common.Address) *AdmissionControl {
-	return &AdmissionControl{
+	ac := &AdmissionControl{
 		chain:                 chain,
 		address:               address,
 		admissionContractAddr: admissionContractAddr,
@@ -87,6 +93,24 @@ func NewAdmissionControl(chain consensus.ChainReader, address common.Address, ad
 		rNodeContractAddr:     rNodeContractAddr,
 		status:                AcIdle,
 	}
+	ac.manager = newCampaignManager(ac)
+	return ac
+}
+
+// StartCampaignManager starts to renew the campaign for terms each time
+// before the candidacy ends, 0 for the default number of terms
+func (ac *AdmissionControl) StartCampaignManager(terms uint64) error {
+	return ac.manager.start(terms)
+}
+
+// StopCampaignManager stops renewing the campaign
+func (ac *AdmissionControl) StopCampaignManager() {
+	ac.manager.stop()
+}
+
+// CampaignState returns the candidacy kept by the campaign manager
+func (ac *AdmissionControl) CampaignState() CampaignState {
+	return ac.manager.status()
 }
 
 // Campaign starts running all the proof work to generate the campaign information and waits all proof work done, send msg
@@ -108,14 +132,17 @@ func (ac *AdmissionControl) Campaign(terms uint64) error {
 		return errNotRNode
 	}
 
+	if err := ac.buildWorks(); err != nil {
+		return err
+	}
+
 	ac.status = AcRunning
 	ac.err = nil
 	ac.done = make(chan interface{})
 	ac.abort = make(chan interface{})
-	ac.buildWorks()
 	ac.wg = new(sync.WaitGroup)
-	ac.wg.Add(len(ac.getWorks()))
-	for _, work := range ac.getWorks() {
+	ac.wg.Add(len(ac.works))
+	for _, work := range ac.works {
 		go work.prove(ac.abort, ac.wg)
 	}
 
@@ -167,7 +194,7 @@ func (ac *AdmissionControl) FundForRNode() error {
 	minRnodeFund := new(big.Int).Mul(big.NewInt(configs.RNodeMinFundReq), big.NewInt(configs.Cpc))
 	balance, _ := ac.contractBackend.BalanceAt(context.Background(), ac.address, nil)
 	if balance.Cmp(minRnodeFund) >= 0 {
-		transactOpts := bind.NewKeyedTransactor(ac.key.PrivateKey)
+		transactOpts := ac.transactOpts()
 		transactOpts.Value = minRnodeFund
 		tx, err := rNodeContract.JoinRnode(transactOpts)
 		if err != nil {
@@ -240,7 +267,7 @@ func (ac *AdmissionControl) GetResult() map[string]Result {
 	defer ac.mutex.RUnlock()
 
 	results := make(map[string]Result)
-	for name, work := range ac.getWorks() {
+	for name, work := range ac.works {
 		results[name] = work.result()
 	}
 	return results
@@ -252,6 +279,32 @@ func (ac *AdmissionControl) SetAdmissionKey(key *keystore.Key) {
 	defer ac.mutex.Unlock()
 
 	ac.key = key
+	ac.transactor = bind.NewKeyedTransactor(key.PrivateKey)
+}
+
+// SetAdmissionTransactor sets the transaction signer for admission control to
+// participate campaign, for coinbases whose key cannot be read, e.g. held by
+// a hardware token
+func (ac *AdmissionControl) SetAdmissionTransactor(opts *bind.TransactOpts) {
+	ac.mutex.Lock()
+	defer ac.mutex.Unlock()
+
+	ac.transactor = opts
+}
+
+// AdmissionTransactor returns the transaction signer to participate campaign
+func (ac *AdmissionControl) AdmissionTransactor() *bind.TransactOpts {
+	ac.mutex.RLock()
+	defer ac.mutex.RUnlock()
+
+	return ac.transactor
+}
+
+// transactOpts returns a copy of the transaction signer, to be amended per
+// transaction
+func (ac *AdmissionControl) transactOpts() *bind.TransactOpts {
+	opts := *ac.transactor
+	return &opts
 }
 
 // GetStatus gets status of campaign
@@ -274,11 +327,15 @@ func (ac *AdmissionControl) waitSendCampaignMsg(terms uint64) {
 	}(ac)
 
 	ac.mutex.RLock()
-	works := ac.getWorks()
+	works, required := ac.works, ac.required
 	ac.mutex.RUnlock()
 
-	for _, work := range works {
-		// if work err then return
+	for name, work := range works {
+		if work.error() != nil && !required[name] {
+			log.Info("optional proof work failed", "work", name, "error", work.error())
+			continue
+		}
+		// if required work err then return
 		if work.error() != nil {
 			ac.mutex.Lock()
 			ac.err = work.error()
@@ -298,7 +355,7 @@ func (ac *AdmissionControl) sendCampaignResult(terms uint64) {
 		ac.mutex.Unlock()
 		return
 	}
-	transactOpts := bind.NewKeyedTransactor(ac.key.PrivateKey)
+	transactOpts := ac.transactOpts()
 	campaignContractAddress := ac.campaignContractAddr
 	log.Debug("CampaignContractAddress", "address", campaignContractAddress.Hex())
 	instance, err := campaign.NewCampaign(campaignContractAddress, ac.contractBackend)
@@ -309,8 +366,10 @@ func (ac *AdmissionControl) sendCampaignResult(terms uint64) {
 		return
 	}
 
-	cpuResult := ac.cpuWork.result()
-	memResult := ac.memoryWork.result()
+	ac.mutex.RLock()
+	cpuResult := ac.works[Cpu].result()
+	memResult := ac.works[Memory].result()
+	ac.mutex.RUnlock()
 	_, err = instance.ClaimCampaign(transactOpts, new(big.Int).SetUint64(terms), cpuResult.Nonce, new(big.Int).SetInt64(cpuResult.BlockNumber),
 		memResult.Nonce, new(big.Int).SetInt64(memResult.BlockNumber))
 	if err != nil {
@@ -338,58 +397,47 @@ func (ac *AdmissionControl) SetSimulateBackend(contractBackend contracts.Backend
 	ac.contractBackend = contractBackend
 }
 
-// buildWorks creates proof works required by admission
-func (ac *AdmissionControl) buildWorks() {
-	ac.cpuWork = ac.buildCpuProofWork()
-	ac.memoryWork = ac.buildMemoryProofWork()
-}
-
-func (ac *AdmissionControl) buildCpuProofWork() ProofWork {
-	client := ac.contractBackend
-	instance, err := admission.NewAdmission(ac.admissionContractAddr, client)
-	if err != nil {
-		log.Fatal("NewAdmissionCaller is error", "error is", err)
-	}
-	cd, _, clt, _, err := instance.GetAdmissionParameters(nil)
-	if err != nil {
-		log.Fatal("GetAdmissionParameters is error", "error is ", err)
-	}
-	cpuDifficulty := cd.Uint64()
-	cpuLifeTime := time.Duration(time.Duration(clt.Int64()) * time.Second)
+// SetPlotDir sets the directory storage proof plots are kept in
+func (ac *AdmissionControl) SetPlotDir(dir string) {
+	ac.mutex.Lock()
+	defer ac.mutex.Unlock()
 
-	// must use current block number - 1, because solidity cannot get hash of current block
-	blockNum := ac.chain.CurrentHeader().Number.Uint64()
-	if blockNum > 0 {
-		blockNum = blockNum - 1
-	}
-	return newWork(cpuDifficulty, cpuLifeTime, ac.address, ac.chain.GetHeaderByNumber(blockNum), sha256Func)
+	ac.plotDir = dir
 }
 
-func (ac *AdmissionControl) buildMemoryProofWork() ProofWork {
-	client := ac.contractBackend
-	instance, err := admission.NewAdmission(ac.admissionContractAddr, client)
-	if err != nil {
-		log.Fatal("NewAdmissionCaller is error", "error is", err)
-	}
-	_, md, _, mct, err := instance.GetAdmissionParameters(nil)
-	if err != nil {
-		log.Fatal("GetDifficultyParameter is error", "error is", err)
-	}
-	memoryDifficulty := md.Uint64()
-	memoryCpuLifeTime := time.Duration(time.Duration(mct.Int64()) * time.Second)
-
+// buildWorks creates the proof works registered, a proof work not required
+// is skipped if it cannot be built.
+func (ac *AdmissionControl) buildWorks() error {
 	// must use current block number - 1, because solidity cannot get hash of current block
 	blockNum := ac.chain.CurrentHeader().Number.Uint64()
 	if blockNum > 0 {
 		blockNum = blockNum - 1
 	}
-	return newWork(memoryDifficulty, memoryCpuLifeTime, ac.address, ac.chain.GetHeaderByNumber(blockNum), scryptFunc)
-}
+	header := ac.chain.GetHeaderByNumber(blockNum)
+
+	proofPluginsLock.RLock()
+	defer proofPluginsLock.RUnlock()
+
+	works := make(map[string]ProofWork)
+	required := make(map[string]bool)
+	for name, plugin := range proofPlugins {
+		params, err := plugin.Params(ac)
+		var work ProofWork
+		if err == nil {
+			work, err = plugin.Build(ac, params, header)
+		}
+		if err != nil {
+			if plugin.Required {
+				log.Warn("failed to build proof work", "work", name, "error", err)
+				return err
+			}
+			log.Debug("skip proof work", "work", name, "error", err)
+			continue
+		}
+		works[name] = work
+		required[name] = plugin.Required
+	}
 
-// registerProofWork returns all proof work
-func (ac *AdmissionControl) getWorks() map[string]ProofWork {
-	proofWorks := make(map[string]ProofWork)
-	proofWorks[Cpu] = ac.cpuWork
-	proofWorks[Memory] = ac.memoryWork
-	return proofWorks
+	ac.works, ac.required = works, required
+	return nil
 }
@@ -17,6 +17,7 @@
 package admission
 
 import (
+	"bitbucket.org/cpchain/chain/accounts/abi/bind"
 	"bitbucket.org/cpchain/chain/accounts/keystore"
 	"bitbucket.org/cpchain/chain/api/cpclient"
 	"bitbucket.org/cpchain/chain/api/rpc"
@@ -80,6 +81,14 @@ func (b *AdmissionApiBackend) AdmissionKey() *keystore.Key {
 	return b.admissionControl.key
 }
 
+func (b *AdmissionApiBackend) SetAdmissionTransactor(opts *bind.TransactOpts) {
+	b.admissionControl.SetAdmissionTransactor(opts)
+}
+
+func (b *AdmissionApiBackend) AdmissionTransactor() *bind.TransactOpts {
+	return b.admissionControl.AdmissionTransactor()
+}
+
 // RegisterInProcHandler registers the rpc.Server, handles RPC request to process the API requests in process
 func (b *AdmissionApiBackend) RegisterInProcHandler(localRPCServer *rpc.Server) {
 	client := rpc.DialInProc(localRPCServer)
@@ -89,3 +98,19 @@ func (b *AdmissionApiBackend) RegisterInProcHandler(localRPCServer *rpc.Server)
 func (b *AdmissionApiBackend) SetContractBackend(contractBackend contracts.Backend) {
 	b.admissionControl.SetSimulateBackend(contractBackend)
 }
+
+func (b *AdmissionApiBackend) SetPlotDir(dir string) {
+	b.admissionControl.SetPlotDir(dir)
+}
+
+func (b *AdmissionApiBackend) StartCampaignManager(terms uint64) error {
+	return b.admissionControl.StartCampaignManager(terms)
+}
+
+func (b *AdmissionApiBackend) StopCampaignManager() {
+	b.admissionControl.StopCampaignManager()
+}
+
+func (b *AdmissionApiBackend) CampaignState() CampaignState {
+	return b.admissionControl.CampaignState()
+}
@@ -6,6 +6,7 @@ import (
 	"math/big"
 	"reflect"
 	"testing"
+	"time"
 
 	"bitbucket.org/cpchain/chain/accounts/abi/bind"
 	"bitbucket.org/cpchain/chain/accounts/abi/bind/backends"
@@ -240,3 +241,41 @@ func TestAdmissionApiBackend_FundForRNode(t *testing.T) {
 		t.Error("the balance should not change because it is already RNode and not need to send money to reward contract")
 	}
 }
+
+func TestAdmissionApiBackend_CampaignManager(t *testing.T) {
+	contractBackend, admissionAddr, rNodeAddr, _, campaignAddr := deployRequiredContracts(t)
+	ac := newAcApiBackend(contractBackend.Blockchain(), admissionAddr, campaignAddr, rNodeAddr)
+	ac.SetContractBackend(contractBackend)
+	ac.SetAdmissionKey(key)
+	ac.FundForRNode()
+	contractBackend.Commit()
+
+	if err := ac.StartCampaignManager(11); err == nil {
+		t.Fatal("campaign manager started for 11 terms")
+	}
+	if err := ac.StartCampaignManager(2); err != nil {
+		t.Fatal(err)
+	}
+
+	// the candidacy is checked at once when started
+	var state admission.CampaignState
+	for i := 0; i < 50 && !state.IsRNode; i++ {
+		time.Sleep(100 * time.Millisecond)
+		state = ac.CampaignState()
+	}
+	if !state.Running || state.Terms != 2 || !state.IsRNode {
+		t.Fatalf("campaign manager state %+v", state)
+	}
+	if want := new(big.Int).Mul(big.NewInt(200000), big.NewInt(configs.Cpc)); state.LockedDeposit.Cmp(want) != 0 {
+		t.Fatalf("locked deposit %v, want %v", state.LockedDeposit, want)
+	}
+	if state.StopTerm != 0 || state.RemainingTerms != 0 {
+		t.Fatalf("candidacy before campaign %+v", state)
+	}
+
+	ac.StopCampaignManager()
+	ac.Abort()
+	if state = ac.CampaignState(); state.Running {
+		t.Fatal("campaign manager is running after stopped")
+	}
+}
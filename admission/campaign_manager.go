@@ -0,0 +1,281 @@
+package admission
+
+import (
+	"errors"
+	"math/big"
+	"sync"
+	"time"
+
+	"bitbucket.org/cpchain/chain/commons/log"
+	"bitbucket.org/cpchain/chain/contracts/dpor/campaign3"
+	"bitbucket.org/cpchain/chain/contracts/dpor/rnode"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+const (
+	defaultManagedTerms = 3 // number of terms to claim each time if not given
+
+	campaignCheckInterval = 10 * time.Second // interval to check the candidacy
+	campaignRetryInterval = 30 * time.Second // interval to retry a failed renewal, doubled per failure
+	maxCampaignRetry      = 10 * time.Minute // max interval to retry a failed renewal
+)
+
+var (
+	errNotRenewed = errors.New("campaign is not renewed in time")
+
+	campaignRenewMeter     = metrics.NewRegisteredMeter("admission/campaign/renew", nil)
+	campaignFailureMeter   = metrics.NewRegisteredMeter("admission/campaign/failure", nil)
+	campaignRemainingGauge = metrics.NewRegisteredGauge("admission/campaign/remaining", nil)
+	campaignRNodeGauge     = metrics.NewRegisteredGauge("admission/campaign/rnode", nil)
+)
+
+// CampaignState is the candidacy of the coinbase kept by the campaign manager.
+type CampaignState struct {
+	Running bool   `json:"running"`
+	Terms   uint64 `json:"terms"` // number of terms claimed each time
+
+	IsRNode           bool     `json:"isRNode"`
+	LockedDeposit     *big.Int `json:"lockedDeposit"`
+	DepositUnlockTime uint64   `json:"depositUnlockTime"` // unix time the deposit can be withdrawn
+
+	Term           uint64 `json:"term"`      // current term of campaign contract
+	StartTerm      uint64 `json:"startTerm"` // first term as candidate
+	StopTerm       uint64 `json:"stopTerm"`  // first term not as candidate
+	RemainingTerms uint64 `json:"remainingTerms"`
+
+	LastClaimTerm uint64 `json:"lastClaimTerm"` // term the last renewal is started in
+	Failures      uint64 `json:"failures"`      // number of failed renewals in a row
+	LastError     string `json:"lastError,omitempty"`
+}
+
+// campaignManager renews the campaign of the coinbase in the last term it is
+// a candidate, so that it stays a candidate without breaks. It becomes RNode
+// first if it is not.
+type campaignManager struct {
+	ac *AdmissionControl
+
+	state    CampaignState
+	claiming bool      // a renewal is started and not checked yet
+	renewing bool      // a renewal is started and not seen on chain yet
+	retryAt  time.Time // time to renew again
+	lock     sync.RWMutex
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+func newCampaignManager(ac *AdmissionControl) *campaignManager {
+	return &campaignManager{ac: ac}
+}
+
+// start starts to renew the campaign for terms each time.
+func (m *campaignManager) start(terms uint64) error {
+	if terms == 0 {
+		terms = defaultManagedTerms
+	}
+	if terms > maxNumOfCampaignTerms || terms < minNumOfCampaignTerms {
+		return errTermOutOfRange
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.state.Terms = terms
+	if m.state.Running {
+		return nil
+	}
+	m.state.Running = true
+	m.quit = make(chan struct{})
+
+	m.wg.Add(1)
+	go m.loop(m.quit)
+
+	log.Info("started campaign manager", "terms", terms)
+	return nil
+}
+
+// stop stops renewing the campaign, a running campaign is not aborted.
+func (m *campaignManager) stop() {
+	m.lock.Lock()
+	if !m.state.Running {
+		m.lock.Unlock()
+		return
+	}
+	m.state.Running = false
+	close(m.quit)
+	m.lock.Unlock()
+
+	m.wg.Wait()
+	log.Info("stopped campaign manager")
+}
+
+// status returns the state of the candidacy.
+func (m *campaignManager) status() CampaignState {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	state := m.state
+	if state.LockedDeposit != nil {
+		state.LockedDeposit = new(big.Int).Set(state.LockedDeposit)
+	}
+	return state
+}
+
+func (m *campaignManager) loop(quit chan struct{}) {
+	defer m.wg.Done()
+
+	ticker := time.NewTicker(campaignCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		m.check()
+
+		select {
+		case <-quit:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// check checks the candidacy and renews it if it ends with the current term.
+func (m *campaignManager) check() {
+	// wait for the running campaign
+	if status, _ := m.ac.GetStatus(); status == AcRunning {
+		return
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if m.claiming {
+		m.claiming = false
+		if _, err := m.ac.GetStatus(); err != nil {
+			m.fail(err)
+			return
+		}
+	}
+
+	if err := m.checkRNode(); err != nil {
+		m.fail(err)
+		return
+	}
+	if !m.state.IsRNode {
+		log.Info("campaign manager: not RNode, funding for RNode")
+		if err := m.ac.FundForRNode(); err != nil {
+			m.fail(err)
+		}
+		return
+	}
+
+	if err := m.checkCandidate(); err != nil {
+		m.fail(err)
+		return
+	}
+	if m.state.RemainingTerms > 1 {
+		m.renewing = false
+		m.state.Failures, m.state.LastError = 0, ""
+		return
+	}
+
+	if time.Now().Before(m.retryAt) {
+		return
+	}
+	if m.renewing && m.state.LastClaimTerm == m.state.Term {
+		// the renewal of the term is not seen on chain in time, retry
+		m.fail(errNotRenewed)
+	}
+
+	log.Info("campaign manager: renewing campaign", "term", m.state.Term, "stopTerm", m.state.StopTerm, "terms", m.state.Terms)
+	if err := m.ac.Campaign(m.state.Terms); err != nil {
+		m.fail(err)
+		return
+	}
+	campaignRenewMeter.Mark(1)
+	m.claiming, m.renewing = true, true
+	m.state.LastClaimTerm = m.state.Term
+	if m.retryAt.Before(time.Now()) {
+		m.retryAt = time.Now().Add(campaignRetryInterval)
+	}
+}
+
+// fail records a failed renewal and backs off the next one.
+func (m *campaignManager) fail(err error) {
+	campaignFailureMeter.Mark(1)
+	m.state.Failures++
+	m.state.LastError = err.Error()
+
+	backoff := campaignRetryInterval << (m.state.Failures - 1)
+	if backoff > maxCampaignRetry || backoff <= 0 {
+		backoff = maxCampaignRetry
+	}
+	m.retryAt = time.Now().Add(backoff)
+
+	log.Warn("campaign manager: failed to keep candidacy", "failures", m.state.Failures, "retry", common.PrettyDuration(backoff), "error", err)
+}
+
+// checkRNode reads the RNode status and the deposit from rnode contract.
+func (m *campaignManager) checkRNode() error {
+	instance, err := rnode.NewRnode(m.ac.rNodeContractAddr, m.ac.contractBackend)
+	if err != nil {
+		return err
+	}
+
+	isRNode, err := instance.IsRnode(nil, m.ac.address)
+	if err != nil {
+		return err
+	}
+	participant, err := instance.Participants(nil, m.ac.address)
+	if err != nil {
+		return err
+	}
+	period, err := instance.Period(nil)
+	if err != nil {
+		return err
+	}
+
+	m.state.IsRNode = isRNode
+	m.state.LockedDeposit = participant.LockedDeposit
+	m.state.DepositUnlockTime = 0
+	if isRNode {
+		m.state.DepositUnlockTime = new(big.Int).Add(participant.LockedTime, period).Uint64()
+		campaignRNodeGauge.Update(1)
+	} else {
+		campaignRNodeGauge.Update(0)
+	}
+	return nil
+}
+
+// checkCandidate reads the candidacy of the coinbase from campaign contract.
+func (m *campaignManager) checkCandidate() error {
+	instance, err := campaign.NewCampaign(m.ac.campaignContractAddr, m.ac.contractBackend)
+	if err != nil {
+		return err
+	}
+
+	_, start, stop, err := instance.CandidateInfoOf(nil, m.ac.address)
+	if err != nil {
+		return err
+	}
+	numPerRound, err := instance.NumPerRound(nil)
+	if err != nil {
+		return err
+	}
+
+	// the term of the next block, as claimCampaign sees it
+	number := m.ac.chain.CurrentHeader().Number.Uint64() + 1
+	term := uint64(0)
+	if numPerRound.Sign() > 0 {
+		term = (number - 1) / numPerRound.Uint64()
+	}
+
+	m.state.Term = term
+	m.state.StartTerm, m.state.StopTerm = start.Uint64(), stop.Uint64()
+	m.state.RemainingTerms = 0
+	if m.state.StopTerm > term {
+		m.state.RemainingTerms = m.state.StopTerm - term
+	}
+	campaignRemainingGauge.Update(int64(m.state.RemainingTerms))
+	return nil
+}
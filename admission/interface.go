@@ -19,6 +19,7 @@ package admission
 import (
 	"sync"
 
+	"bitbucket.org/cpchain/chain/accounts/abi/bind"
 	"bitbucket.org/cpchain/chain/accounts/keystore"
 	"bitbucket.org/cpchain/chain/api/rpc"
 	"bitbucket.org/cpchain/chain/contracts/dpor/campaign/tests"
@@ -53,15 +54,35 @@ type ApiBackend interface {
 	// AdmissionKey returns keystore key
 	AdmissionKey() *keystore.Key
 
+	// SetAdmissionTransactor sets the transaction signer to participate campaign
+	// for a coinbase whose key is not in the keystore
+	SetAdmissionTransactor(opts *bind.TransactOpts)
+
+	// AdmissionTransactor returns the transaction signer to participate campaign
+	AdmissionTransactor() *bind.TransactOpts
+
 	// RegisterInProcHandler registers the rpc.Server, handles RPC request to process the API requests in process
 	RegisterInProcHandler(localRPCServer *rpc.Server)
 
 	SetContractBackend(contractBackend contracts.Backend)
+
+	// SetPlotDir sets the directory storage proof plots are kept in
+	SetPlotDir(dir string)
+
+	// StartCampaignManager starts to renew the campaign for terms each time
+	// before the candidacy ends, 0 for the default number of terms
+	StartCampaignManager(terms uint64) error
+
+	// StopCampaignManager stops renewing the campaign
+	StopCampaignManager()
+
+	// CampaignState returns the candidacy kept by the campaign manager
+	CampaignState() CampaignState
 }
 
 // ProofWork represent a proof work
 type ProofWork interface {
-	// prove starts memory/cpu/... POW work.
+	// prove starts memory/cpu/storage/... proof work.
 	prove(abort <-chan interface{}, wg *sync.WaitGroup)
 
 	// error returns err if proof work is abnormal
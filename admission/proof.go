@@ -0,0 +1,147 @@
+package admission
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"bitbucket.org/cpchain/chain/accounts/abi"
+	"bitbucket.org/cpchain/chain/accounts/abi/bind"
+	"bitbucket.org/cpchain/chain/contracts/dpor/admission"
+	"bitbucket.org/cpchain/chain/types"
+)
+
+// proofParamsABI is the admission contract method giving the parameters of
+// proof works other than cpu and memory.
+const proofParamsABI = `[{"constant":true,"inputs":[{"name":"_name","type":"string"}],"name":"getProofParameters","outputs":[{"name":"","type":"uint256"},{"name":"","type":"uint256"}],"payable":false,"stateMutability":"view","type":"function"}]`
+
+var (
+	errNoProofParams = errors.New("proof work parameters are not set in admission contract")
+)
+
+// ProofParams are the parameters of a proof work set in admission contract.
+type ProofParams struct {
+	Difficulty uint64
+	Timeout    time.Duration
+}
+
+// ProofPlugin creates a kind of proof work for admission.
+type ProofPlugin struct {
+	// Required proofs are claimed to campaign contract, the campaign fails if
+	// any of them fails. Other proofs fail alone.
+	Required bool
+
+	// Params reads the parameters of the proof from admission contract, the
+	// proof is skipped if it returns an error and is not required.
+	Params func(ac *AdmissionControl) (ProofParams, error)
+
+	// Build creates the proof work of the coinbase of ac on header.
+	Build func(ac *AdmissionControl, params ProofParams, header *types.Header) (ProofWork, error)
+}
+
+var (
+	proofPluginsLock sync.RWMutex
+	proofPlugins     = make(map[string]ProofPlugin)
+)
+
+func init() {
+	for name, plugin := range map[string]ProofPlugin{
+		Cpu:     {Required: true, Params: cpuProofParams, Build: buildHashWork(sha256Func)},
+		Memory:  {Required: true, Params: memoryProofParams, Build: buildHashWork(scryptFunc)},
+		Storage: {Params: contractProofParams(Storage), Build: buildStorageWork},
+	} {
+		if err := RegisterProofWork(name, plugin); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// RegisterProofWork registers a kind of proof work by name, the result of the
+// proof is reported by GetResult under the name.
+func RegisterProofWork(name string, plugin ProofPlugin) error {
+	if name == "" || plugin.Params == nil || plugin.Build == nil {
+		return fmt.Errorf("invalid proof work %q", name)
+	}
+
+	proofPluginsLock.Lock()
+	defer proofPluginsLock.Unlock()
+
+	if _, ok := proofPlugins[name]; ok {
+		return fmt.Errorf("proof work %q already registered", name)
+	}
+	proofPlugins[name] = plugin
+	return nil
+}
+
+// ProofWorks returns the names of the proof works registered.
+func ProofWorks() []string {
+	proofPluginsLock.RLock()
+	defer proofPluginsLock.RUnlock()
+
+	names := make([]string, 0, len(proofPlugins))
+	for name := range proofPlugins {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (ac *AdmissionControl) admissionParameters() (cpuDifficulty, memoryDifficulty, cpuWorkTimeout, memoryWorkTimeout *big.Int, err error) {
+	instance, err := admission.NewAdmission(ac.admissionContractAddr, ac.contractBackend)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	return instance.GetAdmissionParameters(nil)
+}
+
+func cpuProofParams(ac *AdmissionControl) (ProofParams, error) {
+	cd, _, clt, _, err := ac.admissionParameters()
+	if err != nil {
+		return ProofParams{}, err
+	}
+	return ProofParams{Difficulty: cd.Uint64(), Timeout: time.Duration(clt.Int64()) * time.Second}, nil
+}
+
+func memoryProofParams(ac *AdmissionControl) (ProofParams, error) {
+	_, md, _, mct, err := ac.admissionParameters()
+	if err != nil {
+		return ProofParams{}, err
+	}
+	return ProofParams{Difficulty: md.Uint64(), Timeout: time.Duration(mct.Int64()) * time.Second}, nil
+}
+
+// contractProofParams returns a Params function reading the parameters of the
+// named proof by getProofParameters of admission contract. A zero timeout
+// means the proof is not enabled.
+func contractProofParams(name string) func(ac *AdmissionControl) (ProofParams, error) {
+	return func(ac *AdmissionControl) (ProofParams, error) {
+		parsed, err := abi.JSON(strings.NewReader(proofParamsABI))
+		if err != nil {
+			return ProofParams{}, err
+		}
+		contract := bind.NewBoundContract(ac.admissionContractAddr, parsed, ac.contractBackend, ac.contractBackend, ac.contractBackend)
+
+		var (
+			difficulty = new(*big.Int)
+			timeout    = new(*big.Int)
+		)
+		out := &[]interface{}{difficulty, timeout}
+		if err := contract.Call(nil, out, "getProofParameters", name); err != nil {
+			return ProofParams{}, errNoProofParams
+		}
+		if (*timeout).Sign() == 0 {
+			return ProofParams{}, errNoProofParams
+		}
+		return ProofParams{Difficulty: (*difficulty).Uint64(), Timeout: time.Duration((*timeout).Int64()) * time.Second}, nil
+	}
+}
+
+func buildHashWork(hashfn hashFn) func(ac *AdmissionControl, params ProofParams, header *types.Header) (ProofWork, error) {
+	return func(ac *AdmissionControl, params ProofParams, header *types.Header) (ProofWork, error) {
+		return newWork(params.Difficulty, params.Timeout, ac.address, header, hashfn), nil
+	}
+}
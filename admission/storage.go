@@ -0,0 +1,333 @@
+package admission
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"bitbucket.org/cpchain/chain/commons/log"
+	"bitbucket.org/cpchain/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// A plot file proves storage: it keeps the labels of 2^k indexes of a coinbase
+// sorted, so that an index whose label matches a challenge in difficulty bits
+// is looked up at once, while finding one without the plot takes about
+// 2^difficulty memory hard hashes.
+//
+// The file starts with plotMagic, k and the coinbase, followed by 2^k records
+// of the leading 8 bytes of a label and its index, both big endian, in the
+// order of the labels.
+
+const (
+	plotMagic      = "cpcplot1"
+	plotHeaderSize = len(plotMagic) + 1 + common.AddressLength
+	plotRecordSize = 16
+
+	// maxPlotBits bounds plot files to 2^maxPlotBits records, sorted in memory
+	maxPlotBits = 26
+)
+
+var (
+	errNoPlotDir     = errors.New("plot directory is not set")
+	errBadPlot       = errors.New("invalid plot file")
+	errPlotTooLarge  = errors.New("storage difficulty exceeds the max plot size")
+	errNoPlotMatched = errors.New("no plot record matches the challenge")
+	errPlotting      = errors.New("storage proof plot is being created")
+)
+
+// plotLabel returns the label of index in the plot of coinbase.
+func plotLabel(coinbase common.Address, index uint64) []byte {
+	indexBytes := make([]byte, 8)
+	binary.LittleEndian.PutUint64(indexBytes, index)
+
+	label, _ := scryptFunc(bytes.Join([][]byte{coinbase.Bytes(), indexBytes}, nil))
+	return label
+}
+
+// matchBits reports whether the leading bits of a and b are the same.
+func matchBits(a, b []byte, bits uint64) bool {
+	if bits > 256 || len(a) != 32 || len(b) != 32 {
+		return false
+	}
+	shift := uint(256 - bits)
+	return new(big.Int).Rsh(new(big.Int).SetBytes(a), shift).Cmp(new(big.Int).Rsh(new(big.Int).SetBytes(b), shift)) == 0
+}
+
+// plotBits returns the k of the plot for difficulty, with 2^(difficulty+1)
+// labels about 86% of challenges have a match.
+func plotBits(difficulty uint64) (uint8, error) {
+	if difficulty+1 > maxPlotBits {
+		return 0, errPlotTooLarge
+	}
+	return uint8(difficulty + 1), nil
+}
+
+// CreatePlot writes the plot of 2^k labels of coinbase to path.
+func CreatePlot(path string, coinbase common.Address, k uint8, abort <-chan interface{}) error {
+	if k > maxPlotBits {
+		return errPlotTooLarge
+	}
+	start := time.Now()
+
+	n := uint64(1) << k
+	records := make([]byte, n*plotRecordSize)
+	for i := uint64(0); i < n; i++ {
+		select {
+		case <-abort:
+			return ErrPowAbort
+		default:
+		}
+
+		record := records[i*plotRecordSize : (i+1)*plotRecordSize]
+		copy(record[:8], plotLabel(coinbase, i)[:8])
+		binary.BigEndian.PutUint64(record[8:], i)
+	}
+	sort.Sort(plotRecords(records))
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	w.WriteString(plotMagic)
+	w.WriteByte(k)
+	w.Write(coinbase.Bytes())
+	w.Write(records)
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	log.Info("created storage proof plot", "path", path, "coinbase", coinbase.Hex(), "records", n, "elapsed", common.PrettyDuration(time.Since(start)))
+	return os.Rename(tmp, path)
+}
+
+// plotRecords sorts plot records by label.
+type plotRecords []byte
+
+func (r plotRecords) Len() int { return len(r) / plotRecordSize }
+func (r plotRecords) Less(i, j int) bool {
+	return bytes.Compare(r[i*plotRecordSize:i*plotRecordSize+8], r[j*plotRecordSize:j*plotRecordSize+8]) < 0
+}
+func (r plotRecords) Swap(i, j int) {
+	var tmp [plotRecordSize]byte
+	copy(tmp[:], r[i*plotRecordSize:(i+1)*plotRecordSize])
+	copy(r[i*plotRecordSize:(i+1)*plotRecordSize], r[j*plotRecordSize:(j+1)*plotRecordSize])
+	copy(r[j*plotRecordSize:(j+1)*plotRecordSize], tmp[:])
+}
+
+// plot is an opened plot file.
+type plot struct {
+	f        *os.File
+	k        uint8
+	coinbase common.Address
+}
+
+func openPlot(path string) (*plot, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	header := make([]byte, plotHeaderSize)
+	if _, err := io.ReadFull(f, header); err != nil || string(header[:len(plotMagic)]) != plotMagic {
+		f.Close()
+		return nil, errBadPlot
+	}
+	p := &plot{
+		f:        f,
+		k:        header[len(plotMagic)],
+		coinbase: common.BytesToAddress(header[len(plotMagic)+1:]),
+	}
+
+	info, err := f.Stat()
+	if err != nil || info.Size() != int64(plotHeaderSize)+int64(plotRecordSize)<<p.k {
+		f.Close()
+		return nil, errBadPlot
+	}
+	return p, nil
+}
+
+func (p *plot) close() error {
+	return p.f.Close()
+}
+
+func (p *plot) record(i uint64) (prefix uint64, index uint64, err error) {
+	record := make([]byte, plotRecordSize)
+	if _, err := p.f.ReadAt(record, int64(plotHeaderSize)+int64(i*plotRecordSize)); err != nil {
+		return 0, 0, err
+	}
+	return binary.BigEndian.Uint64(record[:8]), binary.BigEndian.Uint64(record[8:]), nil
+}
+
+// lookup returns an index whose label matches challenge in bits, bits is at
+// most 64.
+func (p *plot) lookup(challenge []byte, bits uint64) (uint64, error) {
+	shift := uint(64 - bits)
+	target := binary.BigEndian.Uint64(challenge[:8]) >> shift
+
+	var err error
+	n := int(uint64(1) << p.k)
+	i := sort.Search(n, func(i int) bool {
+		prefix, _, rerr := p.record(uint64(i))
+		if rerr != nil {
+			err = rerr
+			return true
+		}
+		return prefix>>shift >= target
+	})
+	if err != nil {
+		return 0, err
+	}
+	if i == n {
+		return 0, errNoPlotMatched
+	}
+
+	prefix, index, err := p.record(uint64(i))
+	if err != nil {
+		return 0, err
+	}
+	if prefix>>shift != target {
+		return 0, errNoPlotMatched
+	}
+	return index, nil
+}
+
+// storageWork proves the coinbase keeps a plot for difficulty.
+type storageWork struct {
+	difficulty uint64
+	timeout    time.Duration
+	coinbase   common.Address
+	header     *types.Header
+	path       string
+
+	nonce uint64
+	err   error
+	mutex sync.RWMutex
+}
+
+// buildStorageWork builds the work on the plot of the coinbase of ac. The
+// plot is created in background if it does not exist, and the proof is
+// skipped until it is done.
+func buildStorageWork(ac *AdmissionControl, params ProofParams, header *types.Header) (ProofWork, error) {
+	if ac.plotDir == "" {
+		return nil, errNoPlotDir
+	}
+	k, err := plotBits(params.Difficulty)
+	if err != nil {
+		return nil, err
+	}
+
+	path := filepath.Join(ac.plotDir, fmt.Sprintf("%x.plot", ac.address.Bytes()))
+	if !hasPlot(path, ac.address, k) {
+		if atomic.CompareAndSwapInt32(&ac.plotting, 0, 1) {
+			go func() {
+				defer atomic.StoreInt32(&ac.plotting, 0)
+
+				log.Info("creating storage proof plot, it takes a while", "path", path, "difficulty", params.Difficulty)
+				if err := CreatePlot(path, ac.address, k, nil); err != nil {
+					log.Warn("failed to create storage proof plot", "path", path, "error", err)
+				}
+			}()
+		}
+		return nil, errPlotting
+	}
+
+	return &storageWork{
+		difficulty: params.Difficulty,
+		timeout:    params.Timeout,
+		coinbase:   ac.address,
+		header:     header,
+		path:       path,
+	}, nil
+}
+
+// hasPlot reports whether the plot of 2^k labels of coinbase is at path.
+func hasPlot(path string, coinbase common.Address, k uint8) bool {
+	p, err := openPlot(path)
+	if err != nil {
+		return false
+	}
+	defer p.close()
+
+	return p.k == k && p.coinbase == coinbase
+}
+
+func (w *storageWork) setErr(err error) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.err = err
+}
+
+// prove implements ProofWork, it looks up the plot for the block hash.
+func (w *storageWork) prove(abort <-chan interface{}, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	start := time.Now()
+	p, err := openPlot(w.path)
+	if err != nil {
+		w.setErr(err)
+		return
+	}
+	defer p.close()
+
+	index, err := p.lookup(w.header.Hash().Bytes(), w.difficulty)
+	if err == nil && time.Since(start) > w.timeout {
+		err = ErrPowTimeout
+	}
+	if err == nil && !ValidateStorage(w.coinbase, w.header.Hash().Bytes(), index, w.difficulty) {
+		err = errBadPlot
+	}
+	if err != nil {
+		w.setErr(err)
+		return
+	}
+
+	w.mutex.Lock()
+	w.nonce = index
+	w.mutex.Unlock()
+	log.Info("found plot record", "block hash", w.header.Hash().Hex(), "difficulty", w.difficulty,
+		"sender", w.coinbase.Hex(), "index", index, "timeCost(s)", time.Since(start).Seconds())
+}
+
+// error implements ProofWork
+func (w *storageWork) error() error {
+	w.mutex.RLock()
+	defer w.mutex.RUnlock()
+
+	return w.err
+}
+
+// result implements ProofWork, the nonce is the plot index found
+func (w *storageWork) result() Result {
+	w.mutex.RLock()
+	defer w.mutex.RUnlock()
+
+	var nonce uint64
+	if w.err == nil {
+		nonce = w.nonce
+	}
+	return Result{
+		BlockNumber: w.header.Number.Int64(),
+		Nonce:       nonce,
+		Success:     w.err == nil,
+	}
+}
@@ -0,0 +1,73 @@
+package admission
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestStorageProof(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cpchain-plot")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	var (
+		coinbase   = common.HexToAddress("0x00000000000000000000000000000000000000c1")
+		difficulty = uint64(6)
+		path       = filepath.Join(dir, "test.plot")
+	)
+	k, _ := plotBits(difficulty)
+	if err := CreatePlot(path, coinbase, k, nil); err != nil {
+		t.Fatal(err)
+	}
+	if !hasPlot(path, coinbase, k) || hasPlot(path, common.Address{}, k) || hasPlot(path, coinbase, k+1) {
+		t.Fatal("plot is not recognized by coinbase and size")
+	}
+
+	p, err := openPlot(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer p.close()
+
+	found := 0
+	for i := 0; i < 20; i++ {
+		challenge := crypto.Keccak256([]byte{byte(i)})
+		index, err := p.lookup(challenge, difficulty)
+		if err == errNoPlotMatched {
+			continue
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		found++
+
+		if !ValidateStorage(coinbase, challenge, index, difficulty) {
+			t.Fatalf("challenge %x: index %d is not valid", challenge, index)
+		}
+		if ValidateStorage(common.Address{}, challenge, index, difficulty) && ValidateStorage(coinbase, challenge, index, 64) {
+			t.Fatalf("challenge %x: index %d is valid for another coinbase and difficulty", challenge, index)
+		}
+	}
+	if found == 0 {
+		t.Fatal("no challenge matched by the plot")
+	}
+}
+
+func TestRegisterProofWork(t *testing.T) {
+	if err := RegisterProofWork(Cpu, ProofPlugin{Params: cpuProofParams, Build: buildHashWork(sha256Func)}); err == nil {
+		t.Fatal("registering cpu proof twice: want error")
+	}
+	if err := RegisterProofWork("uptime", ProofPlugin{}); err == nil {
+		t.Fatal("registering proof without functions: want error")
+	}
+	if names := ProofWorks(); len(names) != 3 || names[0] != Cpu || names[1] != Memory || names[2] != Storage {
+		t.Fatalf("proof works %v", names)
+	}
+}
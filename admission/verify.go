@@ -34,3 +34,12 @@ func ValidateMemory(sender common.Address, blockHash []byte, nonce uint64, diffi
 func ValidateCpu(sender common.Address, blockHash []byte, nonce uint64, difficulty uint64) bool {
 	return validate(difficulty, blockHash, sender, nonce, sha256Func)
 }
+
+// ValidateStorage checks the label of index nonce in the plot of sender
+// matches blockHash in the leading difficulty bits.
+func ValidateStorage(sender common.Address, blockHash []byte, nonce uint64, difficulty uint64) bool {
+	if _, err := plotBits(difficulty); err != nil {
+		return false
+	}
+	return matchBits(plotLabel(sender, nonce), blockHash, difficulty)
+}
@@ -0,0 +1,135 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package graphql
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// arguments are the field arguments of a query, with variables substituted.
+type arguments map[string]interface{}
+
+// long returns an unsigned integer argument, given as a number or as a decimal
+// or hex string.
+func (a arguments) long(name string) (uint64, bool, error) {
+	v, ok := a[name]
+	if !ok || v == nil {
+		return 0, false, nil
+	}
+	n, err := toUint64(v)
+	if err != nil {
+		return 0, false, fmt.Errorf("invalid argument %q: %v", name, err)
+	}
+	return n, true, nil
+}
+
+func toUint64(v interface{}) (uint64, error) {
+	switch v := v.(type) {
+	case int64:
+		if v >= 0 {
+			return uint64(v), nil
+		}
+	case float64:
+		if v >= 0 && v <= math.MaxUint64 && v == math.Trunc(v) {
+			return uint64(v), nil
+		}
+	case json.Number:
+		return strconv.ParseUint(string(v), 10, 64)
+	case string:
+		if strings.HasPrefix(v, "0x") || strings.HasPrefix(v, "0X") {
+			return hexutil.DecodeUint64(v)
+		}
+		return strconv.ParseUint(v, 10, 64)
+	}
+	return 0, fmt.Errorf("%v is not a valid unsigned integer", v)
+}
+
+// hash returns a 32 bytes hex argument.
+func (a arguments) hash(name string) (common.Hash, bool, error) {
+	v, ok := a[name]
+	if !ok || v == nil {
+		return common.Hash{}, false, nil
+	}
+	hash, err := toHash(v)
+	if err != nil {
+		return common.Hash{}, false, fmt.Errorf("invalid argument %q: %v", name, err)
+	}
+	return hash, true, nil
+}
+
+func toHash(v interface{}) (common.Hash, error) {
+	var hash common.Hash
+	s, ok := v.(string)
+	if !ok {
+		return hash, fmt.Errorf("%v is not a hex string", v)
+	}
+	err := hash.UnmarshalText([]byte(s))
+	return hash, err
+}
+
+// address returns an account address argument.
+func (a arguments) address(name string) (common.Address, bool, error) {
+	v, ok := a[name]
+	if !ok || v == nil {
+		return common.Address{}, false, nil
+	}
+	addr, err := toAddress(v)
+	if err != nil {
+		return common.Address{}, false, fmt.Errorf("invalid argument %q: %v", name, err)
+	}
+	return addr, true, nil
+}
+
+func toAddress(v interface{}) (common.Address, error) {
+	s, ok := v.(string)
+	if !ok || !common.IsHexAddress(s) {
+		return common.Address{}, fmt.Errorf("%v is not a valid address", v)
+	}
+	return common.HexToAddress(s), nil
+}
+
+// object returns an input object argument.
+func (a arguments) object(name string) (arguments, bool, error) {
+	v, ok := a[name]
+	if !ok || v == nil {
+		return nil, false, nil
+	}
+	obj, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, false, fmt.Errorf("invalid argument %q: not an input object", name)
+	}
+	return arguments(obj), true, nil
+}
+
+// list returns a list argument, a single value is coerced to a list of one.
+func (a arguments) list(name string) ([]interface{}, bool) {
+	v, ok := a[name]
+	if !ok || v == nil {
+		return nil, false
+	}
+	if list, ok := v.([]interface{}); ok {
+		return list, true
+	}
+	return []interface{}{v}, true
+}
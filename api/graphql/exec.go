@@ -0,0 +1,405 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+const (
+	// maxQueryDepth is the maximum nesting of the selection sets of a query.
+	maxQueryDepth = 10
+
+	// maxQueryNodes is the maximum number of fields a query resolves, list
+	// items counted one by one.
+	maxQueryNodes = 20000
+)
+
+// errUnknownField is returned by resolvers for fields their type does not have.
+var errUnknownField = errors.New("unknown field")
+
+// object is a resolver of a GraphQL object type. Field values are nil, scalars
+// marshalled as is, objects and []object.
+type object interface {
+	typeName() string
+	resolve(ctx context.Context, field string, args arguments) (interface{}, error)
+}
+
+// Request is a GraphQL request as posted by clients.
+type Request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName,omitempty"`
+	Variables     map[string]interface{} `json:"variables,omitempty"`
+}
+
+// Response is the result of a GraphQL request. Fields which failed to resolve
+// are null in Data and reported in Errors.
+type Response struct {
+	Data   interface{}   `json:"data,omitempty"`
+	Errors []*QueryError `json:"errors,omitempty"`
+}
+
+// QueryError is an error of a GraphQL request.
+type QueryError struct {
+	Message string        `json:"message"`
+	Path    []interface{} `json:"path,omitempty"`
+}
+
+func (e *QueryError) Error() string {
+	return e.Message
+}
+
+// orderedMap is a JSON object keeping the order of the selection set.
+type orderedMap struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+func (m *orderedMap) set(key string, value interface{}) {
+	if _, ok := m.values[key]; !ok {
+		m.keys = append(m.keys, key)
+	}
+	m.values[key] = value
+}
+
+// MarshalJSON implements json.Marshaler.
+func (m *orderedMap) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range m.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		k, _ := json.Marshal(key)
+		buf.Write(k)
+		buf.WriteByte(':')
+		v, err := json.Marshal(m.values[key])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(v)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// executor runs an operation of a document against a root resolver.
+type executor struct {
+	doc    *document
+	vars   map[string]interface{}
+	errors []*QueryError
+
+	nodes     int  // number of fields resolved
+	exhausted bool // the node budget is spent, the remaining fields are null
+}
+
+// execute parses and runs a query request.
+func execute(ctx context.Context, root object, req *Request) *Response {
+	doc, err := parse(req.Query)
+	if err != nil {
+		return &Response{Errors: []*QueryError{{Message: err.Error()}}}
+	}
+	op, err := selectOperation(doc, req.OperationName)
+	if err != nil {
+		return &Response{Errors: []*QueryError{{Message: err.Error()}}}
+	}
+	if op.kind != "query" {
+		return &Response{Errors: []*QueryError{{Message: fmt.Sprintf("%s operations are not supported", op.kind)}}}
+	}
+	if depth := selectionDepth(doc, op.selection, make(map[string]bool)); depth > maxQueryDepth {
+		return &Response{Errors: []*QueryError{{Message: fmt.Sprintf("query too deep, %d levels of at most %d", depth, maxQueryDepth)}}}
+	}
+	e := &executor{doc: doc, vars: make(map[string]interface{})}
+	for _, def := range op.variables {
+		if value, ok := req.Variables[def.name]; ok {
+			e.vars[def.name] = value
+		} else {
+			e.vars[def.name] = def.defValue
+		}
+	}
+	data := e.executeSelection(ctx, root, op.selection, nil)
+	return &Response{Data: data, Errors: e.errors}
+}
+
+// selectOperation picks the operation to run by name, the name may be omitted
+// if the document has a single operation.
+func selectOperation(doc *document, name string) (*operation, error) {
+	if name == "" {
+		if len(doc.operations) > 1 {
+			return nil, errors.New("operation name required for documents with several operations")
+		}
+		return doc.operations[0], nil
+	}
+	for _, op := range doc.operations {
+		if op.name == name {
+			return op, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown operation %q", name)
+}
+
+// selectionDepth returns the nesting of a selection set, fragments expanded.
+func selectionDepth(doc *document, set []selection, visited map[string]bool) int {
+	depth := 0
+	for _, sel := range set {
+		var d int
+		switch sel := sel.(type) {
+		case *field:
+			if len(sel.selection) > 0 {
+				d = 1 + selectionDepth(doc, sel.selection, visited)
+			} else {
+				d = 1
+			}
+		case *fragmentSpread:
+			frag, ok := doc.fragments[sel.name]
+			if !ok || visited[sel.name] {
+				continue
+			}
+			visited[sel.name] = true
+			d = selectionDepth(doc, frag.selection, visited)
+			delete(visited, sel.name)
+		case *inlineFragment:
+			d = selectionDepth(doc, sel.selection, visited)
+		}
+		if d > depth {
+			depth = d
+		}
+	}
+	return depth
+}
+
+func (e *executor) fail(path []interface{}, format string, args ...interface{}) {
+	e.errors = append(e.errors, &QueryError{
+		Message: fmt.Sprintf(format, args...),
+		Path:    append([]interface{}(nil), path...),
+	})
+}
+
+// executeSelection resolves the fields of a selection set on an object.
+func (e *executor) executeSelection(ctx context.Context, obj object, set []selection, path []interface{}) *orderedMap {
+	result := &orderedMap{values: make(map[string]interface{})}
+	keys, fields := e.collectFields(obj, set, path, make(map[string]bool))
+
+	for _, key := range keys {
+		merged := fields[key]
+		f := merged[0]
+		fieldPath := append(path, key)
+
+		if e.nodes++; e.nodes > maxQueryNodes {
+			if !e.exhausted {
+				e.fail(fieldPath, "query too large, at most %d fields", maxQueryNodes)
+				e.exhausted = true
+			}
+			result.set(key, nil)
+			continue
+		}
+
+		if f.name == "__typename" {
+			result.set(key, obj.typeName())
+			continue
+		}
+		args, err := e.arguments(f.arguments)
+		if err != nil {
+			e.fail(fieldPath, "%v", err)
+			result.set(key, nil)
+			continue
+		}
+		value, err := obj.resolve(ctx, f.name, args)
+		if err == errUnknownField {
+			e.fail(fieldPath, "unknown field %q on type %s", f.name, obj.typeName())
+			result.set(key, nil)
+			continue
+		}
+		if err != nil {
+			e.fail(fieldPath, "%v", err)
+			result.set(key, nil)
+			continue
+		}
+		// fields with the same response key have their selections merged
+		var sub []selection
+		for _, f := range merged {
+			sub = append(sub, f.selection...)
+		}
+		result.set(key, e.complete(ctx, f, value, sub, fieldPath))
+	}
+	return result
+}
+
+// complete executes the sub-selection of a resolved field value.
+func (e *executor) complete(ctx context.Context, f *field, value interface{}, sub []selection, path []interface{}) interface{} {
+	switch value := value.(type) {
+	case nil:
+		return nil
+
+	case object:
+		if len(sub) == 0 {
+			e.fail(path, "field %q of type %s must have a selection of subfields", f.name, value.typeName())
+			return nil
+		}
+		return e.executeSelection(ctx, value, sub, path)
+
+	case []object:
+		if len(sub) == 0 {
+			e.fail(path, "field %q must have a selection of subfields", f.name)
+			return nil
+		}
+		list := make([]interface{}, len(value))
+		for i, item := range value {
+			list[i] = e.complete(ctx, f, item, sub, append(path, i))
+		}
+		return list
+
+	default:
+		if len(sub) != 0 {
+			e.fail(path, "field %q is a scalar and cannot have subfields", f.name)
+			return nil
+		}
+		return value
+	}
+}
+
+// collectFields flattens fragments and groups the fields of a selection set by
+// response key, in query order.
+func (e *executor) collectFields(obj object, set []selection, path []interface{}, visited map[string]bool) ([]string, map[string][]*field) {
+	var (
+		keys   []string
+		fields = make(map[string][]*field)
+	)
+	add := func(f *field) {
+		if _, ok := fields[f.key()]; !ok {
+			keys = append(keys, f.key())
+		}
+		fields[f.key()] = append(fields[f.key()], f)
+	}
+	merge := func(subKeys []string, subFields map[string][]*field) {
+		for _, key := range subKeys {
+			for _, f := range subFields[key] {
+				add(f)
+			}
+		}
+	}
+	for _, sel := range set {
+		switch sel := sel.(type) {
+		case *field:
+			if e.included(sel.directives, path) {
+				add(sel)
+			}
+
+		case *fragmentSpread:
+			if !e.included(sel.directives, path) || visited[sel.name] {
+				continue
+			}
+			frag, ok := e.doc.fragments[sel.name]
+			if !ok {
+				e.fail(path, "unknown fragment %q", sel.name)
+				continue
+			}
+			if frag.typeCond != obj.typeName() {
+				continue
+			}
+			visited[sel.name] = true
+			merge(e.collectFields(obj, frag.selection, path, visited))
+
+		case *inlineFragment:
+			if !e.included(sel.directives, path) {
+				continue
+			}
+			if sel.typeCond != "" && sel.typeCond != obj.typeName() {
+				continue
+			}
+			merge(e.collectFields(obj, sel.selection, path, visited))
+		}
+	}
+	return keys, fields
+}
+
+// included evaluates the @skip and @include directives.
+func (e *executor) included(directives []*directive, path []interface{}) bool {
+	for _, d := range directives {
+		if d.name != "skip" && d.name != "include" {
+			continue
+		}
+		args, err := e.arguments(d.arguments)
+		if err != nil {
+			e.fail(path, "%v", err)
+			return false
+		}
+		cond, ok := args["if"].(bool)
+		if !ok {
+			e.fail(path, "directive @%s requires a boolean \"if\" argument", d.name)
+			return false
+		}
+		if cond == (d.name == "skip") {
+			return false
+		}
+	}
+	return true
+}
+
+// arguments substitutes the variables of field arguments.
+func (e *executor) arguments(args []*argument) (arguments, error) {
+	result := make(arguments, len(args))
+	for _, arg := range args {
+		value, err := e.value(arg.value)
+		if err != nil {
+			return nil, err
+		}
+		result[arg.name] = value
+	}
+	return result, nil
+}
+
+func (e *executor) value(v interface{}) (interface{}, error) {
+	switch v := v.(type) {
+	case variable:
+		value, ok := e.vars[string(v)]
+		if !ok {
+			return nil, fmt.Errorf("undefined variable $%s", v)
+		}
+		return value, nil
+
+	case enumValue:
+		return string(v), nil
+
+	case []interface{}:
+		list := make([]interface{}, len(v))
+		for i, item := range v {
+			value, err := e.value(item)
+			if err != nil {
+				return nil, err
+			}
+			list[i] = value
+		}
+		return list, nil
+
+	case map[string]interface{}:
+		obj := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			value, err := e.value(item)
+			if err != nil {
+				return nil, err
+			}
+			obj[key] = value
+		}
+		return obj, nil
+	}
+	return v, nil
+}
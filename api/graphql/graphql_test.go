@@ -0,0 +1,238 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"bitbucket.org/cpchain/chain/api/rpc"
+	"bitbucket.org/cpchain/chain/internal/cpcapi"
+	"bitbucket.org/cpchain/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var (
+	testContract = common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	testTopic    = common.HexToHash("0x01")
+	testProposer = common.HexToAddress("0x0000000000000000000000000000000000000001")
+)
+
+// testBackend serves a short chain, the methods not overridden panic.
+type testBackend struct {
+	cpcapi.Backend
+	blocks   []*types.Block
+	receipts map[common.Hash]types.Receipts
+}
+
+func newTestBackend() *testBackend {
+	b := &testBackend{receipts: make(map[common.Hash]types.Receipts)}
+	parent := common.Hash{}
+	for i := 0; i < 3; i++ {
+		header := &types.Header{
+			ParentHash: parent,
+			Number:     big.NewInt(int64(i)),
+			Time:       big.NewInt(int64(i * 1000)),
+			GasLimit:   100000,
+			Dpor:       types.DporSnap{Proposers: []common.Address{testProposer}},
+		}
+		var (
+			txs      []*types.Transaction
+			receipts types.Receipts
+		)
+		if i == 2 {
+			tx := types.NewTransaction(0, testContract, big.NewInt(1), 21000, big.NewInt(1), nil)
+			receipt := types.NewReceipt(nil, false, 21000)
+			receipt.GasUsed = 21000
+			receipt.Logs = []*types.Log{{Address: testContract, Topics: []common.Hash{testTopic}, Data: []byte{1}, BlockNumber: 2}}
+			receipt.Bloom = types.CreateBloom(types.Receipts{receipt})
+			header.LogsBloom = receipt.Bloom
+			txs, receipts = []*types.Transaction{tx}, types.Receipts{receipt}
+		}
+		block := types.NewBlock(header, txs, receipts)
+		b.blocks = append(b.blocks, block)
+		b.receipts[block.Hash()] = receipts
+		parent = block.Hash()
+	}
+	return b
+}
+
+func (b *testBackend) BlockByNumber(ctx context.Context, number rpc.BlockNumber) (*types.Block, error) {
+	if number == rpc.LatestBlockNumber {
+		return b.blocks[len(b.blocks)-1], nil
+	}
+	if int(number) >= len(b.blocks) {
+		return nil, nil
+	}
+	return b.blocks[number], nil
+}
+
+func (b *testBackend) HeaderByNumber(ctx context.Context, number rpc.BlockNumber) (*types.Header, error) {
+	block, err := b.BlockByNumber(ctx, number)
+	if block == nil {
+		return nil, err
+	}
+	return block.Header(), nil
+}
+
+func (b *testBackend) GetBlock(ctx context.Context, hash common.Hash) (*types.Block, error) {
+	for _, block := range b.blocks {
+		if block.Hash() == hash {
+			return block, nil
+		}
+	}
+	return nil, nil
+}
+
+func (b *testBackend) GetReceipts(ctx context.Context, hash common.Hash) (types.Receipts, error) {
+	return b.receipts[hash], nil
+}
+
+func (b *testBackend) Proposers(number rpc.BlockNumber) ([]common.Address, error) {
+	return []common.Address{testProposer}, nil
+}
+
+func (b *testBackend) ViewLen() uint64 { return 3 }
+func (b *testBackend) TermLen() uint64 { return 4 }
+
+// run executes a query and returns its JSON encoded response.
+func run(t *testing.T, query string, vars map[string]interface{}) string {
+	resp := New(newTestBackend()).Execute(context.Background(), &Request{Query: query, Variables: vars})
+	out, err := json.Marshal(resp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(out)
+}
+
+func TestQueryBlock(t *testing.T) {
+	tests := []struct {
+		query string
+		vars  map[string]interface{}
+		want  string
+	}{
+		{
+			`{ block(number: 1) { number parent { number } timestamp } }`, nil,
+			`{"data":{"block":{"number":1,"parent":{"number":0},"timestamp":"0x3e8"}}}`,
+		},
+		{
+			`query Head($n: Long = 2) { b: block(number: $n) { number transactionCount proposers } }`, nil,
+			`{"data":{"b":{"number":2,"transactionCount":1,"proposers":["0x0000000000000000000000000000000000000001"]}}}`,
+		},
+		{
+			`query ($n: Long) { block(number: $n) { ...fields } } fragment fields on Block { number __typename }`,
+			map[string]interface{}{"n": json.Number("0")},
+			`{"data":{"block":{"number":0,"__typename":"Block"}}}`,
+		},
+		{
+			`{ block(number: 9) { number } }`, nil,
+			`{"data":{"block":null}}`,
+		},
+		{
+			`{ block { number committee { term view proposer } } }`, nil,
+			`{"data":{"block":{"number":2,"committee":{"term":0,"view":0,"proposer":"0x0000000000000000000000000000000000000001"}}}}`,
+		},
+		{
+			`{ block { transactions { index status gasUsed logs { topics data } } } }`, nil,
+			`{"data":{"block":{"transactions":[{"index":0,"status":1,"gasUsed":21000,"logs":[{"topics":["0x0000000000000000000000000000000000000000000000000000000000000001"],"data":"0x01"}]}]}}}`,
+		},
+		{
+			`{ blocks(from: 1) { number ... on Block @skip(if: true) { hash } } }`, nil,
+			`{"data":{"blocks":[{"number":1},{"number":2}]}}`,
+		},
+		{
+			`{ logs(filter: {fromBlock: 0, addresses: ["0x00000000000000000000000000000000000000aa"]}) { account { address } transaction { block { number } } } }`, nil,
+			`{"data":{"logs":[{"account":{"address":"0x00000000000000000000000000000000000000aa"},"transaction":{"block":{"number":2}}}]}}`,
+		},
+		{
+			`{ logs(filter: {fromBlock: 0, topics: [["0x0000000000000000000000000000000000000000000000000000000000000002"]]}) { data } }`, nil,
+			`{"data":{"logs":[]}}`,
+		},
+	}
+	for i, tt := range tests {
+		if have := run(t, tt.query, tt.vars); have != tt.want {
+			t.Errorf("test %d: wrong response\nhave %s\nwant %s", i, have, tt.want)
+		}
+	}
+}
+
+func TestQueryErrors(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{`{ block { number `, `{"errors":[{"message":"syntax error at 1:18: unterminated selection set"}]}`},
+		{`{ block { nonsense } }`, `{"data":{"block":{"nonsense":null}},"errors":[{"message":"unknown field \"nonsense\" on type Block","path":["block","nonsense"]}]}`},
+		{`{ block }`, `{"data":{"block":null},"errors":[{"message":"field \"block\" of type Block must have a selection of subfields","path":["block"]}]}`},
+		{`{ blocks(from: 0, to: 5000) { number } }`, `{"data":{"blocks":null},"errors":[{"message":"block range too large, at most 100 blocks","path":["blocks"]}]}`},
+		{`{ block { parent { parent { parent { parent { parent { parent { parent { parent { parent { parent { number } } } } } } } } } } } }`, `{"errors":[{"message":"query too deep, 12 levels of at most 10"}]}`},
+		{`{ block { ...a } } fragment a on Block { parent { ...b } } fragment b on Block { parent { ...a } }`, `{"data":{"block":{"parent":{"parent":{"parent":null}}}}}`},
+		{`mutation { block { number } }`, `{"errors":[{"message":"mutation operations are not supported"}]}`},
+		{`{ block(number: $n) { number } }`, `{"data":{"block":null},"errors":[{"message":"undefined variable $n","path":["block"]}]}`},
+	}
+	for i, tt := range tests {
+		if have := run(t, tt.query, nil); have != tt.want {
+			t.Errorf("test %d: wrong response\nhave %s\nwant %s", i, have, tt.want)
+		}
+	}
+}
+
+func TestHTTPHandler(t *testing.T) {
+	server := httptest.NewServer(New(newTestBackend()))
+	defer server.Close()
+
+	body := `{"query":"query ($n: Long!) { block(number: $n) { number } }","variables":{"n":1}}`
+	resp, err := http.Post(server.URL, contentType, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var result struct {
+		Data struct {
+			Block struct{ Number uint64 }
+		}
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		t.Fatal(err)
+	}
+	if result.Data.Block.Number != 1 {
+		t.Fatalf("wrong block number: have %d, want 1", result.Data.Block.Number)
+	}
+
+	resp, err = http.Get(server.URL + "/schema")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("schema request failed: %s", resp.Status)
+	}
+
+	resp, err = http.Post(server.URL, contentType, strings.NewReader(`{"query":`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("malformed request: have status %s, want 400", resp.Status)
+	}
+}
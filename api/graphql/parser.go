@@ -0,0 +1,653 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package graphql
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// document is a parsed GraphQL query document.
+type document struct {
+	operations []*operation
+	fragments  map[string]*fragment
+}
+
+// operation is a query, mutation or subscription definition.
+type operation struct {
+	kind      string
+	name      string
+	variables []*variableDefinition
+	selection []selection
+}
+
+type variableDefinition struct {
+	name     string
+	defValue interface{}
+}
+
+// fragment is a named fragment definition.
+type fragment struct {
+	name      string
+	typeCond  string
+	selection []selection
+}
+
+// selection is one of *field, *fragmentSpread and *inlineFragment.
+type selection interface{}
+
+type field struct {
+	alias      string
+	name       string
+	arguments  []*argument
+	directives []*directive
+	selection  []selection
+}
+
+// key returns the name of the field in the response.
+func (f *field) key() string {
+	if f.alias != "" {
+		return f.alias
+	}
+	return f.name
+}
+
+type fragmentSpread struct {
+	name       string
+	directives []*directive
+}
+
+type inlineFragment struct {
+	typeCond   string
+	directives []*directive
+	selection  []selection
+}
+
+type argument struct {
+	name  string
+	value interface{}
+}
+
+type directive struct {
+	name      string
+	arguments []*argument
+}
+
+// Literal values are parsed to nil, bool, int64, float64, string, enumValue,
+// variable, []interface{} and map[string]interface{}.
+type (
+	enumValue string
+	variable  string
+)
+
+// maxParseDepth bounds the nesting of selection sets, list and object values
+// and types while parsing, before the depth of the query is checked.
+const maxParseDepth = 64
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenPunct
+	tokenName
+	tokenInt
+	tokenFloat
+	tokenString
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+// parser is a recursive descent parser of the executable subset of the GraphQL
+// language, type system definitions are not supported.
+type parser struct {
+	src   string
+	pos   int
+	tok   token
+	depth int // nesting of the parsed selection sets, values and types
+}
+
+// parse parses a query document.
+func parse(src string) (*document, error) {
+	p := &parser{src: src}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	doc := &document{fragments: make(map[string]*fragment)}
+	for p.tok.kind != tokenEOF {
+		switch {
+		case p.is("{"):
+			sel, err := p.parseSelectionSet()
+			if err != nil {
+				return nil, err
+			}
+			doc.operations = append(doc.operations, &operation{kind: "query", selection: sel})
+
+		case p.is("query"), p.is("mutation"), p.is("subscription"):
+			op, err := p.parseOperation()
+			if err != nil {
+				return nil, err
+			}
+			doc.operations = append(doc.operations, op)
+
+		case p.is("fragment"):
+			frag, err := p.parseFragment()
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := doc.fragments[frag.name]; ok {
+				return nil, fmt.Errorf("duplicate fragment %q", frag.name)
+			}
+			doc.fragments[frag.name] = frag
+
+		default:
+			return nil, p.errorf("unexpected %q", p.tok.text)
+		}
+	}
+	if len(doc.operations) == 0 {
+		return nil, fmt.Errorf("no operation in query")
+	}
+	return doc, nil
+}
+
+// errorf returns a syntax error at the current token.
+func (p *parser) errorf(format string, args ...interface{}) error {
+	line, col := 1, 1
+	for _, c := range p.src[:p.tok.pos] {
+		if c == '\n' {
+			line, col = line+1, 1
+		} else {
+			col++
+		}
+	}
+	return fmt.Errorf("syntax error at %d:%d: %s", line, col, fmt.Sprintf(format, args...))
+}
+
+// is reports whether the current token is the given punctuator or name.
+func (p *parser) is(text string) bool {
+	return (p.tok.kind == tokenPunct || p.tok.kind == tokenName) && p.tok.text == text
+}
+
+// expect consumes the given punctuator or name.
+func (p *parser) expect(text string) error {
+	if !p.is(text) {
+		if p.tok.kind == tokenEOF {
+			return p.errorf("expected %q, found end of query", text)
+		}
+		return p.errorf("expected %q, found %q", text, p.tok.text)
+	}
+	return p.advance()
+}
+
+// name consumes a name.
+func (p *parser) name() (string, error) {
+	if p.tok.kind != tokenName {
+		return "", p.errorf("expected name, found %q", p.tok.text)
+	}
+	name := p.tok.text
+	return name, p.advance()
+}
+
+// advance reads the next token, skipping whitespace, commas and comments.
+func (p *parser) advance() error {
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		if c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == ',' {
+			p.pos++
+		} else if c == '#' {
+			for p.pos < len(p.src) && p.src[p.pos] != '\n' {
+				p.pos++
+			}
+		} else {
+			break
+		}
+	}
+	start := p.pos
+	if p.pos >= len(p.src) {
+		p.tok = token{kind: tokenEOF, pos: start}
+		return nil
+	}
+	c := p.src[p.pos]
+	switch {
+	case strings.HasPrefix(p.src[p.pos:], "..."):
+		p.pos += 3
+		p.tok = token{kind: tokenPunct, text: "...", pos: start}
+
+	case strings.IndexByte("!$():=@[]{}|", c) >= 0:
+		p.pos++
+		p.tok = token{kind: tokenPunct, text: string(c), pos: start}
+
+	case c == '_' || isLetter(c):
+		for p.pos < len(p.src) && (p.src[p.pos] == '_' || isLetter(p.src[p.pos]) || isDigit(p.src[p.pos])) {
+			p.pos++
+		}
+		p.tok = token{kind: tokenName, text: p.src[start:p.pos], pos: start}
+
+	case c == '-' || isDigit(c):
+		kind := tokenInt
+		if c == '-' {
+			p.pos++
+		}
+		p.skipDigits()
+		if p.pos < len(p.src) && p.src[p.pos] == '.' {
+			kind = tokenFloat
+			p.pos++
+			p.skipDigits()
+		}
+		if p.pos < len(p.src) && (p.src[p.pos] == 'e' || p.src[p.pos] == 'E') {
+			kind = tokenFloat
+			p.pos++
+			if p.pos < len(p.src) && (p.src[p.pos] == '+' || p.src[p.pos] == '-') {
+				p.pos++
+			}
+			p.skipDigits()
+		}
+		p.tok = token{kind: kind, text: p.src[start:p.pos], pos: start}
+
+	case c == '"':
+		p.pos++
+		for p.pos < len(p.src) && p.src[p.pos] != '"' {
+			if p.src[p.pos] == '\\' {
+				p.pos++
+			}
+			if p.pos < len(p.src) && p.src[p.pos] == '\n' {
+				break
+			}
+			p.pos++
+		}
+		if p.pos >= len(p.src) || p.src[p.pos] != '"' {
+			p.tok = token{pos: start}
+			return p.errorf("unterminated string")
+		}
+		p.pos++
+		// GraphQL string escapes are the JSON ones
+		var s string
+		if err := json.Unmarshal([]byte(p.src[start:p.pos]), &s); err != nil {
+			p.tok = token{pos: start}
+			return p.errorf("invalid string %s", p.src[start:p.pos])
+		}
+		p.tok = token{kind: tokenString, text: s, pos: start}
+
+	default:
+		p.tok = token{pos: start}
+		return p.errorf("unexpected character %q", c)
+	}
+	return nil
+}
+
+// nest enters a nested selection set, value or type, the returned function
+// leaves it.
+func (p *parser) nest() (func(), error) {
+	if p.depth >= maxParseDepth {
+		return nil, p.errorf("query nested too deeply, at most %d levels", maxParseDepth)
+	}
+	p.depth++
+	return func() { p.depth-- }, nil
+}
+
+func (p *parser) skipDigits() {
+	for p.pos < len(p.src) && isDigit(p.src[p.pos]) {
+		p.pos++
+	}
+}
+
+func isLetter(c byte) bool { return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') }
+func isDigit(c byte) bool  { return c >= '0' && c <= '9' }
+
+func (p *parser) parseOperation() (*operation, error) {
+	op := &operation{kind: p.tok.text}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	if p.tok.kind == tokenName {
+		name, err := p.name()
+		if err != nil {
+			return nil, err
+		}
+		op.name = name
+	}
+	if p.is("(") {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		for !p.is(")") {
+			if err := p.expect("$"); err != nil {
+				return nil, err
+			}
+			name, err := p.name()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect(":"); err != nil {
+				return nil, err
+			}
+			if err := p.parseType(); err != nil {
+				return nil, err
+			}
+			def := &variableDefinition{name: name}
+			if p.is("=") {
+				if err := p.advance(); err != nil {
+					return nil, err
+				}
+				if def.defValue, err = p.parseValue(true); err != nil {
+					return nil, err
+				}
+			}
+			op.variables = append(op.variables, def)
+		}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := p.parseDirectives(); err != nil {
+		return nil, err
+	}
+	sel, err := p.parseSelectionSet()
+	if err != nil {
+		return nil, err
+	}
+	op.selection = sel
+	return op, nil
+}
+
+// parseType skips a variable type, values are coerced by the resolvers.
+func (p *parser) parseType() error {
+	if p.is("[") {
+		leave, err := p.nest()
+		if err != nil {
+			return err
+		}
+		defer leave()
+		if err := p.advance(); err != nil {
+			return err
+		}
+		if err := p.parseType(); err != nil {
+			return err
+		}
+		if err := p.expect("]"); err != nil {
+			return err
+		}
+	} else if _, err := p.name(); err != nil {
+		return err
+	}
+	if p.is("!") {
+		return p.advance()
+	}
+	return nil
+}
+
+func (p *parser) parseFragment() (*fragment, error) {
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	name, err := p.name()
+	if err != nil {
+		return nil, err
+	}
+	if name == "on" {
+		return nil, p.errorf("invalid fragment name %q", name)
+	}
+	if err := p.expect("on"); err != nil {
+		return nil, err
+	}
+	typeCond, err := p.name()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := p.parseDirectives(); err != nil {
+		return nil, err
+	}
+	sel, err := p.parseSelectionSet()
+	if err != nil {
+		return nil, err
+	}
+	return &fragment{name: name, typeCond: typeCond, selection: sel}, nil
+}
+
+func (p *parser) parseSelectionSet() ([]selection, error) {
+	leave, err := p.nest()
+	if err != nil {
+		return nil, err
+	}
+	defer leave()
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+	var set []selection
+	for !p.is("}") {
+		if p.tok.kind == tokenEOF {
+			return nil, p.errorf("unterminated selection set")
+		}
+		sel, err := p.parseSelection()
+		if err != nil {
+			return nil, err
+		}
+		set = append(set, sel)
+	}
+	if len(set) == 0 {
+		return nil, p.errorf("empty selection set")
+	}
+	return set, p.advance()
+}
+
+func (p *parser) parseSelection() (selection, error) {
+	if !p.is("...") {
+		return p.parseField()
+	}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	if p.tok.kind == tokenName && p.tok.text != "on" {
+		spread := &fragmentSpread{name: p.tok.text}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		var err error
+		spread.directives, err = p.parseDirectives()
+		return spread, err
+	}
+	inline := new(inlineFragment)
+	if p.is("on") {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		typeCond, err := p.name()
+		if err != nil {
+			return nil, err
+		}
+		inline.typeCond = typeCond
+	}
+	var err error
+	if inline.directives, err = p.parseDirectives(); err != nil {
+		return nil, err
+	}
+	inline.selection, err = p.parseSelectionSet()
+	return inline, err
+}
+
+func (p *parser) parseField() (*field, error) {
+	name, err := p.name()
+	if err != nil {
+		return nil, err
+	}
+	f := &field{name: name}
+	if p.is(":") {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		f.alias = name
+		if f.name, err = p.name(); err != nil {
+			return nil, err
+		}
+	}
+	if f.arguments, err = p.parseArguments(); err != nil {
+		return nil, err
+	}
+	if f.directives, err = p.parseDirectives(); err != nil {
+		return nil, err
+	}
+	if p.is("{") {
+		if f.selection, err = p.parseSelectionSet(); err != nil {
+			return nil, err
+		}
+	}
+	return f, nil
+}
+
+func (p *parser) parseArguments() ([]*argument, error) {
+	if !p.is("(") {
+		return nil, nil
+	}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	var args []*argument
+	for !p.is(")") {
+		name, err := p.name()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(":"); err != nil {
+			return nil, err
+		}
+		value, err := p.parseValue(false)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, &argument{name: name, value: value})
+	}
+	return args, p.advance()
+}
+
+func (p *parser) parseDirectives() ([]*directive, error) {
+	var directives []*directive
+	for p.is("@") {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		name, err := p.name()
+		if err != nil {
+			return nil, err
+		}
+		args, err := p.parseArguments()
+		if err != nil {
+			return nil, err
+		}
+		directives = append(directives, &directive{name: name, arguments: args})
+	}
+	return directives, nil
+}
+
+// parseValue parses a literal value, variables are not allowed in constant
+// values such as variable defaults.
+func (p *parser) parseValue(constant bool) (interface{}, error) {
+	tok := p.tok
+	if p.is("[") || p.is("{") {
+		leave, err := p.nest()
+		if err != nil {
+			return nil, err
+		}
+		defer leave()
+	}
+	switch {
+	case p.is("$"):
+		if constant {
+			return nil, p.errorf("unexpected variable in constant value")
+		}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		name, err := p.name()
+		return variable(name), err
+
+	case p.is("["):
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		list := []interface{}{}
+		for !p.is("]") {
+			if p.tok.kind == tokenEOF {
+				return nil, p.errorf("unterminated list")
+			}
+			value, err := p.parseValue(constant)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, value)
+		}
+		return list, p.advance()
+
+	case p.is("{"):
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		object := make(map[string]interface{})
+		for !p.is("}") {
+			name, err := p.name()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect(":"); err != nil {
+				return nil, err
+			}
+			if object[name], err = p.parseValue(constant); err != nil {
+				return nil, err
+			}
+		}
+		return object, p.advance()
+
+	case tok.kind == tokenInt:
+		n, err := strconv.ParseInt(tok.text, 10, 64)
+		if err != nil {
+			return nil, p.errorf("invalid int %s", tok.text)
+		}
+		return n, p.advance()
+
+	case tok.kind == tokenFloat:
+		f, err := strconv.ParseFloat(tok.text, 64)
+		if err != nil {
+			return nil, p.errorf("invalid float %s", tok.text)
+		}
+		return f, p.advance()
+
+	case tok.kind == tokenString:
+		return tok.text, p.advance()
+
+	case tok.kind == tokenName:
+		var value interface{}
+		switch tok.text {
+		case "true":
+			value = true
+		case "false":
+			value = false
+		case "null":
+			value = nil
+		default:
+			value = enumValue(tok.text)
+		}
+		return value, p.advance()
+	}
+	if tok.kind == tokenEOF {
+		return nil, p.errorf("expected value, found end of query")
+	}
+	return nil, p.errorf("expected value, found %q", tok.text)
+}
@@ -0,0 +1,161 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package graphql
+
+import (
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// validQueries cover every construct of the parser, they are truncated and
+// mutated into malformed queries.
+var validQueries = []string{
+	`{ block { number hash } }`,
+	`query Q($n: Long! = 1, $h: [Bytes32!]) @dir(a: 1) { a: block(number: $n) @include(if: true) { ...f ... on Block { number } } } fragment f on Block { parent { number } }`,
+	`{ logs(filter: {fromBlock: 0, toBlock: -1, addresses: ["0xaa"], topics: [[null], []]}) { data } }`,
+	`{ block(number: 1.5e3) { number } } # comment`,
+	`{ block(hash: "\"A\n") { number } }`,
+}
+
+func TestParseMalformed(t *testing.T) {
+	tests := []string{
+		``,
+		`   # only a comment`,
+		`{`,
+		`}`,
+		`{ }`,
+		`{ block { } }`,
+		`{ block(number: ) { number } }`,
+		`{ block(number 1) { number } }`,
+		`{ block(number: 1 { number } }`,
+		`{ block(number: $) { number } }`,
+		`{ block(number: -) { number } }`,
+		`{ block(number: 1e) { number } }`,
+		`{ block(number: 99999999999999999999) { number } }`,
+		`{ block(hash: "abc) { number } }`,
+		"{ block(hash: \"a\nb\") { number } }",
+		`{ block(hash: "\x") { number } }`,
+		`{ block(hash: "\`,
+		`{ block(filter: [1, 2) { number } }`,
+		`{ block(filter: {a 1}) { number } }`,
+		`{ block(filter: {1: 1}) { number } }`,
+		`{ block @ { number } }`,
+		`{ ... }`,
+		`{ ... on { number } }`,
+		`query ($n Long) { block { number } }`,
+		`query ($n: [Long) { block { number } }`,
+		`query ($n: Long = $m) { block { number } }`,
+		`query ($n: Long`,
+		`fragment on on Block { number }`,
+		`fragment f Block { number }`,
+		`fragment f on Block { number } fragment f on Block { hash }`,
+		`fragment f on Block { number }`,
+		`{ block { number } } }`,
+		`{ block { number } } garbage`,
+		`{ block { number } } ?`,
+		"{ block { number \x00 } }",
+		`{ bl\ock { number } }`,
+	}
+	for i, query := range tests {
+		if doc, err := parse(query); err == nil {
+			t.Errorf("test %d: parsed malformed query %q: %+v", i, query, doc)
+		}
+	}
+}
+
+func TestParseNesting(t *testing.T) {
+	tests := []string{
+		strings.Repeat("{ a ", maxParseDepth+1) + strings.Repeat("}", maxParseDepth+1),
+		`{ a(v: ` + strings.Repeat("[", maxParseDepth+1) + strings.Repeat("]", maxParseDepth+1) + `) }`,
+		`{ a(v: ` + strings.Repeat("{b: ", maxParseDepth+1) + `1` + strings.Repeat("}", maxParseDepth+1) + `) }`,
+		`query ($v: ` + strings.Repeat("[", maxParseDepth+1) + `Long` + strings.Repeat("]", maxParseDepth+1) + `) { a }`,
+		// unterminated, as large as a request may be
+		strings.Repeat("{ a ", maxRequestContentLength/4),
+		`{ a(v: ` + strings.Repeat("[", maxRequestContentLength),
+	}
+	for i, query := range tests {
+		_, err := parse(query)
+		if err == nil || !strings.Contains(err.Error(), "nested too deeply") {
+			t.Errorf("test %d: error mismatch: have %v, want nesting error", i, err)
+		}
+	}
+	// the limit itself is accepted
+	query := `{ a(v: ` + strings.Repeat("[", maxParseDepth-1) + strings.Repeat("]", maxParseDepth-1) + `) }`
+	if _, err := parse(query); err != nil {
+		t.Errorf("failed to parse a value nested up to the limit: %v", err)
+	}
+}
+
+// TestParseTruncatedAndMutated feeds every prefix of the valid queries and
+// random mutations of them to the parser, which must fail or succeed without
+// panicking.
+func TestParseTruncatedAndMutated(t *testing.T) {
+	for _, query := range validQueries {
+		if _, err := parse(query); err != nil {
+			t.Fatalf("failed to parse %q: %v", query, err)
+		}
+		for i := 0; i < len(query); i++ {
+			parse(query[:i])
+		}
+	}
+	rnd := rand.New(rand.NewSource(1))
+	alphabet := []byte("{}[]()!$:=@|.\"\\#,- \n\t\x00\xffaz09eE")
+	for i := 0; i < 20000; i++ {
+		query := []byte(validQueries[rnd.Intn(len(validQueries))])
+		for n := rnd.Intn(4) + 1; n > 0; n-- {
+			pos := rnd.Intn(len(query))
+			switch rnd.Intn(3) {
+			case 0: // replace
+				query[pos] = alphabet[rnd.Intn(len(alphabet))]
+			case 1: // delete
+				query = append(query[:pos], query[pos+1:]...)
+			case 2: // insert
+				query = append(query[:pos], append([]byte{alphabet[rnd.Intn(len(alphabet))]}, query[pos:]...)...)
+			}
+		}
+		parse(string(query))
+	}
+}
+
+func TestHTTPHandlerOversized(t *testing.T) {
+	server := httptest.NewServer(New(newTestBackend()))
+	defer server.Close()
+
+	query := "{ block { number " + strings.Repeat(" ", maxRequestContentLength) + "} }"
+	resp, err := http.Get(server.URL + "?query=" + url.QueryEscape(query))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusRequestEntityTooLarge {
+		t.Errorf("oversized GET: have status %s, want 413", resp.Status)
+	}
+
+	body := `{"query":"` + query + `"}`
+	resp, err = http.Post(server.URL, contentType, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusRequestEntityTooLarge {
+		t.Errorf("oversized POST: have status %s, want 413", resp.Status)
+	}
+}
@@ -0,0 +1,762 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package graphql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"bitbucket.org/cpchain/chain/api/rpc"
+	"bitbucket.org/cpchain/chain/core/rawdb"
+	"bitbucket.org/cpchain/chain/internal/cpcapi"
+	"bitbucket.org/cpchain/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+const (
+	// maxBlockRange is the maximum number of blocks a single logs field may
+	// scan.
+	maxBlockRange = 1000
+
+	// maxBlocksRange is the maximum number of blocks a single blocks field
+	// returns, each of them may be expanded by the query.
+	maxBlocksRange = 100
+)
+
+var (
+	errNoState = errors.New("state not available")
+)
+
+// queryResolver resolves the root Query type.
+type queryResolver struct {
+	b cpcapi.Backend
+}
+
+func (q *queryResolver) typeName() string { return "Query" }
+
+func (q *queryResolver) resolve(ctx context.Context, name string, args arguments) (interface{}, error) {
+	switch name {
+	case "block":
+		if hash, ok, err := args.hash("hash"); err != nil {
+			return nil, err
+		} else if ok {
+			block, err := q.b.GetBlock(ctx, hash)
+			return newBlock(q.b, block), err
+		}
+		number, err := blockNumberArg(args, "number")
+		if err != nil {
+			return nil, err
+		}
+		block, err := q.b.BlockByNumber(ctx, number)
+		return newBlock(q.b, block), err
+
+	case "blocks":
+		from, ok, err := args.long("from")
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, errors.New("missing argument \"from\"")
+		}
+		to, err := q.rangeEnd(ctx, args, "to", from, maxBlocksRange)
+		if err != nil {
+			return nil, err
+		}
+		blocks := []object{}
+		for n := from; n <= to; n++ {
+			block, err := q.b.BlockByNumber(ctx, rpc.BlockNumber(n))
+			if err != nil {
+				return nil, err
+			}
+			if block == nil {
+				break
+			}
+			blocks = append(blocks, newBlock(q.b, block))
+		}
+		return blocks, nil
+
+	case "transaction":
+		hash, ok, err := args.hash("hash")
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, errors.New("missing argument \"hash\"")
+		}
+		return q.transaction(ctx, hash)
+
+	case "logs":
+		criteria, ok, err := args.object("filter")
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, errors.New("missing argument \"filter\"")
+		}
+		filter, err := newLogFilter(criteria)
+		if err != nil {
+			return nil, err
+		}
+		head, err := q.head(ctx)
+		if err != nil {
+			return nil, err
+		}
+		from, ok, err := criteria.long("fromBlock")
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			from = head
+		}
+		to, err := q.rangeEnd(ctx, criteria, "toBlock", from, maxBlockRange)
+		if err != nil {
+			return nil, err
+		}
+		logs := []object{}
+		for n := from; n <= to; n++ {
+			block, err := q.b.BlockByNumber(ctx, rpc.BlockNumber(n))
+			if err != nil {
+				return nil, err
+			}
+			if block == nil {
+				break
+			}
+			matched, err := (&blockResolver{b: q.b, block: block}).logs(ctx, filter)
+			if err != nil {
+				return nil, err
+			}
+			logs = append(logs, matched...)
+		}
+		return logs, nil
+
+	case "account":
+		address, ok, err := args.address("address")
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, errors.New("missing argument \"address\"")
+		}
+		number, err := blockNumberArg(args, "blockNumber")
+		if err != nil {
+			return nil, err
+		}
+		return &accountResolver{b: q.b, address: address, number: number}, nil
+
+	case "pending":
+		return &pendingResolver{b: q.b}, nil
+
+	case "committee":
+		number, ok, err := args.long("blockNumber")
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			if number, err = q.head(ctx); err != nil {
+				return nil, err
+			}
+		}
+		return &committeeResolver{b: q.b, number: number}, nil
+
+	case "gasPrice":
+		price, err := q.b.SuggestPrice(ctx)
+		return (*hexutil.Big)(price), err
+
+	case "protocolVersion":
+		return q.b.ProtocolVersion(), nil
+	}
+	return nil, errUnknownField
+}
+
+// head returns the number of the chain head.
+func (q *queryResolver) head(ctx context.Context) (uint64, error) {
+	header, err := q.b.HeaderByNumber(ctx, rpc.LatestBlockNumber)
+	if err != nil {
+		return 0, err
+	}
+	return header.Number.Uint64(), nil
+}
+
+// rangeEnd returns the end of a block range of at most limit blocks, the chain
+// head if not given.
+func (q *queryResolver) rangeEnd(ctx context.Context, args arguments, name string, from uint64, limit uint64) (uint64, error) {
+	to, ok, err := args.long(name)
+	if err != nil {
+		return 0, err
+	}
+	if !ok {
+		if to, err = q.head(ctx); err != nil {
+			return 0, err
+		}
+	}
+	if to >= from && to-from >= limit {
+		return 0, fmt.Errorf("block range too large, at most %d blocks", limit)
+	}
+	return to, nil
+}
+
+// transaction looks a transaction up in the chain, then in the pool.
+func (q *queryResolver) transaction(ctx context.Context, hash common.Hash) (interface{}, error) {
+	tx, blockHash, _, index := rawdb.ReadTransaction(q.b.ChainDb(), hash)
+	if tx == nil {
+		if tx = q.b.GetPoolTransaction(hash); tx == nil {
+			return nil, nil
+		}
+		return &transactionResolver{b: q.b, tx: tx}, nil
+	}
+	block, err := q.b.GetBlock(ctx, blockHash)
+	if block == nil || err != nil {
+		return nil, err
+	}
+	return &transactionResolver{b: q.b, tx: tx, block: &blockResolver{b: q.b, block: block}, index: index}, nil
+}
+
+// blockNumberArg returns a block number argument, the latest block if not given.
+func blockNumberArg(args arguments, name string) (rpc.BlockNumber, error) {
+	number, ok, err := args.long(name)
+	if err != nil || !ok {
+		return rpc.LatestBlockNumber, err
+	}
+	return rpc.BlockNumber(number), nil
+}
+
+// blockResolver resolves the Block type.
+type blockResolver struct {
+	b        cpcapi.Backend
+	block    *types.Block
+	receipts types.Receipts
+}
+
+// newBlock returns a resolver of the block, or an untyped nil.
+func newBlock(b cpcapi.Backend, block *types.Block) object {
+	if block == nil {
+		return nil
+	}
+	return &blockResolver{b: b, block: block}
+}
+
+func (r *blockResolver) typeName() string { return "Block" }
+
+func (r *blockResolver) resolve(ctx context.Context, name string, args arguments) (interface{}, error) {
+	header := r.block.RefHeader()
+	switch name {
+	case "number":
+		return r.block.NumberU64(), nil
+	case "hash":
+		return r.block.Hash(), nil
+	case "parent":
+		if r.block.NumberU64() == 0 {
+			return nil, nil
+		}
+		parent, err := r.b.GetBlock(ctx, r.block.ParentHash())
+		return newBlock(r.b, parent), err
+	case "timestamp":
+		return (*hexutil.Big)(r.block.Time()), nil
+	case "gasLimit":
+		return r.block.GasLimit(), nil
+	case "gasUsed":
+		return r.block.GasUsed(), nil
+	case "miner":
+		return &accountResolver{b: r.b, address: r.block.Coinbase(), number: rpc.BlockNumber(r.block.NumberU64())}, nil
+	case "extraData":
+		return hexutil.Bytes(r.block.Extra()), nil
+	case "stateRoot":
+		return r.block.StateRoot(), nil
+	case "transactionsRoot":
+		return r.block.TxsRoot(), nil
+	case "receiptsRoot":
+		return r.block.ReceiptsRoot(), nil
+	case "logsBloom":
+		return hexutil.Bytes(header.LogsBloom.Bytes()), nil
+	case "transactionCount":
+		return r.block.Transactions().Len(), nil
+
+	case "transactions":
+		txs := r.block.Transactions()
+		list := make([]object, len(txs))
+		for i, tx := range txs {
+			list[i] = &transactionResolver{b: r.b, tx: tx, block: r, index: uint64(i)}
+		}
+		return list, nil
+
+	case "transactionAt":
+		index, ok, err := args.long("index")
+		if err != nil {
+			return nil, err
+		}
+		txs := r.block.Transactions()
+		if !ok || index >= uint64(len(txs)) {
+			return nil, nil
+		}
+		return &transactionResolver{b: r.b, tx: txs[index], block: r, index: index}, nil
+
+	case "logs":
+		criteria, _, err := args.object("filter")
+		if err != nil {
+			return nil, err
+		}
+		filter, err := newLogFilter(criteria)
+		if err != nil {
+			return nil, err
+		}
+		return r.logs(ctx, filter)
+
+	case "account":
+		address, ok, err := args.address("address")
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, errors.New("missing argument \"address\"")
+		}
+		return &accountResolver{b: r.b, address: address, number: rpc.BlockNumber(r.block.NumberU64())}, nil
+
+	case "proposers":
+		return addressList(header.Dpor.Proposers), nil
+	case "validators":
+		return addressList(header.Dpor.Validators), nil
+	case "seal":
+		return hexutil.Bytes(header.Dpor.Seal[:]), nil
+	case "impeachment":
+		return header.Impeachment(), nil
+	case "committee":
+		return &committeeResolver{b: r.b, number: r.block.NumberU64()}, nil
+	}
+	return nil, errUnknownField
+}
+
+// getReceipts returns the receipts of the public transactions of the block.
+func (r *blockResolver) getReceipts(ctx context.Context) (types.Receipts, error) {
+	if r.receipts == nil {
+		receipts, err := r.b.GetReceipts(ctx, r.block.Hash())
+		if err != nil {
+			return nil, err
+		}
+		r.receipts = receipts
+	}
+	return r.receipts, nil
+}
+
+// logs returns the logs of the block matching the filter.
+func (r *blockResolver) logs(ctx context.Context, filter *logFilter) ([]object, error) {
+	logs := []object{}
+	if filter != nil && !filter.bloomMatch(r.block.LogsBloom()) {
+		return logs, nil
+	}
+	receipts, err := r.getReceipts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i, receipt := range receipts {
+		if i >= r.block.Transactions().Len() {
+			break
+		}
+		tx := &transactionResolver{b: r.b, tx: r.block.Transactions()[i], block: r, index: uint64(i)}
+		for _, log := range receipt.Logs {
+			if filter == nil || filter.match(log) {
+				logs = append(logs, &logResolver{b: r.b, tx: tx, log: log})
+			}
+		}
+	}
+	return logs, nil
+}
+
+// transactionResolver resolves the Transaction type, block is nil for pending
+// transactions.
+type transactionResolver struct {
+	b     cpcapi.Backend
+	tx    *types.Transaction
+	block *blockResolver
+	index uint64
+}
+
+func (r *transactionResolver) typeName() string { return "Transaction" }
+
+func (r *transactionResolver) resolve(ctx context.Context, name string, args arguments) (interface{}, error) {
+	switch name {
+	case "hash":
+		return r.tx.Hash(), nil
+	case "nonce":
+		return r.tx.Nonce(), nil
+	case "index":
+		if r.block == nil {
+			return nil, nil
+		}
+		return r.index, nil
+	case "from":
+		var signer types.Signer = types.FrontierSigner{}
+		if r.tx.Protected() {
+			signer = types.NewCep1Signer(r.tx.ChainId())
+		}
+		from, err := types.Sender(signer, r.tx)
+		if err != nil {
+			return nil, err
+		}
+		return &accountResolver{b: r.b, address: from, number: r.blockNumber()}, nil
+	case "to":
+		if r.tx.To() == nil {
+			return nil, nil
+		}
+		return &accountResolver{b: r.b, address: *r.tx.To(), number: r.blockNumber()}, nil
+	case "value":
+		return (*hexutil.Big)(r.tx.Value()), nil
+	case "gasPrice":
+		return (*hexutil.Big)(r.tx.GasPrice()), nil
+	case "gas":
+		return r.tx.Gas(), nil
+	case "inputData":
+		return hexutil.Bytes(r.tx.Data()), nil
+	case "isPrivate":
+		return r.tx.IsPrivate(), nil
+	case "block":
+		if r.block == nil {
+			return nil, nil
+		}
+		return r.block, nil
+	case "v", "r", "s":
+		v, rr, s := r.tx.RawSignatureValues()
+		values := map[string]*big.Int{"v": v, "r": rr, "s": s}
+		return (*hexutil.Big)(values[name]), nil
+	}
+
+	// the remaining fields come from the receipt
+	switch name {
+	case "status", "gasUsed", "cumulativeGasUsed", "createdContract", "logs":
+	default:
+		return nil, errUnknownField
+	}
+	receipt, err := r.receipt(ctx)
+	if receipt == nil || err != nil {
+		return nil, err
+	}
+	switch name {
+	case "status":
+		return receipt.Status, nil
+	case "gasUsed":
+		return receipt.GasUsed, nil
+	case "cumulativeGasUsed":
+		return receipt.CumulativeGasUsed, nil
+	case "createdContract":
+		if receipt.ContractAddress == (common.Address{}) {
+			return nil, nil
+		}
+		return &accountResolver{b: r.b, address: receipt.ContractAddress, number: r.blockNumber()}, nil
+	default:
+		logs := make([]object, len(receipt.Logs))
+		for i, log := range receipt.Logs {
+			logs[i] = &logResolver{b: r.b, tx: r, log: log}
+		}
+		return logs, nil
+	}
+}
+
+// blockNumber returns the block the state of the transaction accounts is read
+// at, the pending block for pending transactions.
+func (r *transactionResolver) blockNumber() rpc.BlockNumber {
+	if r.block == nil {
+		return rpc.PendingBlockNumber
+	}
+	return rpc.BlockNumber(r.block.block.NumberU64())
+}
+
+// receipt returns the receipt of a mined transaction.
+func (r *transactionResolver) receipt(ctx context.Context) (*types.Receipt, error) {
+	if r.block == nil {
+		return nil, nil
+	}
+	if r.tx.IsPrivate() {
+		return r.b.GetPrivateReceipt(ctx, r.tx.Hash())
+	}
+	receipts, err := r.block.getReceipts(ctx)
+	if err != nil || uint64(len(receipts)) <= r.index {
+		return nil, err
+	}
+	return receipts[r.index], nil
+}
+
+// logResolver resolves the Log type.
+type logResolver struct {
+	b   cpcapi.Backend
+	tx  *transactionResolver
+	log *types.Log
+}
+
+func (r *logResolver) typeName() string { return "Log" }
+
+func (r *logResolver) resolve(ctx context.Context, name string, args arguments) (interface{}, error) {
+	switch name {
+	case "index":
+		return r.log.Index, nil
+	case "account":
+		return &accountResolver{b: r.b, address: r.log.Address, number: r.tx.blockNumber()}, nil
+	case "topics":
+		topics := make([]interface{}, len(r.log.Topics))
+		for i, topic := range r.log.Topics {
+			topics[i] = topic
+		}
+		return topics, nil
+	case "data":
+		return hexutil.Bytes(r.log.Data), nil
+	case "transaction":
+		return r.tx, nil
+	}
+	return nil, errUnknownField
+}
+
+// accountResolver resolves the Account type at a block.
+type accountResolver struct {
+	b       cpcapi.Backend
+	address common.Address
+	number  rpc.BlockNumber
+}
+
+func (r *accountResolver) typeName() string { return "Account" }
+
+func (r *accountResolver) resolve(ctx context.Context, name string, args arguments) (interface{}, error) {
+	if name == "address" {
+		return r.address, nil
+	}
+	switch name {
+	case "balance", "transactionCount", "code", "storage":
+	default:
+		return nil, errUnknownField
+	}
+	state, _, err := r.b.StateAndHeaderByNumber(ctx, r.number, false)
+	if err != nil {
+		return nil, err
+	}
+	if state == nil {
+		return nil, errNoState
+	}
+	switch name {
+	case "balance":
+		return (*hexutil.Big)(state.GetBalance(r.address)), state.Error()
+	case "transactionCount":
+		return state.GetNonce(r.address), state.Error()
+	case "code":
+		return hexutil.Bytes(state.GetCode(r.address)), state.Error()
+	default:
+		slot, ok, err := args.hash("slot")
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, errors.New("missing argument \"slot\"")
+		}
+		return state.GetState(r.address, slot), state.Error()
+	}
+}
+
+// pendingResolver resolves the Pending type, the state of the transaction pool.
+type pendingResolver struct {
+	b cpcapi.Backend
+}
+
+func (r *pendingResolver) typeName() string { return "Pending" }
+
+func (r *pendingResolver) resolve(ctx context.Context, name string, args arguments) (interface{}, error) {
+	switch name {
+	case "transactionCount":
+		pending, _ := r.b.Stats()
+		return pending, nil
+	case "queuedCount":
+		_, queued := r.b.Stats()
+		return queued, nil
+	case "transactions":
+		txs, err := r.b.GetPoolTransactions()
+		if err != nil {
+			return nil, err
+		}
+		list := make([]object, len(txs))
+		for i, tx := range txs {
+			list[i] = &transactionResolver{b: r.b, tx: tx}
+		}
+		return list, nil
+	case "account":
+		address, ok, err := args.address("address")
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, errors.New("missing argument \"address\"")
+		}
+		return &accountResolver{b: r.b, address: address, number: rpc.PendingBlockNumber}, nil
+	}
+	return nil, errUnknownField
+}
+
+// committeeResolver resolves the Committee type, the Dpor proposers and
+// validators in charge of a block.
+type committeeResolver struct {
+	b      cpcapi.Backend
+	number uint64
+}
+
+func (r *committeeResolver) typeName() string { return "Committee" }
+
+func (r *committeeResolver) resolve(ctx context.Context, name string, args arguments) (interface{}, error) {
+	vl, tl := r.b.ViewLen(), r.b.TermLen()
+	switch name {
+	case "blockNumber":
+		return r.number, nil
+	case "term":
+		if r.number == 0 || vl*tl == 0 {
+			return uint64(0), nil
+		}
+		return (r.number - 1) / (vl * tl), nil
+	case "view":
+		if r.number == 0 || vl*tl == 0 {
+			return uint64(0), nil
+		}
+		return ((r.number - 1) % (vl * tl)) / vl, nil
+	case "proposers":
+		proposers, err := r.b.Proposers(rpc.BlockNumber(r.number))
+		return addressList(proposers), err
+	case "validators":
+		validators, err := r.b.Validators(rpc.BlockNumber(r.number))
+		return addressList(validators), err
+	case "proposer":
+		proposers, err := r.b.Proposers(rpc.BlockNumber(r.number))
+		if err != nil || r.number == 0 || vl*tl == 0 {
+			return nil, err
+		}
+		view := ((r.number - 1) % (vl * tl)) / vl
+		if view >= uint64(len(proposers)) {
+			return nil, nil
+		}
+		return proposers[view], nil
+	}
+	return nil, errUnknownField
+}
+
+// addressList converts addresses to a list scalar, never null.
+func addressList(addrs []common.Address) []interface{} {
+	list := make([]interface{}, len(addrs))
+	for i, addr := range addrs {
+		list[i] = addr
+	}
+	return list
+}
+
+// logFilter is the FilterCriteria input type.
+type logFilter struct {
+	addresses []common.Address
+	topics    [][]common.Hash
+}
+
+// newLogFilter parses filter criteria, nil criteria match every log.
+func newLogFilter(criteria arguments) (*logFilter, error) {
+	if criteria == nil {
+		return nil, nil
+	}
+	filter := new(logFilter)
+	addrs, _ := criteria.list("addresses")
+	for _, v := range addrs {
+		addr, err := toAddress(v)
+		if err != nil {
+			return nil, err
+		}
+		filter.addresses = append(filter.addresses, addr)
+	}
+	topics, _ := criteria.list("topics")
+	for _, v := range topics {
+		var (
+			sub  []common.Hash
+			list []interface{}
+		)
+		switch v := v.(type) {
+		case nil:
+		case []interface{}:
+			list = v
+		default:
+			list = []interface{}{v}
+		}
+		for _, item := range list {
+			topic, err := toHash(item)
+			if err != nil {
+				return nil, err
+			}
+			sub = append(sub, topic)
+		}
+		filter.topics = append(filter.topics, sub)
+	}
+	return filter, nil
+}
+
+// bloomMatch reports whether a block may contain matching logs.
+func (f *logFilter) bloomMatch(bloom types.Bloom) bool {
+	if len(f.addresses) > 0 {
+		included := false
+		for _, addr := range f.addresses {
+			if types.BloomLookup(bloom, addr) {
+				included = true
+				break
+			}
+		}
+		if !included {
+			return false
+		}
+	}
+	for _, sub := range f.topics {
+		included := len(sub) == 0
+		for _, topic := range sub {
+			if types.BloomLookup(bloom, topic) {
+				included = true
+				break
+			}
+		}
+		if !included {
+			return false
+		}
+	}
+	return true
+}
+
+// match reports whether a log matches the filter.
+func (f *logFilter) match(log *types.Log) bool {
+	if len(f.addresses) > 0 {
+		included := false
+		for _, addr := range f.addresses {
+			if addr == log.Address {
+				included = true
+				break
+			}
+		}
+		if !included {
+			return false
+		}
+	}
+	if len(f.topics) > len(log.Topics) {
+		return false
+	}
+	for i, sub := range f.topics {
+		included := len(sub) == 0
+		for _, topic := range sub {
+			if log.Topics[i] == topic {
+				included = true
+				break
+			}
+		}
+		if !included {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,146 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package graphql
+
+// schema documents the types served by the resolvers.
+const schema = `
+# Bytes32 is a 32 byte hex string, such as a hash.
+scalar Bytes32
+# Address is a 20 byte hex account address.
+scalar Address
+# Bytes is an arbitrary length hex string.
+scalar Bytes
+# BigInt is a hex encoded unsigned integer of any size.
+scalar BigInt
+# Long is an unsigned 64 bit integer. Arguments may also be decimal or hex strings.
+scalar Long
+
+type Query {
+    # block returns a block by hash or number, the latest block if neither is given.
+    block(number: Long, hash: Bytes32): Block
+    # blocks returns the blocks in [from, to], to defaults to the latest block.
+    # At most 1000 blocks are returned.
+    blocks(from: Long!, to: Long): [Block!]!
+    # transaction returns a mined or pending transaction.
+    transaction(hash: Bytes32!): Transaction
+    # logs returns the logs matching the filter. At most 1000 blocks are scanned.
+    logs(filter: FilterCriteria!): [Log!]!
+    # account returns an account at a block, the latest block if not given.
+    account(address: Address!, blockNumber: Long): Account!
+    # pending returns the state of the transaction pool.
+    pending: Pending!
+    # committee returns the Dpor committee of a block, the latest block if not given.
+    committee(blockNumber: Long): Committee!
+    gasPrice: BigInt!
+    protocolVersion: Int!
+}
+
+type Block {
+    number: Long!
+    hash: Bytes32!
+    parent: Block
+    # timestamp is in milliseconds.
+    timestamp: BigInt!
+    gasLimit: Long!
+    gasUsed: Long!
+    miner: Account!
+    extraData: Bytes!
+    stateRoot: Bytes32!
+    transactionsRoot: Bytes32!
+    receiptsRoot: Bytes32!
+    logsBloom: Bytes!
+    transactionCount: Int!
+    transactions: [Transaction!]!
+    transactionAt(index: Int!): Transaction
+    logs(filter: BlockFilterCriteria): [Log!]!
+    account(address: Address!): Account!
+    # proposers and validators are the Dpor committees recorded in the header.
+    proposers: [Address!]!
+    validators: [Address!]!
+    seal: Bytes!
+    impeachment: Boolean!
+    committee: Committee!
+}
+
+type Transaction {
+    hash: Bytes32!
+    nonce: Long!
+    # index, block and the receipt fields are null for pending transactions.
+    index: Long
+    from: Account!
+    to: Account
+    value: BigInt!
+    gasPrice: BigInt!
+    gas: Long!
+    inputData: Bytes!
+    isPrivate: Boolean!
+    block: Block
+    status: Long
+    gasUsed: Long
+    cumulativeGasUsed: Long
+    createdContract: Account
+    logs: [Log!]
+    v: BigInt!
+    r: BigInt!
+    s: BigInt!
+}
+
+type Log {
+    index: Int!
+    account: Account!
+    topics: [Bytes32!]!
+    data: Bytes!
+    transaction: Transaction!
+}
+
+type Account {
+    address: Address!
+    balance: BigInt!
+    transactionCount: Long!
+    code: Bytes!
+    storage(slot: Bytes32!): Bytes32!
+}
+
+type Pending {
+    transactionCount: Int!
+    queuedCount: Int!
+    transactions: [Transaction!]!
+    account(address: Address!): Account!
+}
+
+type Committee {
+    blockNumber: Long!
+    term: Long!
+    view: Long!
+    proposers: [Address!]!
+    validators: [Address!]!
+    # proposer is the proposer in charge of the view of the block.
+    proposer: Address
+}
+
+input FilterCriteria {
+    fromBlock: Long
+    toBlock: Long
+    addresses: [Address!]
+    topics: [[Bytes32!]]
+}
+
+input BlockFilterCriteria {
+    addresses: [Address!]
+    topics: [[Bytes32!]]
+}
+`
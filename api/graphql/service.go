@@ -0,0 +1,112 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package graphql serves the chain data of internal/cpcapi over GraphQL, so
+// that a block with its transactions, receipts, logs and Dpor committee can be
+// fetched with a single query.
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	"bitbucket.org/cpchain/chain/commons/log"
+	"bitbucket.org/cpchain/chain/internal/cpcapi"
+)
+
+const (
+	maxRequestContentLength = 1024 * 128
+	contentType             = "application/json"
+)
+
+// Handler serves GraphQL queries over HTTP. Queries are posted as JSON
+// requests or sent in the query string of GET requests, and GET /schema
+// returns the schema.
+type Handler struct {
+	root object
+}
+
+// New creates a GraphQL handler reading chain data from the backend.
+func New(backend cpcapi.Backend) *Handler {
+	return &Handler{root: &queryResolver{b: backend}}
+}
+
+// Execute runs a query request.
+func (h *Handler) Execute(ctx context.Context, req *Request) *Response {
+	return execute(ctx, h.root, req)
+}
+
+// ServeHTTP implements http.Handler.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req Request
+	switch r.Method {
+	case http.MethodGet:
+		if strings.HasSuffix(r.URL.Path, "/schema") {
+			w.Header().Set("content-type", "text/plain")
+			io.WriteString(w, schema)
+			return
+		}
+		query := r.URL.Query()
+		if len(query.Get("query")) > maxRequestContentLength {
+			http.Error(w, "query too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+		req.Query = query.Get("query")
+		req.OperationName = query.Get("operationName")
+		if vars := query.Get("variables"); vars != "" {
+			if err := decodeJSON(strings.NewReader(vars), &req.Variables); err != nil {
+				http.Error(w, "invalid variables: "+err.Error(), http.StatusBadRequest)
+				return
+			}
+		}
+
+	case http.MethodPost:
+		if r.ContentLength > maxRequestContentLength {
+			http.Error(w, "content length too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+		body := io.LimitReader(r.Body, maxRequestContentLength)
+		if err := decodeJSON(body, &req); err != nil {
+			http.Error(w, "invalid request: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if req.Query == "" {
+		http.Error(w, "missing query", http.StatusBadRequest)
+		return
+	}
+	resp := h.Execute(r.Context(), &req)
+
+	w.Header().Set("content-type", contentType)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		log.Debug("Failed to write GraphQL response", "err", err)
+	}
+}
+
+// decodeJSON decodes numbers as json.Number so that large integers are not
+// rounded.
+func decodeJSON(r io.Reader, v interface{}) error {
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+	return dec.Decode(v)
+}
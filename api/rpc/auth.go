@@ -0,0 +1,410 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"bitbucket.org/cpchain/chain/commons/log"
+	jwt "github.com/dgrijalva/jwt-go"
+)
+
+// Role is the access level granted to a RPC client. Roles are ordered, a higher
+// role is granted everything a lower one is.
+type Role int
+
+const (
+	// RoleNone grants nothing, it is the role of unauthenticated clients unless
+	// AuthConfig.AnonymousRole says otherwise.
+	RoleNone Role = iota
+	// RoleReadOnly grants methods which do not change the node or the chain.
+	RoleReadOnly
+	// RoleSigner grants methods which sign or send transactions.
+	RoleSigner
+	// RoleAdmin grants everything, including node and account management.
+	RoleAdmin
+)
+
+var roleNames = map[Role]string{
+	RoleNone:     "none",
+	RoleReadOnly: "readonly",
+	RoleSigner:   "signer",
+	RoleAdmin:    "admin",
+}
+
+// String implements fmt.Stringer.
+func (r Role) String() string {
+	if name, ok := roleNames[r]; ok {
+		return name
+	}
+	return fmt.Sprintf("role(%d)", int(r))
+}
+
+// ParseRole converts a role name to a Role.
+func ParseRole(name string) (Role, error) {
+	for role, n := range roleNames {
+		if strings.EqualFold(n, name) {
+			return role, nil
+		}
+	}
+	return RoleNone, fmt.Errorf("unknown rpc role %q", name)
+}
+
+// DefaultMethodRoles are the roles required by methods if they are not
+// overridden by AuthConfig.MethodRoles. The read only namespaces are listed
+// explicitly, methods of any other namespace require RoleAdmin.
+var DefaultMethodRoles = map[string]string{
+	"cpc_*":     "readonly",
+	"eth_*":     "readonly",
+	"net_*":     "readonly",
+	"web3_*":    "readonly",
+	"txpool_*":  "readonly",
+	"rpc_*":     "readonly",
+	"graphql_*": "readonly",
+	"dpor_*":    "readonly",
+	"reward_*":  "readonly",
+
+	"admin_*":                  "admin",
+	"admission_*":              "admin",
+	"debug_*":                  "admin",
+	"miner_*":                  "admin",
+	"personal_*":               "admin",
+	"personal_sign":            "signer",
+	"personal_sendTransaction": "signer",
+	"personal_signTransaction": "signer",
+	"eth_sendTransaction":      "signer",
+	"eth_sendRawTransaction":   "signer",
+	"eth_sign":                 "signer",
+	"eth_signTransaction":      "signer",
+	"cpc_sendTransaction":      "signer",
+	"cpc_sendRawTransaction":   "signer",
+	"cpc_sign":                 "signer",
+	"cpc_signTransaction":      "signer",
+}
+
+// APIKey is a static credential granting a role to the client presenting it in
+// the X-API-Key header.
+type APIKey struct {
+	Name string // name of the client, used in the audit log
+	Key  string
+	Role string
+}
+
+// AuthConfig configures authentication and per method access control of the
+// HTTP and websocket RPC endpoints.
+type AuthConfig struct {
+	// JWTSecret is the HMAC secret of the HS256 JWT bearer tokens. The "sub"
+	// claim identifies the client and the "role" claim carries its role.
+	JWTSecret string `toml:",omitempty"`
+
+	// APIKeys are the accepted static API keys.
+	APIKeys []APIKey `toml:",omitempty"`
+
+	// AnonymousRole is the role of clients without credentials. If empty,
+	// requests without credentials are rejected.
+	AnonymousRole string `toml:",omitempty"`
+
+	// MethodRoles maps a method ("cpc_getBalance") or a namespace wildcard
+	// ("personal_*") to the role it requires, on top of DefaultMethodRoles.
+	MethodRoles map[string]string `toml:",omitempty"`
+
+	// AuditLog is the file privileged calls are appended to as JSON lines. If
+	// empty, they only go to the node log.
+	AuditLog string `toml:",omitempty"`
+}
+
+var (
+	errMissingCredentials = errors.New("missing credentials")
+	errInvalidCredentials = errors.New("invalid credentials")
+)
+
+// authInfo is the authenticated identity of a RPC client.
+type authInfo struct {
+	Subject string
+	Role    Role
+}
+
+type authInfoKey struct{}
+
+// authInfoFromContext retrieves the identity attached to the context, if any.
+func authInfoFromContext(ctx context.Context) (*authInfo, bool) {
+	info, ok := ctx.Value(authInfoKey{}).(*authInfo)
+	return info, ok
+}
+
+// authPolicy authenticates clients and authorizes their calls.
+type authPolicy struct {
+	jwtSecret   []byte
+	apiKeys     []APIKey
+	apiKeyRoles []Role
+	anonymous   Role
+	methodRoles map[string]Role
+
+	auditLock sync.Mutex
+	audit     *os.File
+}
+
+// newAuthPolicy creates the access policy described by the config, nil config
+// disables access control.
+func newAuthPolicy(config *AuthConfig) (*authPolicy, error) {
+	if config == nil {
+		return nil, nil
+	}
+	policy := &authPolicy{
+		jwtSecret:   []byte(config.JWTSecret),
+		apiKeys:     config.APIKeys,
+		methodRoles: make(map[string]Role),
+	}
+	for _, key := range config.APIKeys {
+		role, err := ParseRole(key.Role)
+		if err != nil {
+			return nil, err
+		}
+		if key.Key == "" {
+			return nil, fmt.Errorf("empty rpc api key for %q", key.Name)
+		}
+		policy.apiKeyRoles = append(policy.apiKeyRoles, role)
+	}
+	if config.AnonymousRole != "" {
+		role, err := ParseRole(config.AnonymousRole)
+		if err != nil {
+			return nil, err
+		}
+		policy.anonymous = role
+	}
+	for _, roles := range []map[string]string{DefaultMethodRoles, config.MethodRoles} {
+		for method, name := range roles {
+			role, err := ParseRole(name)
+			if err != nil {
+				return nil, err
+			}
+			policy.methodRoles[method] = role
+		}
+	}
+	if config.AuditLog != "" {
+		f, err := os.OpenFile(config.AuditLog, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+		if err != nil {
+			return nil, err
+		}
+		policy.audit = f
+	}
+	return policy, nil
+}
+
+// authenticate resolves the identity of the client from the request headers.
+func (p *authPolicy) authenticate(r *http.Request) (*authInfo, error) {
+	if key := r.Header.Get("X-API-Key"); key != "" {
+		for i, k := range p.apiKeys {
+			if subtle.ConstantTimeCompare([]byte(k.Key), []byte(key)) == 1 {
+				return &authInfo{Subject: "apikey:" + k.Name, Role: p.apiKeyRoles[i]}, nil
+			}
+		}
+		return nil, errInvalidCredentials
+	}
+
+	if header := r.Header.Get("Authorization"); header != "" {
+		if !strings.HasPrefix(header, "Bearer ") || len(p.jwtSecret) == 0 {
+			return nil, errInvalidCredentials
+		}
+		return p.authenticateJWT(strings.TrimPrefix(header, "Bearer "))
+	}
+
+	if p.anonymous == RoleNone {
+		return nil, errMissingCredentials
+	}
+	return &authInfo{Subject: "anonymous", Role: p.anonymous}, nil
+}
+
+// authenticateJWT validates a HS256 JWT bearer token.
+func (p *authPolicy) authenticateJWT(token string) (*authInfo, error) {
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(token, claims, func(t *jwt.Token) (interface{}, error) {
+		if t.Method != jwt.SigningMethodHS256 {
+			return nil, fmt.Errorf("unexpected signing method %v", t.Header["alg"])
+		}
+		return p.jwtSecret, nil
+	})
+	if err != nil {
+		return nil, errInvalidCredentials
+	}
+	name, _ := claims["role"].(string)
+	role, err := ParseRole(name)
+	if err != nil {
+		return nil, errInvalidCredentials
+	}
+	sub, _ := claims["sub"].(string)
+	return &authInfo{Subject: "jwt:" + sub, Role: role}, nil
+}
+
+// requiredRole returns the role required to call the given method, exact
+// method names take precedence over namespace wildcards. Methods of unknown
+// namespaces require RoleAdmin, they may change the node.
+func (p *authPolicy) requiredRole(method string) Role {
+	if role, ok := p.methodRoles[method]; ok {
+		return role
+	}
+	if i := strings.Index(method, serviceMethodSeparator); i >= 0 {
+		if role, ok := p.methodRoles[method[:i]+serviceMethodSeparator+"*"]; ok {
+			return role
+		}
+	}
+	return RoleAdmin
+}
+
+// authorize checks that the client may call the method, auditing privileged
+// calls.
+func (p *authPolicy) authorize(ctx context.Context, method string) Error {
+	info, ok := authInfoFromContext(ctx)
+	if !ok {
+		info = &authInfo{Subject: "anonymous", Role: p.anonymous}
+	}
+	required := p.requiredRole(method)
+	allowed := info.Role >= required
+
+	if required > RoleReadOnly || !allowed {
+		p.auditCall(ctx, info, method, required, allowed)
+	}
+	if !allowed {
+		return &unauthorizedError{method: method, role: required}
+	}
+	return nil
+}
+
+// auditEntry is a line of the audit log.
+type auditEntry struct {
+	Time     time.Time `json:"time"`
+	Subject  string    `json:"subject"`
+	Role     string    `json:"role"`
+	Remote   string    `json:"remote,omitempty"`
+	Method   string    `json:"method"`
+	Required string    `json:"required"`
+	Allowed  bool      `json:"allowed"`
+}
+
+func (p *authPolicy) auditCall(ctx context.Context, info *authInfo, method string, required Role, allowed bool) {
+	remote, _ := ctx.Value("remote").(string)
+	log.Info("RPC privileged call", "subject", info.Subject, "role", info.Role, "remote", remote, "method", method, "required", required, "allowed", allowed)
+
+	if p.audit == nil {
+		return
+	}
+	line, err := json.Marshal(&auditEntry{
+		Time:     time.Now(),
+		Subject:  info.Subject,
+		Role:     info.Role.String(),
+		Remote:   remote,
+		Method:   method,
+		Required: required.String(),
+		Allowed:  allowed,
+	})
+	if err != nil {
+		return
+	}
+	p.auditLock.Lock()
+	defer p.auditLock.Unlock()
+
+	if _, err := p.audit.Write(append(line, '\n')); err != nil {
+		log.Warn("Failed to write rpc audit log", "err", err)
+	}
+}
+
+// close releases the audit log.
+func (p *authPolicy) close() {
+	p.auditLock.Lock()
+	defer p.auditLock.Unlock()
+
+	if p.audit != nil {
+		p.audit.Close()
+		p.audit = nil
+	}
+}
+
+// authHandler is a handler which authenticates incoming requests and attaches
+// the identity of the client to the request context.
+type authHandler struct {
+	policy *authPolicy
+	next   http.Handler
+}
+
+// ServeHTTP implements http.Handler
+func (h *authHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	info, err := h.policy.authenticate(r)
+	if err != nil {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="cpchain"`)
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	h.next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), authInfoKey{}, info)))
+}
+
+func newAuthHandler(policy *authPolicy, next http.Handler) http.Handler {
+	if policy == nil {
+		return next
+	}
+	return &authHandler{policy: policy, next: next}
+}
+
+// methodAuthHandler authorizes the requests of a handler serving another
+// protocol than JSON-RPC as calls of method. Clients need RoleReadOnly at
+// least, whatever the role configured for the method.
+type methodAuthHandler struct {
+	policy *authPolicy
+	method string
+	next   http.Handler
+}
+
+func newMethodAuthHandler(policy *authPolicy, method string, next http.Handler) http.Handler {
+	if policy == nil {
+		return next
+	}
+	return &methodAuthHandler{policy: policy, method: method, next: next}
+}
+
+// ServeHTTP implements http.Handler
+func (h *methodAuthHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := context.WithValue(r.Context(), "remote", r.RemoteAddr)
+	if info, ok := authInfoFromContext(ctx); !ok || info.Role < RoleReadOnly {
+		err := &unauthorizedError{method: h.method, role: RoleReadOnly}
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+	if err := h.policy.authorize(ctx, h.method); err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+	h.next.ServeHTTP(w, r)
+}
+
+// SetAuth enables authentication and access control on the server. It must be
+// called before the server starts serving requests.
+func (s *Server) SetAuth(config *AuthConfig) error {
+	policy, err := newAuthPolicy(config)
+	if err != nil {
+		return err
+	}
+	s.auth = policy
+	return nil
+}
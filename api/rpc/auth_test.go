@@ -0,0 +1,204 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"bufio"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	jwt "github.com/dgrijalva/jwt-go"
+)
+
+func newTestAuthServer(t *testing.T, config *AuthConfig) *httptest.Server {
+	server := NewServer()
+	if err := server.RegisterName("test", new(Service)); err != nil {
+		t.Fatal(err)
+	}
+	if err := server.RegisterName("personal", new(Service)); err != nil {
+		t.Fatal(err)
+	}
+	// the test namespace is unknown to the defaults, which require admin
+	roles := map[string]string{"test_*": "readonly"}
+	for method, role := range config.MethodRoles {
+		roles[method] = role
+	}
+	config.MethodRoles = roles
+	if err := server.SetAuth(config); err != nil {
+		t.Fatal(err)
+	}
+	return httptest.NewServer(NewHTTPServer(nil, []string{"*"}, server).Handler)
+}
+
+// doAuthCall posts a call with the given credential header and returns the
+// http status and the json-rpc error code, if any.
+func doAuthCall(t *testing.T, url, method, header, value string) (int, int) {
+	body := `{"jsonrpc":"2.0","id":1,"method":"` + method + `","params":[]}`
+	req, _ := http.NewRequest(http.MethodPost, url, strings.NewReader(body))
+	req.Header.Set("content-type", contentType)
+	if header != "" {
+		req.Header.Set(header, value)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return resp.StatusCode, 0
+	}
+	var msg jsonrpcMessage
+	if err := json.NewDecoder(resp.Body).Decode(&msg); err != nil {
+		t.Fatal(err)
+	}
+	if msg.Error != nil {
+		return resp.StatusCode, msg.Error.Code
+	}
+	return resp.StatusCode, 0
+}
+
+func TestAuthHTTP(t *testing.T) {
+	secret := "0123456789abcdef"
+	signer, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"sub": "svc", "role": "signer"}).SignedString([]byte(secret))
+	admin, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"sub": "ops", "role": "admin"}).SignedString([]byte(secret))
+	forged, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"sub": "evil", "role": "admin"}).SignedString([]byte("wrong"))
+
+	hs := newTestAuthServer(t, &AuthConfig{
+		JWTSecret:   secret,
+		APIKeys:     []APIKey{{Name: "explorer", Key: "readkey", Role: "readonly"}},
+		MethodRoles: map[string]string{"personal_noArgsRets": "signer"},
+	})
+	defer hs.Close()
+
+	tests := []struct {
+		method, header, value string
+		status, code          int
+	}{
+		{"test_noArgsRets", "", "", http.StatusUnauthorized, 0},
+		{"test_noArgsRets", "X-API-Key", "badkey", http.StatusUnauthorized, 0},
+		{"test_noArgsRets", "Authorization", "Bearer " + forged, http.StatusUnauthorized, 0},
+		{"test_noArgsRets", "X-API-Key", "readkey", http.StatusOK, 0},
+		{"personal_noArgsRets", "X-API-Key", "readkey", http.StatusOK, -32001},
+		{"personal_noArgsRets", "Authorization", "Bearer " + signer, http.StatusOK, 0},
+		{"personal_rets", "Authorization", "Bearer " + signer, http.StatusOK, -32001},
+		{"personal_rets", "Authorization", "Bearer " + admin, http.StatusOK, 0},
+	}
+	for i, tt := range tests {
+		status, code := doAuthCall(t, hs.URL, tt.method, tt.header, tt.value)
+		if status != tt.status || code != tt.code {
+			t.Errorf("test %d (%s): have status %d code %d, want status %d code %d", i, tt.method, status, code, tt.status, tt.code)
+		}
+	}
+}
+
+func TestAuthAnonymousAndAudit(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rpc-audit")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	auditFile := filepath.Join(dir, "audit.log")
+
+	hs := newTestAuthServer(t, &AuthConfig{AnonymousRole: "readonly", AuditLog: auditFile})
+	defer hs.Close()
+
+	if status, code := doAuthCall(t, hs.URL, "test_noArgsRets", "", ""); status != http.StatusOK || code != 0 {
+		t.Fatalf("anonymous read: have status %d code %d", status, code)
+	}
+	if _, code := doAuthCall(t, hs.URL, "personal_noArgsRets", "", ""); code != -32001 {
+		t.Fatalf("anonymous privileged call: have code %d, want -32001", code)
+	}
+
+	f, err := os.Open(auditFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var entries []auditEntry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var entry auditEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			t.Fatal(err)
+		}
+		entries = append(entries, entry)
+	}
+	if len(entries) != 1 || entries[0].Method != "personal_noArgsRets" || entries[0].Allowed || entries[0].Required != "admin" {
+		t.Fatalf("unexpected audit log: %+v", entries)
+	}
+}
+
+func TestDefaultMethodRoles(t *testing.T) {
+	policy, err := newAuthPolicy(&AuthConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		method string
+		role   Role
+	}{
+		// state changing namespaces
+		{"admin_addPeer", RoleAdmin},
+		{"admission_fundForRNode", RoleAdmin},
+		{"admission_campaign", RoleAdmin},
+		{"admission_startCampaignManager", RoleAdmin},
+		{"debug_setHead", RoleAdmin},
+		{"miner_start", RoleAdmin},
+		{"miner_enterMaintenance", RoleAdmin},
+		{"personal_unlockAccount", RoleAdmin},
+		{"simulation_snapshot", RoleAdmin},
+		{"unknown_method", RoleAdmin},
+		// signing methods of read only namespaces
+		{"personal_sendTransaction", RoleSigner},
+		{"eth_sendRawTransaction", RoleSigner},
+		{"cpc_signTransaction", RoleSigner},
+		// read only namespaces
+		{"cpc_simulate", RoleReadOnly},
+		{"eth_getBalance", RoleReadOnly},
+		{"net_version", RoleReadOnly},
+		{"web3_clientVersion", RoleReadOnly},
+		{"txpool_content", RoleReadOnly},
+		{"rpc_modules", RoleReadOnly},
+		{"dpor_getPenalties", RoleReadOnly},
+		{"reward_getRewards", RoleReadOnly},
+	}
+	for _, tt := range tests {
+		if role := policy.requiredRole(tt.method); role != tt.role {
+			t.Errorf("%s: have role %v, want %v", tt.method, role, tt.role)
+		}
+	}
+}
+
+func TestAuthConfigValidation(t *testing.T) {
+	bad := []*AuthConfig{
+		{AnonymousRole: "root"},
+		{APIKeys: []APIKey{{Name: "a", Key: "k", Role: "superuser"}}},
+		{APIKeys: []APIKey{{Name: "a", Role: "admin"}}},
+		{MethodRoles: map[string]string{"cpc_*": "nobody"}},
+	}
+	for i, config := range bad {
+		if _, err := newAuthPolicy(config); err == nil {
+			t.Errorf("test %d: expected error for invalid config", i)
+		}
+	}
+}
@@ -0,0 +1,259 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+const openRPCVersion = "1.0.0"
+
+// OpenRPCDocument describes the methods of a server, following the OpenRPC
+// specification (https://spec.open-rpc.org).
+type OpenRPCDocument struct {
+	OpenRPC    string            `json:"openrpc"`
+	Info       OpenRPCInfo       `json:"info"`
+	Methods    []*OpenRPCMethod  `json:"methods"`
+	Components OpenRPCComponents `json:"components"`
+}
+
+// OpenRPCInfo is the metadata of an OpenRPC document.
+type OpenRPCInfo struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+// OpenRPCMethod describes a method, its positional parameters and its result.
+type OpenRPCMethod struct {
+	Name   string               `json:"name"`
+	Params []*ContentDescriptor `json:"params"`
+	Result *ContentDescriptor   `json:"result"`
+}
+
+// ContentDescriptor describes a parameter or a result.
+type ContentDescriptor struct {
+	Name     string      `json:"name"`
+	Required bool        `json:"required,omitempty"`
+	Schema   *JSONSchema `json:"schema"`
+}
+
+// OpenRPCComponents holds the schemas of the named struct types referenced by
+// the methods.
+type OpenRPCComponents struct {
+	Schemas map[string]*JSONSchema `json:"schemas"`
+}
+
+// JSONSchema is the subset of JSON schema used to describe Go types.
+type JSONSchema struct {
+	Ref                  string                 `json:"$ref,omitempty"`
+	Type                 string                 `json:"type,omitempty"`
+	Description          string                 `json:"description,omitempty"`
+	Pattern              string                 `json:"pattern,omitempty"`
+	Items                *JSONSchema            `json:"items,omitempty"`
+	Properties           map[string]*JSONSchema `json:"properties,omitempty"`
+	AdditionalProperties *JSONSchema            `json:"additionalProperties,omitempty"`
+}
+
+var (
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+
+	// wellKnownSchemas are the schemas of types whose JSON encoding is not
+	// derived from their Go structure.
+	wellKnownSchemas = map[reflect.Type]*JSONSchema{
+		reflect.TypeOf(common.Hash{}):     {Type: "string", Pattern: "^0x[0-9a-fA-F]{64}$"},
+		reflect.TypeOf(common.Address{}):  {Type: "string", Pattern: "^0x[0-9a-fA-F]{40}$"},
+		reflect.TypeOf(hexutil.Bytes{}):   {Type: "string", Pattern: "^0x([0-9a-fA-F]{2})*$"},
+		reflect.TypeOf(hexutil.Big{}):     {Type: "string", Pattern: "^0x[0-9a-fA-F]+$", Description: "hex encoded integer"},
+		reflect.TypeOf(hexutil.Uint64(0)): {Type: "string", Pattern: "^0x[0-9a-fA-F]+$", Description: "hex encoded integer"},
+		reflect.TypeOf(hexutil.Uint(0)):   {Type: "string", Pattern: "^0x[0-9a-fA-F]+$", Description: "hex encoded integer"},
+		reflect.TypeOf(big.Int{}):         {Type: "integer"},
+		reflect.TypeOf(BlockNumber(0)):    {Type: "string", Description: "hex encoded block number, or one of \"earliest\", \"latest\" and \"pending\""},
+	}
+)
+
+// schemaGenerator derives JSON schemas from Go types, named structs are
+// collected as components so that recursive types terminate.
+type schemaGenerator struct {
+	components map[string]*JSONSchema
+}
+
+func (g *schemaGenerator) schema(t reflect.Type) *JSONSchema {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if schema, ok := wellKnownSchemas[t]; ok {
+		known := *schema
+		return &known
+	}
+	ptr := reflect.PtrTo(t)
+	if t.Implements(textMarshalerType) || ptr.Implements(textMarshalerType) {
+		return &JSONSchema{Type: "string", Description: t.String()}
+	}
+	if t.Implements(jsonMarshalerType) || ptr.Implements(jsonMarshalerType) {
+		return &JSONSchema{Description: t.String()}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &JSONSchema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &JSONSchema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &JSONSchema{Type: "number"}
+	case reflect.String:
+		return &JSONSchema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8 {
+			return &JSONSchema{Type: "string", Description: "base64 encoded bytes"}
+		}
+		return &JSONSchema{Type: "array", Items: g.schema(t.Elem())}
+	case reflect.Map:
+		return &JSONSchema{Type: "object", AdditionalProperties: g.schema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.structSchema(t)
+		}
+		name := t.String()
+		if _, ok := g.components[name]; !ok {
+			g.components[name] = nil // placeholder, breaks recursion
+			g.components[name] = g.structSchema(t)
+		}
+		return &JSONSchema{Ref: "#/components/schemas/" + name}
+	}
+	// interfaces, channels and functions can be anything
+	return &JSONSchema{}
+}
+
+// structSchema describes the exported fields of a struct as encoding/json
+// marshals them.
+func (g *schemaGenerator) structSchema(t reflect.Type) *JSONSchema {
+	schema := &JSONSchema{Type: "object", Properties: make(map[string]*JSONSchema)}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" && !f.Anonymous {
+			continue
+		}
+		name := f.Name
+		if tag := f.Tag.Get("json"); tag != "" {
+			if tag == "-" {
+				continue
+			}
+			if n := strings.Split(tag, ",")[0]; n != "" {
+				name = n
+			} else if f.Anonymous {
+				name = ""
+			}
+		} else if f.Anonymous {
+			name = ""
+		}
+		ft := f.Type
+		for ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		// untagged embedded structs are flattened
+		if name == "" && ft.Kind() == reflect.Struct {
+			for prop, s := range g.structSchema(ft).Properties {
+				schema.Properties[prop] = s
+			}
+			continue
+		}
+		if f.PkgPath != "" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		schema.Properties[name] = g.schema(f.Type)
+	}
+	return schema
+}
+
+// describe returns the OpenRPC description of a callback.
+func (g *schemaGenerator) describe(name string, cb *callback) *OpenRPCMethod {
+	method := &OpenRPCMethod{Name: name, Params: []*ContentDescriptor{}}
+	used := make(map[string]bool)
+	for i, t := range cb.argTypes {
+		pname := paramName(t, i)
+		if used[pname] {
+			pname = fmt.Sprintf("%s%d", pname, i)
+		}
+		used[pname] = true
+		method.Params = append(method.Params, &ContentDescriptor{
+			Name:     pname,
+			Required: t.Kind() != reflect.Ptr, // trailing pointers may be omitted
+			Schema:   g.schema(t),
+		})
+	}
+	result := &ContentDescriptor{Name: "result", Schema: &JSONSchema{Type: "null"}}
+	mtype := cb.method.Type
+	for i := 0; i < mtype.NumOut(); i++ {
+		if i != cb.errPos {
+			result.Schema = g.schema(mtype.Out(i))
+			break
+		}
+	}
+	method.Result = result
+	return method
+}
+
+// paramName derives a parameter name from its type, Go reflection does not
+// know the names of method arguments.
+func paramName(t reflect.Type, i int) string {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if name := t.Name(); name != "" && t.PkgPath() != "" {
+		return formatName(name)
+	}
+	return fmt.Sprintf("arg%d", i)
+}
+
+// openRPCDocument describes every method registered on the server, sorted by
+// name. Subscriptions are not listed.
+func (s *Server) openRPCDocument() *OpenRPCDocument {
+	g := &schemaGenerator{components: make(map[string]*JSONSchema)}
+	doc := &OpenRPCDocument{
+		OpenRPC: openRPCVersion,
+		Info:    OpenRPCInfo{Title: "cpchain JSON-RPC API", Version: "1.0"},
+		Methods: []*OpenRPCMethod{},
+	}
+	for svcname, svc := range s.services {
+		for name, cb := range svc.callbacks {
+			doc.Methods = append(doc.Methods, g.describe(svcname+serviceMethodSeparator+name, cb))
+		}
+	}
+	sort.Slice(doc.Methods, func(i, j int) bool { return doc.Methods[i].Name < doc.Methods[j].Name })
+	doc.Components.Schemas = g.components
+	return doc
+}
+
+// Discover returns the OpenRPC document of the server, it is served as
+// rpc_discover.
+func (s *RPCService) Discover() *OpenRPCDocument {
+	return s.server.openRPCDocument()
+}
@@ -22,8 +22,9 @@ import (
 	"bitbucket.org/cpchain/chain/commons/log"
 )
 
-// StartHTTPEndpoint starts the HTTP RPC endpoint, configured with cors/vhosts/modules
-func StartHTTPEndpoint(endpoint string, apis []API, modules []string, cors []string, vhosts []string) (net.Listener, *Server, error) {
+// StartHTTPEndpoint starts the HTTP RPC endpoint, configured with cors/vhosts/modules,
+// optional access control and request limits and an optional REST gateway
+func StartHTTPEndpoint(endpoint string, apis []API, modules []string, cors []string, vhosts []string, auth *AuthConfig, limits *RateLimitConfig, rest bool) (net.Listener, *Server, error) {
 	// Generate the whitelist based on the allowed modules
 	whitelist := make(map[string]bool)
 	for _, module := range modules {
@@ -39,6 +40,15 @@ func StartHTTPEndpoint(endpoint string, apis []API, modules []string, cors []str
 			log.Debug("HTTP registered", "namespace", api.Namespace)
 		}
 	}
+	if err := handler.SetAuth(auth); err != nil {
+		return nil, nil, err
+	}
+	handler.SetRateLimits(limits)
+	if rest {
+		if err := handler.EnableREST(DefaultRESTRoutes); err != nil {
+			return nil, nil, err
+		}
+	}
 	// All APIs registered, start the HTTP listener
 	var (
 		listener net.Listener
@@ -51,8 +61,9 @@ func StartHTTPEndpoint(endpoint string, apis []API, modules []string, cors []str
 	return listener, handler, err
 }
 
-// StartWSEndpoint starts a websocket endpoint
-func StartWSEndpoint(endpoint string, apis []API, modules []string, wsOrigins []string, exposeAll bool) (net.Listener, *Server, error) {
+// StartWSEndpoint starts a websocket endpoint with optional access control and
+// request limits
+func StartWSEndpoint(endpoint string, apis []API, modules []string, wsOrigins []string, exposeAll bool, auth *AuthConfig, limits *RateLimitConfig) (net.Listener, *Server, error) {
 
 	// Generate the whitelist based on the allowed modules
 	whitelist := make(map[string]bool)
@@ -69,6 +80,10 @@ func StartWSEndpoint(endpoint string, apis []API, modules []string, wsOrigins []
 			log.Debug("WebSocket registered", "service", api.Service, "namespace", api.Namespace)
 		}
 	}
+	if err := handler.SetAuth(auth); err != nil {
+		return nil, nil, err
+	}
+	handler.SetRateLimits(limits)
 	// All APIs registered, start the HTTP listener
 	var (
 		listener net.Listener
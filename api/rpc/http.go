@@ -162,9 +162,15 @@ func (t *httpReadWriteNopCloser) Close() error {
 //
 // Deprecated: Server implements http.Handler
 func NewHTTPServer(cors []string, vhosts []string, srv *Server) *http.Server {
-	// Wrap the CORS-handler within a host-handler
-	handler := newCorsHandler(srv, cors)
+	// Wrap the auth-handler within a CORS-handler within a host-handler
+	handler := newCorsHandler(newAuthHandler(srv.auth, srv), cors)
 	handler = newVHostHandler(vhosts, handler)
+	return NewHTTPServerWithHandler(handler)
+}
+
+// NewHTTPServerWithHandler creates a new HTTP server with the timeouts of the
+// RPC server around an arbitrary handler.
+func NewHTTPServerWithHandler(handler http.Handler) *http.Server {
 	return &http.Server{
 		Handler:      handler,
 		ReadTimeout:  5 * time.Second,
@@ -173,8 +179,30 @@ func NewHTTPServer(cors []string, vhosts []string, srv *Server) *http.Server {
 	}
 }
 
-// ServeHTTP serves JSON-RPC requests over HTTP.
+// HTTPHandlerStack wraps a handler serving other protocols than JSON-RPC, such
+// as GraphQL, in the authentication, access control, rate limit, CORS and
+// virtual host checks of the server. Each request is authorized and charged as
+// a call of method, from the same client budgets as the calls to the server.
+func (srv *Server) HTTPHandlerStack(next http.Handler, cors []string, vhosts []string, method string) http.Handler {
+	handler := newRateLimitHandler(srv.limits, method, next)
+	handler = newAuthHandler(srv.auth, newMethodAuthHandler(srv.auth, method, handler))
+	return newVHostHandler(vhosts, newCorsHandler(handler, cors))
+}
+
+// ServeHTTP serves JSON-RPC requests over HTTP, and REST requests if enabled.
 func (srv *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if srv.rest != nil && strings.HasPrefix(r.URL.Path, restPrefix) {
+		// GET requests only reach read-only methods, others must be JSON
+		// requests so browsers preflight them across origins
+		if r.Method != http.MethodGet {
+			if code, err := validateRequest(r); err != nil {
+				http.Error(w, err.Error(), code)
+				return
+			}
+		}
+		srv.rest.ServeHTTP(w, r)
+		return
+	}
 	// Permit dumb empty requests for remote health-checks (AWS)
 	if r.Method == http.MethodGet && r.ContentLength == 0 && r.URL.RawQuery == "" {
 		return
@@ -217,7 +245,7 @@ func validateRequest(r *http.Request) (int, error) {
 	return 0, nil
 }
 
-func newCorsHandler(srv *Server, allowedOrigins []string) http.Handler {
+func newCorsHandler(srv http.Handler, allowedOrigins []string) http.Handler {
 	// disable CORS support if user has not specified a custom CORS configuration
 	if len(allowedOrigins) == 0 {
 		return srv
@@ -0,0 +1,305 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"bitbucket.org/cpchain/chain/commons/log"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+const (
+	// maxRateLimitClients is the number of client buckets kept before the idle
+	// ones are dropped.
+	maxRateLimitClients = 10000
+
+	// maxExpensiveWait is the time an expensive call waits for a slot before it
+	// is rejected, calls without a deadline such as websocket ones included.
+	maxExpensiveWait = 5 * time.Second
+)
+
+var (
+	rpcCallCostMeter          = metrics.NewRegisteredMeter("rpc/calls/cost", nil)
+	rpcRateLimitedMeter       = metrics.NewRegisteredMeter("rpc/ratelimit/rejected", nil)
+	rpcBatchTooLargeMeter     = metrics.NewRegisteredMeter("rpc/batch/rejected", nil)
+	rpcResponseTooLargeMeter  = metrics.NewRegisteredMeter("rpc/response/rejected", nil)
+	rpcExpensiveActiveGauge   = metrics.NewRegisteredGauge("rpc/expensive/active", nil)
+	rpcExpensiveRejectedMeter = metrics.NewRegisteredMeter("rpc/expensive/rejected", nil)
+)
+
+// DefaultMethodCosts are the costs of expensive methods if they are not
+// overridden by RateLimitConfig.MethodCosts. Any other method costs 1.
+var DefaultMethodCosts = map[string]int{
+	"debug_traceBlock":         50,
+	"debug_traceBlockByNumber": 50,
+	"debug_traceBlockByHash":   50,
+	"debug_traceBlockFromFile": 50,
+	"debug_traceTransaction":   20,
+	"cpc_getLogs":              20,
+	"eth_getLogs":              20,
+	"cpc_getFilterLogs":        20,
+	"eth_getFilterLogs":        20,
+	"cpc_simulate":             20,
+	"reward_getHistory":        20,
+	"reward_getStatement":      20,
+	"graphql_query":            10,
+}
+
+// RateLimitConfig configures per client request limits of the HTTP and
+// websocket RPC interfaces. Zero values disable the corresponding limit.
+type RateLimitConfig struct {
+	// RequestsPerSecond is the rate at which the cost budget of a client, keyed
+	// by API key or JWT subject if authenticated and by IP otherwise, refills.
+	RequestsPerSecond float64 `toml:",omitempty"`
+
+	// Burst is the maximum cost budget a client can accumulate, methods costing
+	// more are always rejected. It defaults to the largest method cost.
+	Burst int `toml:",omitempty"`
+
+	// MethodCosts maps a method ("cpc_getLogs") or a namespace wildcard
+	// ("debug_*") to its cost, on top of DefaultMethodCosts.
+	MethodCosts map[string]int `toml:",omitempty"`
+
+	// MaxBatchSize is the maximum number of calls in a batch request.
+	MaxBatchSize int `toml:",omitempty"`
+
+	// MaxResponseSize is the maximum size in bytes of the result of a call.
+	MaxResponseSize int `toml:",omitempty"`
+
+	// ExpensiveCost is the cost from which a method is considered expensive,
+	// defaults to 10.
+	ExpensiveCost int `toml:",omitempty"`
+
+	// MaxConcurrentExpensive is the maximum number of expensive calls executed
+	// at the same time by the server, across all clients.
+	MaxConcurrentExpensive int `toml:",omitempty"`
+}
+
+// tokenBucket is a refilling cost budget of a client.
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// take refills the bucket and takes the cost out of it if there is enough.
+func (b *tokenBucket) take(cost float64, rate float64, burst float64, now time.Time) bool {
+	b.tokens += now.Sub(b.last).Seconds() * rate
+	if b.tokens > burst {
+		b.tokens = burst
+	}
+	b.last = now
+	if b.tokens < cost {
+		return false
+	}
+	b.tokens -= cost
+	return true
+}
+
+// rateLimiter enforces the limits of a RateLimitConfig.
+type rateLimiter struct {
+	config    RateLimitConfig
+	costs     map[string]int
+	expensive chan struct{} // semaphore of concurrent expensive calls, nil if unlimited
+
+	lock    sync.Mutex
+	buckets map[string]*tokenBucket
+}
+
+// newRateLimiter creates the limiter described by the config, nil config
+// disables rate limiting.
+func newRateLimiter(config *RateLimitConfig) *rateLimiter {
+	if config == nil {
+		return nil
+	}
+	l := &rateLimiter{
+		config:  *config,
+		costs:   make(map[string]int),
+		buckets: make(map[string]*tokenBucket),
+	}
+	if l.config.ExpensiveCost <= 0 {
+		l.config.ExpensiveCost = 10
+	}
+	maxCost := 1
+	for _, costs := range []map[string]int{DefaultMethodCosts, config.MethodCosts} {
+		for method, cost := range costs {
+			l.costs[method] = cost
+		}
+	}
+	for _, cost := range l.costs {
+		if cost > maxCost {
+			maxCost = cost
+		}
+	}
+	if l.config.Burst <= 0 {
+		l.config.Burst = int(l.config.RequestsPerSecond) + 1
+		if l.config.Burst < maxCost {
+			l.config.Burst = maxCost
+		}
+	} else if l.config.RequestsPerSecond > 0 && l.config.Burst < maxCost {
+		log.Warn("RPC rate limit burst below the largest method cost, costlier methods are always rejected", "burst", l.config.Burst, "maxCost", maxCost)
+	}
+	if config.MaxConcurrentExpensive > 0 {
+		l.expensive = make(chan struct{}, config.MaxConcurrentExpensive)
+	}
+	return l
+}
+
+// cost returns the cost of a method, exact method names take precedence over
+// namespace wildcards.
+func (l *rateLimiter) cost(method string) int {
+	if cost, ok := l.costs[method]; ok {
+		return cost
+	}
+	if i := strings.Index(method, serviceMethodSeparator); i >= 0 {
+		if cost, ok := l.costs[method[:i]+serviceMethodSeparator+"*"]; ok {
+			return cost
+		}
+	}
+	return 1
+}
+
+// clientKey identifies the client of a call, the authenticated identity if any
+// and the remote IP otherwise.
+func clientKey(ctx context.Context) string {
+	if info, ok := authInfoFromContext(ctx); ok && info.Subject != "anonymous" {
+		return info.Subject
+	}
+	remote, _ := ctx.Value("remote").(string)
+	if host, _, err := net.SplitHostPort(remote); err == nil {
+		return host
+	}
+	return remote
+}
+
+// allow charges the cost of the method to the client of the call, it returns
+// false if the client's budget is exhausted.
+func (l *rateLimiter) allow(ctx context.Context, method string) bool {
+	cost := l.cost(method)
+	rpcCallCostMeter.Mark(int64(cost))
+
+	if l.config.RequestsPerSecond <= 0 {
+		return true
+	}
+	var (
+		key   = clientKey(ctx)
+		now   = time.Now()
+		burst = float64(l.config.Burst)
+	)
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	bucket, ok := l.buckets[key]
+	if !ok {
+		if len(l.buckets) >= maxRateLimitClients {
+			l.dropIdle(now)
+		}
+		bucket = &tokenBucket{tokens: burst, last: now}
+		l.buckets[key] = bucket
+	}
+	if !bucket.take(float64(cost), l.config.RequestsPerSecond, burst, now) {
+		rpcRateLimitedMeter.Mark(1)
+		return false
+	}
+	return true
+}
+
+// dropIdle removes the buckets which are refilled, they are the same as new ones.
+func (l *rateLimiter) dropIdle(now time.Time) {
+	for key, bucket := range l.buckets {
+		if bucket.tokens+now.Sub(bucket.last).Seconds()*l.config.RequestsPerSecond >= float64(l.config.Burst) {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// acquire waits for a slot if the method is expensive and the number of
+// concurrent expensive calls is limited, at most maxExpensiveWait. The
+// returned function releases it.
+func (l *rateLimiter) acquire(ctx context.Context, method string) (func(), bool) {
+	if l.expensive == nil || l.cost(method) < l.config.ExpensiveCost {
+		return func() {}, true
+	}
+	timer := time.NewTimer(maxExpensiveWait)
+	defer timer.Stop()
+
+	select {
+	case l.expensive <- struct{}{}:
+		rpcExpensiveActiveGauge.Update(int64(len(l.expensive)))
+		return func() {
+			<-l.expensive
+			rpcExpensiveActiveGauge.Update(int64(len(l.expensive)))
+		}, true
+	case <-ctx.Done():
+		rpcExpensiveRejectedMeter.Mark(1)
+		return nil, false
+	case <-timer.C:
+		rpcExpensiveRejectedMeter.Mark(1)
+		return nil, false
+	}
+}
+
+// batchAllowed checks the size of a batch request.
+func (l *rateLimiter) batchAllowed(size int) bool {
+	if l.config.MaxBatchSize > 0 && size > l.config.MaxBatchSize {
+		rpcBatchTooLargeMeter.Mark(1)
+		return false
+	}
+	return true
+}
+
+// rateLimitHandler charges the requests of a handler serving another protocol
+// than JSON-RPC to the client limits, each at the cost of method.
+type rateLimitHandler struct {
+	limits *rateLimiter
+	method string
+	next   http.Handler
+}
+
+func newRateLimitHandler(limits *rateLimiter, method string, next http.Handler) http.Handler {
+	if limits == nil {
+		return next
+	}
+	return &rateLimitHandler{limits: limits, method: method, next: next}
+}
+
+// ServeHTTP implements http.Handler
+func (h *rateLimitHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := context.WithValue(r.Context(), "remote", r.RemoteAddr)
+	if !h.limits.allow(ctx, h.method) {
+		http.Error(w, "rate limit exceeded", http.StatusTooManyRequests)
+		return
+	}
+	release, ok := h.limits.acquire(ctx, h.method)
+	if !ok {
+		http.Error(w, "too many expensive requests", http.StatusServiceUnavailable)
+		return
+	}
+	defer release()
+	h.next.ServeHTTP(w, r)
+}
+
+// SetRateLimits enables request limits on the server. It must be called before
+// the server starts serving requests.
+func (s *Server) SetRateLimits(config *RateLimitConfig) {
+	s.limits = newRateLimiter(config)
+}
@@ -0,0 +1,207 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestLimitedClient(t *testing.T, config *RateLimitConfig) *Client {
+	server := NewServer()
+	if err := server.RegisterName("test", new(Service)); err != nil {
+		t.Fatal(err)
+	}
+	server.SetRateLimits(config)
+	return DialInProc(server)
+}
+
+func TestRateLimitBudget(t *testing.T) {
+	client := newTestLimitedClient(t, &RateLimitConfig{
+		RequestsPerSecond: 0.001,
+		Burst:             2,
+		MethodCosts:       map[string]int{"test_echo": 3},
+	})
+	defer client.Close()
+
+	for i := 0; i < 2; i++ {
+		if err := client.Call(nil, "test_noArgsRets"); err != nil {
+			t.Fatalf("call %d: unexpected error: %v", i, err)
+		}
+	}
+	if err := client.Call(nil, "test_noArgsRets"); err == nil || err.Error() != "rate limit exceeded" {
+		t.Fatalf("expected rate limit error, got %v", err)
+	}
+}
+
+func TestRateLimitMethodCost(t *testing.T) {
+	client := newTestLimitedClient(t, &RateLimitConfig{
+		RequestsPerSecond: 0.001,
+		Burst:             2,
+		MethodCosts:       map[string]int{"test_*": 3},
+	})
+	defer client.Close()
+
+	if err := client.Call(nil, "test_noArgsRets"); err == nil {
+		t.Fatal("expected a call costing more than the burst to fail")
+	}
+}
+
+func TestRateLimitResponseSize(t *testing.T) {
+	client := newTestLimitedClient(t, &RateLimitConfig{MaxResponseSize: 64})
+	defer client.Close()
+
+	var result Result
+	if err := client.Call(&result, "test_echo", "x", 1, &Args{"y"}); err != nil {
+		t.Fatalf("small response: unexpected error: %v", err)
+	}
+	if result.String != "x" || result.Int != 1 || result.Args.S != "y" {
+		t.Fatalf("wrong result: %+v", result)
+	}
+	if err := client.Call(&result, "test_echo", strings.Repeat("x", 100), 1, &Args{"y"}); err == nil || !strings.Contains(err.Error(), "too large") {
+		t.Fatalf("expected response size error, got %v", err)
+	}
+}
+
+func TestRateLimitBatchSize(t *testing.T) {
+	server := NewServer()
+	if err := server.RegisterName("test", new(Service)); err != nil {
+		t.Fatal(err)
+	}
+	server.SetRateLimits(&RateLimitConfig{MaxBatchSize: 1})
+	hs := httptest.NewServer(server)
+	defer hs.Close()
+
+	body := `[{"jsonrpc":"2.0","id":1,"method":"test_noArgsRets"},{"jsonrpc":"2.0","id":2,"method":"test_noArgsRets"}]`
+	resp, err := http.Post(hs.URL, contentType, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	var msg jsonrpcMessage
+	if err := json.NewDecoder(resp.Body).Decode(&msg); err != nil {
+		t.Fatalf("expected a single error response: %v", err)
+	}
+	if msg.Error == nil || msg.Error.Code != -32005 {
+		t.Fatalf("expected limit exceeded error, got %+v", msg.Error)
+	}
+}
+
+func TestRateLimitExpensiveConcurrency(t *testing.T) {
+	limiter := newRateLimiter(&RateLimitConfig{MaxConcurrentExpensive: 1})
+
+	// cheap methods are never limited
+	if release, ok := limiter.acquire(context.Background(), "cpc_blockNumber"); !ok {
+		t.Fatal("cheap method should not be limited")
+	} else {
+		release()
+	}
+	release, ok := limiter.acquire(context.Background(), "debug_traceBlock")
+	if !ok {
+		t.Fatal("first expensive call should get a slot")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, ok := limiter.acquire(ctx, "cpc_getLogs"); ok {
+		t.Fatal("second expensive call should wait for the first one")
+	}
+	release()
+	if release, ok := limiter.acquire(context.Background(), "cpc_getLogs"); !ok {
+		t.Fatal("slot should be available after release")
+	} else {
+		release()
+	}
+}
+
+func TestRateLimitDefaultBurst(t *testing.T) {
+	limiter := newRateLimiter(&RateLimitConfig{RequestsPerSecond: 10})
+	if limiter.config.Burst != DefaultMethodCosts["debug_traceBlock"] {
+		t.Fatalf("default burst %d, want the largest method cost %d", limiter.config.Burst, DefaultMethodCosts["debug_traceBlock"])
+	}
+	if !limiter.allow(context.Background(), "debug_traceBlock") {
+		t.Fatal("the costliest method should fit in the default burst")
+	}
+}
+
+func TestRateLimitHandlerStack(t *testing.T) {
+	served := 0
+	inner := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { served++ })
+	server := NewServer()
+	server.SetRateLimits(&RateLimitConfig{
+		RequestsPerSecond: 0.001,
+		Burst:             10,
+	})
+	hs := httptest.NewServer(server.HTTPHandlerStack(inner, nil, []string{"*"}, "graphql_query"))
+	defer hs.Close()
+
+	for i, want := range []int{http.StatusOK, http.StatusTooManyRequests} {
+		resp, err := http.Get(hs.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != want {
+			t.Fatalf("request %d: status %d, want %d", i, resp.StatusCode, want)
+		}
+	}
+	if served != 1 {
+		t.Fatalf("served %d requests, want 1", served)
+	}
+
+	// the budget is the one of the server's own calls
+	if server.limits.allow(context.WithValue(context.Background(), "remote", "127.0.0.1:1"), "graphql_query") {
+		t.Fatal("handler stack should charge the server's client budget")
+	}
+}
+
+func TestHandlerStackRoles(t *testing.T) {
+	inner := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	server := NewServer()
+	if err := server.SetAuth(&AuthConfig{
+		APIKeys: []APIKey{
+			{Name: "nobody", Key: "nonekey", Role: "none"},
+			{Name: "explorer", Key: "readkey", Role: "readonly"},
+		},
+		// lowering the role of the method doesn't admit clients without one
+		MethodRoles: map[string]string{"graphql_*": "none"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	hs := httptest.NewServer(server.HTTPHandlerStack(inner, nil, []string{"*"}, "graphql_query"))
+	defer hs.Close()
+
+	for key, want := range map[string]int{"": http.StatusUnauthorized, "nonekey": http.StatusForbidden, "readkey": http.StatusOK} {
+		req, _ := http.NewRequest(http.MethodGet, hs.URL, nil)
+		if key != "" {
+			req.Header.Set("X-API-Key", key)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != want {
+			t.Errorf("key %q: status %d, want %d", key, resp.StatusCode, want)
+		}
+	}
+}
@@ -0,0 +1,396 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// restPrefix is the path prefix of the REST gateway on the HTTP endpoint.
+const restPrefix = "/v1/"
+
+// RESTRoute maps a REST endpoint to a RPC method.
+//
+// Params lists the sources of the positional arguments of the method, in order:
+// "{name}" is a segment of the path, "?name" a query parameter, "?name=value" a
+// query parameter with a default value and "body" the request body. Missing
+// trailing arguments are omitted from the call.
+type RESTRoute struct {
+	Method string // HTTP method
+	Path   string // path template such as "/v1/cpc/blocks/{number}"
+	RPC    string // RPC method such as "eth_getBlockByNumber"
+	Params []string
+}
+
+// DefaultRESTRoutes are the REST endpoints of the chain API. Any method can also
+// be called at /v1/<namespace>/<method> with a JSON array or object of
+// arguments in a POST body, and the methods of RESTReadOnlyMethods with
+// arguments named as in rpc_discover given as query parameters of a GET.
+var DefaultRESTRoutes = []RESTRoute{
+	{http.MethodGet, "/v1/cpc/blockNumber", "eth_blockNumber", nil},
+	{http.MethodGet, "/v1/cpc/gasPrice", "eth_gasPrice", nil},
+	{http.MethodGet, "/v1/cpc/blocks/{number}", "eth_getBlockByNumber", []string{"{number}", "?fullTx=false"}},
+	{http.MethodGet, "/v1/cpc/blocks/{number}/proposer", "eth_getProposerByBlock", []string{"{number}"}},
+	{http.MethodGet, "/v1/cpc/blocks/{number}/validators", "eth_getValidatorsByBlockNumber", []string{"{number}"}},
+	{http.MethodGet, "/v1/cpc/blockByHash/{hash}", "eth_getBlockByHash", []string{"{hash}", "?fullTx=false"}},
+	{http.MethodGet, "/v1/cpc/transactions/{hash}", "eth_getTransactionByHash", []string{"{hash}"}},
+	{http.MethodGet, "/v1/cpc/transactions/{hash}/receipt", "eth_getTransactionReceipt", []string{"{hash}"}},
+	{http.MethodPost, "/v1/cpc/transactions", "eth_sendRawTransaction", []string{"body"}},
+	{http.MethodGet, "/v1/cpc/accounts/{address}/balance", "eth_getBalance", []string{"{address}", "?block=latest"}},
+	{http.MethodGet, "/v1/cpc/accounts/{address}/nonce", "eth_getTransactionCount", []string{"{address}", "?block=latest"}},
+	{http.MethodGet, "/v1/cpc/accounts/{address}/code", "eth_getCode", []string{"{address}", "?block=latest"}},
+}
+
+// RESTReadOnlyMethods are the methods the generic endpoints serve on GET
+// requests. They do not change the state of the node, so pages of other
+// origins can not abuse them by making browsers fetch them.
+var RESTReadOnlyMethods = map[string]bool{
+	"eth_blockNumber":                      true,
+	"eth_gasPrice":                         true,
+	"eth_getBlockByNumber":                 true,
+	"eth_getBlockByHash":                   true,
+	"eth_getBlockTransactionCountByNumber": true,
+	"eth_getBlockTransactionCountByHash":   true,
+	"eth_getTransactionByHash":             true,
+	"eth_getTransactionReceipt":            true,
+	"eth_getTransactionCount":              true,
+	"eth_getBalance":                       true,
+	"eth_getCode":                          true,
+	"eth_getStorageAt":                     true,
+	"eth_getProposerByBlock":               true,
+	"eth_getValidatorsByBlockNumber":       true,
+	"net_version":                          true,
+	"net_peerCount":                        true,
+	"web3_clientVersion":                   true,
+}
+
+// errRESTNotReadOnly is returned for GET requests of generic endpoints which
+// are not read-only.
+var errRESTNotReadOnly = errors.New("method must be posted with a JSON body")
+
+var (
+	jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// restGateway serves REST requests by calling the RPC methods of a server.
+type restGateway struct {
+	server *Server
+	routes []*RESTRoute
+}
+
+// EnableREST serves the given REST routes and the generic /v1/<namespace>/<method>
+// endpoints on the HTTP handler of the server. It must be called before the
+// server starts serving requests.
+func (s *Server) EnableREST(routes []RESTRoute) error {
+	g := &restGateway{server: s}
+	for i := range routes {
+		route := routes[i]
+		if !strings.HasPrefix(route.Path, restPrefix) {
+			return fmt.Errorf("rest route %s must start with %s", route.Path, restPrefix)
+		}
+		if !strings.Contains(route.RPC, serviceMethodSeparator) {
+			return fmt.Errorf("invalid rpc method %q of rest route %s", route.RPC, route.Path)
+		}
+		for _, param := range route.Params {
+			if strings.HasPrefix(param, "{") && !strings.Contains(route.Path, param) {
+				return fmt.Errorf("rest route %s has no %s segment", route.Path, param)
+			}
+		}
+		g.routes = append(g.routes, &route)
+	}
+	s.rest = g
+	return nil
+}
+
+// restError is the body of failed REST requests.
+type restError struct {
+	Error *jsonError `json:"error"`
+}
+
+func writeRESTError(w http.ResponseWriter, status int, code int, message string) {
+	w.Header().Set("content-type", contentType)
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(&restError{&jsonError{Code: code, Message: message}})
+}
+
+// ServeHTTP implements http.Handler.
+func (g *restGateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodGet && r.URL.Path == restPrefix+"openrpc.json" {
+		w.Header().Set("content-type", contentType)
+		json.NewEncoder(w).Encode(g.server.openRPCDocument())
+		return
+	}
+	body, err := ioutil.ReadAll(io.LimitReader(r.Body, maxRequestContentLength))
+	if err != nil {
+		writeRESTError(w, http.StatusBadRequest, -32600, err.Error())
+		return
+	}
+	method, params, err := g.request(r, body)
+	if err == errRESTNotReadOnly {
+		writeRESTError(w, http.StatusMethodNotAllowed, -32601, err.Error())
+		return
+	}
+	if err != nil {
+		writeRESTError(w, http.StatusBadRequest, -32602, err.Error())
+		return
+	}
+	if method == "" {
+		writeRESTError(w, http.StatusNotFound, -32601, "no such endpoint")
+		return
+	}
+	g.call(w, r, method, params)
+}
+
+// request resolves the RPC method and arguments of a request, the method is
+// empty if no endpoint matches.
+func (g *restGateway) request(r *http.Request, body []byte) (string, []json.RawMessage, error) {
+	path := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	for _, route := range g.routes {
+		if route.Method != r.Method {
+			continue
+		}
+		vars, ok := matchPath(route.Path, path)
+		if !ok {
+			continue
+		}
+		params, err := g.routeArguments(route, vars, r, body)
+		return route.RPC, params, err
+	}
+	// generic endpoints: /v1/<namespace>/<method>
+	if len(path) != 3 || (r.Method != http.MethodGet && r.Method != http.MethodPost) {
+		return "", nil, nil
+	}
+	method := path[1] + serviceMethodSeparator + path[2]
+	cb := g.server.callback(method)
+	if cb == nil {
+		return "", nil, nil
+	}
+	if r.Method == http.MethodPost && len(bytes.TrimSpace(body)) > 0 {
+		params, err := bodyArguments(method, cb, body)
+		return method, params, err
+	}
+	if r.Method == http.MethodGet && !RESTReadOnlyMethods[method] {
+		return "", nil, errRESTNotReadOnly
+	}
+	query := r.URL.Query()
+	desc := (&schemaGenerator{components: make(map[string]*JSONSchema)}).describe(method, cb)
+	var values []string
+	for _, param := range desc.Params {
+		if _, ok := query[param.Name]; !ok {
+			break
+		}
+		values = append(values, query.Get(param.Name))
+	}
+	return method, convertArguments(cb, values), nil
+}
+
+// routeArguments gathers the arguments of a configured route.
+func (g *restGateway) routeArguments(route *RESTRoute, vars map[string]string, r *http.Request, body []byte) ([]json.RawMessage, error) {
+	query := r.URL.Query()
+	var values []string
+	for _, param := range route.Params {
+		switch {
+		case strings.HasPrefix(param, "{"):
+			values = append(values, vars[param])
+
+		case strings.HasPrefix(param, "?"):
+			name, def := param[1:], ""
+			hasDefault := false
+			if i := strings.Index(name, "="); i >= 0 {
+				name, def, hasDefault = name[:i], name[i+1:], true
+			}
+			if _, ok := query[name]; ok {
+				values = append(values, query.Get(name))
+			} else if hasDefault {
+				values = append(values, def)
+			} else {
+				return convertArguments(g.server.callback(route.RPC), values), nil
+			}
+
+		case param == "body":
+			values = append(values, string(bytes.TrimSpace(body)))
+
+		default:
+			return nil, fmt.Errorf("invalid parameter source %q", param)
+		}
+	}
+	return convertArguments(g.server.callback(route.RPC), values), nil
+}
+
+// matchPath matches a path against a route template, returning the values of
+// its "{name}" segments.
+func matchPath(template string, path []string) (map[string]string, bool) {
+	segments := strings.Split(strings.Trim(template, "/"), "/")
+	if len(segments) != len(path) {
+		return nil, false
+	}
+	vars := make(map[string]string)
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			vars[segment] = path[i]
+		} else if segment != path[i] {
+			return nil, false
+		}
+	}
+	return vars, true
+}
+
+// bodyArguments reads the arguments of a generic POST request, a JSON array of
+// positional arguments or an object keyed by argument name.
+func bodyArguments(method string, cb *callback, body []byte) ([]json.RawMessage, error) {
+	var list []json.RawMessage
+	if err := json.Unmarshal(body, &list); err == nil {
+		return list, nil
+	}
+	var named map[string]json.RawMessage
+	if err := json.Unmarshal(body, &named); err != nil {
+		return nil, fmt.Errorf("body must be a JSON array or object of arguments")
+	}
+	desc := (&schemaGenerator{components: make(map[string]*JSONSchema)}).describe(method, cb)
+	for _, param := range desc.Params {
+		arg, ok := named[param.Name]
+		if !ok {
+			break
+		}
+		list = append(list, arg)
+	}
+	return list, nil
+}
+
+// convertArguments turns the string values of path segments and query
+// parameters into JSON arguments of the method. Without the method, the values
+// are passed as JSON strings.
+func convertArguments(cb *callback, values []string) []json.RawMessage {
+	args := make([]json.RawMessage, len(values))
+	for i, value := range values {
+		var t reflect.Type
+		if cb != nil && i < len(cb.argTypes) {
+			t = cb.argTypes[i]
+		}
+		args[i] = convertArgument(value, t)
+	}
+	return args
+}
+
+func convertArgument(value string, t reflect.Type) json.RawMessage {
+	quoted, _ := json.Marshal(value)
+	if t == nil {
+		return quoted
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	ptr := reflect.PtrTo(t)
+	custom := ptr.Implements(jsonUnmarshalerType) || ptr.Implements(textUnmarshalerType)
+
+	if _, err := strconv.ParseUint(value, 10, 64); err == nil && custom {
+		// block numbers and quantities are hex encoded
+		n, _ := strconv.ParseUint(value, 10, 64)
+		hex, _ := json.Marshal(fmt.Sprintf("%#x", n))
+		return hex
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return quoted
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		if !custom {
+			return json.RawMessage(value)
+		}
+	}
+	if !custom && json.Valid([]byte(value)) {
+		return json.RawMessage(value)
+	}
+	return quoted
+}
+
+// callback returns the callback of a method, nil if there is none.
+func (s *Server) callback(method string) *callback {
+	elems := strings.SplitN(method, serviceMethodSeparator, 2)
+	if len(elems) != 2 {
+		return nil
+	}
+	svc, ok := s.services[elems[0]]
+	if !ok {
+		return nil
+	}
+	return svc.callbacks[elems[1]]
+}
+
+// call runs the method through the JSON-RPC handler of the server, so that
+// access control and request limits apply, and writes the result as is.
+func (g *restGateway) call(w http.ResponseWriter, r *http.Request, method string, params []json.RawMessage) {
+	if params == nil {
+		params = []json.RawMessage{}
+	}
+	rawParams, _ := json.Marshal(params)
+	req, _ := json.Marshal(&jsonrpcMessage{Version: jsonrpcVersion, ID: json.RawMessage("1"), Method: method, Params: rawParams})
+
+	ctx := r.Context()
+	ctx = context.WithValue(ctx, "remote", r.RemoteAddr)
+	ctx = context.WithValue(ctx, "scheme", r.Proto)
+	ctx = context.WithValue(ctx, "local", r.Host)
+
+	var out bytes.Buffer
+	codec := NewJSONCodec(&httpReadWriteNopCloser{bytes.NewReader(req), &out})
+	g.server.ServeSingleRequest(ctx, codec, OptionMethodInvocation)
+	codec.Close()
+
+	var resp jsonrpcMessage
+	if err := json.Unmarshal(out.Bytes(), &resp); err != nil {
+		writeRESTError(w, http.StatusInternalServerError, -32603, "invalid response")
+		return
+	}
+	if resp.Error != nil {
+		writeRESTError(w, restStatus(resp.Error.Code), resp.Error.Code, resp.Error.Message)
+		return
+	}
+	if len(resp.Result) == 0 || string(resp.Result) == "null" {
+		writeRESTError(w, http.StatusNotFound, -32000, "not found")
+		return
+	}
+	w.Header().Set("content-type", contentType)
+	w.Write(resp.Result)
+}
+
+// restStatus maps JSON-RPC error codes to HTTP statuses.
+func restStatus(code int) int {
+	switch code {
+	case -32601:
+		return http.StatusNotFound
+	case -32600, -32602, -32700:
+		return http.StatusBadRequest
+	case -32001:
+		return http.StatusForbidden
+	case -32005:
+		return http.StatusTooManyRequests
+	}
+	return http.StatusInternalServerError
+}
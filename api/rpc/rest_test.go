@@ -0,0 +1,157 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+type ChainService struct{}
+
+type RESTBlock struct {
+	Number hexutil.Uint64 `json:"number"`
+	Full   bool           `json:"full"`
+	Parent *RESTBlock     `json:"parent,omitempty"`
+}
+
+func (s *ChainService) GetBlockByNumber(number BlockNumber, fullTx bool) (*RESTBlock, error) {
+	if number > 10 {
+		return nil, nil
+	}
+	return &RESTBlock{Number: hexutil.Uint64(number), Full: fullTx}, nil
+}
+
+func (s *ChainService) GetBalance(address common.Address, number *BlockNumber) (*hexutil.Big, error) {
+	return (*hexutil.Big)(address.Big()), nil
+}
+
+func (s *ChainService) SendTransaction(args string) (string, error) {
+	return args, nil
+}
+
+func TestDiscover(t *testing.T) {
+	server := NewServer()
+	if err := server.RegisterName("eth", new(ChainService)); err != nil {
+		t.Fatal(err)
+	}
+	client := DialInProc(server)
+	defer client.Close()
+
+	var doc OpenRPCDocument
+	if err := client.Call(&doc, "rpc_discover"); err != nil {
+		t.Fatal(err)
+	}
+	var method *OpenRPCMethod
+	for _, m := range doc.Methods {
+		if m.Name == "eth_getBalance" {
+			method = m
+		}
+	}
+	if method == nil {
+		t.Fatalf("eth_getBalance missing from %d methods", len(doc.Methods))
+	}
+	if len(method.Params) != 2 || method.Params[0].Name != "address" || !method.Params[0].Required || method.Params[1].Required {
+		t.Fatalf("wrong params: %+v %+v", method.Params[0], method.Params[1])
+	}
+	if method.Params[0].Schema.Pattern == "" || method.Result.Schema.Type != "string" {
+		t.Fatalf("wrong schemas: %+v %+v", method.Params[0].Schema, method.Result.Schema)
+	}
+	block, ok := doc.Components.Schemas["rpc.RESTBlock"]
+	if !ok {
+		t.Fatalf("missing component schema, have %v", doc.Components.Schemas)
+	}
+	if block.Properties["parent"].Ref != "#/components/schemas/rpc.RESTBlock" || block.Properties["full"].Type != "boolean" {
+		t.Fatalf("wrong struct schema: %+v", block.Properties)
+	}
+}
+
+func TestRESTGateway(t *testing.T) {
+	server := NewServer()
+	if err := server.RegisterName("eth", new(ChainService)); err != nil {
+		t.Fatal(err)
+	}
+	if err := server.EnableREST(DefaultRESTRoutes); err != nil {
+		t.Fatal(err)
+	}
+	hs := httptest.NewServer(server)
+	defer hs.Close()
+
+	tests := []struct {
+		method, path, body string
+		status             int
+		want               string
+	}{
+		{"GET", "/v1/cpc/blocks/5", "", 200, `{"number":"0x5","full":false}`},
+		{"GET", "/v1/cpc/blocks/0x5?fullTx=true", "", 200, `{"number":"0x5","full":true}`},
+		{"GET", "/v1/cpc/blocks/latest", "", 200, `{"number":"0xffffffffffffffff","full":false}`},
+		{"GET", "/v1/cpc/blocks/11", "", 404, ""},
+		{"GET", "/v1/cpc/blocks/nonsense", "", 400, ""},
+		{"GET", "/v1/cpc/accounts/0x0000000000000000000000000000000000000010/balance", "", 200, `"0x10"`},
+		{"GET", "/v1/cpc/transactions/0x00", "", 404, ""}, // not registered
+		{"GET", "/v1/eth/getBlockByNumber?blockNumber=3&arg1=true", "", 200, `{"number":"0x3","full":true}`},
+		{"POST", "/v1/eth/getBlockByNumber", `["0x2", false]`, 200, `{"number":"0x2","full":false}`},
+		{"POST", "/v1/eth/getBalance", `{"address":"0x0000000000000000000000000000000000000001"}`, 200, `"0x1"`},
+		{"GET", "/v1/eth/nonsense", "", 404, ""},
+		{"GET", "/v1/eth/sendTransaction?args=x", "", 405, ""}, // not read-only
+	}
+	for i, tt := range tests {
+		req, _ := http.NewRequest(tt.method, hs.URL+tt.path, strings.NewReader(tt.body))
+		if tt.method == http.MethodPost {
+			req.Header.Set("content-type", contentType)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode != tt.status {
+			t.Errorf("test %d (%s): have status %d, want %d: %s", i, tt.path, resp.StatusCode, tt.status, body)
+			continue
+		}
+		if tt.want != "" && string(body) != tt.want {
+			t.Errorf("test %d (%s): have body %s, want %s", i, tt.path, body, tt.want)
+		}
+	}
+
+	// posts of other content types, such as forms of other origins, are rejected
+	resp, err := http.Post(hs.URL+"/v1/eth/getBlockByNumber", "text/plain", strings.NewReader(`["0x2", false]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnsupportedMediaType {
+		t.Fatalf("text/plain post: have status %d, want %d", resp.StatusCode, http.StatusUnsupportedMediaType)
+	}
+
+	// the gateway serves the discovery document as well
+	resp, err = http.Get(hs.URL + "/v1/openrpc.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("openrpc.json: have status %d", resp.StatusCode)
+	}
+}
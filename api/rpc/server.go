@@ -18,6 +18,7 @@ package rpc
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"reflect"
 	"runtime"
@@ -187,6 +188,14 @@ func (s *Server) serveRequest(ctx context.Context, codec ServerCodec, singleShot
 			}
 			return nil
 		}
+		// reject batches over the limit as a whole
+		if batch && s.limits != nil && !s.limits.batchAllowed(len(reqs)) {
+			codec.Write(codec.CreateErrorResponse(nil, &limitExceededError{fmt.Sprintf("batch of %d requests is too large", len(reqs))}))
+			if singleShot {
+				return nil
+			}
+			continue
+		}
 		// If a single shot request is executing, run and return immediately
 		if singleShot {
 			if batch {
@@ -237,6 +246,9 @@ func (s *Server) Stop() {
 			c.(ServerCodec).Close()
 			return true
 		})
+		if s.auth != nil {
+			s.auth.close()
+		}
 	}
 }
 
@@ -277,6 +289,23 @@ func (s *Server) handle(ctx context.Context, codec ServerCodec, req *serverReque
 		return codec.CreateErrorResponse(&req.id, &invalidParamsError{"Expected subscription id as first argument"}), nil
 	}
 
+	method := req.svcname + serviceMethodSeparator + formatName(req.callb.method.Name)
+	if s.auth != nil {
+		if err := s.auth.authorize(ctx, method); err != nil {
+			return codec.CreateErrorResponse(&req.id, err), nil
+		}
+	}
+	if s.limits != nil {
+		if !s.limits.allow(ctx, method) {
+			return codec.CreateErrorResponse(&req.id, &limitExceededError{"rate limit exceeded"}), nil
+		}
+		release, ok := s.limits.acquire(ctx, method)
+		if !ok {
+			return codec.CreateErrorResponse(&req.id, &limitExceededError{"too many concurrent expensive requests"}), nil
+		}
+		defer release()
+	}
+
 	if req.callb.isSubscribe {
 		subid, err := s.createSubscription(ctx, codec, req)
 		if err != nil {
@@ -320,7 +349,20 @@ func (s *Server) handle(ctx context.Context, codec ServerCodec, req *serverReque
 			return res, nil
 		}
 	}
-	return codec.CreateResponse(req.id, reply[0].Interface()), nil
+	result := reply[0].Interface()
+	if s.limits != nil && s.limits.config.MaxResponseSize > 0 {
+		// encode the result once here to measure it, the codec writes it as is
+		data, err := json.Marshal(result)
+		if err != nil {
+			return codec.CreateErrorResponse(&req.id, &callbackError{err.Error()}), nil
+		}
+		if len(data) > s.limits.config.MaxResponseSize {
+			rpcResponseTooLargeMeter.Mark(1)
+			return codec.CreateErrorResponse(&req.id, &limitExceededError{fmt.Sprintf("response of %d bytes is too large", len(data))}), nil
+		}
+		result = json.RawMessage(data)
+	}
+	return codec.CreateResponse(req.id, result), nil
 }
 
 // exec executes the given request and writes the result back using the codec.
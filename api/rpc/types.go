@@ -74,6 +74,10 @@ type Server struct {
 	run      int32
 	codecsMu sync.Mutex
 	codecs   *set.Set
+
+	auth   *authPolicy  // access control, nil if disabled
+	limits *rateLimiter // request limits, nil if disabled
+	rest   *restGateway // REST gateway of the HTTP handler, nil if disabled
 }
 
 // rpcRequest represents a raw incoming RPC request
@@ -56,7 +56,7 @@ var websocketJSONCodec = websocket.Codec{
 // allowedOrigins should be a comma-separated list of allowed origin URLs.
 // To allow connections with any origin, pass "*".
 func (srv *Server) WebsocketHandler(allowedOrigins []string) http.Handler {
-	return websocket.Server{
+	return newAuthHandler(srv.auth, websocket.Server{
 		Handshake: wsHandshakeValidator(allowedOrigins),
 		Handler: func(conn *websocket.Conn) {
 			// Create a custom encode/decode pair to enforce payload size and number encoding
@@ -68,9 +68,16 @@ func (srv *Server) WebsocketHandler(allowedOrigins []string) http.Handler {
 			decoder := func(v interface{}) error {
 				return websocketJSONCodec.Receive(conn, v)
 			}
-			srv.ServeCodec(NewCodec(conn, encoder, decoder), OptionMethodInvocation|OptionSubscriptions)
+			// carry the identity authenticated during the upgrade over to the calls
+			ctx := context.WithValue(context.Background(), "remote", conn.Request().RemoteAddr)
+			if info, ok := authInfoFromContext(conn.Request().Context()); ok {
+				ctx = context.WithValue(ctx, authInfoKey{}, info)
+			}
+			codec := NewCodec(conn, encoder, decoder)
+			defer codec.Close()
+			srv.serveRequest(ctx, codec, false, OptionMethodInvocation|OptionSubscriptions)
 		},
-	}
+	})
 }
 
 // NewWSServer creates a new websocket RPC server around an API provider.
@@ -18,10 +18,12 @@ package main
 
 import (
 	"fmt"
+	"io/ioutil"
 
 	"bitbucket.org/cpchain/chain/accounts"
 	"bitbucket.org/cpchain/chain/accounts/keystore"
 	"bitbucket.org/cpchain/chain/cmd/cpchain/commons"
+	"bitbucket.org/cpchain/chain/cmd/cpchain/commons/inpututil"
 	"bitbucket.org/cpchain/chain/cmd/cpchain/flags"
 	"bitbucket.org/cpchain/chain/commons/log"
 	"github.com/ethereum/go-ethereum/common"
@@ -57,10 +59,16 @@ Keys are stored under <datadir>/keystore.`,
 					flags.GetByName(flags.DataDirFlagName),
 					flags.GetByName(flags.PasswordFlagName),
 					flags.GetByName(flags.LightKdfFlagName),
+					flags.GetByName(flags.MnemonicFlagName),
+					flags.GetByName(flags.DeriveFlagName),
 				},
 				Description: `Creates a new account and prints the address.
 The account is saved in encrypted format, you are prompted for a password.
-You must remember this password to unlock your account in the future.`,
+You must remember this password to unlock your account in the future.
+
+With --mnemonic an HD wallet is created from a new BIP-39 mnemonic, which is
+printed once. Write it down, it restores all accounts of the wallet with
+account import-mnemonic. --derive sets the number of accounts derived.`,
 			},
 			{
 				Name:      "update",
@@ -104,6 +112,26 @@ You must remember this password to unlock your account in the future.
 For non-interactive use the password can be specified with the --password flag:
     cpchain account import [options] <keyfile>`,
 			},
+			{
+				Name:   "import-mnemonic",
+				Usage:  "Import a BIP-39 mnemonic into a new HD wallet",
+				Action: accountImportMnemonic,
+				Flags: []cli.Flag{
+					flags.GetByName(flags.DataDirFlagName),
+					flags.GetByName(flags.LightKdfFlagName),
+					flags.GetByName(flags.DeriveFlagName),
+				},
+				ArgsUsage: "[<mnemonicFile>]",
+				Description: `cpchain account import-mnemonic [<mnemonicFile>]
+
+Imports a BIP-39 mnemonic and creates an HD wallet, deriving the accounts
+m/44'/60'/0'/0/0, m/44'/60'/0'/0/1, ... Prints their addresses.
+The mnemonic is read from <mnemonicFile> if given, you are prompted for it
+otherwise. The seed is saved in encrypted format, you are prompted for a password.
+--derive sets the number of accounts derived, more can be derived later with
+personal_openWallet and personal_deriveAccount.`,
+			},
+			rotateSignerCommand,
 		},
 	}
 )
@@ -122,6 +150,9 @@ func accountList(ctx *cli.Context) error {
 
 // accountCreate creates a new account into the keystore defined by the CLI flags
 func createAccount(ctx *cli.Context) error {
+	if ctx.Bool(flags.MnemonicFlagName) {
+		return createHDWallet(ctx)
+	}
 	cfg, _ := newConfigNode(ctx)
 	scryptN, scryptP, keydir, err := cfg.Node.AccountConfig()
 	if err != nil {
@@ -136,6 +167,66 @@ func createAccount(ctx *cli.Context) error {
 	return nil
 }
 
+// createHDWallet creates an HD wallet from a new mnemonic.
+func createHDWallet(ctx *cli.Context) error {
+	mnemonic, err := keystore.NewMnemonic()
+	if err != nil {
+		commons.Fatalf("Failed to create mnemonic: %v", err)
+	}
+	fmt.Printf("Mnemonic: %s\n\n", mnemonic)
+	fmt.Println("Write the mnemonic down and keep it safe, it is the only backup of the wallet and is not shown again.")
+	importMnemonic(ctx, mnemonic)
+	return nil
+}
+
+func accountImportMnemonic(ctx *cli.Context) error {
+	var mnemonic string
+	if file := ctx.Args().First(); file != "" {
+		text, err := ioutil.ReadFile(file)
+		if err != nil {
+			log.Fatalf("Failed to read the mnemonic: %v", err)
+		}
+		mnemonic = string(text)
+	} else {
+		text, err := inpututil.Stdin.PromptPassword("Mnemonic: ")
+		if err != nil {
+			log.Fatalf("Failed to read the mnemonic: %v", err)
+		}
+		mnemonic = text
+	}
+	importMnemonic(ctx, mnemonic)
+	return nil
+}
+
+// importMnemonic stores the HD wallet of a mnemonic and derives the number of
+// accounts given by --derive.
+func importMnemonic(ctx *cli.Context, mnemonic string) {
+	count := ctx.Int(flags.DeriveFlagName)
+	if count < 1 {
+		log.Fatalf("At least one account has to be derived")
+	}
+	_, n := newConfigNode(ctx)
+	password, _ := commons.ReadPassword("If your password contains whitespaces, please be careful enough to avoid later confusion.\nPlease give a password.", true)
+	ks := n.AccountManager().Backends(keystore.KeyStoreType)[0].(*keystore.KeyStore)
+	wallet, err := ks.ImportMnemonic(mnemonic, password)
+	if err != nil {
+		commons.Fatalf("Failed to create HD wallet: %v", err)
+	}
+	if count > 1 {
+		if err := wallet.Open(password); err != nil {
+			commons.Fatalf("Failed to open HD wallet: %v", err)
+		}
+		if _, err := wallet.DeriveAccounts(count - 1); err != nil {
+			commons.Fatalf("Failed to derive accounts: %v", err)
+		}
+		wallet.Close()
+	}
+	fmt.Printf("Wallet: %s\n", wallet.URL())
+	for _, account := range wallet.Accounts() {
+		fmt.Printf("Address: {%x}\n", account.Address)
+	}
+}
+
 // accountUpdate transitions an account from a previous format to the current
 // one, also providing the possibility to change the password.
 func accountUpdate(ctx *cli.Context) error {
@@ -0,0 +1,345 @@
+// Copyright 2018 The cpchain authors
+// This file is part of cpchain.
+//
+// cpchain is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// cpchain is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with cpchain. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"bitbucket.org/cpchain/chain/accounts/abi"
+	"bitbucket.org/cpchain/chain/accounts/abi/bind"
+	"bitbucket.org/cpchain/chain/api/cpclient"
+	"bitbucket.org/cpchain/chain/cmd/cpchain/flags"
+	"bitbucket.org/cpchain/chain/configs"
+	"bitbucket.org/cpchain/chain/contracts/proxy"
+	"bitbucket.org/cpchain/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/urfave/cli"
+)
+
+// contractTxTimeout is how long to wait for a contract transaction to be mined.
+const contractTxTimeout = 5 * time.Minute
+
+var contractFlags = append([]cli.Flag{
+	flags.GetByName(flags.DataDirFlagName),
+	flags.GetByName(flags.RunModeFlagName),
+	flags.GetByName(flags.PasswordFlagName),
+	flags.GetByName(flags.UnlockFlagName),
+	flags.GetByName(flags.EndpointFlagName),
+	flags.GetByName(flags.RegisterFlagName),
+}, flags.LogFlags...)
+
+var contractCommand = cli.Command{
+	Name:  "contract",
+	Usage: "Manage contracts behind the proxy contract register",
+	Description: `Upgrade the implementation registered for a proxy contract, roll it back
+to a previous version or list its versions.
+
+Transactions are signed by the --unlock account of the keystore under <datadir>/keystore,
+which must own the proxy contract register, and sent to the node at --endpoint.`,
+	Subcommands: []cli.Command{
+		{
+			Name:      "upgrade",
+			Usage:     "Deploy a new implementation of a proxy contract and register it",
+			Action:    upgradeContract,
+			ArgsUsage: "<proxy address>",
+			Flags: append([]cli.Flag{
+				flags.GetByName(flags.AbiFlagName),
+				flags.GetByName(flags.BinFlagName),
+				flags.GetByName(flags.LayoutFlagName),
+				flags.GetByName(flags.PrevAbiFlagName),
+				flags.GetByName(flags.PrevLayoutFlagName),
+				flags.GetByName(flags.DryCallFlagName),
+				flags.GetByName(flags.ForceFlagName),
+			}, contractFlags...),
+			Description: `The upgrade runs in four steps, aborting at the first failing one:
+
+  1. The new ABI must keep the methods and events of --prevabi, and if both storage
+     layouts are given, the new layout must keep the slots and types of --prevlayout.
+  2. The new implementation is deployed from --bin.
+  3. Every --call is dry run against the registered and the new implementation on the
+     latest state. Nothing is committed, it is what the proxy answers once upgraded.
+  4. The new implementation is registered for the proxy.
+
+--force continues despite incompatibilities and changed dry run results.`,
+		},
+		{
+			Name:      "rollback",
+			Usage:     "Register a previous implementation of a proxy contract again",
+			Action:    rollbackContract,
+			ArgsUsage: "<proxy address> <version>",
+			Flags:     contractFlags,
+			Description: `The implementation of the given version becomes the newest version,
+the history of the proxy is kept.`,
+		},
+		{
+			Name:        "versions",
+			Usage:       "List the implementations registered for a proxy contract",
+			Action:      contractVersions,
+			ArgsUsage:   "<proxy address>",
+			Flags:       contractFlags,
+			Description: `Print every version registered for the proxy, the last one is in use.`,
+		},
+	},
+}
+
+// openProxyRegister connects to the node and binds the proxy contract register,
+// with the unlocked account if it sends transactions.
+func openProxyRegister(ctx *cli.Context, transact bool) (*cpclient.Client, *bind.TransactOpts, *proxy.ProxyContractRegister, error) {
+	_, n := newConfigNode(ctx)
+	opts := new(bind.TransactOpts)
+	if transact {
+		key := unlockAccounts(ctx, n)
+		if key == nil {
+			return nil, nil, nil, errors.New("no account unlocked, set it with --unlock")
+		}
+		opts = bind.NewKeyedTransactor(key.PrivateKey)
+	}
+	client, err := cpclient.Dial(ctx.String(flags.EndpointFlagName))
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	registerAddr := configs.ChainConfigInfo().Dpor.ProxyContractRegister
+	if ctx.IsSet(flags.RegisterFlagName) {
+		if !common.IsHexAddress(ctx.String(flags.RegisterFlagName)) {
+			return nil, nil, nil, fmt.Errorf("invalid register address %q", ctx.String(flags.RegisterFlagName))
+		}
+		registerAddr = common.HexToAddress(ctx.String(flags.RegisterFlagName))
+	}
+	register, err := proxy.NewProxyContractRegister(opts, registerAddr, client)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return client, opts, register, nil
+}
+
+func proxyAddress(ctx *cli.Context) (common.Address, error) {
+	addr := ctx.Args().First()
+	if !common.IsHexAddress(addr) {
+		return common.Address{}, fmt.Errorf("invalid proxy address %q", addr)
+	}
+	return common.HexToAddress(addr), nil
+}
+
+func loadABI(path string) (abi.ABI, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return abi.ABI{}, err
+	}
+	defer file.Close()
+	return abi.JSON(file)
+}
+
+func loadBytecode(path string) ([]byte, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	code := strings.TrimSpace(string(data))
+	if !strings.HasPrefix(code, "0x") {
+		code = "0x" + code
+	}
+	return hexutil.Decode(code)
+}
+
+// registerImplementation waits for the register transaction and checks the
+// implementation is in use, the register ignoring calls not sent by its owner.
+func registerImplementation(client *cpclient.Client, register *proxy.ProxyContractRegister, proxyAddr, realAddr common.Address, tx *types.Transaction) error {
+	ctx, cancel := context.WithTimeout(context.Background(), contractTxTimeout)
+	defer cancel()
+
+	receipt, err := bind.WaitMined(ctx, client, tx)
+	if err != nil {
+		return err
+	}
+	if receipt.Status == types.ReceiptStatusFailed {
+		return fmt.Errorf("register transaction %x failed", tx.Hash())
+	}
+	inUse, err := register.GetRealContract(proxyAddr)
+	if err != nil {
+		return err
+	}
+	if inUse != realAddr {
+		return errors.New("implementation not registered, is the account the owner of the register?")
+	}
+	version, err := register.GetContractVersion(proxyAddr)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Registered %x for proxy %x as version %v\n", realAddr, proxyAddr, version)
+	return nil
+}
+
+func upgradeContract(ctx *cli.Context) error {
+	proxyAddr, err := proxyAddress(ctx)
+	if err != nil {
+		return err
+	}
+	if ctx.String(flags.AbiFlagName) == "" || ctx.String(flags.BinFlagName) == "" {
+		return errors.New("the new implementation is required, set it with --abi and --bin")
+	}
+	nextABI, err := loadABI(ctx.String(flags.AbiFlagName))
+	if err != nil {
+		return err
+	}
+	code, err := loadBytecode(ctx.String(flags.BinFlagName))
+	if err != nil {
+		return err
+	}
+	prevABI := nextABI
+	if path := ctx.String(flags.PrevAbiFlagName); path != "" {
+		if prevABI, err = loadABI(path); err != nil {
+			return err
+		}
+	}
+	var prevLayout, nextLayout *proxy.StorageLayout
+	if path := ctx.String(flags.PrevLayoutFlagName); path != "" {
+		if prevLayout, err = proxy.LoadStorageLayout(path); err != nil {
+			return err
+		}
+	}
+	if path := ctx.String(flags.LayoutFlagName); path != "" {
+		if nextLayout, err = proxy.LoadStorageLayout(path); err != nil {
+			return err
+		}
+	}
+	var calls []proxy.DryCall
+	for _, s := range ctx.StringSlice(flags.DryCallFlagName) {
+		call, err := proxy.ParseDryCall(nextABI, s)
+		if err != nil {
+			return err
+		}
+		calls = append(calls, call)
+	}
+	force := ctx.Bool(flags.ForceFlagName)
+
+	// 1. compatibility
+	if errs := proxy.CheckUpgrade(prevABI, nextABI, prevLayout, nextLayout); len(errs) > 0 {
+		fmt.Println("Incompatibilities with the registered implementation:")
+		for _, err := range errs {
+			fmt.Println("  " + err.Error())
+		}
+		if !force {
+			return errors.New("upgrade aborted, the new implementation is incompatible")
+		}
+	}
+
+	client, opts, register, err := openProxyRegister(ctx, true)
+	if err != nil {
+		return err
+	}
+	current, err := register.GetRealContract(proxyAddr)
+	if err != nil {
+		return err
+	}
+
+	// 2. deployment
+	realAddr, tx, _, err := bind.DeployContract(opts, nextABI, code, client)
+	if err != nil {
+		return err
+	}
+	waitCtx, cancel := context.WithTimeout(context.Background(), contractTxTimeout)
+	defer cancel()
+	if _, err := bind.WaitDeployed(waitCtx, client, tx); err != nil {
+		return err
+	}
+	fmt.Printf("Deployed the new implementation at %x\n", realAddr)
+
+	// 3. dry run
+	if len(calls) > 0 {
+		if current == (common.Address{}) {
+			return errors.New("no implementation registered for the proxy to dry run against")
+		}
+		results, err := proxy.DryRun(context.Background(), client, nextABI, current, realAddr, calls)
+		if err != nil {
+			return err
+		}
+		changed := false
+		for _, r := range results {
+			status := "same"
+			if r.Changed() {
+				status, changed = "CHANGED", true
+			}
+			fmt.Printf("%-8s %s%v\n", status, r.Call.Method, r.Call.Args)
+			fmt.Printf("  current: %x %v\n", r.Current, r.CurrentErr)
+			fmt.Printf("  new:     %x %v\n", r.Next, r.NextErr)
+		}
+		if changed && !force {
+			return fmt.Errorf("upgrade aborted, dry run results changed, %x is not registered", realAddr)
+		}
+	}
+
+	// 4. registration
+	tx, err = register.RegisterPublicKey(proxyAddr, realAddr)
+	if err != nil {
+		return err
+	}
+	return registerImplementation(client, register, proxyAddr, realAddr, tx)
+}
+
+func rollbackContract(ctx *cli.Context) error {
+	proxyAddr, err := proxyAddress(ctx)
+	if err != nil {
+		return err
+	}
+	version, err := strconv.ParseUint(ctx.Args().Get(1), 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid version %q", ctx.Args().Get(1))
+	}
+	client, _, register, err := openProxyRegister(ctx, true)
+	if err != nil {
+		return err
+	}
+	versions, err := register.Versions(proxyAddr)
+	if err != nil {
+		return err
+	}
+	if version == 0 || version > uint64(len(versions)) {
+		return proxy.ErrNoSuchVersion
+	}
+	tx, err := register.Rollback(proxyAddr, version)
+	if err != nil {
+		return err
+	}
+	return registerImplementation(client, register, proxyAddr, versions[version-1], tx)
+}
+
+func contractVersions(ctx *cli.Context) error {
+	proxyAddr, err := proxyAddress(ctx)
+	if err != nil {
+		return err
+	}
+	_, _, register, err := openProxyRegister(ctx, false)
+	if err != nil {
+		return err
+	}
+	versions, err := register.Versions(proxyAddr)
+	if err != nil {
+		return err
+	}
+	for i, addr := range versions {
+		fmt.Printf("Version #%d: %x\n", i+1, addr)
+	}
+	return nil
+}
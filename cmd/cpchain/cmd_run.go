@@ -24,6 +24,7 @@ import (
 	"syscall"
 
 	"bitbucket.org/cpchain/chain/accounts"
+	"bitbucket.org/cpchain/chain/accounts/abi/bind"
 	"bitbucket.org/cpchain/chain/accounts/keystore"
 	"bitbucket.org/cpchain/chain/api/cpclient"
 	"bitbucket.org/cpchain/chain/cmd/cpchain/flags"
@@ -138,11 +139,23 @@ func makePasswordList(ctx *cli.Context) []string {
 	return lines
 }
 
+// unlockAccounts unlocks the accounts of --unlock and returns the key of the first one.
 func unlockAccounts(ctx *cli.Context, n *node.Node) *keystore.Key {
+	keys := unlockKeys(ctx, n)
+	if len(keys) == 0 {
+		return nil
+	}
+	return keys[0]
+}
+
+// unlockKeys unlocks the accounts of --unlock, e.g. the coinbase and the
+// signing key bound to it, and returns their keys in order.
+func unlockKeys(ctx *cli.Context, n *node.Node) []*keystore.Key {
 	ks := n.AccountManager().Backends(keystore.KeyStoreType)[0].(*keystore.KeyStore)
 	passwords := makePasswordList(ctx)
 	unlock := ctx.String("unlock")
 	unlocks := strings.FieldsFunc(unlock, func(c rune) bool { return c == ',' })
+	var keys []*keystore.Key
 	for i, account := range unlocks {
 		// log.Infof("%v, %v\n", i, account)
 		if i < len(passwords) {
@@ -151,17 +164,17 @@ func unlockAccounts(ctx *cli.Context, n *node.Node) *keystore.Key {
 				log.Error("unlock account error", "err", err)
 				return nil
 			}
-			return key
+			keys = append(keys, key)
 		} else {
 			_, _, key, err := unlockAccountWithPrompt(ks, account)
 			if err != nil {
 				log.Error("unlock account error", "err", err)
 				return nil
 			}
-			return key
+			keys = append(keys, key)
 		}
 	}
-	return nil
+	return keys
 }
 
 // TODO @chengxin @xumx please be sure about the underlying logic.
@@ -219,8 +232,14 @@ func setupMining(ctx *cli.Context, n *node.Node, key *keystore.Key) {
 
 	// TODO: fix this, do not use *keystore.Key, use wallet instead
 	contractCaller := createContractCaller(n, key)
-	if contractCaller != nil {
+	if contractCaller != nil && contractCaller.Key != nil {
 		cpchainService.AdmissionApiBackend.SetAdmissionKey(contractCaller.Key)
+	} else if coinbase, err := cpchainService.Coinbase(); err == nil {
+		// the coinbase key is held outside the keystore, e.g. by a PKCS#11 token
+		account := accounts.Account{Address: coinbase}
+		if wallet, err := n.AccountManager().Find(account); err == nil && wallet.URL().Scheme != keystore.KeyStoreScheme {
+			cpchainService.AdmissionApiBackend.SetAdmissionTransactor(bind.NewWalletTransactor(wallet, account))
+		}
 	}
 
 	if ctx.Bool(flags.MineFlagName) {
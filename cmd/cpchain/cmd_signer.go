@@ -0,0 +1,125 @@
+// Copyright 2018 The cpchain authors
+// This file is part of cpchain.
+//
+// cpchain is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// cpchain is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with cpchain. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"bitbucket.org/cpchain/chain/accounts/abi/bind"
+	"bitbucket.org/cpchain/chain/api/cpclient"
+	"bitbucket.org/cpchain/chain/cmd/cpchain/flags"
+	"bitbucket.org/cpchain/chain/configs"
+	"bitbucket.org/cpchain/chain/contracts/dpor/signer_register"
+	"bitbucket.org/cpchain/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/urfave/cli"
+)
+
+// signerRotationDistance is the number of terms between the current term and
+// the earliest term the signer register binds a new signing key from.
+const signerRotationDistance = 4
+
+var rotateSignerCommand = cli.Command{
+	Name:   "rotate-signer",
+	Usage:  "Bind a new signing key to a proposer or validator",
+	Action: rotateSigner,
+	Flags: append([]cli.Flag{
+		flags.GetByName(flags.DataDirFlagName),
+		flags.GetByName(flags.RunModeFlagName),
+		flags.GetByName(flags.PasswordFlagName),
+		flags.GetByName(flags.UnlockFlagName),
+		flags.GetByName(flags.EndpointFlagName),
+		flags.GetByName(flags.SignerRegisterFlagName),
+		flags.GetByName(flags.TermFlagName),
+	}, flags.LogFlags...),
+	Description: `cpchain account rotate-signer --unlock <coinbase>,<signer>
+
+Binds the signer account to the coinbase it campaigned with, from the first block
+of --term on. Both accounts are read from the keystore under <datadir>/keystore,
+their passwords from the first two lines of --password or prompted for.
+
+The coinbase keeps its place in the committee, its blocks, signatures and handshakes
+are signed by the new key from --term on. The node must then run with both
+accounts unlocked: cpchain run --unlock <coinbase>,<signer>.`,
+}
+
+func rotateSigner(ctx *cli.Context) error {
+	_, n := newConfigNode(ctx)
+	keys := unlockKeys(ctx, n)
+	if len(keys) != 2 {
+		return errors.New("the coinbase and the new signing key are required, set them with --unlock <coinbase>,<signer>")
+	}
+	identity, signer := keys[0], keys[1]
+
+	client, err := cpclient.Dial(ctx.String(flags.EndpointFlagName))
+	if err != nil {
+		return err
+	}
+	registerAddr := configs.ChainConfigInfo().Dpor.Contracts[configs.ContractSigner]
+	if ctx.IsSet(flags.SignerRegisterFlagName) {
+		if !common.IsHexAddress(ctx.String(flags.SignerRegisterFlagName)) {
+			return fmt.Errorf("invalid register address %q", ctx.String(flags.SignerRegisterFlagName))
+		}
+		registerAddr = common.HexToAddress(ctx.String(flags.SignerRegisterFlagName))
+	}
+	if registerAddr == (common.Address{}) {
+		return errors.New("no signer register deployed for the runmode, set it with --signerregister")
+	}
+	register, err := signer_register.NewSignerRegister(registerAddr, client)
+	if err != nil {
+		return err
+	}
+
+	term := ctx.Uint64(flags.TermFlagName)
+	if !ctx.IsSet(flags.TermFlagName) {
+		current, err := register.CurrentTerm(nil)
+		if err != nil {
+			return err
+		}
+		// one more term for the transaction to be mined
+		term = current.Uint64() + signerRotationDistance + 1
+	}
+
+	tx, err := register.RotateSignerWithKey(bind.NewKeyedTransactor(identity.PrivateKey), signer.PrivateKey, term)
+	if err != nil {
+		return err
+	}
+	waitCtx, cancel := context.WithTimeout(context.Background(), contractTxTimeout)
+	defer cancel()
+	receipt, err := bind.WaitMined(waitCtx, client, tx)
+	if err != nil {
+		return err
+	}
+	if receipt.Status == types.ReceiptStatusFailed {
+		return fmt.Errorf("rotate transaction %x failed, is --term at least %d terms ahead?", tx.Hash(), signerRotationDistance)
+	}
+
+	signerAddr := crypto.PubkeyToAddress(signer.PrivateKey.PublicKey)
+	bound, err := register.SignerOf(nil, identity.Address, new(big.Int).SetUint64(term))
+	if err != nil {
+		return err
+	}
+	if bound != signerAddr {
+		return fmt.Errorf("signing key not bound, %x signs for %x in term %d", bound, identity.Address, term)
+	}
+	fmt.Printf("Bound signing key %x to %x from term %d\n", signerAddr, identity.Address, term)
+	return nil
+}
@@ -28,6 +28,8 @@ import (
 	"bitbucket.org/cpchain/chain/protocols/cpc/syncer"
 
 	"bitbucket.org/cpchain/chain/accounts"
+	"bitbucket.org/cpchain/chain/accounts/external"
+	"bitbucket.org/cpchain/chain/accounts/pkcs11"
 	"bitbucket.org/cpchain/chain/accounts/keystore"
 	"bitbucket.org/cpchain/chain/cmd/cpchain/flags"
 	"bitbucket.org/cpchain/chain/commons/log"
@@ -188,6 +190,23 @@ func updateRpcConfig(ctx *cli.Context, cfg *node.Config) {
 		log.Infof("HTTPModules:%v", cfg.HTTPModules)
 	}
 
+	if ctx.IsSet(flags.RpcRestFlagName) {
+		cfg.HTTPREST = ctx.Bool(flags.RpcRestFlagName)
+	}
+
+	// graphql setting
+	if ctx.IsSet(flags.GraphQLAddrFlagName) {
+		addr := strings.Split(ctx.String(flags.GraphQLAddrFlagName), ":")
+		if len(addr) != 2 {
+			log.Fatalf("Wrong number of arguments for --%v flag\n", flags.GraphQLAddrFlagName)
+		}
+		cfg.GraphQLHost = addr[0]
+		cfg.GraphQLPort, _ = strconv.Atoi(addr[1])
+	}
+	if ctx.IsSet(flags.GraphQLCorsDomainFlagName) {
+		cfg.GraphQLCors = strings.Split(ctx.String(flags.GraphQLCorsDomainFlagName), ",")
+	}
+
 	// ws is omitted for now
 
 	if ctx.IsSet(flags.RpcCorsDomainFlagName) {
@@ -203,12 +222,24 @@ func updateNodeConfig(ctx *cli.Context, cfg *node.Config) {
 	if ctx.IsSet(flags.LightKdfFlagName) {
 		cfg.UseLightweightKDF = ctx.Bool(flags.LightKdfFlagName)
 	}
+	if ctx.IsSet(flags.SignerFlagName) {
+		cfg.ExternalSigner = ctx.String(flags.SignerFlagName)
+	}
+	if ctx.IsSet(flags.PKCS11ModuleFlagName) {
+		cfg.PKCS11.Module = ctx.String(flags.PKCS11ModuleFlagName)
+	}
+	if ctx.IsSet(flags.PKCS11TokenFlagName) {
+		cfg.PKCS11.Token = ctx.String(flags.PKCS11TokenFlagName)
+	}
+	if ctx.IsSet(flags.PKCS11PinFileFlagName) {
+		cfg.PKCS11.PinFile = ctx.String(flags.PKCS11PinFileFlagName)
+	}
 }
 
 // begin chain configs
 
 // Updates the account for cfg.Coinbase
-func updateBaseAccount(ctx *cli.Context, ks *keystore.KeyStore, cfg *cpc.Config) {
+func updateBaseAccount(ctx *cli.Context, am *accounts.Manager, cfg *cpc.Config) {
 	if ctx.IsSet("account") {
 		val := ctx.String("account")
 		if !common.IsHexAddress(val) {
@@ -218,8 +249,22 @@ func updateBaseAccount(ctx *cli.Context, ks *keystore.KeyStore, cfg *cpc.Config)
 		cfg.Cpcbase = account.Address
 	} else {
 		isRunCommand := ctx.Command.Name == runCommand.Name
-		// fall back on the first account
-		accs := ks.Accounts()
+		// fall back on the first account, preferring a PKCS#11 token, which
+		// is only there if configured, over the key store and the key store
+		// over an external signer
+		var accs []accounts.Account
+		for _, backend := range am.Backends(pkcs11.BackendType) {
+			for _, wallet := range backend.Wallets() {
+				accs = append(accs, wallet.Accounts()...)
+			}
+		}
+		ks := am.Backends(keystore.KeyStoreType)[0].(*keystore.KeyStore)
+		accs = append(accs, ks.Accounts()...)
+		for _, backend := range am.Backends(external.BackendType) {
+			for _, wallet := range backend.Wallets() {
+				accs = append(accs, wallet.Accounts()...)
+			}
+		}
 		if len(accs) > 0 {
 			account := accs[0].Address
 			cfg.Cpcbase = account
@@ -269,12 +314,12 @@ func updateChainGeneralConfig(ctx *cli.Context, cfg *cpc.Config) {
 func updateChainConfig(ctx *cli.Context, cfg *cpc.Config, n *node.Node) {
 	updateChainGeneralConfig(ctx, cfg)
 	// passing in a node, all for this.  a pity.
-	ks := n.AccountManager().Backends(keystore.KeyStoreType)[0].(*keystore.KeyStore)
-	updateBaseAccount(ctx, ks, cfg)
+	updateBaseAccount(ctx, n.AccountManager(), cfg)
 	// setGPO(ctx, &cfg.GPO)
 	updateTxPool(ctx, &cfg.TxPool)
 	updateDatabaseCache(ctx, cfg)
 	updateTrieCache(ctx, cfg)
+	updateRewardIndex(ctx, cfg)
 }
 
 // updateDatabaseCache updates database cache.
@@ -291,6 +336,13 @@ func updateTrieCache(ctx *cli.Context, cfg *cpc.Config) {
 	}
 }
 
+// updateRewardIndex enables the reward history index.
+func updateRewardIndex(ctx *cli.Context, cfg *cpc.Config) {
+	if ctx.IsSet(flags.RewardIndexFlagName) {
+		cfg.RewardIndex = ctx.Bool(flags.RewardIndexFlagName)
+	}
+}
+
 // updateTrieCache updates trie cache.
 func updateSyncModeFlag(ctx *cli.Context, cfg *cpc.Config) {
 	if ctx.IsSet(flags.FastSyncFlagName) {
@@ -40,8 +40,10 @@ func init() {
 	Register(ConfigFileFlag)
 	Register(GeneralFlags...)
 	Register(AccountFlags...)
+	Register(HDWalletFlags...)
 	Register(ChainFlags...)
 	Register(RpcFlags...)
+	Register(ContractFlags...)
 	Register(MiscFlags...)
 }
 
@@ -97,6 +99,11 @@ const (
 	PasswordFlagName = "password"
 	LightKdfFlagName = "lightkdf"
 	UnlockFlagName   = "unlock"
+	SignerFlagName   = "signer"
+
+	PKCS11ModuleFlagName  = "pkcs11.module"
+	PKCS11TokenFlagName   = "pkcs11.token"
+	PKCS11PinFileFlagName = "pkcs11.pin"
 )
 
 var AccountFlags = []cli.Flag{
@@ -115,6 +122,44 @@ var AccountFlags = []cli.Flag{
 		Usage: "Comma separated list of accounts to unlock",
 		Value: "",
 	},
+	cli.StringFlag{
+		Name:  SignerFlagName,
+		Usage: "IPC path or http(s) URL of an external signer holding the account keys",
+		Value: "",
+	},
+	cli.StringFlag{
+		Name:  PKCS11ModuleFlagName,
+		Usage: "PKCS#11 library of the token holding the account keys, e.g. /usr/lib/softhsm/libsofthsm2.so",
+		Value: "",
+	},
+	cli.StringFlag{
+		Name:  PKCS11TokenFlagName,
+		Usage: "Label of the PKCS#11 token, the first token of the library if not set",
+		Value: "",
+	},
+	cli.StringFlag{
+		Name:  PKCS11PinFileFlagName,
+		Usage: "File holding the user PIN of the PKCS#11 token",
+		Value: "",
+	},
+}
+
+const (
+	MnemonicFlagName = "mnemonic"
+	DeriveFlagName   = "derive"
+)
+
+// HDWalletFlags are only used by the account commands.
+var HDWalletFlags = []cli.Flag{
+	cli.BoolFlag{
+		Name:  MnemonicFlagName,
+		Usage: "Create an HD wallet from a new BIP-39 mnemonic instead of a single key",
+	},
+	cli.IntFlag{
+		Name:  DeriveFlagName,
+		Usage: "Number of accounts of the HD wallet to derive",
+		Value: 1,
+	},
 }
 
 const (
@@ -124,6 +169,7 @@ const (
 	CacheDatabaseFlagName = "cache.database"
 	CacheGCFlagName       = "cache.gc"
 	MaxTxMapSizeFlagName  = "txpoolsize"
+	RewardIndexFlagName   = "rewardindex"
 )
 
 var ChainFlags = []cli.Flag{
@@ -159,6 +205,10 @@ var ChainFlags = []cli.Flag{
 		Usage: "Maximum number of pending transactions",
 		Value: 1024,
 	},
+	cli.BoolFlag{
+		Name:  RewardIndexFlagName,
+		Usage: "Index the reward history of addresses for the reward RPC API",
+	},
 }
 
 const (
@@ -189,8 +239,11 @@ var SyncFlags = []cli.Flag{
 }
 
 const (
-	IpcAddrFlagName = "ipcaddr"
-	RpcAddrFlagName = "rpcaddr"
+	IpcAddrFlagName           = "ipcaddr"
+	RpcAddrFlagName           = "rpcaddr"
+	GraphQLAddrFlagName       = "graphqladdr"
+	GraphQLCorsDomainFlagName = "graphqlcorsdomain"
+	RpcRestFlagName           = "rpcrest"
 	// these two flags should be removed in the future
 	RpcCorsDomainFlagName = "rpccorsdomain"
 	RpcApiFlagName        = "rpcapi"
@@ -212,12 +265,87 @@ var RpcFlags = []cli.Flag{
 		Name:  RpcAddrFlagName,
 		Usage: "RPC address whose format is <host:port>",
 	},
+	cli.BoolFlag{
+		Name:  RpcRestFlagName,
+		Usage: "Enable the REST gateway under /v1/ on the HTTP-RPC interface",
+	},
+	cli.StringFlag{
+		Name:  GraphQLAddrFlagName,
+		Usage: "GraphQL address whose format is <host:port>, the GraphQL server is disabled if not set",
+	},
+	cli.StringFlag{
+		Name:  GraphQLCorsDomainFlagName,
+		Usage: "Comma separated list of domains from which to accept cross origin GraphQL requests (browser enforced)",
+	},
 	cli.StringFlag{
 		Name:  RpcCorsDomainFlagName,
 		Usage: "Comma separated list of domains from which to accept cross origin requests (browser enforced)",
 	},
 }
 
+const (
+	EndpointFlagName   = "endpoint"
+	RegisterFlagName   = "register"
+	AbiFlagName        = "abi"
+	BinFlagName        = "bin"
+	LayoutFlagName     = "layout"
+	PrevAbiFlagName    = "prevabi"
+	PrevLayoutFlagName = "prevlayout"
+	DryCallFlagName    = "call"
+	ForceFlagName      = "force"
+
+	SignerRegisterFlagName = "signerregister"
+	TermFlagName           = "term"
+)
+
+var ContractFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:  EndpointFlagName,
+		Usage: "IPC path or http(s) URL of the node to send the contract transactions to",
+		Value: "http://127.0.0.1:8545",
+	},
+	cli.StringFlag{
+		Name:  RegisterFlagName,
+		Usage: "Address of the proxy contract register (default: the one of the runmode)",
+	},
+	cli.StringFlag{
+		Name:  AbiFlagName,
+		Usage: "ABI file of the new implementation",
+	},
+	cli.StringFlag{
+		Name:  BinFlagName,
+		Usage: "Hex encoded bytecode file of the new implementation",
+	},
+	cli.StringFlag{
+		Name:  LayoutFlagName,
+		Usage: "Storage layout file (solc --storage-layout) of the new implementation",
+	},
+	cli.StringFlag{
+		Name:  PrevAbiFlagName,
+		Usage: "ABI file of the registered implementation (default: the new ABI)",
+	},
+	cli.StringFlag{
+		Name:  PrevLayoutFlagName,
+		Usage: "Storage layout file of the registered implementation",
+	},
+	cli.StringSliceFlag{
+		Name:  DryCallFlagName,
+		Usage: "Constant call, written method(arg1,arg2), dry run against both implementations before registering",
+	},
+	cli.BoolFlag{
+		Name:  ForceFlagName,
+		Usage: "Register the new implementation despite incompatibilities and changed dry run results",
+	},
+	cli.StringFlag{
+		Name:  SignerRegisterFlagName,
+		Usage: "Address of the signer register contract (default: the one of the runmode)",
+	},
+	cli.Uint64Flag{
+		Name:  TermFlagName,
+		Usage: "Term from which the new signing key signs (default: the earliest term the register accepts, plus one)",
+	},
+}
+
 const (
 	MaxPeersFlagName        = "maxpeers"
 	MaxPendingPeersFlagName = "maxpendpeers"
@@ -50,6 +50,7 @@ func newApp() *cli.App {
 		runCommand,
 		dumpConfigCommand,
 		chainCommand,
+		contractCommand,
 	}
 
 	// global flags
@@ -18,6 +18,15 @@ var (
 
 	insertionElapsedTime = prometheus.NewGauge(prometheus.GaugeOpts{Name: "cpchain_insertion_elapsed_time",
 		Help: "current insertion elapsed time."})
+
+	gasLimitCounter = prometheus.NewGauge(prometheus.GaugeOpts{Name: "cpchain_gas_limit",
+		Help: "current gasLimit."})
+
+	gasUsedCounter = prometheus.NewGauge(prometheus.GaugeOpts{Name: "cpchain_gas_used",
+		Help: "current gasUsed."})
+
+	blockIntervalCounter = prometheus.NewGauge(prometheus.GaugeOpts{Name: "cpchain_block_interval",
+		Help: "current interval to the parent block in milliseconds."})
 )
 
 // configuration items
@@ -55,6 +64,21 @@ func ReportInsertionElapsedTime(exportedJob string, elapsed float64) {
 	reportGauge(gatewayAddress, exportedJob, chainId, insertionElapsedTime)
 }
 
+func ReportGasLimitGauge(exportedJob string, gasLimit float64) {
+	gasLimitCounter.Set(gasLimit)
+	reportGauge(gatewayAddress, exportedJob, chainId, gasLimitCounter)
+}
+
+func ReportGasUsedGauge(exportedJob string, gasUsed float64) {
+	gasUsedCounter.Set(gasUsed)
+	reportGauge(gatewayAddress, exportedJob, chainId, gasUsedCounter)
+}
+
+func ReportBlockIntervalGauge(exportedJob string, interval float64) {
+	blockIntervalCounter.Set(interval)
+	reportGauge(gatewayAddress, exportedJob, chainId, blockIntervalCounter)
+}
+
 func reportGauge(monitorURL, exportedJob, host string, gauge prometheus.Gauge) {
 	if err := push.New(monitorURL, exportedJob).
 		Collector(gauge).
@@ -48,6 +48,12 @@ var (
 			Contracts:             devContractAddressMap,
 			ImpeachTimeout:        time.Millisecond * DefaultBlockPeriod * 10,
 		},
+		RptMethod2Block: big.NewInt(rptMethod2Block),
+		RptMethod3Block: big.NewInt(rptMethod3Block),
+		RptMethod4Block: big.NewInt(rptMethod4Block),
+		RptMethod5Block: big.NewInt(rptMethod5Block),
+		Campaign2Block:  big.NewInt(campaign2Block),
+		Campaign3Block:  big.NewInt(campaign3Block),
 	}
 
 	devProposers = []common.Address{
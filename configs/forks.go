@@ -0,0 +1,215 @@
+// Copyright 2018 The cpchain authors
+// Copyright 2016 The go-ethereum Authors
+
+package configs
+
+import "math/big"
+
+// forkBlock returns the configured block of a fork, or the mainnet block if
+// the config predates the fork schedule and leaves it unset.
+func forkBlock(configured *big.Int, mainnet uint64) *big.Int {
+	if configured != nil {
+		return configured
+	}
+	return new(big.Int).SetUint64(mainnet)
+}
+
+// RptMethod2BlockNumber returns the block rpt collector 2 activates at.
+func (c *ChainConfig) RptMethod2BlockNumber() *big.Int {
+	return forkBlock(c.RptMethod2Block, rptMethod2Block)
+}
+
+// RptMethod3BlockNumber returns the block rpt collector 3 activates at.
+func (c *ChainConfig) RptMethod3BlockNumber() *big.Int {
+	return forkBlock(c.RptMethod3Block, rptMethod3Block)
+}
+
+// RptMethod4BlockNumber returns the block rpt collector 4 activates at.
+func (c *ChainConfig) RptMethod4BlockNumber() *big.Int {
+	return forkBlock(c.RptMethod4Block, rptMethod4Block)
+}
+
+// RptMethod5BlockNumber returns the block rpt collector 5 activates at.
+func (c *ChainConfig) RptMethod5BlockNumber() *big.Int {
+	return forkBlock(c.RptMethod5Block, rptMethod5Block)
+}
+
+// Campaign2BlockNumber returns the block the campaign2 contract takes over
+// the candidates at.
+func (c *ChainConfig) Campaign2BlockNumber() *big.Int {
+	return forkBlock(c.Campaign2Block, campaign2Block)
+}
+
+// Campaign3BlockNumber returns the block the campaign3 contract takes over
+// the candidates at.
+func (c *ChainConfig) Campaign3BlockNumber() *big.Int {
+	return forkBlock(c.Campaign3Block, campaign3Block)
+}
+
+// SlashingBlockNumber returns the block offline proposers are penalised from,
+// nil unless slashing is configured.
+func (c *ChainConfig) SlashingBlockNumber() *big.Int {
+	if c.Dpor != nil && c.Dpor.Slashing != nil {
+		return c.Dpor.Slashing.Block
+	}
+	return nil
+}
+
+// GasLimitVotingBlockNumber returns the block the gas limit votes are bounded
+// from, nil unless the gas limit voting is configured.
+func (c *ChainConfig) GasLimitVotingBlockNumber() *big.Int {
+	if c.Dpor != nil && c.Dpor.GasLimit != nil {
+		return c.Dpor.GasLimit.Block
+	}
+	return nil
+}
+
+// SignerRotationBlockNumber returns the block the headers carry the signing
+// keys of the committees from, nil unless key rotation is configured.
+func (c *ChainConfig) SignerRotationBlockNumber() *big.Int {
+	if c.Dpor != nil {
+		return c.Dpor.SignerRotationBlock
+	}
+	return nil
+}
+
+// PeriodGovernorBlockNumber returns the block the headers carry the governed
+// block period of the next term from, nil unless the period governor is configured.
+func (c *ChainConfig) PeriodGovernorBlockNumber() *big.Int {
+	if c.Dpor != nil {
+		return c.Dpor.PeriodGovernorBlock
+	}
+	return nil
+}
+
+// IsRptMethod2 returns whether num is either equal to the rpt method 2 fork block or greater.
+func (c *ChainConfig) IsRptMethod2(num *big.Int) bool {
+	return isForked(c.RptMethod2BlockNumber(), num)
+}
+
+// IsRptMethod3 returns whether num is either equal to the rpt method 3 fork block or greater.
+func (c *ChainConfig) IsRptMethod3(num *big.Int) bool {
+	return isForked(c.RptMethod3BlockNumber(), num)
+}
+
+// IsRptMethod4 returns whether num is either equal to the rpt method 4 fork block or greater.
+func (c *ChainConfig) IsRptMethod4(num *big.Int) bool {
+	return isForked(c.RptMethod4BlockNumber(), num)
+}
+
+// IsRptMethod5 returns whether num is either equal to the rpt method 5 fork block or greater.
+func (c *ChainConfig) IsRptMethod5(num *big.Int) bool {
+	return isForked(c.RptMethod5BlockNumber(), num)
+}
+
+// IsCampaign2 returns whether num is either equal to the campaign2 fork block or greater.
+func (c *ChainConfig) IsCampaign2(num *big.Int) bool {
+	return isForked(c.Campaign2BlockNumber(), num)
+}
+
+// IsCampaign3 returns whether num is either equal to the campaign3 fork block or greater.
+func (c *ChainConfig) IsCampaign3(num *big.Int) bool {
+	return isForked(c.Campaign3BlockNumber(), num)
+}
+
+// IsSlashing returns whether num is either equal to the slashing fork block or greater.
+func (c *ChainConfig) IsSlashing(num *big.Int) bool {
+	return isForked(c.SlashingBlockNumber(), num)
+}
+
+// IsGasLimitVoting returns whether num is either equal to the gas limit voting fork block or greater.
+func (c *ChainConfig) IsGasLimitVoting(num *big.Int) bool {
+	return isForked(c.GasLimitVotingBlockNumber(), num)
+}
+
+// IsSignerRotation returns whether num is either equal to the signer rotation fork block or greater.
+func (c *ChainConfig) IsSignerRotation(num *big.Int) bool {
+	return isForked(c.SignerRotationBlockNumber(), num)
+}
+
+// IsPeriodGoverned returns whether num is either equal to the period governor fork block or greater.
+func (c *ChainConfig) IsPeriodGoverned(num *big.Int) bool {
+	return isForked(c.PeriodGovernorBlockNumber(), num)
+}
+
+// CheckCompatible checks whether scheduled fork transitions have been imported
+// with a mismatching chain configuration.
+func (c *ChainConfig) CheckCompatible(newcfg *ChainConfig, height uint64) *ConfigCompatError {
+	bhead := new(big.Int).SetUint64(height)
+
+	// Iterate checkCompatible to find the lowest conflict.
+	var lasterr *ConfigCompatError
+	for {
+		err := c.checkCompatible(newcfg, bhead)
+		if err == nil || (lasterr != nil && err.RewindTo == lasterr.RewindTo) {
+			break
+		}
+		lasterr = err
+		bhead.SetUint64(err.RewindTo)
+	}
+	return lasterr
+}
+
+func (c *ChainConfig) checkCompatible(newcfg *ChainConfig, head *big.Int) *ConfigCompatError {
+	forks := []struct {
+		what             string
+		stored, newblock *big.Int
+	}{
+		{"rpt method 2 fork block", c.RptMethod2BlockNumber(), newcfg.RptMethod2BlockNumber()},
+		{"rpt method 3 fork block", c.RptMethod3BlockNumber(), newcfg.RptMethod3BlockNumber()},
+		{"rpt method 4 fork block", c.RptMethod4BlockNumber(), newcfg.RptMethod4BlockNumber()},
+		{"rpt method 5 fork block", c.RptMethod5BlockNumber(), newcfg.RptMethod5BlockNumber()},
+		{"campaign2 fork block", c.Campaign2BlockNumber(), newcfg.Campaign2BlockNumber()},
+		{"campaign3 fork block", c.Campaign3BlockNumber(), newcfg.Campaign3BlockNumber()},
+		{"slashing fork block", c.SlashingBlockNumber(), newcfg.SlashingBlockNumber()},
+		{"gas limit voting fork block", c.GasLimitVotingBlockNumber(), newcfg.GasLimitVotingBlockNumber()},
+		{"signer rotation fork block", c.SignerRotationBlockNumber(), newcfg.SignerRotationBlockNumber()},
+		{"period governor fork block", c.PeriodGovernorBlockNumber(), newcfg.PeriodGovernorBlockNumber()},
+	}
+	for _, fork := range forks {
+		if isForkIncompatible(fork.stored, fork.newblock, head) {
+			return newCompatError(fork.what, fork.stored, fork.newblock)
+		}
+	}
+	return nil
+}
+
+// isForkIncompatible returns true if a fork scheduled at s1 cannot be rescheduled to
+// block s2 because head is already past the fork.
+func isForkIncompatible(s1, s2, head *big.Int) bool {
+	return (isForked(s1, head) || isForked(s2, head)) && !configNumEqual(s1, s2)
+}
+
+// configNumEqual returns whether two fork blocks are the same, an unscheduled
+// fork is only equal to another unscheduled one.
+func configNumEqual(x, y *big.Int) bool {
+	if x == nil || y == nil {
+		return x == y
+	}
+	return x.Cmp(y) == 0
+}
+
+// isForked returns whether a fork scheduled at block s is active at the given head block.
+func isForked(s, head *big.Int) bool {
+	if s == nil || head == nil {
+		return false
+	}
+	return s.Cmp(head) <= 0
+}
+
+func newCompatError(what string, storedblock, newblock *big.Int) *ConfigCompatError {
+	var rew *big.Int
+	switch {
+	case storedblock == nil:
+		rew = newblock
+	case newblock == nil || storedblock.Cmp(newblock) < 0:
+		rew = storedblock
+	default:
+		rew = newblock
+	}
+	err := &ConfigCompatError{what, storedblock, newblock, 0}
+	if rew != nil && rew.Sign() > 0 {
+		err.RewindTo = rew.Uint64() - 1
+	}
+	return err
+}
@@ -0,0 +1,115 @@
+// Copyright 2018 The cpchain authors
+
+package configs
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestForkScheduleDefaults(t *testing.T) {
+	// configs written before the fork schedule follow mainnet
+	cc := ChainConfig{}
+	assert.Equal(t, big.NewInt(rptMethod2Block), cc.RptMethod2BlockNumber())
+	assert.Equal(t, big.NewInt(campaign3Block), cc.Campaign3BlockNumber())
+	assert.Nil(t, cc.CheckCompatible(mainnetChainConfig, rptMethod5Block+1))
+
+	rules := cc.Rules(big.NewInt(rptMethod2Block - 1))
+	assert.False(t, rules.IsRptMethod2)
+	rules = cc.Rules(big.NewInt(rptMethod2Block))
+	assert.True(t, rules.IsRptMethod2)
+	assert.False(t, rules.IsRptMethod3)
+}
+
+func TestForkScheduleJSON(t *testing.T) {
+	var cc ChainConfig
+	err := json.Unmarshal([]byte(`{"chainId": 41, "rptMethod2Block": 0, "campaign2Block": 100}`), &cc)
+	assert.Nil(t, err)
+
+	rules := cc.Rules(big.NewInt(0))
+	assert.True(t, rules.IsRptMethod2)
+	assert.False(t, rules.IsRptMethod3)
+	assert.False(t, rules.IsCampaign2)
+	assert.True(t, cc.Rules(big.NewInt(100)).IsCampaign2)
+}
+
+func TestCheckCompatible(t *testing.T) {
+	stored := &ChainConfig{Campaign2Block: big.NewInt(100), Campaign3Block: big.NewInt(200)}
+
+	tests := []struct {
+		newcfg  *ChainConfig
+		head    uint64
+		wantErr *ConfigCompatError
+	}{
+		{newcfg: stored, head: 0},
+		{newcfg: stored, head: 1000},
+		// forks ahead of the head can be rescheduled
+		{newcfg: &ChainConfig{Campaign2Block: big.NewInt(100), Campaign3Block: big.NewInt(300)}, head: 150},
+		{
+			newcfg:  &ChainConfig{Campaign2Block: big.NewInt(100), Campaign3Block: big.NewInt(300)},
+			head:    250,
+			wantErr: &ConfigCompatError{What: "campaign3 fork block", StoredConfig: big.NewInt(200), NewConfig: big.NewInt(300), RewindTo: 199},
+		},
+		// the lowest conflict is reported
+		{
+			newcfg:  &ChainConfig{Campaign2Block: big.NewInt(50), Campaign3Block: big.NewInt(300)},
+			head:    250,
+			wantErr: &ConfigCompatError{What: "campaign2 fork block", StoredConfig: big.NewInt(100), NewConfig: big.NewInt(50), RewindTo: 49},
+		},
+		// an unset fork is the mainnet one
+		{
+			newcfg:  &ChainConfig{Campaign2Block: big.NewInt(100), Campaign3Block: big.NewInt(200), RptMethod2Block: big.NewInt(0)},
+			head:    10,
+			wantErr: &ConfigCompatError{What: "rpt method 2 fork block", StoredConfig: big.NewInt(rptMethod2Block), NewConfig: big.NewInt(0), RewindTo: 0},
+		},
+	}
+	for i, tt := range tests {
+		err := stored.CheckCompatible(tt.newcfg, tt.head)
+		assert.Equal(t, tt.wantErr, err, "test %d", i)
+	}
+}
+
+func TestCheckCompatibleSlashing(t *testing.T) {
+	unscheduled := &ChainConfig{Dpor: &DporConfig{}}
+	scheduled := &ChainConfig{Dpor: &DporConfig{Slashing: &SlashingConfig{Block: big.NewInt(100)}}}
+
+	assert.False(t, unscheduled.IsSlashing(big.NewInt(1000)))
+	assert.True(t, scheduled.IsSlashing(big.NewInt(100)))
+	assert.True(t, scheduled.Dpor.IsSlashing(big.NewInt(100)))
+
+	// scheduling slashing ahead of the head is compatible
+	assert.Nil(t, unscheduled.CheckCompatible(scheduled, 50))
+	assert.Nil(t, unscheduled.CheckCompatible(unscheduled, 1000))
+	assert.Equal(t, &ConfigCompatError{What: "slashing fork block", NewConfig: big.NewInt(100), RewindTo: 99},
+		unscheduled.CheckCompatible(scheduled, 150))
+	assert.Equal(t, &ConfigCompatError{What: "slashing fork block", StoredConfig: big.NewInt(100), RewindTo: 99},
+		scheduled.CheckCompatible(unscheduled, 150))
+}
+
+func TestCheckCompatibleGasLimitVoting(t *testing.T) {
+	unscheduled := &ChainConfig{Dpor: &DporConfig{}}
+	scheduled := &ChainConfig{Dpor: &DporConfig{GasLimit: &GasLimitConfig{Block: big.NewInt(100)}}}
+
+	assert.False(t, unscheduled.IsGasLimitVoting(big.NewInt(1000)))
+	assert.True(t, scheduled.Dpor.IsGasLimitVoting(big.NewInt(100)))
+
+	assert.Nil(t, unscheduled.CheckCompatible(scheduled, 50))
+	assert.Equal(t, &ConfigCompatError{What: "gas limit voting fork block", NewConfig: big.NewInt(100), RewindTo: 99},
+		unscheduled.CheckCompatible(scheduled, 150))
+}
+
+func TestCheckCompatiblePeriodGovernor(t *testing.T) {
+	unscheduled := &ChainConfig{Dpor: &DporConfig{}}
+	scheduled := &ChainConfig{Dpor: &DporConfig{PeriodGovernorBlock: big.NewInt(100)}}
+
+	assert.False(t, unscheduled.IsPeriodGoverned(big.NewInt(1000)))
+	assert.False(t, scheduled.IsPeriodGoverned(big.NewInt(99)))
+	assert.True(t, scheduled.Dpor.IsPeriodGoverned(big.NewInt(100)))
+
+	assert.Nil(t, unscheduled.CheckCompatible(scheduled, 50))
+	assert.Equal(t, &ConfigCompatError{What: "period governor fork block", NewConfig: big.NewInt(100), RewindTo: 99},
+		unscheduled.CheckCompatible(scheduled, 150))
+}
@@ -102,21 +102,24 @@ const (
 	ContractRnode      = "rnode"      // address of rnode
 	ContractCampaign2  = "campaign2"  // address of campaign2
 	ContractCampaign3  = "campaign3"  // address of campaign3
+	ContractSigner     = "signer"     // address of signer_register contract, bind new signing keys to proposers and validators
+	ContractPeriod     = "period"     // address of period_governor contract, schedule block periods of future terms
 )
 
 const (
 	RNodeMinFundReq = 200000 // 200000 CPC for becoming a RNode
 )
 
-// pivot blocks numbers
+// fork blocks of mainnet, they are also used by chain configs written before
+// the fork schedule became part of ChainConfig, see forkBlock.
 const (
-	RptCalcMethod2BlockNumber = 343000
-	RptCalcMethod3BlockNumber = 372400
-	RptCalcMethod4BlockNumber = 390500
-	RptCalcMethod5BlockNumber = 398000
+	rptMethod2Block = 343000
+	rptMethod3Block = 372400
+	rptMethod4Block = 390500
+	rptMethod5Block = 398000
 
-	Campaign2BlockNumber = 371900
-	Campaign3BlockNumber = 390500
+	campaign2Block = 371900
+	campaign3Block = 390500
 )
 
 var (
@@ -151,7 +154,7 @@ var (
 
 var (
 	// just for test
-	TestChainConfig = &ChainConfig{big.NewInt(DevChainId), &DporConfig{Period: 0, TermLen: 4}}
+	TestChainConfig = &ChainConfig{ChainID: big.NewInt(DevChainId), Dpor: &DporConfig{Period: 0, TermLen: 4}}
 )
 
 // this contains all the changes we have made to the cpchain protocol.
@@ -305,6 +308,15 @@ type ChainConfig struct {
 
 	// Various consensus engines
 	Dpor *DporConfig `json:"dpor,omitempty" toml:"dpor,omitempty"`
+
+	// Fork schedule, the block numbers the protocol upgrades activate at.
+	// A nil block falls back to the mainnet one.
+	RptMethod2Block *big.Int `json:"rptMethod2Block,omitempty" toml:"rptMethod2Block,omitempty"` // Rpt calculated by rpt collector 2
+	RptMethod3Block *big.Int `json:"rptMethod3Block,omitempty" toml:"rptMethod3Block,omitempty"` // Rpt calculated by rpt collector 3
+	RptMethod4Block *big.Int `json:"rptMethod4Block,omitempty" toml:"rptMethod4Block,omitempty"` // Rpt calculated by rpt collector 4
+	RptMethod5Block *big.Int `json:"rptMethod5Block,omitempty" toml:"rptMethod5Block,omitempty"` // Rpt calculated by rpt collector 5
+	Campaign2Block  *big.Int `json:"campaign2Block,omitempty"  toml:"campaign2Block,omitempty"`  // Candidates read from campaign2 contract
+	Campaign3Block  *big.Int `json:"campaign3Block,omitempty"  toml:"campaign3Block,omitempty"`  // Candidates read from campaign3 contract
 }
 
 // DporConfig is the consensus engine configs for proof-of-authority based sealing.
@@ -317,6 +329,62 @@ type DporConfig struct {
 	Contracts             map[string]common.Address `json:"contracts"             toml:"contracts"`
 	ProxyContractRegister common.Address            `json:"proxyContractRegister" toml:"proxyContractRegister"`
 	ImpeachTimeout        time.Duration             `json:"impeachTimeout" toml:"impeachTimeout"`
+	Slashing              *SlashingConfig           `json:"slashing,omitempty" toml:"slashing,omitempty"`                       // Penalties of offline proposers, nil disables them
+	GasLimit              *GasLimitConfig           `json:"gasLimit,omitempty" toml:"gasLimit,omitempty"`                       // Gas limit voting of proposers, nil leaves it to the miners
+	SignerRotationBlock   *big.Int                  `json:"signerRotationBlock,omitempty" toml:"signerRotationBlock,omitempty"` // Block the headers carry the signing keys of the committees from, nil disables key rotation
+	PeriodGovernorBlock   *big.Int                  `json:"periodGovernorBlock,omitempty" toml:"periodGovernorBlock,omitempty"` // Block the headers carry the block period of the next term governed by the period governor from, nil keeps Period
+}
+
+// SlashingConfig is the penalties of proposers impeached for failing to
+// propose their blocks.
+type SlashingConfig struct {
+	Block          *big.Int `json:"block"          toml:"block"`          // Block the offences are recorded and penalised from, nil disables them
+	ImpeachPenalty *big.Int `json:"impeachPenalty" toml:"impeachPenalty"` // Deposit deducted per impeachment, times the offences of the proposer in the window
+	OffenceWindow  uint64   `json:"offenceWindow"  toml:"offenceWindow"`  // Number of terms an offence is counted for
+	BarThreshold   uint64   `json:"barThreshold"   toml:"barThreshold"`   // Number of offences in the window barring the proposer from candidacy, 0 never bars
+	BarTerms       uint64   `json:"barTerms"       toml:"barTerms"`       // Number of elected terms a barred proposer is left out of
+}
+
+// GasLimitConfig is the bounds of the gas limit proposers vote for. Each
+// block moves the gas limit of its parent toward the target of its proposer
+// by at most a step.
+type GasLimitConfig struct {
+	Block       *big.Int `json:"block"       toml:"block"`       // Block the bounds are enforced from, nil disables them
+	Floor       uint64   `json:"floor"       toml:"floor"`       // Min gas limit of a block, MinGasLimit if 0
+	Ceil        uint64   `json:"ceil"        toml:"ceil"`        // Max gas limit of a block, MaxGasLimit if 0 or above it
+	StepDivisor uint64   `json:"stepDivisor" toml:"stepDivisor"` // A block moves the gas limit by at most 1/StepDivisor of its parent, GasLimitBoundDivisor if 0
+}
+
+// Bounds returns the min and max gas limit of a block after a parent of
+// gas limit parent. A parent out of the floor and the ceil is moved toward
+// them by at most a step.
+func (c *GasLimitConfig) Bounds(parent uint64) (uint64, uint64) {
+	floor, ceil, divisor := c.Floor, c.Ceil, c.StepDivisor
+	if floor < MinGasLimit {
+		floor = MinGasLimit
+	}
+	if ceil == 0 || ceil > MaxGasLimit {
+		ceil = MaxGasLimit
+	}
+	if divisor == 0 {
+		divisor = GasLimitBoundDivisor
+	}
+
+	step := parent / divisor
+	lo, hi := parent-step, parent+step
+	if hi > ceil {
+		hi = ceil
+		if hi < lo {
+			hi = lo
+		}
+	}
+	if lo < floor {
+		lo = floor
+		if lo > hi {
+			lo = hi
+		}
+	}
+	return lo, hi
 }
 
 // String implements the stringer interface, returning the consensus engine details.
@@ -345,6 +413,32 @@ func (c *DporConfig) ImpeachCertificate(n uint64) bool {
 	return false
 }
 
+// IsSlashing returns whether offences in block num are recorded and penalised.
+func (c *DporConfig) IsSlashing(num *big.Int) bool {
+	if c != nil && c.Slashing != nil {
+		return isForked(c.Slashing.Block, num)
+	}
+	return false
+}
+
+// IsSignerRotation returns whether the proposed blocks from num on carry the
+// signing keys bound to the identities of the next committee.
+func (c *DporConfig) IsSignerRotation(num *big.Int) bool {
+	if c != nil {
+		return isForked(c.SignerRotationBlock, num)
+	}
+	return false
+}
+
+// IsPeriodGoverned returns whether the proposed blocks from num on carry the
+// block period of the next term set in the period governor.
+func (c *DporConfig) IsPeriodGoverned(num *big.Int) bool {
+	if c != nil {
+		return isForked(c.PeriodGovernorBlock, num)
+	}
+	return false
+}
+
 func (c *DporConfig) PeriodDuration() time.Duration {
 	if c != nil {
 		return time.Duration(int64(c.Period) * int64(time.Millisecond))
@@ -352,6 +446,15 @@ func (c *DporConfig) PeriodDuration() time.Duration {
 	return time.Duration(0)
 }
 
+// IsGasLimitVoting returns whether the gas limit of block num is bounded by
+// the gas limit voting.
+func (c *DporConfig) IsGasLimitVoting(num *big.Int) bool {
+	if c != nil && c.GasLimit != nil {
+		return isForked(c.GasLimit.Block, num)
+	}
+	return false
+}
+
 func (c *DporConfig) BlockDelay() time.Duration {
 	if c != nil {
 		return c.ImpeachTimeout * 1 / 4
@@ -409,13 +512,17 @@ func (err *ConfigCompatError) Error() string {
 }
 
 // Rules wraps ChainConfig and is merely syntatic sugar or can be used for functions
-// that do not have or require information about the block.
+// that do not have or require information about the block. The rpt and campaign
+// forks are read from it, the slashing and gas limit voting forks of the Dpor
+// engine from DporConfig.
 //
 // Rules is a one time interface meaning that it shouldn't be used in between transition
 // phases.
 type Rules struct {
-	ChainID   *big.Int
-	IsCpchain bool
+	ChainID                                                *big.Int
+	IsCpchain                                              bool
+	IsRptMethod2, IsRptMethod3, IsRptMethod4, IsRptMethod5 bool
+	IsCampaign2, IsCampaign3                               bool
 }
 
 // Rules ensures c's ChainID is not nil.
@@ -424,5 +531,14 @@ func (c *ChainConfig) Rules(num *big.Int) Rules {
 	if chainID == nil {
 		chainID = new(big.Int)
 	}
-	return Rules{ChainID: new(big.Int).Set(chainID), IsCpchain: c.IsCpchain()}
+	return Rules{
+		ChainID:      new(big.Int).Set(chainID),
+		IsCpchain:    c.IsCpchain(),
+		IsRptMethod2: c.IsRptMethod2(num),
+		IsRptMethod3: c.IsRptMethod3(num),
+		IsRptMethod4: c.IsRptMethod4(num),
+		IsRptMethod5: c.IsRptMethod5(num),
+		IsCampaign2:  c.IsCampaign2(num),
+		IsCampaign3:  c.IsCampaign3(num),
+	}
 }
@@ -212,3 +212,29 @@ func TestResolveUrl(t *testing.T) {
 		t.Skip("skip if no hosts mapping")
 	}
 }
+
+func TestGasLimitConfigBounds(t *testing.T) {
+	c := &GasLimitConfig{Floor: 10000000, Ceil: 20000000, StepDivisor: 100}
+
+	tests := []struct {
+		parent, lo, hi uint64
+	}{
+		{15000000, 14850000, 15150000},
+		// clamped into the floor and the ceil
+		{10050000, 10000000, 10150500},
+		{19900000, 19701000, 20000000},
+		// a parent out of them moves toward them by a step
+		{30000000, 29700000, 29700000},
+		{5000000, 5050000, 5050000},
+	}
+	for _, tt := range tests {
+		lo, hi := c.Bounds(tt.parent)
+		assert.Equal(t, tt.lo, lo, "lower bound after %d", tt.parent)
+		assert.Equal(t, tt.hi, hi, "upper bound after %d", tt.parent)
+	}
+
+	// unset bounds fall back to the protocol ones
+	lo, hi := (&GasLimitConfig{}).Bounds(MaxGasLimit)
+	assert.Equal(t, MaxGasLimit-MaxGasLimit/GasLimitBoundDivisor, lo)
+	assert.Equal(t, MaxGasLimit, hi)
+}
@@ -57,6 +57,12 @@ var (
 			Contracts:             MainnetContractAddressMap,
 			ImpeachTimeout:        time.Millisecond * MainnetBlockPeriod,
 		},
+		RptMethod2Block: big.NewInt(rptMethod2Block),
+		RptMethod3Block: big.NewInt(rptMethod3Block),
+		RptMethod4Block: big.NewInt(rptMethod4Block),
+		RptMethod5Block: big.NewInt(rptMethod5Block),
+		Campaign2Block:  big.NewInt(campaign2Block),
+		Campaign3Block:  big.NewInt(campaign3Block),
 	}
 	mainnetProposers = []common.Address{
 		common.HexToAddress("0x9e61732d0b1c1674151a01ac0bba824c5b6258fb"), // #1
@@ -69,6 +69,7 @@ const (
 	GetUploadRewardGas uint64 = 1600 // Gas needed for GetUploadRewardGas, need to call contract
 	CpuPowValidateGas  uint64 = 200  // Gas needed for CpuPowValidate, involving hash
 	MemPowValidateGas  uint64 = 200  // Gas needed for MemPowValidate, involving hash
+	StorageValidateGas uint64 = 200  // Gas needed for StorageValidate, involving hash
 )
 
 var (
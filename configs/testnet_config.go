@@ -49,6 +49,12 @@ var (
 			Contracts:             testnetContractAddressMap,
 			ImpeachTimeout:        time.Millisecond * TestnetBlockPeriod * 2,
 		},
+		RptMethod2Block: big.NewInt(rptMethod2Block),
+		RptMethod3Block: big.NewInt(rptMethod3Block),
+		RptMethod4Block: big.NewInt(rptMethod4Block),
+		RptMethod5Block: big.NewInt(rptMethod5Block),
+		Campaign2Block:  big.NewInt(campaign2Block),
+		Campaign3Block:  big.NewInt(campaign3Block),
 	}
 
 	testnetProposers = testnetDefaultCandidates[0:4]
@@ -1,12 +1,24 @@
 package dpor
 
 import (
+	"context"
+	"errors"
+	"time"
+
 	"bitbucket.org/cpchain/chain/api/rpc"
 	"bitbucket.org/cpchain/chain/consensus"
+	"bitbucket.org/cpchain/chain/consensus/dpor/rpt"
 	"bitbucket.org/cpchain/chain/types"
 	"github.com/ethereum/go-ethereum/common"
 )
 
+var (
+	errNoRptService = errors.New("rpt service is not available")
+	errNotCandidate = errors.New("not a candidate at the block")
+	errNoTermRpts   = errors.New("no rpts indexed for the term")
+	errNoCoinbase   = errors.New("no coinbase to propose blocks")
+)
+
 // API is a user facing RPC API to allow controlling the signer and voting
 // mechanisms of the proof-of-authority scheme.
 type API struct {
@@ -65,3 +77,163 @@ func (api *API) GetValidators(number rpc.BlockNumber) ([]common.Address, error)
 func (api *API) GetRNodes() ([]common.Address, error) {
 	return api.dpor.GetRNodes()
 }
+
+// GetRptBreakdown explains the rpt of a candidate at a given block, it is
+// calculated among the candidates of the Snapshot at that block as in the
+// election.
+func (api *API) GetRptBreakdown(address common.Address, number rpc.BlockNumber) (*rpt.RptBreakdown, error) {
+	// Retrieve the requested block number (or current if none requested)
+	var header *types.Header
+	if number == 0 || number == rpc.LatestBlockNumber {
+		header = api.chain.CurrentHeader()
+	} else {
+		header = api.chain.GetHeaderByNumber(uint64(number.Int64()))
+	}
+	if header == nil {
+		return nil, errUnknownBlock
+	}
+
+	rptService := api.dpor.GetRptBackend()
+	if rptService == nil {
+		return nil, errNoRptService
+	}
+
+	snap, err := api.dpor.dh.snapshot(api.dpor, api.chain, header.Number.Uint64(), header.Hash(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// the rank of a candidate depends on the others, only candidates are
+	// explained so the rpts match the ones of the election
+	candidates := snap.candidates()
+	for _, candidate := range candidates {
+		if candidate == address {
+			b := rptService.CalcRptBreakdown(address, candidates, snap.number())
+			return &b, nil
+		}
+	}
+	return nil, errNotCandidate
+}
+
+// GetTermRpts retrieves the rpts the proposers of a given term were elected
+// by, as indexed when the election ran.
+func (api *API) GetTermRpts(term uint64) (*TermRpts, error) {
+	rpts, err := readTermRpts(api.dpor.db, term)
+	if err != nil {
+		return nil, errNoTermRpts
+	}
+	return rpts, nil
+}
+
+// GetPenalties retrieves the penalties deducted from impeached proposers in a
+// given block.
+func (api *API) GetPenalties(number rpc.BlockNumber) ([]*Penalty, error) {
+	// Retrieve the requested block number (or current if none requested)
+	var header *types.Header
+	if number == 0 || number == rpc.LatestBlockNumber {
+		header = api.chain.CurrentHeader()
+	} else {
+		header = api.chain.GetHeaderByNumber(uint64(number.Int64()))
+	}
+	if header == nil {
+		return nil, errUnknownBlock
+	}
+
+	penalties := []*Penalty{}
+	if !api.dpor.config.IsSlashing(header.Number) {
+		return penalties, nil
+	}
+	deducted, err := api.dpor.penaltiesOf(api.chain, header)
+	if err != nil {
+		return nil, err
+	}
+	return append(penalties, deducted...), nil
+}
+
+// GetOffences retrieves the offences of a proposer in the offence window at a
+// given block, and the last term it is barred from the elections until.
+func (api *API) GetOffences(address common.Address, number rpc.BlockNumber) (*Offences, error) {
+	snap, err := api.GetSnapshot(number)
+	if err != nil {
+		return nil, err
+	}
+	return snap.offencesOf(address), nil
+}
+
+// GetBlockLimits retrieves the gas usage of a given block, and the period and
+// the gas limit bounds of the block after it.
+func (api *API) GetBlockLimits(number rpc.BlockNumber) (*BlockLimits, error) {
+	// Retrieve the requested block number (or current if none requested)
+	var header *types.Header
+	if number == 0 || number == rpc.LatestBlockNumber {
+		header = api.chain.CurrentHeader()
+	} else {
+		header = api.chain.GetHeaderByNumber(uint64(number.Int64()))
+	}
+	if header == nil {
+		return nil, errUnknownBlock
+	}
+	snap, err := api.dpor.dh.snapshot(api.dpor, api.chain, header.Number.Uint64(), header.Hash(), nil)
+	if err != nil {
+		return nil, err
+	}
+	return limitsOf(api.dpor.config, snap, header), nil
+}
+
+// GetSchedule retrieves the schedules of the next given number of terms from
+// the current block on, with the proposers elected or unknown yet, the
+// expected blocks and time of each view and whether the local coinbase
+// proposes in it.
+func (api *API) GetSchedule(terms uint64) ([]*TermSchedule, error) {
+	if terms == 0 {
+		terms = defaultScheduleTerms
+	}
+	if terms > maxScheduleTerms {
+		terms = maxScheduleTerms
+	}
+	return api.dpor.Schedule(api.chain, api.chain.CurrentHeader(), terms)
+}
+
+// ProposingTurn creates a subscription notified once for each view of the
+// local coinbase, given number of blocks before the view starts.
+func (api *API) ProposingTurn(ctx context.Context, blocksBefore uint64) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+	if api.dpor.Coinbase() == (common.Address{}) {
+		return &rpc.Subscription{}, errNoCoinbase
+	}
+
+	rpcSub := notifier.CreateSubscription()
+
+	go func() {
+		ticker := time.NewTicker(turnCheckInterval)
+		defer ticker.Stop()
+
+		var notified *ViewSchedule
+		for {
+			select {
+			case <-ticker.C:
+				head := api.chain.CurrentHeader()
+				turn, err := api.dpor.NextTurn(api.chain, head)
+				if err != nil || turn == nil {
+					continue
+				}
+				if notified != nil && notified.Term == turn.Term && notified.View == turn.View {
+					continue
+				}
+				if head.Number.Uint64()+blocksBefore >= turn.StartBlock {
+					notifier.Notify(rpcSub.ID, turn)
+					notified = turn
+				}
+			case <-rpcSub.Err():
+				return
+			case <-notifier.Closed():
+				return
+			}
+		}
+	}()
+
+	return rpcSub, nil
+}
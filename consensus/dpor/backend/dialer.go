@@ -82,6 +82,16 @@ func (d *Dialer) isCurrentOrFutureValidator(address common.Address, term uint64,
 	return isValidator
 }
 
+// identityOf returns the coinbase a key signs for in the period between current term and future term
+func (d *Dialer) identityOf(signer common.Address, term uint64, futureTerm uint64) common.Address {
+	for t := term; t <= futureTerm; t++ {
+		if identity := d.dpor.IdentityOf(signer, t); identity != signer {
+			return identity
+		}
+	}
+	return signer
+}
+
 // addPeer tries to add a p2p peer as a proposer or a validator to local peer set based on its coinbase
 func (d *Dialer) addPeer(version int, p *p2p.Peer, rw p2p.MsgReadWriter, mac string, sig []byte, term uint64, futureTerm uint64) (string, bool, bool, error) {
 
@@ -89,6 +99,9 @@ func (d *Dialer) addPeer(version int, p *p2p.Peer, rw p2p.MsgReadWriter, mac str
 	log.Debug("do handshaking with remote peer...")
 	coinbase, err := Handshake(p, rw, mac, sig, term, futureTerm)
 
+	// remote peer may sign with a new signing key bound to its coinbase
+	coinbase = d.identityOf(coinbase, term, futureTerm)
+
 	// some debug output
 	log.Debug("received handshake from", "addr", coinbase.Hex())
 
@@ -9,6 +9,7 @@ import (
 	"bitbucket.org/cpchain/chain/configs"
 	"bitbucket.org/cpchain/chain/consensus"
 	"bitbucket.org/cpchain/chain/database"
+	cconfigs "bitbucket.org/cpchain/chain/protocols/cpc/configs"
 	"bitbucket.org/cpchain/chain/types"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/p2p"
@@ -187,6 +188,13 @@ func (h *Handler) handleMsg(p *RemoteSigner, msg p2p.Msg) error {
 		return nil
 	}
 
+	if msg.Code == MaintenanceNoticeMsg {
+		if p.Version() < cconfigs.Cpc2 {
+			return errResp(ErrInvalidMsgCode, "%v", msg.Code)
+		}
+		return h.handleMaintenanceNotice(msg, p)
+	}
+
 	switch h.mode {
 	case LBFTMode:
 		return h.handleLBFTMsg(msg, p)
@@ -7,6 +7,9 @@ import (
 	"testing"
 
 	"bitbucket.org/cpchain/chain/accounts/keystore"
+	cconfigs "bitbucket.org/cpchain/chain/protocols/cpc/configs"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/p2p"
 )
 
 func TestHandler_Available(t *testing.T) {
@@ -23,6 +26,20 @@ func TestHandler_Available(t *testing.T) {
 	}
 }
 
+func TestHandler_maintenanceNoticeVersion(t *testing.T) {
+	var testHandler Handler
+	p := NewRemoteSigner(common.Address{0x01})
+	p.SetPeer(cconfigs.Cpc1, nil, nil)
+
+	// a peer before cpc/2 neither sends nor receives maintenance notices
+	if err := testHandler.handleMsg(p, p2p.Msg{Code: MaintenanceNoticeMsg}); err == nil {
+		t.Error("maintenance notice handled from a cpc/1 peer")
+	}
+	if err := p.SendMaintenanceNotice(&MaintenanceNotice{From: 1, To: 2}); err != nil {
+		t.Errorf("SendMaintenanceNotice() to a cpc/1 peer error = %v", err)
+	}
+}
+
 // Load account. Used for create ContractCaller
 func getAccount(keyStoreFilePath string, passphrase string, t *testing.T) keystore.Key {
 	ff, err := filepath.Abs("../../../")
@@ -3,7 +3,6 @@ package backend
 import (
 	"context"
 	"math/big"
-	"strings"
 	"time"
 
 	"bitbucket.org/cpchain/chain/accounts"
@@ -16,7 +15,6 @@ import (
 	"bitbucket.org/cpchain/chain/types"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
-	"github.com/ethereum/go-ethereum/crypto/sha3"
 	"github.com/ethereum/go-ethereum/p2p"
 )
 
@@ -174,6 +172,9 @@ type DporService interface {
 	// VerifyValidatorOf verifies if an address is a validator of given term
 	VerifyValidatorOf(signer common.Address, term uint64) (bool, error)
 
+	// IdentityOf returns the proposer or validator a key signs for in given term
+	IdentityOf(signer common.Address, term uint64) common.Address
+
 	// ValidatorsOf returns the list of validators in committee for the specified block number
 	ValidatorsOf(number uint64) ([]common.Address, error)
 
@@ -231,6 +232,9 @@ type DporService interface {
 	// ImpeachTimeout returns the timeout for impeachment
 	ImpeachTimeout() time.Duration
 
+	// AddMaintenanceNotice verifies and adds a maintenance notice of a proposer, returns false if already known
+	AddMaintenanceNotice(notice *MaintenanceNotice) (bool, error)
+
 	// ECRecoverProposer recovers proposer's address from a seal of a header
 	ECRecoverProposer(header *types.Header) (common.Address, error)
 
@@ -268,23 +272,10 @@ func ValidMacSig(mac string, sig []byte) (valid bool, signer common.Address, err
 
 	log.Debug("received mac", "mac", mac)
 
-	// check if mac prefix is valid
-	split := "|"
-	s := strings.Split(mac, split)
-	if len(s) != 2 {
-		log.Warn("wrong mac format", "mac", mac)
-		return
-	}
-	prefix, timeString := s[0], s[1]
-	if prefix != "cpchain" {
-		log.Warn("wrong mac prefix", "prefix", prefix)
-		return
-	}
-
 	// check if remote time is valid
-	remoteTime, err := time.Parse(time.RFC3339, timeString)
+	remoteTime, err := ParseMac(mac)
 	if err != nil {
-		log.Warn("err when parsing time string", "time", timeString)
+		log.Warn("wrong mac format", "mac", mac)
 		return
 	}
 	timeGap := time.Now().Sub(remoteTime)
@@ -296,10 +287,7 @@ func ValidMacSig(mac string, sig []byte) (valid bool, signer common.Address, err
 		return
 	}
 
-	var hash common.Hash
-	hasher := sha3.NewKeccak256()
-	hasher.Write([]byte(mac))
-	hasher.Sum(hash[:0])
+	hash := MacHash(mac)
 
 	// recover address
 	pubkey, err := crypto.Ecrecover(hash.Bytes(), sig)
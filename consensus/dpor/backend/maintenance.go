@@ -0,0 +1,118 @@
+package backend
+
+import (
+	"errors"
+
+	"bitbucket.org/cpchain/chain/commons/log"
+	cconfigs "bitbucket.org/cpchain/chain/protocols/cpc/configs"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+var (
+	// ErrInvalidMaintenanceNotice is returned if a maintenance notice is not signed by a proposer of its blocks
+	ErrInvalidMaintenanceNotice = errors.New("invalid maintenance notice")
+)
+
+// maintenancePrefix is hashed with the blocks of a maintenance notice, so the
+// signature is never valid for other msgs
+var maintenancePrefix = []byte("dpor maintenance")
+
+// MaintenanceNotice is a notice of a proposer skipping its blocks from From to
+// To. Once a proposer carries it in a block before From, validators impeach
+// the blocks when their time comes without waiting for the impeach timeout.
+type MaintenanceNotice struct {
+	From      uint64 `json:"from"`
+	To        uint64 `json:"to"`
+	Signature []byte `json:"signature"`
+}
+
+// SigHash returns the hash the proposer signs
+func (n *MaintenanceNotice) SigHash() common.Hash {
+	data, _ := rlp.EncodeToBytes([]interface{}{maintenancePrefix, n.From, n.To})
+	return crypto.Keccak256Hash(data)
+}
+
+// Signer recovers the key signing the notice
+func (n *MaintenanceNotice) Signer() (common.Address, error) {
+	pubkey, err := crypto.SigToPub(n.SigHash().Bytes(), n.Signature)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return crypto.PubkeyToAddress(*pubkey), nil
+}
+
+// Covers returns if the notice skips the block of number
+func (n *MaintenanceNotice) Covers(number uint64) bool {
+	return n.From <= number && number <= n.To
+}
+
+// SendMaintenanceNotice sends a maintenance notice to the remote signer, the
+// peers before cpc/2 do not speak it
+func (s *RemoteSigner) SendMaintenanceNotice(notice *MaintenanceNotice) error {
+	if s.Version() < cconfigs.Cpc2 {
+		return nil
+	}
+	return p2p.Send(s.rw, MaintenanceNoticeMsg, notice)
+}
+
+// BroadcastMaintenanceNotice sends a maintenance notice to the proposers up to
+// the terms it covers to carry it in their blocks, and to the validators of
+// the terms it covers connected.
+func (h *Handler) BroadcastMaintenanceNotice(notice *MaintenanceNotice) {
+	log.Debug("broadcasting maintenance notice", "from", notice.From, "to", notice.To)
+
+	var signers []*RemoteSigner
+	sent := make(map[common.Address]bool)
+	add := func(addr common.Address, peer *RemoteSigner) {
+		if !sent[addr] {
+			sent[addr] = true
+			signers = append(signers, peer)
+		}
+	}
+
+	term := h.dpor.TermOf(notice.From)
+	if current := h.dpor.GetCurrentBlock(); current != nil {
+		term = h.dpor.TermOf(current.NumberU64() + 1)
+	}
+	for ; term <= h.dpor.TermOf(notice.To); term++ {
+		for addr, peer := range h.dialer.ProposersOfTerm(term) {
+			add(addr, peer.RemoteSigner)
+		}
+		if term < h.dpor.TermOf(notice.From) {
+			continue
+		}
+		for addr, peer := range h.dialer.ValidatorsOfTerm(term) {
+			add(addr, peer.RemoteSigner)
+		}
+	}
+
+	for _, peer := range signers {
+		go func(peer *RemoteSigner) {
+			if err := peer.SendMaintenanceNotice(notice); err != nil {
+				log.Warn("failed to send maintenance notice", "signer", peer.Coinbase().Hex(), "err", err)
+			}
+		}(peer)
+	}
+}
+
+// handleMaintenanceNotice adds a received maintenance notice, and relays it to
+// the other proposers and validators the first time.
+func (h *Handler) handleMaintenanceNotice(msg p2p.Msg, p *RemoteSigner) error {
+	var notice MaintenanceNotice
+	if err := msg.Decode(&notice); err != nil {
+		return errResp(ErrDecode, "msg %v: %v", msg, err)
+	}
+
+	added, err := h.dpor.AddMaintenanceNotice(&notice)
+	if err != nil {
+		log.Debug("dropped maintenance notice", "from", notice.From, "to", notice.To, "peer", p.Coinbase().Hex(), "err", err)
+		return nil
+	}
+	if added {
+		go h.BroadcastMaintenanceNotice(&notice)
+	}
+	return nil
+}
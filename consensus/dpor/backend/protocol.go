@@ -37,6 +37,9 @@ const (
 	PrepareImpeachHeaderMsg   = 0x48
 	CommitImpeachHeaderMsg    = 0x49
 	ValidateImpeachBlockMsg   = 0x50
+
+	// MaintenanceNoticeMsg is a msg code of a proposer announcing the blocks it skips, spoken from cpc/2 on
+	MaintenanceNoticeMsg = 0x51
 )
 
 // ProtocolMaxMsgSize Maximum cap on the size of a protocol message
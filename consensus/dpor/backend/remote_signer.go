@@ -91,6 +91,14 @@ func (s *RemoteSigner) SetPeer(version int, p *p2p.Peer, rw p2p.MsgReadWriter) {
 	s.version, s.Peer, s.rw = version, p, rw
 }
 
+// Version returns the protocol version negotiated with the remote signer
+func (s *RemoteSigner) Version() int {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return s.version
+}
+
 // Handshake tries to handshake with remote validator
 func Handshake(p *p2p.Peer, rw p2p.MsgReadWriter, mac string, sig []byte, term uint64, futureTerm uint64) (address common.Address, err error) {
 	// Send out own handshake in a new thread
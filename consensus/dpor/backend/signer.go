@@ -0,0 +1,67 @@
+package backend
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"bitbucket.org/cpchain/chain/accounts"
+	"bitbucket.org/cpchain/chain/consensus"
+	"bitbucket.org/cpchain/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const (
+	macPrefix = "cpchain"
+	macSplit  = "|"
+)
+
+var (
+	// ErrInvalidMac is returned if a mac is not like "cpchain|<RFC3339 time>"
+	ErrInvalidMac = errors.New("invalid mac")
+)
+
+// DporSigner is implemented by wallets which don't sign raw hashes, like the
+// external signer. They are handed the Dpor msgs themselves and compute the
+// hashes to sign on their own, so they know what they sign.
+type DporSigner interface {
+	// SignDporHeader signs the sig hash of a header in a consensus state
+	SignDporHeader(account accounts.Account, header *types.Header, state consensus.State) ([]byte, error)
+
+	// SignDporMac signs a mac of the handshake
+	SignDporMac(account accounts.Account, mac string) ([]byte, error)
+
+	// SignDporMaintenance signs a maintenance notice skipping blocks from to to
+	SignDporMaintenance(account accounts.Account, from, to uint64) ([]byte, error)
+}
+
+// DporSignerFn returns the typed signer of an account, or nil if it signs
+// raw hashes with SignFn
+type DporSignerFn func(accounts.Account) DporSigner
+
+// NewMac composes a mac of the handshake at time t
+func NewMac(t time.Time) string {
+	return macPrefix + macSplit + t.Format(time.RFC3339)
+}
+
+// ParseMac returns the time of a mac
+func ParseMac(mac string) (time.Time, error) {
+	s := strings.Split(mac, macSplit)
+	if len(s) != 2 || s[0] != macPrefix {
+		return time.Time{}, ErrInvalidMac
+	}
+	return time.Parse(time.RFC3339, s[1])
+}
+
+// MacTimely reports whether a mac composed at t is within the time gap
+// allowed by the handshake, either way
+func MacTimely(t time.Time) bool {
+	gap := time.Since(t)
+	return gap <= defaultTimeGapAllowed && -gap <= defaultTimeGapAllowed
+}
+
+// MacHash returns the hash signed for a mac
+func MacHash(mac string) common.Hash {
+	return crypto.Keccak256Hash([]byte(mac))
+}
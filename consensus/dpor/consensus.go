@@ -159,6 +159,32 @@ func (d *Dpor) PrepareBlock(chain consensus.ChainReader, header *types.Header) e
 	}
 	header.Extra = header.Extra[:extraVanity]
 
+	// Carry the signing keys of the next committee and the block period of the
+	// next term in the first block of a term, and the maintenance notices
+	// received not on chain yet
+	extra := new(headerExtra)
+	if term := snap.signersTermDue(number); term != 0 {
+		signers, err := d.signersOf(snap, term, number-1)
+		if err != nil {
+			return err
+		}
+		extra.SignersTerm, extra.Signers = term, signers
+	}
+	if term := snap.periodTermDue(number); term != 0 {
+		period, err := d.periodOf(snap, term, number-1)
+		if err != nil {
+			return err
+		}
+		extra.PeriodTerm, extra.Period = term, period
+	}
+	if d.config.IsSlashing(header.Number) {
+		d.maintenance.prune(number)
+		extra.Notices = d.maintenance.pending(snap, number)
+	}
+	if err := setExtra(header, extra); err != nil {
+		return err
+	}
+
 	for _, proposer := range snap.ProposersOf(number) {
 		header.Dpor.Proposers = append(header.Dpor.Proposers, proposer)
 	}
@@ -176,7 +202,7 @@ func (d *Dpor) PrepareBlock(chain consensus.ChainReader, header *types.Header) e
 		return consensus.ErrUnknownAncestor
 	}
 
-	header.SetTimestamp(parent.Timestamp().Add(d.config.PeriodDuration()))
+	header.SetTimestamp(parent.Timestamp().Add(snap.PeriodDurationOf(number)))
 	if header.Timestamp().Before(time.Now()) {
 		header.SetTimestamp(time.Now())
 	}
@@ -191,6 +217,11 @@ func (d *Dpor) TryCampaign() {
 		return
 	}
 
+	if d.ac.CampaignState().Running {
+		// the campaign manager keeps the candidacy
+		return
+	}
+
 	snap := d.CurrentSnap()
 	if snap != nil {
 		isV := snap.IsValidatorOf(d.coinbase, snap.Number)
@@ -215,6 +246,12 @@ func (d *Dpor) TryCampaign() {
 		}
 
 		if d.IsToCampaign() && snap.isStartCampaign() && !isV {
+			// a barred proposer is left out of the elections anyway
+			if snap.isBarred(d.Coinbase(), snap.FutureTermOf(snap.Number)) {
+				log.Info("barred from candidacy for failing to propose, not to campaign", "until term", snap.offencesOf(d.Coinbase()).BarredUntil)
+				return
+			}
+
 			newTerm := d.CurrentSnap().TermOf(snap.Number)
 			if newTerm > d.lastCampaignTerm+defaultCampaignTerms-1 {
 				d.lastCampaignTerm = newTerm
@@ -227,22 +264,24 @@ func (d *Dpor) TryCampaign() {
 	}
 }
 
-func addCoinbaseReward(coinbase common.Address, state *state.StateDB, number *big.Int) {
-	var amount *big.Int
+// BlockReward returns the reward minted to the coinbase of block number.
+func BlockReward(number *big.Int) *big.Int {
 	if number.Cmp(configs.Cep1LastBlockY1) <= 0 {
-		amount = configs.Cep1BlockRewardY1
+		return configs.Cep1BlockRewardY1
 	} else if number.Cmp(configs.Cep1LastBlockY2) <= 0 {
-		amount = configs.Cep1BlockRewardY2
+		return configs.Cep1BlockRewardY2
 	} else if number.Cmp(configs.Cep1LastBlockY3) <= 0 {
-		amount = configs.Cep1BlockRewardY3
+		return configs.Cep1BlockRewardY3
 	} else if number.Cmp(configs.Cep1LastBlockY4) <= 0 {
-		amount = configs.Cep1BlockRewardY4
+		return configs.Cep1BlockRewardY4
 	} else if number.Cmp(configs.Cep1LastBlockY5) <= 0 {
-		amount = configs.Cep1BlockRewardY5
-	} else {
-		amount = big.NewInt(0)
+		return configs.Cep1BlockRewardY5
 	}
-	state.AddBalance(coinbase, amount)
+	return big.NewInt(0)
+}
+
+func addCoinbaseReward(coinbase common.Address, state *state.StateDB, number *big.Int) {
+	state.AddBalance(coinbase, BlockReward(number))
 }
 
 // Finalize implements consensus.Engine, ensuring no uncles are set, nor block
@@ -251,6 +290,17 @@ func (d *Dpor) Finalize(chain consensus.ChainReader, header *types.Header, state
 
 	if (header.Coinbase != common.Address{}) {
 		addCoinbaseReward(header.Coinbase, state, header.Number)
+
+		// penalise the proposers impeached right before the block, the logs
+		// of the penalties are the block's own, kept apart from the ones of
+		// its transactions
+		state.Prepare(common.Hash{}, common.Hash{}, len(txs))
+		if err := d.slash(chain, header, state); err != nil {
+			return nil, err
+		}
+		if logs := state.GetLogs(common.Hash{}); len(logs) > 0 {
+			receipts = append(receipts[:len(receipts):len(receipts)], types.NewBlockReceipt(logs, header.GasUsed))
+		}
 	}
 
 	// last step
@@ -285,7 +335,6 @@ func (d *Dpor) Seal(chain consensus.ChainReader, block *types.Block, stop <-chan
 		number = header.Number.Uint64()
 
 		coinbase = d.Coinbase()
-		signFn   = d.SignHash
 	)
 
 	// Sealing the genesis block is not supported
@@ -330,7 +379,7 @@ func (d *Dpor) Seal(chain consensus.ChainReader, block *types.Block, stop <-chan
 	}
 
 	// Proposer seals the block with signature
-	sighash, err := signFn(d.dh.sigHash(header).Bytes())
+	sighash, err := d.signDporHeader(header, consensus.Commit)
 	if err != nil {
 		return nil, err
 	}
@@ -364,6 +413,12 @@ func (d *Dpor) CanMakeBlock(chain consensus.ChainReader, coinbase common.Address
 		log.Debug("it is not proposer", "msg", err)
 		return false
 	}
+
+	// skip the block announced in a maintenance notice on chain, validators impeach it
+	if ok && snap.excused(number+1) {
+		log.Info("skip the block in maintenance", "number", number+1)
+		return false
+	}
 	log.Debug("now can finished CanMakeBlock call", "ok", ok)
 	return ok
 }
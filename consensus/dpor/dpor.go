@@ -59,12 +59,15 @@ type Dpor struct {
 
 	signedBlocks *signedBlocksRecord // Record signed blocks.
 
+	maintenance *maintenanceRecord // Maintenance notices of proposers skipping their blocks
+
 	currentSnap     *DporSnapshot // Current snapshot
 	currentSnapLock sync.RWMutex
 
-	coinbase     common.Address // Coinbase of the miner(proposer or validator)
-	signFn       backend.SignFn // Sign function to authorize hashes with
-	coinbaseLock sync.RWMutex   // Protects the signer fields
+	coinbase     common.Address       // Coinbase of the miner(proposer or validator)
+	signFn       backend.SignFn       // Sign function to authorize hashes with
+	dporSignerFn backend.DporSignerFn // Typed signers of accounts not signing raw hashes
+	coinbaseLock sync.RWMutex         // Protects the signer fields
 
 	handler *backend.Handler
 
@@ -86,6 +89,8 @@ type Dpor struct {
 	rNodeBackend     *rnode.Rnode
 	rptBackend       rpt.RptService
 	candidateBackend rpt.CandidateService
+	signerBackend    rpt.SignerService
+	periodBackend    rpt.PeriodService
 
 	chain consensus.ChainReadWriter
 
@@ -103,19 +108,86 @@ type Dpor struct {
 	validatorInitialized int32
 }
 
-// SignHash signs a hash msg with dpor coinbase account
+// SignHash signs a hash msg with the signing key of dpor coinbase account
 func (d *Dpor) SignHash(hash []byte) ([]byte, error) {
 	d.coinbaseLock.Lock()
 	defer d.coinbaseLock.Unlock()
 
 	var (
-		coinbase = d.coinbase
-		account  = accounts.Account{Address: coinbase}
+		signer  = d.signerOf(d.coinbase)
+		account = accounts.Account{Address: signer}
 	)
 
 	return d.signFn(account, hash)
 }
 
+// SetDporSigner sets the typed signers used instead of the sign function by
+// accounts which don't sign raw hashes
+func (d *Dpor) SetDporSigner(fn backend.DporSignerFn) {
+	d.coinbaseLock.Lock()
+	defer d.coinbaseLock.Unlock()
+
+	d.dporSignerFn = fn
+}
+
+// sign signs a Dpor msg with the signing key of dpor coinbase account,
+// handing the msg to its typed signer if it has one, or else its hash to the
+// sign function
+func (d *Dpor) sign(hash func() (common.Hash, error), typed func(backend.DporSigner, accounts.Account) ([]byte, error)) ([]byte, error) {
+	d.coinbaseLock.Lock()
+	defer d.coinbaseLock.Unlock()
+
+	account := accounts.Account{Address: d.signerOf(d.coinbase)}
+	if d.dporSignerFn != nil {
+		if signer := d.dporSignerFn(account); signer != nil {
+			return typed(signer, account)
+		}
+	}
+
+	h, err := hash()
+	if err != nil {
+		return nil, err
+	}
+	return d.signFn(account, h.Bytes())
+}
+
+// signDporHeader signs header in state with the signing key of dpor coinbase account
+func (d *Dpor) signDporHeader(header *types.Header, state consensus.State) ([]byte, error) {
+	return d.sign(func() (common.Hash, error) {
+		return HeaderSigHash(header, state)
+	}, func(signer backend.DporSigner, account accounts.Account) ([]byte, error) {
+		return signer.SignDporHeader(account, header, state)
+	})
+}
+
+// signDporMac signs a mac of the handshake with the signing key of dpor coinbase account
+func (d *Dpor) signDporMac(mac string) ([]byte, error) {
+	return d.sign(func() (common.Hash, error) {
+		return backend.MacHash(mac), nil
+	}, func(signer backend.DporSigner, account accounts.Account) ([]byte, error) {
+		return signer.SignDporMac(account, mac)
+	})
+}
+
+// signDporMaintenance signs a maintenance notice with the signing key of dpor coinbase account
+func (d *Dpor) signDporMaintenance(notice *backend.MaintenanceNotice) ([]byte, error) {
+	return d.sign(func() (common.Hash, error) {
+		return notice.SigHash(), nil
+	}, func(signer backend.DporSigner, account accounts.Account) ([]byte, error) {
+		return signer.SignDporMaintenance(account, notice.From, notice.To)
+	})
+}
+
+// signerOf returns the key signing for the coinbase from the next block on,
+// the coinbase itself unless a new signing key is bound to it
+func (d *Dpor) signerOf(coinbase common.Address) common.Address {
+	snap := d.CurrentSnap()
+	if snap == nil {
+		return coinbase
+	}
+	return snap.SignerOf(coinbase, snap.number()+1)
+}
+
 // IsMiner returns if local coinbase is a miner(proposer or validator)
 func (d *Dpor) IsMiner() bool {
 	d.isMinerLock.RLock()
@@ -211,6 +283,7 @@ func New(config *configs.DporConfig, db database.Database) *Dpor {
 		finalSigs:    finalSigs,
 		prepareSigs:  preparedSigs,
 		signedBlocks: signedBlocks,
+		maintenance:  newMaintenanceRecord(),
 	}
 }
 
@@ -390,22 +463,38 @@ func (d *Dpor) SetupAdmission(ac admission.ApiBackend) {
 	d.ac = ac
 }
 
-func (d *Dpor) SetRptBackend(backend backend.ClientBackend) {
-	d.rptBackend, _ = rpt.NewRptService(backend, configs.ChainConfigInfo().Dpor.Contracts[configs.ContractRpt])
+func (d *Dpor) SetRptBackend(config *configs.ChainConfig, backend backend.ClientBackend) {
+	d.rptBackend, _ = rpt.NewRptService(config, backend, configs.ChainConfigInfo().Dpor.Contracts[configs.ContractRpt])
 }
 
 func (d *Dpor) GetRptBackend() rpt.RptService {
 	return d.rptBackend
 }
 
-func (d *Dpor) SetCandidateBackend(backend backend.ClientBackend) {
-	d.candidateBackend, _ = rpt.NewCandidateService(backend)
+func (d *Dpor) SetCandidateBackend(config *configs.ChainConfig, backend backend.ClientBackend) {
+	d.candidateBackend, _ = rpt.NewCandidateService(config, backend)
 }
 
 func (d *Dpor) GetCandidateBackend() rpt.CandidateService {
 	return d.candidateBackend
 }
 
+func (d *Dpor) SetSignerBackend(backend backend.ClientBackend) {
+	d.signerBackend, _ = rpt.NewSignerService(backend)
+}
+
+func (d *Dpor) GetSignerBackend() rpt.SignerService {
+	return d.signerBackend
+}
+
+func (d *Dpor) SetPeriodBackend(backend backend.ClientBackend) {
+	d.periodBackend, _ = rpt.NewPeriodService(backend)
+}
+
+func (d *Dpor) GetPeriodBackend() rpt.PeriodService {
+	return d.periodBackend
+}
+
 func (d *Dpor) SetRNodeBackend(backend backend.ClientBackend) {
 	instance, err := rnode.NewRnode(configs.ChainConfigInfo().Dpor.Contracts[configs.ContractRnode], backend)
 	if err == nil {
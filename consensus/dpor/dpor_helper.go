@@ -185,16 +185,21 @@ func (dh *defaultDporHelper) verifyBasic(dpor *Dpor, chain consensus.ChainReader
 		return consensus.ErrUnknownAncestor
 	}
 
-	// If timestamp is in a valid field, wait for it, otherwise, return invalid timestamp.
-	log.Debug("timestamp related values", "parent timestamp", parent.Timestamp(), "block timestamp", header.Timestamp(), "period", dpor.config.PeriodDuration(), "timeout", dpor.config.ImpeachTimeout)
-
 	// Ensure that the block's timestamp is valid
 	if dpor.Mode() == NormalMode && number > dpor.config.MaxInitBlockNumber && !isImpeach {
 
-		if header.Timestamp().Before(parent.Timestamp().Add(dpor.config.PeriodDuration())) {
+		// If timestamp is in a valid field, wait for it, otherwise, return invalid timestamp.
+		snap, err := dh.snapshot(dpor, chain, number-1, header.ParentHash, parents)
+		if err != nil {
+			return err
+		}
+		period := snap.PeriodDurationOf(number)
+		log.Debug("timestamp related values", "parent timestamp", parent.Timestamp(), "block timestamp", header.Timestamp(), "period", period, "timeout", dpor.config.ImpeachTimeout)
+
+		if header.Timestamp().Before(parent.Timestamp().Add(period)) {
 			return ErrInvalidTimestamp
 		}
-		if header.Timestamp().After(parent.Timestamp().Add(dpor.config.PeriodDuration()).Add(dpor.config.ImpeachTimeout)) {
+		if header.Timestamp().After(parent.Timestamp().Add(period).Add(dpor.config.ImpeachTimeout)) {
 			return ErrInvalidTimestamp
 		}
 	}
@@ -204,8 +209,27 @@ func (dh *defaultDporHelper) verifyBasic(dpor *Dpor, chain consensus.ChainReader
 		return ErrInvalidGasLimit
 	}
 
+	// Ensure that the gas limit moves within a step toward the bounds voted, impeach blocks keep the one of the parent
+	if dpor.config.IsGasLimitVoting(header.Number) && !isImpeach {
+		if lo, hi := dpor.config.GasLimit.Bounds(parent.GasLimit); header.GasLimit < lo || header.GasLimit > hi {
+			return ErrInvalidGasLimit
+		}
+	}
+
 	if isImpeach {
-		return dh.verifyBasicImpeach(dpor, chain, header, parent)
+		return dh.verifyBasicImpeach(dpor, chain, header, parent, parents)
+	}
+
+	// Ensure that the block carries the signing keys of the next committee when due,
+	// and well formed maintenance notices
+	if hasExtra(dpor.config, header.Number) {
+		snap, err := dh.snapshot(dpor, chain, number-1, header.ParentHash, parents)
+		if err != nil {
+			return err
+		}
+		if err := snap.verifyExtra(header); err != nil {
+			return err
+		}
 	}
 
 	// Delay to verify it!
@@ -217,7 +241,7 @@ func (dh *defaultDporHelper) verifyBasic(dpor *Dpor, chain consensus.ChainReader
 }
 
 // verifyBasicImpeach verifies basic fields of an impeach header, i.e. Number, Hash, Coinbase, Time
-func (dh *defaultDporHelper) verifyBasicImpeach(dpor *Dpor, chain consensus.ChainReader, header *types.Header, parent *types.Header) error {
+func (dh *defaultDporHelper) verifyBasicImpeach(dpor *Dpor, chain consensus.ChainReader, header *types.Header, parent *types.Header, parents []*types.Header) error {
 
 	expectedImpeachBlock := types.NewBlock(header, []*types.Transaction{}, []*types.Receipt{})
 	expectedImpeachBlock.RefHeader().Extra = make([]byte, extraSeal)
@@ -356,6 +380,20 @@ func (dh *defaultDporHelper) verifyProposers(dpor *Dpor, chain consensus.ChainRe
 		}
 	}
 
+	// Check the signing keys and the block period carried are the registered
+	// ones, and the maintenance notices are signed by the proposers elected
+	if dpor.Mode() == NormalMode {
+		if err := dpor.verifyCarriedSigners(snap, header); err != nil {
+			return err
+		}
+		if err := dpor.verifyCarriedPeriod(snap, header); err != nil {
+			return err
+		}
+		if err := snap.verifyCarriedNotices(header); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -476,6 +514,13 @@ func (dh *defaultDporHelper) snapshot(dpor *Dpor, chain consensus.ChainReader, n
 
 	log.Debug("now created a new snap", "number", newSnap.number(), "hash", newSnap.hash().Hex(), "apply elapsed", common.PrettyDuration(time.Now().Sub(applyStartTime)))
 
+	// Index the rpts of the elections for later queries
+	for _, rpts := range newSnap.takeElectionRpts() {
+		if err := writeTermRpts(dpor.db, rpts); err != nil {
+			log.Warn("failed to store rpts of election", "term", rpts.Term, "error", err)
+		}
+	}
+
 	// Save to cache
 	dpor.recentSnaps.Add(newSnap.hash(), newSnap)
 
@@ -537,8 +582,14 @@ func (dh *defaultDporHelper) verifySeal(dpor *Dpor, chain consensus.ChainReader,
 	}
 	log.Debug("proposer", "address", proposer.Hex())
 
+	// Resolve the proposer from its signing key
+	proposer, ok := snap.IdentityOf(proposer, number)
+	if !ok {
+		return consensus.ErrUnauthorized
+	}
+
 	// Check if the proposer is right proposer
-	ok, err := snap.IsProposerOf(proposer, number)
+	ok, err = snap.IsProposerOf(proposer, number)
 	if err != nil {
 		return err
 	}
@@ -609,6 +660,11 @@ func (dh *defaultDporHelper) verifySignatures(dpor *Dpor, chain consensus.ChainR
 
 	count := 0
 	for _, v := range validators {
+		// count the signature for the validator the key signs for
+		v, ok := snap.IdentityOf(v, number)
+		if !ok {
+			continue
+		}
 		for _, ev := range expectValidators {
 			if v == ev {
 				count++
@@ -676,7 +732,7 @@ func (dh *defaultDporHelper) signHeader(dpor *Dpor, chain consensus.ChainReader,
 
 	// fulfill all known validator signatures to dpor.sigs to accumulate
 	for signPos, signer := range snap.ValidatorsOf(number) {
-		if sigHash, ok := s.(*signatures).getSig(signer); ok {
+		if sigHash, ok := s.(*signatures).getSig(snap.SignerOf(signer, number)); ok {
 			copy(allSigs[signPos][:], sigHash)
 		}
 	}
@@ -689,15 +745,8 @@ func (dh *defaultDporHelper) signHeader(dpor *Dpor, chain consensus.ChainReader,
 			return errMultiBlocksInOneHeight
 		}
 
-		// get hash with state
-		hashToSign, err := hashBytesWithState(dpor.dh.sigHash(header).Bytes(), state)
-		if err != nil {
-			log.Warn("failed to get hash bytes with state", "number", number, "hash", hash.Hex(), "state", state)
-			return err
-		}
-
-		// Sign it
-		sighash, err := dpor.SignHash(hashToSign)
+		// Sign it with state
+		sighash, err := dpor.signDporHeader(header, state)
 		if err != nil {
 			log.Warn("signing block header failed", "error", err)
 			return err
@@ -719,7 +768,7 @@ func (dh *defaultDporHelper) signHeader(dpor *Dpor, chain consensus.ChainReader,
 		copy(header.Dpor.Sigs[sigPos][:], sighash)
 
 		// Record new sig to signature cache
-		s.(*signatures).setSig(dpor.Coinbase(), sighash)
+		s.(*signatures).setSig(snap.SignerOf(dpor.Coinbase(), number), sighash)
 
 		return nil
 	}
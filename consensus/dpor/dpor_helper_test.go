@@ -17,6 +17,7 @@
 package dpor
 
 import (
+	"crypto/ecdsa"
 	"fmt"
 	"math/big"
 	"reflect"
@@ -25,9 +26,11 @@ import (
 
 	"bitbucket.org/cpchain/chain/configs"
 	"bitbucket.org/cpchain/chain/consensus"
+	"bitbucket.org/cpchain/chain/database"
 	"bitbucket.org/cpchain/chain/types"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
 	lru "github.com/hashicorp/golang-lru"
 )
 
@@ -255,3 +258,170 @@ func Test_dporHelper_verifySeal(t *testing.T) {
 		})
 	}
 }
+
+// rotationChain is a chain of headers a node not running the elections reads
+type rotationChain struct {
+	consensus.ChainReader
+	headers []*types.Header
+}
+
+func (c *rotationChain) GetHeaderByNumber(number uint64) *types.Header {
+	return c.headers[number]
+}
+
+func (c *rotationChain) CurrentHeader() *types.Header {
+	return c.headers[len(c.headers)-1]
+}
+
+func (c *rotationChain) CurrentBlock() *types.Block {
+	return nil
+}
+
+func (c *rotationChain) GetBlock(hash common.Hash, number uint64) *types.Block {
+	return nil
+}
+
+func (c *rotationChain) KnownHead() (common.Hash, uint64) {
+	head := c.CurrentHeader()
+	return head.Hash(), head.Number.Uint64()
+}
+
+func Test_dporHelper_verifyRotatedSigners(t *testing.T) {
+	var (
+		keys       = make([]*ecdsa.PrivateKey, 4)
+		validators = make([]common.Address, 4)
+	)
+	for i := range keys {
+		keys[i], _ = crypto.GenerateKey()
+		validators[i] = crypto.PubkeyToAddress(keys[i].PublicKey)
+	}
+	rotatedKey, _ := crypto.GenerateKey()
+	rotated := map[common.Address]common.Address{validators[0]: crypto.PubkeyToAddress(rotatedKey.PublicKey)}
+
+	// terms of 2 blocks, validators[0] signs with the rotated key from term 1 on
+	config := &configs.DporConfig{Period: 1, TermLen: 2, ViewLen: 1, FaultyNumber: 1, MaxInitBlockNumber: 1000, SignerRotationBlock: big.NewInt(1)}
+	genesis := &types.Header{Number: big.NewInt(0), Time: big.NewInt(0), Dpor: types.DporSnap{Proposers: getProposerAddress()[:2], Validators: validators}}
+	chain := &rotationChain{headers: []*types.Header{genesis}}
+
+	newBlock := func(signers map[common.Address]common.Address, term uint64) *types.Header {
+		parent := chain.CurrentHeader()
+		header := &types.Header{
+			ParentHash: parent.Hash(),
+			Number:     new(big.Int).Add(parent.Number, big.NewInt(1)),
+			Time:       new(big.Int).Add(parent.Time, big.NewInt(1)),
+			GasLimit:   configs.MinGasLimit,
+			Coinbase:   addr1,
+			Extra:      make([]byte, extraVanity),
+			Dpor:       types.DporSnap{Proposers: getProposerAddress()[:2], Sigs: make([]types.DporSignature, 4)},
+		}
+		if err := setExtra(header, &headerExtra{SignersTerm: term, Signers: newSignerBindings(signers)}); err != nil {
+			t.Fatal(err)
+		}
+		return header
+	}
+	sign := func(header *types.Header, keys ...*ecdsa.PrivateKey) {
+		hash, err := HeaderSigHash(header, consensus.Commit)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i, key := range keys {
+			sig, err := crypto.Sign(hash.Bytes(), key)
+			if err != nil {
+				t.Fatal(err)
+			}
+			copy(header.Dpor.Sigs[i][:], sig)
+		}
+	}
+
+	// the first blocks of terms 0 and 1 carry the keys of the next terms
+	chain.headers = append(chain.headers, newBlock(rotated, 1))
+	chain.headers = append(chain.headers, newBlock(nil, 0))
+	chain.headers = append(chain.headers, newBlock(rotated, 2))
+	block := chain.headers[3]
+
+	// a node neither proposing nor validating learns the keys from the headers
+	dpor := New(config, database.NewMemDatabase())
+	parents := chain.headers[1:3]
+
+	sign(block, keys[:3]...)
+	if err := dpor.dh.verifyHeader(dpor, chain, block, parents, nil, true, false); err != consensus.ErrNotEnoughSigs {
+		t.Fatalf("block signed with the retired key of validator 0, err %v, want %v", err, consensus.ErrNotEnoughSigs)
+	}
+
+	sign(block, rotatedKey, keys[1], keys[2])
+	if err := dpor.dh.verifyHeader(dpor, chain, block, parents, nil, true, false); err != nil {
+		t.Fatalf("block signed with the rotated key of validator 0, err %v", err)
+	}
+	if identity := dpor.IdentityOf(rotated[validators[0]], 1); identity != validators[0] {
+		t.Errorf("identity of the rotated key %v, want %v", identity.Hex(), validators[0].Hex())
+	}
+
+	// a block leaving out the keys of the next term is rejected
+	missing := newBlock(nil, 0)
+	missing.ParentHash, missing.Number = block.ParentHash, block.Number
+	sign(missing, rotatedKey, keys[1], keys[2])
+	if err := dpor.dh.verifyHeader(dpor, chain, missing, parents, nil, true, false); err != errInvalidCarriedSigners {
+		t.Fatalf("block without the keys of the next term, err %v, want %v", err, errInvalidCarriedSigners)
+	}
+}
+
+func Test_dporHelper_verifyGovernedPeriod(t *testing.T) {
+	// terms of 2 blocks, the period of term 1 is governed to 3000ms
+	config := &configs.DporConfig{Period: 1000, TermLen: 2, ViewLen: 1, ImpeachTimeout: 10 * time.Second, PeriodGovernorBlock: big.NewInt(1)}
+	genesis := &types.Header{Number: big.NewInt(0), Time: big.NewInt(0), Dpor: types.DporSnap{Proposers: getProposerAddress()[:2], Validators: getValidatorAddress()}}
+	chain := &rotationChain{headers: []*types.Header{genesis}}
+
+	newBlock := func(interval int64, term uint64, period uint64) *types.Header {
+		parent := chain.CurrentHeader()
+		header := &types.Header{
+			ParentHash: parent.Hash(),
+			Number:     new(big.Int).Add(parent.Number, big.NewInt(1)),
+			Time:       new(big.Int).Add(parent.Time, big.NewInt(interval)),
+			GasLimit:   configs.MinGasLimit,
+			Coinbase:   addr1,
+			Extra:      make([]byte, extraVanity),
+			Dpor:       types.DporSnap{Proposers: getProposerAddress()[:2]},
+		}
+		if err := setExtra(header, &headerExtra{PeriodTerm: term, Period: period}); err != nil {
+			t.Fatal(err)
+		}
+		return header
+	}
+
+	// a node neither proposing nor validating learns the periods from the headers
+	dpor := New(config, database.NewMemDatabase())
+	verify := func(header *types.Header) error {
+		return dpor.dh.verifyHeader(dpor, chain, header, chain.headers[1:], nil, false, false)
+	}
+
+	// the first block of term 0 carries the period of term 1
+	block := newBlock(1000, 1, 3000)
+	if err := verify(block); err != nil {
+		t.Fatalf("block 1 carrying the period of term 1, err %v", err)
+	}
+	chain.headers = append(chain.headers, block)
+
+	// the last block of term 0 keeps the period of the config
+	block = newBlock(1000, 0, 0)
+	if err := verify(block); err != nil {
+		t.Fatalf("block 2 after the period of the config, err %v", err)
+	}
+	chain.headers = append(chain.headers, block)
+
+	// the first block of term 1 applies the new period
+	if err := verify(newBlock(1000, 2, 3000)); err != ErrInvalidTimestamp {
+		t.Fatalf("block 3 after the period of the config, err %v, want %v", err, ErrInvalidTimestamp)
+	}
+	if err := verify(newBlock(3000, 0, 0)); err != errInvalidCarriedPeriod {
+		t.Fatalf("block 3 without the period of term 2, err %v, want %v", err, errInvalidCarriedPeriod)
+	}
+	block = newBlock(3000, 2, 3000)
+	if err := verify(block); err != nil {
+		t.Fatalf("block 3 after the governed period, err %v", err)
+	}
+	chain.headers = append(chain.headers, block)
+
+	if period := dpor.CurrentSnap().PeriodOf(3); period != 3000 {
+		t.Errorf("period of block 3 %d, want 3000", period)
+	}
+}
@@ -4,21 +4,27 @@ import (
 	"math/big"
 	"time"
 
-	"bitbucket.org/cpchain/chain/accounts"
 	"bitbucket.org/cpchain/chain/configs"
 	"bitbucket.org/cpchain/chain/consensus"
+	"bitbucket.org/cpchain/chain/consensus/dpor/backend"
 	"bitbucket.org/cpchain/chain/types"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
-	"github.com/ethereum/go-ethereum/crypto/sha3"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/p2p"
 )
 
 // Those are functions implement backend.DporService
 
-// Period returns period of block generation
+// Period returns period of block generation, the one before the next block
 func (d *Dpor) Period() time.Duration {
+	if d.chain == nil || d.chain.CurrentHeader() == nil {
+		return d.config.PeriodDuration()
+	}
+	number := d.chain.CurrentHeader().Number.Uint64() + 1
+	if snap := d.CurrentSnap(); snap != nil {
+		return snap.PeriodDurationOf(number)
+	}
 	return d.config.PeriodDuration()
 }
 
@@ -86,7 +92,7 @@ func (d *Dpor) VerifyProposerOf(signer common.Address, term uint64) (bool, error
 	log.Debug("proposers in dpor current snapshot", "count", len(proposers), "term", term)
 
 	for _, p := range proposers {
-		if p == signer {
+		if p == signer || snap.signerOfTerm(p, term) == signer {
 			return true, nil
 		}
 	}
@@ -107,7 +113,7 @@ func (d *Dpor) VerifyValidatorOf(signer common.Address, term uint64) (bool, erro
 	log.Debug("validators in dpor current snapshot", "count", len(validators), "term", term)
 
 	for _, p := range validators {
-		if p == signer {
+		if p == signer || snap.signerOfTerm(p, term) == signer {
 			return true, nil
 		}
 	}
@@ -115,6 +121,20 @@ func (d *Dpor) VerifyValidatorOf(signer common.Address, term uint64) (bool, erro
 	return false, nil
 }
 
+// IdentityOf returns the proposer or validator a key signs for in given term
+func (d *Dpor) IdentityOf(signer common.Address, term uint64) common.Address {
+	snap := d.CurrentSnap()
+	if snap == nil {
+		log.Warn("currentSnap field is nil")
+		return signer
+	}
+
+	if identity, ok := snap.identityOfTerm(signer, term); ok {
+		return identity
+	}
+	return signer
+}
+
 // ValidatorsOf returns validators of given block number
 func (d *Dpor) ValidatorsOf(number uint64) ([]common.Address, error) {
 	snap := d.currentSnap
@@ -254,14 +274,20 @@ func (d *Dpor) CreateImpeachBlock() (*types.Block, error) {
 		StateRoot:  parentHeader.StateRoot,
 	}
 
-	for _, proposer := range d.CurrentSnap().ProposersOf(parentNum + 1) {
+	snap := d.CurrentSnap()
+	for _, proposer := range snap.ProposersOf(parentNum + 1) {
 		impeachHeader.Dpor.Proposers = append(impeachHeader.Dpor.Proposers, proposer)
 	}
 	impeachHeader.Dpor.Sigs = make([]types.DporSignature, d.config.ValidatorsLen())
 
-	timestamp := parent.Timestamp().Add(d.config.PeriodDuration()).Add(d.config.ImpeachTimeout)
+	timestamp := parent.Timestamp().Add(snap.PeriodDurationOf(parentNum + 1)).Add(d.config.ImpeachTimeout)
 	impeachHeader.SetTimestamp(timestamp)
 
+	// the proposer announced to skip the block on chain, impeach it without waiting for the timeout
+	if snap.excused(parentNum + 1) {
+		impeachHeader.SetTimestamp(parent.Timestamp().Add(snap.PeriodDurationOf(parentNum + 1)))
+	}
+
 	impeach := types.NewBlock(impeachHeader, []*types.Transaction{}, []*types.Receipt{})
 
 	return impeach, nil
@@ -281,14 +307,20 @@ func (d *Dpor) CreateImpeachBlockAt(parentHeader *types.Header) (*types.Block, e
 		StateRoot:  parentHeader.StateRoot,
 	}
 
-	for _, proposer := range d.CurrentSnap().ProposersOf(parentNum + 1) {
+	snap := d.CurrentSnap()
+	for _, proposer := range snap.ProposersOf(parentNum + 1) {
 		impeachHeader.Dpor.Proposers = append(impeachHeader.Dpor.Proposers, proposer)
 	}
 	impeachHeader.Dpor.Sigs = make([]types.DporSignature, d.config.ValidatorsLen())
 
-	timestamp := parent.Timestamp().Add(d.config.PeriodDuration()).Add(d.config.ImpeachTimeout)
+	timestamp := parent.Timestamp().Add(snap.PeriodDurationOf(parentNum + 1)).Add(d.config.ImpeachTimeout)
 	impeachHeader.SetTimestamp(timestamp)
 
+	// the proposer announced to skip the block on chain, impeach it without waiting for the timeout
+	if snap.excused(parentNum + 1) {
+		impeachHeader.SetTimestamp(parent.Timestamp().Add(snap.PeriodDurationOf(parentNum + 1)))
+	}
+
 	impeach := types.NewBlock(impeachHeader, []*types.Transaction{}, []*types.Receipt{})
 
 	return impeach, nil
@@ -390,21 +422,12 @@ func (d *Dpor) GetMac() (mac string, sig []byte, err error) {
 	// mac is like this: "cpchain|2019-02-26T16:22:21+08:00"
 
 	// compose the msg
-	prefix := "cpchain"
-	t := time.Now().Format(time.RFC3339)
-	split := "|"
-	mac = prefix + split + t
-
-	// make a hash for it
-	var hash common.Hash
-	hasher := sha3.NewKeccak256()
-	hasher.Write([]byte(mac))
-	hasher.Sum(hash[:0])
+	mac = backend.NewMac(time.Now())
 
 	log.Debug("generated mac", "mac", mac)
 
 	// sign it!
-	sig, err = d.signFn(accounts.Account{Address: d.Coinbase()}, hash.Bytes())
+	sig, err = d.signDporMac(mac)
 
 	return mac, sig, err
 }
@@ -19,6 +19,7 @@ package dpor
 import (
 	"bytes"
 	"encoding/binary"
+	"fmt"
 	"sync"
 	"time"
 
@@ -262,3 +263,18 @@ func hashBytesWithState(hash []byte, state consensus.State) (signHashBytes []byt
 	signHashBytes = signHash.Bytes()
 	return
 }
+
+// HeaderSigHash returns the hash a proposer or validator signs for header in
+// state, the seal of the proposer is signed in Commit state
+func HeaderSigHash(header *types.Header, state consensus.State) (common.Hash, error) {
+	switch state {
+	case consensus.Prepare, consensus.Commit, consensus.ImpeachPrepare, consensus.ImpeachCommit:
+	default:
+		return common.Hash{}, fmt.Errorf("unknown state %d to sign a header in", state)
+	}
+	hash, err := hashBytesWithState((&defaultDporUtil{}).sigHash(header).Bytes(), state)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return common.BytesToHash(hash), nil
+}
@@ -0,0 +1,40 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package dpor
+
+// Storage layout of the deposits, exported to the tests against the compiled
+// contracts, which cannot be deployed from package dpor itself.
+const (
+	RnodeThresholdSlot     = rnodeThresholdSlot
+	RnodeSetSlot           = rnodeSetSlot
+	RnodeParticipantsSlot  = rnodeParticipantsSlot
+	RnodeLockedDepositItem = rnodeLockedDepositItem
+
+	RewardElectionCriteriaSlot = rewardElectionCriteriaSlot
+	RewardBonusPoolSlot        = rewardBonusPoolSlot
+	RewardRnodeSetSlot         = rewardRnodeSetSlot
+	RewardInvestorsSlot        = rewardInvestorsSlot
+	RewardFreeDepositItem      = rewardFreeDepositItem
+	RewardLockedDepositItem    = rewardLockedDepositItem
+)
+
+var (
+	StorageSlot = storageSlot
+	MappingSlot = mappingSlot
+	DepositSlot = depositSlot
+	ArraySlot   = arraySlot
+)
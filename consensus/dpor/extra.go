@@ -0,0 +1,292 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package dpor
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"sort"
+
+	"bitbucket.org/cpchain/chain/commons/log"
+	"bitbucket.org/cpchain/chain/configs"
+	"bitbucket.org/cpchain/chain/consensus/dpor/backend"
+	"bitbucket.org/cpchain/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+var (
+	// errInvalidExtra is returned if the consensus data after the vanity of
+	// the extra data of a proposed block can not be decoded.
+	errInvalidExtra = errors.New("invalid consensus data in extra")
+
+	// errInvalidCarriedSigners is returned if a proposed block does not carry
+	// the signing keys of the next committee when due, or carries others.
+	errInvalidCarriedSigners = errors.New("invalid signing keys carried in extra")
+
+	// errInvalidCarriedPeriod is returned if a proposed block does not carry
+	// the block period of the next term when due, or carries another.
+	errInvalidCarriedPeriod = errors.New("invalid block period carried in extra")
+)
+
+// signerBinding is a signing key bound to the identity of a proposer or a validator
+type signerBinding struct {
+	Identity common.Address
+	Signer   common.Address
+}
+
+// headerExtra is the consensus data a proposed block carries after the vanity
+// of its extra data, the extra data of a block carrying none is the vanity.
+type headerExtra struct {
+	SignersTerm uint64          // Term the signing keys are bound in, 0 if the block carries none
+	Signers     []signerBinding // Signing keys of the committee of SignersTerm not being the identities, sorted by identity
+	PeriodTerm  uint64          // Term the block period applies to, 0 if the block carries none
+	Period      uint64          // Block period of PeriodTerm in milliseconds
+
+	Notices []*backend.MaintenanceNotice `rlp:"tail"` // Maintenance notices of proposers not on chain before
+}
+
+func (e *headerExtra) empty() bool {
+	return e.SignersTerm == 0 && len(e.Signers) == 0 && e.PeriodTerm == 0 && e.Period == 0 && len(e.Notices) == 0
+}
+
+// hasExtra returns if the proposed blocks of number carry consensus data
+// after the vanity of their extra data
+func hasExtra(config *configs.DporConfig, number *big.Int) bool {
+	return config.IsSignerRotation(number) || config.IsPeriodGoverned(number) || config.IsSlashing(number)
+}
+
+// signers returns the signing keys the extra carries, identity => signer
+func (e *headerExtra) signers() map[common.Address]common.Address {
+	signers := make(map[common.Address]common.Address, len(e.Signers))
+	for _, binding := range e.Signers {
+		signers[binding.Identity] = binding.Signer
+	}
+	return signers
+}
+
+// newSignerBindings returns the signing keys sorted by identity
+func newSignerBindings(signers map[common.Address]common.Address) []signerBinding {
+	bindings := make([]signerBinding, 0, len(signers))
+	for identity, signer := range signers {
+		bindings = append(bindings, signerBinding{Identity: identity, Signer: signer})
+	}
+	sort.Slice(bindings, func(i, j int) bool {
+		return bytes.Compare(bindings[i].Identity[:], bindings[j].Identity[:]) < 0
+	})
+	return bindings
+}
+
+// extraOf decodes the consensus data of a proposed block header
+func extraOf(header *types.Header) (*headerExtra, error) {
+	extra := new(headerExtra)
+	if header.Impeachment() || len(header.Extra) <= extraVanity {
+		return extra, nil
+	}
+	if err := rlp.DecodeBytes(header.Extra[extraVanity:], extra); err != nil {
+		return nil, errInvalidExtra
+	}
+	// a block carrying none leaves the data out
+	if extra.empty() {
+		return nil, errInvalidExtra
+	}
+	return extra, nil
+}
+
+// setExtra sets the consensus data after the vanity of header's extra data
+func setExtra(header *types.Header, extra *headerExtra) error {
+	vanity := make([]byte, extraVanity)
+	copy(vanity, header.Extra)
+	header.Extra = vanity
+
+	if extra.empty() {
+		return nil
+	}
+	data, err := rlp.EncodeToBytes(extra)
+	if err != nil {
+		return err
+	}
+	header.Extra = append(header.Extra, data...)
+	return nil
+}
+
+// signersTermDue returns the term the signing keys a proposed block number on
+// top of the snapshot must carry are bound in, 0 if it carries none. The first
+// proposed block of a term carries the keys of the next term, which are final
+// by then as the signer register binds keys more than an election ahead.
+// If all the blocks of a term are impeached, the next term signs with the
+// identities.
+func (s *DporSnapshot) signersTermDue(number uint64) uint64 {
+	if !s.config.IsSignerRotation(new(big.Int).SetUint64(number)) {
+		return 0
+	}
+	term := s.TermOf(number) + 1
+	if s.hasSigners(term) {
+		return 0
+	}
+	return term
+}
+
+// periodTermDue returns the term the block period a proposed block number on
+// top of the snapshot must carry applies to, 0 if it carries none. As the
+// signing keys, the first proposed block of a term carries the period of the
+// next term, which the period governor sets more than an election ahead.
+func (s *DporSnapshot) periodTermDue(number uint64) uint64 {
+	if !s.config.IsPeriodGoverned(new(big.Int).SetUint64(number)) {
+		return 0
+	}
+	term := s.TermOf(number) + 1
+	if s.hasPeriod(term) {
+		return 0
+	}
+	return term
+}
+
+// verifyExtra verifies the consensus data of a proposed block header on top
+// of the snapshot. It only checks the data is due and well formed, the
+// validators check the signing keys against the signer register, the block
+// period against the period governor and the maintenance notices against the
+// proposers elected.
+func (s *DporSnapshot) verifyExtra(header *types.Header) error {
+	if len(header.Extra) < extraVanity {
+		return errInvalidExtra
+	}
+	extra, err := extraOf(header)
+	if err != nil {
+		return err
+	}
+
+	if extra.SignersTerm != s.signersTermDue(header.Number.Uint64()) {
+		return errInvalidCarriedSigners
+	}
+	if extra.SignersTerm == 0 && len(extra.Signers) != 0 {
+		return errInvalidCarriedSigners
+	}
+
+	// a key signs for one identity only, so every node resolves the same one
+	keys := make(map[common.Address]struct{}, len(extra.Signers))
+	for i, binding := range extra.Signers {
+		if i > 0 && bytes.Compare(extra.Signers[i-1].Identity[:], binding.Identity[:]) >= 0 {
+			return errInvalidCarriedSigners
+		}
+		if binding.Signer == (common.Address{}) || binding.Signer == binding.Identity {
+			return errInvalidCarriedSigners
+		}
+		if _, ok := keys[binding.Signer]; ok {
+			return errInvalidCarriedSigners
+		}
+		keys[binding.Signer] = struct{}{}
+	}
+
+	if extra.PeriodTerm != s.periodTermDue(header.Number.Uint64()) {
+		return errInvalidCarriedPeriod
+	}
+	if (extra.PeriodTerm == 0) != (extra.Period == 0) {
+		return errInvalidCarriedPeriod
+	}
+	return s.verifyNotices(header, extra.Notices)
+}
+
+// signersOf reads the signing keys bound to the committee of term from the
+// signer register at block number, leaving out the identities signing with
+// their own keys.
+func (d *Dpor) signersOf(snap *DporSnapshot, term uint64, number uint64) ([]signerBinding, error) {
+	service := d.GetSignerBackend()
+	if service == nil {
+		return nil, nil
+	}
+
+	// validators are carried over to following terms
+	var identities []common.Address
+	identities = append(identities, snap.getRecentProposers(term)...)
+	identities = append(identities, snap.ValidatorsOf(number+1)...)
+
+	signers, err := service.SignersOf(term, identities, number)
+	if err != nil {
+		log.Warn("read signing keys error", "term", term, "number", number, "err", err)
+		return nil, err
+	}
+	return newSignerBindings(signers), nil
+}
+
+// verifyCarriedSigners verifies the signing keys a proposed block carries are
+// the ones bound in the signer register at its parent.
+func (d *Dpor) verifyCarriedSigners(snap *DporSnapshot, header *types.Header) error {
+	extra, err := extraOf(header)
+	if err != nil {
+		return err
+	}
+	if extra.SignersTerm == 0 {
+		return nil
+	}
+
+	signers, err := d.signersOf(snap, extra.SignersTerm, header.Number.Uint64()-1)
+	if err != nil {
+		return err
+	}
+	if len(signers) != len(extra.Signers) {
+		return errInvalidCarriedSigners
+	}
+	for i, binding := range signers {
+		if extra.Signers[i] != binding {
+			return errInvalidCarriedSigners
+		}
+	}
+	return nil
+}
+
+// periodOf reads the block period of term from the period governor at block
+// number, the period of the term of block number applies if none is set.
+func (d *Dpor) periodOf(snap *DporSnapshot, term uint64, number uint64) (uint64, error) {
+	current := snap.PeriodOf(number + 1)
+	service := d.GetPeriodBackend()
+	if service == nil {
+		return current, nil
+	}
+
+	period, err := service.PeriodOf(term, number)
+	if err != nil {
+		log.Warn("read block period error", "term", term, "number", number, "err", err)
+		return 0, err
+	}
+	if period == 0 {
+		return current, nil
+	}
+	return period, nil
+}
+
+// verifyCarriedPeriod verifies the block period a proposed block carries is
+// the one set in the period governor at its parent.
+func (d *Dpor) verifyCarriedPeriod(snap *DporSnapshot, header *types.Header) error {
+	extra, err := extraOf(header)
+	if err != nil {
+		return err
+	}
+	if extra.PeriodTerm == 0 {
+		return nil
+	}
+
+	period, err := d.periodOf(snap, extra.PeriodTerm, header.Number.Uint64()-1)
+	if err != nil {
+		return err
+	}
+	if extra.Period != period {
+		return errInvalidCarriedPeriod
+	}
+	return nil
+}
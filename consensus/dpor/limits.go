@@ -0,0 +1,99 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package dpor
+
+import (
+	"math/big"
+	"time"
+
+	"bitbucket.org/cpchain/chain/configs"
+	"bitbucket.org/cpchain/chain/types"
+)
+
+// BlockLimits is the gas usage of a block, and the period and the gas limit
+// bounds of the block after it.
+type BlockLimits struct {
+	Number      uint64  `json:"number"`
+	GasLimit    uint64  `json:"gasLimit"`
+	GasUsed     uint64  `json:"gasUsed"`
+	Utilisation float64 `json:"utilisation"` // gas used over gas limit
+	Period      uint64  `json:"period"`      // milliseconds before the next block
+	Voting      bool    `json:"voting"`      // proposers vote for the gas limit of the next block
+	MinGasLimit uint64  `json:"minGasLimit"` // bounds of the gas limit of the next block
+	MaxGasLimit uint64  `json:"maxGasLimit"`
+}
+
+// limitsOf returns the limits of block header, snap is the snapshot at it
+func limitsOf(config *configs.DporConfig, snap *DporSnapshot, header *types.Header) *BlockLimits {
+	number := header.Number.Uint64()
+	period := snap.PeriodOf(number + 1)
+	limits := &BlockLimits{
+		Number:      number,
+		GasLimit:    header.GasLimit,
+		GasUsed:     header.GasUsed,
+		Period:      period,
+		MinGasLimit: configs.MinGasLimit,
+		MaxGasLimit: configs.MaxGasLimit,
+	}
+	if header.GasLimit > 0 {
+		limits.Utilisation = float64(header.GasUsed) / float64(header.GasLimit)
+	}
+
+	if config.IsGasLimitVoting(new(big.Int).SetUint64(number + 1)) {
+		limits.Voting = true
+		limits.MinGasLimit, limits.MaxGasLimit = config.GasLimit.Bounds(header.GasLimit)
+	}
+	return limits
+}
+
+// PeriodOf returns the block period in milliseconds before block number, the
+// one carried in the headers for its term, or else the one of the chain config.
+func (s *DporSnapshot) PeriodOf(number uint64) uint64 {
+	if period, ok := s.periodOfTerm(s.TermOf(number)); ok {
+		return period
+	}
+	return s.config.Period
+}
+
+// PeriodDurationOf returns the block period before block number.
+func (s *DporSnapshot) PeriodDurationOf(number uint64) time.Duration {
+	period := s.PeriodOf(number)
+	return time.Duration(int64(period) * int64(time.Millisecond))
+}
+
+// periodsBetween returns the sum of the periods of the blocks after block from
+// up to block to in milliseconds, the time between them if none is impeached.
+func (s *DporSnapshot) periodsBetween(from, to uint64) uint64 {
+	blocks := s.config.TermLen * s.config.ViewLen
+	if blocks == 0 {
+		period := s.PeriodOf(to)
+		return (to - from) * period
+	}
+
+	var sum uint64
+	for n := from; n < to; {
+		// the blocks after n up to the end of its term share a period
+		end := (s.TermOf(n+1) + 1) * blocks
+		if end > to {
+			end = to
+		}
+		period := s.PeriodOf(n + 1)
+		sum += (end - n) * period
+		n = end
+	}
+	return sum
+}
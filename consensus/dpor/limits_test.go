@@ -0,0 +1,52 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package dpor
+
+import (
+	"math/big"
+	"testing"
+
+	"bitbucket.org/cpchain/chain/configs"
+	"bitbucket.org/cpchain/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestLimitsOf(t *testing.T) {
+	config := &configs.DporConfig{
+		Period:   1000,
+		TermLen:  2,
+		ViewLen:  2,
+		GasLimit: &configs.GasLimitConfig{Block: big.NewInt(5), StepDivisor: 100},
+	}
+	snap := newSnapshot(config, 0, common.Hash{}, nil, nil, NormalMode)
+	snap.setRecentPeriod(1, 3000)
+	header := &types.Header{Number: big.NewInt(3), GasLimit: 100000000, GasUsed: 25000000}
+
+	limits := limitsOf(config, snap, header)
+	if limits.Period != 1000 || limits.Utilisation != 0.25 || limits.Voting {
+		t.Fatalf("limits of block 3 %+v, want period 1000, utilisation 0.25, no voting", limits)
+	}
+	if limits.MinGasLimit != configs.MinGasLimit || limits.MaxGasLimit != configs.MaxGasLimit {
+		t.Fatalf("bounds %d-%d before voting", limits.MinGasLimit, limits.MaxGasLimit)
+	}
+
+	header.Number = big.NewInt(4)
+	limits = limitsOf(config, snap, header)
+	if limits.Period != 3000 || !limits.Voting || limits.MinGasLimit != 99000000 || limits.MaxGasLimit != 101000000 {
+		t.Fatalf("limits of block 4 %+v, want period 3000, voting within 99000000-101000000", limits)
+	}
+}
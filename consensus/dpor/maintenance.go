@@ -0,0 +1,327 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package dpor
+
+import (
+	"errors"
+	"math/big"
+	"sync"
+
+	"bitbucket.org/cpchain/chain/commons/log"
+	"bitbucket.org/cpchain/chain/consensus/dpor/backend"
+	"bitbucket.org/cpchain/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// maxMaintenanceTerms is the max number of terms a maintenance notice covers,
+// the proposers of later terms are not elected yet
+const maxMaintenanceTerms = TermDistBetweenElectionAndMining + 1
+
+// maxCarriedNotices is the max number of maintenance notices a block carries
+const maxCarriedNotices = 4
+
+var (
+	errInvalidMaintenanceRange = errors.New("invalid maintenance range")
+	errNoMaintenanceBlock      = errors.New("no block of the coinbase to skip in the range")
+	errNilSnapshot             = errors.New("current snapshot is not ready")
+	errMaintenanceDisabled     = errors.New("maintenance notices are carried from the slashing fork on")
+
+	// errInvalidCarriedNotices is returned if a proposed block carries maintenance
+	// notices malformed, on chain already or not signed by a proposer of their blocks
+	errInvalidCarriedNotices = errors.New("invalid maintenance notices carried in extra")
+)
+
+// sameNotice returns if notices a and b are signed by the same key for the same blocks
+func sameNotice(a, b *backend.MaintenanceNotice) bool {
+	if a.From != b.From || a.To != b.To {
+		return false
+	}
+	signerA, errA := a.Signer()
+	signerB, errB := b.Signer()
+	return errA == nil && errB == nil && signerA == signerB
+}
+
+// maintenanceRecord keeps the maintenance notices received, the proposers
+// carry them in their blocks until they are on chain
+type maintenanceRecord struct {
+	notices []*backend.MaintenanceNotice
+	lock    sync.RWMutex
+}
+
+func newMaintenanceRecord() *maintenanceRecord {
+	return &maintenanceRecord{}
+}
+
+// add adds a notice, returns false if it is known
+func (r *maintenanceRecord) add(notice *backend.MaintenanceNotice) bool {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	for _, n := range r.notices {
+		if sameNotice(n, notice) {
+			return false
+		}
+	}
+	r.notices = append(r.notices, notice)
+	return true
+}
+
+// pending returns the notices block number on top of snap carries, the valid
+// ones not on chain yet
+func (r *maintenanceRecord) pending(snap *DporSnapshot, number uint64) []*backend.MaintenanceNotice {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	var notices []*backend.MaintenanceNotice
+	for _, n := range r.notices {
+		if len(notices) == maxCarriedNotices {
+			break
+		}
+		if n.From <= number || snap.hasNotice(n) {
+			continue
+		}
+		if _, err := snap.maintainerOf(n); err != nil {
+			continue
+		}
+		notices = append(notices, n)
+	}
+	return notices
+}
+
+// prune drops the notices no block from block number on can carry
+func (r *maintenanceRecord) prune(number uint64) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	var kept []*backend.MaintenanceNotice
+	for _, n := range r.notices {
+		if n.From > number {
+			kept = append(kept, n)
+		}
+	}
+	r.notices = kept
+}
+
+// checkMaintenanceRange checks the blocks of notice are within the terms elected
+func (s *DporSnapshot) checkMaintenanceRange(notice *backend.MaintenanceNotice) error {
+	if notice.To < notice.From || notice.To-notice.From >= maxMaintenanceTerms*s.config.TermLen*s.config.ViewLen {
+		return errInvalidMaintenanceRange
+	}
+	return nil
+}
+
+// maintainerOf returns the proposer signing notice, it must be the proposer
+// of a block the notice covers.
+func (s *DporSnapshot) maintainerOf(notice *backend.MaintenanceNotice) (common.Address, error) {
+	if err := s.checkMaintenanceRange(notice); err != nil {
+		return common.Address{}, err
+	}
+	signer, err := notice.Signer()
+	if err != nil {
+		return common.Address{}, backend.ErrInvalidMaintenanceNotice
+	}
+	for number := notice.From; number <= notice.To; number++ {
+		proposer, ok := s.proposerOf(number)
+		if !ok {
+			continue
+		}
+		if identity, ok := s.IdentityOf(signer, number); ok && identity == proposer {
+			return proposer, nil
+		}
+	}
+	return common.Address{}, backend.ErrInvalidMaintenanceNotice
+}
+
+// hasNotice returns if a notice of the same key for the same blocks is on chain
+func (s *DporSnapshot) hasNotice(notice *backend.MaintenanceNotice) bool {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	for _, n := range s.Notices {
+		if sameNotice(n, notice) {
+			return true
+		}
+	}
+	return false
+}
+
+// addNotices records the maintenance notices a block carries
+func (s *DporSnapshot) addNotices(notices []*backend.MaintenanceNotice) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for _, n := range notices {
+		log.Debug("maintenance notice on chain", "from", n.From, "to", n.To)
+		s.Notices = append(s.Notices, n)
+	}
+}
+
+// pruneNotices drops the notices ending before block number
+func (s *DporSnapshot) pruneNotices(number uint64) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	var kept []*backend.MaintenanceNotice
+	for _, n := range s.Notices {
+		if n.To >= number {
+			kept = append(kept, n)
+		}
+	}
+	s.Notices = kept
+}
+
+// excused returns if the proposer of block number announced to skip it in a
+// maintenance notice on chain.
+func (s *DporSnapshot) excused(number uint64) bool {
+	proposer, ok := s.proposerOf(number)
+	if !ok {
+		return false
+	}
+
+	s.lock.RLock()
+	notices := s.Notices
+	s.lock.RUnlock()
+
+	for _, n := range notices {
+		if !n.Covers(number) {
+			continue
+		}
+		signer, err := n.Signer()
+		if err != nil {
+			continue
+		}
+		if identity, ok := s.IdentityOf(signer, number); ok && identity == proposer {
+			return true
+		}
+	}
+	return false
+}
+
+// verifyNotices verifies the maintenance notices a proposed block header
+// carries are well formed and not on chain yet.
+func (s *DporSnapshot) verifyNotices(header *types.Header, notices []*backend.MaintenanceNotice) error {
+	if len(notices) == 0 {
+		return nil
+	}
+	if !s.config.IsSlashing(header.Number) || len(notices) > maxCarriedNotices {
+		return errInvalidCarriedNotices
+	}
+
+	number := header.Number.Uint64()
+	for i, notice := range notices {
+		if notice.From <= number || s.checkMaintenanceRange(notice) != nil {
+			return errInvalidCarriedNotices
+		}
+		if _, err := notice.Signer(); err != nil {
+			return errInvalidCarriedNotices
+		}
+		if s.hasNotice(notice) {
+			return errInvalidCarriedNotices
+		}
+		for _, n := range notices[:i] {
+			if sameNotice(n, notice) {
+				return errInvalidCarriedNotices
+			}
+		}
+	}
+	return nil
+}
+
+// verifyCarriedNotices verifies the maintenance notices a proposed block
+// carries are signed by a proposer of a block they cover.
+func (s *DporSnapshot) verifyCarriedNotices(header *types.Header) error {
+	extra, err := extraOf(header)
+	if err != nil {
+		return err
+	}
+	for _, notice := range extra.Notices {
+		if _, err := s.maintainerOf(notice); err != nil {
+			return errInvalidCarriedNotices
+		}
+	}
+	return nil
+}
+
+// EnterMaintenance announces the local coinbase skips its blocks from block
+// from to block to. Once a proposer carries the notice in a block before
+// from, the validators impeach the blocks without waiting for the impeach
+// timeout, and the skips are not counted as offences.
+func (d *Dpor) EnterMaintenance(from, to uint64) (*backend.MaintenanceNotice, error) {
+	current := d.chain.CurrentHeader().Number.Uint64()
+	if !d.config.IsSlashing(new(big.Int).SetUint64(current + 1)) {
+		return nil, errMaintenanceDisabled
+	}
+	// a block before from carries the notice
+	if from <= current+1 {
+		return nil, errInvalidMaintenanceRange
+	}
+
+	snap := d.CurrentSnap()
+	if snap == nil {
+		return nil, errNilSnapshot
+	}
+
+	notice := &backend.MaintenanceNotice{From: from, To: to}
+	sig, err := d.signDporMaintenance(notice)
+	if err != nil {
+		return nil, err
+	}
+	notice.Signature = sig
+
+	proposer, err := snap.maintainerOf(notice)
+	if err == backend.ErrInvalidMaintenanceNotice {
+		return nil, errNoMaintenanceBlock
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	d.maintenance.prune(current + 1)
+	d.maintenance.add(notice)
+	log.Info("entering maintenance", "proposer", proposer.Hex(), "from", from, "to", to)
+
+	go d.handler.BroadcastMaintenanceNotice(notice)
+	return notice, nil
+}
+
+// AddMaintenanceNotice implements backend.DporService, it adds a maintenance
+// notice received from a remote proposer or validator to carry it on chain.
+func (d *Dpor) AddMaintenanceNotice(notice *backend.MaintenanceNotice) (bool, error) {
+	current := d.chain.CurrentHeader().Number.Uint64()
+	if notice.From <= current+1 {
+		return false, errInvalidMaintenanceRange
+	}
+
+	snap := d.CurrentSnap()
+	if snap == nil {
+		return false, errNilSnapshot
+	}
+	proposer, err := snap.maintainerOf(notice)
+	if err != nil {
+		return false, err
+	}
+	if snap.hasNotice(notice) {
+		return false, nil
+	}
+
+	d.maintenance.prune(current + 1)
+	added := d.maintenance.add(notice)
+	if added {
+		log.Debug("added maintenance notice", "proposer", proposer.Hex(), "from", notice.From, "to", notice.To)
+	}
+	return added, nil
+}
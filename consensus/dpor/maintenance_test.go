@@ -0,0 +1,242 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package dpor
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"bitbucket.org/cpchain/chain/configs"
+	"bitbucket.org/cpchain/chain/consensus/dpor/backend"
+	"bitbucket.org/cpchain/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func signMaintenance(t *testing.T, key *ecdsa.PrivateKey, from, to uint64) *backend.MaintenanceNotice {
+	notice := &backend.MaintenanceNotice{From: from, To: to}
+	sig, err := crypto.Sign(notice.SigHash().Bytes(), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	notice.Signature = sig
+	return notice
+}
+
+func newMaintenanceSnapshot() (*DporSnapshot, *ecdsa.PrivateKey) {
+	key, _ := crypto.GenerateKey()
+	config := &configs.DporConfig{TermLen: 3, ViewLen: 1, Slashing: &configs.SlashingConfig{
+		Block:          big.NewInt(1),
+		ImpeachPenalty: big.NewInt(10),
+		OffenceWindow:  2,
+	}}
+	proposers := getProposerAddress()[:3]
+	proposers[1] = crypto.PubkeyToAddress(key.PublicKey)
+	snap := newSnapshot(config, 0, common.Hash{}, proposers, getValidatorAddress(), NormalMode)
+	for term := uint64(1); term < 4; term++ {
+		snap.setRecentProposers(term, proposers)
+	}
+	return snap, key
+}
+
+func TestSnapshot_maintainerOf(t *testing.T) {
+	snap, key := newMaintenanceSnapshot()
+	proposer := crypto.PubkeyToAddress(key.PublicKey)
+	other, _ := crypto.GenerateKey()
+
+	if got, err := snap.maintainerOf(signMaintenance(t, key, 2, 5)); err != nil || got != proposer {
+		t.Fatalf("maintainer %v, %v, want %v", got.Hex(), err, proposer.Hex())
+	}
+	// the proposer of no block in the range
+	if _, err := snap.maintainerOf(signMaintenance(t, key, 3, 4)); err != backend.ErrInvalidMaintenanceNotice {
+		t.Fatalf("maintainer of a range without its blocks, err %v", err)
+	}
+	if _, err := snap.maintainerOf(signMaintenance(t, other, 2, 5)); err != backend.ErrInvalidMaintenanceNotice {
+		t.Fatalf("maintainer of a notice of a non proposer, err %v", err)
+	}
+	// at most the terms elected
+	if _, err := snap.maintainerOf(signMaintenance(t, key, 2, 11)); err != errInvalidMaintenanceRange {
+		t.Fatalf("maintainer of a range too long, err %v", err)
+	}
+	if _, err := snap.maintainerOf(signMaintenance(t, key, 5, 2)); err != errInvalidMaintenanceRange {
+		t.Fatalf("maintainer of a reversed range, err %v", err)
+	}
+}
+
+// newNoticesHeader returns a proposed block header carrying notices
+func newNoticesHeader(t *testing.T, number uint64, notices ...*backend.MaintenanceNotice) *types.Header {
+	header := &types.Header{Number: new(big.Int).SetUint64(number), Coinbase: common.Address{0x01}}
+	if err := setExtra(header, &headerExtra{Notices: notices}); err != nil {
+		t.Fatal(err)
+	}
+	return header
+}
+
+func TestSnapshot_recordSkip(t *testing.T) {
+	snap, key := newMaintenanceSnapshot()
+	proposer := crypto.PubkeyToAddress(key.PublicKey)
+
+	apply := func(header *types.Header) {
+		if err := snap.applyHeader(header, false, nil, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	impeach := func(n uint64) *types.Header {
+		return &types.Header{Number: new(big.Int).SetUint64(n), Extra: make([]byte, extraSeal)}
+	}
+
+	// block 2 is skipped on the notice carried in block 1, block 5 is not covered by it
+	apply(newNoticesHeader(t, 1, signMaintenance(t, key, 2, 2)))
+	if !snap.excused(2) || snap.excused(5) {
+		t.Fatal("excused blocks differ from the notice on chain")
+	}
+	apply(impeach(2))
+	apply(newNoticesHeader(t, 3))
+	apply(newNoticesHeader(t, 4))
+	apply(impeach(5))
+
+	record := snap.offencesOf(proposer)
+	if !reflect.DeepEqual(record.Skips, []uint64{2}) || !reflect.DeepEqual(record.Blocks, []uint64{5}) {
+		t.Fatalf("offence record %+v, want skips [2] and offences [5]", record)
+	}
+	if _, ok := snap.penaltyOf(2); ok {
+		t.Fatal("penalty of a skip")
+	}
+	if penalty, ok := snap.penaltyOf(5); !ok || penalty.Offences != 1 {
+		t.Fatalf("penalty of block 5 %+v, want 1 offence", penalty)
+	}
+	if len(snap.Notices) != 0 {
+		t.Fatalf("notices %v after their last block", snap.Notices)
+	}
+
+	// the skip leaves the window as offences do
+	for n := uint64(6); n <= 9; n++ {
+		apply(newNoticesHeader(t, n))
+	}
+	if record := snap.offencesOf(proposer); len(record.Skips) != 0 {
+		t.Fatalf("skips %v after the window", record.Skips)
+	}
+}
+
+func TestSnapshot_recordHeaderProposers(t *testing.T) {
+	snap, key := newMaintenanceSnapshot()
+	proposer := crypto.PubkeyToAddress(key.PublicKey)
+	proposers := snap.getRecentProposers(1)
+
+	// a node not running the elections does not know the proposers of term 4
+	header := &types.Header{Number: big.NewInt(14), Extra: make([]byte, extraSeal)}
+	header.Dpor.Proposers = proposers
+	for n := uint64(1); n < 14; n++ {
+		if err := snap.applyHeader(newNoticesHeader(t, n), false, nil, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := snap.applyHeader(header, false, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(snap.getRecentProposers(4), proposers) {
+		t.Fatalf("proposers of term 4 %v, want the ones of the header", snap.getRecentProposers(4))
+	}
+	if record := snap.offencesOf(proposer); !reflect.DeepEqual(record.Blocks, []uint64{14}) {
+		t.Fatalf("offences %v, want [14]", record.Blocks)
+	}
+}
+
+func TestSnapshot_verifyNotices(t *testing.T) {
+	snap, key := newMaintenanceSnapshot()
+	other, _ := crypto.GenerateKey()
+	notice := signMaintenance(t, key, 3, 5)
+
+	if err := snap.applyHeader(newNoticesHeader(t, 1, notice), false, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		number  uint64
+		notices []*backend.MaintenanceNotice
+		want    error
+	}{
+		{"none", 2, nil, nil},
+		{"new", 2, []*backend.MaintenanceNotice{signMaintenance(t, key, 5, 6), signMaintenance(t, other, 3, 5)}, nil},
+		{"on chain", 2, []*backend.MaintenanceNotice{signMaintenance(t, key, 3, 5)}, errInvalidCarriedNotices},
+		{"duplicate", 2, []*backend.MaintenanceNotice{signMaintenance(t, key, 5, 6), signMaintenance(t, key, 5, 6)}, errInvalidCarriedNotices},
+		{"started", 3, []*backend.MaintenanceNotice{signMaintenance(t, key, 3, 6)}, errInvalidCarriedNotices},
+		{"too long", 2, []*backend.MaintenanceNotice{signMaintenance(t, key, 3, 30)}, errInvalidCarriedNotices},
+		{"unsigned", 2, []*backend.MaintenanceNotice{{From: 3, To: 5}}, errInvalidCarriedNotices},
+		{"too many", 2, []*backend.MaintenanceNotice{
+			signMaintenance(t, key, 3, 3), signMaintenance(t, key, 4, 4), signMaintenance(t, key, 5, 5),
+			signMaintenance(t, key, 6, 6), signMaintenance(t, key, 7, 7),
+		}, errInvalidCarriedNotices},
+	}
+	for _, tt := range tests {
+		if err := snap.verifyExtra(newNoticesHeader(t, tt.number, tt.notices...)); err != tt.want {
+			t.Errorf("%s: verifyExtra() error = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+
+	// the proposers elected check the notices are signed by a proposer of their blocks
+	if err := snap.verifyCarriedNotices(newNoticesHeader(t, 2, signMaintenance(t, other, 3, 5))); err != errInvalidCarriedNotices {
+		t.Fatalf("notice of a non proposer carried, err %v", err)
+	}
+
+	// no notice is carried before the slashing fork
+	snap.config.Slashing.Block = big.NewInt(10)
+	if err := snap.verifyExtra(newNoticesHeader(t, 2, signMaintenance(t, key, 5, 6))); err != errInvalidCarriedNotices {
+		t.Fatalf("notice carried before the slashing fork, err %v", err)
+	}
+}
+
+func TestMaintenanceRecord(t *testing.T) {
+	snap, key := newMaintenanceSnapshot()
+	other, _ := crypto.GenerateKey()
+	r := newMaintenanceRecord()
+
+	onChain := signMaintenance(t, key, 5, 5)
+	early := signMaintenance(t, key, 2, 3)
+	late := signMaintenance(t, key, 8, 9)
+	stranger := signMaintenance(t, other, 3, 4)
+
+	if err := snap.applyHeader(newNoticesHeader(t, 1, onChain), false, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, n := range []*backend.MaintenanceNotice{onChain, early, late, stranger} {
+		if !r.add(n) {
+			t.Fatalf("notice %d-%d not added", n.From, n.To)
+		}
+	}
+	if r.add(signMaintenance(t, key, 2, 3)) {
+		t.Fatal("known notice added")
+	}
+
+	// the valid notices not on chain yet are carried
+	if got := r.pending(snap, 1); !reflect.DeepEqual(got, []*backend.MaintenanceNotice{early, late}) {
+		t.Fatalf("pending notices %v, want the early and the late ones", got)
+	}
+	if got := r.pending(snap, 3); !reflect.DeepEqual(got, []*backend.MaintenanceNotice{late}) {
+		t.Fatalf("pending notices of block 3 %v, want the late one", got)
+	}
+
+	r.prune(3)
+	if len(r.notices) != 2 || r.notices[0] != onChain || r.notices[1] != late {
+		t.Fatalf("notices after pruning %v, want the ones from block 4 on", r.notices)
+	}
+}
@@ -0,0 +1,137 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpt
+
+import (
+	"fmt"
+
+	"bitbucket.org/cpchain/chain/consensus/dpor/backend"
+	contracts "bitbucket.org/cpchain/chain/contracts/dpor/rpt"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// CollectorSpec configures an rpt method as a composition of shared component
+// implementations, one per term of the reputation formula.
+type CollectorSpec struct {
+	Method int `json:"method"`
+
+	Rank        ComponentSpec `json:"rank"`
+	Txs         ComponentSpec `json:"txs"`
+	Maintenance ComponentSpec `json:"maintenance"`
+	Upload      ComponentSpec `json:"upload"`
+	Proxy       ComponentSpec `json:"proxy"`
+}
+
+var (
+	// RptMethod4Spec ranks balance, transactions and proposed blocks among the
+	// candidates in ascending order, then scores them by percentage.
+	RptMethod4Spec = CollectorSpec{
+		Method:      4,
+		Rank:        ComponentSpec{Measure: MeasureBalanceWei, Rank: RankAscending, Score: ScorePercentage},
+		Txs:         ComponentSpec{Measure: MeasureTxs, Rank: RankAscending, Score: ScorePercentage},
+		Maintenance: ComponentSpec{Measure: MeasureProposed, Rank: RankAscending, Score: ScorePercentage},
+		Upload:      ComponentSpec{Measure: MeasureConst, Score: ScorePercentage, Value: 0},
+		Proxy:       ComponentSpec{Measure: MeasureConst, Score: ScorePercentage, Value: 0},
+	}
+
+	// RptMethod5Spec ranks balance in cpc, transactions and proposed blocks
+	// among the candidates in descending order, and uses the ranks as scores.
+	RptMethod5Spec = CollectorSpec{
+		Method:      5,
+		Rank:        ComponentSpec{Measure: MeasureBalanceCpc, Rank: RankDescending, Score: ScoreRank},
+		Txs:         ComponentSpec{Measure: MeasureTxs, Rank: RankDescending, Score: ScoreRank},
+		Maintenance: ComponentSpec{Measure: MeasureProposed, Rank: RankDescending, Score: ScoreRank},
+		Upload:      ComponentSpec{Measure: MeasureConst, Score: ScoreRank, Value: 1},
+		Proxy:       ComponentSpec{Measure: MeasureConst, Score: ScoreRank, Value: 1},
+	}
+)
+
+// ComposedCollector implements RptCollector with the components configured
+// by a CollectorSpec, weighted by the parameters of the rpt contract.
+type ComposedCollector struct {
+	method int
+	params *ParamReader
+
+	rank        Component
+	txs         Component
+	maintenance Component
+	upload      Component
+	proxy       Component
+}
+
+// NewComposedCollector creates the collector configured by spec.
+func NewComposedCollector(spec CollectorSpec, params *ParamReader, chainBackend backend.ChainBackend) (*ComposedCollector, error) {
+	if spec.Method <= 1 {
+		return nil, fmt.Errorf("invalid rpt method %d", spec.Method)
+	}
+
+	rc := &ComposedCollector{
+		method: spec.Method,
+		params: params,
+	}
+	for _, c := range []struct {
+		name string
+		spec ComponentSpec
+		comp *Component
+	}{
+		{"rank", spec.Rank, &rc.rank},
+		{"txs", spec.Txs, &rc.txs},
+		{"maintenance", spec.Maintenance, &rc.maintenance},
+		{"upload", spec.Upload, &rc.upload},
+		{"proxy", spec.Proxy, &rc.proxy},
+	} {
+		comp, err := newComponent(c.spec, chainBackend)
+		if err != nil {
+			return nil, fmt.Errorf("rpt method %d %s: %v", spec.Method, c.name, err)
+		}
+		*c.comp = comp
+	}
+	return rc, nil
+}
+
+// NewRptCollectorImpl4 creates the collector of rpt method 4
+func NewRptCollectorImpl4(rptInstance *contracts.Rpt, chainBackend backend.ChainBackend) *ComposedCollector {
+	rc, _ := NewComposedCollector(RptMethod4Spec, NewParamReader(rptInstance), chainBackend)
+	return rc
+}
+
+// NewRptCollectorImpl5 creates the collector of rpt method 5
+func NewRptCollectorImpl5(rptInstance *contracts.Rpt, chainBackend backend.ChainBackend) *ComposedCollector {
+	rc, _ := NewComposedCollector(RptMethod5Spec, NewParamReader(rptInstance), chainBackend)
+	return rc
+}
+
+// RptOf returns the reputation value of a given address among a batch addresses
+func (rc *ComposedCollector) RptOf(addr common.Address, addrs []common.Address, num uint64) Rpt {
+	b := rc.BreakdownOf(addr, addrs, num)
+	return Rpt{Address: addr, Rpt: b.Rpt}
+}
+
+// BreakdownOf returns the components and coefficients the reputation value of
+// a given address among a batch addresses is made of
+func (rc *ComposedCollector) BreakdownOf(addr common.Address, addrs []common.Address, num uint64) RptBreakdown {
+	p := rc.params.At(num)
+
+	b := p.breakdown(addr, num, rc.method)
+	b.Rank = rc.rank.ValueOf(addr, addrs, num, p.WindowSize)
+	b.Txs = rc.txs.ValueOf(addr, addrs, num, p.WindowSize)
+	b.Maintenance = rc.maintenance.ValueOf(addr, addrs, num, p.WindowSize)
+	b.Upload = rc.upload.ValueOf(addr, addrs, num, p.WindowSize)
+	b.Proxy = rc.proxy.ValueOf(addr, addrs, num, p.WindowSize)
+	b.score()
+	return b
+}
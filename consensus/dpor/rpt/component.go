@@ -0,0 +1,274 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpt
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+	"time"
+
+	"bitbucket.org/cpchain/chain/commons/log"
+	"bitbucket.org/cpchain/chain/configs"
+	"bitbucket.org/cpchain/chain/consensus/dpor/backend"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Measures a component can be built on.
+const (
+	MeasureBalanceWei = "balanceWei" // balance in wei, defaultRank if unknown
+	MeasureBalanceCpc = "balanceCpc" // balance in cpc, 0 if unknown
+	MeasureTxs        = "txs"        // transactions sent in the window
+	MeasureProposed   = "proposed"   // blocks proposed in the window
+	MeasureConst      = "const"      // ComponentSpec.Value, not ranked
+)
+
+// Rankings of a measure among the candidates.
+const (
+	RankAscending  = "ascending"  // percentage of candidates with a smaller measure
+	RankDescending = "descending" // percentage of candidates not before the exact measure
+)
+
+// Scorings of a rank.
+const (
+	ScoreRank       = "rank"       // the rank itself
+	ScorePercentage = "percentage" // the rank mapped to 20, 40, 60, 70, 80, 90 or 100
+)
+
+// ComponentSpec configures a shared component implementation as one term of
+// the reputation formula.
+type ComponentSpec struct {
+	Measure string `json:"measure"`
+	Rank    string `json:"rank,omitempty"` // ignored by MeasureConst
+	Score   string `json:"score"`
+	Value   int64  `json:"value,omitempty"` // used by MeasureConst only
+}
+
+// Component calculates one term of the reputation formula for an address
+// among a batch of addresses.
+type Component interface {
+	ValueOf(addr common.Address, addrs []common.Address, num uint64, windowSize int) int64
+}
+
+// measureFunc returns the raw measure of an address at block num.
+type measureFunc func(chainBackend backend.ChainBackend, addr common.Address, num uint64, windowSize int) float64
+
+var measures = map[string]measureFunc{
+	MeasureBalanceWei: balanceWeiOf,
+	MeasureBalanceCpc: balanceCpcOf,
+	MeasureTxs:        txsOf,
+	MeasureProposed:   proposedOf,
+}
+
+// rankFunc ranks item among a sorted array of the measures of count addresses.
+type rankFunc func(item float64, array []float64, count int) int64
+
+type ranking struct {
+	sort func(array []float64)
+	rank rankFunc
+}
+
+var rankings = map[string]ranking{
+	RankAscending: {
+		sort: func(array []float64) { sort.Sort(sort.Float64Slice(array)) },
+		rank: func(item float64, array []float64, count int) int64 {
+			index := sort.SearchFloat64s(array, item)
+			return int64(float64(index) / float64(count) * 100)
+		},
+	},
+	RankDescending: {
+		sort: func(array []float64) { sortAndReverse(array) },
+		rank: func(item float64, array []float64, count int) int64 {
+			return getRank(item, array)
+		},
+	},
+}
+
+var scorings = map[string]func(rank int64) int64{
+	ScoreRank:       func(rank int64) int64 { return rank },
+	ScorePercentage: percentage,
+}
+
+func (s ComponentSpec) validate() error {
+	if _, ok := scorings[s.Score]; !ok {
+		return fmt.Errorf("unknown rpt component score %q", s.Score)
+	}
+	if s.Measure == MeasureConst {
+		return nil
+	}
+	if _, ok := measures[s.Measure]; !ok {
+		return fmt.Errorf("unknown rpt component measure %q", s.Measure)
+	}
+	if _, ok := rankings[s.Rank]; !ok {
+		return fmt.Errorf("unknown rpt component rank %q", s.Rank)
+	}
+	return nil
+}
+
+// newComponent creates the component configured by spec.
+func newComponent(spec ComponentSpec, chainBackend backend.ChainBackend) (Component, error) {
+	if err := spec.validate(); err != nil {
+		return nil, err
+	}
+	if spec.Measure == MeasureConst {
+		return &constComponent{value: scorings[spec.Score](spec.Value)}, nil
+	}
+	return &rankedComponent{
+		name:         spec.Measure,
+		chainBackend: chainBackend,
+		measure:      measures[spec.Measure],
+		ranking:      rankings[spec.Rank],
+		score:        scorings[spec.Score],
+		cache:        newRptDataCache(),
+	}, nil
+}
+
+// constComponent gives every address the same value.
+type constComponent struct {
+	value int64
+}
+
+// ValueOf implements Component
+func (c *constComponent) ValueOf(addr common.Address, addrs []common.Address, num uint64, windowSize int) int64 {
+	return c.value
+}
+
+// rankedComponent scores an address by the rank of its measure among addrs.
+// The sorted measures are cached by block number.
+type rankedComponent struct {
+	name         string
+	chainBackend backend.ChainBackend
+
+	measure measureFunc
+	ranking ranking
+	score   func(rank int64) int64
+
+	cache *rptDataCache
+}
+
+// ValueOf implements Component
+func (c *rankedComponent) ValueOf(addr common.Address, addrs []common.Address, num uint64, windowSize int) int64 {
+	start := time.Now()
+
+	mine := c.measure(c.chainBackend, addr, num, windowSize)
+	array, ok := c.cache.getCache(num)
+	if !ok {
+		for _, candidate := range addrs {
+			array = append(array, c.measure(c.chainBackend, candidate, num, windowSize))
+		}
+		c.ranking.sort(array)
+		c.cache.addCache(num, array)
+	}
+	rank := c.ranking.rank(mine, array, len(addrs))
+
+	log.Debug("now calculating rpt", "component", c.name, "num", num, "addr", addr.Hex(), "rank", rank, "elapsed", common.PrettyDuration(time.Now().Sub(start)))
+	return c.score(rank)
+}
+
+func balanceWeiOf(chainBackend backend.ChainBackend, addr common.Address, num uint64, windowSize int) float64 {
+	balance, err := chainBackend.BalanceAt(context.Background(), addr, big.NewInt(int64(num)))
+	if balance == nil || err != nil {
+		return defaultRank
+	}
+	return float64(balance.Int64())
+}
+
+func balanceCpcOf(chainBackend backend.ChainBackend, addr common.Address, num uint64, windowSize int) float64 {
+	balance, err := chainBackend.BalanceAt(context.Background(), addr, big.NewInt(int64(num)))
+	if balance == nil || err != nil {
+		return 0
+	}
+	return float64(new(big.Int).Div(balance, big.NewInt(configs.Cpc)).Uint64())
+}
+
+func txsOf(chainBackend backend.ChainBackend, addr common.Address, num uint64, windowSize int) float64 {
+	nonce, err := chainBackend.NonceAt(context.Background(), addr, big.NewInt(int64(num)))
+	if err != nil {
+		return 0
+	}
+
+	nonce0, err := chainBackend.NonceAt(context.Background(), addr, big.NewInt(int64(offset(num, windowSize))))
+	if err != nil {
+		return 0
+	}
+
+	return float64(int64(nonce - nonce0))
+}
+
+func proposedOf(chainBackend backend.ChainBackend, addr common.Address, num uint64, windowSize int) float64 {
+	mtn := int64(0)
+	for i := offset(num, windowSize); i < num; i++ {
+		header, err := chainBackend.HeaderByNumber(context.Background(), big.NewInt(int64(i)))
+		if header == nil || err != nil {
+			continue
+		}
+
+		if header.Coinbase == addr {
+			mtn++
+		}
+	}
+	return float64(mtn)
+}
+
+func percentage(rank int64) int64 {
+	if rank < 20 {
+		return 20
+	}
+	if rank < 40 {
+		return 40
+	}
+	if rank < 65 {
+		return 60
+	}
+	if rank < 85 {
+		return 70
+	}
+	if rank < 95 {
+		return 80
+	}
+	if rank < 98 {
+		return 90
+	}
+	return 100
+}
+
+// getRank return the rank of the given item among the array
+// the array is in decreasing order
+func getRank(item float64, array sort.Float64Slice) int64 {
+	len := len(array)
+	index := searchIndex(item, array)
+	rank := int64((1 - float64(index)/float64(len)) * 100)
+	log.Debug("array", "array", array, "rank", rank, "index", index, "len", len)
+	return rank
+}
+
+// sortAndReverse returns an decreasing order of the given array
+func sortAndReverse(array []float64) sort.Float64Slice {
+	sort.Sort(sort.Reverse(sort.Float64Slice(array)))
+	return array
+}
+
+// searchIndex return the index of an item in an array
+func searchIndex(item float64, array []float64) int64 {
+	for i, x := range array {
+		if x == item {
+			return int64(i)
+		}
+	}
+	return int64(len(array))
+}
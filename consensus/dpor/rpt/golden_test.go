@@ -0,0 +1,222 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpt_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"bitbucket.org/cpchain/chain/configs"
+	"bitbucket.org/cpchain/chain/consensus/dpor/rpt"
+	"bitbucket.org/cpchain/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// goldenChainBackend is a fixed chain of goldenBlocks blocks whose balances,
+// nonces and proposers are pure functions of the account and block number.
+type goldenChainBackend struct {
+	accounts []common.Address
+}
+
+const goldenBlocks = 600
+
+var errGoldenUnknown = errors.New("unknown golden state")
+
+func newGoldenChainBackend() *goldenChainBackend {
+	var accounts []common.Address
+	for i := 1; i <= 9; i++ {
+		accounts = append(accounts, common.HexToAddress(fmt.Sprintf("0x%040x", i*0x1111)))
+	}
+	return &goldenChainBackend{accounts: accounts}
+}
+
+func (gc *goldenChainBackend) indexOf(account common.Address) int {
+	for i, a := range gc.accounts {
+		if a == account {
+			return i
+		}
+	}
+	return -1
+}
+
+func (gc *goldenChainBackend) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	n := number.Uint64()
+	if n >= goldenBlocks || n%37 == 0 {
+		return nil, errGoldenUnknown
+	}
+	return &types.Header{Number: new(big.Int).Set(number), Coinbase: gc.accounts[(n*n/7)%uint64(len(gc.accounts)-2)]}, nil
+}
+
+func (gc *goldenChainBackend) BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error) {
+	i, n := gc.indexOf(account), blockNumber.Int64()
+	if i < 0 || i == 4 {
+		return nil, errGoldenUnknown
+	}
+	cpc := new(big.Int).Mul(big.NewInt(int64(i%4)+n/200), big.NewInt(configs.Cpc))
+	return cpc.Add(cpc, big.NewInt(int64(i*i)*n)), nil
+}
+
+func (gc *goldenChainBackend) NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error) {
+	i, n := gc.indexOf(account), blockNumber.Uint64()
+	if i < 0 || (i == 6 && n < 250) {
+		return 0, errGoldenUnknown
+	}
+	return n * uint64(i%5) / 13, nil
+}
+
+// goldenBreakdown is a breakdown of the account at index idx at block num.
+type goldenBreakdown struct {
+	num uint64
+	idx int
+
+	rank, txs, maintenance, upload, proxy, rpt int64
+}
+
+// The golden breakdowns were recorded with RptCollectorImpl4 and
+// RptCollectorImpl5 before they were replaced by collector specs, on
+// goldenChainBackend with the default parameters.
+var (
+	goldenRptMethod4 = []goldenBreakdown{
+		{1, 0, 20, 20, 20, 20, 20, 2000},
+		{1, 1, 40, 20, 20, 20, 20, 3000},
+		{1, 2, 60, 20, 20, 20, 20, 4000},
+		{1, 3, 70, 20, 20, 20, 20, 4500},
+		{1, 4, 40, 20, 20, 20, 20, 3000},
+		{1, 5, 40, 20, 20, 20, 20, 3000},
+		{1, 6, 60, 20, 20, 20, 20, 4000},
+		{1, 7, 70, 20, 20, 20, 20, 4500},
+		{1, 8, 20, 20, 20, 20, 20, 2000},
+		{150, 0, 20, 20, 80, 20, 20, 2600},
+		{150, 1, 40, 40, 60, 20, 20, 3700},
+		{150, 2, 60, 60, 60, 20, 20, 5000},
+		{150, 3, 70, 70, 40, 20, 20, 5450},
+		{150, 4, 20, 80, 60, 20, 20, 3300},
+		{150, 5, 60, 20, 40, 20, 20, 4200},
+		{150, 6, 70, 20, 60, 20, 20, 4900},
+		{150, 7, 80, 60, 20, 20, 20, 5600},
+		{150, 8, 40, 70, 20, 20, 20, 3750},
+		{300, 0, 20, 20, 80, 20, 20, 2600},
+		{300, 1, 40, 40, 40, 20, 20, 3500},
+		{300, 2, 60, 60, 70, 20, 20, 5100},
+		{300, 3, 70, 70, 70, 20, 20, 5750},
+		{300, 4, 20, 80, 40, 20, 20, 3100},
+		{300, 5, 60, 20, 40, 20, 20, 4200},
+		{300, 6, 70, 20, 40, 20, 20, 4700},
+		{300, 7, 80, 60, 20, 20, 20, 5600},
+		{300, 8, 40, 70, 20, 20, 20, 3750},
+		{301, 0, 20, 20, 80, 20, 20, 2600},
+		{301, 1, 40, 40, 40, 20, 20, 3500},
+		{301, 2, 60, 60, 40, 20, 20, 4800},
+		{301, 3, 70, 70, 70, 20, 20, 5750},
+		{301, 4, 20, 80, 40, 20, 20, 3100},
+		{301, 5, 60, 20, 40, 20, 20, 4200},
+		{301, 6, 70, 20, 40, 20, 20, 4700},
+		{301, 7, 80, 60, 20, 20, 20, 5600},
+		{301, 8, 40, 70, 20, 20, 20, 3750},
+		{555, 0, 20, 20, 80, 20, 20, 2600},
+		{555, 1, 40, 40, 40, 20, 20, 3500},
+		{555, 2, 60, 60, 40, 20, 20, 4800},
+		{555, 3, 70, 70, 40, 20, 20, 5450},
+		{555, 4, 20, 80, 40, 20, 20, 3100},
+		{555, 5, 60, 20, 40, 20, 20, 4200},
+		{555, 6, 70, 40, 40, 20, 20, 5000},
+		{555, 7, 80, 60, 20, 20, 20, 5600},
+		{555, 8, 40, 70, 20, 20, 20, 3750},
+	}
+
+	goldenRptMethod5 = []goldenBreakdown{
+		{1, 0, 33, 100, 100, 1, 1, 4175},
+		{1, 1, 55, 100, 100, 1, 1, 5275},
+		{1, 2, 77, 100, 100, 1, 1, 6375},
+		{1, 3, 100, 100, 100, 1, 1, 7525},
+		{1, 4, 33, 100, 100, 1, 1, 4175},
+		{1, 5, 55, 100, 100, 1, 1, 5275},
+		{1, 6, 77, 100, 100, 1, 1, 6375},
+		{1, 7, 100, 100, 100, 1, 1, 7525},
+		{1, 8, 33, 100, 100, 1, 1, 4175},
+		{150, 0, 33, 33, 100, 1, 1, 3170},
+		{150, 1, 55, 44, 88, 1, 1, 4315},
+		{150, 2, 77, 66, 88, 1, 1, 5745},
+		{150, 3, 100, 88, 44, 1, 1, 6785},
+		{150, 4, 33, 100, 88, 1, 1, 4055},
+		{150, 5, 55, 33, 44, 1, 1, 3710},
+		{150, 6, 77, 33, 88, 1, 1, 5250},
+		{150, 7, 100, 66, 22, 1, 1, 6235},
+		{150, 8, 33, 88, 22, 1, 1, 3215},
+		{300, 0, 33, 33, 100, 1, 1, 3170},
+		{300, 1, 55, 44, 66, 1, 1, 4095},
+		{300, 2, 77, 66, 88, 1, 1, 5745},
+		{300, 3, 100, 88, 88, 1, 1, 7225},
+		{300, 4, 11, 100, 66, 1, 1, 2735},
+		{300, 5, 55, 33, 33, 1, 1, 3600},
+		{300, 6, 77, 33, 66, 1, 1, 5030},
+		{300, 7, 100, 66, 22, 1, 1, 6235},
+		{300, 8, 33, 88, 22, 1, 1, 3215},
+		{301, 0, 33, 33, 100, 1, 1, 3170},
+		{301, 1, 55, 44, 77, 1, 1, 4205},
+		{301, 2, 77, 66, 77, 1, 1, 5635},
+		{301, 3, 100, 88, 88, 1, 1, 7225},
+		{301, 4, 11, 100, 77, 1, 1, 2845},
+		{301, 5, 55, 33, 77, 1, 1, 4040},
+		{301, 6, 77, 33, 77, 1, 1, 5140},
+		{301, 7, 100, 66, 22, 1, 1, 6235},
+		{301, 8, 33, 88, 22, 1, 1, 3215},
+		{555, 0, 33, 22, 100, 1, 1, 3005},
+		{555, 1, 55, 44, 88, 1, 1, 4315},
+		{555, 2, 77, 66, 88, 1, 1, 5745},
+		{555, 3, 100, 88, 88, 1, 1, 7225},
+		{555, 4, 11, 100, 88, 1, 1, 2955},
+		{555, 5, 55, 22, 88, 1, 1, 3985},
+		{555, 6, 77, 44, 88, 1, 1, 5415},
+		{555, 7, 100, 66, 22, 1, 1, 6235},
+		{555, 8, 33, 88, 22, 1, 1, 3215},
+	}
+)
+
+func TestGoldenRptMethods(t *testing.T) {
+	for _, tt := range []struct {
+		spec   rpt.CollectorSpec
+		golden []goldenBreakdown
+	}{
+		{rpt.RptMethod4Spec, goldenRptMethod4},
+		{rpt.RptMethod5Spec, goldenRptMethod5},
+	} {
+		gc := newGoldenChainBackend()
+		rptCollector, err := rpt.NewComposedCollector(tt.spec, rpt.NewParamReader(nil), gc)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, g := range tt.golden {
+			addr := gc.accounts[g.idx]
+			b := rptCollector.BreakdownOf(addr, gc.accounts, g.num)
+			got := goldenBreakdown{g.num, g.idx, b.Rank, b.Txs, b.Maintenance, b.Upload, b.Proxy, b.Rpt}
+			if got != g {
+				t.Errorf("rpt method %d: breakdown of account %d at block %d is %+v, want %+v", tt.spec.Method, g.idx, g.num, got, g)
+			}
+			if b.Method != tt.spec.Method || b.WindowSize != 100 {
+				t.Errorf("rpt method %d: unexpected calculation %+v", tt.spec.Method, b)
+			}
+			if r := rptCollector.RptOf(addr, gc.accounts, g.num); r.Rpt != g.rpt {
+				t.Errorf("rpt method %d: rpt of account %d at block %d is %d, want %d", tt.spec.Method, g.idx, g.num, r.Rpt, g.rpt)
+			}
+		}
+	}
+}
@@ -0,0 +1,127 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpt
+
+import (
+	"sync"
+
+	"bitbucket.org/cpchain/chain/commons/log"
+	contracts "bitbucket.org/cpchain/chain/contracts/dpor/rpt"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Params is a set of weight parameters of the reputation formula, governed by
+// the owner of the rpt contract.
+type Params struct {
+	Alpha int64 // coefficient of balance(coin age)
+	Beta  int64 // coefficient of transaction count
+	Gamma int64 // coefficient of chain maintenance
+	Psi   int64 // coefficient of file contribution
+	Omega int64 // coefficient of proxy information in pdash
+
+	WindowSize int // number of blocks the components look back
+
+	// Version is bumped every time the parameters read from the contract
+	// differ from the previous ones, 0 being the built-in defaults.
+	Version uint64
+}
+
+// defaultParams are used until the rpt contract is read successfully.
+var defaultParams = Params{
+	Alpha: 50,
+	Beta:  15,
+	Gamma: 10,
+	Psi:   15,
+	Omega: 10,
+
+	WindowSize: 100,
+}
+
+// ParamReader reads the weight parameters from the rpt contract, at most once
+// per block number. Parameters failed to read keep their previous value.
+type ParamReader struct {
+	rptInstance *contracts.Rpt
+
+	params     Params
+	currentNum uint64
+	lock       sync.Mutex
+}
+
+// NewParamReader creates a ParamReader of the given rpt contract, a nil
+// contract always gives the built-in defaults.
+func NewParamReader(rptInstance *contracts.Rpt) *ParamReader {
+	return &ParamReader{
+		rptInstance: rptInstance,
+		params:      defaultParams,
+		currentNum:  0,
+	}
+}
+
+// At returns the parameters used to calculate reputations at block num.
+func (pr *ParamReader) At(num uint64) Params {
+	pr.lock.Lock()
+	defer pr.lock.Unlock()
+
+	if pr.rptInstance == nil || num == pr.currentNum {
+		return pr.params
+	}
+	pr.currentNum = num
+
+	p := pr.params
+	if w, err := pr.rptInstance.Window(nil); err == nil {
+		p.WindowSize = int(w.Int64())
+	}
+	if a, err := pr.rptInstance.Alpha(nil); err == nil {
+		p.Alpha = a.Int64()
+	}
+	if b, err := pr.rptInstance.Beta(nil); err == nil {
+		p.Beta = b.Int64()
+	}
+	if g, err := pr.rptInstance.Gamma(nil); err == nil {
+		p.Gamma = g.Int64()
+	}
+	if ps, err := pr.rptInstance.Psi(nil); err == nil {
+		p.Psi = ps.Int64()
+	}
+	if o, err := pr.rptInstance.Omega(nil); err == nil {
+		p.Omega = o.Int64()
+	}
+
+	if p != pr.params {
+		p.Version++
+		log.Info("using new rpt parameters from contract", "version", p.Version, "num", num, "alpha", p.Alpha, "beta", p.Beta, "gamma", p.Gamma, "psi", p.Psi, "omega", p.Omega, "window", p.WindowSize)
+		pr.params = p
+	}
+	return pr.params
+}
+
+// breakdown returns an RptBreakdown of addr with the parameters filled in.
+func (p Params) breakdown(addr common.Address, num uint64, method int) RptBreakdown {
+	return RptBreakdown{
+		Address:       addr,
+		Number:        num,
+		Method:        method,
+		WindowSize:    p.WindowSize,
+		ParamsVersion: p.Version,
+
+		Alpha: p.Alpha,
+		Beta:  p.Beta,
+		Gamma: p.Gamma,
+		Psi:   p.Psi,
+		Omega: p.Omega,
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpt
+
+import (
+	"math/big"
+
+	"bitbucket.org/cpchain/chain/accounts/abi/bind"
+	"bitbucket.org/cpchain/chain/commons/log"
+	"bitbucket.org/cpchain/chain/configs"
+	"bitbucket.org/cpchain/chain/contracts/dpor/period_governor"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// PeriodService provides methods to obtain the governed block periods
+type PeriodService interface {
+	// PeriodOf returns the block period of the term in milliseconds as
+	// scheduled at block number, 0 if none is scheduled
+	PeriodOf(term uint64, number uint64) (uint64, error)
+}
+
+// PeriodServiceImpl is the default block period collector
+type PeriodServiceImpl struct {
+	client bind.ContractBackend
+}
+
+// NewPeriodService creates a concrete period service instance.
+func NewPeriodService(backend bind.ContractBackend) (PeriodService, error) {
+	return &PeriodServiceImpl{
+		client: backend,
+	}, nil
+}
+
+// PeriodOf implements PeriodService
+func (ps *PeriodServiceImpl) PeriodOf(term uint64, number uint64) (uint64, error) {
+	// no period governor, the periods of the chain config apply
+	governorAddr := configs.ChainConfigInfo().Dpor.Contracts[configs.ContractPeriod]
+	if governorAddr == (common.Address{}) {
+		return 0, nil
+	}
+
+	contractInstance, err := period_governor.NewPeriodGovernor(governorAddr, ps.client)
+	if err != nil {
+		return 0, err
+	}
+
+	// read the governor at the given block, not the latest state
+	opts := &bind.CallOpts{BlockNumber: new(big.Int).SetUint64(number)}
+	period, err := contractInstance.PeriodOf(opts, new(big.Int).SetUint64(term))
+	if err == bind.ErrNoCode {
+		// the governor is not deployed yet, no period is scheduled
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	log.Debug("read block period from period governor", "term", term, "number", number, "period", period, "contract addr", governorAddr.Hex())
+	return period.Uint64(), nil
+}
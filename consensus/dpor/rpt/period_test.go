@@ -0,0 +1,89 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpt_test
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"bitbucket.org/cpchain/chain"
+	"bitbucket.org/cpchain/chain/accounts/abi/bind"
+	"bitbucket.org/cpchain/chain/configs"
+	"bitbucket.org/cpchain/chain/consensus/dpor/rpt"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// fakeGovernorBackend serves the periods of a period governor deployed at
+// block deployed, as scheduled at block number
+type fakeGovernorBackend struct {
+	bind.ContractBackend
+	deployed uint64
+	number   uint64
+	periods  map[uint64]uint64
+}
+
+func (b *fakeGovernorBackend) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	if blockNumber.Uint64() < b.deployed {
+		return nil, nil
+	}
+	return []byte{0x01}, nil
+}
+
+func (b *fakeGovernorBackend) CallContract(ctx context.Context, call cpchain.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	if blockNumber.Uint64() < b.deployed {
+		return nil, nil
+	}
+	if blockNumber.Uint64() != b.number {
+		return nil, fmt.Errorf("period governor read at block %d, want %d", blockNumber.Uint64(), b.number)
+	}
+	// periodOf(uint256) takes the term after the method id
+	term := new(big.Int).SetBytes(call.Data[4:36]).Uint64()
+	return common.LeftPadBytes(new(big.Int).SetUint64(b.periods[term]).Bytes(), 32), nil
+}
+
+func TestPeriodServiceImpl_PeriodOf(t *testing.T) {
+	backend := &fakeGovernorBackend{deployed: 5, number: 10, periods: map[uint64]uint64{4: 3000}}
+	service, _ := rpt.NewPeriodService(backend)
+
+	// no period governor, none is scheduled
+	contracts := configs.ChainConfigInfo().Dpor.Contracts
+	governorAddr := contracts[configs.ContractPeriod]
+	defer func() { contracts[configs.ContractPeriod] = governorAddr }()
+
+	contracts[configs.ContractPeriod] = common.Address{}
+	if period, err := service.PeriodOf(4, 10); err != nil || period != 0 {
+		t.Fatalf("period %d, err %v without governor, want 0", period, err)
+	}
+
+	contracts[configs.ContractPeriod] = common.HexToAddress("0x4444444444444444444444444444444444444444")
+	for term, want := range map[uint64]uint64{3: 0, 4: 3000} {
+		if period, err := service.PeriodOf(term, 10); err != nil || period != want {
+			t.Errorf("period of term %d %d, err %v, want %d", term, period, err, want)
+		}
+	}
+
+	// the governor is not deployed yet
+	if period, err := service.PeriodOf(4, 2); err != nil || period != 0 {
+		t.Errorf("period %d, err %v before deployment, want 0", period, err)
+	}
+
+	if _, err := service.PeriodOf(4, 11); err == nil {
+		t.Error("expect error reading the governor at another block")
+	}
+}
@@ -0,0 +1,114 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpt
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"bitbucket.org/cpchain/chain/configs"
+	"bitbucket.org/cpchain/chain/consensus/dpor/backend"
+)
+
+// ForkFunc reports whether a fork is active in the rules of a block.
+type ForkFunc func(rules configs.Rules) bool
+
+// CollectorFactory creates the collector of an rpt method, all collectors of
+// an rpt service share the same parameters.
+type CollectorFactory func(params *ParamReader, chainBackend backend.ChainBackend) RptCollector
+
+// registeredCollector is an rpt method activated by a fork.
+type registeredCollector struct {
+	method   int
+	isForked ForkFunc
+	factory  CollectorFactory
+}
+
+var (
+	collectorsLock sync.RWMutex
+	collectors     []registeredCollector // sorted by method
+)
+
+func init() {
+	mustRegister := func(err error) {
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	mustRegister(RegisterCollector(2, func(r configs.Rules) bool { return r.IsRptMethod2 }, func(params *ParamReader, chainBackend backend.ChainBackend) RptCollector {
+		return NewRptCollectorImpl2(params, chainBackend)
+	}))
+	mustRegister(RegisterCollector(3, func(r configs.Rules) bool { return r.IsRptMethod3 }, func(params *ParamReader, chainBackend backend.ChainBackend) RptCollector {
+		return NewRptCollectorImpl3(params, chainBackend)
+	}))
+	mustRegister(RegisterCollectorSpec(RptMethod4Spec, func(r configs.Rules) bool { return r.IsRptMethod4 }))
+	mustRegister(RegisterCollectorSpec(RptMethod5Spec, func(r configs.Rules) bool { return r.IsRptMethod5 }))
+}
+
+// RegisterCollector registers the collector of an rpt method, used from the
+// block isForked returns true. A later method takes precedence over earlier
+// ones, method 1 is the one built in the rpt service.
+func RegisterCollector(method int, isForked ForkFunc, factory CollectorFactory) error {
+	if method <= 1 {
+		return fmt.Errorf("invalid rpt method %d", method)
+	}
+
+	collectorsLock.Lock()
+	defer collectorsLock.Unlock()
+
+	for _, c := range collectors {
+		if c.method == method {
+			return fmt.Errorf("rpt method %d already registered", method)
+		}
+	}
+	collectors = append(collectors, registeredCollector{method: method, isForked: isForked, factory: factory})
+	sort.Slice(collectors, func(i, j int) bool { return collectors[i].method < collectors[j].method })
+	return nil
+}
+
+// RegisterCollectorSpec registers the rpt method composed as spec configures.
+func RegisterCollectorSpec(spec CollectorSpec, isForked ForkFunc) error {
+	// fail on registration rather than on the first block of the fork
+	if _, err := NewComposedCollector(spec, NewParamReader(nil), nil); err != nil {
+		return err
+	}
+	return RegisterCollector(spec.Method, isForked, func(params *ParamReader, chainBackend backend.ChainBackend) RptCollector {
+		rc, _ := NewComposedCollector(spec, params, chainBackend)
+		return rc
+	})
+}
+
+// methodCollector is an instantiated registeredCollector.
+type methodCollector struct {
+	method    int
+	isForked  ForkFunc
+	collector RptCollector
+}
+
+// newCollectors instantiates all registered collectors.
+func newCollectors(params *ParamReader, chainBackend backend.ChainBackend) []methodCollector {
+	collectorsLock.RLock()
+	defer collectorsLock.RUnlock()
+
+	mcs := make([]methodCollector, 0, len(collectors))
+	for _, c := range collectors {
+		mcs = append(mcs, methodCollector{method: c.method, isForked: c.isForked, collector: c.factory(params, chainBackend)})
+	}
+	return mcs
+}
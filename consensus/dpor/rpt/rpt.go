@@ -118,6 +118,41 @@ type RptItems struct {
 // RptList is an array of Rpt.
 type RptList []Rpt
 
+// RptBreakdown explains a reputation value: the components ranked by the rpt
+// collector, the coefficients weighting them, and the method and window of
+// the calculation. Rpt calculated by the old method has no components.
+type RptBreakdown struct {
+	Address    common.Address `json:"address"`
+	Number     uint64         `json:"number"`     // block number the rpt is calculated at
+	Method     int            `json:"method"`     // rpt calculation method, 1 for the old one
+	WindowSize int            `json:"windowSize"` // number of blocks the components look back
+
+	ParamsVersion uint64 `json:"paramsVersion"` // version of the parameters read from rpt contract
+
+	Alpha int64 `json:"alpha"` // coefficient of Rank
+	Beta  int64 `json:"beta"`  // coefficient of Txs
+	Gamma int64 `json:"gamma"` // coefficient of Maintenance
+	Psi   int64 `json:"psi"`   // coefficient of Upload
+	Omega int64 `json:"omega"` // coefficient of Proxy
+
+	Rank        int64 `json:"rank"`        // balance(coin age) value among the candidates
+	Txs         int64 `json:"txs"`         // transaction count value
+	Maintenance int64 `json:"maintenance"` // chain maintenance value, i.e. blocks proposed
+	Upload      int64 `json:"upload"`      // file contribution value
+	Proxy       int64 `json:"proxy"`       // proxy information in pdash value
+
+	Rpt int64 `json:"rpt"`
+}
+
+// score sets Rpt to the weighted sum of the components, at least minRptScore.
+func (b *RptBreakdown) score() {
+	rpt := b.Alpha*b.Rank + b.Beta*b.Txs + b.Gamma*b.Maintenance + b.Psi*b.Upload + b.Omega*b.Proxy
+	if rpt <= minRptScore {
+		rpt = minRptScore
+	}
+	b.Rpt = rpt
+}
+
 func (r *RptList) FormatString() string {
 	items := make([]string, len(*r))
 	for i, v := range *r {
@@ -149,13 +184,16 @@ type CandidateService interface {
 
 // CandidateServiceImpl is the default candidate list collector
 type CandidateServiceImpl struct {
+	config *configs.ChainConfig
 	client bind.ContractBackend
 }
 
-// NewCandidateService creates a concrete candidate service instance.
-func NewCandidateService(backend bind.ContractBackend) (CandidateService, error) {
+// NewCandidateService creates a concrete candidate service instance, the
+// campaign contract of a term follows the fork schedule of config.
+func NewCandidateService(config *configs.ChainConfig, backend bind.ContractBackend) (CandidateService, error) {
 
 	rs := &CandidateServiceImpl{
+		config: config,
 		client: backend,
 	}
 	return rs, nil
@@ -164,7 +202,11 @@ func NewCandidateService(backend bind.ContractBackend) (CandidateService, error)
 // CandidatesOf implements CandidateService
 func (rs *CandidateServiceImpl) CandidatesOf(term uint64) ([]common.Address, error) {
 
-	if term < backend.TermOf(configs.Campaign2BlockNumber) {
+	// a term reads the campaign contract forked in by its last block
+	dpor := configs.ChainConfigInfo().Dpor
+	rules := rs.config.Rules(new(big.Int).SetUint64((term + 1) * dpor.TermLen * dpor.ViewLen))
+
+	if !rules.IsCampaign2 {
 		// old campaign contract address
 		campaignAddr := configs.ChainConfigInfo().Dpor.Contracts[configs.ContractCampaign]
 
@@ -185,7 +227,7 @@ func (rs *CandidateServiceImpl) CandidatesOf(term uint64) ([]common.Address, err
 		return cds, nil
 	}
 
-	if term < backend.TermOf(configs.Campaign3BlockNumber) {
+	if !rules.IsCampaign3 {
 
 		// new campaign contract address
 		campaignAddr := configs.ChainConfigInfo().Dpor.Contracts[configs.ContractCampaign2]
@@ -229,30 +271,31 @@ func (rs *CandidateServiceImpl) CandidatesOf(term uint64) ([]common.Address, err
 type RptService interface {
 	CalcRptInfoList(addresses []common.Address, number uint64) RptList
 	CalcRptInfo(address common.Address, addresses []common.Address, blockNum uint64) Rpt
+	CalcRptBreakdown(address common.Address, addresses []common.Address, blockNum uint64) RptBreakdown
 	WindowSize() (uint64, error)
 }
 
 // RptCollector collects rpts infos of a given candidate
 type RptCollector interface {
 	RptOf(addr common.Address, addrs []common.Address, num uint64) Rpt
+	BreakdownOf(addr common.Address, addrs []common.Address, num uint64) RptBreakdown
 }
 
 // BasicCollector is the default rpt collector
 type RptServiceImpl struct {
+	config      *configs.ChainConfig
 	rptContract common.Address
 	client      bind.ContractBackend
 	rptInstance *contracts.Rpt
 
 	rptcache *lru.ARCCache
 
-	rptCollector2 RptCollector
-	rptCollector3 RptCollector
-	rptCollector4 RptCollector
-	rptCollector5 RptCollector
+	collectors []methodCollector // rpt methods registered, sorted by method
 }
 
-// NewRptService creates a concrete RPT service instance.
-func NewRptService(backend backend.ClientBackend, rptContractAddr common.Address) (RptService, error) {
+// NewRptService creates a concrete RPT service instance, the rpt method of a
+// block follows the fork schedule of config.
+func NewRptService(config *configs.ChainConfig, backend backend.ClientBackend, rptContractAddr common.Address) (RptService, error) {
 	log.Debug("rptContractAddr", "contractAddr", rptContractAddr.Hex())
 
 	rptInstance, err := contracts.NewRpt(rptContractAddr, backend)
@@ -262,21 +305,13 @@ func NewRptService(backend backend.ClientBackend, rptContractAddr common.Address
 
 	cache, _ := lru.NewARC(cacheSize)
 
-	newRptCollector2 := NewRptCollectorImpl2(rptInstance, backend)
-	newRptCollector3 := NewRptCollectorImpl3(rptInstance, backend)
-	newRptCollector4 := NewRptCollectorImpl4(rptInstance, backend)
-	newRptCollector5 := NewRptCollectorImpl5(rptInstance, backend)
-
 	bc := &RptServiceImpl{
+		config:      config,
 		client:      backend,
 		rptContract: rptContractAddr,
 		rptInstance: rptInstance,
 		rptcache:    cache,
-
-		rptCollector2: newRptCollector2,
-		rptCollector3: newRptCollector3,
-		rptCollector4: newRptCollector4,
-		rptCollector5: newRptCollector5,
+		collectors:  newCollectors(NewParamReader(rptInstance), backend),
 	}
 	return bc, nil
 }
@@ -315,28 +350,34 @@ func (rs *RptServiceImpl) CalcRptInfoList(addresses []common.Address, number uin
 
 // CalcRptInfo return the Rpt of the candidate address
 func (rs *RptServiceImpl) CalcRptInfo(address common.Address, addresses []common.Address, number uint64) Rpt {
-	if number < configs.RptCalcMethod2BlockNumber {
-		log.Debug("now calc rpt for with old rpt method", "addr", address.Hex(), "number", number)
-		return rs.calcRptInfo(address, number)
-	}
+	b := rs.CalcRptBreakdown(address, addresses, number)
+	return Rpt{Address: address, Rpt: b.Rpt}
+}
 
-	if number < configs.RptCalcMethod3BlockNumber {
-		log.Debug("now calc rpt for with rpt method 2", "addr", address.Hex(), "number", number)
-		return rs.rptCollector2.RptOf(address, addresses, number)
+// CalcRptBreakdown returns the Rpt of the candidate address with the
+// components it is made of
+func (rs *RptServiceImpl) CalcRptBreakdown(address common.Address, addresses []common.Address, number uint64) RptBreakdown {
+	// rpt methods fork one after another, the last forked is used
+	var collector *methodCollector
+	rules := rs.config.Rules(new(big.Int).SetUint64(number))
+	for i := range rs.collectors {
+		if !rs.collectors[i].isForked(rules) {
+			break
+		}
+		collector = &rs.collectors[i]
 	}
 
-	if number < configs.RptCalcMethod4BlockNumber {
-		log.Debug("now calc rpt for with rpt method 3", "addr", address.Hex(), "number", number)
-		return rs.rptCollector3.RptOf(address, addresses, number)
+	if collector != nil {
+		log.Debug("now calc rpt for with rpt method", "method", collector.method, "addr", address.Hex(), "number", number)
+		return collector.collector.BreakdownOf(address, addresses, number)
 	}
 
-	if number < configs.RptCalcMethod5BlockNumber {
-		log.Debug("now calc rpt for with rpt method 4", "addr", address.Hex(), "number", number)
-		return rs.rptCollector4.RptOf(address, addresses, number)
+	log.Debug("now calc rpt for with old rpt method", "addr", address.Hex(), "number", number)
+	b := RptBreakdown{Address: address, Number: number, Method: 1, Rpt: rs.calcRptInfo(address, number).Rpt}
+	if windowSize, err := rs.WindowSize(); err == nil {
+		b.WindowSize = int(windowSize)
 	}
-
-	log.Debug("now calc rpt for with rpt method 5", "addr", address.Hex(), "number", number)
-	return rs.rptCollector5.RptOf(address, addresses, number)
+	return b
 }
 
 func (rs *RptServiceImpl) calcRptInfo(address common.Address, blockNum uint64) Rpt {
@@ -4,172 +4,52 @@ import (
 	"context"
 	"math/big"
 	"sort"
-	"sync"
 	"time"
 
 	"bitbucket.org/cpchain/chain/commons/log"
 	"bitbucket.org/cpchain/chain/consensus/dpor/backend"
-	contracts "bitbucket.org/cpchain/chain/contracts/dpor/rpt"
 	"github.com/ethereum/go-ethereum/common"
 )
 
 // RptCollectorImpl2 implements RptCollector
 type RptCollectorImpl2 struct {
-	rptInstance  *contracts.Rpt
+	params       *ParamReader
 	chainBackend backend.ChainBackend
 	balances     *rptDataCache
-
-	alpha int64
-	beta  int64
-	gamma int64
-	psi   int64
-	omega int64
-
-	windowSize int
-
-	currentNum uint64
-	lock       sync.RWMutex
 }
 
-func NewRptCollectorImpl2(rptInstance *contracts.Rpt, chainBackend backend.ChainBackend) *RptCollectorImpl2 {
+// NewRptCollectorImpl2 creates the collector of rpt method 2, it is kept
+// as is for the blocks calculated with it.
+func NewRptCollectorImpl2(params *ParamReader, chainBackend backend.ChainBackend) *RptCollectorImpl2 {
 
 	return &RptCollectorImpl2{
-		rptInstance:  rptInstance,
+		params:       params,
 		chainBackend: chainBackend,
 		balances:     newRptDataCache(),
-		currentNum:   0,
-
-		alpha: 50,
-		beta:  15,
-		gamma: 10,
-		psi:   15,
-		omega: 10,
-
-		windowSize: 100,
-	}
-}
-
-func (rc *RptCollectorImpl2) Alpha(num uint64) int64 {
-	rc.lock.Lock()
-	defer rc.lock.Unlock()
-
-	if rc.rptInstance == nil || num == rc.currentNum {
-		return rc.alpha
-	}
-
-	a, err := rc.rptInstance.Alpha(nil)
-	if err == nil {
-		log.Debug("using parameters from contract", "alpha", a.Int64(), "num", num)
-		rc.alpha = a.Int64()
-	}
-
-	return rc.alpha
-}
-
-func (rc *RptCollectorImpl2) Beta(num uint64) int64 {
-	rc.lock.Lock()
-	defer rc.lock.Unlock()
-
-	if rc.rptInstance == nil || num == rc.currentNum {
-		return rc.beta
-	}
-
-	b, err := rc.rptInstance.Beta(nil)
-	if err == nil {
-		log.Debug("using parameters from contract", "beta", b.Int64(), "num", num)
-		rc.beta = b.Int64()
-	}
-
-	return rc.beta
-}
-
-func (rc *RptCollectorImpl2) Gamma(num uint64) int64 {
-	rc.lock.Lock()
-	defer rc.lock.Unlock()
-
-	if rc.rptInstance == nil || num == rc.currentNum {
-		return rc.gamma
-	}
-
-	g, err := rc.rptInstance.Gamma(nil)
-	if err == nil {
-		log.Debug("using parameters from contract", "gamma", g.Int64(), "num", num)
-		rc.gamma = g.Int64()
-	}
-
-	return rc.gamma
-}
-
-func (rc *RptCollectorImpl2) Psi(num uint64) int64 {
-	rc.lock.Lock()
-	defer rc.lock.Unlock()
-
-	if rc.rptInstance == nil || num == rc.currentNum {
-		return rc.psi
-	}
-
-	p, err := rc.rptInstance.Psi(nil)
-	if err == nil {
-		log.Debug("using parameters from contract", "psi", p.Int64(), "num", num)
-		rc.psi = p.Int64()
-	}
-
-	return rc.psi
-}
-
-func (rc *RptCollectorImpl2) Omega(num uint64) int64 {
-	rc.lock.Lock()
-	defer rc.lock.Unlock()
-
-	if rc.rptInstance == nil || num == rc.currentNum {
-		return rc.omega
-	}
-
-	o, err := rc.rptInstance.Omega(nil)
-	if err == nil {
-		log.Debug("using parameters from contract", "omega", o.Int64(), "num", num)
-		rc.omega = o.Int64()
 	}
-
-	return rc.omega
-}
-
-func (rc *RptCollectorImpl2) WindowSize(num uint64) int {
-	rc.lock.Lock()
-	defer rc.lock.Unlock()
-
-	if rc.rptInstance == nil || num == rc.currentNum {
-		return rc.windowSize
-	}
-
-	w, err := rc.rptInstance.Window(nil)
-	if err == nil {
-		log.Debug("using parameters from contract", "window", w.Int64(), "num", num)
-		rc.windowSize = int(w.Int64())
-	}
-
-	return rc.windowSize
-}
-
-func (rc *RptCollectorImpl2) coefficients(num uint64) (int64, int64, int64, int64, int64) {
-	return rc.Alpha(num), rc.Beta(num), rc.Gamma(num), rc.Psi(num), rc.Omega(num)
 }
 
+// RptOf returns the reputation value of a given address among a batch addresses
 func (rc *RptCollectorImpl2) RptOf(addr common.Address, addrs []common.Address, num uint64) Rpt {
+	b := rc.BreakdownOf(addr, addrs, num)
+	return Rpt{Address: addr, Rpt: b.Rpt}
+}
 
-	windowSize := rc.WindowSize(num)
-	alpha, beta, gamma, psi, omega := rc.coefficients(num)
-	if num != rc.currentNum {
-		rc.currentNum = num
-	}
+// BreakdownOf returns the components and coefficients the reputation value of
+// a given address among a batch addresses is made of
+func (rc *RptCollectorImpl2) BreakdownOf(addr common.Address, addrs []common.Address, num uint64) RptBreakdown {
 
-	rpt := int64(0)
-	rpt = alpha*rc.RankValueOf(addr, addrs, num, windowSize) + beta*rc.TxsValueOf(addr, num, windowSize) + gamma*rc.MaintenanceValueOf(addr, num, windowSize) + psi*rc.UploadValueOf(addr, num, windowSize) + omega*rc.ProxyValueOf(addr, num, windowSize)
+	p := rc.params.At(num)
+	windowSize := p.WindowSize
 
-	if rpt <= minRptScore {
-		rpt = minRptScore
-	}
-	return Rpt{Address: addr, Rpt: rpt}
+	b := p.breakdown(addr, num, 2)
+	b.Rank = rc.RankValueOf(addr, addrs, num, windowSize)
+	b.Txs = rc.TxsValueOf(addr, num, windowSize)
+	b.Maintenance = rc.MaintenanceValueOf(addr, num, windowSize)
+	b.Upload = rc.UploadValueOf(addr, num, windowSize)
+	b.Proxy = rc.ProxyValueOf(addr, num, windowSize)
+	b.score()
+	return b
 }
 
 func (rc *RptCollectorImpl2) RankValueOf(addr common.Address, addrs []common.Address, num uint64, windowSize int) int64 {
@@ -207,7 +207,7 @@ func TestRptServiceImpl_CalcRptInfoList(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			rs, _ := rpt.NewRptService(tt.fields.Client, tt.fields.RptContract)
+			rs, _ := rpt.NewRptService(configs.TestChainConfig, tt.fields.Client, tt.fields.RptContract)
 			tt.prepare()
 			log.Printf("Testcase [%s], RPT: %v", tt.name, rs.CalcRptInfoList(tt.args.addresses, tt.args.number)[0].Rpt)
 			if got := rs.CalcRptInfoList(tt.args.addresses, tt.args.number); !reflect.DeepEqual(got, tt.want) {
@@ -377,3 +377,84 @@ func TestRptOf5(t *testing.T) {
 	}
 
 }
+
+func TestBreakdownOf5(t *testing.T) {
+
+	numAccount := 30
+	numBlocks := 1000
+	accounts := generateABatchAccounts(numAccount)
+	fc := newFakeChainBackendForRptCollectorWithBalances(numBlocks, accounts)
+
+	rptCollector := rpt.NewRptCollectorImpl5(nil, fc)
+	for _, addr := range accounts {
+		b := rptCollector.BreakdownOf(addr, accounts, 500)
+		if b.Address != addr || b.Number != 500 || b.Method != 5 || b.WindowSize != 100 {
+			t.Fatalf("breakdown of %x: unexpected calculation %+v", addr, b)
+		}
+		if b.Alpha != 50 || b.Beta != 15 || b.Gamma != 10 || b.Psi != 15 || b.Omega != 10 {
+			t.Fatalf("breakdown of %x: unexpected coefficients %+v", addr, b)
+		}
+		sum := b.Alpha*b.Rank + b.Beta*b.Txs + b.Gamma*b.Maintenance + b.Psi*b.Upload + b.Omega*b.Proxy
+		if b.Rpt != sum {
+			t.Fatalf("breakdown of %x: rpt %d, want weighted sum %d", addr, b.Rpt, sum)
+		}
+		if r := rptCollector.RptOf(addr, accounts, 500); r.Rpt != b.Rpt {
+			t.Fatalf("breakdown of %x: rpt %d, RptOf %d", addr, b.Rpt, r.Rpt)
+		}
+	}
+}
+
+func TestRegisterCollector(t *testing.T) {
+	if err := rpt.RegisterCollectorSpec(rpt.RptMethod5Spec, func(r configs.Rules) bool { return r.IsRptMethod5 }); err == nil {
+		t.Fatal("registering rpt method 5 twice: want error")
+	}
+
+	spec := rpt.RptMethod5Spec
+	spec.Method = 6
+	spec.Txs.Measure = "coinage"
+	if err := rpt.RegisterCollectorSpec(spec, func(r configs.Rules) bool { return r.IsRptMethod5 }); err == nil {
+		t.Fatal("registering rpt method with unknown measure: want error")
+	}
+	if err := rpt.RegisterCollector(1, func(r configs.Rules) bool { return r.IsRptMethod2 }, nil); err == nil {
+		t.Fatal("registering rpt method 1: want error")
+	}
+}
+
+func TestComposedCollector(t *testing.T) {
+	numAccount := 30
+	numBlocks := 600
+	accounts := generateABatchAccounts(numAccount)
+	fc := newFakeChainBackendForRptCollectorWithBalances(numBlocks, accounts)
+
+	spec := rpt.CollectorSpec{
+		Method:      6,
+		Rank:        rpt.ComponentSpec{Measure: rpt.MeasureBalanceCpc, Rank: rpt.RankAscending, Score: rpt.ScoreRank},
+		Txs:         rpt.ComponentSpec{Measure: rpt.MeasureConst, Score: rpt.ScoreRank, Value: 0},
+		Maintenance: rpt.ComponentSpec{Measure: rpt.MeasureConst, Score: rpt.ScoreRank, Value: 0},
+		Upload:      rpt.ComponentSpec{Measure: rpt.MeasureConst, Score: rpt.ScoreRank, Value: 0},
+		Proxy:       rpt.ComponentSpec{Measure: rpt.MeasureConst, Score: rpt.ScoreRank, Value: 0},
+	}
+	rptCollector, err := rpt.NewComposedCollector(spec, rpt.NewParamReader(nil), fc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// balances of accounts are non-decreasing, so are the ranks
+	last := int64(-1)
+	for _, addr := range accounts {
+		b := rptCollector.BreakdownOf(addr, accounts, 500)
+		if b.Method != 6 || b.ParamsVersion != 0 || b.Alpha != 50 {
+			t.Fatalf("breakdown of %x: unexpected calculation %+v", addr, b)
+		}
+		if b.Rank < last {
+			t.Fatalf("breakdown of %x: rank %d less than %d", addr, b.Rank, last)
+		}
+		if b.Txs != 0 || b.Maintenance != 0 || b.Upload != 0 || b.Proxy != 0 {
+			t.Fatalf("breakdown of %x: unexpected constant components %+v", addr, b)
+		}
+		last = b.Rank
+	}
+	if last == 0 {
+		t.Fatal("rank of the richest account is 0")
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpt
+
+import (
+	"math/big"
+
+	"bitbucket.org/cpchain/chain/accounts/abi/bind"
+	"bitbucket.org/cpchain/chain/commons/log"
+	"bitbucket.org/cpchain/chain/configs"
+	"bitbucket.org/cpchain/chain/contracts/dpor/signer_register"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// SignerService provides methods to obtain the signing keys bound to proposers and validators
+type SignerService interface {
+	// SignersOf returns the signing keys of the identities in the term as
+	// registered at block number, only for the identities a new signing key
+	// is bound to
+	SignersOf(term uint64, identities []common.Address, number uint64) (map[common.Address]common.Address, error)
+}
+
+// SignerServiceImpl is the default signing key collector
+type SignerServiceImpl struct {
+	client bind.ContractBackend
+}
+
+// NewSignerService creates a concrete signer service instance.
+func NewSignerService(backend bind.ContractBackend) (SignerService, error) {
+	return &SignerServiceImpl{
+		client: backend,
+	}, nil
+}
+
+// SignersOf implements SignerService
+func (ss *SignerServiceImpl) SignersOf(term uint64, identities []common.Address, number uint64) (map[common.Address]common.Address, error) {
+	signers := make(map[common.Address]common.Address)
+
+	// no signer register, every identity signs with its own key
+	registerAddr := configs.ChainConfigInfo().Dpor.Contracts[configs.ContractSigner]
+	if registerAddr == (common.Address{}) || len(identities) == 0 {
+		return signers, nil
+	}
+
+	contractInstance, err := signer_register.NewSignerRegister(registerAddr, ss.client)
+	if err != nil {
+		return nil, err
+	}
+
+	// read the register at the given block, not the latest state
+	opts := &bind.CallOpts{BlockNumber: new(big.Int).SetUint64(number)}
+	keys, err := contractInstance.GetSignersOf(opts, identities, new(big.Int).SetUint64(term))
+	if err == bind.ErrNoCode {
+		// the register is not deployed yet, no signing key is bound
+		return signers, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for i, identity := range identities {
+		if i < len(keys) && keys[i] != identity {
+			signers[identity] = keys[i]
+		}
+	}
+
+	log.Debug("read signing keys from signer register", "term", term, "number", number, "len", len(signers), "contract addr", registerAddr.Hex())
+	return signers, nil
+}
@@ -0,0 +1,65 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package dpor
+
+import (
+	"encoding/binary"
+	"encoding/json"
+
+	"bitbucket.org/cpchain/chain/consensus/dpor/rpt"
+	"bitbucket.org/cpchain/chain/database"
+)
+
+// rptIndexPrefix + term (uint64 big endian) -> TermRpts
+var rptIndexPrefix = []byte("dpor-rpt-")
+
+// TermRpts is the rpt list the proposers of a term are elected by.
+type TermRpts struct {
+	Term   uint64      `json:"term"`   // term the proposers are elected for
+	Number uint64      `json:"number"` // block number the rpts are calculated at
+	Rpts   rpt.RptList `json:"rpts"`
+}
+
+func rptIndexKey(term uint64) []byte {
+	key := make([]byte, len(rptIndexPrefix)+8)
+	copy(key, rptIndexPrefix)
+	binary.BigEndian.PutUint64(key[len(rptIndexPrefix):], term)
+	return key
+}
+
+// writeTermRpts stores the rpts of an election, overwriting the ones of an
+// election of the same term on another branch.
+func writeTermRpts(db database.Database, rpts *TermRpts) error {
+	blob, err := json.Marshal(rpts)
+	if err != nil {
+		return err
+	}
+	return db.Put(rptIndexKey(rpts.Term), blob)
+}
+
+// readTermRpts loads the rpts the proposers of a term are elected by.
+func readTermRpts(db database.Database, term uint64) (*TermRpts, error) {
+	blob, err := db.Get(rptIndexKey(term))
+	if err != nil {
+		return nil, err
+	}
+	rpts := new(TermRpts)
+	if err := json.Unmarshal(blob, rpts); err != nil {
+		return nil, err
+	}
+	return rpts, nil
+}
@@ -0,0 +1,65 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package dpor
+
+import (
+	"reflect"
+	"testing"
+
+	"bitbucket.org/cpchain/chain/configs"
+	"bitbucket.org/cpchain/chain/consensus/dpor/rpt"
+	"bitbucket.org/cpchain/chain/database"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestTermRpts(t *testing.T) {
+	db := database.NewMemDatabase()
+	if _, err := readTermRpts(db, 5); err == nil {
+		t.Fatal("expected error for missing term")
+	}
+
+	want := &TermRpts{Term: 5, Number: 24, Rpts: rpt.RptList{{Address: common.Address{1}, Rpt: 100}, {Address: common.Address{2}, Rpt: 16}}}
+	if err := writeTermRpts(db, want); err != nil {
+		t.Fatal(err)
+	}
+	got, err := readTermRpts(db, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("rpts mismatch: have %+v, want %+v", got, want)
+	}
+}
+
+func TestSnapshot_electionRpts(t *testing.T) {
+	config := &configs.DporConfig{TermLen: 4, ViewLen: 3, MaxInitBlockNumber: 24}
+	snap := newSnapshot(config, 12, common.Hash{}, nil, nil, NormalMode)
+
+	rpts := rpt.RptList{{Address: common.Address{1}, Rpt: 4}, {Address: common.Address{2}, Rpt: 3}, {Address: common.Address{3}, Rpt: 2}, {Address: common.Address{4}, Rpt: 1}}
+	snap.updateProposers(rpts, 1)
+
+	elections := snap.takeElectionRpts()
+	if len(elections) != 1 {
+		t.Fatalf("elections mismatch: have %d, want 1", len(elections))
+	}
+	if e := elections[0]; e.Term != snap.FutureTermOf(12) || e.Number != 12 || !reflect.DeepEqual(e.Rpts, rpts) {
+		t.Errorf("election mismatch: %+v", e)
+	}
+	if len(snap.takeElectionRpts()) != 0 {
+		t.Error("elections not taken")
+	}
+}
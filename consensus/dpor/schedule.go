@@ -0,0 +1,152 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package dpor
+
+import (
+	"time"
+
+	"bitbucket.org/cpchain/chain/consensus"
+	"bitbucket.org/cpchain/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	defaultScheduleTerms = 4   // number of terms of a schedule if not given
+	maxScheduleTerms     = 100 // max number of terms of a schedule
+
+	turnCheckInterval = time.Second // interval to check the turn of the coinbase for subscriptions
+)
+
+// ViewSchedule is the blocks a proposer proposes in a term.
+type ViewSchedule struct {
+	Term       uint64          `json:"term"`
+	View       uint64          `json:"view"`
+	Proposer   *common.Address `json:"proposer,omitempty"` // nil if the term is not elected yet
+	StartBlock uint64          `json:"startBlock"`
+	EndBlock   uint64          `json:"endBlock"`
+	StartTime  uint64          `json:"startTime"` // unix time in milliseconds, expected if the block is not in the chain
+	Local      bool            `json:"local"`     // the local coinbase proposes the view
+}
+
+// TermSchedule is the blocks, the committees and the expected time of a term.
+type TermSchedule struct {
+	Term       uint64           `json:"term"`
+	StartBlock uint64           `json:"startBlock"`
+	EndBlock   uint64           `json:"endBlock"`
+	StartTime  uint64           `json:"startTime"` // unix time in milliseconds, expected if the block is not in the chain
+	EndTime    uint64           `json:"endTime"`
+	Elected    bool             `json:"elected"` // the proposers are elected, otherwise they are unknown yet
+	Proposers  []common.Address `json:"proposers"`
+	Validators []common.Address `json:"validators"` // projected from the last known committee for later terms
+	Views      []*ViewSchedule  `json:"views"`
+	Local      bool             `json:"local"` // the local coinbase proposes in the term
+}
+
+// schedule returns the schedules of terms from term from on. timeOf returns
+// the time of a block, the actual one if it is in the chain.
+func (s *DporSnapshot) schedule(from, terms uint64, coinbase common.Address, timeOf func(number uint64) uint64) []*TermSchedule {
+	var (
+		viewLen    = s.config.ViewLen
+		blocks     = s.config.TermLen * viewLen
+		validators []common.Address
+		schedules  []*TermSchedule
+	)
+	for term := from; term < from+terms; term++ {
+		// a term starts right after the last block of the previous one
+		start := s.StartBlockNumberOfTerm(term) + 1
+		ts := &TermSchedule{
+			Term:       term,
+			StartBlock: start,
+			EndBlock:   start + blocks - 1,
+			StartTime:  timeOf(start),
+			EndTime:    timeOf(start + blocks - 1),
+		}
+
+		// validators are carried over until a new committee is known
+		if known := s.getRecentValidators(term); len(known) > 0 {
+			validators = known
+		}
+		ts.Validators = validators
+
+		proposers := s.getRecentProposers(term)
+		ts.Elected = len(proposers) > 0
+		ts.Proposers = proposers
+
+		for view := uint64(0); view < s.config.TermLen; view++ {
+			vs := &ViewSchedule{
+				Term:       term,
+				View:       view,
+				StartBlock: start + view*viewLen,
+				EndBlock:   start + (view+1)*viewLen - 1,
+				StartTime:  timeOf(start + view*viewLen),
+			}
+			if view < uint64(len(proposers)) {
+				proposer := proposers[view]
+				vs.Proposer = &proposer
+				vs.Local = coinbase != (common.Address{}) && proposer == coinbase
+				ts.Local = ts.Local || vs.Local
+			}
+			ts.Views = append(ts.Views, vs)
+		}
+		schedules = append(schedules, ts)
+	}
+	return schedules
+}
+
+// nextTurn returns the first view of the local coinbase starting after block
+// number, nil if there is none in schedules.
+func nextTurn(schedules []*TermSchedule, number uint64) *ViewSchedule {
+	for _, ts := range schedules {
+		for _, vs := range ts.Views {
+			if vs.Local && vs.StartBlock > number {
+				return vs
+			}
+		}
+	}
+	return nil
+}
+
+// Schedule returns the schedules of terms from the term of the block after
+// head on. The time of a block not in the chain is expected from the block
+// periods, impeachments delay it.
+func (d *Dpor) Schedule(chain consensus.ChainReader, head *types.Header, terms uint64) ([]*TermSchedule, error) {
+	snap, err := d.dh.snapshot(d, chain, head.Number.Uint64(), head.Hash(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	number := head.Number.Uint64()
+	timeOf := func(n uint64) uint64 {
+		if n <= number {
+			if header := chain.GetHeaderByNumber(n); header != nil {
+				return header.Time.Uint64()
+			}
+		}
+		return head.Time.Uint64() + snap.periodsBetween(number, n)
+	}
+	return snap.schedule(snap.TermOf(number+1), terms, d.Coinbase(), timeOf), nil
+}
+
+// NextTurn returns the next view of the local coinbase after head among the
+// terms elected, nil if it is not elected.
+func (d *Dpor) NextTurn(chain consensus.ChainReader, head *types.Header) (*ViewSchedule, error) {
+	schedules, err := d.Schedule(chain, head, TermDistBetweenElectionAndMining+2)
+	if err != nil {
+		return nil, err
+	}
+	return nextTurn(schedules, head.Number.Uint64()), nil
+}
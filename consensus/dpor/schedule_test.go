@@ -0,0 +1,97 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package dpor
+
+import (
+	"reflect"
+	"testing"
+
+	"bitbucket.org/cpchain/chain/configs"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestSnapshot_schedule(t *testing.T) {
+	config := &configs.DporConfig{TermLen: 3, ViewLen: 2}
+	proposers := getProposerAddress()[:3]
+	validators := getValidatorAddress()
+	snap := newSnapshot(config, 0, common.Hash{}, proposers, validators, NormalMode)
+	snap.setRecentProposers(1, proposers)
+	snap.setRecentValidators(1, validators)
+	reversed := []common.Address{proposers[2], proposers[1], proposers[0]}
+	snap.setRecentProposers(2, reversed)
+
+	// the blocks up to 8 are in the chain, one every 100ms from 1000ms
+	timeOf := func(n uint64) uint64 {
+		if n <= 8 {
+			return 1000 + n*100
+		}
+		return 1800 + (n-8)*1000
+	}
+	schedules := snap.schedule(1, 3, proposers[2], timeOf)
+	if len(schedules) != 3 {
+		t.Fatalf("%d terms, want 3", len(schedules))
+	}
+
+	for i, tt := range []struct {
+		term, start, end, startTime uint64
+		elected, local              bool
+		proposers                   []common.Address
+	}{
+		{1, 7, 12, 1700, true, true, proposers},
+		{2, 13, 18, 6800, true, true, reversed},
+		{3, 19, 24, 12800, false, false, nil},
+	} {
+		ts := schedules[i]
+		if ts.Term != tt.term || ts.StartBlock != tt.start || ts.EndBlock != tt.end || ts.StartTime != tt.startTime {
+			t.Errorf("term %d schedule %+v", tt.term, ts)
+		}
+		if ts.Elected != tt.elected || ts.Local != tt.local || !reflect.DeepEqual(ts.Proposers, tt.proposers) {
+			t.Errorf("term %d elected %v, local %v, proposers %v", tt.term, ts.Elected, ts.Local, ts.Proposers)
+		}
+		// the committee of term 1 is carried over to the later terms
+		if !reflect.DeepEqual(ts.Validators, validators) {
+			t.Errorf("term %d validators %v", tt.term, ts.Validators)
+		}
+		if len(ts.Views) != 3 {
+			t.Fatalf("term %d has %d views, want 3", tt.term, len(ts.Views))
+		}
+		for view, vs := range ts.Views {
+			if vs.StartBlock != tt.start+uint64(view)*2 || vs.EndBlock != vs.StartBlock+1 || vs.StartTime != timeOf(vs.StartBlock) {
+				t.Errorf("term %d view %d schedule %+v", tt.term, view, vs)
+			}
+			if tt.elected != (vs.Proposer != nil) {
+				t.Errorf("term %d view %d proposer %v", tt.term, view, vs.Proposer)
+			}
+		}
+	}
+
+	// the local coinbase proposes the last view of term 1 and the first of term 2
+	if turn := nextTurn(schedules, 8); turn == nil || turn.Term != 1 || turn.View != 2 || turn.StartBlock != 11 {
+		t.Fatalf("next turn after block 8 %+v", turn)
+	}
+	if turn := nextTurn(schedules, 11); turn == nil || turn.Term != 2 || turn.View != 0 || turn.StartBlock != 13 {
+		t.Fatalf("next turn after block 11 %+v", turn)
+	}
+	if turn := nextTurn(schedules, 13); turn != nil {
+		t.Fatalf("next turn after block 13 %+v, want none", turn)
+	}
+
+	// no turn without a coinbase
+	if turn := nextTurn(snap.schedule(1, 3, common.Address{}, timeOf), 0); turn != nil {
+		t.Fatalf("next turn without a coinbase %+v", turn)
+	}
+}
@@ -0,0 +1,472 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package dpor
+
+import (
+	"math/big"
+
+	"bitbucket.org/cpchain/chain/commons/log"
+	"bitbucket.org/cpchain/chain/configs"
+	"bitbucket.org/cpchain/chain/consensus"
+	"bitbucket.org/cpchain/chain/core/state"
+	"bitbucket.org/cpchain/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// storage layout of rnode.sol and reward.sol, the slot of a variable or a
+// mapping and the offset of an item in its struct. A Set.Data takes the slot
+// of its flags mapping and the next one of its values array.
+const (
+	rnodeThresholdSlot     = 2 // uint256 rnodeThreshold
+	rnodeSetSlot           = 3 // Set.Data rnodes
+	rnodeParticipantsSlot  = 5 // mapping (address => Participant) Participants
+	rnodeLockedDepositItem = 0 // Participant.lockedDeposit
+
+	rewardElectionCriteriaSlot = 2  // uint256 electionCriteria
+	rewardBonusPoolSlot        = 3  // uint256 bonusPool
+	rewardRnodeSetSlot         = 5  // Set.Data rnodes
+	rewardInvestorsSlot        = 11 // mapping (address => Investor) investors
+	rewardFreeDepositItem      = 0  // Investor.freeDeposit
+	rewardLockedDepositItem    = 1  // Investor.lockedDeposit
+)
+
+// OffenceImpeached is the offence of a proposer failing to propose its block.
+const OffenceImpeached = "impeached"
+
+// PenaltyEvent is the topic of the log of a penalty deducted in a block. The
+// log is addressed from the reward contract credited the deduction, indexes
+// the offender and the hash of the offence, and its data is the block, the
+// offences, the penalty and the amount deducted, 32 bytes each.
+var PenaltyEvent = crypto.Keccak256Hash([]byte("Penalty(address,string,uint256,uint256,uint256,uint256)"))
+
+// Penalty is a deposit deduction of a proposer impeached for failing to
+// propose its block.
+type Penalty struct {
+	Offender common.Address `json:"offender"`
+	Offence  string         `json:"offence"`  // OffenceImpeached
+	Block    uint64         `json:"block"`    // impeachment block of the offence
+	Offences uint64         `json:"offences"` // offences of the proposer in the window up to the block
+	Amount   *hexutil.Big   `json:"amount"`   // deducted as far as the deposits of the offender cover it
+}
+
+// Offences is the offence record of a proposer.
+type Offences struct {
+	Blocks      []uint64 `json:"blocks"`      // impeachment blocks in the window
+	BarredUntil uint64   `json:"barredUntil"` // last term left out of the elections, 0 if not barred
+	Skips       []uint64 `json:"skips"`       // impeachment blocks in the window announced in maintenance notices, not penalised
+}
+
+// proposerOf returns the proposer of the slot of block number.
+func (s *DporSnapshot) proposerOf(number uint64) (common.Address, bool) {
+	if number == 0 {
+		return common.Address{}, false
+	}
+	proposers := s.ProposersOf(number)
+	idx := int(((number - 1) % (s.config.TermLen * s.config.ViewLen)) / s.config.ViewLen)
+	if idx >= len(proposers) {
+		return common.Address{}, false
+	}
+	return proposers[idx], true
+}
+
+// inWindow returns the blocks counted in the offence window of term.
+func (s *DporSnapshot) inWindow(blocks []uint64, term uint64) []uint64 {
+	var recent []uint64
+	for _, number := range blocks {
+		if s.TermOf(number)+s.config.Slashing.OffenceWindow > term {
+			recent = append(recent, number)
+		}
+	}
+	return recent
+}
+
+// recentOffences returns the offences of addr counted in term, s.lock is held.
+func (s *DporSnapshot) recentOffences(addr common.Address, term uint64) []uint64 {
+	return s.inWindow(s.Offences[addr], term)
+}
+
+// recordImpeachment records the offence of the proposer failing to propose
+// block number, and bars it from the elections once it reaches the threshold.
+func (s *DporSnapshot) recordImpeachment(number uint64) {
+	proposer, ok := s.proposerOf(number)
+	if !ok {
+		return
+	}
+	slashing := s.config.Slashing
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.Offences == nil {
+		s.Offences = make(map[common.Address][]uint64)
+	}
+	offences := append(s.recentOffences(proposer, s.TermOf(number)), number)
+	s.Offences[proposer] = offences
+	log.Debug("recorded impeachment of proposer", "number", number, "proposer", proposer.Hex(), "offences", len(offences))
+
+	if slashing.BarThreshold == 0 || slashing.BarTerms == 0 || uint64(len(offences)) < slashing.BarThreshold {
+		return
+	}
+
+	// bar it from the elections from now on, the terms already elected are kept
+	until := s.FutureTermOf(number) + slashing.BarTerms - 1
+	if s.Barred == nil {
+		s.Barred = make(map[common.Address]uint64)
+	}
+	if until > s.Barred[proposer] {
+		s.Barred[proposer] = until
+		log.Info("proposer barred from candidacy", "proposer", proposer.Hex(), "offences", len(offences), "until term", until)
+	}
+}
+
+// recordSkip records the proposer skipping block number on its maintenance
+// notice, it is not an offence.
+func (s *DporSnapshot) recordSkip(number uint64) {
+	proposer, ok := s.proposerOf(number)
+	if !ok {
+		return
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.Skips == nil {
+		s.Skips = make(map[common.Address][]uint64)
+	}
+	s.Skips[proposer] = append(s.inWindow(s.Skips[proposer], s.TermOf(number)), number)
+	log.Debug("recorded maintenance skip of proposer", "number", number, "proposer", proposer.Hex())
+}
+
+// pruneOffences drops the offences out of the window and the bars not
+// affecting the elections from block number on.
+func (s *DporSnapshot) pruneOffences(number uint64) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	term := s.TermOf(number)
+	for addr := range s.Offences {
+		if offences := s.recentOffences(addr, term); len(offences) > 0 {
+			s.Offences[addr] = offences
+		} else {
+			delete(s.Offences, addr)
+		}
+	}
+
+	for addr := range s.Skips {
+		if skips := s.inWindow(s.Skips[addr], term); len(skips) > 0 {
+			s.Skips[addr] = skips
+		} else {
+			delete(s.Skips, addr)
+		}
+	}
+
+	electedTerm := s.FutureTermOf(number)
+	for addr, until := range s.Barred {
+		if until < electedTerm {
+			delete(s.Barred, addr)
+		}
+	}
+}
+
+// offencesOf returns the offence record of addr.
+func (s *DporSnapshot) offencesOf(addr common.Address) *Offences {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	blocks := make([]uint64, len(s.Offences[addr]))
+	copy(blocks, s.Offences[addr])
+	skips := make([]uint64, len(s.Skips[addr]))
+	copy(skips, s.Skips[addr])
+	return &Offences{Blocks: blocks, BarredUntil: s.Barred[addr], Skips: skips}
+}
+
+// isBarred returns if addr is left out of the election of term.
+func (s *DporSnapshot) isBarred(addr common.Address, term uint64) bool {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	until, ok := s.Barred[addr]
+	return ok && until >= term
+}
+
+// unbarred returns the candidates not barred from the election of term.
+func (s *DporSnapshot) unbarred(candidates []common.Address, term uint64) []common.Address {
+	var kept []common.Address
+	for _, candidate := range candidates {
+		if s.isBarred(candidate, term) {
+			log.Debug("leave out barred candidate", "candidate", candidate.Hex(), "term", term)
+			continue
+		}
+		kept = append(kept, candidate)
+	}
+	return kept
+}
+
+// penaltyOf returns the penalty of the proposer impeached in block number,
+// the impeachment penalty times its offences up to the block.
+func (s *DporSnapshot) penaltyOf(number uint64) (*Penalty, bool) {
+	proposer, ok := s.proposerOf(number)
+	if !ok {
+		return nil, false
+	}
+
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	var (
+		offences uint64
+		recorded bool
+	)
+	for _, n := range s.Offences[proposer] {
+		if n <= number {
+			offences++
+		}
+		recorded = recorded || n == number
+	}
+	if !recorded {
+		return nil, false
+	}
+
+	amount := new(big.Int)
+	if penalty := s.config.Slashing.ImpeachPenalty; penalty != nil {
+		amount.Mul(penalty, new(big.Int).SetUint64(offences))
+	}
+	return &Penalty{Offender: proposer, Offence: OffenceImpeached, Block: number, Offences: offences, Amount: (*hexutil.Big)(amount)}, true
+}
+
+// penaltiesOf returns the penalties deducted in header. Impeachment blocks
+// leave the state untouched, so the offences of the impeachment blocks right
+// before a block are penalised in it.
+func (d *Dpor) penaltiesOf(chain consensus.ChainReader, header *types.Header) ([]*Penalty, error) {
+	number := header.Number.Uint64()
+	if number == 0 || header.Impeachment() {
+		return nil, nil
+	}
+	parent := chain.GetHeader(header.ParentHash, number-1)
+	if parent == nil {
+		return nil, consensus.ErrUnknownAncestor
+	}
+	if !parent.Impeachment() {
+		return nil, nil
+	}
+
+	snap, err := d.dh.snapshot(d, chain, number-1, header.ParentHash, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var penalties []*Penalty
+	for parent != nil && parent.Number.Uint64() > 0 && parent.Impeachment() {
+		if penalty, ok := snap.penaltyOf(parent.Number.Uint64()); ok {
+			penalties = append([]*Penalty{penalty}, penalties...)
+		}
+		parent = chain.GetHeader(parent.ParentHash, parent.Number.Uint64()-1)
+	}
+	return penalties, nil
+}
+
+// slash deducts the penalties of header from the deposits of the offenders,
+// and logs each of them as a PenaltyEvent of the block. The logs make up the
+// receipt of the block itself, after the receipts of its transactions.
+func (d *Dpor) slash(chain consensus.ChainReader, header *types.Header, statedb *state.StateDB) error {
+	if !d.config.IsSlashing(header.Number) {
+		return nil
+	}
+	penalties, err := d.penaltiesOf(chain, header)
+	if err != nil {
+		return err
+	}
+
+	var (
+		rnodeAddr  = d.config.Contracts[configs.ContractRnode]
+		rewardAddr = d.config.Contracts[configs.ContractReward]
+	)
+	for _, penalty := range penalties {
+		deducted := SlashDeposit(statedb, penalty.Offender, penalty.Amount.ToInt(), rnodeAddr, rewardAddr)
+		statedb.AddLog(penaltyLog(penalty, deducted, rewardAddr, header.Number.Uint64()))
+		log.Info("slashed offender", "number", header.Number, "offender", penalty.Offender.Hex(), "offence", penalty.Offence,
+			"block", penalty.Block, "offences", penalty.Offences, "penalty", penalty.Amount.ToInt(), "deducted", deducted)
+	}
+	return nil
+}
+
+// penaltyLog returns the PenaltyEvent log of penalty.
+func penaltyLog(penalty *Penalty, deducted *big.Int, rewardAddr common.Address, number uint64) *types.Log {
+	var data []byte
+	for _, word := range []*big.Int{new(big.Int).SetUint64(penalty.Block), new(big.Int).SetUint64(penalty.Offences), penalty.Amount.ToInt(), deducted} {
+		data = append(data, common.BigToHash(word).Bytes()...)
+	}
+	return &types.Log{
+		Address: rewardAddr,
+		Topics: []common.Hash{
+			PenaltyEvent,
+			common.BytesToHash(penalty.Offender.Bytes()),
+			crypto.Keccak256Hash([]byte(penalty.Offence)),
+		},
+		Data:        data,
+		BlockNumber: number,
+	}
+}
+
+// SlashDeposit deducts amount from the deposit addr locked in the rnode
+// contract, and the rest from the deposit it locked in the reward contract.
+// The deduction is moved to the reward contract and credited to its bonusPool,
+// paid out to the investors when the round closes unless the owner sets the
+// pool anew. Without a reward contract the deduction is burnt. It returns the
+// amount deducted, less than amount if the deposits fall short.
+//
+// The contracts are left as their own code leaves them once deposits fall
+// short: an rnode whose deposit falls below the rnode threshold quits the
+// rnodes, and the rest of its deposit is returned as quitRnode does, and an
+// investor whose deposits fall below the election criteria leaves the rnodes
+// of the reward contract as startNewRound does.
+func SlashDeposit(statedb *state.StateDB, addr common.Address, amount *big.Int, rnodeAddr, rewardAddr common.Address) *big.Int {
+	deducted := new(big.Int)
+	if amount.Sign() <= 0 {
+		return deducted
+	}
+
+	if rnodeAddr != (common.Address{}) {
+		// the deposits are all the contract holds, never take more
+		available := statedb.GetBalance(rnodeAddr)
+		if available.Cmp(amount) > 0 {
+			available = amount
+		}
+		taken := deductDeposit(statedb, rnodeAddr, depositSlot(addr, rnodeParticipantsSlot, rnodeLockedDepositItem), available)
+		statedb.SubBalance(rnodeAddr, taken)
+		if rewardAddr != (common.Address{}) {
+			statedb.AddBalance(rewardAddr, taken)
+		}
+		deducted.Add(deducted, taken)
+	}
+
+	if rewardAddr != (common.Address{}) && deducted.Cmp(amount) < 0 {
+		rest := new(big.Int).Sub(amount, deducted)
+		taken := deductDeposit(statedb, rewardAddr, depositSlot(addr, rewardInvestorsSlot, rewardLockedDepositItem), rest)
+		deducted.Add(deducted, taken)
+	}
+
+	if rewardAddr != (common.Address{}) && deducted.Sign() > 0 {
+		pool := statedb.GetState(rewardAddr, storageSlot(rewardBonusPoolSlot)).Big()
+		statedb.SetState(rewardAddr, storageSlot(rewardBonusPoolSlot), common.BigToHash(pool.Add(pool, deducted)))
+	}
+
+	if rnodeAddr != (common.Address{}) {
+		quitRnode(statedb, rnodeAddr, addr)
+	}
+	if rewardAddr != (common.Address{}) {
+		leaveRewardRnodes(statedb, rewardAddr, addr)
+	}
+	return deducted
+}
+
+// quitRnode removes addr from the rnodes of the rnode contract if its deposit
+// is below the rnode threshold, and returns the rest of its deposit.
+func quitRnode(statedb *state.StateDB, rnodeAddr common.Address, addr common.Address) {
+	slot := depositSlot(addr, rnodeParticipantsSlot, rnodeLockedDepositItem)
+	locked := statedb.GetState(rnodeAddr, slot).Big()
+	threshold := statedb.GetState(rnodeAddr, storageSlot(rnodeThresholdSlot)).Big()
+	if locked.Cmp(threshold) >= 0 || !setRemove(statedb, rnodeAddr, rnodeSetSlot, addr) {
+		return
+	}
+
+	// the deposits are all the contract holds, never return more
+	returned := statedb.GetBalance(rnodeAddr)
+	if returned.Cmp(locked) > 0 {
+		returned = locked
+	}
+	statedb.SetState(rnodeAddr, slot, common.Hash{})
+	statedb.SubBalance(rnodeAddr, returned)
+	statedb.AddBalance(addr, returned)
+	log.Info("rnode deposit below threshold, quit rnodes", "rnode", addr.Hex(), "returned", returned, "threshold", threshold)
+}
+
+// leaveRewardRnodes removes addr from the rnodes of the reward contract if its
+// deposits are below the election criteria.
+func leaveRewardRnodes(statedb *state.StateDB, rewardAddr common.Address, addr common.Address) {
+	total := new(big.Int).Add(
+		statedb.GetState(rewardAddr, depositSlot(addr, rewardInvestorsSlot, rewardFreeDepositItem)).Big(),
+		statedb.GetState(rewardAddr, depositSlot(addr, rewardInvestorsSlot, rewardLockedDepositItem)).Big(),
+	)
+	criteria := statedb.GetState(rewardAddr, storageSlot(rewardElectionCriteriaSlot)).Big()
+	if total.Cmp(criteria) < 0 && setRemove(statedb, rewardAddr, rewardRnodeSetSlot, addr) {
+		log.Info("reward deposit below election criteria, left rnodes", "investor", addr.Hex(), "deposit", total, "criteria", criteria)
+	}
+}
+
+// setRemove removes addr from the Set.Data at slot of contract as Set.remove
+// does, and returns whether it was in the set.
+func setRemove(statedb *state.StateDB, contract common.Address, slot uint64, addr common.Address) bool {
+	flag := mappingSlot(addr, slot)
+	if statedb.GetState(contract, flag) == (common.Hash{}) {
+		return false
+	}
+	statedb.SetState(contract, flag, common.Hash{})
+
+	// move the last value to the one removed
+	length := storageSlot(slot + 1)
+	size := statedb.GetState(contract, length).Big().Uint64()
+	for i := uint64(0); i < size; i++ {
+		if common.BytesToAddress(statedb.GetState(contract, arraySlot(slot+1, i)).Bytes()) == addr {
+			statedb.SetState(contract, arraySlot(slot+1, i), statedb.GetState(contract, arraySlot(slot+1, size-1)))
+			statedb.SetState(contract, arraySlot(slot+1, size-1), common.Hash{})
+			statedb.SetState(contract, length, common.BigToHash(new(big.Int).SetUint64(size-1)))
+			break
+		}
+	}
+	return true
+}
+
+// deductDeposit deducts up to amount from the deposit stored in slot of
+// contract, and returns the amount deducted.
+func deductDeposit(statedb *state.StateDB, contract common.Address, slot common.Hash, amount *big.Int) *big.Int {
+	locked := statedb.GetState(contract, slot).Big()
+	taken := new(big.Int).Set(amount)
+	if locked.Cmp(taken) < 0 {
+		taken.Set(locked)
+	}
+	if taken.Sign() > 0 {
+		statedb.SetState(contract, slot, common.BigToHash(new(big.Int).Sub(locked, taken)))
+	}
+	return taken
+}
+
+// storageSlot returns the storage slot of the variable at slot.
+func storageSlot(slot uint64) common.Hash {
+	return common.BigToHash(new(big.Int).SetUint64(slot))
+}
+
+// mappingSlot returns the storage slot of the value mapped to addr by the
+// mapping at slot, as solc lays them out.
+func mappingSlot(addr common.Address, slot uint64) common.Hash {
+	return crypto.Keccak256Hash(common.LeftPadBytes(addr.Bytes(), 32), storageSlot(slot).Bytes())
+}
+
+// depositSlot returns the storage slot of item of the struct mapped to addr by
+// the mapping at slot, as solc lays them out.
+func depositSlot(addr common.Address, slot uint64, item uint64) common.Hash {
+	return common.BigToHash(new(big.Int).Add(mappingSlot(addr, slot).Big(), new(big.Int).SetUint64(item)))
+}
+
+// arraySlot returns the storage slot of item index of the dynamic array at
+// slot, as solc lays them out.
+func arraySlot(slot uint64, index uint64) common.Hash {
+	base := crypto.Keccak256Hash(storageSlot(slot).Bytes())
+	return common.BigToHash(new(big.Int).Add(base.Big(), new(big.Int).SetUint64(index)))
+}
@@ -0,0 +1,273 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package dpor_test
+
+import (
+	"context"
+	"math"
+	"math/big"
+	"testing"
+
+	"bitbucket.org/cpchain/chain"
+	"bitbucket.org/cpchain/chain/accounts/abi/bind"
+	"bitbucket.org/cpchain/chain/accounts/abi/bind/backends"
+	"bitbucket.org/cpchain/chain/configs"
+	"bitbucket.org/cpchain/chain/consensus/dpor"
+	"bitbucket.org/cpchain/chain/contracts/dpor/reward"
+	"bitbucket.org/cpchain/chain/contracts/dpor/rnode"
+	"bitbucket.org/cpchain/chain/core"
+	"bitbucket.org/cpchain/chain/core/state"
+	"bitbucket.org/cpchain/chain/core/vm"
+	"bitbucket.org/cpchain/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var (
+	ownerKey, _    = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+	ownerAddr      = crypto.PubkeyToAddress(ownerKey.PublicKey)
+	offenderKey, _ = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f292")
+	offenderAddr   = crypto.PubkeyToAddress(offenderKey.PublicKey)
+)
+
+func cpc(n int64) *big.Int {
+	return new(big.Int).Mul(big.NewInt(n), big.NewInt(configs.Cpc))
+}
+
+// stateCaller runs contract calls on a state.
+type stateCaller struct {
+	chain *core.BlockChain
+	state *state.StateDB
+}
+
+func (c *stateCaller) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	return c.state.GetCode(contract), nil
+}
+
+func (c *stateCaller) CallContract(ctx context.Context, call cpchain.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	snapshot := c.state.Snapshot()
+	defer c.state.RevertToSnapshot(snapshot)
+
+	msg := types.NewMessage(call.From, call.To, 0, new(big.Int), math.MaxUint64/2, new(big.Int), call.Data, false)
+	evm := vm.NewEVM(core.NewEVMContext(msg, c.chain.CurrentHeader(), c.chain, nil), c.state, c.chain.Config(), vm.Config{})
+	ret, _, _, err := core.ApplyMessage(evm, msg, new(core.GasPool).AddGas(math.MaxUint64))
+	return ret, err
+}
+
+// depositFixture is an offender holding deposits in the rnode and the reward
+// contracts, joining the rnodes of both.
+type depositFixture struct {
+	rnodeAddr    common.Address
+	rewardAddr   common.Address
+	statedb      *state.StateDB
+	rnodeCaller  *rnode.RnodeCaller
+	rewardCaller *reward.RewardCaller
+}
+
+func newDepositFixture(t *testing.T) *depositFixture {
+	contractBackend := backends.NewDporSimulatedBackend(core.GenesisAlloc{
+		ownerAddr:    {Balance: cpc(1000000)},
+		offenderAddr: {Balance: cpc(1000000)},
+	})
+	owner := bind.NewKeyedTransactor(ownerKey)
+	owner.GasLimit = 5000000
+	offender := bind.NewKeyedTransactor(offenderKey)
+	offender.GasLimit = 5000000
+
+	mustTx := func(_ interface{}, err error) {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	rnodeAddr, _, rnodeInstance, err := rnode.DeployRnode(owner, contractBackend)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rewardAddr, _, rewardInstance, err := reward.DeployReward(owner, contractBackend)
+	if err != nil {
+		t.Fatal(err)
+	}
+	contractBackend.Commit()
+
+	// 200000 cpc locked in each contract, right at the rnode threshold and
+	// the election criteria
+	offender.Value = cpc(200000)
+	mustTx(rnodeInstance.JoinRnode(offender))
+	mustTx(rewardInstance.SetPeriod(owner, big.NewInt(0)))
+	mustTx(rewardInstance.NewRaise(owner))
+	contractBackend.Commit()
+	offender.Value = cpc(200000)
+	mustTx(rewardInstance.SubmitDeposit(offender))
+	offender.Value = nil
+	contractBackend.Commit()
+	mustTx(rewardInstance.StartNewRound(owner))
+	contractBackend.Commit()
+
+	// and 1000 cpc free in the reward contract
+	mustTx(rewardInstance.NewRaise(owner))
+	contractBackend.Commit()
+	offender.Value = cpc(1000)
+	mustTx(rewardInstance.SubmitDeposit(offender))
+	offender.Value = nil
+	contractBackend.Commit()
+
+	chain := contractBackend.Blockchain()
+	statedb, err := chain.State()
+	if err != nil {
+		t.Fatal(err)
+	}
+	caller := &stateCaller{chain: chain, state: statedb}
+	rnodeCaller, _ := rnode.NewRnodeCaller(rnodeAddr, caller)
+	rewardCaller, _ := reward.NewRewardCaller(rewardAddr, caller)
+	return &depositFixture{
+		rnodeAddr:    rnodeAddr,
+		rewardAddr:   rewardAddr,
+		statedb:      statedb,
+		rnodeCaller:  rnodeCaller,
+		rewardCaller: rewardCaller,
+	}
+}
+
+// TestDepositStorageLayout pins the storage slots slashing reads and writes
+// to the variables of the compiled rnode and reward contracts.
+func TestDepositStorageLayout(t *testing.T) {
+	f := newDepositFixture(t)
+
+	word := func(contract common.Address, slot common.Hash) *big.Int {
+		return f.statedb.GetState(contract, slot).Big()
+	}
+	for _, tt := range []struct {
+		what     string
+		contract common.Address
+		slot     common.Hash
+		get      func() (*big.Int, error)
+	}{
+		{"rnode threshold", f.rnodeAddr, dpor.StorageSlot(dpor.RnodeThresholdSlot), func() (*big.Int, error) {
+			return f.rnodeCaller.RnodeThreshold(nil)
+		}},
+		{"rnode locked deposit", f.rnodeAddr, dpor.DepositSlot(offenderAddr, dpor.RnodeParticipantsSlot, dpor.RnodeLockedDepositItem), func() (*big.Int, error) {
+			participant, err := f.rnodeCaller.Participants(nil, offenderAddr)
+			return participant.LockedDeposit, err
+		}},
+		{"rnodes length", f.rnodeAddr, dpor.StorageSlot(dpor.RnodeSetSlot + 1), func() (*big.Int, error) {
+			return f.rnodeCaller.GetRnodeNum(nil)
+		}},
+		{"reward election criteria", f.rewardAddr, dpor.StorageSlot(dpor.RewardElectionCriteriaSlot), func() (*big.Int, error) {
+			return f.rewardCaller.ElectionCriteria(nil)
+		}},
+		{"reward bonus pool", f.rewardAddr, dpor.StorageSlot(dpor.RewardBonusPoolSlot), func() (*big.Int, error) {
+			return f.rewardCaller.BonusPool(nil)
+		}},
+		{"reward free deposit", f.rewardAddr, dpor.DepositSlot(offenderAddr, dpor.RewardInvestorsSlot, dpor.RewardFreeDepositItem), func() (*big.Int, error) {
+			return f.rewardCaller.GetFreeBalance(nil, offenderAddr)
+		}},
+		{"reward locked deposit", f.rewardAddr, dpor.DepositSlot(offenderAddr, dpor.RewardInvestorsSlot, dpor.RewardLockedDepositItem), func() (*big.Int, error) {
+			return f.rewardCaller.GetLockedBalance(nil, offenderAddr)
+		}},
+	} {
+		want, err := tt.get()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want.Sign() == 0 {
+			t.Fatalf("%s: zero in the contract, the slot is not pinned", tt.what)
+		}
+		if got := word(tt.contract, tt.slot); got.Cmp(want) != 0 {
+			t.Errorf("%s: %v in its slot, %v in the contract", tt.what, got, want)
+		}
+	}
+
+	// the rnodes sets hold the offender as their only value
+	for _, set := range []struct {
+		what     string
+		contract common.Address
+		slot     uint64
+	}{
+		{"rnodes", f.rnodeAddr, dpor.RnodeSetSlot},
+		{"reward rnodes", f.rewardAddr, dpor.RewardRnodeSetSlot},
+	} {
+		if word(set.contract, dpor.MappingSlot(offenderAddr, set.slot)).Sign() == 0 {
+			t.Errorf("%s: offender not flagged", set.what)
+		}
+		if got := common.BytesToAddress(f.statedb.GetState(set.contract, dpor.ArraySlot(set.slot+1, 0)).Bytes()); got != offenderAddr {
+			t.Errorf("%s: first value %x, want %x", set.what, got, offenderAddr)
+		}
+	}
+}
+
+func TestSlashDeposit(t *testing.T) {
+	f := newDepositFixture(t)
+	statedb := f.statedb
+
+	check := func(rnodeDeposit, rewardDeposit, rewardBalance, bonusPool *big.Int, isRnode, isRewardRnode bool) {
+		t.Helper()
+		participant, err := f.rnodeCaller.Participants(nil, offenderAddr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if participant.LockedDeposit.Cmp(rnodeDeposit) != 0 {
+			t.Fatalf("rnode deposit %v, want %v", participant.LockedDeposit, rnodeDeposit)
+		}
+		locked, err := f.rewardCaller.GetLockedBalance(nil, offenderAddr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if locked.Cmp(rewardDeposit) != 0 {
+			t.Fatalf("reward deposit %v, want %v", locked, rewardDeposit)
+		}
+		if got := statedb.GetBalance(f.rnodeAddr); got.Cmp(rnodeDeposit) != 0 {
+			t.Fatalf("rnode contract balance %v, want %v", got, rnodeDeposit)
+		}
+		if got := statedb.GetBalance(f.rewardAddr); got.Cmp(rewardBalance) != 0 {
+			t.Fatalf("reward contract balance %v, want %v", got, rewardBalance)
+		}
+		if got, _ := f.rewardCaller.BonusPool(nil); got.Cmp(bonusPool) != 0 {
+			t.Fatalf("bonus pool %v, want %v", got, bonusPool)
+		}
+		if got, _ := f.rnodeCaller.IsRnode(nil, offenderAddr); got != isRnode {
+			t.Fatalf("rnode %v, want %v", got, isRnode)
+		}
+		if got, _ := f.rnodeCaller.GetRnodeNum(nil); got.Cmp(big.NewInt(map[bool]int64{false: 0, true: 1}[isRnode])) != 0 {
+			t.Fatalf("%v rnodes, want rnode %v", got, isRnode)
+		}
+		if got, _ := f.rewardCaller.IsRNode(nil, offenderAddr); got != isRewardRnode {
+			t.Fatalf("reward rnode %v, want %v", got, isRewardRnode)
+		}
+	}
+	check(cpc(200000), cpc(200000), cpc(201000), cpc(1250000), true, true)
+	balance := statedb.GetBalance(offenderAddr)
+
+	// taken from the rnode deposit first, into the bonus pool of the reward
+	// contract, and the offender quits the rnodes below the threshold with the
+	// rest returned
+	if deducted := dpor.SlashDeposit(statedb, offenderAddr, cpc(1000), f.rnodeAddr, f.rewardAddr); deducted.Cmp(cpc(1000)) != 0 {
+		t.Fatalf("deducted %v, want 1000 cpc", deducted)
+	}
+	check(new(big.Int), cpc(200000), cpc(202000), cpc(1251000), false, true)
+	if got, want := statedb.GetBalance(offenderAddr), new(big.Int).Add(balance, cpc(199000)); got.Cmp(want) != 0 {
+		t.Fatalf("offender balance %v, want %v", got, want)
+	}
+
+	// then from the locked reward deposit, as far as it covers it, and the
+	// offender leaves the rnodes of the reward contract below the election
+	// criteria
+	if deducted := dpor.SlashDeposit(statedb, offenderAddr, cpc(300000), f.rnodeAddr, f.rewardAddr); deducted.Cmp(cpc(200000)) != 0 {
+		t.Fatalf("deducted %v, want 200000 cpc", deducted)
+	}
+	check(new(big.Int), new(big.Int), cpc(202000), cpc(1451000), false, false)
+}
@@ -0,0 +1,273 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package dpor
+
+import (
+	"io/ioutil"
+	"math/big"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	"bitbucket.org/cpchain/chain/configs"
+	"bitbucket.org/cpchain/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func newSlashingSnapshot(block int64) (*DporSnapshot, []common.Address) {
+	config := &configs.DporConfig{TermLen: 3, ViewLen: 1, Slashing: &configs.SlashingConfig{
+		Block:          big.NewInt(block),
+		ImpeachPenalty: big.NewInt(10),
+		OffenceWindow:  2,
+		BarThreshold:   2,
+		BarTerms:       2,
+	}}
+	proposers := getProposerAddress()[:3]
+	snap := newSnapshot(config, 0, common.Hash{}, proposers, getValidatorAddress(), NormalMode)
+	for term := uint64(1); term < 8; term++ {
+		snap.setRecentProposers(term, proposers)
+	}
+	return snap, proposers
+}
+
+// applySlots applies the blocks from the next one to number, the ones in
+// impeached are impeachment blocks.
+func applySlots(t *testing.T, snap *DporSnapshot, number uint64, impeached ...uint64) {
+	for n := snap.number() + 1; n <= number; n++ {
+		header := &types.Header{Number: new(big.Int).SetUint64(n), Coinbase: common.Address{0x01}}
+		for _, i := range impeached {
+			if i == n {
+				header.Coinbase = common.Address{}
+			}
+		}
+		if err := snap.applyHeader(header, false, nil, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestSnapshot_recordImpeachment(t *testing.T) {
+	snap, proposers := newSlashingSnapshot(1)
+	offender := proposers[1]
+
+	// the second slot of term 0 and term 1
+	applySlots(t, snap, 5, 2, 5)
+	if got := snap.offencesOf(offender).Blocks; !reflect.DeepEqual(got, []uint64{2, 5}) {
+		t.Fatalf("offences %v, want [2 5]", got)
+	}
+	if got := snap.offencesOf(proposers[0]).Blocks; len(got) != 0 {
+		t.Fatalf("offences of an innocent proposer %v", got)
+	}
+
+	// the penalty grows with the offences
+	for _, tt := range []struct {
+		number, offences uint64
+		amount           int64
+	}{{2, 1, 10}, {5, 2, 20}} {
+		penalty, ok := snap.penaltyOf(tt.number)
+		if !ok {
+			t.Fatalf("no penalty of block %d", tt.number)
+		}
+		if penalty.Offender != offender || penalty.Offences != tt.offences || penalty.Amount.ToInt().Int64() != tt.amount {
+			t.Fatalf("penalty of block %d %+v, want %d offences and %d", tt.number, penalty, tt.offences, tt.amount)
+		}
+	}
+	if _, ok := snap.penaltyOf(4); ok {
+		t.Fatal("penalty of a block not impeached")
+	}
+
+	// two offences bar it from the next two elections, term 4 and 5
+	if until := snap.offencesOf(offender).BarredUntil; until != 5 {
+		t.Fatalf("barred until %d, want 5", until)
+	}
+	if got := snap.unbarred(proposers, 4); !reflect.DeepEqual(got, []common.Address{proposers[0], proposers[2]}) {
+		t.Fatalf("unbarred candidates %v", got)
+	}
+	if !snap.isBarred(offender, 5) || snap.isBarred(offender, 6) {
+		t.Fatal("barred for the wrong terms")
+	}
+
+	// a copy keeps the record
+	if cpy := snap.copy(); !reflect.DeepEqual(cpy.offencesOf(offender), snap.offencesOf(offender)) {
+		t.Fatal("offences not copied")
+	}
+
+	// the offence of term 0 is out of the window from term 2 on
+	applySlots(t, snap, 9)
+	if got := snap.offencesOf(offender).Blocks; !reflect.DeepEqual(got, []uint64{5}) {
+		t.Fatalf("offences %v, want [5]", got)
+	}
+
+	// the bar ends once the elections it affects are over
+	applySlots(t, snap, 12)
+	if got := snap.offencesOf(offender); len(got.Blocks) != 0 || got.BarredUntil != 0 {
+		t.Fatalf("offence record %+v after the window", got)
+	}
+}
+
+func TestSnapshot_recordImpeachmentBeforeFork(t *testing.T) {
+	snap, proposers := newSlashingSnapshot(100)
+
+	applySlots(t, snap, 5, 2, 5)
+	if got := snap.offencesOf(proposers[1]); len(got.Blocks) != 0 || got.BarredUntil != 0 {
+		t.Fatalf("offences recorded before the fork %+v", got)
+	}
+	if _, ok := snap.penaltyOf(5); ok {
+		t.Fatal("penalty before the fork")
+	}
+}
+
+func TestPenaltyLog(t *testing.T) {
+	penalty := &Penalty{Offender: common.Address{0x01}, Offence: OffenceImpeached, Block: 5, Offences: 2, Amount: (*hexutil.Big)(big.NewInt(20))}
+	l := penaltyLog(penalty, big.NewInt(15), common.Address{0x02}, 6)
+
+	if l.Address != (common.Address{0x02}) || l.BlockNumber != 6 {
+		t.Fatalf("unexpected log %+v", l)
+	}
+	topics := []common.Hash{PenaltyEvent, common.BytesToHash(penalty.Offender.Bytes()), crypto.Keccak256Hash([]byte("impeached"))}
+	if !reflect.DeepEqual(l.Topics, topics) {
+		t.Fatalf("topics %v, want %v", l.Topics, topics)
+	}
+	var data []byte
+	for _, word := range []int64{5, 2, 20, 15} {
+		data = append(data, common.BigToHash(big.NewInt(word)).Bytes()...)
+	}
+	if !reflect.DeepEqual(l.Data, data) {
+		t.Fatalf("data %x, want %x", l.Data, data)
+	}
+}
+
+// stateVarSlots lays out the state variables of the contract in source as
+// solc does, returning the slot of each. Value types share a slot while they
+// fit, a mapping takes one slot and a Set.Data two.
+func stateVarSlots(t *testing.T, source string) map[string]uint64 {
+	source = regexp.MustCompile(`(?s)/\*.*?\*/|//[^\n]*`).ReplaceAllString(source, "")
+
+	var (
+		statements []string
+		current    strings.Builder
+		depth      int
+	)
+	for _, c := range source {
+		switch {
+		case c == '{':
+			depth++
+			current.Reset()
+		case c == '}':
+			depth--
+			current.Reset()
+		case c == ';' && depth == 1:
+			statements = append(statements, strings.TrimSpace(current.String()))
+			current.Reset()
+		case depth == 1:
+			current.WriteRune(c)
+		}
+	}
+
+	sizes := map[string]uint64{"address": 20, "bool": 1, "uint256": 32}
+	slots := make(map[string]uint64)
+	var slot, offset uint64
+	for _, statement := range statements {
+		statement = regexp.MustCompile(`mapping\s*\([^)]*\)`).ReplaceAllString(statement, "mapping")
+		fields := strings.Fields(strings.SplitN(statement, "=", 2)[0])
+		if len(fields) < 2 || fields[0] == "using" || fields[0] == "event" {
+			continue
+		}
+		name := fields[len(fields)-1]
+		if offset > 0 && (fields[0] == "mapping" || fields[0] == "Set.Data" || offset+sizes[fields[0]] > 32) {
+			slot, offset = slot+1, 0
+		}
+		slots[name] = slot
+
+		switch {
+		case fields[0] == "mapping":
+			slot++
+		case fields[0] == "Set.Data":
+			slot += 2
+		default:
+			size, ok := sizes[fields[0]]
+			if !ok {
+				t.Fatalf("unknown type of state variable %q", statement)
+			}
+			if offset += size; offset == 32 {
+				slot, offset = slot+1, 0
+			}
+		}
+	}
+	return slots
+}
+
+// structFields returns the fields of struct name in source, in order.
+func structFields(t *testing.T, source string, name string) []string {
+	match := regexp.MustCompile(`struct\s+` + name + `\s*\{([^}]*)\}`).FindStringSubmatch(source)
+	if match == nil {
+		t.Fatalf("no struct %s", name)
+	}
+	var fields []string
+	for _, field := range strings.Split(match[1], ";") {
+		field = regexp.MustCompile(`//[^\n]*`).ReplaceAllString(field, "")
+		if words := strings.Fields(field); len(words) > 0 {
+			fields = append(fields, words[len(words)-1])
+		}
+	}
+	return fields
+}
+
+// TestDepositStorageLayoutSource pins the storage slots slashing reads and
+// writes to the state variables of the rnode and reward contract sources, so
+// that a change of their layout fails before the bindings are regenerated.
+func TestDepositStorageLayoutSource(t *testing.T) {
+	for _, tt := range []struct {
+		path    string
+		slots   map[string]uint64
+		structs map[string]map[string]uint64
+	}{
+		{
+			path:    "../../contracts/dpor/rnode/rnode.sol",
+			slots:   map[string]uint64{"rnodeThreshold": rnodeThresholdSlot, "rnodes": rnodeSetSlot, "Participants": rnodeParticipantsSlot},
+			structs: map[string]map[string]uint64{"Participant": {"lockedDeposit": rnodeLockedDepositItem}},
+		},
+		{
+			path: "../../contracts/dpor/reward/reward.sol",
+			slots: map[string]uint64{"electionCriteria": rewardElectionCriteriaSlot, "bonusPool": rewardBonusPoolSlot,
+				"rnodes": rewardRnodeSetSlot, "investors": rewardInvestorsSlot},
+			structs: map[string]map[string]uint64{"Investor": {"freeDeposit": rewardFreeDepositItem, "lockedDeposit": rewardLockedDepositItem}},
+		},
+	} {
+		source, err := ioutil.ReadFile(tt.path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		slots := stateVarSlots(t, string(source))
+		for name, want := range tt.slots {
+			if got, ok := slots[name]; !ok || got != want {
+				t.Errorf("%s: %s in slot %d, want %d", tt.path, name, got, want)
+			}
+		}
+		for name, items := range tt.structs {
+			fields := structFields(t, string(source), name)
+			for item, want := range items {
+				if want >= uint64(len(fields)) || fields[want] != item {
+					t.Errorf("%s: %s.%s not item %d of %v", tt.path, name, item, want, fields)
+				}
+			}
+		}
+	}
+}
@@ -49,8 +49,28 @@ type DporSnapshot struct {
 	RecentProposers  map[uint64][]common.Address `json:"proposers"`  // Set of recent proposers
 	RecentValidators map[uint64][]common.Address `json:"validators"` // Set of recent validators
 
+	// Signing keys bound to proposers and validators of recent terms as carried in the headers, term => identity => signing key
+	RecentSigners map[uint64]map[common.Address]common.Address `json:"signers"`
+
+	// Block periods of recent terms in milliseconds as carried in the headers, term => period
+	RecentPeriods map[uint64]uint64 `json:"periods,omitempty"`
+
+	// Impeachment blocks of the proposers failing to propose in the offence
+	// window, and the last term offenders are left out of the elections
+	Offences map[common.Address][]uint64 `json:"offences,omitempty"`
+	Barred   map[common.Address]uint64   `json:"barred,omitempty"`
+
+	// Impeachment blocks of the proposers skipping their blocks on a maintenance notice in the offence window
+	Skips map[common.Address][]uint64 `json:"skips,omitempty"`
+
+	// Maintenance notices carried in the headers not ended yet
+	Notices []*backend.MaintenanceNotice `json:"notices,omitempty"`
+
 	config *configs.DporConfig // Consensus engine parameters to fine tune behavior
 
+	// Rpts of the elections run while applying headers, not yet written to the rpt index
+	electionRpts []*TermRpts
+
 	lock sync.RWMutex
 }
 
@@ -189,6 +209,124 @@ func (s *DporSnapshot) setRecentProposers(term uint64, proposers []common.Addres
 
 }
 
+func (s *DporSnapshot) recentSigners() map[uint64]map[common.Address]common.Address {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	// copy and return signers
+	recentSigners := make(map[uint64]map[common.Address]common.Address)
+	for term, signers := range s.RecentSigners {
+		recentSigners[term] = make(map[common.Address]common.Address, len(signers))
+		for identity, signer := range signers {
+			recentSigners[term][identity] = signer
+		}
+	}
+	return recentSigners
+}
+
+func (s *DporSnapshot) setRecentSigners(term uint64, signers map[common.Address]common.Address) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	ss := make(map[common.Address]common.Address, len(signers))
+	for identity, signer := range signers {
+		ss[identity] = signer
+	}
+
+	// snapshots stored before signing keys were introduced have none
+	if s.RecentSigners == nil {
+		s.RecentSigners = make(map[uint64]map[common.Address]common.Address)
+	}
+	s.RecentSigners[term] = ss
+
+	// terms all impeached carry no keys, prune every term before
+	for t := range s.RecentSigners {
+		if t+MaxSizeOfRecentProposers <= term {
+			delete(s.RecentSigners, t)
+		}
+	}
+}
+
+// hasSigners returns if the signing keys of the term are recorded
+func (s *DporSnapshot) hasSigners(term uint64) bool {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	_, ok := s.RecentSigners[term]
+	return ok
+}
+
+func (s *DporSnapshot) recentPeriods() map[uint64]uint64 {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	recentPeriods := make(map[uint64]uint64, len(s.RecentPeriods))
+	for term, period := range s.RecentPeriods {
+		recentPeriods[term] = period
+	}
+	return recentPeriods
+}
+
+func (s *DporSnapshot) setRecentPeriod(term uint64, period uint64) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.RecentPeriods == nil {
+		s.RecentPeriods = make(map[uint64]uint64)
+	}
+	s.RecentPeriods[term] = period
+
+	beforeTerm := uint64(math.Max(0, float64(term-MaxSizeOfRecentProposers)))
+	if _, ok := s.RecentPeriods[beforeTerm]; ok {
+		delete(s.RecentPeriods, beforeTerm)
+	}
+}
+
+// hasPeriod returns if the block period of the term is recorded
+func (s *DporSnapshot) hasPeriod(term uint64) bool {
+	_, ok := s.periodOfTerm(term)
+	return ok
+}
+
+// periodOfTerm returns the block period of the term carried in the headers,
+// false if it is not recorded
+func (s *DporSnapshot) periodOfTerm(term uint64) (uint64, bool) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	period, ok := s.RecentPeriods[term]
+	return period, ok
+}
+
+// signerOfTerm returns the key signing for the identity in the given term
+func (s *DporSnapshot) signerOfTerm(identity common.Address, term uint64) common.Address {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if signer, ok := s.RecentSigners[term][identity]; ok {
+		return signer
+	}
+	return identity
+}
+
+// identityOfTerm returns the identity a key signs for in the given term,
+// the key of an identity bound to a new signing key signs for no identity
+func (s *DporSnapshot) identityOfTerm(signer common.Address, term uint64) (common.Address, bool) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	signers := s.RecentSigners[term]
+	for identity, key := range signers {
+		if key == signer {
+			return identity, true
+		}
+	}
+	if _, rotated := signers[signer]; rotated {
+		return common.Address{}, false
+	}
+	return signer, true
+}
+
 // newSnapshot creates a new Snapshot with the specified startup parameters. This
 // method does not initialize the set of recent proposers, so only ever use if for
 // the genesis block.
@@ -201,6 +339,7 @@ func newSnapshot(config *configs.DporConfig, number uint64, hash common.Hash, pr
 		Hash:             hash,
 		RecentProposers:  make(map[uint64][]common.Address),
 		RecentValidators: make(map[uint64][]common.Address),
+		RecentSigners:    make(map[uint64]map[common.Address]common.Address),
 	}
 
 	snap.setRecentProposers(snap.Term(), proposers)
@@ -248,6 +387,7 @@ func (s *DporSnapshot) copy() *DporSnapshot {
 		Candidates:       make([]common.Address, len(s.Candidates)),
 		RecentValidators: make(map[uint64][]common.Address),
 		RecentProposers:  make(map[uint64][]common.Address),
+		RecentSigners:    make(map[uint64]map[common.Address]common.Address),
 	}
 
 	copy(cpy.Candidates, s.candidates())
@@ -257,6 +397,34 @@ func (s *DporSnapshot) copy() *DporSnapshot {
 	for term, validator := range s.recentValidators() {
 		cpy.setRecentValidators(term, validator)
 	}
+	for term, signers := range s.recentSigners() {
+		cpy.setRecentSigners(term, signers)
+	}
+	for term, period := range s.recentPeriods() {
+		cpy.setRecentPeriod(term, period)
+	}
+
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	if len(s.Offences) > 0 {
+		cpy.Offences = make(map[common.Address][]uint64, len(s.Offences))
+		for addr, offences := range s.Offences {
+			cpy.Offences[addr] = append([]uint64(nil), offences...)
+		}
+	}
+	if len(s.Barred) > 0 {
+		cpy.Barred = make(map[common.Address]uint64, len(s.Barred))
+		for addr, until := range s.Barred {
+			cpy.Barred[addr] = until
+		}
+	}
+	if len(s.Skips) > 0 {
+		cpy.Skips = make(map[common.Address][]uint64, len(s.Skips))
+		for addr, skips := range s.Skips {
+			cpy.Skips[addr] = append([]uint64(nil), skips...)
+		}
+	}
+	cpy.Notices = append([]*backend.MaintenanceNotice(nil), s.Notices...)
 	return cpy
 }
 
@@ -338,7 +506,35 @@ func (s *DporSnapshot) applyHeader(header *types.Header, ifUpdateCommittee bool,
 
 	}
 
+	// Record the signing keys of the next committee, the block period of the
+	// next term and the maintenance notices the block carries, on every node
+	// whether it runs the elections or not
+	if hasExtra(s.config, header.Number) && !header.Impeachment() {
+		extra, err := extraOf(header)
+		if err != nil {
+			log.Warn("err when decode header extra", "number", header.Number, "err", err)
+			return err
+		}
+		if extra.SignersTerm != 0 {
+			for _, binding := range extra.Signers {
+				log.Debug("signing key bound to identity", "term", extra.SignersTerm, "identity", binding.Identity.Hex(), "signer", binding.Signer.Hex())
+			}
+			s.setRecentSigners(extra.SignersTerm, extra.signers())
+		}
+		if extra.PeriodTerm != 0 {
+			log.Debug("block period of term", "term", extra.PeriodTerm, "period", extra.Period)
+			s.setRecentPeriod(extra.PeriodTerm, extra.Period)
+		}
+		s.addNotices(extra.Notices)
+	}
+
 	term := s.TermOf(header.Number.Uint64())
+
+	// If no block of the term carried the period of the next term, it keeps the period
+	if s.config.IsPeriodGoverned(header.Number) && backend.IsCheckPoint(header.Number.Uint64(), s.config.TermLen, s.config.ViewLen) && !s.hasPeriod(term+1) {
+		s.setRecentPeriod(term+1, s.PeriodOf(header.Number.Uint64()))
+	}
+
 	if len(header.Dpor.Validators) != 0 && len(header.Dpor.Validators) == int(s.config.ValidatorsLen()) {
 		// TODO: there is a vulnerability about validators in header, check it!
 		// for now, i just do not update validators from header.
@@ -347,6 +543,27 @@ func (s *DporSnapshot) applyHeader(header *types.Header, ifUpdateCommittee bool,
 		s.setRecentValidators(term+1, s.getRecentValidators(term))
 	}
 
+	// Record the offence of the proposer an impeachment block replaces, or
+	// the skip if it announced it in a maintenance notice on chain
+	if s.config.IsSlashing(header.Number) {
+		// the nodes not running the elections learn the proposers of the term
+		// from the headers, so every node records the same offences
+		if len(s.getRecentProposers(term)) == 0 && len(header.Dpor.Proposers) != 0 {
+			s.setRecentProposers(term, header.Dpor.Proposers)
+		}
+
+		switch {
+		case header.Impeachment() && s.excused(header.Number.Uint64()):
+			s.recordSkip(header.Number.Uint64())
+		case header.Impeachment():
+			s.recordImpeachment(header.Number.Uint64())
+		}
+		s.pruneNotices(header.Number.Uint64())
+		if backend.IsCheckPoint(header.Number.Uint64(), s.config.TermLen, s.config.ViewLen) {
+			s.pruneOffences(header.Number.Uint64())
+		}
+	}
+
 	return nil
 }
 
@@ -368,6 +585,9 @@ func (s *DporSnapshot) updateCandidates(candidateService rpt.CandidateService) e
 
 		log.Debug("got candidates from contract of term", "num", s.Number, "len(candidates)", len(cds), "term", term)
 
+		// Leave out the proposers barred for failing to propose
+		cds = s.unbarred(cds, s.FutureTermOf(s.Number))
+
 		// If useful, use it!
 		if uint64(len(cds)) >= s.config.TermLen {
 			candidates = cds
@@ -497,6 +717,7 @@ func (s *DporSnapshot) updateProposers(rpts rpt.RptList, seed int64) {
 		// save to cache
 		term := s.FutureTermOf(s.number())
 		s.setRecentProposers(term, proposers)
+		s.addElectionRpts(&TermRpts{Term: term, Number: s.number(), Rpts: rpts})
 
 		// some logs about elected proposers
 		log.Debug("---------------------------")
@@ -525,6 +746,23 @@ func (s *DporSnapshot) updateProposers(rpts rpt.RptList, seed int64) {
 	return
 }
 
+func (s *DporSnapshot) addElectionRpts(rpts *TermRpts) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.electionRpts = append(s.electionRpts, rpts)
+}
+
+// takeElectionRpts returns the rpts of the elections run since the last call.
+func (s *DporSnapshot) takeElectionRpts() []*TermRpts {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	rpts := s.electionRpts
+	s.electionRpts = nil
+	return rpts
+}
+
 // Term returns the term index of current block number, which is 0-based
 func (s *DporSnapshot) Term() uint64 {
 	return s.TermOf(s.number())
@@ -586,6 +824,18 @@ func (s *DporSnapshot) IsProposerOf(signer common.Address, number uint64) (bool,
 	return false, errProposerNotInCommittee
 }
 
+// SignerOf returns the key signing for an identity in the given block number,
+// the identity itself unless a new signing key is bound to it
+func (s *DporSnapshot) SignerOf(identity common.Address, number uint64) common.Address {
+	return s.signerOfTerm(identity, s.TermOf(number))
+}
+
+// IdentityOf returns the identity a key signs for in the given block number,
+// false if the key is the one of an identity bound to a new signing key
+func (s *DporSnapshot) IdentityOf(signer common.Address, number uint64) (common.Address, bool) {
+	return s.identityOfTerm(signer, s.TermOf(number))
+}
+
 // FutureValidatorsOf returns future validators of given block number
 func (s *DporSnapshot) FutureValidatorsOf(number uint64) []common.Address {
 	return s.getRecentValidators(s.FutureTermOf(number))
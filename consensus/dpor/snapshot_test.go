@@ -3,6 +3,7 @@ package dpor
 import (
 	"encoding/json"
 	"fmt"
+	"math/big"
 	"reflect"
 	"testing"
 
@@ -521,6 +522,285 @@ func TestSnapshot_isNotLeader(t *testing.T) {
 	}
 }
 
+// fakeSignerService serves the signing keys registered at block number
+type fakeSignerService struct {
+	number  uint64
+	signers map[common.Address]common.Address
+}
+
+func (f fakeSignerService) SignersOf(term uint64, identities []common.Address, number uint64) (map[common.Address]common.Address, error) {
+	if number != f.number {
+		return nil, fmt.Errorf("signer register read at block %d, want %d", number, f.number)
+	}
+	signers := make(map[common.Address]common.Address)
+	for _, identity := range identities {
+		if signer, ok := f.signers[identity]; ok {
+			signers[identity] = signer
+		}
+	}
+	return signers, nil
+}
+
+// newSignersHeader returns a proposed block number carrying the signing keys of term
+func newSignersHeader(t *testing.T, number uint64, term uint64, signers map[common.Address]common.Address) *types.Header {
+	header := &types.Header{Number: new(big.Int).SetUint64(number), Coinbase: common.Address{0x01}, Extra: make([]byte, extraVanity)}
+	if err := setExtra(header, &headerExtra{SignersTerm: term, Signers: newSignerBindings(signers)}); err != nil {
+		t.Fatal(err)
+	}
+	return header
+}
+
+func TestSnapshot_signerOf(t *testing.T) {
+	snap := createSnapshot()
+	snap.config.SignerRotationBlock = big.NewInt(1)
+	rotated := common.HexToAddress("0x4444444444444444444444444444444444444444")
+
+	// the first proposed block of term 0 carries the keys of term 1
+	header := newSignersHeader(t, 2, 1, map[common.Address]common.Address{addr1: rotated})
+	if err := snap.verifyExtra(header); err != nil {
+		t.Fatal(err)
+	}
+	if err := snap.applyHeader(header, false, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	number := snap.StartBlockNumberOfTerm(1) + 1
+
+	if signer := snap.SignerOf(addr1, number); signer != rotated {
+		t.Errorf("expect signer %v, get %v", rotated.Hex(), signer.Hex())
+	}
+	if signer := snap.SignerOf(addr1, 1); signer != addr1 {
+		t.Errorf("expect signer %v before rotation, get %v", addr1.Hex(), signer.Hex())
+	}
+	if identity, ok := snap.IdentityOf(rotated, number); !ok || identity != addr1 {
+		t.Errorf("expect identity %v, get %v", addr1.Hex(), identity.Hex())
+	}
+	if _, ok := snap.IdentityOf(addr1, number); ok {
+		t.Errorf("expect key of rotated identity to sign for no identity")
+	}
+	if identity, ok := snap.IdentityOf(addr2, number); !ok || identity != addr2 {
+		t.Errorf("expect identity %v, get %v", addr2.Hex(), identity.Hex())
+	}
+
+	// signing keys survive copies and stores
+	blob, err := json.Marshal(snap.copy())
+	if err != nil {
+		t.Fatal(err)
+	}
+	loaded := new(DporSnapshot)
+	if err := json.Unmarshal(blob, loaded); err != nil {
+		t.Fatal(err)
+	}
+	loaded.config = snap.config
+	if signer := loaded.SignerOf(addr1, number); signer != rotated {
+		t.Errorf("expect signer %v after reload, get %v", rotated.Hex(), signer.Hex())
+	}
+}
+
+func TestSnapshot_verifyExtra(t *testing.T) {
+	snap := createSnapshot()
+	snap.config.SignerRotationBlock = big.NewInt(3)
+	rotated := common.HexToAddress("0x4444444444444444444444444444444444444444")
+	keys := map[common.Address]common.Address{addr1: rotated}
+
+	// before the fork no block carries keys
+	if err := snap.verifyExtra(newSignersHeader(t, 2, 1, keys)); err != errInvalidCarriedSigners {
+		t.Errorf("keys carried before the fork, err %v", err)
+	}
+
+	// the first proposed block from the fork on carries the keys of the next
+	// term, even if they are none
+	if err := snap.verifyExtra(newSignersHeader(t, 3, 0, nil)); err != errInvalidCarriedSigners {
+		t.Errorf("keys not carried when due, err %v", err)
+	}
+	if err := snap.verifyExtra(newSignersHeader(t, 3, 2, keys)); err != errInvalidCarriedSigners {
+		t.Errorf("keys of another term carried, err %v", err)
+	}
+	if err := snap.verifyExtra(newSignersHeader(t, 3, 1, nil)); err != nil {
+		t.Errorf("no keys bound, err %v", err)
+	}
+
+	// a key signs for one identity
+	twice := newSignersHeader(t, 3, 1, map[common.Address]common.Address{addr1: rotated, addr2: rotated})
+	if err := snap.verifyExtra(twice); err != errInvalidCarriedSigners {
+		t.Errorf("key bound to two identities, err %v", err)
+	}
+
+	// the data of a block carrying none is left out
+	empty := &types.Header{Number: big.NewInt(4), Coinbase: common.Address{0x01}, Extra: make([]byte, extraVanity)}
+	empty.Extra = append(empty.Extra, 0xc2, 0x80, 0xc0)
+	if err := snap.verifyExtra(empty); err != errInvalidExtra {
+		t.Errorf("empty data carried, err %v", err)
+	}
+
+	header := newSignersHeader(t, 3, 1, keys)
+	if err := snap.applyHeader(header, false, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := snap.verifyExtra(newSignersHeader(t, 4, 1, keys)); err != errInvalidCarriedSigners {
+		t.Errorf("keys carried twice, err %v", err)
+	}
+	if err := snap.verifyExtra(newSignersHeader(t, 4, 0, nil)); err != nil {
+		t.Errorf("keys carried once, err %v", err)
+	}
+
+	// the first proposed block of the next term carries the keys of the one after
+	if err := snap.verifyExtra(newSignersHeader(t, snap.StartBlockNumberOfTerm(1)+1, 2, nil)); err != nil {
+		t.Errorf("keys of term 2, err %v", err)
+	}
+}
+
+func TestDpor_verifyCarriedSigners(t *testing.T) {
+	snap := createSnapshot()
+	snap.config.SignerRotationBlock = big.NewInt(1)
+	snap.setRecentProposers(1, getProposerAddress())
+	rotated := common.HexToAddress("0x4444444444444444444444444444444444444444")
+	keys := map[common.Address]common.Address{addr1: rotated}
+
+	d := &Dpor{config: snap.config, signerBackend: fakeSignerService{number: 1, signers: keys}}
+	if err := d.verifyCarriedSigners(snap, newSignersHeader(t, 2, 1, keys)); err != nil {
+		t.Errorf("registered keys, err %v", err)
+	}
+	if err := d.verifyCarriedSigners(snap, newSignersHeader(t, 2, 1, nil)); err != errInvalidCarriedSigners {
+		t.Errorf("registered keys left out, err %v", err)
+	}
+	other := map[common.Address]common.Address{addr2: rotated}
+	if err := d.verifyCarriedSigners(snap, newSignersHeader(t, 2, 1, other)); err != errInvalidCarriedSigners {
+		t.Errorf("keys not registered, err %v", err)
+	}
+}
+
+// newPeriodHeader returns a proposed block number carrying the block period of term
+func newPeriodHeader(t *testing.T, number uint64, term uint64, period uint64) *types.Header {
+	header := &types.Header{Number: new(big.Int).SetUint64(number), Coinbase: common.Address{0x01}, Extra: make([]byte, extraVanity)}
+	if err := setExtra(header, &headerExtra{PeriodTerm: term, Period: period}); err != nil {
+		t.Fatal(err)
+	}
+	return header
+}
+
+func TestSnapshot_periodOf(t *testing.T) {
+	snap := createSnapshot()
+	snap.config.PeriodGovernorBlock = big.NewInt(1)
+
+	// the periods of the terms not recorded fall back to the config
+	if period := snap.PeriodOf(snap.StartBlockNumberOfTerm(1) + 1); period != 3 {
+		t.Fatalf("period %d before recording, want 3 of the config", period)
+	}
+
+	// the first proposed block of term 0 carries the period of term 1
+	header := newPeriodHeader(t, 2, 1, 500)
+	if err := snap.verifyExtra(header); err != nil {
+		t.Fatal(err)
+	}
+	if err := snap.applyHeader(header, false, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	for number, want := range map[uint64]uint64{1: 3, 9: 3, 10: 500, 18: 500} {
+		if period := snap.PeriodOf(number); period != want {
+			t.Errorf("period of block %d %d, want %d", number, period, want)
+		}
+	}
+
+	// blocks 8 and 9 of term 0 and 10 to 12 of term 1
+	if sum := snap.periodsBetween(7, 12); sum != 2*3+3*500 {
+		t.Errorf("periods between blocks 7 and 12 %d, want %d", sum, 2*3+3*500)
+	}
+
+	// if no block of term 1 carries the period of term 2, it keeps the one of term 1
+	for number := uint64(3); number <= snap.StartBlockNumberOfTerm(2); number++ {
+		header := &types.Header{Number: new(big.Int).SetUint64(number), Coinbase: common.Address{0x01}, Extra: make([]byte, extraVanity)}
+		if err := snap.applyHeader(header, false, nil, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if period := snap.PeriodOf(snap.StartBlockNumberOfTerm(2) + 1); period != 500 {
+		t.Errorf("period of term 2 %d, want 500 of term 1", period)
+	}
+
+	// periods survive copies and stores
+	blob, err := json.Marshal(snap.copy())
+	if err != nil {
+		t.Fatal(err)
+	}
+	loaded := new(DporSnapshot)
+	if err := json.Unmarshal(blob, loaded); err != nil {
+		t.Fatal(err)
+	}
+	loaded.config = snap.config
+	if period := loaded.PeriodOf(snap.StartBlockNumberOfTerm(1) + 1); period != 500 {
+		t.Errorf("period %d after reload, want 500", period)
+	}
+}
+
+func TestSnapshot_verifyExtraPeriod(t *testing.T) {
+	snap := createSnapshot()
+	snap.config.PeriodGovernorBlock = big.NewInt(3)
+
+	// before the fork no block carries a period
+	if err := snap.verifyExtra(newPeriodHeader(t, 2, 1, 500)); err != errInvalidCarriedPeriod {
+		t.Errorf("period carried before the fork, err %v", err)
+	}
+
+	// the first proposed block from the fork on carries the period of the next term
+	if err := snap.verifyExtra(newPeriodHeader(t, 3, 0, 0)); err != errInvalidCarriedPeriod {
+		t.Errorf("period not carried when due, err %v", err)
+	}
+	if err := snap.verifyExtra(newPeriodHeader(t, 3, 2, 500)); err != errInvalidCarriedPeriod {
+		t.Errorf("period of another term carried, err %v", err)
+	}
+	if err := snap.verifyExtra(newPeriodHeader(t, 3, 1, 0)); err != errInvalidCarriedPeriod {
+		t.Errorf("zero period carried, err %v", err)
+	}
+
+	header := newPeriodHeader(t, 3, 1, 500)
+	if err := snap.verifyExtra(header); err != nil {
+		t.Fatal(err)
+	}
+	if err := snap.applyHeader(header, false, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := snap.verifyExtra(newPeriodHeader(t, 4, 1, 500)); err != errInvalidCarriedPeriod {
+		t.Errorf("period carried twice, err %v", err)
+	}
+}
+
+// fakePeriodService serves the block periods scheduled at block number
+type fakePeriodService struct {
+	number  uint64
+	periods map[uint64]uint64
+}
+
+func (f fakePeriodService) PeriodOf(term uint64, number uint64) (uint64, error) {
+	if number != f.number {
+		return 0, fmt.Errorf("period governor read at block %d, want %d", number, f.number)
+	}
+	return f.periods[term], nil
+}
+
+func TestDpor_verifyCarriedPeriod(t *testing.T) {
+	snap := createSnapshot()
+	snap.config.PeriodGovernorBlock = big.NewInt(1)
+
+	d := &Dpor{config: snap.config, periodBackend: fakePeriodService{number: 1, periods: map[uint64]uint64{1: 500}}}
+	if err := d.verifyCarriedPeriod(snap, newPeriodHeader(t, 2, 1, 500)); err != nil {
+		t.Errorf("scheduled period, err %v", err)
+	}
+	if err := d.verifyCarriedPeriod(snap, newPeriodHeader(t, 2, 1, 3)); err != errInvalidCarriedPeriod {
+		t.Errorf("period not scheduled, err %v", err)
+	}
+
+	// the period of the current term is carried over if none is scheduled
+	d.periodBackend = fakePeriodService{number: 1}
+	if err := d.verifyCarriedPeriod(snap, newPeriodHeader(t, 2, 1, 3)); err != nil {
+		t.Errorf("period carried over, err %v", err)
+	}
+
+	// a failed read fails the verification
+	if err := d.verifyCarriedPeriod(snap, newPeriodHeader(t, 3, 1, 3)); err == nil {
+		t.Error("expect error reading the governor at another block")
+	}
+}
+
 func createSnapshot() *DporSnapshot {
 	proposers := getProposerAddress()
 	validators := getValidatorAddress()
@@ -47,3 +47,7 @@ package contracts
 //go:generate abigen --sol ./dpor/contracts/campaign3/campaign3.sol --pkg campaign --out ./dpor/contracts/campaign3/campaign3.go
 
 
+
+//go:generate abigen --sol ./dpor/multisig/multisig.sol --pkg multisig --type MultiSig --out ./dpor/multisig/multisig.go
+
+//go:generate abigen --sol ./dpor/signer_register/signer_register.sol --pkg signer_register --type SignerRegister --out ./dpor/signer_register/signer_register.go
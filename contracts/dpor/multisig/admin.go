@@ -0,0 +1,205 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package multisig
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"bitbucket.org/cpchain/chain/accounts/abi"
+	"bitbucket.org/cpchain/chain/accounts/abi/bind"
+	campaign "bitbucket.org/cpchain/chain/contracts/dpor/campaign3"
+	"bitbucket.org/cpchain/chain/contracts/dpor/reward"
+	"bitbucket.org/cpchain/chain/contracts/dpor/rnode"
+	contract "bitbucket.org/cpchain/chain/contracts/proxy/proxy_contract"
+	"bitbucket.org/cpchain/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var ErrNoSubmission = errors.New("no multisig submission in receipt")
+
+// Call is a transaction proposed to the multisig wallet, a zero destination
+// deploys a contract owned by the wallet.
+type Call struct {
+	Destination common.Address
+	Value       *big.Int
+	Data        []byte
+}
+
+// NewCall packs the call of method on the contract with the given ABI.
+func NewCall(contractABI string, destination common.Address, method string, args ...interface{}) (Call, error) {
+	parsed, err := abi.JSON(strings.NewReader(contractABI))
+	if err != nil {
+		return Call{}, err
+	}
+	data, err := parsed.Pack(method, args...)
+	if err != nil {
+		return Call{}, err
+	}
+	return Call{Destination: destination, Value: new(big.Int), Data: data}, nil
+}
+
+// StartNewRound calls startNewRound of the reward contract.
+func StartNewRound(rewardAddr common.Address) (Call, error) {
+	return NewCall(reward.RewardABI, rewardAddr, "startNewRound")
+}
+
+// NewRaise calls newRaise of the reward contract.
+func NewRaise(rewardAddr common.Address) (Call, error) {
+	return NewCall(reward.RewardABI, rewardAddr, "newRaise")
+}
+
+// SetRewardPeriod calls setPeriod of the reward contract.
+func SetRewardPeriod(rewardAddr common.Address, period *big.Int) (Call, error) {
+	return NewCall(reward.RewardABI, rewardAddr, "setPeriod", period)
+}
+
+// SetRnodePeriod calls setPeriod of the rnode contract.
+func SetRnodePeriod(rnodeAddr common.Address, period *big.Int) (Call, error) {
+	return NewCall(rnode.RnodeABI, rnodeAddr, "setPeriod", period)
+}
+
+// RegisterProxyContract calls registerProxyContract of the proxy register.
+func RegisterProxyContract(register common.Address, proxy common.Address, real common.Address) (Call, error) {
+	return NewCall(contract.ProxyContractRegisterABI, register, "registerProxyContract", proxy, real)
+}
+
+// knownABIs are the contracts administered by the wallet, used to describe
+// the proposed calls.
+var knownABIs = []string{
+	reward.RewardABI,
+	rnode.RnodeABI,
+	campaign.CampaignABI,
+	contract.ProxyContractRegisterABI,
+	MultiSigABI,
+}
+
+// Describe returns the signature of the method called by data, or the
+// selector if it is unknown.
+func Describe(data []byte) string {
+	if len(data) == 0 {
+		return "transfer"
+	}
+	if len(data) < 4 {
+		return fmt.Sprintf("%#x", data)
+	}
+	for _, known := range knownABIs {
+		parsed, err := abi.JSON(strings.NewReader(known))
+		if err != nil {
+			continue
+		}
+		for _, method := range parsed.Methods {
+			if bytes.Equal(method.Id(), data[:4]) {
+				return method.Sig()
+			}
+		}
+	}
+	return fmt.Sprintf("%#x", data[:4])
+}
+
+// Proposal is a transaction of the multisig wallet with its confirmations.
+type Proposal struct {
+	ID            *big.Int
+	Destination   common.Address
+	Value         *big.Int
+	Data          []byte
+	Method        string
+	Executed      bool
+	Confirmations []common.Address
+	Required      *big.Int
+}
+
+// Confirmed reports whether enough owners confirmed the proposal to execute it.
+func (p *Proposal) Confirmed() bool {
+	return int64(len(p.Confirmations)) >= p.Required.Int64()
+}
+
+// Propose submits call to the wallet, which confirms it by the sender.
+func Propose(wallet *MultiSig, opts *bind.TransactOpts, call Call) (*types.Transaction, error) {
+	value := call.Value
+	if value == nil {
+		value = new(big.Int)
+	}
+	return wallet.SubmitTransaction(opts, call.Destination, value, call.Data)
+}
+
+// GetProposal reads the proposal with the given id from the wallet.
+func GetProposal(wallet *MultiSigCaller, opts *bind.CallOpts, id *big.Int) (*Proposal, error) {
+	count, err := wallet.TransactionCount(opts)
+	if err != nil {
+		return nil, err
+	}
+	if id.Sign() < 0 || id.Cmp(count) >= 0 {
+		return nil, fmt.Errorf("unknown proposal %v, %v proposals submitted", id, count)
+	}
+	txn, err := wallet.Transactions(opts, id)
+	if err != nil {
+		return nil, err
+	}
+	confirmations, err := wallet.GetConfirmations(opts, id)
+	if err != nil {
+		return nil, err
+	}
+	required, err := wallet.Required(opts)
+	if err != nil {
+		return nil, err
+	}
+	return &Proposal{
+		ID:            id,
+		Destination:   txn.Destination,
+		Value:         txn.Value,
+		Data:          txn.Data,
+		Method:        Describe(txn.Data),
+		Executed:      txn.Executed,
+		Confirmations: confirmations,
+		Required:      required,
+	}, nil
+}
+
+// ProposalID returns the id of the proposal submitted by the transaction of
+// the receipt.
+func ProposalID(wallet common.Address, receipt *types.Receipt) (*big.Int, error) {
+	parsed, err := abi.JSON(strings.NewReader(MultiSigABI))
+	if err != nil {
+		return nil, err
+	}
+	topic := parsed.Events["Submission"].Id()
+	for _, log := range receipt.Logs {
+		if log.Address == wallet && len(log.Topics) == 2 && log.Topics[0] == topic {
+			return log.Topics[1].Big(), nil
+		}
+	}
+	return nil, ErrNoSubmission
+}
+
+// Executed reports whether the transaction of the receipt executed the proposal.
+func Executed(wallet common.Address, receipt *types.Receipt, id *big.Int) bool {
+	parsed, err := abi.JSON(strings.NewReader(MultiSigABI))
+	if err != nil {
+		return false
+	}
+	topic := parsed.Events["Execution"].Id()
+	for _, log := range receipt.Logs {
+		if log.Address == wallet && len(log.Topics) == 2 && log.Topics[0] == topic && log.Topics[1].Big().Cmp(id) == 0 {
+			return true
+		}
+	}
+	return false
+}
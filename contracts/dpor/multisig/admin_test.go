@@ -0,0 +1,152 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package multisig
+
+import (
+	"bytes"
+	"context"
+	"math/big"
+	"strings"
+	"testing"
+
+	cpchain "bitbucket.org/cpchain/chain"
+	"bitbucket.org/cpchain/chain/accounts/abi"
+	"bitbucket.org/cpchain/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var (
+	walletAddr = common.HexToAddress("0x1000000000000000000000000000000000000001")
+	rewardAddr = common.HexToAddress("0x94576e35a55D6BbF9bB45120bC835a668557eF42")
+	owners     = []common.Address{{1}, {2}, {3}}
+)
+
+func TestCalls(t *testing.T) {
+	selector := func(sig string) []byte { return crypto.Keccak256([]byte(sig))[:4] }
+
+	tests := []struct {
+		call func() (Call, error)
+		sig  string
+		size int
+	}{
+		{func() (Call, error) { return StartNewRound(rewardAddr) }, "startNewRound()", 4},
+		{func() (Call, error) { return NewRaise(rewardAddr) }, "newRaise()", 4},
+		{func() (Call, error) { return SetRewardPeriod(rewardAddr, big.NewInt(60)) }, "setPeriod(uint256)", 36},
+		{func() (Call, error) { return SetRnodePeriod(rewardAddr, big.NewInt(60)) }, "setPeriod(uint256)", 36},
+		{func() (Call, error) { return RegisterProxyContract(rewardAddr, owners[0], owners[1]) }, "registerProxyContract(address,address)", 68},
+	}
+	for _, test := range tests {
+		call, err := test.call()
+		if err != nil {
+			t.Fatalf("%s: %v", test.sig, err)
+		}
+		if call.Destination != rewardAddr || call.Value.Sign() != 0 {
+			t.Errorf("%s: destination or value mismatch: %x %v", test.sig, call.Destination, call.Value)
+		}
+		if !bytes.Equal(call.Data[:4], selector(test.sig)) || len(call.Data) != test.size {
+			t.Errorf("%s: data mismatch: %x", test.sig, call.Data)
+		}
+		if have := Describe(call.Data); have != test.sig {
+			t.Errorf("description mismatch: have %s, want %s", have, test.sig)
+		}
+	}
+	if _, err := NewCall(MultiSigABI, walletAddr, "unknown"); err == nil {
+		t.Error("packed an unknown method")
+	}
+	if have := Describe(nil); have != "transfer" {
+		t.Errorf("description mismatch: have %s, want transfer", have)
+	}
+	if have := Describe([]byte{1, 2, 3, 4, 5}); have != "0x01020304" {
+		t.Errorf("description mismatch: have %s, want 0x01020304", have)
+	}
+}
+
+func TestProposalID(t *testing.T) {
+	parsed, _ := abi.JSON(strings.NewReader(MultiSigABI))
+	id := common.BigToHash(big.NewInt(7))
+	receipt := &types.Receipt{Logs: []*types.Log{
+		{Address: owners[0], Topics: []common.Hash{parsed.Events["Submission"].Id(), common.BigToHash(big.NewInt(3))}},
+		{Address: walletAddr, Topics: []common.Hash{parsed.Events["Submission"].Id(), id}},
+		{Address: walletAddr, Topics: []common.Hash{parsed.Events["Confirmation"].Id(), owners[0].Hash(), id}},
+	}}
+	have, err := ProposalID(walletAddr, receipt)
+	if err != nil || have.Int64() != 7 {
+		t.Fatalf("id mismatch: have %v (%v), want 7", have, err)
+	}
+	if Executed(walletAddr, receipt, have) {
+		t.Error("proposal executed without execution event")
+	}
+	receipt.Logs = append(receipt.Logs, &types.Log{Address: walletAddr, Topics: []common.Hash{parsed.Events["Execution"].Id(), id}})
+	if !Executed(walletAddr, receipt, have) {
+		t.Error("execution event not found")
+	}
+	if _, err := ProposalID(walletAddr, &types.Receipt{}); err != ErrNoSubmission {
+		t.Errorf("error mismatch: have %v, want %v", err, ErrNoSubmission)
+	}
+}
+
+// testWallet answers the view calls of a wallet with one proposal.
+type testWallet struct {
+	abi  abi.ABI
+	call Call
+}
+
+func (w *testWallet) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	return []byte{1}, nil
+}
+
+func (w *testWallet) CallContract(ctx context.Context, call cpchain.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	for name, method := range w.abi.Methods {
+		if !bytes.Equal(call.Data[:4], method.Id()) {
+			continue
+		}
+		switch name {
+		case "transactionCount":
+			return method.Outputs.Pack(big.NewInt(1))
+		case "transactions":
+			return method.Outputs.Pack(w.call.Destination, w.call.Value, w.call.Data, false)
+		case "getConfirmations":
+			return method.Outputs.Pack(owners[:1])
+		case "required":
+			return method.Outputs.Pack(big.NewInt(2))
+		}
+	}
+	return nil, nil
+}
+
+func TestGetProposal(t *testing.T) {
+	parsed, _ := abi.JSON(strings.NewReader(MultiSigABI))
+	call, _ := SetRewardPeriod(rewardAddr, big.NewInt(60))
+	wallet, err := NewMultiSigCaller(walletAddr, &testWallet{abi: parsed, call: call})
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := GetProposal(wallet, nil, big.NewInt(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Destination != rewardAddr || !bytes.Equal(p.Data, call.Data) || p.Method != "setPeriod(uint256)" || p.Executed {
+		t.Errorf("proposal mismatch: %+v", p)
+	}
+	if len(p.Confirmations) != 1 || p.Confirmations[0] != owners[0] || p.Confirmed() {
+		t.Errorf("confirmations mismatch: have %v, required %v", p.Confirmations, p.Required)
+	}
+	if _, err := GetProposal(wallet, nil, big.NewInt(1)); err == nil {
+		t.Error("read an unknown proposal")
+	}
+}
@@ -0,0 +1,2375 @@
+// Code generated - DO NOT EDIT.
+// This file is a generated binding and any manual changes will be lost.
+
+package multisig
+
+import (
+	"math/big"
+	"strings"
+
+	cpchain "bitbucket.org/cpchain/chain"
+	"bitbucket.org/cpchain/chain/accounts/abi"
+	"bitbucket.org/cpchain/chain/accounts/abi/bind"
+	"bitbucket.org/cpchain/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+// MultiSigABI is the input ABI used to generate the binding from.
+const MultiSigABI = "[{\"constant\":true,\"inputs\":[],\"name\":\"MAX_OWNER_COUNT\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"name\":\"transactions\",\"outputs\":[{\"name\":\"destination\",\"type\":\"address\"},{\"name\":\"value\",\"type\":\"uint256\"},{\"name\":\"data\",\"type\":\"bytes\"},{\"name\":\"executed\",\"type\":\"bool\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[{\"name\":\"\",\"type\":\"uint256\"},{\"name\":\"\",\"type\":\"address\"}],\"name\":\"confirmations\",\"outputs\":[{\"name\":\"\",\"type\":\"bool\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[{\"name\":\"\",\"type\":\"address\"}],\"name\":\"isOwner\",\"outputs\":[{\"name\":\"\",\"type\":\"bool\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"name\":\"owners\",\"outputs\":[{\"name\":\"\",\"type\":\"address\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[],\"name\":\"required\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[],\"name\":\"transactionCount\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"owner\",\"type\":\"address\"}],\"name\":\"addOwner\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"owner\",\"type\":\"address\"}],\"name\":\"removeOwner\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"_required\",\"type\":\"uint256\"}],\"name\":\"changeRequirement\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"destination\",\"type\":\"address\"},{\"name\":\"value\",\"type\":\"uint256\"},{\"name\":\"data\",\"type\":\"bytes\"}],\"name\":\"submitTransaction\",\"outputs\":[{\"name\":\"transactionId\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"transactionId\",\"type\":\"uint256\"}],\"name\":\"confirmTransaction\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"transactionId\",\"type\":\"uint256\"}],\"name\":\"revokeConfirmation\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"transactionId\",\"type\":\"uint256\"}],\"name\":\"executeTransaction\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[{\"name\":\"transactionId\",\"type\":\"uint256\"}],\"name\":\"isConfirmed\",\"outputs\":[{\"name\":\"\",\"type\":\"bool\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[{\"name\":\"transactionId\",\"type\":\"uint256\"}],\"name\":\"getConfirmationCount\",\"outputs\":[{\"name\":\"count\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[{\"name\":\"transactionId\",\"type\":\"uint256\"}],\"name\":\"getConfirmations\",\"outputs\":[{\"name\":\"_confirmations\",\"type\":\"address[]\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[],\"name\":\"getOwners\",\"outputs\":[{\"name\":\"\",\"type\":\"address[]\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"name\":\"_owners\",\"type\":\"address[]\"},{\"name\":\"_required\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"constructor\"},{\"payable\":true,\"stateMutability\":\"payable\",\"type\":\"fallback\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"name\":\"sender\",\"type\":\"address\"},{\"indexed\":true,\"name\":\"transactionId\",\"type\":\"uint256\"}],\"name\":\"Confirmation\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"name\":\"sender\",\"type\":\"address\"},{\"indexed\":true,\"name\":\"transactionId\",\"type\":\"uint256\"}],\"name\":\"Revocation\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"name\":\"transactionId\",\"type\":\"uint256\"}],\"name\":\"Submission\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"name\":\"transactionId\",\"type\":\"uint256\"}],\"name\":\"Execution\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"name\":\"transactionId\",\"type\":\"uint256\"}],\"name\":\"ExecutionFailure\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"name\":\"transactionId\",\"type\":\"uint256\"},{\"indexed\":false,\"name\":\"contractAddress\",\"type\":\"address\"}],\"name\":\"Creation\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"name\":\"sender\",\"type\":\"address\"},{\"indexed\":false,\"name\":\"value\",\"type\":\"uint256\"}],\"name\":\"Deposit\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"name\":\"owner\",\"type\":\"address\"}],\"name\":\"OwnerAddition\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"name\":\"owner\",\"type\":\"address\"}],\"name\":\"OwnerRemoval\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":false,\"name\":\"required\",\"type\":\"uint256\"}],\"name\":\"RequirementChange\",\"type\":\"event\"}]"
+
+// MultiSig is an auto generated Go binding around an cpchain contract.
+type MultiSig struct {
+	MultiSigCaller     // Read-only binding to the contract
+	MultiSigTransactor // Write-only binding to the contract
+	MultiSigFilterer   // Log filterer for contract events
+}
+
+// MultiSigCaller is an auto generated read-only Go binding around an cpchain contract.
+type MultiSigCaller struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// MultiSigTransactor is an auto generated write-only Go binding around an cpchain contract.
+type MultiSigTransactor struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// MultiSigFilterer is an auto generated log filtering Go binding around an cpchain contract events.
+type MultiSigFilterer struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// MultiSigSession is an auto generated Go binding around an cpchain contract,
+// with pre-set call and transact options.
+type MultiSigSession struct {
+	Contract     *MultiSig         // Generic contract binding to set the session for
+	CallOpts     bind.CallOpts     // Call options to use throughout this session
+	TransactOpts bind.TransactOpts // Transaction auth options to use throughout this session
+}
+
+// MultiSigCallerSession is an auto generated read-only Go binding around an cpchain contract,
+// with pre-set call options.
+type MultiSigCallerSession struct {
+	Contract *MultiSigCaller // Generic contract caller binding to set the session for
+	CallOpts bind.CallOpts   // Call options to use throughout this session
+}
+
+// MultiSigTransactorSession is an auto generated write-only Go binding around an cpchain contract,
+// with pre-set transact options.
+type MultiSigTransactorSession struct {
+	Contract     *MultiSigTransactor // Generic contract transactor binding to set the session for
+	TransactOpts bind.TransactOpts   // Transaction auth options to use throughout this session
+}
+
+// MultiSigRaw is an auto generated low-level Go binding around an cpchain contract.
+type MultiSigRaw struct {
+	Contract *MultiSig // Generic contract binding to access the raw methods on
+}
+
+// MultiSigCallerRaw is an auto generated low-level read-only Go binding around an cpchain contract.
+type MultiSigCallerRaw struct {
+	Contract *MultiSigCaller // Generic read-only contract binding to access the raw methods on
+}
+
+// MultiSigTransactorRaw is an auto generated low-level write-only Go binding around an cpchain contract.
+type MultiSigTransactorRaw struct {
+	Contract *MultiSigTransactor // Generic write-only contract binding to access the raw methods on
+}
+
+// NewMultiSig creates a new instance of MultiSig, bound to a specific deployed contract.
+func NewMultiSig(address common.Address, backend bind.ContractBackend) (*MultiSig, error) {
+	contract, err := bindMultiSig(address, backend, backend, backend)
+	if err != nil {
+		return nil, err
+	}
+	return &MultiSig{MultiSigCaller: MultiSigCaller{contract: contract}, MultiSigTransactor: MultiSigTransactor{contract: contract}, MultiSigFilterer: MultiSigFilterer{contract: contract}}, nil
+}
+
+// NewMultiSigCaller creates a new read-only instance of MultiSig, bound to a specific deployed contract.
+func NewMultiSigCaller(address common.Address, caller bind.ContractCaller) (*MultiSigCaller, error) {
+	contract, err := bindMultiSig(address, caller, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &MultiSigCaller{contract: contract}, nil
+}
+
+// NewMultiSigTransactor creates a new write-only instance of MultiSig, bound to a specific deployed contract.
+func NewMultiSigTransactor(address common.Address, transactor bind.ContractTransactor) (*MultiSigTransactor, error) {
+	contract, err := bindMultiSig(address, nil, transactor, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &MultiSigTransactor{contract: contract}, nil
+}
+
+// NewMultiSigFilterer creates a new log filterer instance of MultiSig, bound to a specific deployed contract.
+func NewMultiSigFilterer(address common.Address, filterer bind.ContractFilterer) (*MultiSigFilterer, error) {
+	contract, err := bindMultiSig(address, nil, nil, filterer)
+	if err != nil {
+		return nil, err
+	}
+	return &MultiSigFilterer{contract: contract}, nil
+}
+
+// bindMultiSig binds a generic wrapper to an already deployed contract.
+func bindMultiSig(address common.Address, caller bind.ContractCaller, transactor bind.ContractTransactor, filterer bind.ContractFilterer) (*bind.BoundContract, error) {
+	parsed, err := abi.JSON(strings.NewReader(MultiSigABI))
+	if err != nil {
+		return nil, err
+	}
+	return bind.NewBoundContract(address, parsed, caller, transactor, filterer), nil
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_MultiSig *MultiSigRaw) Call(opts *bind.CallOpts, result interface{}, method string, params ...interface{}) error {
+	return _MultiSig.Contract.MultiSigCaller.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_MultiSig *MultiSigRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _MultiSig.Contract.MultiSigTransactor.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_MultiSig *MultiSigRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _MultiSig.Contract.MultiSigTransactor.contract.Transact(opts, method, params...)
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_MultiSig *MultiSigCallerRaw) Call(opts *bind.CallOpts, result interface{}, method string, params ...interface{}) error {
+	return _MultiSig.Contract.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_MultiSig *MultiSigTransactorRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _MultiSig.Contract.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_MultiSig *MultiSigTransactorRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _MultiSig.Contract.contract.Transact(opts, method, params...)
+}
+
+// MAXOWNERCOUNT is a free data retrieval call binding the contract method 0xd74f8edd.
+//
+// Solidity: function MAX_OWNER_COUNT() constant returns(uint256)
+func (_MultiSig *MultiSigCaller) MAXOWNERCOUNT(opts *bind.CallOpts) (*big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _MultiSig.contract.Call(opts, out, "MAX_OWNER_COUNT")
+	return *ret0, err
+}
+
+// MAXOWNERCOUNT is a free data retrieval call binding the contract method 0xd74f8edd.
+//
+// Solidity: function MAX_OWNER_COUNT() constant returns(uint256)
+func (_MultiSig *MultiSigSession) MAXOWNERCOUNT() (*big.Int, error) {
+	return _MultiSig.Contract.MAXOWNERCOUNT(&_MultiSig.CallOpts)
+}
+
+// MAXOWNERCOUNT is a free data retrieval call binding the contract method 0xd74f8edd.
+//
+// Solidity: function MAX_OWNER_COUNT() constant returns(uint256)
+func (_MultiSig *MultiSigCallerSession) MAXOWNERCOUNT() (*big.Int, error) {
+	return _MultiSig.Contract.MAXOWNERCOUNT(&_MultiSig.CallOpts)
+}
+
+// Confirmations is a free data retrieval call binding the contract method 0x3411c81c.
+//
+// Solidity: function confirmations( uint256,  address) constant returns(bool)
+func (_MultiSig *MultiSigCaller) Confirmations(opts *bind.CallOpts, arg0 *big.Int, arg1 common.Address) (bool, error) {
+	var (
+		ret0 = new(bool)
+	)
+	out := ret0
+	err := _MultiSig.contract.Call(opts, out, "confirmations", arg0, arg1)
+	return *ret0, err
+}
+
+// Confirmations is a free data retrieval call binding the contract method 0x3411c81c.
+//
+// Solidity: function confirmations( uint256,  address) constant returns(bool)
+func (_MultiSig *MultiSigSession) Confirmations(arg0 *big.Int, arg1 common.Address) (bool, error) {
+	return _MultiSig.Contract.Confirmations(&_MultiSig.CallOpts, arg0, arg1)
+}
+
+// Confirmations is a free data retrieval call binding the contract method 0x3411c81c.
+//
+// Solidity: function confirmations( uint256,  address) constant returns(bool)
+func (_MultiSig *MultiSigCallerSession) Confirmations(arg0 *big.Int, arg1 common.Address) (bool, error) {
+	return _MultiSig.Contract.Confirmations(&_MultiSig.CallOpts, arg0, arg1)
+}
+
+// GetConfirmationCount is a free data retrieval call binding the contract method 0x8b51d13f.
+//
+// Solidity: function getConfirmationCount(transactionId uint256) constant returns(count uint256)
+func (_MultiSig *MultiSigCaller) GetConfirmationCount(opts *bind.CallOpts, transactionId *big.Int) (*big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _MultiSig.contract.Call(opts, out, "getConfirmationCount", transactionId)
+	return *ret0, err
+}
+
+// GetConfirmationCount is a free data retrieval call binding the contract method 0x8b51d13f.
+//
+// Solidity: function getConfirmationCount(transactionId uint256) constant returns(count uint256)
+func (_MultiSig *MultiSigSession) GetConfirmationCount(transactionId *big.Int) (*big.Int, error) {
+	return _MultiSig.Contract.GetConfirmationCount(&_MultiSig.CallOpts, transactionId)
+}
+
+// GetConfirmationCount is a free data retrieval call binding the contract method 0x8b51d13f.
+//
+// Solidity: function getConfirmationCount(transactionId uint256) constant returns(count uint256)
+func (_MultiSig *MultiSigCallerSession) GetConfirmationCount(transactionId *big.Int) (*big.Int, error) {
+	return _MultiSig.Contract.GetConfirmationCount(&_MultiSig.CallOpts, transactionId)
+}
+
+// GetConfirmations is a free data retrieval call binding the contract method 0xb5dc40c3.
+//
+// Solidity: function getConfirmations(transactionId uint256) constant returns(_confirmations address[])
+func (_MultiSig *MultiSigCaller) GetConfirmations(opts *bind.CallOpts, transactionId *big.Int) ([]common.Address, error) {
+	var (
+		ret0 = new([]common.Address)
+	)
+	out := ret0
+	err := _MultiSig.contract.Call(opts, out, "getConfirmations", transactionId)
+	return *ret0, err
+}
+
+// GetConfirmations is a free data retrieval call binding the contract method 0xb5dc40c3.
+//
+// Solidity: function getConfirmations(transactionId uint256) constant returns(_confirmations address[])
+func (_MultiSig *MultiSigSession) GetConfirmations(transactionId *big.Int) ([]common.Address, error) {
+	return _MultiSig.Contract.GetConfirmations(&_MultiSig.CallOpts, transactionId)
+}
+
+// GetConfirmations is a free data retrieval call binding the contract method 0xb5dc40c3.
+//
+// Solidity: function getConfirmations(transactionId uint256) constant returns(_confirmations address[])
+func (_MultiSig *MultiSigCallerSession) GetConfirmations(transactionId *big.Int) ([]common.Address, error) {
+	return _MultiSig.Contract.GetConfirmations(&_MultiSig.CallOpts, transactionId)
+}
+
+// GetOwners is a free data retrieval call binding the contract method 0xa0e67e2b.
+//
+// Solidity: function getOwners() constant returns(address[])
+func (_MultiSig *MultiSigCaller) GetOwners(opts *bind.CallOpts) ([]common.Address, error) {
+	var (
+		ret0 = new([]common.Address)
+	)
+	out := ret0
+	err := _MultiSig.contract.Call(opts, out, "getOwners")
+	return *ret0, err
+}
+
+// GetOwners is a free data retrieval call binding the contract method 0xa0e67e2b.
+//
+// Solidity: function getOwners() constant returns(address[])
+func (_MultiSig *MultiSigSession) GetOwners() ([]common.Address, error) {
+	return _MultiSig.Contract.GetOwners(&_MultiSig.CallOpts)
+}
+
+// GetOwners is a free data retrieval call binding the contract method 0xa0e67e2b.
+//
+// Solidity: function getOwners() constant returns(address[])
+func (_MultiSig *MultiSigCallerSession) GetOwners() ([]common.Address, error) {
+	return _MultiSig.Contract.GetOwners(&_MultiSig.CallOpts)
+}
+
+// IsConfirmed is a free data retrieval call binding the contract method 0x784547a7.
+//
+// Solidity: function isConfirmed(transactionId uint256) constant returns(bool)
+func (_MultiSig *MultiSigCaller) IsConfirmed(opts *bind.CallOpts, transactionId *big.Int) (bool, error) {
+	var (
+		ret0 = new(bool)
+	)
+	out := ret0
+	err := _MultiSig.contract.Call(opts, out, "isConfirmed", transactionId)
+	return *ret0, err
+}
+
+// IsConfirmed is a free data retrieval call binding the contract method 0x784547a7.
+//
+// Solidity: function isConfirmed(transactionId uint256) constant returns(bool)
+func (_MultiSig *MultiSigSession) IsConfirmed(transactionId *big.Int) (bool, error) {
+	return _MultiSig.Contract.IsConfirmed(&_MultiSig.CallOpts, transactionId)
+}
+
+// IsConfirmed is a free data retrieval call binding the contract method 0x784547a7.
+//
+// Solidity: function isConfirmed(transactionId uint256) constant returns(bool)
+func (_MultiSig *MultiSigCallerSession) IsConfirmed(transactionId *big.Int) (bool, error) {
+	return _MultiSig.Contract.IsConfirmed(&_MultiSig.CallOpts, transactionId)
+}
+
+// IsOwner is a free data retrieval call binding the contract method 0x2f54bf6e.
+//
+// Solidity: function isOwner( address) constant returns(bool)
+func (_MultiSig *MultiSigCaller) IsOwner(opts *bind.CallOpts, arg0 common.Address) (bool, error) {
+	var (
+		ret0 = new(bool)
+	)
+	out := ret0
+	err := _MultiSig.contract.Call(opts, out, "isOwner", arg0)
+	return *ret0, err
+}
+
+// IsOwner is a free data retrieval call binding the contract method 0x2f54bf6e.
+//
+// Solidity: function isOwner( address) constant returns(bool)
+func (_MultiSig *MultiSigSession) IsOwner(arg0 common.Address) (bool, error) {
+	return _MultiSig.Contract.IsOwner(&_MultiSig.CallOpts, arg0)
+}
+
+// IsOwner is a free data retrieval call binding the contract method 0x2f54bf6e.
+//
+// Solidity: function isOwner( address) constant returns(bool)
+func (_MultiSig *MultiSigCallerSession) IsOwner(arg0 common.Address) (bool, error) {
+	return _MultiSig.Contract.IsOwner(&_MultiSig.CallOpts, arg0)
+}
+
+// Owners is a free data retrieval call binding the contract method 0x025e7c27.
+//
+// Solidity: function owners( uint256) constant returns(address)
+func (_MultiSig *MultiSigCaller) Owners(opts *bind.CallOpts, arg0 *big.Int) (common.Address, error) {
+	var (
+		ret0 = new(common.Address)
+	)
+	out := ret0
+	err := _MultiSig.contract.Call(opts, out, "owners", arg0)
+	return *ret0, err
+}
+
+// Owners is a free data retrieval call binding the contract method 0x025e7c27.
+//
+// Solidity: function owners( uint256) constant returns(address)
+func (_MultiSig *MultiSigSession) Owners(arg0 *big.Int) (common.Address, error) {
+	return _MultiSig.Contract.Owners(&_MultiSig.CallOpts, arg0)
+}
+
+// Owners is a free data retrieval call binding the contract method 0x025e7c27.
+//
+// Solidity: function owners( uint256) constant returns(address)
+func (_MultiSig *MultiSigCallerSession) Owners(arg0 *big.Int) (common.Address, error) {
+	return _MultiSig.Contract.Owners(&_MultiSig.CallOpts, arg0)
+}
+
+// Required is a free data retrieval call binding the contract method 0xdc8452cd.
+//
+// Solidity: function required() constant returns(uint256)
+func (_MultiSig *MultiSigCaller) Required(opts *bind.CallOpts) (*big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _MultiSig.contract.Call(opts, out, "required")
+	return *ret0, err
+}
+
+// Required is a free data retrieval call binding the contract method 0xdc8452cd.
+//
+// Solidity: function required() constant returns(uint256)
+func (_MultiSig *MultiSigSession) Required() (*big.Int, error) {
+	return _MultiSig.Contract.Required(&_MultiSig.CallOpts)
+}
+
+// Required is a free data retrieval call binding the contract method 0xdc8452cd.
+//
+// Solidity: function required() constant returns(uint256)
+func (_MultiSig *MultiSigCallerSession) Required() (*big.Int, error) {
+	return _MultiSig.Contract.Required(&_MultiSig.CallOpts)
+}
+
+// TransactionCount is a free data retrieval call binding the contract method 0xb77bf600.
+//
+// Solidity: function transactionCount() constant returns(uint256)
+func (_MultiSig *MultiSigCaller) TransactionCount(opts *bind.CallOpts) (*big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _MultiSig.contract.Call(opts, out, "transactionCount")
+	return *ret0, err
+}
+
+// TransactionCount is a free data retrieval call binding the contract method 0xb77bf600.
+//
+// Solidity: function transactionCount() constant returns(uint256)
+func (_MultiSig *MultiSigSession) TransactionCount() (*big.Int, error) {
+	return _MultiSig.Contract.TransactionCount(&_MultiSig.CallOpts)
+}
+
+// TransactionCount is a free data retrieval call binding the contract method 0xb77bf600.
+//
+// Solidity: function transactionCount() constant returns(uint256)
+func (_MultiSig *MultiSigCallerSession) TransactionCount() (*big.Int, error) {
+	return _MultiSig.Contract.TransactionCount(&_MultiSig.CallOpts)
+}
+
+// Transactions is a free data retrieval call binding the contract method 0x9ace38c2.
+//
+// Solidity: function transactions( uint256) constant returns(destination address, value uint256, data bytes, executed bool)
+func (_MultiSig *MultiSigCaller) Transactions(opts *bind.CallOpts, arg0 *big.Int) (struct {
+	Destination common.Address
+	Value       *big.Int
+	Data        []byte
+	Executed    bool
+}, error) {
+	ret := new(struct {
+		Destination common.Address
+		Value       *big.Int
+		Data        []byte
+		Executed    bool
+	})
+	out := ret
+	err := _MultiSig.contract.Call(opts, out, "transactions", arg0)
+	return *ret, err
+}
+
+// Transactions is a free data retrieval call binding the contract method 0x9ace38c2.
+//
+// Solidity: function transactions( uint256) constant returns(destination address, value uint256, data bytes, executed bool)
+func (_MultiSig *MultiSigSession) Transactions(arg0 *big.Int) (struct {
+	Destination common.Address
+	Value       *big.Int
+	Data        []byte
+	Executed    bool
+}, error) {
+	return _MultiSig.Contract.Transactions(&_MultiSig.CallOpts, arg0)
+}
+
+// Transactions is a free data retrieval call binding the contract method 0x9ace38c2.
+//
+// Solidity: function transactions( uint256) constant returns(destination address, value uint256, data bytes, executed bool)
+func (_MultiSig *MultiSigCallerSession) Transactions(arg0 *big.Int) (struct {
+	Destination common.Address
+	Value       *big.Int
+	Data        []byte
+	Executed    bool
+}, error) {
+	return _MultiSig.Contract.Transactions(&_MultiSig.CallOpts, arg0)
+}
+
+// AddOwner is a paid mutator transaction binding the contract method 0x7065cb48.
+//
+// Solidity: function addOwner(owner address) returns()
+func (_MultiSig *MultiSigTransactor) AddOwner(opts *bind.TransactOpts, owner common.Address) (*types.Transaction, error) {
+	return _MultiSig.contract.Transact(opts, "addOwner", owner)
+}
+
+// AddOwner is a paid mutator transaction binding the contract method 0x7065cb48.
+//
+// Solidity: function addOwner(owner address) returns()
+func (_MultiSig *MultiSigSession) AddOwner(owner common.Address) (*types.Transaction, error) {
+	return _MultiSig.Contract.AddOwner(&_MultiSig.TransactOpts, owner)
+}
+
+// AddOwner is a paid mutator transaction binding the contract method 0x7065cb48.
+//
+// Solidity: function addOwner(owner address) returns()
+func (_MultiSig *MultiSigTransactorSession) AddOwner(owner common.Address) (*types.Transaction, error) {
+	return _MultiSig.Contract.AddOwner(&_MultiSig.TransactOpts, owner)
+}
+
+// ChangeRequirement is a paid mutator transaction binding the contract method 0xba51a6df.
+//
+// Solidity: function changeRequirement(_required uint256) returns()
+func (_MultiSig *MultiSigTransactor) ChangeRequirement(opts *bind.TransactOpts, _required *big.Int) (*types.Transaction, error) {
+	return _MultiSig.contract.Transact(opts, "changeRequirement", _required)
+}
+
+// ChangeRequirement is a paid mutator transaction binding the contract method 0xba51a6df.
+//
+// Solidity: function changeRequirement(_required uint256) returns()
+func (_MultiSig *MultiSigSession) ChangeRequirement(_required *big.Int) (*types.Transaction, error) {
+	return _MultiSig.Contract.ChangeRequirement(&_MultiSig.TransactOpts, _required)
+}
+
+// ChangeRequirement is a paid mutator transaction binding the contract method 0xba51a6df.
+//
+// Solidity: function changeRequirement(_required uint256) returns()
+func (_MultiSig *MultiSigTransactorSession) ChangeRequirement(_required *big.Int) (*types.Transaction, error) {
+	return _MultiSig.Contract.ChangeRequirement(&_MultiSig.TransactOpts, _required)
+}
+
+// ConfirmTransaction is a paid mutator transaction binding the contract method 0xc01a8c84.
+//
+// Solidity: function confirmTransaction(transactionId uint256) returns()
+func (_MultiSig *MultiSigTransactor) ConfirmTransaction(opts *bind.TransactOpts, transactionId *big.Int) (*types.Transaction, error) {
+	return _MultiSig.contract.Transact(opts, "confirmTransaction", transactionId)
+}
+
+// ConfirmTransaction is a paid mutator transaction binding the contract method 0xc01a8c84.
+//
+// Solidity: function confirmTransaction(transactionId uint256) returns()
+func (_MultiSig *MultiSigSession) ConfirmTransaction(transactionId *big.Int) (*types.Transaction, error) {
+	return _MultiSig.Contract.ConfirmTransaction(&_MultiSig.TransactOpts, transactionId)
+}
+
+// ConfirmTransaction is a paid mutator transaction binding the contract method 0xc01a8c84.
+//
+// Solidity: function confirmTransaction(transactionId uint256) returns()
+func (_MultiSig *MultiSigTransactorSession) ConfirmTransaction(transactionId *big.Int) (*types.Transaction, error) {
+	return _MultiSig.Contract.ConfirmTransaction(&_MultiSig.TransactOpts, transactionId)
+}
+
+// ExecuteTransaction is a paid mutator transaction binding the contract method 0xee22610b.
+//
+// Solidity: function executeTransaction(transactionId uint256) returns()
+func (_MultiSig *MultiSigTransactor) ExecuteTransaction(opts *bind.TransactOpts, transactionId *big.Int) (*types.Transaction, error) {
+	return _MultiSig.contract.Transact(opts, "executeTransaction", transactionId)
+}
+
+// ExecuteTransaction is a paid mutator transaction binding the contract method 0xee22610b.
+//
+// Solidity: function executeTransaction(transactionId uint256) returns()
+func (_MultiSig *MultiSigSession) ExecuteTransaction(transactionId *big.Int) (*types.Transaction, error) {
+	return _MultiSig.Contract.ExecuteTransaction(&_MultiSig.TransactOpts, transactionId)
+}
+
+// ExecuteTransaction is a paid mutator transaction binding the contract method 0xee22610b.
+//
+// Solidity: function executeTransaction(transactionId uint256) returns()
+func (_MultiSig *MultiSigTransactorSession) ExecuteTransaction(transactionId *big.Int) (*types.Transaction, error) {
+	return _MultiSig.Contract.ExecuteTransaction(&_MultiSig.TransactOpts, transactionId)
+}
+
+// RemoveOwner is a paid mutator transaction binding the contract method 0x173825d9.
+//
+// Solidity: function removeOwner(owner address) returns()
+func (_MultiSig *MultiSigTransactor) RemoveOwner(opts *bind.TransactOpts, owner common.Address) (*types.Transaction, error) {
+	return _MultiSig.contract.Transact(opts, "removeOwner", owner)
+}
+
+// RemoveOwner is a paid mutator transaction binding the contract method 0x173825d9.
+//
+// Solidity: function removeOwner(owner address) returns()
+func (_MultiSig *MultiSigSession) RemoveOwner(owner common.Address) (*types.Transaction, error) {
+	return _MultiSig.Contract.RemoveOwner(&_MultiSig.TransactOpts, owner)
+}
+
+// RemoveOwner is a paid mutator transaction binding the contract method 0x173825d9.
+//
+// Solidity: function removeOwner(owner address) returns()
+func (_MultiSig *MultiSigTransactorSession) RemoveOwner(owner common.Address) (*types.Transaction, error) {
+	return _MultiSig.Contract.RemoveOwner(&_MultiSig.TransactOpts, owner)
+}
+
+// RevokeConfirmation is a paid mutator transaction binding the contract method 0x20ea8d86.
+//
+// Solidity: function revokeConfirmation(transactionId uint256) returns()
+func (_MultiSig *MultiSigTransactor) RevokeConfirmation(opts *bind.TransactOpts, transactionId *big.Int) (*types.Transaction, error) {
+	return _MultiSig.contract.Transact(opts, "revokeConfirmation", transactionId)
+}
+
+// RevokeConfirmation is a paid mutator transaction binding the contract method 0x20ea8d86.
+//
+// Solidity: function revokeConfirmation(transactionId uint256) returns()
+func (_MultiSig *MultiSigSession) RevokeConfirmation(transactionId *big.Int) (*types.Transaction, error) {
+	return _MultiSig.Contract.RevokeConfirmation(&_MultiSig.TransactOpts, transactionId)
+}
+
+// RevokeConfirmation is a paid mutator transaction binding the contract method 0x20ea8d86.
+//
+// Solidity: function revokeConfirmation(transactionId uint256) returns()
+func (_MultiSig *MultiSigTransactorSession) RevokeConfirmation(transactionId *big.Int) (*types.Transaction, error) {
+	return _MultiSig.Contract.RevokeConfirmation(&_MultiSig.TransactOpts, transactionId)
+}
+
+// SubmitTransaction is a paid mutator transaction binding the contract method 0xc6427474.
+//
+// Solidity: function submitTransaction(destination address, value uint256, data bytes) returns(transactionId uint256)
+func (_MultiSig *MultiSigTransactor) SubmitTransaction(opts *bind.TransactOpts, destination common.Address, value *big.Int, data []byte) (*types.Transaction, error) {
+	return _MultiSig.contract.Transact(opts, "submitTransaction", destination, value, data)
+}
+
+// SubmitTransaction is a paid mutator transaction binding the contract method 0xc6427474.
+//
+// Solidity: function submitTransaction(destination address, value uint256, data bytes) returns(transactionId uint256)
+func (_MultiSig *MultiSigSession) SubmitTransaction(destination common.Address, value *big.Int, data []byte) (*types.Transaction, error) {
+	return _MultiSig.Contract.SubmitTransaction(&_MultiSig.TransactOpts, destination, value, data)
+}
+
+// SubmitTransaction is a paid mutator transaction binding the contract method 0xc6427474.
+//
+// Solidity: function submitTransaction(destination address, value uint256, data bytes) returns(transactionId uint256)
+func (_MultiSig *MultiSigTransactorSession) SubmitTransaction(destination common.Address, value *big.Int, data []byte) (*types.Transaction, error) {
+	return _MultiSig.Contract.SubmitTransaction(&_MultiSig.TransactOpts, destination, value, data)
+}
+
+// MultiSigConfirmationIterator is returned from FilterConfirmation and is used to iterate over the raw logs and unpacked data for Confirmation events raised by the MultiSig contract.
+type MultiSigConfirmationIterator struct {
+	Event *MultiSigConfirmation // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log       // Log channel receiving the found contract events
+	sub  cpchain.Subscription // Subscription for errors, completion and termination
+	done bool                 // Whether the subscription completed delivering logs
+	fail error                // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *MultiSigConfirmationIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(MultiSigConfirmation)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(MultiSigConfirmation)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *MultiSigConfirmationIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *MultiSigConfirmationIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// MultiSigConfirmation represents a Confirmation event raised by the MultiSig contract.
+type MultiSigConfirmation struct {
+	Sender        common.Address
+	TransactionId *big.Int
+	Raw           types.Log // Blockchain specific contextual infos
+}
+
+// FilterConfirmation is a free log retrieval operation binding the contract event 0x4a504a94899432a9846e1aa406dceb1bcfd538bb839071d49d1e5e23f5be30ef.
+//
+// Solidity: e Confirmation(sender indexed address, transactionId indexed uint256)
+func (_MultiSig *MultiSigFilterer) FilterConfirmation(opts *bind.FilterOpts, sender []common.Address, transactionId []*big.Int) (*MultiSigConfirmationIterator, error) {
+
+	var senderRule []interface{}
+	for _, senderItem := range sender {
+		senderRule = append(senderRule, senderItem)
+	}
+	var transactionIdRule []interface{}
+	for _, transactionIdItem := range transactionId {
+		transactionIdRule = append(transactionIdRule, transactionIdItem)
+	}
+
+	logs, sub, err := _MultiSig.contract.FilterLogs(opts, "Confirmation", senderRule, transactionIdRule)
+	if err != nil {
+		return nil, err
+	}
+	return &MultiSigConfirmationIterator{contract: _MultiSig.contract, event: "Confirmation", logs: logs, sub: sub}, nil
+}
+
+// WatchConfirmation is a free log subscription operation binding the contract event 0x4a504a94899432a9846e1aa406dceb1bcfd538bb839071d49d1e5e23f5be30ef.
+//
+// Solidity: e Confirmation(sender indexed address, transactionId indexed uint256)
+func (_MultiSig *MultiSigFilterer) WatchConfirmation(opts *bind.WatchOpts, sink chan<- *MultiSigConfirmation, sender []common.Address, transactionId []*big.Int) (event.Subscription, error) {
+
+	var senderRule []interface{}
+	for _, senderItem := range sender {
+		senderRule = append(senderRule, senderItem)
+	}
+	var transactionIdRule []interface{}
+	for _, transactionIdItem := range transactionId {
+		transactionIdRule = append(transactionIdRule, transactionIdItem)
+	}
+
+	logs, sub, err := _MultiSig.contract.WatchLogs(opts, "Confirmation", senderRule, transactionIdRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(MultiSigConfirmation)
+				if err := _MultiSig.contract.UnpackLog(event, "Confirmation", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// StreamConfirmation is a reorg-aware log subscription operation binding the contract event 0x4a504a94899432a9846e1aa406dceb1bcfd538bb839071d49d1e5e23f5be30ef,
+// resuming from the cursor of opts. Events reverted by a reorg are delivered again with Raw.Removed set.
+//
+// Solidity: e Confirmation(sender indexed address, transactionId indexed uint256)
+func (_MultiSig *MultiSigFilterer) StreamConfirmation(opts *bind.StreamOpts, sink chan<- *MultiSigConfirmation, sender []common.Address, transactionId []*big.Int) (event.Subscription, error) {
+
+	var senderRule []interface{}
+	for _, senderItem := range sender {
+		senderRule = append(senderRule, senderItem)
+	}
+	var transactionIdRule []interface{}
+	for _, transactionIdItem := range transactionId {
+		transactionIdRule = append(transactionIdRule, transactionIdItem)
+	}
+
+	logs, sub, err := _MultiSig.contract.StreamLogs(opts, "Confirmation", senderRule, transactionIdRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New or reverted log arrived, parse the event and forward to the user
+				event := new(MultiSigConfirmation)
+				if err := _MultiSig.contract.UnpackLog(event, "Confirmation", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// MultiSigCreationIterator is returned from FilterCreation and is used to iterate over the raw logs and unpacked data for Creation events raised by the MultiSig contract.
+type MultiSigCreationIterator struct {
+	Event *MultiSigCreation // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log       // Log channel receiving the found contract events
+	sub  cpchain.Subscription // Subscription for errors, completion and termination
+	done bool                 // Whether the subscription completed delivering logs
+	fail error                // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *MultiSigCreationIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(MultiSigCreation)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(MultiSigCreation)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *MultiSigCreationIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *MultiSigCreationIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// MultiSigCreation represents a Creation event raised by the MultiSig contract.
+type MultiSigCreation struct {
+	TransactionId   *big.Int
+	ContractAddress common.Address
+	Raw             types.Log // Blockchain specific contextual infos
+}
+
+// FilterCreation is a free log retrieval operation binding the contract event 0xc27fa55521edf9abb9d6183a277694c7f9e1f9e70e7fca7f115654a5916a415b.
+//
+// Solidity: e Creation(transactionId indexed uint256, contractAddress address)
+func (_MultiSig *MultiSigFilterer) FilterCreation(opts *bind.FilterOpts, transactionId []*big.Int) (*MultiSigCreationIterator, error) {
+
+	var transactionIdRule []interface{}
+	for _, transactionIdItem := range transactionId {
+		transactionIdRule = append(transactionIdRule, transactionIdItem)
+	}
+
+	logs, sub, err := _MultiSig.contract.FilterLogs(opts, "Creation", transactionIdRule)
+	if err != nil {
+		return nil, err
+	}
+	return &MultiSigCreationIterator{contract: _MultiSig.contract, event: "Creation", logs: logs, sub: sub}, nil
+}
+
+// WatchCreation is a free log subscription operation binding the contract event 0xc27fa55521edf9abb9d6183a277694c7f9e1f9e70e7fca7f115654a5916a415b.
+//
+// Solidity: e Creation(transactionId indexed uint256, contractAddress address)
+func (_MultiSig *MultiSigFilterer) WatchCreation(opts *bind.WatchOpts, sink chan<- *MultiSigCreation, transactionId []*big.Int) (event.Subscription, error) {
+
+	var transactionIdRule []interface{}
+	for _, transactionIdItem := range transactionId {
+		transactionIdRule = append(transactionIdRule, transactionIdItem)
+	}
+
+	logs, sub, err := _MultiSig.contract.WatchLogs(opts, "Creation", transactionIdRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(MultiSigCreation)
+				if err := _MultiSig.contract.UnpackLog(event, "Creation", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// StreamCreation is a reorg-aware log subscription operation binding the contract event 0xc27fa55521edf9abb9d6183a277694c7f9e1f9e70e7fca7f115654a5916a415b,
+// resuming from the cursor of opts. Events reverted by a reorg are delivered again with Raw.Removed set.
+//
+// Solidity: e Creation(transactionId indexed uint256, contractAddress address)
+func (_MultiSig *MultiSigFilterer) StreamCreation(opts *bind.StreamOpts, sink chan<- *MultiSigCreation, transactionId []*big.Int) (event.Subscription, error) {
+
+	var transactionIdRule []interface{}
+	for _, transactionIdItem := range transactionId {
+		transactionIdRule = append(transactionIdRule, transactionIdItem)
+	}
+
+	logs, sub, err := _MultiSig.contract.StreamLogs(opts, "Creation", transactionIdRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New or reverted log arrived, parse the event and forward to the user
+				event := new(MultiSigCreation)
+				if err := _MultiSig.contract.UnpackLog(event, "Creation", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// MultiSigDepositIterator is returned from FilterDeposit and is used to iterate over the raw logs and unpacked data for Deposit events raised by the MultiSig contract.
+type MultiSigDepositIterator struct {
+	Event *MultiSigDeposit // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log       // Log channel receiving the found contract events
+	sub  cpchain.Subscription // Subscription for errors, completion and termination
+	done bool                 // Whether the subscription completed delivering logs
+	fail error                // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *MultiSigDepositIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(MultiSigDeposit)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(MultiSigDeposit)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *MultiSigDepositIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *MultiSigDepositIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// MultiSigDeposit represents a Deposit event raised by the MultiSig contract.
+type MultiSigDeposit struct {
+	Sender common.Address
+	Value  *big.Int
+	Raw    types.Log // Blockchain specific contextual infos
+}
+
+// FilterDeposit is a free log retrieval operation binding the contract event 0xe1fffcc4923d04b559f4d29a8bfc6cda04eb5b0d3c460751c2402c5c5cc9109c.
+//
+// Solidity: e Deposit(sender indexed address, value uint256)
+func (_MultiSig *MultiSigFilterer) FilterDeposit(opts *bind.FilterOpts, sender []common.Address) (*MultiSigDepositIterator, error) {
+
+	var senderRule []interface{}
+	for _, senderItem := range sender {
+		senderRule = append(senderRule, senderItem)
+	}
+
+	logs, sub, err := _MultiSig.contract.FilterLogs(opts, "Deposit", senderRule)
+	if err != nil {
+		return nil, err
+	}
+	return &MultiSigDepositIterator{contract: _MultiSig.contract, event: "Deposit", logs: logs, sub: sub}, nil
+}
+
+// WatchDeposit is a free log subscription operation binding the contract event 0xe1fffcc4923d04b559f4d29a8bfc6cda04eb5b0d3c460751c2402c5c5cc9109c.
+//
+// Solidity: e Deposit(sender indexed address, value uint256)
+func (_MultiSig *MultiSigFilterer) WatchDeposit(opts *bind.WatchOpts, sink chan<- *MultiSigDeposit, sender []common.Address) (event.Subscription, error) {
+
+	var senderRule []interface{}
+	for _, senderItem := range sender {
+		senderRule = append(senderRule, senderItem)
+	}
+
+	logs, sub, err := _MultiSig.contract.WatchLogs(opts, "Deposit", senderRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(MultiSigDeposit)
+				if err := _MultiSig.contract.UnpackLog(event, "Deposit", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// StreamDeposit is a reorg-aware log subscription operation binding the contract event 0xe1fffcc4923d04b559f4d29a8bfc6cda04eb5b0d3c460751c2402c5c5cc9109c,
+// resuming from the cursor of opts. Events reverted by a reorg are delivered again with Raw.Removed set.
+//
+// Solidity: e Deposit(sender indexed address, value uint256)
+func (_MultiSig *MultiSigFilterer) StreamDeposit(opts *bind.StreamOpts, sink chan<- *MultiSigDeposit, sender []common.Address) (event.Subscription, error) {
+
+	var senderRule []interface{}
+	for _, senderItem := range sender {
+		senderRule = append(senderRule, senderItem)
+	}
+
+	logs, sub, err := _MultiSig.contract.StreamLogs(opts, "Deposit", senderRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New or reverted log arrived, parse the event and forward to the user
+				event := new(MultiSigDeposit)
+				if err := _MultiSig.contract.UnpackLog(event, "Deposit", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// MultiSigExecutionIterator is returned from FilterExecution and is used to iterate over the raw logs and unpacked data for Execution events raised by the MultiSig contract.
+type MultiSigExecutionIterator struct {
+	Event *MultiSigExecution // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log       // Log channel receiving the found contract events
+	sub  cpchain.Subscription // Subscription for errors, completion and termination
+	done bool                 // Whether the subscription completed delivering logs
+	fail error                // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *MultiSigExecutionIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(MultiSigExecution)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(MultiSigExecution)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *MultiSigExecutionIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *MultiSigExecutionIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// MultiSigExecution represents a Execution event raised by the MultiSig contract.
+type MultiSigExecution struct {
+	TransactionId *big.Int
+	Raw           types.Log // Blockchain specific contextual infos
+}
+
+// FilterExecution is a free log retrieval operation binding the contract event 0x33e13ecb54c3076d8e8bb8c2881800a4d972b792045ffae98fdf46df365fed75.
+//
+// Solidity: e Execution(transactionId indexed uint256)
+func (_MultiSig *MultiSigFilterer) FilterExecution(opts *bind.FilterOpts, transactionId []*big.Int) (*MultiSigExecutionIterator, error) {
+
+	var transactionIdRule []interface{}
+	for _, transactionIdItem := range transactionId {
+		transactionIdRule = append(transactionIdRule, transactionIdItem)
+	}
+
+	logs, sub, err := _MultiSig.contract.FilterLogs(opts, "Execution", transactionIdRule)
+	if err != nil {
+		return nil, err
+	}
+	return &MultiSigExecutionIterator{contract: _MultiSig.contract, event: "Execution", logs: logs, sub: sub}, nil
+}
+
+// WatchExecution is a free log subscription operation binding the contract event 0x33e13ecb54c3076d8e8bb8c2881800a4d972b792045ffae98fdf46df365fed75.
+//
+// Solidity: e Execution(transactionId indexed uint256)
+func (_MultiSig *MultiSigFilterer) WatchExecution(opts *bind.WatchOpts, sink chan<- *MultiSigExecution, transactionId []*big.Int) (event.Subscription, error) {
+
+	var transactionIdRule []interface{}
+	for _, transactionIdItem := range transactionId {
+		transactionIdRule = append(transactionIdRule, transactionIdItem)
+	}
+
+	logs, sub, err := _MultiSig.contract.WatchLogs(opts, "Execution", transactionIdRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(MultiSigExecution)
+				if err := _MultiSig.contract.UnpackLog(event, "Execution", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// StreamExecution is a reorg-aware log subscription operation binding the contract event 0x33e13ecb54c3076d8e8bb8c2881800a4d972b792045ffae98fdf46df365fed75,
+// resuming from the cursor of opts. Events reverted by a reorg are delivered again with Raw.Removed set.
+//
+// Solidity: e Execution(transactionId indexed uint256)
+func (_MultiSig *MultiSigFilterer) StreamExecution(opts *bind.StreamOpts, sink chan<- *MultiSigExecution, transactionId []*big.Int) (event.Subscription, error) {
+
+	var transactionIdRule []interface{}
+	for _, transactionIdItem := range transactionId {
+		transactionIdRule = append(transactionIdRule, transactionIdItem)
+	}
+
+	logs, sub, err := _MultiSig.contract.StreamLogs(opts, "Execution", transactionIdRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New or reverted log arrived, parse the event and forward to the user
+				event := new(MultiSigExecution)
+				if err := _MultiSig.contract.UnpackLog(event, "Execution", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// MultiSigExecutionFailureIterator is returned from FilterExecutionFailure and is used to iterate over the raw logs and unpacked data for ExecutionFailure events raised by the MultiSig contract.
+type MultiSigExecutionFailureIterator struct {
+	Event *MultiSigExecutionFailure // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log       // Log channel receiving the found contract events
+	sub  cpchain.Subscription // Subscription for errors, completion and termination
+	done bool                 // Whether the subscription completed delivering logs
+	fail error                // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *MultiSigExecutionFailureIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(MultiSigExecutionFailure)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(MultiSigExecutionFailure)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *MultiSigExecutionFailureIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *MultiSigExecutionFailureIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// MultiSigExecutionFailure represents a ExecutionFailure event raised by the MultiSig contract.
+type MultiSigExecutionFailure struct {
+	TransactionId *big.Int
+	Raw           types.Log // Blockchain specific contextual infos
+}
+
+// FilterExecutionFailure is a free log retrieval operation binding the contract event 0x526441bb6c1aba3c9a4a6ca1d6545da9c2333c8c48343ef398eb858d72b79236.
+//
+// Solidity: e ExecutionFailure(transactionId indexed uint256)
+func (_MultiSig *MultiSigFilterer) FilterExecutionFailure(opts *bind.FilterOpts, transactionId []*big.Int) (*MultiSigExecutionFailureIterator, error) {
+
+	var transactionIdRule []interface{}
+	for _, transactionIdItem := range transactionId {
+		transactionIdRule = append(transactionIdRule, transactionIdItem)
+	}
+
+	logs, sub, err := _MultiSig.contract.FilterLogs(opts, "ExecutionFailure", transactionIdRule)
+	if err != nil {
+		return nil, err
+	}
+	return &MultiSigExecutionFailureIterator{contract: _MultiSig.contract, event: "ExecutionFailure", logs: logs, sub: sub}, nil
+}
+
+// WatchExecutionFailure is a free log subscription operation binding the contract event 0x526441bb6c1aba3c9a4a6ca1d6545da9c2333c8c48343ef398eb858d72b79236.
+//
+// Solidity: e ExecutionFailure(transactionId indexed uint256)
+func (_MultiSig *MultiSigFilterer) WatchExecutionFailure(opts *bind.WatchOpts, sink chan<- *MultiSigExecutionFailure, transactionId []*big.Int) (event.Subscription, error) {
+
+	var transactionIdRule []interface{}
+	for _, transactionIdItem := range transactionId {
+		transactionIdRule = append(transactionIdRule, transactionIdItem)
+	}
+
+	logs, sub, err := _MultiSig.contract.WatchLogs(opts, "ExecutionFailure", transactionIdRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(MultiSigExecutionFailure)
+				if err := _MultiSig.contract.UnpackLog(event, "ExecutionFailure", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// StreamExecutionFailure is a reorg-aware log subscription operation binding the contract event 0x526441bb6c1aba3c9a4a6ca1d6545da9c2333c8c48343ef398eb858d72b79236,
+// resuming from the cursor of opts. Events reverted by a reorg are delivered again with Raw.Removed set.
+//
+// Solidity: e ExecutionFailure(transactionId indexed uint256)
+func (_MultiSig *MultiSigFilterer) StreamExecutionFailure(opts *bind.StreamOpts, sink chan<- *MultiSigExecutionFailure, transactionId []*big.Int) (event.Subscription, error) {
+
+	var transactionIdRule []interface{}
+	for _, transactionIdItem := range transactionId {
+		transactionIdRule = append(transactionIdRule, transactionIdItem)
+	}
+
+	logs, sub, err := _MultiSig.contract.StreamLogs(opts, "ExecutionFailure", transactionIdRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New or reverted log arrived, parse the event and forward to the user
+				event := new(MultiSigExecutionFailure)
+				if err := _MultiSig.contract.UnpackLog(event, "ExecutionFailure", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// MultiSigOwnerAdditionIterator is returned from FilterOwnerAddition and is used to iterate over the raw logs and unpacked data for OwnerAddition events raised by the MultiSig contract.
+type MultiSigOwnerAdditionIterator struct {
+	Event *MultiSigOwnerAddition // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log       // Log channel receiving the found contract events
+	sub  cpchain.Subscription // Subscription for errors, completion and termination
+	done bool                 // Whether the subscription completed delivering logs
+	fail error                // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *MultiSigOwnerAdditionIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(MultiSigOwnerAddition)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(MultiSigOwnerAddition)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *MultiSigOwnerAdditionIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *MultiSigOwnerAdditionIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// MultiSigOwnerAddition represents a OwnerAddition event raised by the MultiSig contract.
+type MultiSigOwnerAddition struct {
+	Owner common.Address
+	Raw   types.Log // Blockchain specific contextual infos
+}
+
+// FilterOwnerAddition is a free log retrieval operation binding the contract event 0xf39e6e1eb0edcf53c221607b54b00cd28f3196fed0a24994dc308b8f611b682d.
+//
+// Solidity: e OwnerAddition(owner indexed address)
+func (_MultiSig *MultiSigFilterer) FilterOwnerAddition(opts *bind.FilterOpts, owner []common.Address) (*MultiSigOwnerAdditionIterator, error) {
+
+	var ownerRule []interface{}
+	for _, ownerItem := range owner {
+		ownerRule = append(ownerRule, ownerItem)
+	}
+
+	logs, sub, err := _MultiSig.contract.FilterLogs(opts, "OwnerAddition", ownerRule)
+	if err != nil {
+		return nil, err
+	}
+	return &MultiSigOwnerAdditionIterator{contract: _MultiSig.contract, event: "OwnerAddition", logs: logs, sub: sub}, nil
+}
+
+// WatchOwnerAddition is a free log subscription operation binding the contract event 0xf39e6e1eb0edcf53c221607b54b00cd28f3196fed0a24994dc308b8f611b682d.
+//
+// Solidity: e OwnerAddition(owner indexed address)
+func (_MultiSig *MultiSigFilterer) WatchOwnerAddition(opts *bind.WatchOpts, sink chan<- *MultiSigOwnerAddition, owner []common.Address) (event.Subscription, error) {
+
+	var ownerRule []interface{}
+	for _, ownerItem := range owner {
+		ownerRule = append(ownerRule, ownerItem)
+	}
+
+	logs, sub, err := _MultiSig.contract.WatchLogs(opts, "OwnerAddition", ownerRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(MultiSigOwnerAddition)
+				if err := _MultiSig.contract.UnpackLog(event, "OwnerAddition", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// StreamOwnerAddition is a reorg-aware log subscription operation binding the contract event 0xf39e6e1eb0edcf53c221607b54b00cd28f3196fed0a24994dc308b8f611b682d,
+// resuming from the cursor of opts. Events reverted by a reorg are delivered again with Raw.Removed set.
+//
+// Solidity: e OwnerAddition(owner indexed address)
+func (_MultiSig *MultiSigFilterer) StreamOwnerAddition(opts *bind.StreamOpts, sink chan<- *MultiSigOwnerAddition, owner []common.Address) (event.Subscription, error) {
+
+	var ownerRule []interface{}
+	for _, ownerItem := range owner {
+		ownerRule = append(ownerRule, ownerItem)
+	}
+
+	logs, sub, err := _MultiSig.contract.StreamLogs(opts, "OwnerAddition", ownerRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New or reverted log arrived, parse the event and forward to the user
+				event := new(MultiSigOwnerAddition)
+				if err := _MultiSig.contract.UnpackLog(event, "OwnerAddition", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// MultiSigOwnerRemovalIterator is returned from FilterOwnerRemoval and is used to iterate over the raw logs and unpacked data for OwnerRemoval events raised by the MultiSig contract.
+type MultiSigOwnerRemovalIterator struct {
+	Event *MultiSigOwnerRemoval // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log       // Log channel receiving the found contract events
+	sub  cpchain.Subscription // Subscription for errors, completion and termination
+	done bool                 // Whether the subscription completed delivering logs
+	fail error                // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *MultiSigOwnerRemovalIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(MultiSigOwnerRemoval)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(MultiSigOwnerRemoval)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *MultiSigOwnerRemovalIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *MultiSigOwnerRemovalIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// MultiSigOwnerRemoval represents a OwnerRemoval event raised by the MultiSig contract.
+type MultiSigOwnerRemoval struct {
+	Owner common.Address
+	Raw   types.Log // Blockchain specific contextual infos
+}
+
+// FilterOwnerRemoval is a free log retrieval operation binding the contract event 0x8001553a916ef2f495d26a907cc54d96ed840d7bda71e73194bf5a9df7a76b90.
+//
+// Solidity: e OwnerRemoval(owner indexed address)
+func (_MultiSig *MultiSigFilterer) FilterOwnerRemoval(opts *bind.FilterOpts, owner []common.Address) (*MultiSigOwnerRemovalIterator, error) {
+
+	var ownerRule []interface{}
+	for _, ownerItem := range owner {
+		ownerRule = append(ownerRule, ownerItem)
+	}
+
+	logs, sub, err := _MultiSig.contract.FilterLogs(opts, "OwnerRemoval", ownerRule)
+	if err != nil {
+		return nil, err
+	}
+	return &MultiSigOwnerRemovalIterator{contract: _MultiSig.contract, event: "OwnerRemoval", logs: logs, sub: sub}, nil
+}
+
+// WatchOwnerRemoval is a free log subscription operation binding the contract event 0x8001553a916ef2f495d26a907cc54d96ed840d7bda71e73194bf5a9df7a76b90.
+//
+// Solidity: e OwnerRemoval(owner indexed address)
+func (_MultiSig *MultiSigFilterer) WatchOwnerRemoval(opts *bind.WatchOpts, sink chan<- *MultiSigOwnerRemoval, owner []common.Address) (event.Subscription, error) {
+
+	var ownerRule []interface{}
+	for _, ownerItem := range owner {
+		ownerRule = append(ownerRule, ownerItem)
+	}
+
+	logs, sub, err := _MultiSig.contract.WatchLogs(opts, "OwnerRemoval", ownerRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(MultiSigOwnerRemoval)
+				if err := _MultiSig.contract.UnpackLog(event, "OwnerRemoval", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// StreamOwnerRemoval is a reorg-aware log subscription operation binding the contract event 0x8001553a916ef2f495d26a907cc54d96ed840d7bda71e73194bf5a9df7a76b90,
+// resuming from the cursor of opts. Events reverted by a reorg are delivered again with Raw.Removed set.
+//
+// Solidity: e OwnerRemoval(owner indexed address)
+func (_MultiSig *MultiSigFilterer) StreamOwnerRemoval(opts *bind.StreamOpts, sink chan<- *MultiSigOwnerRemoval, owner []common.Address) (event.Subscription, error) {
+
+	var ownerRule []interface{}
+	for _, ownerItem := range owner {
+		ownerRule = append(ownerRule, ownerItem)
+	}
+
+	logs, sub, err := _MultiSig.contract.StreamLogs(opts, "OwnerRemoval", ownerRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New or reverted log arrived, parse the event and forward to the user
+				event := new(MultiSigOwnerRemoval)
+				if err := _MultiSig.contract.UnpackLog(event, "OwnerRemoval", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// MultiSigRequirementChangeIterator is returned from FilterRequirementChange and is used to iterate over the raw logs and unpacked data for RequirementChange events raised by the MultiSig contract.
+type MultiSigRequirementChangeIterator struct {
+	Event *MultiSigRequirementChange // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log       // Log channel receiving the found contract events
+	sub  cpchain.Subscription // Subscription for errors, completion and termination
+	done bool                 // Whether the subscription completed delivering logs
+	fail error                // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *MultiSigRequirementChangeIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(MultiSigRequirementChange)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(MultiSigRequirementChange)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *MultiSigRequirementChangeIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *MultiSigRequirementChangeIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// MultiSigRequirementChange represents a RequirementChange event raised by the MultiSig contract.
+type MultiSigRequirementChange struct {
+	Required *big.Int
+	Raw      types.Log // Blockchain specific contextual infos
+}
+
+// FilterRequirementChange is a free log retrieval operation binding the contract event 0xa3f1ee9126a074d9326c682f561767f710e927faa811f7a99829d49dc421797a.
+//
+// Solidity: e RequirementChange(required uint256)
+func (_MultiSig *MultiSigFilterer) FilterRequirementChange(opts *bind.FilterOpts) (*MultiSigRequirementChangeIterator, error) {
+
+	logs, sub, err := _MultiSig.contract.FilterLogs(opts, "RequirementChange")
+	if err != nil {
+		return nil, err
+	}
+	return &MultiSigRequirementChangeIterator{contract: _MultiSig.contract, event: "RequirementChange", logs: logs, sub: sub}, nil
+}
+
+// WatchRequirementChange is a free log subscription operation binding the contract event 0xa3f1ee9126a074d9326c682f561767f710e927faa811f7a99829d49dc421797a.
+//
+// Solidity: e RequirementChange(required uint256)
+func (_MultiSig *MultiSigFilterer) WatchRequirementChange(opts *bind.WatchOpts, sink chan<- *MultiSigRequirementChange) (event.Subscription, error) {
+
+	logs, sub, err := _MultiSig.contract.WatchLogs(opts, "RequirementChange")
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(MultiSigRequirementChange)
+				if err := _MultiSig.contract.UnpackLog(event, "RequirementChange", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// StreamRequirementChange is a reorg-aware log subscription operation binding the contract event 0xa3f1ee9126a074d9326c682f561767f710e927faa811f7a99829d49dc421797a,
+// resuming from the cursor of opts. Events reverted by a reorg are delivered again with Raw.Removed set.
+//
+// Solidity: e RequirementChange(required uint256)
+func (_MultiSig *MultiSigFilterer) StreamRequirementChange(opts *bind.StreamOpts, sink chan<- *MultiSigRequirementChange) (event.Subscription, error) {
+
+	logs, sub, err := _MultiSig.contract.StreamLogs(opts, "RequirementChange")
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New or reverted log arrived, parse the event and forward to the user
+				event := new(MultiSigRequirementChange)
+				if err := _MultiSig.contract.UnpackLog(event, "RequirementChange", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// MultiSigRevocationIterator is returned from FilterRevocation and is used to iterate over the raw logs and unpacked data for Revocation events raised by the MultiSig contract.
+type MultiSigRevocationIterator struct {
+	Event *MultiSigRevocation // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log       // Log channel receiving the found contract events
+	sub  cpchain.Subscription // Subscription for errors, completion and termination
+	done bool                 // Whether the subscription completed delivering logs
+	fail error                // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *MultiSigRevocationIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(MultiSigRevocation)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(MultiSigRevocation)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *MultiSigRevocationIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *MultiSigRevocationIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// MultiSigRevocation represents a Revocation event raised by the MultiSig contract.
+type MultiSigRevocation struct {
+	Sender        common.Address
+	TransactionId *big.Int
+	Raw           types.Log // Blockchain specific contextual infos
+}
+
+// FilterRevocation is a free log retrieval operation binding the contract event 0xf6a317157440607f36269043eb55f1287a5a19ba2216afeab88cd46cbcfb88e9.
+//
+// Solidity: e Revocation(sender indexed address, transactionId indexed uint256)
+func (_MultiSig *MultiSigFilterer) FilterRevocation(opts *bind.FilterOpts, sender []common.Address, transactionId []*big.Int) (*MultiSigRevocationIterator, error) {
+
+	var senderRule []interface{}
+	for _, senderItem := range sender {
+		senderRule = append(senderRule, senderItem)
+	}
+	var transactionIdRule []interface{}
+	for _, transactionIdItem := range transactionId {
+		transactionIdRule = append(transactionIdRule, transactionIdItem)
+	}
+
+	logs, sub, err := _MultiSig.contract.FilterLogs(opts, "Revocation", senderRule, transactionIdRule)
+	if err != nil {
+		return nil, err
+	}
+	return &MultiSigRevocationIterator{contract: _MultiSig.contract, event: "Revocation", logs: logs, sub: sub}, nil
+}
+
+// WatchRevocation is a free log subscription operation binding the contract event 0xf6a317157440607f36269043eb55f1287a5a19ba2216afeab88cd46cbcfb88e9.
+//
+// Solidity: e Revocation(sender indexed address, transactionId indexed uint256)
+func (_MultiSig *MultiSigFilterer) WatchRevocation(opts *bind.WatchOpts, sink chan<- *MultiSigRevocation, sender []common.Address, transactionId []*big.Int) (event.Subscription, error) {
+
+	var senderRule []interface{}
+	for _, senderItem := range sender {
+		senderRule = append(senderRule, senderItem)
+	}
+	var transactionIdRule []interface{}
+	for _, transactionIdItem := range transactionId {
+		transactionIdRule = append(transactionIdRule, transactionIdItem)
+	}
+
+	logs, sub, err := _MultiSig.contract.WatchLogs(opts, "Revocation", senderRule, transactionIdRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(MultiSigRevocation)
+				if err := _MultiSig.contract.UnpackLog(event, "Revocation", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// StreamRevocation is a reorg-aware log subscription operation binding the contract event 0xf6a317157440607f36269043eb55f1287a5a19ba2216afeab88cd46cbcfb88e9,
+// resuming from the cursor of opts. Events reverted by a reorg are delivered again with Raw.Removed set.
+//
+// Solidity: e Revocation(sender indexed address, transactionId indexed uint256)
+func (_MultiSig *MultiSigFilterer) StreamRevocation(opts *bind.StreamOpts, sink chan<- *MultiSigRevocation, sender []common.Address, transactionId []*big.Int) (event.Subscription, error) {
+
+	var senderRule []interface{}
+	for _, senderItem := range sender {
+		senderRule = append(senderRule, senderItem)
+	}
+	var transactionIdRule []interface{}
+	for _, transactionIdItem := range transactionId {
+		transactionIdRule = append(transactionIdRule, transactionIdItem)
+	}
+
+	logs, sub, err := _MultiSig.contract.StreamLogs(opts, "Revocation", senderRule, transactionIdRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New or reverted log arrived, parse the event and forward to the user
+				event := new(MultiSigRevocation)
+				if err := _MultiSig.contract.UnpackLog(event, "Revocation", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// MultiSigSubmissionIterator is returned from FilterSubmission and is used to iterate over the raw logs and unpacked data for Submission events raised by the MultiSig contract.
+type MultiSigSubmissionIterator struct {
+	Event *MultiSigSubmission // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log       // Log channel receiving the found contract events
+	sub  cpchain.Subscription // Subscription for errors, completion and termination
+	done bool                 // Whether the subscription completed delivering logs
+	fail error                // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *MultiSigSubmissionIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(MultiSigSubmission)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(MultiSigSubmission)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *MultiSigSubmissionIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *MultiSigSubmissionIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// MultiSigSubmission represents a Submission event raised by the MultiSig contract.
+type MultiSigSubmission struct {
+	TransactionId *big.Int
+	Raw           types.Log // Blockchain specific contextual infos
+}
+
+// FilterSubmission is a free log retrieval operation binding the contract event 0xc0ba8fe4b176c1714197d43b9cc6bcf797a4a7461c5fe8d0ef6e184ae7601e51.
+//
+// Solidity: e Submission(transactionId indexed uint256)
+func (_MultiSig *MultiSigFilterer) FilterSubmission(opts *bind.FilterOpts, transactionId []*big.Int) (*MultiSigSubmissionIterator, error) {
+
+	var transactionIdRule []interface{}
+	for _, transactionIdItem := range transactionId {
+		transactionIdRule = append(transactionIdRule, transactionIdItem)
+	}
+
+	logs, sub, err := _MultiSig.contract.FilterLogs(opts, "Submission", transactionIdRule)
+	if err != nil {
+		return nil, err
+	}
+	return &MultiSigSubmissionIterator{contract: _MultiSig.contract, event: "Submission", logs: logs, sub: sub}, nil
+}
+
+// WatchSubmission is a free log subscription operation binding the contract event 0xc0ba8fe4b176c1714197d43b9cc6bcf797a4a7461c5fe8d0ef6e184ae7601e51.
+//
+// Solidity: e Submission(transactionId indexed uint256)
+func (_MultiSig *MultiSigFilterer) WatchSubmission(opts *bind.WatchOpts, sink chan<- *MultiSigSubmission, transactionId []*big.Int) (event.Subscription, error) {
+
+	var transactionIdRule []interface{}
+	for _, transactionIdItem := range transactionId {
+		transactionIdRule = append(transactionIdRule, transactionIdItem)
+	}
+
+	logs, sub, err := _MultiSig.contract.WatchLogs(opts, "Submission", transactionIdRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(MultiSigSubmission)
+				if err := _MultiSig.contract.UnpackLog(event, "Submission", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// StreamSubmission is a reorg-aware log subscription operation binding the contract event 0xc0ba8fe4b176c1714197d43b9cc6bcf797a4a7461c5fe8d0ef6e184ae7601e51,
+// resuming from the cursor of opts. Events reverted by a reorg are delivered again with Raw.Removed set.
+//
+// Solidity: e Submission(transactionId indexed uint256)
+func (_MultiSig *MultiSigFilterer) StreamSubmission(opts *bind.StreamOpts, sink chan<- *MultiSigSubmission, transactionId []*big.Int) (event.Subscription, error) {
+
+	var transactionIdRule []interface{}
+	for _, transactionIdItem := range transactionId {
+		transactionIdRule = append(transactionIdRule, transactionIdItem)
+	}
+
+	logs, sub, err := _MultiSig.contract.StreamLogs(opts, "Submission", transactionIdRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New or reverted log arrived, parse the event and forward to the user
+				event := new(MultiSigSubmission)
+				if err := _MultiSig.contract.UnpackLog(event, "Submission", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
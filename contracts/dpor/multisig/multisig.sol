@@ -0,0 +1,184 @@
+/**
+ * MultiSig is an M-of-N wallet administering the owner-only functions of the reward, rnode, campaign and
+ * proxy register contracts. Any owner submits a transaction, it is executed once `required` owners confirmed it.
+ *
+ * The administered contracts have no ownership transfer, their owner is the account deploying them. A contract
+ * is deployed by the wallet by submitting a transaction to address 0 with the creation code as data.
+**/
+
+pragma solidity ^0.4.24;
+
+contract MultiSig {
+    uint256 constant public MAX_OWNER_COUNT = 50;
+
+    event Confirmation(address indexed sender, uint256 indexed transactionId);
+    event Revocation(address indexed sender, uint256 indexed transactionId);
+    event Submission(uint256 indexed transactionId);
+    event Execution(uint256 indexed transactionId);
+    event ExecutionFailure(uint256 indexed transactionId);
+    event Creation(uint256 indexed transactionId, address contractAddress);
+    event Deposit(address indexed sender, uint256 value);
+    event OwnerAddition(address indexed owner);
+    event OwnerRemoval(address indexed owner);
+    event RequirementChange(uint256 required);
+
+    struct Transaction {
+        address destination; // address 0 creates a contract
+        uint256 value;
+        bytes data;
+        bool executed;
+    }
+
+    mapping (uint256 => Transaction) public transactions;
+    mapping (uint256 => mapping (address => bool)) public confirmations;
+    mapping (address => bool) public isOwner;
+    address[] public owners;
+    uint256 public required;
+    uint256 public transactionCount;
+
+    // owners are only changed by transactions of the wallet itself
+    modifier onlyWallet() {require(msg.sender == address(this));_;}
+    modifier ownerDoesNotExist(address owner) {require(!isOwner[owner]);_;}
+    modifier ownerExists(address owner) {require(isOwner[owner]);_;}
+    modifier transactionExists(uint256 transactionId) {require(transactionId < transactionCount);_;}
+    modifier confirmed(uint256 transactionId, address owner) {require(confirmations[transactionId][owner]);_;}
+    modifier notConfirmed(uint256 transactionId, address owner) {require(!confirmations[transactionId][owner]);_;}
+    modifier notExecuted(uint256 transactionId) {require(!transactions[transactionId].executed);_;}
+    modifier notNull(address _address) {require(_address != address(0));_;}
+
+    modifier validRequirement(uint256 ownerCount, uint256 _required) {
+        require(ownerCount <= MAX_OWNER_COUNT && _required <= ownerCount && _required != 0 && ownerCount != 0);
+        _;
+    }
+
+    constructor (address[] _owners, uint256 _required) public validRequirement(_owners.length, _required) {
+        for (uint256 i = 0; i < _owners.length; i++) {
+            require(!isOwner[_owners[i]] && _owners[i] != address(0));
+            isOwner[_owners[i]] = true;
+        }
+        owners = _owners;
+        required = _required;
+    }
+
+    function() public payable {
+        if (msg.value > 0) {
+            emit Deposit(msg.sender, msg.value);
+        }
+    }
+
+    function addOwner(address owner) public onlyWallet ownerDoesNotExist(owner) notNull(owner) validRequirement(owners.length + 1, required) {
+        isOwner[owner] = true;
+        owners.push(owner);
+        emit OwnerAddition(owner);
+    }
+
+    function removeOwner(address owner) public onlyWallet ownerExists(owner) {
+        isOwner[owner] = false;
+        for (uint256 i = 0; i < owners.length - 1; i++) {
+            if (owners[i] == owner) {
+                owners[i] = owners[owners.length - 1];
+                break;
+            }
+        }
+        owners.length -= 1;
+        if (required > owners.length) {
+            changeRequirement(owners.length);
+        }
+        emit OwnerRemoval(owner);
+    }
+
+    function changeRequirement(uint256 _required) public onlyWallet validRequirement(owners.length, _required) {
+        required = _required;
+        emit RequirementChange(_required);
+    }
+
+    // submitTransaction adds a transaction and confirms it by the sender
+    function submitTransaction(address destination, uint256 value, bytes data) public ownerExists(msg.sender) returns (uint256 transactionId) {
+        transactionId = transactionCount;
+        transactions[transactionId] = Transaction({
+            destination: destination,
+            value: value,
+            data: data,
+            executed: false
+        });
+        transactionCount += 1;
+        emit Submission(transactionId);
+        confirmTransaction(transactionId);
+    }
+
+    function confirmTransaction(uint256 transactionId) public ownerExists(msg.sender) transactionExists(transactionId) notConfirmed(transactionId, msg.sender) {
+        confirmations[transactionId][msg.sender] = true;
+        emit Confirmation(msg.sender, transactionId);
+        executeTransaction(transactionId);
+    }
+
+    function revokeConfirmation(uint256 transactionId) public ownerExists(msg.sender) confirmed(transactionId, msg.sender) notExecuted(transactionId) {
+        confirmations[transactionId][msg.sender] = false;
+        emit Revocation(msg.sender, transactionId);
+    }
+
+    // executeTransaction executes a confirmed transaction, a failed one may be executed again
+    function executeTransaction(uint256 transactionId) public ownerExists(msg.sender) confirmed(transactionId, msg.sender) notExecuted(transactionId) {
+        if (!isConfirmed(transactionId)) {
+            return;
+        }
+        Transaction storage txn = transactions[transactionId];
+        txn.executed = true;
+        if (txn.destination == address(0)) {
+            address created = deploy(txn.value, txn.data);
+            if (created != address(0)) {
+                emit Creation(transactionId, created);
+                emit Execution(transactionId);
+                return;
+            }
+        } else if (txn.destination.call.value(txn.value)(txn.data)) {
+            emit Execution(transactionId);
+            return;
+        }
+        emit ExecutionFailure(transactionId);
+        txn.executed = false;
+    }
+
+    function deploy(uint256 value, bytes code) internal returns (address created) {
+        assembly {
+            created := create(value, add(code, 0x20), mload(code))
+        }
+    }
+
+    function isConfirmed(uint256 transactionId) public view returns (bool) {
+        uint256 count = 0;
+        for (uint256 i = 0; i < owners.length; i++) {
+            if (confirmations[transactionId][owners[i]]) {
+                count += 1;
+            }
+            if (count == required) {
+                return true;
+            }
+        }
+        return false;
+    }
+
+    function getConfirmationCount(uint256 transactionId) public view returns (uint256 count) {
+        for (uint256 i = 0; i < owners.length; i++) {
+            if (confirmations[transactionId][owners[i]]) {
+                count += 1;
+            }
+        }
+    }
+
+    function getConfirmations(uint256 transactionId) public view returns (address[] _confirmations) {
+        uint256 count = getConfirmationCount(transactionId);
+        _confirmations = new address[](count);
+        uint256 j = 0;
+        for (uint256 i = 0; i < owners.length; i++) {
+            if (confirmations[transactionId][owners[i]]) {
+                _confirmations[j] = owners[i];
+                j += 1;
+            }
+        }
+    }
+
+    function getOwners() public view returns (address[]) {
+        return owners;
+    }
+}
@@ -0,0 +1,71 @@
+pragma solidity ^0.4.24;
+
+// PeriodGovernor schedules the block period of future terms. A change takes
+// effect at the first block of the given term, which must be at least one
+// election ahead so that every node loads it into its snapshot before the
+// term starts.
+contract PeriodGovernor {
+
+    // TermDistBetweenElectionAndMining + 1 terms, see consensus/dpor/snapshot.go
+    uint constant electionDistance = 3;
+
+    struct Change {
+        uint term;
+        uint period;
+    }
+
+    address public owner;
+
+    uint public blocksPerTerm; // TermLen * ViewLen
+
+    // bounds of the periods in milliseconds
+    uint public minPeriod;
+    uint public maxPeriod;
+
+    // changes in order of effective terms
+    Change[] changes;
+
+    modifier onlyOwner() {require(msg.sender == owner);_;}
+
+    event PeriodChanged(uint term, uint period);
+
+    constructor (uint _blocksPerTerm, uint _minPeriod, uint _maxPeriod) public {
+        require(_blocksPerTerm > 0);
+        require(_minPeriod > 0 && _minPeriod <= _maxPeriod);
+        owner = msg.sender;
+        blocksPerTerm = _blocksPerTerm;
+        minPeriod = _minPeriod;
+        maxPeriod = _maxPeriod;
+    }
+
+    function currentTerm() public view returns (uint) {
+        if (block.number == 0) {
+            return 0;
+        }
+        return (block.number - 1) / blocksPerTerm;
+    }
+
+    // setPeriod sets the block period from term on, in milliseconds.
+    function setPeriod(uint term, uint period) public onlyOwner {
+        require(term > currentTerm() + electionDistance);
+        require(period >= minPeriod && period <= maxPeriod);
+
+        // pending changes are replaced
+        while (changes.length > 0 && changes[changes.length - 1].term >= term) {
+            changes.length--;
+        }
+        changes.push(Change(term, period));
+
+        emit PeriodChanged(term, period);
+    }
+
+    // periodOf returns the block period of the term, 0 if none is set.
+    function periodOf(uint term) public view returns (uint) {
+        for (uint i = changes.length; i > 0; i--) {
+            if (changes[i - 1].term <= term) {
+                return changes[i - 1].period;
+            }
+        }
+        return 0;
+    }
+}
@@ -58,5 +58,6 @@ func MakePrimitiveContracts(contractClient bind.ContractBackend, chainClient *rp
 	contracts[common.BytesToAddress([]byte{105})] = &primitives.IsProxy{Backend: RptEvaluator}
 	contracts[common.BytesToAddress([]byte{106})] = &primitives.CpuPowValidate{}
 	contracts[common.BytesToAddress([]byte{107})] = &primitives.MemPowValidate{}
+	contracts[common.BytesToAddress([]byte{108})] = &primitives.StorageValidate{}
 	return contracts
 }
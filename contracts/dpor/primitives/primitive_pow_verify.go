@@ -49,6 +49,23 @@ func (m *CpuPowValidate) Run(input []byte) ([]byte, error) {
 	}
 }
 
+// StorageValidate does a storage proof validation, the nonce is the index
+// of a plot record.
+type StorageValidate struct{}
+
+func (m *StorageValidate) RequiredGas(input []byte) uint64 {
+	return configs.StorageValidateGas
+}
+
+func (m *StorageValidate) Run(input []byte) ([]byte, error) {
+	address, nonce, blockHash, difficulty := unpackPowValidateArgs(input)
+	if admission.ValidateStorage(address, blockHash, nonce, difficulty) {
+		return true32Byte, nil
+	} else {
+		return false32Byte, nil
+	}
+}
+
 func unpackPowValidateArgs(input []byte) (address common.Address, nonce uint64, blockHash []byte, difficulty uint64) {
 	address = common.BytesToAddress(input[12:32])
 	nonce = new(big.Int).SetBytes(input[32:64]).Uint64()
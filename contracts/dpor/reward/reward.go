@@ -904,6 +904,44 @@ func (_Reward *RewardFilterer) WatchContinuedInvest(opts *bind.WatchOpts, sink c
 	}), nil
 }
 
+// StreamContinuedInvest is a reorg-aware log subscription operation binding the contract event 0x2772659b237083773d3a2874ab3591def1a8625215ae057bde8fc4ef3dee7290,
+// resuming from the cursor of opts. Events reverted by a reorg are delivered again with Raw.Removed set.
+//
+// Solidity: e ContinuedInvest(_addr address, _iscontinue bool)
+func (_Reward *RewardFilterer) StreamContinuedInvest(opts *bind.StreamOpts, sink chan<- *RewardContinuedInvest) (event.Subscription, error) {
+
+	logs, sub, err := _Reward.contract.StreamLogs(opts, "ContinuedInvest")
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New or reverted log arrived, parse the event and forward to the user
+				event := new(RewardContinuedInvest)
+				if err := _Reward.contract.UnpackLog(event, "ContinuedInvest", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
 // RewardDepositInsufficientIterator is returned from FilterDepositInsufficient and is used to iterate over the raw logs and unpacked data for DepositInsufficient events raised by the Reward contract.
 type RewardDepositInsufficientIterator struct {
 	Event *RewardDepositInsufficient // Event containing the contract specifics and raw log
@@ -1027,6 +1065,44 @@ func (_Reward *RewardFilterer) WatchDepositInsufficient(opts *bind.WatchOpts, si
 	}), nil
 }
 
+// StreamDepositInsufficient is a reorg-aware log subscription operation binding the contract event 0x9873c485f5a9e0be9a918f4d6ad5b64912fcb8352006b316a63427b1f408e824,
+// resuming from the cursor of opts. Events reverted by a reorg are delivered again with Raw.Removed set.
+//
+// Solidity: e DepositInsufficient(who address, value uint256)
+func (_Reward *RewardFilterer) StreamDepositInsufficient(opts *bind.StreamOpts, sink chan<- *RewardDepositInsufficient) (event.Subscription, error) {
+
+	logs, sub, err := _Reward.contract.StreamLogs(opts, "DepositInsufficient")
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New or reverted log arrived, parse the event and forward to the user
+				event := new(RewardDepositInsufficient)
+				if err := _Reward.contract.UnpackLog(event, "DepositInsufficient", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
 // RewardFundBonusPoolIterator is returned from FilterFundBonusPool and is used to iterate over the raw logs and unpacked data for FundBonusPool events raised by the Reward contract.
 type RewardFundBonusPoolIterator struct {
 	Event *RewardFundBonusPool // Event containing the contract specifics and raw log
@@ -1149,6 +1225,44 @@ func (_Reward *RewardFilterer) WatchFundBonusPool(opts *bind.WatchOpts, sink cha
 	}), nil
 }
 
+// StreamFundBonusPool is a reorg-aware log subscription operation binding the contract event 0x71030773066b852afef8d0f98dbfdaec8e9a62f2f5533916ec7dfa15a0edc1f2,
+// resuming from the cursor of opts. Events reverted by a reorg are delivered again with Raw.Removed set.
+//
+// Solidity: e FundBonusPool(value uint256)
+func (_Reward *RewardFilterer) StreamFundBonusPool(opts *bind.StreamOpts, sink chan<- *RewardFundBonusPool) (event.Subscription, error) {
+
+	logs, sub, err := _Reward.contract.StreamLogs(opts, "FundBonusPool")
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New or reverted log arrived, parse the event and forward to the user
+				event := new(RewardFundBonusPool)
+				if err := _Reward.contract.UnpackLog(event, "FundBonusPool", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
 // RewardJoinENodesIterator is returned from FilterJoinENodes and is used to iterate over the raw logs and unpacked data for JoinENodes events raised by the Reward contract.
 type RewardJoinENodesIterator struct {
 	Event *RewardJoinENodes // Event containing the contract specifics and raw log
@@ -1272,6 +1386,44 @@ func (_Reward *RewardFilterer) WatchJoinENodes(opts *bind.WatchOpts, sink chan<-
 	}), nil
 }
 
+// StreamJoinENodes is a reorg-aware log subscription operation binding the contract event 0xc77e37a0f773afcb213afe8a3075752e50740f11368b2098fcffb6b99dd43978,
+// resuming from the cursor of opts. Events reverted by a reorg are delivered again with Raw.Removed set.
+//
+// Solidity: e JoinENodes(who address, value uint256)
+func (_Reward *RewardFilterer) StreamJoinENodes(opts *bind.StreamOpts, sink chan<- *RewardJoinENodes) (event.Subscription, error) {
+
+	logs, sub, err := _Reward.contract.StreamLogs(opts, "JoinENodes")
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New or reverted log arrived, parse the event and forward to the user
+				event := new(RewardJoinENodes)
+				if err := _Reward.contract.UnpackLog(event, "JoinENodes", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
 // RewardJoinRNodesIterator is returned from FilterJoinRNodes and is used to iterate over the raw logs and unpacked data for JoinRNodes events raised by the Reward contract.
 type RewardJoinRNodesIterator struct {
 	Event *RewardJoinRNodes // Event containing the contract specifics and raw log
@@ -1395,6 +1547,44 @@ func (_Reward *RewardFilterer) WatchJoinRNodes(opts *bind.WatchOpts, sink chan<-
 	}), nil
 }
 
+// StreamJoinRNodes is a reorg-aware log subscription operation binding the contract event 0xd8bcb238cc6e3b6f0e56058422877e35c9b0c97497d911cd98ec09ea45b4e623,
+// resuming from the cursor of opts. Events reverted by a reorg are delivered again with Raw.Removed set.
+//
+// Solidity: e JoinRNodes(who address, value uint256)
+func (_Reward *RewardFilterer) StreamJoinRNodes(opts *bind.StreamOpts, sink chan<- *RewardJoinRNodes) (event.Subscription, error) {
+
+	logs, sub, err := _Reward.contract.StreamLogs(opts, "JoinRNodes")
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New or reverted log arrived, parse the event and forward to the user
+				event := new(RewardJoinRNodes)
+				if err := _Reward.contract.UnpackLog(event, "JoinRNodes", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
 // RewardNewRaiseIterator is returned from FilterNewRaise and is used to iterate over the raw logs and unpacked data for NewRaise events raised by the Reward contract.
 type RewardNewRaiseIterator struct {
 	Event *RewardNewRaise // Event containing the contract specifics and raw log
@@ -1519,6 +1709,44 @@ func (_Reward *RewardFilterer) WatchNewRaise(opts *bind.WatchOpts, sink chan<- *
 	}), nil
 }
 
+// StreamNewRaise is a reorg-aware log subscription operation binding the contract event 0xc8ea7d3c44e48dda18a813373040ce0eda7c908ad2cd30b53310d9b4b3001214,
+// resuming from the cursor of opts. Events reverted by a reorg are delivered again with Raw.Removed set.
+//
+// Solidity: e NewRaise(round uint256, lock bool, _bonusPool uint256)
+func (_Reward *RewardFilterer) StreamNewRaise(opts *bind.StreamOpts, sink chan<- *RewardNewRaise) (event.Subscription, error) {
+
+	logs, sub, err := _Reward.contract.StreamLogs(opts, "NewRaise")
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New or reverted log arrived, parse the event and forward to the user
+				event := new(RewardNewRaise)
+				if err := _Reward.contract.UnpackLog(event, "NewRaise", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
 // RewardSubmitDepositIterator is returned from FilterSubmitDeposit and is used to iterate over the raw logs and unpacked data for SubmitDeposit events raised by the Reward contract.
 type RewardSubmitDepositIterator struct {
 	Event *RewardSubmitDeposit // Event containing the contract specifics and raw log
@@ -1642,6 +1870,44 @@ func (_Reward *RewardFilterer) WatchSubmitDeposit(opts *bind.WatchOpts, sink cha
 	}), nil
 }
 
+// StreamSubmitDeposit is a reorg-aware log subscription operation binding the contract event 0x78d81951b78dad84771f88d35b4c93a632e1ed2da8706bbc7d8e465110686830,
+// resuming from the cursor of opts. Events reverted by a reorg are delivered again with Raw.Removed set.
+//
+// Solidity: e SubmitDeposit(who address, value uint256)
+func (_Reward *RewardFilterer) StreamSubmitDeposit(opts *bind.StreamOpts, sink chan<- *RewardSubmitDeposit) (event.Subscription, error) {
+
+	logs, sub, err := _Reward.contract.StreamLogs(opts, "SubmitDeposit")
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New or reverted log arrived, parse the event and forward to the user
+				event := new(RewardSubmitDeposit)
+				if err := _Reward.contract.UnpackLog(event, "SubmitDeposit", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
 // RewardTransferDepositIterator is returned from FilterTransferDeposit and is used to iterate over the raw logs and unpacked data for TransferDeposit events raised by the Reward contract.
 type RewardTransferDepositIterator struct {
 	Event *RewardTransferDeposit // Event containing the contract specifics and raw log
@@ -1765,6 +2031,44 @@ func (_Reward *RewardFilterer) WatchTransferDeposit(opts *bind.WatchOpts, sink c
 	}), nil
 }
 
+// StreamTransferDeposit is a reorg-aware log subscription operation binding the contract event 0x65134cf3b0cc43a1e4a814449241d36665e5774b4c36f7747755a62cf02493d5,
+// resuming from the cursor of opts. Events reverted by a reorg are delivered again with Raw.Removed set.
+//
+// Solidity: e TransferDeposit(who address, value uint256)
+func (_Reward *RewardFilterer) StreamTransferDeposit(opts *bind.StreamOpts, sink chan<- *RewardTransferDeposit) (event.Subscription, error) {
+
+	logs, sub, err := _Reward.contract.StreamLogs(opts, "TransferDeposit")
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New or reverted log arrived, parse the event and forward to the user
+				event := new(RewardTransferDeposit)
+				if err := _Reward.contract.UnpackLog(event, "TransferDeposit", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
 // RewardWithdrawDepositIterator is returned from FilterWithdrawDeposit and is used to iterate over the raw logs and unpacked data for WithdrawDeposit events raised by the Reward contract.
 type RewardWithdrawDepositIterator struct {
 	Event *RewardWithdrawDeposit // Event containing the contract specifics and raw log
@@ -1888,6 +2192,44 @@ func (_Reward *RewardFilterer) WatchWithdrawDeposit(opts *bind.WatchOpts, sink c
 	}), nil
 }
 
+// StreamWithdrawDeposit is a reorg-aware log subscription operation binding the contract event 0x195ddc41d185a27fe901831dcad44dd85716c95be78b1d71aa42393697966d40,
+// resuming from the cursor of opts. Events reverted by a reorg are delivered again with Raw.Removed set.
+//
+// Solidity: e WithdrawDeposit(who address, value uint256)
+func (_Reward *RewardFilterer) StreamWithdrawDeposit(opts *bind.StreamOpts, sink chan<- *RewardWithdrawDeposit) (event.Subscription, error) {
+
+	logs, sub, err := _Reward.contract.StreamLogs(opts, "WithdrawDeposit")
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New or reverted log arrived, parse the event and forward to the user
+				event := new(RewardWithdrawDeposit)
+				if err := _Reward.contract.UnpackLog(event, "WithdrawDeposit", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
 // SafeMathABI is the input ABI used to generate the binding from.
 const SafeMathABI = "[]"
 
@@ -88,7 +88,7 @@ func (cc *RptApiClient) HeaderByNumber(ctx context.Context, number *big.Int) (*t
 }
 
 func (cc *RptApiClient) CodeAt(ctx context.Context, account common.Address, blockNumber *big.Int) ([]byte, error) {
-	blockNr := rpc.LatestBlockNumber
+	blockNr := toBlockNumber(blockNumber)
 	state, _, err := cc.ChainBackend.StateAndHeaderByNumber(ctx, blockNr, false)
 	if state == nil || err != nil {
 		return nil, err
@@ -97,13 +97,28 @@ func (cc *RptApiClient) CodeAt(ctx context.Context, account common.Address, bloc
 	return code, state.Error()
 }
 
+// CallContract executes a contract call at the given block, the latest one
+// if blockNumber is nil.
 func (cc *RptApiClient) CallContract(ctx context.Context, call cpchain.CallMsg, blockNumber *big.Int) ([]byte, error) {
-	result, err := cc.ContractBackend.Call(ctx, toCallArg(call), rpc.LatestBlockNumber)
+	result, err := cc.ContractBackend.Call(ctx, toCallArg(call), toBlockNumber(blockNumber))
 	if err != nil {
+		// historical state may be missing, callers handle the error
+		if blockNumber != nil {
+			log.Warn("CallContract using PublicBlockChainAPI is error ", "number", blockNumber, "error is ", err)
+			return nil, err
+		}
 		log.Fatal("CallContract using PublicBlockChainAPI is error ", "error is ", err)
 	}
 	return result, err
 }
+
+// toBlockNumber converts a block number, nil for the latest block.
+func toBlockNumber(number *big.Int) rpc.BlockNumber {
+	if number == nil {
+		return rpc.LatestBlockNumber
+	}
+	return rpc.BlockNumber(number.Int64())
+}
 func toCallArg(msg cpchain.CallMsg) cpcapi.CallArgs {
 	arg := cpcapi.CallArgs{
 		From: msg.From,
@@ -0,0 +1,46 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package signer_register
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+
+	"bitbucket.org/cpchain/chain/accounts/abi/bind"
+	"bitbucket.org/cpchain/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// RotationHash returns the hash the new signing key signs to prove its
+// possession, keccak256(identity, term) as packed by the register.
+func RotationHash(identity common.Address, term uint64) common.Hash {
+	return crypto.Keccak256Hash(identity.Bytes(), common.LeftPadBytes(new(big.Int).SetUint64(term).Bytes(), 32))
+}
+
+// RotateSignerWithKey binds the signing key to the identity sending the transaction
+// from the given term on.
+func (_SignerRegister *SignerRegisterTransactor) RotateSignerWithKey(opts *bind.TransactOpts, signer *ecdsa.PrivateKey, term uint64) (*types.Transaction, error) {
+	sig, err := crypto.Sign(RotationHash(opts.From, term).Bytes(), signer)
+	if err != nil {
+		return nil, err
+	}
+	var r, s [32]byte
+	copy(r[:], sig[:32])
+	copy(s[:], sig[32:64])
+	return _SignerRegister.RotateSigner(opts, crypto.PubkeyToAddress(signer.PublicKey), new(big.Int).SetUint64(term), sig[64]+27, r, s)
+}
@@ -0,0 +1,81 @@
+pragma solidity ^0.4.24;
+
+// SignerRegister binds a new signing key to the identity of a proposer or a
+// validator, i.e. the coinbase it campaigned with. The binding takes effect at
+// the first block of the given term, which must be at least one election ahead
+// so that every node loads it into its snapshot before the term starts.
+contract SignerRegister {
+
+    // TermDistBetweenElectionAndMining + 1 terms, see consensus/dpor/snapshot.go
+    uint constant electionDistance = 3;
+
+    struct Binding {
+        address signer;
+        uint term;
+    }
+
+    uint public blocksPerTerm; // TermLen * ViewLen
+
+    // identity => signing keys in order of effective terms
+    mapping(address => Binding[]) bindings;
+
+    // signing key => identity
+    mapping(address => address) public identityOf;
+
+    event SignerRotated(address indexed identity, address indexed signer, uint term);
+
+    constructor (uint _blocksPerTerm) public {
+        require(_blocksPerTerm > 0);
+        blocksPerTerm = _blocksPerTerm;
+    }
+
+    function currentTerm() public view returns (uint) {
+        if (block.number == 0) {
+            return 0;
+        }
+        return (block.number - 1) / blocksPerTerm;
+    }
+
+    // rotateSigner binds signer to the sender from term on. The signer proves
+    // possession of its key by signing keccak256(sender, term).
+    function rotateSigner(address signer, uint term, uint8 v, bytes32 r, bytes32 s) public {
+        require(signer != address(0));
+        require(term > currentTerm() + electionDistance);
+        require(identityOf[signer] == address(0) || identityOf[signer] == msg.sender);
+        // identities cannot sign for another identity
+        require(bindings[signer].length == 0);
+
+        bytes32 hash = keccak256(abi.encodePacked(msg.sender, term));
+        require(ecrecover(hash, v, r, s) == signer);
+
+        Binding[] storage bs = bindings[msg.sender];
+        // pending bindings are replaced
+        while (bs.length > 0 && bs[bs.length - 1].term >= term) {
+            bs.length--;
+        }
+        bs.push(Binding(signer, term));
+        identityOf[signer] = msg.sender;
+
+        emit SignerRotated(msg.sender, signer, term);
+    }
+
+    // signerOf returns the key signing for identity in the term, the identity
+    // itself if no signing key is bound to it.
+    function signerOf(address identity, uint term) public view returns (address) {
+        Binding[] storage bs = bindings[identity];
+        for (uint i = bs.length; i > 0; i--) {
+            if (bs[i - 1].term <= term) {
+                return bs[i - 1].signer;
+            }
+        }
+        return identity;
+    }
+
+    function getSignersOf(address[] identities, uint term) public view returns (address[]) {
+        address[] memory signers = new address[](identities.length);
+        for (uint i = 0; i < identities.length; i++) {
+            signers[i] = signerOf(identities[i], term);
+        }
+        return signers;
+    }
+}
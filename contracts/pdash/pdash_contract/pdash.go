@@ -988,6 +988,44 @@ func (_Pdash *PdashFilterer) WatchBuyerConfirmed(opts *bind.WatchOpts, sink chan
 	}), nil
 }
 
+// StreamBuyerConfirmed is a reorg-aware log subscription operation binding the contract event 0xd87dd92b1de3627ad322286c5c45566583bef03d0334ca6f0c9a9db7f7c0f16b,
+// resuming from the cursor of opts. Events reverted by a reorg are delivered again with Raw.Removed set.
+//
+// Solidity: e BuyerConfirmed(orderId uint256, time uint256)
+func (_Pdash *PdashFilterer) StreamBuyerConfirmed(opts *bind.StreamOpts, sink chan<- *PdashBuyerConfirmed) (event.Subscription, error) {
+
+	logs, sub, err := _Pdash.contract.StreamLogs(opts, "BuyerConfirmed")
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New or reverted log arrived, parse the event and forward to the user
+				event := new(PdashBuyerConfirmed)
+				if err := _Pdash.contract.UnpackLog(event, "BuyerConfirmed", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
 // PdashBuyerDisputedIterator is returned from FilterBuyerDisputed and is used to iterate over the raw logs and unpacked data for BuyerDisputed events raised by the Pdash contract.
 type PdashBuyerDisputedIterator struct {
 	Event *PdashBuyerDisputed // Event containing the contract specifics and raw log
@@ -1111,6 +1149,44 @@ func (_Pdash *PdashFilterer) WatchBuyerDisputed(opts *bind.WatchOpts, sink chan<
 	}), nil
 }
 
+// StreamBuyerDisputed is a reorg-aware log subscription operation binding the contract event 0xa6c88e175a49cd3945a40a26a490ae07ede7d4eb825c4f26a7ff37a464d05d35,
+// resuming from the cursor of opts. Events reverted by a reorg are delivered again with Raw.Removed set.
+//
+// Solidity: e BuyerDisputed(orderId uint256, time uint256)
+func (_Pdash *PdashFilterer) StreamBuyerDisputed(opts *bind.StreamOpts, sink chan<- *PdashBuyerDisputed) (event.Subscription, error) {
+
+	logs, sub, err := _Pdash.contract.StreamLogs(opts, "BuyerDisputed")
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New or reverted log arrived, parse the event and forward to the user
+				event := new(PdashBuyerDisputed)
+				if err := _Pdash.contract.UnpackLog(event, "BuyerDisputed", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
 // PdashOrderFinishedIterator is returned from FilterOrderFinished and is used to iterate over the raw logs and unpacked data for OrderFinished events raised by the Pdash contract.
 type PdashOrderFinishedIterator struct {
 	Event *PdashOrderFinished // Event containing the contract specifics and raw log
@@ -1234,6 +1310,44 @@ func (_Pdash *PdashFilterer) WatchOrderFinished(opts *bind.WatchOpts, sink chan<
 	}), nil
 }
 
+// StreamOrderFinished is a reorg-aware log subscription operation binding the contract event 0x581a6384d4701bb245eaf6ebd9afd551a8c4f9d4e4ec70b1dfbab15569272bba,
+// resuming from the cursor of opts. Events reverted by a reorg are delivered again with Raw.Removed set.
+//
+// Solidity: e OrderFinished(orderId uint256, time uint256)
+func (_Pdash *PdashFilterer) StreamOrderFinished(opts *bind.StreamOpts, sink chan<- *PdashOrderFinished) (event.Subscription, error) {
+
+	logs, sub, err := _Pdash.contract.StreamLogs(opts, "OrderFinished")
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New or reverted log arrived, parse the event and forward to the user
+				event := new(PdashOrderFinished)
+				if err := _Pdash.contract.UnpackLog(event, "OrderFinished", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
 // PdashOrderInitiatedIterator is returned from FilterOrderInitiated and is used to iterate over the raw logs and unpacked data for OrderInitiated events raised by the Pdash contract.
 type PdashOrderInitiatedIterator struct {
 	Event *PdashOrderInitiated // Event containing the contract specifics and raw log
@@ -1359,6 +1473,44 @@ func (_Pdash *PdashFilterer) WatchOrderInitiated(opts *bind.WatchOpts, sink chan
 	}), nil
 }
 
+// StreamOrderInitiated is a reorg-aware log subscription operation binding the contract event 0xca46e2845b8de28445b9ac838c4fe91c25ecde13a4d5661c14acf05fcc89d7d9,
+// resuming from the cursor of opts. Events reverted by a reorg are delivered again with Raw.Removed set.
+//
+// Solidity: e OrderInitiated(from address, orderId uint256, value uint256, time uint256)
+func (_Pdash *PdashFilterer) StreamOrderInitiated(opts *bind.StreamOpts, sink chan<- *PdashOrderInitiated) (event.Subscription, error) {
+
+	logs, sub, err := _Pdash.contract.StreamLogs(opts, "OrderInitiated")
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New or reverted log arrived, parse the event and forward to the user
+				event := new(PdashOrderInitiated)
+				if err := _Pdash.contract.UnpackLog(event, "OrderInitiated", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
 // PdashOrderWithdrawnIterator is returned from FilterOrderWithdrawn and is used to iterate over the raw logs and unpacked data for OrderWithdrawn events raised by the Pdash contract.
 type PdashOrderWithdrawnIterator struct {
 	Event *PdashOrderWithdrawn // Event containing the contract specifics and raw log
@@ -1482,6 +1634,44 @@ func (_Pdash *PdashFilterer) WatchOrderWithdrawn(opts *bind.WatchOpts, sink chan
 	}), nil
 }
 
+// StreamOrderWithdrawn is a reorg-aware log subscription operation binding the contract event 0x01e7164b56bfdcd76ac7df9a68a09f177aed22f0b3ef728d56f452745418ebb0,
+// resuming from the cursor of opts. Events reverted by a reorg are delivered again with Raw.Removed set.
+//
+// Solidity: e OrderWithdrawn(orderId uint256, time uint256)
+func (_Pdash *PdashFilterer) StreamOrderWithdrawn(opts *bind.StreamOpts, sink chan<- *PdashOrderWithdrawn) (event.Subscription, error) {
+
+	logs, sub, err := _Pdash.contract.StreamLogs(opts, "OrderWithdrawn")
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New or reverted log arrived, parse the event and forward to the user
+				event := new(PdashOrderWithdrawn)
+				if err := _Pdash.contract.UnpackLog(event, "OrderWithdrawn", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
 // PdashProxyDeliveredIterator is returned from FilterProxyDelivered and is used to iterate over the raw logs and unpacked data for ProxyDelivered events raised by the Pdash contract.
 type PdashProxyDeliveredIterator struct {
 	Event *PdashProxyDelivered // Event containing the contract specifics and raw log
@@ -1605,6 +1795,44 @@ func (_Pdash *PdashFilterer) WatchProxyDelivered(opts *bind.WatchOpts, sink chan
 	}), nil
 }
 
+// StreamProxyDelivered is a reorg-aware log subscription operation binding the contract event 0xee6a57b211b9b5284bd6650f9cca90a9ecbc0236e1610fe97c468faa0fc68287,
+// resuming from the cursor of opts. Events reverted by a reorg are delivered again with Raw.Removed set.
+//
+// Solidity: e ProxyDelivered(orderId uint256, time uint256)
+func (_Pdash *PdashFilterer) StreamProxyDelivered(opts *bind.StreamOpts, sink chan<- *PdashProxyDelivered) (event.Subscription, error) {
+
+	logs, sub, err := _Pdash.contract.StreamLogs(opts, "ProxyDelivered")
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New or reverted log arrived, parse the event and forward to the user
+				event := new(PdashProxyDelivered)
+				if err := _Pdash.contract.UnpackLog(event, "ProxyDelivered", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
 // PdashProxyDepositedIterator is returned from FilterProxyDeposited and is used to iterate over the raw logs and unpacked data for ProxyDeposited events raised by the Pdash contract.
 type PdashProxyDepositedIterator struct {
 	Event *PdashProxyDeposited // Event containing the contract specifics and raw log
@@ -1729,6 +1957,44 @@ func (_Pdash *PdashFilterer) WatchProxyDeposited(opts *bind.WatchOpts, sink chan
 	}), nil
 }
 
+// StreamProxyDeposited is a reorg-aware log subscription operation binding the contract event 0xe485b77aa65aed2e79b44431303e4512f39e8d8d6bb557ba6273ff499f6c4cec,
+// resuming from the cursor of opts. Events reverted by a reorg are delivered again with Raw.Removed set.
+//
+// Solidity: e ProxyDeposited(from address, value uint256, time uint256)
+func (_Pdash *PdashFilterer) StreamProxyDeposited(opts *bind.StreamOpts, sink chan<- *PdashProxyDeposited) (event.Subscription, error) {
+
+	logs, sub, err := _Pdash.contract.StreamLogs(opts, "ProxyDeposited")
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New or reverted log arrived, parse the event and forward to the user
+				event := new(PdashProxyDeposited)
+				if err := _Pdash.contract.UnpackLog(event, "ProxyDeposited", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
 // PdashProxyFetchedIterator is returned from FilterProxyFetched and is used to iterate over the raw logs and unpacked data for ProxyFetched events raised by the Pdash contract.
 type PdashProxyFetchedIterator struct {
 	Event *PdashProxyFetched // Event containing the contract specifics and raw log
@@ -1852,6 +2118,44 @@ func (_Pdash *PdashFilterer) WatchProxyFetched(opts *bind.WatchOpts, sink chan<-
 	}), nil
 }
 
+// StreamProxyFetched is a reorg-aware log subscription operation binding the contract event 0xf432f8d0b15f3b091c000b649a96bedc45f97d6f5361ff4cfd391f16a581fe8f,
+// resuming from the cursor of opts. Events reverted by a reorg are delivered again with Raw.Removed set.
+//
+// Solidity: e ProxyFetched(orderId uint256, time uint256)
+func (_Pdash *PdashFilterer) StreamProxyFetched(opts *bind.StreamOpts, sink chan<- *PdashProxyFetched) (event.Subscription, error) {
+
+	logs, sub, err := _Pdash.contract.StreamLogs(opts, "ProxyFetched")
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New or reverted log arrived, parse the event and forward to the user
+				event := new(PdashProxyFetched)
+				if err := _Pdash.contract.UnpackLog(event, "ProxyFetched", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
 // PdashProxyWithdrawnIterator is returned from FilterProxyWithdrawn and is used to iterate over the raw logs and unpacked data for ProxyWithdrawn events raised by the Pdash contract.
 type PdashProxyWithdrawnIterator struct {
 	Event *PdashProxyWithdrawn // Event containing the contract specifics and raw log
@@ -1976,6 +2280,44 @@ func (_Pdash *PdashFilterer) WatchProxyWithdrawn(opts *bind.WatchOpts, sink chan
 	}), nil
 }
 
+// StreamProxyWithdrawn is a reorg-aware log subscription operation binding the contract event 0xc6311a7ead0ac41d26e6a97e6c05f885c84fa52336b5fc201c83451f05b08b7d,
+// resuming from the cursor of opts. Events reverted by a reorg are delivered again with Raw.Removed set.
+//
+// Solidity: e ProxyWithdrawn(from address, value uint256, time uint256)
+func (_Pdash *PdashFilterer) StreamProxyWithdrawn(opts *bind.StreamOpts, sink chan<- *PdashProxyWithdrawn) (event.Subscription, error) {
+
+	logs, sub, err := _Pdash.contract.StreamLogs(opts, "ProxyWithdrawn")
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New or reverted log arrived, parse the event and forward to the user
+				event := new(PdashProxyWithdrawn)
+				if err := _Pdash.contract.UnpackLog(event, "ProxyWithdrawn", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
 // PdashSellerClaimTimeoutIterator is returned from FilterSellerClaimTimeout and is used to iterate over the raw logs and unpacked data for SellerClaimTimeout events raised by the Pdash contract.
 type PdashSellerClaimTimeoutIterator struct {
 	Event *PdashSellerClaimTimeout // Event containing the contract specifics and raw log
@@ -2099,6 +2441,44 @@ func (_Pdash *PdashFilterer) WatchSellerClaimTimeout(opts *bind.WatchOpts, sink
 	}), nil
 }
 
+// StreamSellerClaimTimeout is a reorg-aware log subscription operation binding the contract event 0x34ce961a05a1558f29e54cc2618b644ec6d298fda6c1eda6395c910d04c63f21,
+// resuming from the cursor of opts. Events reverted by a reorg are delivered again with Raw.Removed set.
+//
+// Solidity: e SellerClaimTimeout(orderId uint256, time uint256)
+func (_Pdash *PdashFilterer) StreamSellerClaimTimeout(opts *bind.StreamOpts, sink chan<- *PdashSellerClaimTimeout) (event.Subscription, error) {
+
+	logs, sub, err := _Pdash.contract.StreamLogs(opts, "SellerClaimTimeout")
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New or reverted log arrived, parse the event and forward to the user
+				event := new(PdashSellerClaimTimeout)
+				if err := _Pdash.contract.UnpackLog(event, "SellerClaimTimeout", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
 // PdashSellerConfirmedIterator is returned from FilterSellerConfirmed and is used to iterate over the raw logs and unpacked data for SellerConfirmed events raised by the Pdash contract.
 type PdashSellerConfirmedIterator struct {
 	Event *PdashSellerConfirmed // Event containing the contract specifics and raw log
@@ -2223,6 +2603,44 @@ func (_Pdash *PdashFilterer) WatchSellerConfirmed(opts *bind.WatchOpts, sink cha
 	}), nil
 }
 
+// StreamSellerConfirmed is a reorg-aware log subscription operation binding the contract event 0x76408f7d8666ddeb495564b182efc9e10ee49e1caa750fc79568b86ae940e42b,
+// resuming from the cursor of opts. Events reverted by a reorg are delivered again with Raw.Removed set.
+//
+// Solidity: e SellerConfirmed(orderId uint256, value uint256, time uint256)
+func (_Pdash *PdashFilterer) StreamSellerConfirmed(opts *bind.StreamOpts, sink chan<- *PdashSellerConfirmed) (event.Subscription, error) {
+
+	logs, sub, err := _Pdash.contract.StreamLogs(opts, "SellerConfirmed")
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New or reverted log arrived, parse the event and forward to the user
+				event := new(PdashSellerConfirmed)
+				if err := _Pdash.contract.UnpackLog(event, "SellerConfirmed", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
 // SafeMathABI is the input ABI used to generate the binding from.
 const SafeMathABI = "[]"
 
@@ -0,0 +1,333 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package proxy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	cpchain "bitbucket.org/cpchain/chain"
+	"bitbucket.org/cpchain/chain/accounts/abi"
+	"bitbucket.org/cpchain/chain/accounts/abi/bind"
+	"bitbucket.org/cpchain/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+var (
+	ErrNoSuchVersion = errors.New("no contract registered with the version")
+)
+
+// StorageVariable is a state variable of the storage layout emitted by
+// solc --storage-layout.
+type StorageVariable struct {
+	Label  string `json:"label"`
+	Slot   string `json:"slot"`
+	Offset int    `json:"offset"`
+	Type   string `json:"type"`
+}
+
+// StorageType is a type referred to by the variables of a storage layout.
+type StorageType struct {
+	Label         string `json:"label"`
+	NumberOfBytes string `json:"numberOfBytes"`
+}
+
+// StorageLayout is the storage layout of a contract as emitted by
+// solc --storage-layout.
+type StorageLayout struct {
+	Storage []StorageVariable      `json:"storage"`
+	Types   map[string]StorageType `json:"types"`
+}
+
+// LoadStorageLayout reads the storage layout of a contract from a file.
+func LoadStorageLayout(path string) (*StorageLayout, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	layout := new(StorageLayout)
+	if err := json.Unmarshal(data, layout); err != nil {
+		return nil, err
+	}
+	return layout, nil
+}
+
+// typeLabel returns the solidity type of the variable, which is stable across
+// compilations unlike the type identifier.
+func (l *StorageLayout) typeLabel(v StorageVariable) string {
+	if t, ok := l.Types[v.Type]; ok {
+		return t.Label
+	}
+	return v.Type
+}
+
+// CheckUpgrade checks the next implementation of a contract against the previous
+// one. Methods and events of the previous ABI must be kept with the same
+// signatures, and the variables of the previous storage layout must keep their
+// slots and types; new variables can only be appended. The storage layouts are
+// only compared if both are given.
+func CheckUpgrade(prevABI, nextABI abi.ABI, prev, next *StorageLayout) []error {
+	var errs []error
+
+	for _, name := range sortedMethods(prevABI) {
+		method := prevABI.Methods[name]
+		nextMethod, ok := nextABI.Methods[name]
+		if !ok {
+			errs = append(errs, fmt.Errorf("method %s removed", method.Sig()))
+			continue
+		}
+		if method.Sig() != nextMethod.Sig() {
+			errs = append(errs, fmt.Errorf("method %s changed to %s", method.Sig(), nextMethod.Sig()))
+			continue
+		}
+		if outputTypes(method) != outputTypes(nextMethod) {
+			errs = append(errs, fmt.Errorf("outputs of method %s changed from (%s) to (%s)", method.Sig(), outputTypes(method), outputTypes(nextMethod)))
+		}
+	}
+	for _, name := range sortedEvents(prevABI) {
+		event := prevABI.Events[name]
+		nextEvent, ok := nextABI.Events[name]
+		if !ok {
+			errs = append(errs, fmt.Errorf("event %s removed", event.Name))
+			continue
+		}
+		if event.Id() != nextEvent.Id() || eventInputs(event) != eventInputs(nextEvent) {
+			errs = append(errs, fmt.Errorf("event %v changed to %v", event, nextEvent))
+		}
+	}
+
+	if prev == nil || next == nil {
+		return errs
+	}
+	for i, v := range prev.Storage {
+		if i >= len(next.Storage) {
+			errs = append(errs, fmt.Errorf("state variable %s removed", v.Label))
+			continue
+		}
+		n := next.Storage[i]
+		if v.Slot != n.Slot || v.Offset != n.Offset {
+			errs = append(errs, fmt.Errorf("state variable %s moved from slot %s offset %d to %s at slot %s offset %d", v.Label, v.Slot, v.Offset, n.Label, n.Slot, n.Offset))
+			continue
+		}
+		if prev.typeLabel(v) != next.typeLabel(n) {
+			errs = append(errs, fmt.Errorf("type of state variable %s changed from %s to %s", v.Label, prev.typeLabel(v), next.typeLabel(n)))
+		}
+	}
+	return errs
+}
+
+func sortedMethods(contractABI abi.ABI) []string {
+	names := make([]string, 0, len(contractABI.Methods))
+	for name := range contractABI.Methods {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func sortedEvents(contractABI abi.ABI) []string {
+	names := make([]string, 0, len(contractABI.Events))
+	for name := range contractABI.Events {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func outputTypes(method abi.Method) string {
+	outputs := make([]string, len(method.Outputs))
+	for i, output := range method.Outputs {
+		outputs[i] = output.Type.String()
+	}
+	return strings.Join(outputs, ",")
+}
+
+// eventInputs returns the input types of the event with their indexing, which
+// the event id does not account for.
+func eventInputs(event abi.Event) string {
+	inputs := make([]string, len(event.Inputs))
+	for i, input := range event.Inputs {
+		inputs[i] = input.Type.String()
+		if input.Indexed {
+			inputs[i] += " indexed"
+		}
+	}
+	return strings.Join(inputs, ",")
+}
+
+// DryCall is a constant call replayed against the current and the next
+// implementation of a contract before upgrading it.
+type DryCall struct {
+	Method string
+	Args   []interface{}
+}
+
+// ParseDryCall parses a call written as method(arg1,arg2). Arguments are
+// converted to the input types of the method, only elementary types are
+// supported and strings cannot contain commas.
+func ParseDryCall(contractABI abi.ABI, call string) (DryCall, error) {
+	name, rest := call, ""
+	if i := strings.Index(call, "("); i >= 0 {
+		if !strings.HasSuffix(call, ")") {
+			return DryCall{}, fmt.Errorf("invalid call %q", call)
+		}
+		name, rest = call[:i], call[i+1:len(call)-1]
+	}
+	method, ok := contractABI.Methods[name]
+	if !ok {
+		return DryCall{}, fmt.Errorf("method %s not found", name)
+	}
+	var args []string
+	if strings.TrimSpace(rest) != "" {
+		args = strings.Split(rest, ",")
+	}
+	if len(args) != len(method.Inputs) {
+		return DryCall{}, fmt.Errorf("method %s takes %d arguments, got %d", method.Sig(), len(method.Inputs), len(args))
+	}
+	dc := DryCall{Method: name, Args: make([]interface{}, len(args))}
+	for i, arg := range args {
+		v, err := parseArg(method.Inputs[i].Type, strings.TrimSpace(arg))
+		if err != nil {
+			return DryCall{}, fmt.Errorf("argument %d of %s: %v", i, method.Sig(), err)
+		}
+		dc.Args[i] = v
+	}
+	return dc, nil
+}
+
+// parseArg converts s to the go type abi packs for t.
+func parseArg(t abi.Type, s string) (interface{}, error) {
+	switch t.T {
+	case abi.AddressTy:
+		if !common.IsHexAddress(s) {
+			return nil, fmt.Errorf("invalid address %q", s)
+		}
+		return common.HexToAddress(s), nil
+	case abi.BoolTy:
+		return strconv.ParseBool(s)
+	case abi.StringTy:
+		return s, nil
+	case abi.BytesTy:
+		return hexutil.Decode(s)
+	case abi.FixedBytesTy:
+		b, err := hexutil.Decode(s)
+		if err != nil {
+			return nil, err
+		}
+		if len(b) != t.Size {
+			return nil, fmt.Errorf("want %d bytes, got %d", t.Size, len(b))
+		}
+		v := reflect.New(t.Type).Elem()
+		reflect.Copy(v, reflect.ValueOf(b))
+		return v.Interface(), nil
+	case abi.IntTy, abi.UintTy:
+		n, ok := new(big.Int).SetString(s, 0)
+		if !ok {
+			return nil, fmt.Errorf("invalid integer %q", s)
+		}
+		if t.Type == reflect.TypeOf(n) {
+			return n, nil
+		}
+		v := reflect.New(t.Type).Elem()
+		if t.T == abi.UintTy {
+			if !n.IsUint64() || v.OverflowUint(n.Uint64()) {
+				return nil, fmt.Errorf("%s out of range of %v", s, t)
+			}
+			v.SetUint(n.Uint64())
+		} else {
+			if !n.IsInt64() || v.OverflowInt(n.Int64()) {
+				return nil, fmt.Errorf("%s out of range of %v", s, t)
+			}
+			v.SetInt(n.Int64())
+		}
+		return v.Interface(), nil
+	}
+	return nil, fmt.Errorf("unsupported type %v", t)
+}
+
+// DryRunResult is the outcome of a dry call against both implementations.
+type DryRunResult struct {
+	Call       DryCall
+	Current    []byte
+	CurrentErr error
+	Next       []byte
+	NextErr    error
+}
+
+// Changed returns whether the next implementation answers the call differently.
+func (r DryRunResult) Changed() bool {
+	return (r.CurrentErr == nil) != (r.NextErr == nil) || !bytes.Equal(r.Current, r.Next)
+}
+
+// DryRun replays the calls against the current and the next implementation on
+// the latest state. Calls to a proxy run the code and storage of its registered
+// implementation, so calling the next implementation is what the proxy answers
+// once the next implementation is registered, nothing being committed.
+func DryRun(ctx context.Context, caller bind.ContractCaller, contractABI abi.ABI, current, next common.Address, calls []DryCall) ([]DryRunResult, error) {
+	results := make([]DryRunResult, len(calls))
+	for i, call := range calls {
+		input, err := contractABI.Pack(call.Method, call.Args...)
+		if err != nil {
+			return nil, err
+		}
+		results[i].Call = call
+		results[i].Current, results[i].CurrentErr = caller.CallContract(ctx, cpchain.CallMsg{To: &current, Data: input}, nil)
+		results[i].Next, results[i].NextErr = caller.CallContract(ctx, cpchain.CallMsg{To: &next, Data: input}, nil)
+	}
+	return results, nil
+}
+
+// Versions returns the implementations registered for the proxy, the one of
+// version v being at index v-1.
+func (self *ProxyContractRegister) Versions(proxyAddress common.Address) ([]common.Address, error) {
+	latest, err := self.Contract.GetContractVersion(&self.CallOpts, proxyAddress)
+	if err != nil {
+		return nil, err
+	}
+	versions := make([]common.Address, 0, latest.Uint64())
+	for v := uint64(1); v <= latest.Uint64(); v++ {
+		addr, err := self.Contract.GetOldContract(&self.CallOpts, proxyAddress, new(big.Int).SetUint64(v))
+		if err != nil {
+			return nil, err
+		}
+		versions = append(versions, addr)
+	}
+	return versions, nil
+}
+
+// Rollback registers the implementation of the given version for the proxy
+// again, it becomes the newest version.
+func (self *ProxyContractRegister) Rollback(proxyAddress common.Address, version uint64) (*types.Transaction, error) {
+	realAddress, err := self.Contract.GetOldContract(&self.CallOpts, proxyAddress, new(big.Int).SetUint64(version))
+	if err != nil {
+		return nil, err
+	}
+	if realAddress == (common.Address{}) {
+		return nil, ErrNoSuchVersion
+	}
+	return self.RegisterPublicKey(proxyAddress, realAddress)
+}
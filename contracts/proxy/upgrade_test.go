@@ -0,0 +1,131 @@
+package proxy_test
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"bitbucket.org/cpchain/chain/accounts/abi"
+	"bitbucket.org/cpchain/chain/accounts/abi/bind/backends"
+	"bitbucket.org/cpchain/chain/contracts/proxy"
+	"bitbucket.org/cpchain/chain/core"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	prevABIJSON = `[
+		{"constant":true,"inputs":[{"name":"_addr","type":"address"}],"name":"balanceOf","outputs":[{"name":"","type":"uint256"}],"type":"function"},
+		{"constant":false,"inputs":[{"name":"_period","type":"uint64"}],"name":"setPeriod","outputs":[],"type":"function"},
+		{"anonymous":false,"inputs":[{"indexed":false,"name":"_addr","type":"address"}],"name":"Joined","type":"event"}
+	]`
+	nextABIJSON = `[
+		{"constant":true,"inputs":[{"name":"_addr","type":"address"}],"name":"balanceOf","outputs":[{"name":"","type":"uint128"}],"type":"function"},
+		{"anonymous":false,"inputs":[{"indexed":true,"name":"_addr","type":"address"}],"name":"Joined","type":"event"}
+	]`
+)
+
+func TestCheckUpgrade(t *testing.T) {
+	prevABI, _ := abi.JSON(strings.NewReader(prevABIJSON))
+	nextABI, _ := abi.JSON(strings.NewReader(nextABIJSON))
+
+	prev := &proxy.StorageLayout{
+		Storage: []proxy.StorageVariable{
+			{Label: "owner", Slot: "0", Offset: 0, Type: "t_address"},
+			{Label: "period", Slot: "1", Offset: 0, Type: "t_uint256"},
+		},
+		Types: map[string]proxy.StorageType{
+			"t_address": {Label: "address", NumberOfBytes: "20"},
+			"t_uint256": {Label: "uint256", NumberOfBytes: "32"},
+		},
+	}
+	next := &proxy.StorageLayout{
+		Storage: []proxy.StorageVariable{
+			{Label: "owner", Slot: "0", Offset: 0, Type: "t_address"},
+			{Label: "period", Slot: "1", Offset: 0, Type: "t_uint64"},
+			{Label: "paused", Slot: "2", Offset: 0, Type: "t_bool"},
+		},
+		Types: map[string]proxy.StorageType{
+			"t_address": {Label: "address", NumberOfBytes: "20"},
+			"t_uint64":  {Label: "uint64", NumberOfBytes: "8"},
+			"t_bool":    {Label: "bool", NumberOfBytes: "1"},
+		},
+	}
+
+	if errs := proxy.CheckUpgrade(prevABI, prevABI, prev, prev); len(errs) != 0 {
+		t.Fatalf("unexpected incompatibilities: %v", errs)
+	}
+	errs := proxy.CheckUpgrade(prevABI, nextABI, prev, next)
+	want := []string{
+		"outputs of method balanceOf(address) changed from (uint256) to (uint128)",
+		"method setPeriod(uint64) removed",
+		"event e Joined(_addr address) changed to e Joined(_addr indexed address)",
+		"type of state variable period changed from uint256 to uint64",
+	}
+	if len(errs) != len(want) {
+		t.Fatalf("incompatibility count mismatch: have %v, want %v", errs, want)
+	}
+	for i, err := range errs {
+		if err.Error() != want[i] {
+			t.Errorf("incompatibility %d mismatch: have %q, want %q", i, err, want[i])
+		}
+	}
+}
+
+func TestParseDryCall(t *testing.T) {
+	contractABI, _ := abi.JSON(strings.NewReader(prevABIJSON))
+
+	call, err := proxy.ParseDryCall(contractABI, "balanceOf(0x095e7baea6a6c7c4c2dfeb977efac326af552d87)")
+	checkError(t, "ParseDryCall : expected no error, got %v", err)
+	if call.Method != "balanceOf" || call.Args[0] != realaddr {
+		t.Fatalf("call mismatch: have %v", call)
+	}
+	call, err = proxy.ParseDryCall(contractABI, "setPeriod(300)")
+	checkError(t, "ParseDryCall : expected no error, got %v", err)
+	if call.Args[0] != uint64(300) {
+		t.Fatalf("argument mismatch: have %v (%T), want %v", call.Args[0], call.Args[0], 300)
+	}
+
+	for _, invalid := range []string{"balanceOf", "balanceOf(0x12)", "setPeriod(-1)", "unknown()", "setPeriod(1"} {
+		if _, err := proxy.ParseDryCall(contractABI, invalid); err == nil {
+			t.Errorf("expected error for %q", invalid)
+		}
+	}
+}
+
+func TestProxyContractRegister_Rollback(t *testing.T) {
+	contractBackend := backends.NewDporSimulatedBackend(core.GenesisAlloc{addr: {Balance: big.NewInt(1000000000000)}})
+	_, _, instance, err := deploy(key, big.NewInt(0), contractBackend)
+	checkError(t, "deploy contract: expected no error, got %v", err)
+
+	nextaddr := common.HexToAddress("095e7baea6a6c7c4c2dfeb977efac326af552d88")
+	for _, real := range []common.Address{realaddr, nextaddr} {
+		_, err = instance.RegisterPublicKey(proxyaddr, real)
+		checkError(t, "RegisterPublicKey : expected no error, got %v ", err)
+		contractBackend.Commit()
+	}
+
+	_, err = instance.Rollback(proxyaddr, 1)
+	checkError(t, "Rollback : expected no error, got %v ", err)
+	contractBackend.Commit()
+
+	addr, err := instance.GetRealContract(proxyaddr)
+	checkError(t, "GetRealContract : expected no error, got %v ", err)
+	if addr != realaddr {
+		t.Fatal("get wrong address", "get addr:", addr, "real address", realaddr)
+	}
+	versions, err := instance.Versions(proxyaddr)
+	checkError(t, "Versions : expected no error, got %v ", err)
+	want := []common.Address{realaddr, nextaddr, realaddr}
+	if len(versions) != len(want) {
+		t.Fatalf("version count mismatch: have %d, want %d", len(versions), len(want))
+	}
+	for i := range want {
+		if versions[i] != want[i] {
+			t.Errorf("version %d mismatch: have %x, want %x", i+1, versions[i], want[i])
+		}
+	}
+
+	if _, err := instance.Rollback(proxyaddr, 10); err != proxy.ErrNoSuchVersion {
+		t.Fatalf("error mismatch: have %v, want %v", err, proxy.ErrNoSuchVersion)
+	}
+}
@@ -133,3 +133,28 @@ func CalcGasLimit(parent *types.Block) uint64 {
 	}
 	return limit
 }
+
+// CalcGasLimitToward computes the gas limit of the next block after parent
+// once proposers vote for it. The limit moves toward target by at most a step
+// of config. A target of 0 follows the usage of parent, raising the limit if
+// it uses more than 2/3 of it and lowering it below 1/3.
+func CalcGasLimitToward(config *configs.GasLimitConfig, parent *types.Block, target uint64) uint64 {
+	lo, hi := config.Bounds(parent.GasLimit())
+	if target == 0 {
+		switch used := parent.GasUsed(); {
+		case used > parent.GasLimit()/3*2:
+			target = hi
+		case used < parent.GasLimit()/3:
+			target = lo
+		default:
+			target = parent.GasLimit()
+		}
+	}
+	if target < lo {
+		return lo
+	}
+	if target > hi {
+		return hi
+	}
+	return target
+}
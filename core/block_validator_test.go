@@ -228,3 +228,28 @@ func testHeaderConcurrentAbortion(t *testing.T, threads int) {
 		t.Errorf("verification count too large: have %d, want below %d", verified, 3*threads)
 	}
 }
+
+func TestCalcGasLimitToward(t *testing.T) {
+	config := &configs.GasLimitConfig{Floor: 90000000, Ceil: 110000000, StepDivisor: 100}
+	parentOf := func(limit, used uint64) *types.Block {
+		return types.NewBlockWithHeader(&types.Header{GasLimit: limit, GasUsed: used})
+	}
+
+	for i, tt := range []struct {
+		limit, used, target, want uint64
+	}{
+		{100000000, 0, 105000000, 101000000},                                       // a step toward the target
+		{100000000, 0, 100500000, 100500000},                                       // the target within a step
+		{100000000, 0, 50000000, 99000000},                                         // a step down
+		{109500000, 0, 200000000, 110000000},                                       // the ceil
+		{100000000, 80000000, 0, 101000000},                                        // high usage raises the limit
+		{100000000, 10000000, 0, 99000000},                                         // low usage lowers it
+		{100000000, 50000000, 0, 100000000},                                        // usage in between keeps it
+		{120000000, 0, 100000000, 118800000},                                       // back toward the ceil
+		{configs.MinGasLimit, 0, 0, configs.MinGasLimit + configs.MinGasLimit/100}, // up toward the floor
+	} {
+		if got := CalcGasLimitToward(config, parentOf(tt.limit, tt.used), tt.target); got != tt.want {
+			t.Errorf("test %d: gas limit %d, want %d", i, got, tt.want)
+		}
+	}
+}
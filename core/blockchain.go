@@ -914,16 +914,31 @@ func (bc *BlockChain) Rollback(chain []common.Hash) {
 	}
 }
 
-// SetReceiptsData computes all the non-consensus fields of the receipts
+// SetReceiptsData computes all the non-consensus fields of the receipts. The
+// receipt of the logs of the block itself, if any, follows the ones of the
+// transactions.
 func SetReceiptsData(config *configs.ChainConfig, block *types.Block, receipts types.Receipts) error {
 	signer := types.MakeSigner(config)
 
 	transactions, logIndex := block.Transactions(), uint(0)
-	if len(transactions) != len(receipts) {
+	if len(transactions) != len(receipts) && len(transactions)+1 != len(receipts) {
 		return errors.New("transaction and receipt count mismatch")
 	}
 
 	for j := 0; j < len(receipts); j++ {
+		if j == len(transactions) {
+			// The receipt of the block itself has no transaction and uses no gas
+			receipts[j].TxHash, receipts[j].GasUsed = common.Hash{}, 0
+			for k := 0; k < len(receipts[j].Logs); k++ {
+				receipts[j].Logs[k].BlockNumber = block.NumberU64()
+				receipts[j].Logs[k].BlockHash = block.Hash()
+				receipts[j].Logs[k].TxHash = common.Hash{}
+				receipts[j].Logs[k].TxIndex = uint(j)
+				receipts[j].Logs[k].Index = logIndex
+				logIndex++
+			}
+			break
+		}
 		// The transaction hash can be retrieved from the transaction itself
 		receipts[j].TxHash = transactions[j].Hash()
 
@@ -1519,6 +1534,9 @@ func (bc *BlockChain) insertChain(chain types.Blocks) (int, []interface{}, []*ty
 			go chainmetrics.ReportBlockNumberGauge("blocknumber", float64(chain[i].Number().Int64()))
 			go chainmetrics.ReportTxsNumberGauge("txs", float64(len(chain[i].Transactions())))
 			go chainmetrics.ReportInsertionElapsedTime("insertion_elapsed", float64(time.Since(bstart).Nanoseconds()*int64(time.Nanosecond)/int64(time.Millisecond)))
+			go chainmetrics.ReportGasLimitGauge("gaslimit", float64(chain[i].GasLimit()))
+			go chainmetrics.ReportGasUsedGauge("gasused", float64(chain[i].GasUsed()))
+			go chainmetrics.ReportBlockIntervalGauge("interval", float64(new(big.Int).Sub(chain[i].Time(), parent.Time()).Int64()))
 		}
 	}
 	// Append a single chain head event if we've progressed the chain
@@ -187,6 +187,9 @@ func GenerateChain(config *configs.ChainConfig, parent *types.Block, engine cons
 
 		if b.engine != nil {
 			block, _ := b.engine.Finalize(b.chainReader, b.header, pubStatedb, b.txs, []*types.Header{}, b.receipts)
+			if logs := pubStatedb.GetLogs(common.Hash{}); len(logs) > 0 {
+				b.receipts = append(b.receipts, types.NewBlockReceipt(logs, b.header.GasUsed))
+			}
 			// Write state changes to db
 			root, err := pubStatedb.Commit(true)
 			if err != nil {
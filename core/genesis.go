@@ -134,7 +134,7 @@ func (e *GenesisMismatchError) Error() string {
 //
 // The stored chain configuration will be updated if it is compatible (i.e. does not
 // specify a fork block below the local head block). In case of a conflict, the
-// error is a *params.ConfigCompatError and the new, unwritten config is returned.
+// error is a *configs.ConfigCompatError and the new, unwritten config is returned.
 //
 // The returned chain configuration is never nil.
 func SetupGenesisBlock(db database.Database, genesis *Genesis) (*configs.ChainConfig, common.Hash, error) {
@@ -164,7 +164,10 @@ func SetupGenesisBlock(db database.Database, genesis *Genesis) (*configs.ChainCo
 			if hash != stored {
 				return genesis.Config, hash, &GenesisMismatchError{stored, hash}
 			}
-			finalCfg = updateChainConfig(storedCfg, newCfg, db, stored)
+			if err := updateChainConfig(storedCfg, newCfg, db, stored); err != nil {
+				return newCfg, stored, err
+			}
+			finalCfg = newCfg
 		} else {
 			// Special case: don't change the existing config of a non-mainnet chain if no new
 			// config is supplied. These chains would get AllProtocolChanges (and a compat error)
@@ -172,20 +175,28 @@ func SetupGenesisBlock(db database.Database, genesis *Genesis) (*configs.ChainCo
 			if stored != MainnetGenesisHash {
 				return storedCfg, stored, nil
 			} else {
-				finalCfg = updateChainConfig(storedCfg, newCfg, db, stored)
+				if err := updateChainConfig(storedCfg, newCfg, db, stored); err != nil {
+					return newCfg, stored, err
+				}
+				finalCfg = newCfg
 			}
 		}
 		return finalCfg, stored, nil
 	}
 }
 
-func updateChainConfig(storedcfg *configs.ChainConfig, newcfg *configs.ChainConfig, db database.Database, stored common.Hash) *configs.ChainConfig {
+// updateChainConfig writes newcfg over the stored configuration unless it
+// reschedules a fork the local chain has already passed.
+func updateChainConfig(storedcfg *configs.ChainConfig, newcfg *configs.ChainConfig, db database.Database, stored common.Hash) error {
 	if storedcfg == nil {
 		log.Warn("Found genesis block without chain config")
+	} else if height := rawdb.ReadHeaderNumber(db, rawdb.ReadHeadHeaderHash(db)); height != nil {
+		if compatErr := storedcfg.CheckCompatible(newcfg, *height); compatErr != nil {
+			return compatErr
+		}
 	}
-	// NOTICE: in future we may need to check the compatibility of old and new configurations.
 	rawdb.WriteChainConfig(db, stored, newcfg)
-	return newcfg
+	return nil
 }
 
 // OpenGenesisBlock opens genesis block and returns its chain configuration and hash.
@@ -24,6 +24,7 @@ import (
 	"bitbucket.org/cpchain/chain/configs"
 	"bitbucket.org/cpchain/chain/core/rawdb"
 	"bitbucket.org/cpchain/chain/database"
+	"bitbucket.org/cpchain/chain/types"
 	"github.com/davecgh/go-spew/spew"
 	"github.com/ethereum/go-ethereum/common"
 )
@@ -52,6 +53,8 @@ func TestSetupGenesis(t *testing.T) {
 		oldcustomg = customg
 	)
 	oldcustomg.Config = &configs.ChainConfig{}
+	forkedg := customg
+	forkedg.Config = &configs.ChainConfig{Campaign2Block: big.NewInt(5)}
 	tests := []struct {
 		name       string
 		fn         func(database.Database) (*configs.ChainConfig, common.Hash, error)
@@ -102,6 +105,27 @@ func TestSetupGenesis(t *testing.T) {
 			wantHash:   customghash,
 			wantConfig: customg.Config,
 		},
+		{
+			name: "incompatible config in DB",
+			fn: func(db database.Database) (*configs.ChainConfig, common.Hash, error) {
+				genesis := oldcustomg.MustCommit(db)
+				// the chain is past the rescheduled fork
+				head := types.CopyHeader(genesis.Header())
+				head.Number = big.NewInt(10)
+				head.ParentHash = genesis.Hash()
+				rawdb.WriteHeader(db, head)
+				rawdb.WriteHeadHeaderHash(db, head.Hash())
+				return SetupGenesisBlock(db, &forkedg)
+			},
+			wantHash:   customghash,
+			wantConfig: forkedg.Config,
+			wantErr: &configs.ConfigCompatError{
+				What:         "campaign2 fork block",
+				StoredConfig: oldcustomg.Config.Campaign2BlockNumber(),
+				NewConfig:    big.NewInt(5),
+				RewindTo:     4,
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -197,6 +197,17 @@ func (self *stateObject) setState(key, value common.Hash) {
 	self.dirtyStorage[key] = value
 }
 
+// setStorage replaces the storage with the given entries, on an empty trie.
+func (self *stateObject) setStorage(storage map[common.Hash]common.Hash) {
+	self.trie = nil
+	self.data.Root = common.Hash{}
+	self.cachedStorage = make(Storage)
+	self.dirtyStorage = make(Storage)
+	for key, value := range storage {
+		self.setState(key, value)
+	}
+}
+
 // updateTrie writes cached storage modifications into the object's storage trie.
 func (self *stateObject) updateTrie(db Database) Trie {
 	tr := self.getTrie(db)
@@ -61,6 +61,24 @@ func TestUpdateLeaks(t *testing.T) {
 	}
 }
 
+// Tests that SetStorage drops the committed storage of an account.
+func TestSetStorage(t *testing.T) {
+	state, _ := New(common.Hash{}, NewDatabase(database.NewMemDatabase()))
+	addr := common.BytesToAddress([]byte{1})
+	one, two := common.BytesToHash([]byte{1}), common.BytesToHash([]byte{2})
+	state.SetState(addr, one, one)
+	root, _ := state.Commit(false)
+
+	state, _ = New(root, state.Database())
+	state.SetStorage(addr, map[common.Hash]common.Hash{two: two})
+	if have := state.GetState(addr, one); have != (common.Hash{}) {
+		t.Errorf("replaced slot: have %x, want empty", have)
+	}
+	if have := state.GetState(addr, two); have != two {
+		t.Errorf("new slot: have %x, want %x", have, two)
+	}
+}
+
 // Tests that no intermediate state of an object is stored into the database,
 // only the one right before the commit.
 func TestIntermediateLeaks(t *testing.T) {
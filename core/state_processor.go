@@ -103,6 +103,16 @@ func (p *StateProcessor) Process(block *types.Block, statedb *state.StateDB, sta
 	// Finalize the block, applying any consensus engine specific extras (e.g. block rewards)
 	p.engine.Finalize(p.bc, header, statedb, block.Transactions(), []*types.Header{}, pubReceipts)
 
+	// Collect the logs of the block itself, kept under the empty transaction hash,
+	// into the receipt of the block after the ones of its transactions
+	if logs := statedb.GetLogs(common.Hash{}); len(logs) > 0 {
+		for _, l := range logs {
+			l.BlockHash = block.Hash()
+		}
+		pubReceipts = append(pubReceipts, types.NewBlockReceipt(logs, *usedGas))
+		allLogs = append(allLogs, logs...)
+	}
+
 	// TODO: if return private logs separately or merge them together as a whole logs collection?
 	return pubReceipts, privReceipts, allLogs, *usedGas, nil
 }
@@ -27,4 +27,5 @@ var (
 	ErrInsufficientBalance      = errors.New("insufficient balance for transfer")
 	ErrContractAddressCollision = errors.New("contract address collision")
 	ErrPrimitiveContractExists  = errors.New("primitive contract already exist")
+	ErrExecutionReverted        = errors.New("evm: execution reverted")
 )
@@ -0,0 +1,319 @@
+// Copyright 2018 The cpchain Authors
+package cpcapi
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"time"
+
+	"bitbucket.org/cpchain/chain/accounts/abi"
+	"bitbucket.org/cpchain/chain/api/rpc"
+	"bitbucket.org/cpchain/chain/core"
+	"bitbucket.org/cpchain/chain/core/state"
+	"bitbucket.org/cpchain/chain/core/vm"
+	"bitbucket.org/cpchain/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const (
+	// maxSimulatedCalls bounds the number of calls of a single simulation.
+	maxSimulatedCalls = 64
+
+	// simulateTimeout bounds the time of a whole simulation.
+	simulateTimeout = 10 * time.Second
+)
+
+// OverrideAccount replaces fields of an account for a simulation. State
+// replaces the whole storage while StateDiff only the given slots.
+type OverrideAccount struct {
+	Nonce     *hexutil.Uint64             `json:"nonce"`
+	Code      *hexutil.Bytes              `json:"code"`
+	Balance   *hexutil.Big                `json:"balance"`
+	State     map[common.Hash]common.Hash `json:"state"`
+	StateDiff map[common.Hash]common.Hash `json:"stateDiff"`
+}
+
+// StateOverride is the set of accounts overridden for a simulation.
+type StateOverride map[common.Address]OverrideAccount
+
+// apply writes the overrides into the state.
+func (o StateOverride) apply(state *state.StateDB) error {
+	for addr, account := range o {
+		if account.State != nil && account.StateDiff != nil {
+			return fmt.Errorf("account %x has both state and stateDiff overrides", addr)
+		}
+		if account.Nonce != nil {
+			state.SetNonce(addr, uint64(*account.Nonce))
+		}
+		if account.Code != nil {
+			state.SetCode(addr, *account.Code)
+		}
+		if account.Balance != nil {
+			state.SetBalance(addr, (*big.Int)(account.Balance))
+		}
+		if account.State != nil {
+			state.SetStorage(addr, account.State)
+		}
+		for key, value := range account.StateDiff {
+			state.SetState(addr, key, value)
+		}
+	}
+	return nil
+}
+
+// BlockOverrides replaces fields of the block context the calls run in.
+type BlockOverrides struct {
+	Number   *hexutil.Big    `json:"number"`
+	Time     *hexutil.Uint64 `json:"time"` // seconds, as seen by the EVM
+	Coinbase *common.Address `json:"coinbase"`
+}
+
+// apply writes the overrides into the context of the EVM.
+func (o *BlockOverrides) apply(evm *vm.EVM) {
+	if o == nil {
+		return
+	}
+	if o.Number != nil {
+		evm.BlockNumber = new(big.Int).Set((*big.Int)(o.Number))
+	}
+	if o.Time != nil {
+		evm.Time = new(big.Int).SetUint64(uint64(*o.Time))
+	}
+	if o.Coinbase != nil {
+		evm.Coinbase = *o.Coinbase
+	}
+}
+
+// AccessedAccount is an account a call touched, with the storage slots it
+// read or wrote.
+type AccessedAccount struct {
+	Address     common.Address `json:"address"`
+	StorageKeys []common.Hash  `json:"storageKeys"`
+}
+
+// SimulateResult is the outcome of a simulated call.
+type SimulateResult struct {
+	ReturnData      hexutil.Bytes     `json:"returnData"`
+	GasUsed         hexutil.Uint64    `json:"gasUsed"`
+	Failed          bool              `json:"failed"`
+	Error           string            `json:"error,omitempty"`
+	RevertReason    string            `json:"revertReason,omitempty"`
+	ContractAddress *common.Address   `json:"contractAddress,omitempty"`
+	Logs            []*types.Log      `json:"logs"`
+	Accessed        []AccessedAccount `json:"accessed"`
+}
+
+// PublicSimulationAPI previews transactions without sending them.
+type PublicSimulationAPI struct {
+	b Backend
+}
+
+// NewPublicSimulationAPI creates a new simulation API.
+func NewPublicSimulationAPI(b Backend) *PublicSimulationAPI {
+	return &PublicSimulationAPI{b}
+}
+
+// Simulate executes the calls one after the other on the state of the given
+// block, each call seeing the effects of the previous ones. Accounts and the
+// block context can be overridden. Nothing is committed.
+//
+// Like cpc_call, the sender of a call is funded for its gas unless its balance
+// is overridden.
+func (s *PublicSimulationAPI) Simulate(ctx context.Context, calls []CallArgs, blockNr rpc.BlockNumber, stateOverride *StateOverride, blockOverrides *BlockOverrides) ([]*SimulateResult, error) {
+	if len(calls) == 0 {
+		return nil, errors.New("no calls to simulate")
+	}
+	if len(calls) > maxSimulatedCalls {
+		return nil, fmt.Errorf("too many calls, want at most %d", maxSimulatedCalls)
+	}
+	isPrivate := calls[0].IsPrivate
+	for _, call := range calls[1:] {
+		if call.IsPrivate != isPrivate {
+			return nil, errors.New("calls must be all public or all private")
+		}
+	}
+
+	state, header, err := s.b.StateAndHeaderByNumber(ctx, blockNr, isPrivate)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	if stateOverride != nil {
+		if err := stateOverride.apply(state); err != nil {
+			return nil, err
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, simulateTimeout)
+	defer cancel()
+
+	// use a default sender if none specified, as in cpc_call
+	var defaultFrom common.Address
+	if wallets := s.b.AccountManager().Wallets(); len(wallets) > 0 {
+		if accounts := wallets[0].Accounts(); len(accounts) > 0 {
+			defaultFrom = accounts[0].Address
+		}
+	}
+
+	results := make([]*SimulateResult, 0, len(calls))
+	for i, args := range calls {
+		if args.From == (common.Address{}) {
+			args.From = defaultFrom
+		}
+		result, err := s.simulateCall(ctx, state, header, i, args, stateOverride, blockOverrides)
+		if err != nil {
+			return nil, fmt.Errorf("call %d: %v", i, err)
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+func (s *PublicSimulationAPI) simulateCall(ctx context.Context, state *state.StateDB, header *types.Header, index int, args CallArgs,
+	stateOverride *StateOverride, blockOverrides *BlockOverrides) (*SimulateResult, error) {
+	gas, gasPrice := uint64(args.Gas), args.GasPrice.ToInt()
+	if gas == 0 {
+		gas = math.MaxUint64 / 2
+	}
+	if gasPrice.Sign() == 0 {
+		gasPrice = new(big.Int).SetUint64(defaultGasPrice)
+	}
+	msg := types.NewMessage(args.From, args.To, 0, args.Value.ToInt(), gas, gasPrice, args.Data, false)
+
+	tracer := newAccessTracer()
+	evm, vmError, err := s.b.GetEVM(ctx, msg, state, header, vm.Config{Debug: true, Tracer: tracer})
+	if err != nil {
+		return nil, err
+	}
+	// GetEVM funds the sender, an overridden balance takes precedence
+	if stateOverride != nil {
+		if account, ok := (*stateOverride)[args.From]; ok && account.Balance != nil {
+			state.SetBalance(args.From, (*big.Int)(account.Balance))
+		}
+	}
+	blockOverrides.apply(evm)
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			evm.Cancel()
+		case <-done:
+		}
+	}()
+
+	var contractAddr *common.Address
+	if args.To == nil {
+		addr := crypto.CreateAddress(args.From, state.GetNonce(args.From))
+		contractAddr = &addr
+	}
+	state.Prepare(common.Hash{}, header.Hash(), index)
+	logsBefore := len(state.GetLogs(common.Hash{}))
+
+	ret, gasUsed, failed, err := core.ApplyMessage(evm, msg, new(core.GasPool).AddGas(math.MaxUint64))
+	if err != nil {
+		return nil, err
+	}
+	if err := vmError(); err != nil {
+		return nil, err
+	}
+	if ctx.Err() != nil {
+		return nil, fmt.Errorf("execution aborted (timeout = %v)", simulateTimeout)
+	}
+	state.Finalise(true)
+
+	result := &SimulateResult{
+		ReturnData: ret,
+		GasUsed:    hexutil.Uint64(gasUsed),
+		Failed:     failed,
+		Logs:       state.GetLogs(common.Hash{})[logsBefore:],
+		Accessed:   tracer.accessed(),
+	}
+	if failed {
+		if tracer.err != nil {
+			result.Error = tracer.err.Error()
+		}
+		if tracer.err == vm.ErrExecutionReverted {
+			if reason, err := abi.UnpackRevert(ret); err == nil {
+				result.RevertReason = reason
+			}
+		}
+	} else {
+		result.ContractAddress = contractAddr
+	}
+	if result.Logs == nil {
+		result.Logs = []*types.Log{}
+	}
+	return result, nil
+}
+
+// accessTracer records the accounts and storage slots touched by a call.
+type accessTracer struct {
+	touched map[common.Address]map[common.Hash]struct{}
+	err     error // error of the outermost call
+}
+
+func newAccessTracer() *accessTracer {
+	return &accessTracer{touched: make(map[common.Address]map[common.Hash]struct{})}
+}
+
+func (t *accessTracer) touch(addr common.Address) map[common.Hash]struct{} {
+	slots, ok := t.touched[addr]
+	if !ok {
+		slots = make(map[common.Hash]struct{})
+		t.touched[addr] = slots
+	}
+	return slots
+}
+
+func (t *accessTracer) CaptureStart(from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) error {
+	t.touch(from)
+	t.touch(to)
+	return nil
+}
+
+func (t *accessTracer) CaptureState(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, memory *vm.Memory, stack *vm.Stack, contract *vm.Contract, depth int, err error) error {
+	slots := t.touch(contract.Address())
+	switch {
+	case (op == vm.SLOAD || op == vm.SSTORE) && len(stack.Data()) >= 1:
+		slots[common.BigToHash(stack.Back(0))] = struct{}{}
+	case (op == vm.BALANCE || op == vm.EXTCODESIZE || op == vm.EXTCODECOPY || op == vm.SELFDESTRUCT) && len(stack.Data()) >= 1:
+		t.touch(common.BigToAddress(stack.Back(0)))
+	case (op == vm.CALL || op == vm.CALLCODE || op == vm.DELEGATECALL || op == vm.STATICCALL) && len(stack.Data()) >= 2:
+		t.touch(common.BigToAddress(stack.Back(1)))
+	case op == vm.CREATE:
+		t.touch(crypto.CreateAddress(contract.Address(), env.StateDB.GetNonce(contract.Address())))
+	}
+	return nil
+}
+
+func (t *accessTracer) CaptureFault(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, memory *vm.Memory, stack *vm.Stack, contract *vm.Contract, depth int, err error) error {
+	return nil
+}
+
+func (t *accessTracer) CaptureEnd(output []byte, gasUsed uint64, d time.Duration, err error) error {
+	t.err = err
+	return nil
+}
+
+// accessed returns the touched accounts and slots, sorted.
+func (t *accessTracer) accessed() []AccessedAccount {
+	accessed := make([]AccessedAccount, 0, len(t.touched))
+	for addr, slots := range t.touched {
+		keys := make([]common.Hash, 0, len(slots))
+		for key := range slots {
+			keys = append(keys, key)
+		}
+		sort.Slice(keys, func(i, j int) bool { return bytes.Compare(keys[i][:], keys[j][:]) < 0 })
+		accessed = append(accessed, AccessedAccount{Address: addr, StorageKeys: keys})
+	}
+	sort.Slice(accessed, func(i, j int) bool { return bytes.Compare(accessed[i].Address[:], accessed[j].Address[:]) < 0 })
+	return accessed
+}
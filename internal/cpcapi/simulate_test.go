@@ -0,0 +1,148 @@
+package cpcapi
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"bitbucket.org/cpchain/chain/accounts"
+	"bitbucket.org/cpchain/chain/api/rpc"
+	"bitbucket.org/cpchain/chain/configs"
+	"bitbucket.org/cpchain/chain/core"
+	"bitbucket.org/cpchain/chain/core/state"
+	"bitbucket.org/cpchain/chain/core/vm"
+	"bitbucket.org/cpchain/chain/database"
+	"bitbucket.org/cpchain/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+)
+
+// simulateBackend serves a fresh state at every block, only the methods used
+// by the simulation API are implemented.
+type simulateBackend struct {
+	Backend
+	db state.Database
+}
+
+func (b *simulateBackend) StateAndHeaderByNumber(ctx context.Context, blockNr rpc.BlockNumber, isPrivate bool) (*state.StateDB, *types.Header, error) {
+	st, err := state.New(common.Hash{}, b.db)
+	header := &types.Header{Number: big.NewInt(1), Time: big.NewInt(1000), GasLimit: math.MaxUint64 / 2}
+	return st, header, err
+}
+
+func (b *simulateBackend) GetEVM(ctx context.Context, msg core.Message, state *state.StateDB, header *types.Header, vmCfg vm.Config) (*vm.EVM, func() error, error) {
+	state.SetBalance(msg.From(), math.MaxBig256)
+	context := vm.Context{
+		CanTransfer: core.CanTransfer,
+		Transfer:    core.Transfer,
+		GetHash:     func(uint64) common.Hash { return common.Hash{} },
+		Origin:      msg.From(),
+		BlockNumber: new(big.Int).Set(header.Number),
+		Time:        big.NewInt(1),
+		Difficulty:  new(big.Int),
+		GasLimit:    header.GasLimit,
+		GasPrice:    msg.GasPrice(),
+	}
+	return vm.NewEVM(context, state, configs.TestChainConfig, vmCfg), func() error { return nil }, nil
+}
+
+func (b *simulateBackend) AccountManager() *accounts.Manager {
+	return accounts.NewManager()
+}
+
+var (
+	// guardCode reverts with Error("nope") unless storage slot 1 is set, and
+	// increments slot 2 otherwise.
+	guardCode = hexutil.MustDecode("0x" +
+		"600154601257" + // if sload(1) != 0 jump to 0x12
+		"6064601d600039" + // codecopy(0, 0x1d, 0x64)
+		"60646000fd" + // revert(0, 0x64)
+		"5b" + // 0x12: jumpdest
+		"6001600254016002" + "55" + // sstore(2, sload(2) + 1)
+		"00" + // stop
+		// Error("nope")
+		"08c379a0" +
+		"0000000000000000000000000000000000000000000000000000000000000020" +
+		"0000000000000000000000000000000000000000000000000000000000000004" +
+		"6e6f706500000000000000000000000000000000000000000000000000000000")
+
+	guardAddr  = common.HexToAddress("0x0000000000000000000000000000000000001234")
+	senderAddr = common.HexToAddress("0x0000000000000000000000000000000000005678")
+)
+
+func TestSimulate(t *testing.T) {
+	api := NewPublicSimulationAPI(&simulateBackend{db: state.NewDatabase(database.NewMemDatabase())})
+	call := CallArgs{From: senderAddr, To: &guardAddr}
+	slot1, slot2 := common.BigToHash(big.NewInt(1)), common.BigToHash(big.NewInt(2))
+
+	// without the slot set, the call reverts with its reason
+	overrides := StateOverride{guardAddr: {Code: (*hexutil.Bytes)(&guardCode)}}
+	results, err := api.Simulate(context.Background(), []CallArgs{call}, rpc.LatestBlockNumber, &overrides, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res := results[0]
+	if !res.Failed || res.RevertReason != "nope" || res.Error != vm.ErrExecutionReverted.Error() {
+		t.Fatalf("revert mismatch: failed %v, reason %q, error %q", res.Failed, res.RevertReason, res.Error)
+	}
+	if len(res.Accessed) != 2 || res.Accessed[0].Address != guardAddr || res.Accessed[1].Address != senderAddr {
+		t.Fatalf("accessed accounts mismatch: %+v", res.Accessed)
+	}
+	if keys := res.Accessed[0].StorageKeys; len(keys) != 1 || keys[0] != slot1 {
+		t.Fatalf("accessed slots mismatch: %x", keys)
+	}
+
+	// with the slot overridden, the calls see the effects of each other
+	overrides = StateOverride{guardAddr: {
+		Code:      (*hexutil.Bytes)(&guardCode),
+		StateDiff: map[common.Hash]common.Hash{slot1: slot1},
+	}}
+	results, err = api.Simulate(context.Background(), []CallArgs{call, call}, rpc.LatestBlockNumber, &overrides, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, res := range results {
+		if res.Failed {
+			t.Fatalf("call %d failed: %s", i, res.Error)
+		}
+		if keys := res.Accessed[0].StorageKeys; len(keys) != 2 || keys[0] != slot1 || keys[1] != slot2 {
+			t.Fatalf("call %d: accessed slots mismatch: %x", i, keys)
+		}
+	}
+	// the second call pays for changing a non zero slot
+	if results[0].GasUsed <= results[1].GasUsed {
+		t.Fatalf("gas mismatch: first %d, second %d", results[0].GasUsed, results[1].GasUsed)
+	}
+
+	// state and stateDiff overrides exclude each other
+	overrides = StateOverride{guardAddr: {
+		State:     map[common.Hash]common.Hash{},
+		StateDiff: map[common.Hash]common.Hash{},
+	}}
+	if _, err := api.Simulate(context.Background(), []CallArgs{call}, rpc.LatestBlockNumber, &overrides, nil); err == nil {
+		t.Fatal("expected error for state and stateDiff overrides")
+	}
+}
+
+func TestSimulateBlockOverrides(t *testing.T) {
+	api := NewPublicSimulationAPI(&simulateBackend{db: state.NewDatabase(database.NewMemDatabase())})
+	// return(number) and return(timestamp) with mstore(0, x)
+	numberCode := hexutil.Bytes(hexutil.MustDecode("0x4360005260206000f3"))
+	timeCode := hexutil.Bytes(hexutil.MustDecode("0x4260005260206000f3"))
+	numberAddr, timeAddr := common.Address{1}, common.Address{2}
+	overrides := StateOverride{numberAddr: {Code: &numberCode}, timeAddr: {Code: &timeCode}}
+	block := &BlockOverrides{Number: (*hexutil.Big)(big.NewInt(77)), Time: new(hexutil.Uint64)}
+	*block.Time = 1234
+
+	results, err := api.Simulate(context.Background(), []CallArgs{{To: &numberAddr}, {To: &timeAddr}}, rpc.LatestBlockNumber, &overrides, block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if have := new(big.Int).SetBytes(results[0].ReturnData); have.Int64() != 77 {
+		t.Errorf("number mismatch: have %v, want 77", have)
+	}
+	if have := new(big.Int).SetBytes(results[1].ReturnData); have.Int64() != 1234 {
+		t.Errorf("time mismatch: have %v, want 1234", have)
+	}
+}
@@ -103,8 +103,9 @@ type engine struct {
 	proc    core.Validator
 	chainDb database.Database
 
-	coinbase common.Address
-	extra    []byte
+	coinbase  common.Address
+	extra     []byte
+	gasTarget uint64 // gas limit the proposer votes for once voting is on, 0 follows the usage, atomic
 
 	currentMu   sync.RWMutex
 	currentWork *Work
@@ -157,6 +158,10 @@ func (e *engine) setExtra(extra []byte) {
 	e.extra = extra
 }
 
+func (e *engine) setGasTarget(target uint64) {
+	atomic.StoreUint64(&e.gasTarget, target)
+}
+
 func (e *engine) pending() (*types.Block, *state.StateDB) {
 	if atomic.LoadInt32(&e.mining) == 0 {
 		// return a snapshot to avoid contention on currentMu mutex
@@ -396,6 +401,9 @@ func (e *engine) commitNewWork() {
 		GasLimit:   core.CalcGasLimit(parent),
 		Extra:      e.extra,
 	}
+	if e.config.Dpor.IsGasLimitVoting(header.Number) {
+		header.GasLimit = core.CalcGasLimitToward(e.config.Dpor.GasLimit, parent, atomic.LoadUint64(&e.gasTarget))
+	}
 	// Only set the coinbase if we are mining (avoid spurious block rewards)
 	if atomic.LoadInt32(&e.mining) == 1 {
 		header.Coinbase = e.coinbase
@@ -137,6 +137,17 @@ func (m *Miner) SetExtra(extra []byte) error {
 	return nil
 }
 
+// SetGasTarget sets the gas limit the blocks mined vote for, 0 follows the
+// usage of the parent blocks. It applies once gas limit voting is on.
+func (m *Miner) SetGasTarget(target uint64) {
+	m.eng.setGasTarget(target)
+}
+
+// GasTarget returns the gas limit the blocks mined vote for.
+func (m *Miner) GasTarget() uint64 {
+	return atomic.LoadUint64(&m.eng.gasTarget)
+}
+
 // Pending returns the currently pending block and associated state.
 func (m *Miner) Pending() (*types.Block, *state.StateDB) {
 	return m.eng.pending()
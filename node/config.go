@@ -14,7 +14,10 @@ import (
 	"strings"
 
 	"bitbucket.org/cpchain/chain/accounts"
+	"bitbucket.org/cpchain/chain/accounts/external"
 	"bitbucket.org/cpchain/chain/accounts/keystore"
+	"bitbucket.org/cpchain/chain/accounts/pkcs11"
+	"bitbucket.org/cpchain/chain/api/rpc"
 	"bitbucket.org/cpchain/chain/configs"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
@@ -74,6 +77,16 @@ type Config struct {
 	// NoUSB disables hardware wallet monitoring and connectivity.
 	NoUSB bool `toml:",omitempty"`
 
+	// ExternalSigner is the IPC path or http(s) URL of an external signer. If
+	// set, the accounts of the signer are available next to the key store and
+	// their private keys never enter the node.
+	ExternalSigner string `toml:",omitempty"`
+
+	// PKCS11 selects a PKCS#11 token, e.g. a hardware security module. If its
+	// module is set, the secp256k1 keys of the token are available next to the
+	// key store and sign on the token.
+	PKCS11 pkcs11.Config
+
 	// IPCPath is the requested location to place the IPC endpoint. If the path is
 	// a simple file name, it is placed inside the data directory (or on the root
 	// pipe path on Windows), whereas if it's a resolvable path name (absolute or
@@ -107,6 +120,10 @@ type Config struct {
 	// exposed.
 	HTTPModules []string `toml:",omitempty"`
 
+	// HTTPREST enables the REST gateway under /v1/ on the HTTP endpoint, mapping
+	// REST requests to the methods exposed via HTTPModules.
+	HTTPREST bool `toml:",omitempty"`
+
 	// WSHost is the host interface on which to start the websocket RPC server. If
 	// this field is empty, no websocket API endpoint will be started.
 	WSHost string `toml:",omitempty"`
@@ -133,6 +150,30 @@ type Config struct {
 	// private APIs to untrusted users is a major security risk.
 	WSExposeAll bool `toml:",omitempty"`
 
+	// GraphQLHost is the host interface on which to start the GraphQL server. If
+	// this field is empty, no GraphQL endpoint will be started.
+	GraphQLHost string `toml:",omitempty"`
+
+	// GraphQLPort is the TCP port number on which to start the GraphQL server.
+	GraphQLPort int `toml:",omitempty"`
+
+	// GraphQLCors is the Cross-Origin Resource Sharing header to send to requesting
+	// clients of the GraphQL endpoint.
+	GraphQLCors []string `toml:",omitempty"`
+
+	// GraphQLVirtualHosts is the list of virtual hostnames which are allowed on
+	// incoming GraphQL requests.
+	GraphQLVirtualHosts []string `toml:",omitempty"`
+
+	// RPCAuth enables authentication and per method role policies on the HTTP and
+	// websocket RPC interfaces, and authentication on the GraphQL endpoint. If
+	// nil, they are open to anyone reaching the port.
+	RPCAuth *rpc.AuthConfig `toml:",omitempty"`
+
+	// RPCRateLimit enables per client rate limits, method costs and batch and
+	// response size limits on the HTTP and websocket RPC interfaces.
+	RPCRateLimit *rpc.RateLimitConfig `toml:",omitempty"`
+
 	// Logger is a custom logger to use with the p2p.Server.
 	Logger log.Logger `toml:",omitempty"`
 }
@@ -197,6 +238,15 @@ func DefaultHTTPEndpoint() string {
 	return config.HTTPEndpoint()
 }
 
+// GraphQLEndpoint resolves a GraphQL endpoint based on the configured host
+// interface and port parameters.
+func (c *Config) GraphQLEndpoint() string {
+	if c.GraphQLHost == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s:%d", c.GraphQLHost, c.GraphQLPort)
+}
+
 // WSEndpoint resolves a websocket endpoint based on the configured host interface
 // and port parameters.
 func (c *Config) WSEndpoint() string {
@@ -383,6 +433,20 @@ func makeAccountManager(conf *Config) (*accounts.Manager, string, error) {
 	backends := []accounts.Backend{
 		keystore.NewKeyStore(keydir, scryptN, scryptP),
 	}
+	if conf.ExternalSigner != "" {
+		signer, err := external.NewExternalBackend(conf.ExternalSigner)
+		if err != nil {
+			return nil, "", err
+		}
+		backends = append(backends, signer)
+	}
+	if conf.PKCS11.Module != "" {
+		token, err := pkcs11.NewBackend(conf.PKCS11)
+		if err != nil {
+			return nil, "", err
+		}
+		backends = append(backends, token)
+	}
 	return accounts.NewManager(backends...), ephemeral, nil
 }
 
@@ -393,19 +457,24 @@ const (
 	DefaultHTTPPort = 8545        // Default TCP port for the HTTP RPC server
 	DefaultWSHost   = "localhost" // Default host interface for the websocket RPC server
 	DefaultWSPort   = 8546        // Default TCP port for the websocket RPC server
+
+	DefaultGraphQLHost = "localhost" // Default host interface for the GraphQL server
+	DefaultGraphQLPort = 8547        // Default TCP port for the GraphQL server
 )
 
 // DefaultConfig contains reasonable default settings.
 var DefaultConfig = Config{
-	Name:             configs.ClientIdentifier,
-	Version:          configs.Version,
-	DataDir:          DefaultDataDir(),
-	IPCPath:          DefaultIPCEndpoint(configs.ClientIdentifier),
-	HTTPPort:         DefaultHTTPPort,
-	HTTPModules:      []string{"net", "web3", "eth", "cpc"},
-	HTTPVirtualHosts: []string{"localhost"},
-	WSPort:           DefaultWSPort,
-	WSModules:        []string{"net", "web3", "eth"},
+	Name:                configs.ClientIdentifier,
+	Version:             configs.Version,
+	DataDir:             DefaultDataDir(),
+	IPCPath:             DefaultIPCEndpoint(configs.ClientIdentifier),
+	HTTPPort:            DefaultHTTPPort,
+	HTTPModules:         []string{"net", "web3", "eth", "cpc"},
+	HTTPVirtualHosts:    []string{"localhost"},
+	WSPort:              DefaultWSPort,
+	WSModules:           []string{"net", "web3", "eth"},
+	GraphQLPort:         DefaultGraphQLPort,
+	GraphQLVirtualHosts: []string{"localhost"},
 	P2P: p2p.Config{
 		ListenAddr: ":30303",
 		MaxPeers:   25,
@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"net/http"
 	"os"
 	"path/filepath"
 	"reflect"
@@ -57,6 +58,10 @@ type Node struct {
 	wsListener net.Listener // Websocket RPC listener socket to server API requests
 	wsHandler  *rpc.Server  // Websocket RPC request handler to process the API requests
 
+	graphqlEndpoint string       // GraphQL endpoint (interface + port) to listen at (empty = GraphQL disabled)
+	graphqlListener net.Listener // GraphQL listener socket to serve queries
+	graphqlHandler  *rpc.Server  // RPC server authorizing queries if neither HTTP nor websocket is enabled
+
 	quitCh chan struct{} // Channel to wait for termination notifications
 
 	log log.Logger
@@ -106,6 +111,7 @@ func New(conf *Config) (*Node, error) {
 		ipcEndpoint:       conf.IPCEndpoint(),
 		httpEndpoint:      conf.HTTPEndpoint(),
 		wsEndpoint:        conf.WSEndpoint(),
+		graphqlEndpoint:   conf.GraphQLEndpoint(),
 		eventmux:          new(event.TypeMux),
 		log:               conf.Logger,
 	}, nil
@@ -259,12 +265,20 @@ func (n *Node) startRPC(services map[reflect.Type]Service) error {
 		n.stopInProc()
 		return err
 	}
+	if err := n.startGraphQL(n.graphqlEndpoint, services); err != nil {
+		n.stopWS()
+		n.stopHTTP()
+		n.stopIPC()
+		n.stopInProc()
+		return err
+	}
 	// All API endpoints started successfully
 	n.rpcAPIs = apis
 	return nil
 }
 
 func (n *Node) stopRPC() {
+	n.stopGraphQL()
 	n.stopWS()
 	n.stopInProc()
 	n.stopIPC()
@@ -331,11 +345,11 @@ func (n *Node) startHTTP(endpoint string, apis []rpc.API, modules []string, cors
 	if endpoint == "" {
 		return nil
 	}
-	listener, handler, err := rpc.StartHTTPEndpoint(endpoint, apis, modules, cors, vhosts)
+	listener, handler, err := rpc.StartHTTPEndpoint(endpoint, apis, modules, cors, vhosts, n.config.RPCAuth, n.config.RPCRateLimit, n.config.HTTPREST)
 	if err != nil {
 		return err
 	}
-	n.log.Info("HTTP endpoint opened", "url", fmt.Sprintf("http://%s", endpoint), "cors", strings.Join(cors, ","), "vhosts", strings.Join(vhosts, ","))
+	n.log.Info("HTTP endpoint opened", "url", fmt.Sprintf("http://%s", endpoint), "cors", strings.Join(cors, ","), "vhosts", strings.Join(vhosts, ","), "auth", n.config.RPCAuth != nil, "rest", n.config.HTTPREST)
 	// All listeners booted successfully
 	n.httpEndpoint = endpoint
 	n.httpListener = listener
@@ -364,7 +378,7 @@ func (n *Node) startWS(endpoint string, apis []rpc.API, modules []string, wsOrig
 	if endpoint == "" {
 		return nil
 	}
-	listener, handler, err := rpc.StartWSEndpoint(endpoint, apis, modules, wsOrigins, exposeAll)
+	listener, handler, err := rpc.StartWSEndpoint(endpoint, apis, modules, wsOrigins, exposeAll, n.config.RPCAuth, n.config.RPCRateLimit)
 	if err != nil {
 		return err
 	}
@@ -390,6 +404,70 @@ func (n *Node) stopWS() {
 	}
 }
 
+// startGraphQL initializes and starts the GraphQL endpoint, served by the first
+// service implementing GraphQLService.
+func (n *Node) startGraphQL(endpoint string, services map[reflect.Type]Service) error {
+	// Short circuit if the GraphQL endpoint isn't being exposed
+	if endpoint == "" {
+		return nil
+	}
+	var handler http.Handler
+	for _, service := range services {
+		if service, ok := service.(GraphQLService); ok {
+			handler = service.GraphQLHandler()
+			break
+		}
+	}
+	if handler == nil {
+		n.log.Warn("GraphQL endpoint not opened, no service serves GraphQL queries")
+		return nil
+	}
+	// queries are authorized and charged by the JSON-RPC server, so clients
+	// have a single budget across both endpoints
+	var own *rpc.Server
+	srv := n.httpHandler
+	if srv == nil {
+		srv = n.wsHandler
+	}
+	if srv == nil {
+		own = rpc.NewServer()
+		if err := own.SetAuth(n.config.RPCAuth); err != nil {
+			return err
+		}
+		own.SetRateLimits(n.config.RPCRateLimit)
+		srv = own
+	}
+	handler = srv.HTTPHandlerStack(handler, n.config.GraphQLCors, n.config.GraphQLVirtualHosts, "graphql_query")
+	listener, err := net.Listen("tcp", endpoint)
+	if err != nil {
+		if own != nil {
+			own.Stop()
+		}
+		return err
+	}
+	go rpc.NewHTTPServerWithHandler(handler).Serve(listener)
+	n.log.Info("GraphQL endpoint opened", "url", fmt.Sprintf("http://%s", endpoint), "cors", strings.Join(n.config.GraphQLCors, ","), "vhosts", strings.Join(n.config.GraphQLVirtualHosts, ","))
+
+	n.graphqlEndpoint = endpoint
+	n.graphqlListener = listener
+	n.graphqlHandler = own
+	return nil
+}
+
+// stopGraphQL terminates the GraphQL endpoint.
+func (n *Node) stopGraphQL() {
+	if n.graphqlListener != nil {
+		_ = n.graphqlListener.Close()
+		n.graphqlListener = nil
+
+		n.log.Info("GraphQL endpoint closed", "url", fmt.Sprintf("http://%s", n.graphqlEndpoint))
+	}
+	if n.graphqlHandler != nil {
+		n.graphqlHandler.Stop()
+		n.graphqlHandler = nil
+	}
+}
+
 // Stop terminates a running node along with all it's services. In the node was
 // not started, an error is returned.
 func (n *Node) Stop() error {
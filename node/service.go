@@ -3,6 +3,7 @@
 package node
 
 import (
+	"net/http"
 	"reflect"
 
 	"bitbucket.org/cpchain/chain/accounts"
@@ -82,3 +83,9 @@ type Service interface {
 	// are all terminated.
 	Stop() error
 }
+
+// GraphQLService is implemented by services which serve GraphQL queries on the
+// node's GraphQL endpoint.
+type GraphQLService interface {
+	GraphQLHandler() http.Handler
+}
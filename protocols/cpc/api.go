@@ -30,6 +30,8 @@ import (
 	"bitbucket.org/cpchain/chain/api/rpc"
 	"bitbucket.org/cpchain/chain/commons/log"
 	"bitbucket.org/cpchain/chain/configs"
+	"bitbucket.org/cpchain/chain/consensus/dpor"
+	"bitbucket.org/cpchain/chain/consensus/dpor/backend"
 	"bitbucket.org/cpchain/chain/core"
 	"bitbucket.org/cpchain/chain/core/rawdb"
 	"bitbucket.org/cpchain/chain/core/state"
@@ -176,6 +178,39 @@ func (api *PrivateMinerAPI) SetCoinbase(coinbase common.Address) bool {
 	return true
 }
 
+// SetGasTarget sets the gas limit the blocks of the miner vote for once gas
+// limit voting is on, 0 follows the usage of the parent blocks. Each block
+// moves the gas limit toward it by at most a step.
+func (api *PrivateMinerAPI) SetGasTarget(target uint64) bool {
+	// make sure the api executes in sequence(no parallel)
+	api.lock.Lock()
+	defer api.lock.Unlock()
+
+	api.c.Miner().SetGasTarget(target)
+	return true
+}
+
+// GasTarget returns the gas limit the blocks of the miner vote for.
+func (api *PrivateMinerAPI) GasTarget() uint64 {
+	return api.c.Miner().GasTarget()
+}
+
+// EnterMaintenance announces the blocks of the miner from block from to block
+// to are skipped. Once a proposer carries the notice in a block before from,
+// validators impeach them without waiting for the impeach timeout and the
+// skips are not penalised. It returns the notice broadcast.
+func (api *PrivateMinerAPI) EnterMaintenance(from uint64, to uint64) (*backend.MaintenanceNotice, error) {
+	// make sure the api executes in sequence(no parallel)
+	api.lock.Lock()
+	defer api.lock.Unlock()
+
+	engine, ok := api.c.engine.(*dpor.Dpor)
+	if !ok {
+		return nil, errors.New("maintenance is only supported by dpor")
+	}
+	return engine.EnterMaintenance(from, to)
+}
+
 // PrivateAdminAPI is the collection of cpchain full node-related APIs
 // exposed over the private admin endpoint.
 type PrivateAdminAPI struct {
@@ -21,16 +21,19 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"net/http"
 	"sync"
 	"sync/atomic"
 
 	"bitbucket.org/cpchain/chain/accounts"
 	"bitbucket.org/cpchain/chain/admission"
+	"bitbucket.org/cpchain/chain/api/graphql"
 	"bitbucket.org/cpchain/chain/api/rpc"
 	"bitbucket.org/cpchain/chain/commons/log"
 	"bitbucket.org/cpchain/chain/configs"
 	"bitbucket.org/cpchain/chain/consensus"
 	"bitbucket.org/cpchain/chain/consensus/dpor"
+	"bitbucket.org/cpchain/chain/consensus/dpor/backend"
 	"bitbucket.org/cpchain/chain/contracts/dpor/primitive_register"
 	"bitbucket.org/cpchain/chain/contracts/dpor/rpt_backend_holder"
 	"bitbucket.org/cpchain/chain/core"
@@ -44,6 +47,7 @@ import (
 	"bitbucket.org/cpchain/chain/private"
 	"bitbucket.org/cpchain/chain/protocols/cpc/filters"
 	"bitbucket.org/cpchain/chain/protocols/cpc/gasprice"
+	"bitbucket.org/cpchain/chain/protocols/cpc/rewards"
 	"bitbucket.org/cpchain/chain/protocols/cpc/syncer"
 	"bitbucket.org/cpchain/chain/types"
 	"github.com/ethereum/go-ethereum/common"
@@ -88,6 +92,7 @@ type CpchainService struct {
 
 	bloomRequests chan chan *bloombits.Retrieval // Channel receiving bloom data retrieval requests
 	bloomIndexer  *core.ChainIndexer             // LogsBloom indexer operating during block imports
+	rewardIndexer *rewards.Indexer               // Reward history indexer, nil if not enabled
 
 	// chain service backend
 	APIBackend          *APIBackend
@@ -118,8 +123,10 @@ func New(ctx *node.ServiceContext, config *Config) (*CpchainService, error) {
 	if err != nil {
 		return nil, err
 	}
-	chainConfig, genesisHash, genesisErr := core.SetupGenesisBlock(chainDb, config.Genesis)
-	if _, ok := genesisErr.(*configs.ConfigCompatError); genesisErr != nil && !ok {
+	// a config rescheduling a fork the chain has passed is refused, finalized
+	// blocks are never rewound
+	chainConfig, _, genesisErr := core.SetupGenesisBlock(chainDb, config.Genesis)
+	if genesisErr != nil {
 		return nil, genesisErr
 	}
 	log.Info("Initialised chain configuration", "config", chainConfig)
@@ -168,9 +175,11 @@ func New(ctx *node.ServiceContext, config *Config) (*CpchainService, error) {
 	contractClient := cpcapi.NewPublicBlockChainAPI(cpc.APIBackend)
 	rpt_backend_holder.GetApiBackendHolderInstance().Init(cpc.APIBackend, contractClient)
 	if dpor, ok := cpc.engine.(*dpor.Dpor); ok {
-		dpor.SetCandidateBackend(primitive_register.GetChainClient())
-		dpor.SetRptBackend(primitive_register.GetChainClient())
+		dpor.SetCandidateBackend(chainConfig, primitive_register.GetChainClient())
+		dpor.SetRptBackend(chainConfig, primitive_register.GetChainClient())
 		dpor.SetRNodeBackend(primitive_register.GetChainClient())
+		dpor.SetSignerBackend(primitive_register.GetChainClient())
+		dpor.SetPeriodBackend(primitive_register.GetChainClient())
 	}
 
 	log.Info("Initialising cpchain protocol", "versions", ProtocolVersions, "network", config.NetworkId)
@@ -200,20 +209,21 @@ func New(ctx *node.ServiceContext, config *Config) (*CpchainService, error) {
 		contractAddrs[configs.ContractAdmission],
 		contractAddrs[configs.ContractCampaign3],
 		contractAddrs[configs.ContractRnode])
+	cpc.AdmissionApiBackend.SetPlotDir(ctx.ResolvePath("plots"))
 
 	if dpor, ok := cpc.engine.(*dpor.Dpor); ok {
 		dpor.SetupAdmission(cpc.AdmissionApiBackend)
 		dpor.SetChain(cpc.blockchain)
 	}
 
-	// Rewind the chain in case of an incompatible config upgrade.
-	if compat, ok := genesisErr.(*configs.ConfigCompatError); ok {
-		log.Warn("Rewinding chain to upgrade configuration", "err", compat)
-		cpc.blockchain.SetHead(compat.RewindTo)
-		rawdb.WriteChainConfig(chainDb, genesisHash, chainConfig)
-	}
 	cpc.bloomIndexer.Start(cpc.blockchain)
 
+	if config.RewardIndex {
+		if cpc.rewardIndexer, err = rewards.NewIndexer(cpc.blockchain, chainDb, contractAddrs[configs.ContractReward]); err != nil {
+			return nil, err
+		}
+	}
+
 	if config.TxPool.Journal != "" {
 		config.TxPool.Journal = ctx.ResolvePath(config.TxPool.Journal)
 	}
@@ -263,19 +273,41 @@ func (s *CpchainService) CreateConsensusEngine(ctx *node.ServiceContext, chainCo
 	if chainConfig.Dpor != nil {
 		// TODO: fix this. @liuq
 		dpor := dpor.New(chainConfig.Dpor, db)
+		dpor.SetDporSigner(s.dporSigner)
 		if eb != (common.Address{}) {
 			wallet, err := s.accountManager.Find(accounts.Account{Address: eb})
 			if wallet == nil || err != nil {
 				log.Error("Etherbase account unavailable locally", "err", err)
 				return nil
 			}
-			dpor.Authorize(eb, wallet.SignHash)
+			dpor.Authorize(eb, s.signHash)
 		}
 		return dpor
 	}
 	return nil
 }
 
+// signHash signs a hash with a local account, the coinbase or the signing key
+// bound to it, which dpor selects for the term.
+func (s *CpchainService) signHash(account accounts.Account, hash []byte) ([]byte, error) {
+	wallet, err := s.accountManager.Find(account)
+	if err != nil {
+		return nil, err
+	}
+	return wallet.SignHash(account, hash)
+}
+
+// dporSigner returns the wallet of a local account if it signs Dpor msgs
+// itself instead of raw hashes, like the external signer.
+func (s *CpchainService) dporSigner(account accounts.Account) backend.DporSigner {
+	wallet, err := s.accountManager.Find(account)
+	if err != nil {
+		return nil
+	}
+	signer, _ := wallet.(backend.DporSigner)
+	return signer
+}
+
 // APIs return the collection of RPC services the cpc package offers.
 // NOTE, some of these services probably need to be moved to somewhere else.
 func (s *CpchainService) APIs() []rpc.API {
@@ -287,6 +319,10 @@ func (s *CpchainService) APIs() []rpc.API {
 	// Append any APIs exposed explicitly by the admission control
 	apis = append(apis, s.AdmissionApiBackend.Apis()...)
 
+	if s.rewardIndexer != nil {
+		apis = append(apis, rewards.APIs(s.rewardIndexer)...)
+	}
+
 	// Append all the local APIs and return
 	return append(apis, []rpc.API{
 		{
@@ -340,6 +376,12 @@ func (s *CpchainService) APIs() []rpc.API {
 	}...)
 }
 
+// GraphQLHandler returns the handler of the node's GraphQL endpoint, it
+// implements node.GraphQLService.
+func (s *CpchainService) GraphQLHandler() http.Handler {
+	return graphql.New(s.APIBackend)
+}
+
 func (s *CpchainService) ResetWithGenesisBlock(gb *types.Block) {
 	s.blockchain.ResetWithGenesisBlock(gb)
 }
@@ -392,7 +434,7 @@ func (s *CpchainService) StartMining(local bool) error {
 		if dpor.IsValidator() {
 			return errForbidValidatorMining
 		}
-		if s.AdmissionApiBackend.AdmissionKey() == nil {
+		if s.AdmissionApiBackend.AdmissionTransactor() == nil {
 			return errNotAdmissionKey
 		}
 
@@ -402,12 +444,15 @@ func (s *CpchainService) StartMining(local bool) error {
 				log.Error("Etherbase account unavailable locally", "err", err)
 				return nil
 			}
-			dpor.Authorize(coinbase, wallet.SignHash)
+			dpor.Authorize(coinbase, s.signHash)
 		}
 
 		log.Debug("server.nodeid", "enode", s.server.NodeInfo().Enode)
 
 		dpor.SetToCampaign(true)
+		if err := s.AdmissionApiBackend.StartCampaignManager(0); err != nil {
+			log.Warn("failed to start campaign manager", "error", err)
+		}
 
 		// make sure dpor.StartMining start once
 		dpor.SetAsMiner(true)
@@ -453,6 +498,7 @@ func (s *CpchainService) StopMining() {
 	if dpor, ok := s.engine.(*dpor.Dpor); ok {
 		// for dpor, keep miner mining, just stop participating campaign
 		dpor.SetToCampaign(false)
+		s.AdmissionApiBackend.StopCampaignManager()
 		log.Info("stopped participating campaign", "campaign", dpor.IsToCampaign())
 	} else {
 		s.miner.Stop()
@@ -521,6 +567,9 @@ func (s *CpchainService) Start(srvr *p2p.Server) error {
 	if s.lesServer != nil {
 		s.lesServer.Start(srvr)
 	}
+	if s.rewardIndexer != nil {
+		s.rewardIndexer.Start()
+	}
 
 	return nil
 }
@@ -529,6 +578,9 @@ func (s *CpchainService) Start(srvr *p2p.Server) error {
 // cpchain protocol.
 func (s *CpchainService) Stop() error {
 	s.bloomIndexer.Close()
+	if s.rewardIndexer != nil {
+		s.rewardIndexer.Stop()
+	}
 	s.blockchain.Stop()
 	s.protocolManager.Stop()
 	if s.lesServer != nil {
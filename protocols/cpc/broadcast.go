@@ -40,6 +40,9 @@ func (pm *ProtocolManager) BroadcastBlock(block *types.Block, propagate bool) {
 			return
 		}
 
+		// keep the block around to serve missing txs of compact blocks
+		pm.recentBlocks.Add(hash, block)
+
 		// Send the block to a subset of our peers
 		// transfer := peers[:int(math.Sqrt(float64(len(peers))))]
 		transfer := peers[:]
@@ -0,0 +1,249 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package cpc
+
+import (
+	"encoding/binary"
+	"errors"
+	"time"
+
+	"bitbucket.org/cpchain/chain/commons/log"
+	"bitbucket.org/cpchain/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const (
+	// shortIDMask keeps the lower 48 bits of a short transaction id.
+	shortIDMask = 1<<48 - 1
+
+	// maxPendingCompactBlocks is the maximum number of compact blocks waiting
+	// for missing transactions at the same time.
+	maxPendingCompactBlocks = 64
+
+	// compactBlockTimeout is the time to wait for the missing transactions of a
+	// compact block before retrieving the full block instead.
+	compactBlockTimeout = 2 * time.Second
+
+	// recentBlocksCacheSize is the number of recently propagated blocks kept to
+	// serve missing transaction and full block requests before they are inserted
+	// into the chain.
+	recentBlocksCacheSize = 16
+)
+
+var (
+	errBadPrefilledIndex = errors.New("invalid prefilled transaction index")
+	errBadBlockTxs       = errors.New("mismatched number of block transactions")
+	errTxsRootMismatch   = errors.New("reconstructed transactions root mismatch")
+)
+
+// compactBlockData is the network packet for compact block propagation. It
+// carries the header, the short ids of the transactions the remote peer is
+// expected to know and the transactions it is not.
+type compactBlockData struct {
+	Header    *types.Header
+	ShortIDs  []uint64
+	Prefilled []prefilledTx
+}
+
+// prefilledTx is a transaction sent in full within a compact block, together
+// with its index in the block.
+type prefilledTx struct {
+	Index uint64
+	Tx    *types.Transaction
+}
+
+// getBlockTxsData is the network packet requesting the missing transactions
+// of a compact block.
+type getBlockTxsData struct {
+	Hash    common.Hash
+	Indexes []uint64
+}
+
+// blockTxsData is the network packet answering a getBlockTxsData request.
+type blockTxsData struct {
+	Hash common.Hash
+	Txs  []*types.Transaction
+}
+
+// shortTxID returns the 48 bits short id of a transaction within a block. The
+// block hash is used as a salt so that collisions can not be precomputed.
+func shortTxID(blockHash common.Hash, txHash common.Hash) uint64 {
+	h := crypto.Keccak256(blockHash[:], txHash[:])
+	return binary.BigEndian.Uint64(h[:8]) & shortIDMask
+}
+
+// newCompactBlock builds a compact block, prefilling all transactions which are
+// not known to the remote peer.
+func newCompactBlock(block *types.Block, known func(hash common.Hash) bool) *compactBlockData {
+	var (
+		hash = block.Hash()
+		txs  = block.Transactions()
+		data = &compactBlockData{
+			Header:    block.Header(),
+			ShortIDs:  make([]uint64, 0, len(txs)),
+			Prefilled: make([]prefilledTx, 0),
+		}
+	)
+	for i, tx := range txs {
+		if known(tx.Hash()) {
+			data.ShortIDs = append(data.ShortIDs, shortTxID(hash, tx.Hash()))
+		} else {
+			data.Prefilled = append(data.Prefilled, prefilledTx{Index: uint64(i), Tx: tx})
+		}
+	}
+	return data
+}
+
+// compactBlockState is a compact block under reconstruction.
+type compactBlockState struct {
+	header     *types.Header
+	txs        []*types.Transaction
+	missing    []uint64
+	peer       string
+	receivedAt time.Time
+}
+
+// fill fills the missing transactions with the given ones, in order.
+func (s *compactBlockState) fill(txs []*types.Transaction) error {
+	if len(txs) != len(s.missing) {
+		return errBadBlockTxs
+	}
+	for i, idx := range s.missing {
+		s.txs[idx] = txs[i]
+	}
+	s.missing = nil
+	return nil
+}
+
+// block assembles the reconstructed block and checks it against the header.
+func (s *compactBlockState) block() (*types.Block, error) {
+	if types.DeriveSha(types.Transactions(s.txs)) != s.header.TxsRoot {
+		return nil, errTxsRootMismatch
+	}
+	return types.NewBlockWithHeader(s.header).WithBody(s.txs), nil
+}
+
+// reconstructCompactBlock rebuilds as much as possible of a compact block from
+// the prefilled transactions and the local transaction pool.
+func (pm *ProtocolManager) reconstructCompactBlock(data *compactBlockData) (*compactBlockState, error) {
+	var (
+		hash  = data.Header.Hash()
+		total = len(data.ShortIDs) + len(data.Prefilled)
+		state = &compactBlockState{
+			header: data.Header,
+			txs:    make([]*types.Transaction, total),
+		}
+		filled = make([]bool, total)
+	)
+
+	for i, prefilled := range data.Prefilled {
+		if prefilled.Tx == nil || prefilled.Index >= uint64(total) || (i > 0 && prefilled.Index <= data.Prefilled[i-1].Index) {
+			return nil, errBadPrefilledIndex
+		}
+		state.txs[prefilled.Index] = prefilled.Tx
+		filled[prefilled.Index] = true
+	}
+
+	// index the pool by short id, colliding ids are dropped and requested explicitly
+	index := make(map[uint64]common.Hash)
+	if len(data.ShortIDs) > 0 {
+		pending, _ := pm.txpool.Pending()
+		for _, txs := range pending {
+			for _, tx := range txs {
+				id := shortTxID(hash, tx.Hash())
+				if _, ok := index[id]; ok {
+					index[id] = common.Hash{}
+					continue
+				}
+				index[id] = tx.Hash()
+			}
+		}
+	}
+
+	next := 0
+	for i := range state.txs {
+		if filled[i] {
+			continue
+		}
+		if txHash, ok := index[data.ShortIDs[next]]; ok && txHash != (common.Hash{}) {
+			state.txs[i] = pm.txpool.Get(txHash)
+		}
+		if state.txs[i] == nil {
+			state.missing = append(state.missing, uint64(i))
+		}
+		next++
+	}
+	return state, nil
+}
+
+// addPendingCompactBlock stores a compact block waiting for its missing
+// transactions, evicting the oldest one if there are too many.
+func (pm *ProtocolManager) addPendingCompactBlock(hash common.Hash, state *compactBlockState) {
+	pm.compactLock.Lock()
+	defer pm.compactLock.Unlock()
+
+	if len(pm.compactPending) >= maxPendingCompactBlocks {
+		var (
+			oldest     common.Hash
+			oldestTime time.Time
+		)
+		for h, s := range pm.compactPending {
+			if oldestTime.IsZero() || s.receivedAt.Before(oldestTime) {
+				oldest, oldestTime = h, s.receivedAt
+			}
+		}
+		delete(pm.compactPending, oldest)
+	}
+	pm.compactPending[hash] = state
+}
+
+// takePendingCompactBlock removes and returns a compact block waiting for its
+// missing transactions.
+func (pm *ProtocolManager) takePendingCompactBlock(hash common.Hash) *compactBlockState {
+	pm.compactLock.Lock()
+	defer pm.compactLock.Unlock()
+
+	state := pm.compactPending[hash]
+	delete(pm.compactPending, hash)
+	return state
+}
+
+// expirePendingCompactBlock retrieves the full block of a compact block still
+// waiting for its missing transactions once the timeout is reached.
+func (pm *ProtocolManager) expirePendingCompactBlock(p *peer, hash common.Hash, state *compactBlockState) {
+	pm.compactLock.Lock()
+	expired := pm.compactPending[hash] == state
+	if expired {
+		delete(pm.compactPending, hash)
+	}
+	pm.compactLock.Unlock()
+
+	if expired {
+		log.Debug("missing txs of compact block timed out", "hash", hash.Hex(), "peer", p.id)
+		pm.fetchFullBlock(p, state.header)
+	}
+}
+
+// blockForRequest retrieves a block to serve a missing transactions or full
+// block request, either from the recently propagated ones or from the chain.
+func (pm *ProtocolManager) blockForRequest(hash common.Hash) *types.Block {
+	if block, ok := pm.recentBlocks.Get(hash); ok {
+		return block.(*types.Block)
+	}
+	return pm.blockchain.GetBlockByHash(hash)
+}
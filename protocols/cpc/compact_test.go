@@ -0,0 +1,252 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package cpc
+
+import (
+	"math/big"
+	"testing"
+
+	cconfigs "bitbucket.org/cpchain/chain/protocols/cpc/configs"
+	"bitbucket.org/cpchain/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/p2p"
+)
+
+func newTestCompactBlock(txs []*types.Transaction) *types.Block {
+	header := &types.Header{
+		Number: big.NewInt(100),
+		Time:   big.NewInt(0),
+	}
+	return types.NewBlock(header, txs, nil)
+}
+
+// Tests that a compact block is rebuilt from the pool and the prefilled txs,
+// and that the txs unknown to both are reported as missing.
+func TestCompactBlockReconstruction(t *testing.T) {
+	pm, _ := newTestProtocolManagerMust(t, 0, nil, nil)
+	defer pm.Stop()
+
+	txs := make([]*types.Transaction, 6)
+	for nonce := range txs {
+		txs[nonce] = newTestTransaction(testAccount, uint64(nonce), 0)
+	}
+	block := newTestCompactBlock(txs)
+
+	// the pool knows txs 0-3, the remote peer is believed to know txs 0-4
+	pm.txpool.AddRemotes(txs[:4])
+	compact := newCompactBlock(block, func(hash common.Hash) bool {
+		return hash != txs[5].Hash()
+	})
+	if len(compact.ShortIDs) != 5 || len(compact.Prefilled) != 1 {
+		t.Fatalf("wrong compact block: have %d short ids and %d prefilled, want 5 and 1", len(compact.ShortIDs), len(compact.Prefilled))
+	}
+
+	state, err := pm.reconstructCompactBlock(compact)
+	if err != nil {
+		t.Fatalf("failed to reconstruct compact block: %v", err)
+	}
+	if len(state.missing) != 1 || state.missing[0] != 4 {
+		t.Fatalf("wrong missing txs: have %v, want [4]", state.missing)
+	}
+	if err := state.fill(txs[4:5]); err != nil {
+		t.Fatalf("failed to fill compact block: %v", err)
+	}
+	rebuilt, err := state.block()
+	if err != nil {
+		t.Fatalf("failed to assemble compact block: %v", err)
+	}
+	if rebuilt.Hash() != block.Hash() || rebuilt.Transactions().Len() != len(txs) {
+		t.Fatalf("rebuilt block mismatch: have %x with %d txs, want %x with %d txs", rebuilt.Hash(), rebuilt.Transactions().Len(), block.Hash(), len(txs))
+	}
+
+	// filling with wrong transactions must be detected against the header
+	state, _ = pm.reconstructCompactBlock(compact)
+	state.fill(txs[0:1])
+	if _, err := state.block(); err != errTxsRootMismatch {
+		t.Fatalf("wrong error for bad txs: have %v, want %v", err, errTxsRootMismatch)
+	}
+}
+
+// Tests that malformed prefilled indexes are rejected.
+func TestCompactBlockBadPrefilled(t *testing.T) {
+	pm, _ := newTestProtocolManagerMust(t, 0, nil, nil)
+	defer pm.Stop()
+
+	tx := newTestTransaction(testAccount, 0, 0)
+	block := newTestCompactBlock([]*types.Transaction{tx})
+
+	tests := [][]prefilledTx{
+		{{Index: 1, Tx: tx}},
+		{{Index: 0, Tx: nil}},
+		{{Index: 0, Tx: tx}, {Index: 0, Tx: tx}},
+	}
+	for i, prefilled := range tests {
+		compact := &compactBlockData{Header: block.Header(), Prefilled: prefilled}
+		if _, err := pm.reconstructCompactBlock(compact); err != errBadPrefilledIndex {
+			t.Errorf("test %d: wrong error: have %v, want %v", i, err, errBadPrefilledIndex)
+		}
+	}
+}
+
+// Tests that a compact block with unknown txs triggers a missing txs request.
+func TestCompactBlockRequestsMissingTxsCpc2(t *testing.T) {
+	pm, _ := newTestProtocolManagerMust(t, 0, nil, nil)
+	defer pm.Stop()
+
+	p, _ := newTestPeer("peer", cconfigs.Cpc2, pm, true)
+	defer p.close()
+
+	txs := make([]*types.Transaction, 3)
+	for nonce := range txs {
+		txs[nonce] = newTestTransaction(testAccount, uint64(nonce), 0)
+	}
+	block := newTestCompactBlock(txs)
+	compact := newCompactBlock(block, func(common.Hash) bool { return true })
+
+	if err := p2p.Send(p.app, CompactBlockMsg, compact); err != nil {
+		t.Fatalf("send error: %v", err)
+	}
+	want := &getBlockTxsData{Hash: block.Hash(), Indexes: []uint64{0, 1, 2}}
+	if err := p2p.ExpectMsg(p.app, GetBlockTxsMsg, want); err != nil {
+		t.Fatalf("missing txs request mismatch: %v", err)
+	}
+}
+
+// Tests that missing txs requests are served from recently propagated blocks.
+func TestGetBlockTxsCpc2(t *testing.T) {
+	pm, _ := newTestProtocolManagerMust(t, 0, nil, nil)
+	defer pm.Stop()
+
+	p, _ := newTestPeer("peer", cconfigs.Cpc2, pm, true)
+	defer p.close()
+
+	txs := make([]*types.Transaction, 3)
+	for nonce := range txs {
+		txs[nonce] = newTestTransaction(testAccount, uint64(nonce), 0)
+	}
+	block := newTestCompactBlock(txs)
+	pm.recentBlocks.Add(block.Hash(), block)
+
+	if err := p2p.Send(p.app, GetBlockTxsMsg, &getBlockTxsData{Hash: block.Hash(), Indexes: []uint64{2, 0}}); err != nil {
+		t.Fatalf("send error: %v", err)
+	}
+	want := &blockTxsData{Hash: block.Hash(), Txs: []*types.Transaction{txs[2], txs[0]}}
+	if err := p2p.ExpectMsg(p.app, BlockTxsMsg, want); err != nil {
+		t.Fatalf("block txs response mismatch: %v", err)
+	}
+}
+
+// Tests that a missing txs request for an unknown block is answered with no
+// txs, so the requester falls back to a full block retrieval.
+func TestGetBlockTxsUnknownCpc2(t *testing.T) {
+	pm, _ := newTestProtocolManagerMust(t, 0, nil, nil)
+	defer pm.Stop()
+
+	p, _ := newTestPeer("peer", cconfigs.Cpc2, pm, true)
+	defer p.close()
+
+	hash := common.Hash{0x01}
+	if err := p2p.Send(p.app, GetBlockTxsMsg, &getBlockTxsData{Hash: hash, Indexes: []uint64{0}}); err != nil {
+		t.Fatalf("send error: %v", err)
+	}
+	if err := p2p.ExpectMsg(p.app, BlockTxsMsg, &blockTxsData{Hash: hash}); err != nil {
+		t.Fatalf("block txs response mismatch: %v", err)
+	}
+}
+
+// Tests that full block requests are served from recently propagated blocks,
+// which are not inserted into the chain yet.
+func TestGetFullBlockCpc2(t *testing.T) {
+	pm, _ := newTestProtocolManagerMust(t, 0, nil, nil)
+	defer pm.Stop()
+
+	p, _ := newTestPeer("peer", cconfigs.Cpc2, pm, true)
+	defer p.close()
+
+	txs := make([]*types.Transaction, 2)
+	for nonce := range txs {
+		txs[nonce] = newTestTransaction(testAccount, uint64(nonce), 0)
+	}
+	block := newTestCompactBlock(txs)
+	pm.recentBlocks.Add(block.Hash(), block)
+
+	if err := p2p.Send(p.app, GetFullBlockMsg, block.Hash()); err != nil {
+		t.Fatalf("send error: %v", err)
+	}
+	if err := p2p.ExpectMsg(p.app, NewBlockMsg, []interface{}{block}); err != nil {
+		t.Fatalf("full block response mismatch: %v", err)
+	}
+}
+
+// Tests that a compact block which can not be completed with the returned txs
+// falls back to requesting the full block from the announcing peer.
+func TestCompactBlockFullBlockFallbackCpc2(t *testing.T) {
+	pm, _ := newTestProtocolManagerMust(t, 0, nil, nil)
+	defer pm.Stop()
+
+	p, _ := newTestPeer("peer", cconfigs.Cpc2, pm, true)
+	defer p.close()
+
+	txs := make([]*types.Transaction, 2)
+	for nonce := range txs {
+		txs[nonce] = newTestTransaction(testAccount, uint64(nonce), 0)
+	}
+	block := newTestCompactBlock(txs)
+	compact := newCompactBlock(block, func(common.Hash) bool { return true })
+
+	if err := p2p.Send(p.app, CompactBlockMsg, compact); err != nil {
+		t.Fatalf("send error: %v", err)
+	}
+	want := &getBlockTxsData{Hash: block.Hash(), Indexes: []uint64{0, 1}}
+	if err := p2p.ExpectMsg(p.app, GetBlockTxsMsg, want); err != nil {
+		t.Fatalf("missing txs request mismatch: %v", err)
+	}
+	// the peer does not know the txs anymore, answering with none
+	if err := p2p.Send(p.app, BlockTxsMsg, &blockTxsData{Hash: block.Hash()}); err != nil {
+		t.Fatalf("send error: %v", err)
+	}
+	if err := p2p.ExpectMsg(p.app, GetFullBlockMsg, block.Hash()); err != nil {
+		t.Fatalf("full block request mismatch: %v", err)
+	}
+}
+
+// Tests that a compact block waiting for its missing txs is only expired once.
+func TestExpirePendingCompactBlock(t *testing.T) {
+	pm, _ := newTestProtocolManagerMust(t, 0, nil, nil)
+	defer pm.Stop()
+
+	p, _ := newTestPeer("peer", cconfigs.Cpc2, pm, true)
+	defer p.close()
+
+	block := newTestCompactBlock(nil)
+	stale := &compactBlockState{header: block.Header()}
+	state := &compactBlockState{header: block.Header()}
+	pm.addPendingCompactBlock(block.Hash(), state)
+
+	// a timeout of a replaced state leaves the pending one
+	pm.expirePendingCompactBlock(p.peer, block.Hash(), stale)
+	if pm.compactPending[block.Hash()] != state {
+		t.Fatal("pending compact block expired by a stale timeout")
+	}
+	go pm.expirePendingCompactBlock(p.peer, block.Hash(), state)
+	if err := p2p.ExpectMsg(p.app, GetFullBlockMsg, block.Hash()); err != nil {
+		t.Fatalf("full block request mismatch: %v", err)
+	}
+	if pm.takePendingCompactBlock(block.Hash()) != nil {
+		t.Fatal("pending compact block not expired")
+	}
+}
@@ -95,6 +95,9 @@ type Config struct {
 	// Enables tracking of SHA3 preimages in the VM
 	EnablePreimageRecording bool
 
+	// Enables indexing the reward history of addresses
+	RewardIndex bool
+
 	// Miscellaneous options
 	DocRoot string `toml:"-"`
 
@@ -3,4 +3,8 @@ package configs
 // Constants to match up protocol versions and messages
 const (
 	Cpc1 = 1
+
+	// Cpc2 adds compact block propagation (CompactBlockMsg, GetBlockTxsMsg, BlockTxsMsg)
+	// and dpor maintenance notices (MaintenanceNoticeMsg)
+	Cpc2 = 2
 )
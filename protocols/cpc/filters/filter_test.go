@@ -23,10 +23,14 @@ import (
 	"os"
 	"testing"
 
+	"bitbucket.org/cpchain/chain/api/rpc"
 	"bitbucket.org/cpchain/chain/configs"
+	"bitbucket.org/cpchain/chain/consensus"
 	"bitbucket.org/cpchain/chain/consensus/dpor"
 	"bitbucket.org/cpchain/chain/core"
 	"bitbucket.org/cpchain/chain/core/rawdb"
+	"bitbucket.org/cpchain/chain/core/state"
+	"bitbucket.org/cpchain/chain/core/vm"
 	"bitbucket.org/cpchain/chain/database"
 	"bitbucket.org/cpchain/chain/types"
 	"github.com/ethereum/go-ethereum/common"
@@ -239,3 +243,77 @@ func TestFilters(t *testing.T) {
 		t.Error("expected 0 log, got", len(logs))
 	}
 }
+
+// penaltyEngine is a faking dpor engine deducting a penalty in every block it
+// finalizes, logged as the slashing of an impeached proposer is.
+type penaltyEngine struct {
+	*dpor.Dpor
+	offender common.Address
+}
+
+func (e *penaltyEngine) Finalize(chain consensus.ChainReader, header *types.Header, state *state.StateDB, txs []*types.Transaction, uncles []*types.Header, receipts []*types.Receipt) (*types.Block, error) {
+	state.Prepare(common.Hash{}, common.Hash{}, len(txs))
+	state.AddLog(&types.Log{
+		Address:     common.Address{0x0d},
+		Topics:      []common.Hash{dpor.PenaltyEvent, common.BytesToHash(e.offender.Bytes())},
+		BlockNumber: header.Number.Uint64(),
+	})
+	return e.Dpor.Finalize(chain, header, state, txs, uncles, receipts)
+}
+
+// Tests that the logs a block emits while it is finalized, such as penalties,
+// are stored in the receipt of the block and returned by cpc_getLogs.
+func TestFinalizeLogs(t *testing.T) {
+	var (
+		db       = database.NewMemDatabase()
+		remoteDB = database.NewIpfsDbWithAdapter(database.NewFakeIpfsAdapter())
+		backend  = &testBackend{new(event.TypeMux), db, 0, new(event.Feed), new(event.Feed), new(event.Feed), new(event.Feed)}
+		key, _   = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		addr     = crypto.PubkeyToAddress(key.PublicKey)
+		offender = common.Address{0x01}
+		signer   = types.MakeSigner(configs.ChainConfigInfo())
+	)
+	genesis := core.GenesisBlockForTesting(db, addr, big.NewInt(1000000000000000000))
+	engine := &penaltyEngine{Dpor: dpor.NewFaker(configs.ChainConfigInfo().Dpor, db), offender: offender}
+
+	chain, _ := core.GenerateChain(configs.ChainConfigInfo(), genesis, engine, db, remoteDB, 3, func(i int, gen *core.BlockGen) {
+		gen.SetCoinbase(addr)
+		if i == 1 {
+			tx, _ := types.SignTx(types.NewTransaction(gen.TxNonce(addr), common.Address{0x02}, big.NewInt(1), configs.TxGas, nil, nil), signer, key)
+			gen.AddTx(tx)
+		}
+	})
+	blockchain, err := core.NewBlockChain(db, nil, configs.ChainConfigInfo(), engine, vm.Config{}, remoteDB, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer blockchain.Stop()
+	if _, err := blockchain.InsertChain(chain); err != nil {
+		t.Fatal(err)
+	}
+
+	// the receipt of the block follows the ones of its transactions
+	if receipts := rawdb.ReadReceipts(db, chain[1].Hash(), chain[1].NumberU64()); len(receipts) != 2 || len(receipts[1].Logs) != 1 {
+		t.Fatalf("receipts %v, want the one of the tx and the one of the block", receipts)
+	}
+
+	api := NewPublicFilterAPI(backend, false)
+	from, to := rpc.BlockNumber(1), rpc.LatestBlockNumber
+	logs, err := api.GetLogs(context.Background(), FilterCriteria{
+		FromBlock: big.NewInt(from.Int64()),
+		ToBlock:   big.NewInt(to.Int64()),
+		Topics:    [][]common.Hash{{dpor.PenaltyEvent}, {common.BytesToHash(offender.Bytes())}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(logs) != len(chain) {
+		t.Fatalf("%d logs, want one of each block", len(logs))
+	}
+	for i, l := range logs {
+		block := chain[i]
+		if l.BlockNumber != block.NumberU64() || l.BlockHash != block.Hash() || l.TxHash != (common.Hash{}) || l.TxIndex != uint(len(block.Transactions())) {
+			t.Errorf("log %d: %+v, want the block log of block %d", i, l, block.NumberU64())
+		}
+	}
+}
@@ -35,6 +35,7 @@ func (c Config) MarshalTOML() (interface{}, error) {
 		TxPool                  core.TxPoolConfig
 		GPO                     gasprice.Config
 		EnablePreimageRecording bool
+		RewardIndex             bool
 		DocRoot                 string `toml:"-"`
 		PrivateTx               private.Config
 	}
@@ -56,6 +57,7 @@ func (c Config) MarshalTOML() (interface{}, error) {
 	enc.TxPool = c.TxPool
 	enc.GPO = c.GPO
 	enc.EnablePreimageRecording = c.EnablePreimageRecording
+	enc.RewardIndex = c.RewardIndex
 	enc.DocRoot = c.DocRoot
 	enc.PrivateTx = c.PrivateTx
 	return &enc, nil
@@ -81,6 +83,7 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 		TxPool                  *core.TxPoolConfig
 		GPO                     *gasprice.Config
 		EnablePreimageRecording *bool
+		RewardIndex             *bool
 		DocRoot                 *string `toml:"-"`
 		PrivateTx               *private.Config
 	}
@@ -139,6 +142,9 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.EnablePreimageRecording != nil {
 		c.EnablePreimageRecording = *dec.EnablePreimageRecording
 	}
+	if dec.RewardIndex != nil {
+		c.RewardIndex = *dec.RewardIndex
+	}
 	if dec.DocRoot != nil {
 		c.DocRoot = *dec.DocRoot
 	}
@@ -20,6 +20,7 @@ import (
 	"bitbucket.org/cpchain/chain/consensus/dpor/backend"
 	"bitbucket.org/cpchain/chain/core"
 	"bitbucket.org/cpchain/chain/database"
+	cconfigs "bitbucket.org/cpchain/chain/protocols/cpc/configs"
 	"bitbucket.org/cpchain/chain/protocols/cpc/fetcher"
 	"bitbucket.org/cpchain/chain/protocols/cpc/syncer"
 	"bitbucket.org/cpchain/chain/types"
@@ -28,6 +29,7 @@ import (
 	"github.com/ethereum/go-ethereum/p2p"
 	"github.com/ethereum/go-ethereum/p2p/discover"
 	"github.com/ethereum/go-ethereum/rlp"
+	lru "github.com/hashicorp/golang-lru"
 )
 
 const (
@@ -68,6 +70,11 @@ type ProtocolManager struct {
 	fetcher *fetcher.Fetcher
 	peers   *peerSet
 
+	// compact block propagation
+	compactPending map[common.Hash]*compactBlockState // compact blocks waiting for missing txs
+	compactLock    sync.Mutex                         // protects compactPending
+	recentBlocks   *lru.Cache                         // recently propagated blocks, serving missing txs requests
+
 	SubProtocols []p2p.Protocol
 
 	eventMux      *event.TypeMux
@@ -108,15 +115,20 @@ func NewProtocolManager(config *configs.ChainConfig, networkID uint64, mux *even
 		txsyncCh:    make(chan *txsync),
 		quitSync:    make(chan struct{}),
 
+		compactPending: make(map[common.Hash]*compactBlockState),
+
 		engine:   engine,
 		coinbase: coinbase,
 		syncMode: syncMode,
 	}
 
+	manager.recentBlocks, _ = lru.New(recentBlocksCacheSize)
+
 	// initialize a sub-protocol for every implemented version we can handle
 	manager.SubProtocols = make([]p2p.Protocol, 0, len(ProtocolVersions))
 
 	for i, version := range ProtocolVersions {
+		version := version // closure for the run
 		// compatible; initialise the sub-protocol
 		manager.SubProtocols = append(manager.SubProtocols, p2p.Protocol{
 			Name:    ProtocolName,
@@ -258,7 +270,7 @@ func (pm *ProtocolManager) newPeer(pv int, p *p2p.Peer, rw p2p.MsgReadWriter) *p
 // when this function terminates, the peer is disconnected.
 func (pm *ProtocolManager) addPeer(p *peer, isMinerOrValidator bool) (bool, error) {
 	// ignore maxPeers if this is a trusted or a static peer
-	if pm.peers.Len() >= pm.maxPeers && !(p.Peer.Info().Network.Trusted || p.Peer.Info().Network.Static){
+	if pm.peers.Len() >= pm.maxPeers && !(p.Peer.Info().Network.Trusted || p.Peer.Info().Network.Static) {
 		return false, p2p.DiscTooManyPeers
 	}
 
@@ -723,30 +735,114 @@ func (pm *ProtocolManager) handleSyncMsg(msg p2p.Msg, p *peer) error {
 		if err := msg.Decode(&request); err != nil {
 			return errResp(ErrDecode, "%v: %v", msg, err)
 		}
-		request.Block.ReceivedAt = msg.ReceivedAt
-		request.Block.ReceivedFrom = p
 
 		log.Debug("received NewBlockMsg", "hash", request.Block.Hash().Hex(), "number", request.Block.NumberU64())
 
-		// mark the peer as owning the block and schedule it for import
-		p.MarkBlock(request.Block.Hash())
-		// notify fetcher to inject the block
-		pm.fetcher.Enqueue(p.id, request.Block)
+		pm.importPropagatedBlock(p, request.Block, msg.ReceivedAt)
+
+	case msg.Code == CompactBlockMsg && p.version >= cconfigs.Cpc2:
+		// Retrieve and decode the compact block
+		var request compactBlockData
+		if err := msg.Decode(&request); err != nil {
+			return errResp(ErrDecode, "%v: %v", msg, err)
+		}
+		if request.Header == nil {
+			return errResp(ErrDecode, "compact block without header")
+		}
+		var (
+			hash   = request.Header.Hash()
+			number = request.Header.Number.Uint64()
+		)
+
+		log.Debug("received CompactBlockMsg", "hash", hash.Hex(), "number", number, "shortIDs", len(request.ShortIDs), "prefilled", len(request.Prefilled))
+
+		p.MarkBlock(hash)
+		if pm.blockchain.HasBlock(hash, number) {
+			break
+		}
+
+		state, err := pm.reconstructCompactBlock(&request)
+		if err != nil {
+			return errResp(ErrDecode, "%v: %v", msg, err)
+		}
+		state.peer, state.receivedAt = p.id, msg.ReceivedAt
+
+		// request the transactions we do not have
+		if len(state.missing) > 0 {
+			log.Debug("requesting missing txs of compact block", "hash", hash.Hex(), "number", number, "missing", len(state.missing))
+
+			pm.addPendingCompactBlock(hash, state)
+			time.AfterFunc(compactBlockTimeout, func() { pm.expirePendingCompactBlock(p, hash, state) })
+			return p.RequestBlockTxs(hash, state.missing)
+		}
+
+		pm.importCompactBlock(p, state)
+
+	case msg.Code == GetBlockTxsMsg && p.version >= cconfigs.Cpc2:
+		var request getBlockTxsData
+		if err := msg.Decode(&request); err != nil {
+			return errResp(ErrDecode, "%v: %v", msg, err)
+		}
+
+		log.Debug("received GetBlockTxsMsg", "hash", request.Hash.Hex(), "len", len(request.Indexes))
+
+		block := pm.blockForRequest(request.Hash)
+		if block == nil {
+			// reply with no txs, so the requester falls back to a full block retrieval
+			return p.SendBlockTxs(request.Hash, nil)
+		}
 		var (
-			trueHead   = request.Block.Hash()
-			trueHeight = request.Block.Number()
+			all = block.Transactions()
+			txs = make([]*types.Transaction, 0, len(request.Indexes))
 		)
-		// Update the peers total difficulty if better than the previous
-		if _, ht := p.Head(); trueHeight.Cmp(ht) > 0 {
-			p.SetHead(trueHead, trueHeight)
-
-			currentBlock := pm.blockchain.CurrentBlock()
-			if trueHeight.Cmp(currentBlock.Number()) > 0 {
-				// bulk sync from the peer
-				go pm.synchronize(p)
+		for _, idx := range request.Indexes {
+			if idx >= uint64(len(all)) {
+				return errResp(ErrDecode, "tx index %d out of range [0, %d)", idx, len(all))
 			}
+			txs = append(txs, all[idx])
+		}
+		return p.SendBlockTxs(request.Hash, txs)
+
+	case msg.Code == BlockTxsMsg && p.version >= cconfigs.Cpc2:
+		var request blockTxsData
+		if err := msg.Decode(&request); err != nil {
+			return errResp(ErrDecode, "%v: %v", msg, err)
 		}
 
+		log.Debug("received BlockTxsMsg", "hash", request.Hash.Hex(), "len", len(request.Txs))
+
+		state := pm.takePendingCompactBlock(request.Hash)
+		if state == nil {
+			break
+		}
+		for _, tx := range request.Txs {
+			if tx == nil {
+				return errResp(ErrDecode, "nil transaction for compact block %x", request.Hash)
+			}
+			p.MarkTransaction(tx.Hash())
+		}
+		if err := state.fill(request.Txs); err != nil {
+			log.Debug("failed to fill compact block", "hash", request.Hash.Hex(), "err", err)
+			pm.fetchFullBlock(p, state.header)
+			break
+		}
+
+		pm.importCompactBlock(p, state)
+
+	case msg.Code == GetFullBlockMsg && p.version >= cconfigs.Cpc2:
+		var hash common.Hash
+		if err := msg.Decode(&hash); err != nil {
+			return errResp(ErrDecode, "%v: %v", msg, err)
+		}
+
+		log.Debug("received GetFullBlockMsg", "hash", hash.Hex())
+
+		block := pm.blockForRequest(hash)
+		if block == nil {
+			break
+		}
+		return p.SendNewBlock(block)
+
 	case msg.Code == TxMsg:
 		// Transactions arrived, make sure we have a valid and fresh chain to handle them
 		if atomic.LoadUint32(&pm.acceptTxs) == 0 {
@@ -818,6 +914,53 @@ func (pm *ProtocolManager) handleSyncMsg(msg p2p.Msg, p *peer) error {
 	return nil
 }
 
+// importPropagatedBlock schedules a propagated block for import and starts
+// syncing with the peer if it is ahead of us.
+func (pm *ProtocolManager) importPropagatedBlock(p *peer, block *types.Block, receivedAt time.Time) {
+	block.ReceivedAt = receivedAt
+	block.ReceivedFrom = p
+
+	// mark the peer as owning the block and schedule it for import
+	p.MarkBlock(block.Hash())
+	// notify fetcher to inject the block
+	pm.fetcher.Enqueue(p.id, block)
+	var (
+		trueHead   = block.Hash()
+		trueHeight = block.Number()
+	)
+	// Update the peers total difficulty if better than the previous
+	if _, ht := p.Head(); trueHeight.Cmp(ht) > 0 {
+		p.SetHead(trueHead, trueHeight)
+
+		currentBlock := pm.blockchain.CurrentBlock()
+		if trueHeight.Cmp(currentBlock.Number()) > 0 {
+			// bulk sync from the peer
+			go pm.synchronize(p)
+		}
+	}
+}
+
+// importCompactBlock imports a fully reconstructed compact block, falling back
+// to a full block retrieval if the reconstruction turns out to be wrong.
+func (pm *ProtocolManager) importCompactBlock(p *peer, state *compactBlockState) {
+	block, err := state.block()
+	if err != nil {
+		log.Debug("failed to reconstruct compact block", "hash", state.header.Hash().Hex(), "err", err)
+		pm.fetchFullBlock(p, state.header)
+		return
+	}
+	pm.importPropagatedBlock(p, block, state.receivedAt)
+}
+
+// fetchFullBlock requests the whole block from the announcing peer, used if a
+// compact block can not be reconstructed. The peer serves it from its recently
+// propagated blocks, so this works before the block is inserted on its side.
+func (pm *ProtocolManager) fetchFullBlock(p *peer, header *types.Header) {
+	if err := p.RequestFullBlock(header.Hash()); err != nil {
+		log.Debug("failed to request full block", "hash", header.Hash().Hex(), "err", err)
+	}
+}
+
 // NodeInfo represents a short summary of the Cpchain sub-protocol metadata
 // known about the host peer.
 type NodeInfo struct {
@@ -56,6 +56,18 @@ var (
 	miscOutTrafficMeter       = metrics.NewRegisteredMeter("eth/misc/out/traffic", nil)
 )
 
+// compact block propagation meters
+var (
+	propCompactInPacketsMeter  = metrics.NewRegisteredMeter("eth/prop/compact/in/packets", nil)
+	propCompactInTrafficMeter  = metrics.NewRegisteredMeter("eth/prop/compact/in/traffic", nil)
+	propCompactOutPacketsMeter = metrics.NewRegisteredMeter("eth/prop/compact/out/packets", nil)
+	propCompactOutTrafficMeter = metrics.NewRegisteredMeter("eth/prop/compact/out/traffic", nil)
+	reqBlockTxsInPacketsMeter  = metrics.NewRegisteredMeter("eth/req/blocktxs/in/packets", nil)
+	reqBlockTxsInTrafficMeter  = metrics.NewRegisteredMeter("eth/req/blocktxs/in/traffic", nil)
+	reqBlockTxsOutPacketsMeter = metrics.NewRegisteredMeter("eth/req/blocktxs/out/packets", nil)
+	reqBlockTxsOutTrafficMeter = metrics.NewRegisteredMeter("eth/req/blocktxs/out/traffic", nil)
+)
+
 // meteredMsgReadWriter is a wrapper around a p2p.MsgReadWriter, capable of
 // accumulating the above defined metrics based on the data stream contents.
 type meteredMsgReadWriter struct {
@@ -101,6 +113,10 @@ func (rw *meteredMsgReadWriter) ReadMsg() (p2p.Msg, error) {
 		packets, traffic = propHashInPacketsMeter, propHashInTrafficMeter
 	case msg.Code == NewBlockMsg:
 		packets, traffic = propBlockInPacketsMeter, propBlockInTrafficMeter
+	case msg.Code == CompactBlockMsg:
+		packets, traffic = propCompactInPacketsMeter, propCompactInTrafficMeter
+	case msg.Code == BlockTxsMsg:
+		packets, traffic = reqBlockTxsInPacketsMeter, reqBlockTxsInTrafficMeter
 	case msg.Code == TxMsg:
 		packets, traffic = propTxnInPacketsMeter, propTxnInTrafficMeter
 	}
@@ -128,6 +144,10 @@ func (rw *meteredMsgReadWriter) WriteMsg(msg p2p.Msg) error {
 		packets, traffic = propHashOutPacketsMeter, propHashOutTrafficMeter
 	case msg.Code == NewBlockMsg:
 		packets, traffic = propBlockOutPacketsMeter, propBlockOutTrafficMeter
+	case msg.Code == CompactBlockMsg:
+		packets, traffic = propCompactOutPacketsMeter, propCompactOutTrafficMeter
+	case msg.Code == BlockTxsMsg:
+		packets, traffic = reqBlockTxsOutPacketsMeter, reqBlockTxsOutTrafficMeter
 	case msg.Code == TxMsg:
 		packets, traffic = propTxnOutPacketsMeter, propTxnOutTrafficMeter
 	}
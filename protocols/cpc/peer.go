@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"bitbucket.org/cpchain/chain/commons/log"
+	cconfigs "bitbucket.org/cpchain/chain/protocols/cpc/configs"
 	"bitbucket.org/cpchain/chain/types"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/p2p"
@@ -110,9 +111,13 @@ func (p *peer) broadcast() {
 			}
 			p.Log().Trace("Broadcast transactions", "count", len(txs))
 
-		// prop is for full block
+		// prop is for full block, or compact block if the peer supports it
 		case prop := <-p.queuedProps:
-			if err := p.SendNewBlock(prop.block); err != nil {
+			send := p.SendNewBlock
+			if p.version >= cconfigs.Cpc2 {
+				send = p.SendCompactBlock
+			}
+			if err := send(prop.block); err != nil {
 				return
 			}
 			p.Log().Trace("Propagated block", "number", prop.block.Number(), "hash", prop.block.Hash(), "ht", prop.block.NumberU64())
@@ -240,6 +245,35 @@ func (p *peer) SendNewBlock(block *types.Block) error {
 	return p2p.Send(p.rw, NewBlockMsg, []interface{}{block})
 }
 
+// SendCompactBlock propagates a block to a remote peer as a compact block,
+// only the transactions not known to the peer are sent in full.
+func (p *peer) SendCompactBlock(block *types.Block) error {
+	p.knownBlocks.Add(block.Hash())
+	compact := newCompactBlock(block, func(hash common.Hash) bool {
+		return p.knownTxs.Has(hash)
+	})
+	return p2p.Send(p.rw, CompactBlockMsg, compact)
+}
+
+// RequestBlockTxs fetches the transactions at the given indexes of a block,
+// used to complete a compact block.
+func (p *peer) RequestBlockTxs(hash common.Hash, indexes []uint64) error {
+	p.Log().Debug("Fetching missing txs of compact block", "hash", hash, "count", len(indexes))
+	return p2p.Send(p.rw, GetBlockTxsMsg, &getBlockTxsData{Hash: hash, Indexes: indexes})
+}
+
+// SendBlockTxs sends the requested transactions of a block to the remote peer.
+func (p *peer) SendBlockTxs(hash common.Hash, txs []*types.Transaction) error {
+	return p2p.Send(p.rw, BlockTxsMsg, &blockTxsData{Hash: hash, Txs: txs})
+}
+
+// RequestFullBlock fetches a whole block by hash, answered with a NewBlockMsg.
+// Used if a compact block can not be completed with its missing txs.
+func (p *peer) RequestFullBlock(hash common.Hash) error {
+	p.Log().Debug("Fetching full block of compact block", "hash", hash)
+	return p2p.Send(p.rw, GetFullBlockMsg, hash)
+}
+
 // AsyncSendNewBlock queues an entire block for propagation to a remote peer. If
 // the peer's broadcast queue is full, the event is silently dropped.
 func (p *peer) AsyncSendNewBlock(block *types.Block) {
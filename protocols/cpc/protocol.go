@@ -19,10 +19,10 @@ import (
 var ProtocolName = "cpc"
 
 // ProtocolVersions are the versions of the cpchain protocol (first is primary).
-var ProtocolVersions = []uint{cconfigs.Cpc1}
+var ProtocolVersions = []uint{cconfigs.Cpc2, cconfigs.Cpc1}
 
 // ProtocolLengths are the number of implemented message corresponding to different protocol versions.
-var ProtocolLengths = []uint64{100}
+var ProtocolLengths = []uint64{100, 100}
 
 const ProtocolMaxMsgSize = 10 * 1024 * 1024 // Maximum cap on the size of a protocol message
 
@@ -45,6 +45,12 @@ const (
 	NodeDataMsg    = 0x0e
 	GetReceiptsMsg = 0x0f
 	ReceiptsMsg    = 0x10
+
+	// Protocol messages belonging to cpc/2
+	CompactBlockMsg = 0x11
+	GetBlockTxsMsg  = 0x12
+	BlockTxsMsg     = 0x13
+	GetFullBlockMsg = 0x14
 )
 
 type errCode int
@@ -82,6 +88,10 @@ type txPool interface {
 	// AddRemotes should add the given transactions to the pool.
 	AddRemotes([]*types.Transaction) []error
 
+	// Get should return a transaction if it is contained in the pool
+	// and nil otherwise.
+	Get(hash common.Hash) *types.Transaction
+
 	// Pending should return pending transactions.
 	// The slice should be modifiable by the caller.
 	Pending() (map[common.Address]types.Transactions, error)
@@ -0,0 +1,80 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package rewards
+
+import (
+	"bitbucket.org/cpchain/chain/api/rpc"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// PublicRewardAPI offers the reward history indexed by the node.
+type PublicRewardAPI struct {
+	indexer *Indexer
+}
+
+// NewPublicRewardAPI creates the reward API of indexer.
+func NewPublicRewardAPI(indexer *Indexer) *PublicRewardAPI {
+	return &PublicRewardAPI{indexer: indexer}
+}
+
+// APIs returns the RPC services of the reward index.
+func APIs(indexer *Indexer) []rpc.API {
+	return []rpc.API{
+		{
+			Namespace: "reward",
+			Version:   "1.0",
+			Service:   NewPublicRewardAPI(indexer),
+			Public:    true,
+		},
+	}
+}
+
+// IndexedBlock returns the last block indexed.
+func (api *PublicRewardAPI) IndexedBlock() uint64 {
+	return api.indexer.Head()
+}
+
+// GetHistory returns the reward entries of addr between two blocks.
+func (api *PublicRewardAPI) GetHistory(addr common.Address, fromBlock, toBlock rpc.BlockNumber) ([]*Entry, error) {
+	from, to := api.blockRange(fromBlock, toBlock)
+	entries, _, err := api.indexer.History(addr, from, to)
+	if err != nil {
+		return nil, err
+	}
+	if entries == nil {
+		entries = []*Entry{}
+	}
+	return entries, nil
+}
+
+// GetStatement returns the statement of addr between two blocks.
+func (api *PublicRewardAPI) GetStatement(addr common.Address, fromBlock, toBlock rpc.BlockNumber) (*Statement, error) {
+	from, to := api.blockRange(fromBlock, toBlock)
+	return api.indexer.Statement(addr, from, to)
+}
+
+// blockRange resolves latest and pending to the last block indexed.
+func (api *PublicRewardAPI) blockRange(fromBlock, toBlock rpc.BlockNumber) (uint64, uint64) {
+	head := api.indexer.Head()
+	resolve := func(number rpc.BlockNumber) uint64 {
+		if number < 0 {
+			return head
+		}
+		return uint64(number)
+	}
+	return resolve(fromBlock), resolve(toBlock)
+}
@@ -0,0 +1,146 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package rewards
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"math/big"
+
+	"bitbucket.org/cpchain/chain/database"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// Kind is the kind of a reward history entry.
+type Kind string
+
+const (
+	KindBlockReward  Kind = "blockReward"  // block reward minted to the coinbase
+	KindTxFee        Kind = "txFee"        // transaction fees paid to the coinbase
+	KindDeposit      Kind = "deposit"      // deposit submitted to the reward contract
+	KindWithdraw     Kind = "withdraw"     // free deposit withdrawn by the investor
+	KindTransfer     Kind = "transfer"     // free deposit transferred back by the contract owner
+	KindLock         Kind = "lock"         // deposit locked for a round, amount is the locked total
+	KindInsufficient Kind = "insufficient" // deposit not enough to join a round, amount is the total
+	KindInterest     Kind = "interest"     // bonus pool interest of a closed round
+	KindRenew        Kind = "renew"        // locked deposit renewed for the next round
+	KindUnlock       Kind = "unlock"       // locked deposit returned as free deposit
+)
+
+// Entry is a change of the rewards or the deposit of an address.
+type Entry struct {
+	Kind        Kind           `json:"kind"`
+	Address     common.Address `json:"address"`
+	Amount      *hexutil.Big   `json:"amount"`
+	BlockNumber uint64         `json:"blockNumber"`
+	BlockHash   common.Hash    `json:"blockHash"`
+	Time        uint64         `json:"time"`             // unix time of the block in seconds
+	TxHash      *common.Hash   `json:"txHash,omitempty"` // nil for block rewards and fees
+	Round       uint64         `json:"round,omitempty"`  // round of the reward contract
+}
+
+func newEntry(kind Kind, addr common.Address, amount *big.Int, block *blockInfo, txHash *common.Hash) *Entry {
+	return &Entry{
+		Kind:        kind,
+		Address:     addr,
+		Amount:      (*hexutil.Big)(new(big.Int).Set(amount)),
+		BlockNumber: block.number,
+		BlockHash:   block.hash,
+		Time:        block.time,
+		TxHash:      txHash,
+	}
+}
+
+var (
+	// entriesPrefix + address + bucket (uint64 big endian) -> []*Entry
+	entriesPrefix = []byte("reward-entries-")
+
+	// blockPrefix + number (uint64 big endian) -> indexedBlock
+	blockPrefix = []byte("reward-block-")
+
+	// headKey -> indexedBlock, the last block indexed
+	headKey = []byte("reward-head")
+)
+
+// bucketSize is the number of blocks whose entries of an address are stored
+// together, it bounds the size rewritten per block.
+const bucketSize = 128
+
+// indexedBlock records the addresses having entries in a block, to revert
+// them on reorg.
+type indexedBlock struct {
+	Number    uint64           `json:"number"`
+	Hash      common.Hash      `json:"hash"`
+	Addresses []common.Address `json:"addresses"`
+}
+
+func entriesKey(addr common.Address, bucket uint64) []byte {
+	key := make([]byte, len(entriesPrefix)+common.AddressLength+8)
+	copy(key, entriesPrefix)
+	copy(key[len(entriesPrefix):], addr.Bytes())
+	binary.BigEndian.PutUint64(key[len(entriesPrefix)+common.AddressLength:], bucket)
+	return key
+}
+
+func blockKey(number uint64) []byte {
+	key := make([]byte, len(blockPrefix)+8)
+	copy(key, blockPrefix)
+	binary.BigEndian.PutUint64(key[len(blockPrefix):], number)
+	return key
+}
+
+// readEntries loads the entries of addr in a bucket, nil if there is none.
+func readEntries(db database.Database, addr common.Address, bucket uint64) ([]*Entry, error) {
+	blob, err := db.Get(entriesKey(addr, bucket))
+	if err != nil {
+		return nil, nil
+	}
+	var entries []*Entry
+	if err := json.Unmarshal(blob, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func writeEntries(db database.Putter, addr common.Address, bucket uint64, entries []*Entry) error {
+	blob, err := json.Marshal(entries)
+	if err != nil {
+		return err
+	}
+	return db.Put(entriesKey(addr, bucket), blob)
+}
+
+func readIndexedBlock(db database.Database, key []byte) (*indexedBlock, error) {
+	blob, err := db.Get(key)
+	if err != nil {
+		return nil, err
+	}
+	block := new(indexedBlock)
+	if err := json.Unmarshal(blob, block); err != nil {
+		return nil, err
+	}
+	return block, nil
+}
+
+func writeIndexedBlock(db database.Putter, key []byte, block *indexedBlock) error {
+	blob, err := json.Marshal(block)
+	if err != nil {
+		return err
+	}
+	return db.Put(key, blob)
+}
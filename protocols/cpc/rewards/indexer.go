@@ -0,0 +1,406 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package rewards reconstructs the reward history of addresses: the block
+// rewards and fees paid to proposers, and the deposits, renewals and bonus
+// pool interest of the reward contract.
+package rewards
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+	"sync"
+
+	"bitbucket.org/cpchain/chain/accounts/abi"
+	"bitbucket.org/cpchain/chain/accounts/abi/bind"
+	"bitbucket.org/cpchain/chain/commons/log"
+	"bitbucket.org/cpchain/chain/configs"
+	"bitbucket.org/cpchain/chain/consensus/dpor"
+	"bitbucket.org/cpchain/chain/contracts/dpor/reward"
+	"bitbucket.org/cpchain/chain/core"
+	"bitbucket.org/cpchain/chain/core/state"
+	"bitbucket.org/cpchain/chain/database"
+	"bitbucket.org/cpchain/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+var (
+	errNotIndexed = errors.New("blocks are not indexed yet")
+
+	rewardIndexedGauge = metrics.NewRegisteredGauge("rewards/indexed", nil)
+)
+
+// Backend is the chain rewards are indexed from, *core.BlockChain implements
+// it.
+type Backend interface {
+	core.ChainContext
+	Config() *configs.ChainConfig
+	CurrentBlock() *types.Block
+	GetBlockByNumber(number uint64) *types.Block
+	GetReceiptsByHash(hash common.Hash) types.Receipts
+	StateAt(root common.Hash) (*state.StateDB, error)
+	SubscribeChainHeadEvent(ch chan<- core.ChainHeadEvent) event.Subscription
+}
+
+// events of the reward contract carrying an address and an amount, and the
+// kinds of their entries
+var addressEvents = map[string]Kind{
+	"SubmitDeposit":       KindDeposit,
+	"WithdrawDeposit":     KindWithdraw,
+	"TransferDeposit":     KindTransfer,
+	"JoinENodes":          KindLock,
+	"JoinRNodes":          KindLock,
+	"DepositInsufficient": KindInsufficient,
+}
+
+// addressEvent is the content of addressEvents.
+type addressEvent struct {
+	Who   common.Address
+	Value *big.Int
+}
+
+// blockInfo is the block an entry is in.
+type blockInfo struct {
+	number uint64
+	hash   common.Hash
+	time   uint64
+}
+
+// Indexer follows the canonical chain and stores the reward entries of each
+// address, reverting the blocks of a stale branch on reorg.
+type Indexer struct {
+	backend  Backend
+	db       database.Database
+	address  common.Address      // reward contract
+	contract *bind.BoundContract // to unpack reward contract logs
+	events   map[common.Hash]string
+
+	lock sync.RWMutex // protects the index being updated from reads
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewIndexer creates an indexer of the rewards of the reward contract at
+// address, stored in db.
+func NewIndexer(backend Backend, db database.Database, address common.Address) (*Indexer, error) {
+	parsed, err := abi.JSON(strings.NewReader(reward.RewardABI))
+	if err != nil {
+		return nil, err
+	}
+	events := make(map[common.Hash]string)
+	for name, ev := range parsed.Events {
+		events[ev.Id()] = name
+	}
+
+	return &Indexer{
+		backend:  backend,
+		db:       db,
+		address:  address,
+		contract: bind.NewBoundContract(address, parsed, nil, nil, nil),
+		events:   events,
+	}, nil
+}
+
+// Start starts indexing the chain in background.
+func (ix *Indexer) Start() {
+	ix.quit = make(chan struct{})
+	ix.wg.Add(1)
+	go ix.loop()
+}
+
+// Stop stops indexing, the index resumes from where it stopped.
+func (ix *Indexer) Stop() {
+	close(ix.quit)
+	ix.wg.Wait()
+}
+
+func (ix *Indexer) loop() {
+	defer ix.wg.Done()
+
+	heads := make(chan core.ChainHeadEvent, 16)
+	sub := ix.backend.SubscribeChainHeadEvent(heads)
+	defer sub.Unsubscribe()
+
+	for {
+		if err := ix.sync(); err != nil {
+			log.Warn("failed to index rewards", "error", err)
+		}
+
+		select {
+		case <-heads:
+		case <-sub.Err():
+			return
+		case <-ix.quit:
+			return
+		}
+	}
+}
+
+// Head returns the last block indexed.
+func (ix *Indexer) Head() uint64 {
+	ix.lock.RLock()
+	defer ix.lock.RUnlock()
+
+	head, err := readIndexedBlock(ix.db, headKey)
+	if err != nil {
+		return 0
+	}
+	return head.Number
+}
+
+// sync reverts the blocks indexed on a stale branch and indexes the canonical
+// chain up to the current block.
+func (ix *Indexer) sync() error {
+	next, err := ix.rewind()
+	if err != nil {
+		return err
+	}
+
+	head := ix.backend.CurrentBlock().NumberU64()
+	for number := next; number <= head; number++ {
+		select {
+		case <-ix.quit:
+			return nil
+		default:
+		}
+
+		block := ix.backend.GetBlockByNumber(number)
+		if block == nil {
+			return nil
+		}
+		if err := ix.indexBlock(block); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rewind reverts the indexed blocks not in the canonical chain, and returns the
+// next block to index.
+func (ix *Indexer) rewind() (uint64, error) {
+	for {
+		head, err := readIndexedBlock(ix.db, headKey)
+		if err != nil {
+			// nothing indexed, the genesis block has no rewards
+			return 1, nil
+		}
+		if block := ix.backend.GetBlockByNumber(head.Number); block != nil && block.Hash() == head.Hash {
+			return head.Number + 1, nil
+		}
+
+		log.Debug("reverting reward entries of stale block", "number", head.Number, "hash", head.Hash.Hex())
+		if err := ix.revertBlock(head.Number); err != nil {
+			return 0, err
+		}
+	}
+}
+
+// indexBlock stores the entries of a block.
+func (ix *Indexer) indexBlock(block *types.Block) error {
+	entries, err := ix.blockEntries(block)
+	if err != nil {
+		return err
+	}
+
+	var (
+		addrs  []common.Address
+		byAddr = make(map[common.Address][]*Entry)
+	)
+	for _, entry := range entries {
+		if _, ok := byAddr[entry.Address]; !ok {
+			addrs = append(addrs, entry.Address)
+		}
+		byAddr[entry.Address] = append(byAddr[entry.Address], entry)
+	}
+
+	ix.lock.Lock()
+	defer ix.lock.Unlock()
+
+	batch := ix.db.NewBatch()
+	bucket := block.NumberU64() / bucketSize
+	for _, addr := range addrs {
+		stored, err := readEntries(ix.db, addr, bucket)
+		if err != nil {
+			return err
+		}
+		if err := writeEntries(batch, addr, bucket, append(stored, byAddr[addr]...)); err != nil {
+			return err
+		}
+	}
+
+	indexed := &indexedBlock{Number: block.NumberU64(), Hash: block.Hash(), Addresses: addrs}
+	if err := writeIndexedBlock(batch, blockKey(indexed.Number), indexed); err != nil {
+		return err
+	}
+	if err := writeIndexedBlock(batch, headKey, indexed); err != nil {
+		return err
+	}
+	if err := batch.Write(); err != nil {
+		return err
+	}
+	rewardIndexedGauge.Update(int64(indexed.Number))
+	return nil
+}
+
+// revertBlock removes the entries of an indexed block.
+func (ix *Indexer) revertBlock(number uint64) error {
+	ix.lock.Lock()
+	defer ix.lock.Unlock()
+
+	indexed, err := readIndexedBlock(ix.db, blockKey(number))
+	if err != nil {
+		return err
+	}
+
+	batch := ix.db.NewBatch()
+	bucket := number / bucketSize
+	for _, addr := range indexed.Addresses {
+		stored, err := readEntries(ix.db, addr, bucket)
+		if err != nil {
+			return err
+		}
+		kept := stored[:0]
+		for _, entry := range stored {
+			if entry.BlockNumber != number {
+				kept = append(kept, entry)
+			}
+		}
+		if len(kept) == 0 {
+			err = batch.Delete(entriesKey(addr, bucket))
+		} else {
+			err = writeEntries(batch, addr, bucket, kept)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := batch.Delete(blockKey(number)); err != nil {
+		return err
+	}
+	if parent, err := readIndexedBlock(ix.db, blockKey(number-1)); err == nil {
+		err = writeIndexedBlock(batch, headKey, parent)
+	} else {
+		err = batch.Delete(headKey)
+	}
+	if err != nil {
+		return err
+	}
+	return batch.Write()
+}
+
+// blockEntries returns the reward entries of a block.
+func (ix *Indexer) blockEntries(block *types.Block) ([]*Entry, error) {
+	header := block.Header()
+	info := &blockInfo{number: block.NumberU64(), hash: block.Hash(), time: uint64(header.Timestamp().Unix())}
+	receipts := ix.backend.GetReceiptsByHash(block.Hash())
+
+	var entries []*Entry
+	if header.Coinbase != (common.Address{}) {
+		if amount := dpor.BlockReward(header.Number); amount.Sign() > 0 {
+			entries = append(entries, newEntry(KindBlockReward, header.Coinbase, amount, info, nil))
+		}
+
+		fees := new(big.Int)
+		for i, tx := range block.Transactions() {
+			if i < len(receipts) {
+				fees.Add(fees, new(big.Int).Mul(new(big.Int).SetUint64(receipts[i].GasUsed), tx.GasPrice()))
+			}
+		}
+		if fees.Sign() > 0 {
+			entries = append(entries, newEntry(KindTxFee, header.Coinbase, fees, info, nil))
+		}
+	}
+
+	var rounds *roundReader
+	for _, receipt := range receipts {
+		for _, l := range receipt.Logs {
+			if l.Address != ix.address || len(l.Topics) == 0 {
+				continue
+			}
+			name, ok := ix.events[l.Topics[0]]
+			if !ok {
+				continue
+			}
+			if rounds == nil {
+				rounds = newRoundReader(ix.backend, ix.address, block)
+			}
+
+			logEntries, err := ix.logEntries(name, l, info, rounds)
+			if err != nil {
+				return nil, err
+			}
+			entries = append(entries, logEntries...)
+		}
+	}
+	return entries, nil
+}
+
+// logEntries returns the entries of a reward contract log.
+func (ix *Indexer) logEntries(name string, l *types.Log, info *blockInfo, rounds *roundReader) ([]*Entry, error) {
+	txHash := l.TxHash
+
+	if kind, ok := addressEvents[name]; ok {
+		ev := new(addressEvent)
+		if err := ix.contract.UnpackLog(ev, name, *l); err != nil {
+			return nil, err
+		}
+		entry := newEntry(kind, ev.Who, ev.Value, info, &txHash)
+		if kind == KindLock {
+			// startNewRound locks the deposits for the round it starts
+			round, err := rounds.nextRound()
+			if err != nil {
+				return nil, err
+			}
+			entry.Round = round + 1
+		}
+		return []*Entry{entry}, nil
+	}
+
+	if name != "ContinuedInvest" {
+		// NewRaise and FundBonusPool are not of an address
+		return nil, nil
+	}
+	ev := new(reward.RewardContinuedInvest)
+	if err := ix.contract.UnpackLog(ev, name, *l); err != nil {
+		return nil, err
+	}
+	round, err := rounds.nextRound()
+	if err != nil {
+		return nil, err
+	}
+	deposit, interest, err := rounds.settle(ev.Addr)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []*Entry
+	if interest.Sign() > 0 {
+		entries = append(entries, newEntry(KindInterest, ev.Addr, interest, info, &txHash))
+	}
+	kind := KindRenew
+	if !ev.Iscontinue {
+		kind = KindUnlock
+	}
+	entries = append(entries, newEntry(kind, ev.Addr, deposit, info, &txHash))
+	for _, entry := range entries {
+		entry.Round = round
+	}
+	return entries, nil
+}
@@ -0,0 +1,189 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package rewards
+
+import (
+	"math/big"
+	"testing"
+
+	"bitbucket.org/cpchain/chain/accounts/abi/bind"
+	"bitbucket.org/cpchain/chain/accounts/abi/bind/backends"
+	"bitbucket.org/cpchain/chain/configs"
+	"bitbucket.org/cpchain/chain/contracts/dpor/reward"
+	"bitbucket.org/cpchain/chain/core"
+	"bitbucket.org/cpchain/chain/database"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var (
+	ownerKey, _    = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+	ownerAddr      = crypto.PubkeyToAddress(ownerKey.PublicKey)
+	investorKey, _ = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f292")
+	investorAddr   = crypto.PubkeyToAddress(investorKey.PublicKey)
+	otherKey, _    = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f293")
+	otherAddr      = crypto.PubkeyToAddress(otherKey.PublicKey)
+)
+
+func cpc(n int64) *big.Int {
+	return new(big.Int).Mul(big.NewInt(n), big.NewInt(configs.Cpc))
+}
+
+// runRounds runs two rounds of the reward contract, the investor quits after
+// the first one.
+func runRounds(t *testing.T) (*backends.SimulatedBackend, common.Address) {
+	contractBackend := backends.NewDporSimulatedBackend(core.GenesisAlloc{
+		ownerAddr:    {Balance: cpc(1000000)},
+		investorAddr: {Balance: cpc(1000000)},
+		otherAddr:    {Balance: cpc(1000000)},
+	})
+	owner := bind.NewKeyedTransactor(ownerKey)
+	owner.GasLimit = 5000000
+	addr, _, instance, err := reward.DeployReward(owner, contractBackend)
+	if err != nil {
+		t.Fatal(err)
+	}
+	contractBackend.Commit()
+
+	submit := func(opts *bind.TransactOpts, value *big.Int) {
+		opts.Value = value
+		if _, err := instance.SubmitDeposit(opts); err != nil {
+			t.Fatal(err)
+		}
+		opts.Value = nil
+	}
+	investor := bind.NewKeyedTransactor(investorKey)
+	investor.GasLimit = 5000000
+	other := bind.NewKeyedTransactor(otherKey)
+	other.GasLimit = 5000000
+
+	mustTx := func(_ interface{}, err error) {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	mustTx(instance.SetPeriod(owner, big.NewInt(0)))
+	mustTx(instance.NewRaise(owner))
+	contractBackend.Commit()
+
+	submit(investor, cpc(200000))
+	submit(other, cpc(50000))
+	contractBackend.Commit()
+
+	mustTx(instance.StartNewRound(owner))
+	contractBackend.Commit()
+
+	mustTx(instance.NewRaise(owner))
+	contractBackend.Commit()
+	mustTx(instance.QuitRenew(investor))
+	contractBackend.Commit()
+
+	mustTx(instance.StartNewRound(owner))
+	contractBackend.Commit()
+
+	mustTx(instance.Withdraw(investor, cpc(1000)))
+	contractBackend.Commit()
+	return contractBackend, addr
+}
+
+func TestIndexer(t *testing.T) {
+	contractBackend, addr := runRounds(t)
+	chain := contractBackend.Blockchain()
+
+	ix, err := NewIndexer(chain, database.NewMemDatabase(), addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ix.sync(); err != nil {
+		t.Fatal(err)
+	}
+	if head := ix.Head(); head != chain.CurrentBlock().NumberU64() {
+		t.Fatalf("indexed head %d, want %d", head, chain.CurrentBlock().NumberU64())
+	}
+
+	// the bonus pool is shared by the locked deposits, 200000 : 50000
+	bonusPool := cpc(1250000)
+	interest := new(big.Int).Div(new(big.Int).Mul(bonusPool, cpc(200000)), cpc(250000))
+
+	check := func() {
+		st, err := ix.Statement(investorAddr, 0, 1000)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var kinds []Kind
+		for _, entry := range st.Entries {
+			kinds = append(kinds, entry.Kind)
+		}
+		// the investor quitting is not enough for the second round
+		want := []Kind{KindDeposit, KindLock, KindInterest, KindUnlock, KindInsufficient, KindWithdraw}
+		if len(kinds) != len(want) {
+			t.Fatalf("entries %v, want %v", kinds, want)
+		}
+		for i := range want {
+			if kinds[i] != want[i] {
+				t.Fatalf("entries %v, want %v", kinds, want)
+			}
+		}
+
+		if st.Entries[1].Round != 1 || st.Entries[2].Round != 1 {
+			t.Fatalf("lock round %d, interest round %d", st.Entries[1].Round, st.Entries[2].Round)
+		}
+		if st.Interest.ToInt().Cmp(interest) != 0 {
+			t.Fatalf("interest %v, want %v", st.Interest.ToInt(), interest)
+		}
+		if st.Entries[3].Amount.ToInt().Cmp(cpc(200000)) != 0 {
+			t.Fatalf("unlocked %v", st.Entries[3].Amount.ToInt())
+		}
+		if st.Deposits.ToInt().Cmp(cpc(200000)) != 0 || st.Withdrawals.ToInt().Cmp(cpc(1000)) != 0 {
+			t.Fatalf("deposits %v, withdrawals %v", st.Deposits.ToInt(), st.Withdrawals.ToInt())
+		}
+		if st.ToBlock != ix.Head() {
+			t.Fatalf("statement to block %d, indexed head %d", st.ToBlock, ix.Head())
+		}
+	}
+	check()
+
+	// other renewed by default, and is locked for the second round
+	entries, _, err := ix.History(otherAddr, 0, 1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) < 2 {
+		t.Fatalf("entries of other %v", entries)
+	}
+	renew, lock := entries[len(entries)-2], entries[len(entries)-1]
+	if renew.Kind != KindRenew || renew.Round != 1 || renew.Amount.ToInt().Cmp(cpc(50000)) != 0 {
+		t.Fatalf("renew entry of other %+v", renew)
+	}
+	if lock.Kind != KindLock || lock.Round != 2 || lock.Amount.ToInt().Cmp(cpc(50000)) != 0 {
+		t.Fatalf("lock entry of other %+v", lock)
+	}
+
+	// a head on a stale branch is reverted and indexed again
+	head, err := readIndexedBlock(ix.db, headKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	head.Hash = common.HexToHash("0xdead")
+	if err := writeIndexedBlock(ix.db, headKey, head); err != nil {
+		t.Fatal(err)
+	}
+	if err := ix.sync(); err != nil {
+		t.Fatal(err)
+	}
+	check()
+}
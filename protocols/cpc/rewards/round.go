@@ -0,0 +1,140 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package rewards
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+
+	"bitbucket.org/cpchain/chain"
+	"bitbucket.org/cpchain/chain/contracts/dpor/reward"
+	"bitbucket.org/cpchain/chain/core"
+	"bitbucket.org/cpchain/chain/core/state"
+	"bitbucket.org/cpchain/chain/core/vm"
+	"bitbucket.org/cpchain/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var errCallFailed = errors.New("reward contract call failed")
+
+// roundReader reads the reward contract at the parent of a block, before the
+// round closed in the block is settled. The interest of a round is not in the
+// logs, it is calculated as the contract does from the locked deposits and
+// the bonus pool.
+type roundReader struct {
+	backend Backend
+	address common.Address
+	block   *types.Block
+
+	caller    *reward.RewardCaller
+	round     *big.Int
+	bonusPool *big.Int
+	total     *big.Int
+}
+
+func newRoundReader(backend Backend, address common.Address, block *types.Block) *roundReader {
+	return &roundReader{backend: backend, address: address, block: block}
+}
+
+func (r *roundReader) init() error {
+	if r.caller != nil {
+		return nil
+	}
+
+	parent := r.backend.GetHeader(r.block.ParentHash(), r.block.NumberU64()-1)
+	if parent == nil {
+		return errNotIndexed
+	}
+	statedb, err := r.backend.StateAt(parent.StateRoot)
+	if err != nil {
+		return fmt.Errorf("no state of block %d to settle the round: %v", parent.Number, err)
+	}
+	caller, err := reward.NewRewardCaller(r.address, &stateCaller{backend: r.backend, header: parent, state: statedb})
+	if err != nil {
+		return err
+	}
+	r.caller = caller
+	return nil
+}
+
+// nextRound returns nextRound of the contract, the round closed in the block.
+func (r *roundReader) nextRound() (uint64, error) {
+	if r.round == nil {
+		if err := r.init(); err != nil {
+			return 0, err
+		}
+		round, err := r.caller.NextRound(nil)
+		if err != nil {
+			return 0, err
+		}
+		r.round = round
+	}
+	return r.round.Uint64(), nil
+}
+
+// settle returns the locked deposit of addr and its interest of the round
+// closed in the block.
+func (r *roundReader) settle(addr common.Address) (deposit *big.Int, interest *big.Int, err error) {
+	if err := r.init(); err != nil {
+		return nil, nil, err
+	}
+	if r.total == nil {
+		if r.bonusPool, err = r.caller.BonusPool(nil); err != nil {
+			return nil, nil, err
+		}
+		if r.total, err = r.caller.TotalInvestAmount(nil); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if deposit, err = r.caller.GetLockedBalance(nil, addr); err != nil {
+		return nil, nil, err
+	}
+	interest = new(big.Int)
+	if r.total.Sign() > 0 {
+		interest.Div(new(big.Int).Mul(r.bonusPool, deposit), r.total)
+	}
+	return deposit, interest, nil
+}
+
+// stateCaller runs contract calls on a state, it implements
+// bind.ContractCaller.
+type stateCaller struct {
+	backend Backend
+	header  *types.Header
+	state   *state.StateDB
+}
+
+func (c *stateCaller) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	return c.state.GetCode(contract), nil
+}
+
+func (c *stateCaller) CallContract(ctx context.Context, call cpchain.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	snapshot := c.state.Snapshot()
+	defer c.state.RevertToSnapshot(snapshot)
+
+	msg := types.NewMessage(call.From, call.To, 0, new(big.Int), math.MaxUint64/2, new(big.Int), call.Data, false)
+	evm := vm.NewEVM(core.NewEVMContext(msg, c.header, c.backend, nil), c.state, c.backend.Config(), vm.Config{})
+	ret, _, failed, err := core.ApplyMessage(evm, msg, new(core.GasPool).AddGas(math.MaxUint64))
+	if err == nil && failed {
+		err = errCallFailed
+	}
+	return ret, err
+}
@@ -0,0 +1,126 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package rewards
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// maxStatementBlocks bounds the blocks of a statement, about a year.
+const maxStatementBlocks = 1 << 22
+
+// Statement sums up the reward history of an address between two blocks.
+type Statement struct {
+	Address   common.Address `json:"address"`
+	FromBlock uint64         `json:"fromBlock"`
+	ToBlock   uint64         `json:"toBlock"`
+
+	Blocks       uint64       `json:"blocks"` // blocks proposed
+	BlockRewards *hexutil.Big `json:"blockRewards"`
+	TxFees       *hexutil.Big `json:"txFees"`
+	Interest     *hexutil.Big `json:"interest"`
+	Deposits     *hexutil.Big `json:"deposits"`
+	Withdrawals  *hexutil.Big `json:"withdrawals"` // withdrawn or transferred back
+
+	Entries []*Entry `json:"entries"`
+}
+
+// History returns the entries of addr from block from to block to, both
+// included, in the order they happened. to is capped to the last block
+// indexed.
+func (ix *Indexer) History(addr common.Address, from, to uint64) ([]*Entry, uint64, error) {
+	head := ix.Head()
+	if to > head {
+		to = head
+	}
+	if from > to {
+		return nil, to, errNotIndexed
+	}
+	if to-from >= maxStatementBlocks {
+		return nil, to, fmt.Errorf("too many blocks, at most %d in a statement", maxStatementBlocks)
+	}
+
+	ix.lock.RLock()
+	defer ix.lock.RUnlock()
+
+	var entries []*Entry
+	for bucket := from / bucketSize; bucket <= to/bucketSize; bucket++ {
+		stored, err := readEntries(ix.db, addr, bucket)
+		if err != nil {
+			return nil, to, err
+		}
+		for _, entry := range stored {
+			if entry.BlockNumber >= from && entry.BlockNumber <= to {
+				entries = append(entries, entry)
+			}
+		}
+	}
+	return entries, to, nil
+}
+
+// Statement returns the statement of addr from block from to block to.
+func (ix *Indexer) Statement(addr common.Address, from, to uint64) (*Statement, error) {
+	entries, to, err := ix.History(addr, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		blocks       uint64
+		blockRewards = new(big.Int)
+		txFees       = new(big.Int)
+		interest     = new(big.Int)
+		deposits     = new(big.Int)
+		withdrawals  = new(big.Int)
+	)
+	for _, entry := range entries {
+		amount := entry.Amount.ToInt()
+		switch entry.Kind {
+		case KindBlockReward:
+			blocks++
+			blockRewards.Add(blockRewards, amount)
+		case KindTxFee:
+			txFees.Add(txFees, amount)
+		case KindInterest:
+			interest.Add(interest, amount)
+		case KindDeposit:
+			deposits.Add(deposits, amount)
+		case KindWithdraw, KindTransfer:
+			withdrawals.Add(withdrawals, amount)
+		}
+	}
+	if entries == nil {
+		entries = []*Entry{}
+	}
+
+	return &Statement{
+		Address:      addr,
+		FromBlock:    from,
+		ToBlock:      to,
+		Blocks:       blocks,
+		BlockRewards: (*hexutil.Big)(blockRewards),
+		TxFees:       (*hexutil.Big)(txFees),
+		Interest:     (*hexutil.Big)(interest),
+		Deposits:     (*hexutil.Big)(deposits),
+		Withdrawals:  (*hexutil.Big)(withdrawals),
+		Entries:      entries,
+	}, nil
+}
@@ -0,0 +1,259 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"bitbucket.org/cpchain/chain/configs"
+	"bitbucket.org/cpchain/chain/contracts/dpor/multisig"
+	cm "bitbucket.org/cpchain/chain/tools/console/common"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/urfave/cli"
+)
+
+var multisigCommand cli.Command
+
+func init() {
+	multisigFlags := append([]cli.Flag{
+		cli.StringFlag{
+			Name:  "multisig",
+			Usage: "Address of the multisig wallet administering the contracts",
+		},
+	}, GasFlags...)
+	multisigCommand = cli.Command{
+		Name:  "multisig",
+		Flags: wrapperFlags(multisigFlags),
+		Usage: "Administer the contracts through a multisig wallet",
+		Subcommands: []cli.Command{
+			{
+				Name:  "propose",
+				Usage: "Propose an owner-only call to the multisig wallet",
+				Flags: wrapperFlags(multisigFlags),
+				Description: `Submit an owner-only call and confirm it by the account. The call is executed once enough owners confirmed it.
+
+    startnewround                 startNewRound of the reward contract
+    newraise                      newRaise of the reward contract
+    setperiod <seconds>           setPeriod of the reward contract
+    setrnodeperiod <seconds>      setPeriod of the rnode contract
+    registerproxy <proxy> <real>  registerProxyContract of the proxy register`,
+				ArgsUsage: "<call> [arguments]",
+				Action:    propose,
+			},
+			{
+				Name:      "confirm",
+				Usage:     "Confirm a proposal, executing it if it has enough confirmations",
+				Flags:     wrapperFlags(multisigFlags),
+				ArgsUsage: "<id>",
+				Action:    confirm,
+			},
+			{
+				Name:      "revoke",
+				Usage:     "Revoke the confirmation of a proposal not yet executed",
+				Flags:     wrapperFlags(multisigFlags),
+				ArgsUsage: "<id>",
+				Action:    revoke,
+			},
+			{
+				Name:      "execute",
+				Usage:     "Execute a confirmed proposal whose execution failed",
+				Flags:     wrapperFlags(multisigFlags),
+				ArgsUsage: "<id>",
+				Action:    execute,
+			},
+			{
+				Name:      "status",
+				Usage:     "Show a proposal and its confirmations",
+				Flags:     wrapperFlags(multisigFlags),
+				ArgsUsage: "<id>",
+				Action:    proposalStatus,
+			},
+		},
+	}
+}
+
+func multisigAddress(ctx *cli.Context) (common.Address, error) {
+	addr := ctx.String("multisig")
+	if !common.IsHexAddress(addr) {
+		return common.Address{}, errors.New("invalid multisig address \"" + addr + "\", set it with --multisig")
+	}
+	return common.HexToAddress(addr), nil
+}
+
+// parseCall builds the owner-only call named by the arguments.
+func parseCall(args cli.Args) (multisig.Call, error) {
+	if len(args) == 0 {
+		return multisig.Call{}, errors.New("missing call to propose")
+	}
+	want := map[string]int{"startnewround": 1, "newraise": 1, "setperiod": 2, "setrnodeperiod": 2, "registerproxy": 3}
+	n, ok := want[args[0]]
+	if !ok {
+		return multisig.Call{}, errors.New("unknown call " + args[0])
+	}
+	if len(args) != n {
+		return multisig.Call{}, fmt.Errorf("%s takes %d arguments", args[0], n-1)
+	}
+	rewardAddr := cm.GetContractAddress(configs.ContractReward)
+	switch args[0] {
+	case "startnewround":
+		return multisig.StartNewRound(rewardAddr)
+	case "newraise":
+		return multisig.NewRaise(rewardAddr)
+	case "setperiod", "setrnodeperiod":
+		period, ok := new(big.Int).SetString(args[1], 10)
+		if !ok || period.Sign() < 0 {
+			return multisig.Call{}, errors.New("invalid period " + args[1])
+		}
+		if args[0] == "setperiod" {
+			return multisig.SetRewardPeriod(rewardAddr, period)
+		}
+		return multisig.SetRnodePeriod(cm.GetContractAddress(configs.ContractRnode), period)
+	default:
+		if !common.IsHexAddress(args[1]) || !common.IsHexAddress(args[2]) {
+			return multisig.Call{}, errors.New("invalid proxy or real contract address")
+		}
+		register := configs.ChainConfigInfo().Dpor.ProxyContractRegister
+		return multisig.RegisterProxyContract(register, common.HexToAddress(args[1]), common.HexToAddress(args[2]))
+	}
+}
+
+// proposalID parses the proposal id argument.
+func proposalID(ctx *cli.Context) (*big.Int, error) {
+	if len(ctx.Args()) != 1 {
+		return nil, errors.New("expected the proposal id as the only argument")
+	}
+	id, ok := new(big.Int).SetString(ctx.Args().First(), 10)
+	if !ok || id.Sign() < 0 {
+		return nil, errors.New("invalid proposal id " + ctx.Args().First())
+	}
+	return id, nil
+}
+
+func propose(ctx *cli.Context) error {
+	wallet, err := multisigAddress(ctx)
+	if err != nil {
+		return err
+	}
+	call, err := parseCall(ctx.Args())
+	if err != nil {
+		return err
+	}
+	console, out, cancel, err := build(ctx)
+	if err != nil {
+		out.Error(err.Error())
+		return nil
+	}
+	defer cancel()
+
+	if _, err := console.ProposeMultiSig(wallet, call); err != nil {
+		out.Error(err.Error())
+	}
+	return nil
+}
+
+func confirm(ctx *cli.Context) error {
+	wallet, err := multisigAddress(ctx)
+	if err != nil {
+		return err
+	}
+	id, err := proposalID(ctx)
+	if err != nil {
+		return err
+	}
+	console, out, cancel, err := build(ctx)
+	if err != nil {
+		out.Error(err.Error())
+		return nil
+	}
+	defer cancel()
+
+	if err := console.ConfirmMultiSig(wallet, id); err != nil {
+		out.Error(err.Error())
+	}
+	return nil
+}
+
+func revoke(ctx *cli.Context) error {
+	wallet, err := multisigAddress(ctx)
+	if err != nil {
+		return err
+	}
+	id, err := proposalID(ctx)
+	if err != nil {
+		return err
+	}
+	console, out, cancel, err := build(ctx)
+	if err != nil {
+		out.Error(err.Error())
+		return nil
+	}
+	defer cancel()
+
+	if err := console.RevokeMultiSig(wallet, id); err != nil {
+		out.Error(err.Error())
+	}
+	return nil
+}
+
+func execute(ctx *cli.Context) error {
+	wallet, err := multisigAddress(ctx)
+	if err != nil {
+		return err
+	}
+	id, err := proposalID(ctx)
+	if err != nil {
+		return err
+	}
+	console, out, cancel, err := build(ctx)
+	if err != nil {
+		out.Error(err.Error())
+		return nil
+	}
+	defer cancel()
+
+	if err := console.ExecuteMultiSig(wallet, id); err != nil {
+		out.Error(err.Error())
+	}
+	return nil
+}
+
+func proposalStatus(ctx *cli.Context) error {
+	wallet, err := multisigAddress(ctx)
+	if err != nil {
+		return err
+	}
+	id, err := proposalID(ctx)
+	if err != nil {
+		return err
+	}
+	console, out, cancel, err := build(ctx)
+	if err != nil {
+		out.Error(err.Error())
+		return nil
+	}
+	defer cancel()
+
+	proposal, err := console.GetMultiSigProposal(wallet, id)
+	if err != nil {
+		out.Error(err.Error())
+		return nil
+	}
+	out.Proposal(proposal)
+	return nil
+}
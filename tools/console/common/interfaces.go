@@ -1,11 +1,16 @@
 package common
 
-import "math/big"
+import (
+	"math/big"
+
+	"bitbucket.org/cpchain/chain/contracts/dpor/multisig"
+)
 
 // Output data
 type Output interface {
 	Status(status *Status)
 	Balance(balance *Balance)
+	Proposal(proposal *multisig.Proposal)
 	Info(msg string, params ...interface{})
 	Error(msg string, params ...interface{})
 	Fatal(msg string, params ...interface{})
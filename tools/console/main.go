@@ -33,6 +33,7 @@ func newApp() *cli.App {
 		minerCommand,
 		accountCommand,
 		rewardCommand,
+		multisigCommand,
 	}
 
 	// maintain order
@@ -0,0 +1,156 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package manager
+
+import (
+	"errors"
+	"math/big"
+
+	"bitbucket.org/cpchain/chain/accounts/abi/bind"
+	"bitbucket.org/cpchain/chain/contracts/dpor/multisig"
+	"bitbucket.org/cpchain/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func (c *Console) multiSig(wallet common.Address) (*multisig.MultiSig, error) {
+	instance, err := multisig.NewMultiSig(wallet, c.client)
+	if err != nil {
+		return nil, err
+	}
+	isOwner, err := instance.IsOwner(nil, c.addr)
+	if err != nil {
+		return nil, err
+	}
+	if !isOwner {
+		return nil, errors.New("account " + c.addr.Hex() + " is not an owner of multisig " + wallet.Hex())
+	}
+	return instance, nil
+}
+
+func (c *Console) waitMultiSig(wallet common.Address, tx *types.Transaction) (*types.Receipt, error) {
+	c.output.Info("Waiting for the transaction to be mined...", "tx", tx.Hash().Hex())
+	receipt, err := bind.WaitMined(*c.ctx, c.client, tx)
+	if err != nil {
+		return nil, err
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return nil, errors.New("multisig transaction " + tx.Hash().Hex() + " failed")
+	}
+	return receipt, nil
+}
+
+// ProposeMultiSig submits call to the multisig wallet and confirms it by
+// the account of the console, it returns the id of the proposal.
+func (c *Console) ProposeMultiSig(wallet common.Address, call multisig.Call) (*big.Int, error) {
+	c.output.Info("Propose...", "method", multisig.Describe(call.Data), "to", call.Destination.Hex())
+	instance, err := c.multiSig(wallet)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := multisig.Propose(instance, c.buildTransactOpts(big.NewInt(0)), call)
+	if err != nil {
+		return nil, err
+	}
+	receipt, err := c.waitMultiSig(wallet, tx)
+	if err != nil {
+		return nil, err
+	}
+	id, err := multisig.ProposalID(wallet, receipt)
+	if err != nil {
+		return nil, err
+	}
+	if multisig.Executed(wallet, receipt, id) {
+		c.output.Info("Proposal executed", "id", id)
+	} else {
+		c.output.Info("Proposal submitted, waiting for the confirmations of the other owners", "id", id)
+	}
+	return id, nil
+}
+
+// ConfirmMultiSig confirms a proposal, which is executed if it has enough
+// confirmations.
+func (c *Console) ConfirmMultiSig(wallet common.Address, id *big.Int) error {
+	c.output.Info("Confirm...", "id", id)
+	instance, err := c.multiSig(wallet)
+	if err != nil {
+		return err
+	}
+	tx, err := instance.ConfirmTransaction(c.buildTransactOpts(big.NewInt(0)), id)
+	if err != nil {
+		return err
+	}
+	receipt, err := c.waitMultiSig(wallet, tx)
+	if err != nil {
+		return err
+	}
+	if multisig.Executed(wallet, receipt, id) {
+		c.output.Info("Proposal executed", "id", id)
+	} else {
+		c.output.Info("Proposal confirmed", "id", id)
+	}
+	return nil
+}
+
+// RevokeMultiSig revokes the confirmation of a proposal not yet executed.
+func (c *Console) RevokeMultiSig(wallet common.Address, id *big.Int) error {
+	c.output.Info("Revoke...", "id", id)
+	instance, err := c.multiSig(wallet)
+	if err != nil {
+		return err
+	}
+	tx, err := instance.RevokeConfirmation(c.buildTransactOpts(big.NewInt(0)), id)
+	if err != nil {
+		return err
+	}
+	if _, err := c.waitMultiSig(wallet, tx); err != nil {
+		return err
+	}
+	c.output.Info("Confirmation revoked", "id", id)
+	return nil
+}
+
+// ExecuteMultiSig executes a confirmed proposal, e.g. one whose execution
+// failed before.
+func (c *Console) ExecuteMultiSig(wallet common.Address, id *big.Int) error {
+	c.output.Info("Execute...", "id", id)
+	instance, err := c.multiSig(wallet)
+	if err != nil {
+		return err
+	}
+	tx, err := instance.ExecuteTransaction(c.buildTransactOpts(big.NewInt(0)), id)
+	if err != nil {
+		return err
+	}
+	receipt, err := c.waitMultiSig(wallet, tx)
+	if err != nil {
+		return err
+	}
+	if !multisig.Executed(wallet, receipt, id) {
+		return errors.New("proposal " + id.String() + " is not executed")
+	}
+	c.output.Info("Proposal executed", "id", id)
+	return nil
+}
+
+// GetMultiSigProposal gets a proposal of the multisig wallet.
+func (c *Console) GetMultiSigProposal(wallet common.Address, id *big.Int) (*multisig.Proposal, error) {
+	instance, err := multisig.NewMultiSigCaller(wallet, c.client)
+	if err != nil {
+		return nil, err
+	}
+	return multisig.GetProposal(instance, nil, id)
+}
@@ -8,6 +8,7 @@ import (
 
 	"bitbucket.org/cpchain/chain/commons/log"
 	"bitbucket.org/cpchain/chain/configs"
+	"bitbucket.org/cpchain/chain/contracts/dpor/multisig"
 	cm "bitbucket.org/cpchain/chain/tools/console/common"
 )
 
@@ -94,6 +95,37 @@ Reward:
 	}
 }
 
+// Proposal of a multisig wallet
+func (l *LogOutput) Proposal(proposal *multisig.Proposal) {
+	outTmpl := `--------------------------
+
+ID:            {{.ID}}
+
+Destination:   {{.Destination.Hex}}
+
+Method:        {{.Method}}
+
+Value:         {{.Value}} Wei
+
+Data:          {{printf "%#x" .Data}}
+
+Executed:      {{.Executed}}
+
+Confirmations: {{len .Confirmations}}/{{.Required}}
+{{range .Confirmations}}	{{.Hex}}
+{{end}}
+--------------------------
+`
+	tmpl, err := template.New("proposal").Parse(outTmpl)
+	if err != nil {
+		l.Error(err.Error())
+	}
+	err = tmpl.Execute(os.Stdout, proposal)
+	if err != nil {
+		l.Error(err.Error())
+	}
+}
+
 // Info log
 func (l *LogOutput) Info(msg string, params ...interface{}) {
 	l.logger.Info(msg, params...)
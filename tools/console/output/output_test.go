@@ -4,7 +4,9 @@ import (
 	"math/big"
 	"testing"
 
+	"bitbucket.org/cpchain/chain/contracts/dpor/multisig"
 	status "bitbucket.org/cpchain/chain/tools/console/common"
+	"github.com/ethereum/go-ethereum/common"
 )
 
 func TestLogOutput(t *testing.T) {
@@ -40,4 +42,15 @@ func TestLogOutput(t *testing.T) {
 		Locked:     true,
 		NextNumber: big.NewInt(100),
 	})
+
+	// Proposal
+	output.Proposal(&multisig.Proposal{
+		ID:            big.NewInt(1),
+		Destination:   common.HexToAddress("0x94576e35a55D6BbF9bB45120bC835a668557eF42"),
+		Value:         big.NewInt(0),
+		Data:          []byte{0xf1, 0xab, 0x1d, 0x40},
+		Method:        "startNewRound()",
+		Confirmations: []common.Address{{1}},
+		Required:      big.NewInt(2),
+	})
 }
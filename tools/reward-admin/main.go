@@ -33,6 +33,8 @@ func newApp() *cli.App {
 		startnewroundCommand,
 		startnewraiseCommand,
 		statusCommand,
+		multisigCommand,
+		statementCommand,
 	}
 
 	// maintain order
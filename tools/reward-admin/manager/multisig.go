@@ -0,0 +1,184 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"math/big"
+
+	"bitbucket.org/cpchain/chain/accounts/abi/bind"
+	"bitbucket.org/cpchain/chain/commons/log"
+	"bitbucket.org/cpchain/chain/configs"
+	"bitbucket.org/cpchain/chain/contracts/dpor/multisig"
+	"bitbucket.org/cpchain/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func (c *Console) multiSig(wallet common.Address) (*multisig.MultiSig, error) {
+	instance, err := multisig.NewMultiSig(wallet, c.client)
+	if err != nil {
+		return nil, err
+	}
+	isOwner, err := instance.IsOwner(nil, c.addr)
+	if err != nil {
+		return nil, err
+	}
+	if !isOwner {
+		return nil, errors.New("account " + c.addr.Hex() + " is not an owner of multisig " + wallet.Hex())
+	}
+	return instance, nil
+}
+
+func (c *Console) waitMultiSig(tx *types.Transaction) (*types.Receipt, error) {
+	log.Info("Transaction hash is", "tx", tx.Hash().Hex())
+	receipt, err := bind.WaitMined(context.Background(), c.client, tx)
+	if err != nil {
+		return nil, err
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return nil, errors.New("multisig transaction " + tx.Hash().Hex() + " failed")
+	}
+	return receipt, nil
+}
+
+// ProposeMultiSig submits call to the multisig wallet and confirms it by the
+// admin account.
+func (c *Console) ProposeMultiSig(wallet common.Address, call multisig.Call) (*big.Int, error) {
+	c.output.Info("Propose through multisig...", "method", multisig.Describe(call.Data))
+	instance, err := c.multiSig(wallet)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := multisig.Propose(instance, c.buildTransactOpts(big.NewInt(0)), call)
+	if err != nil {
+		return nil, err
+	}
+	receipt, err := c.waitMultiSig(tx)
+	if err != nil {
+		return nil, err
+	}
+	id, err := multisig.ProposalID(wallet, receipt)
+	if err != nil {
+		return nil, err
+	}
+	if multisig.Executed(wallet, receipt, id) {
+		c.output.Info("Proposal executed", "id", id)
+	} else {
+		c.output.Info("Proposal submitted, the other owners confirm it with 'multisig confirm'", "id", id)
+	}
+	return id, nil
+}
+
+// StartNewRoundMultiSig proposes startNewRound to the multisig wallet.
+func (c *Console) StartNewRoundMultiSig(wallet common.Address) error {
+	if c.IsLocked() {
+		mark := "Sorry, the reward contract is locked now, to startnewround is failed."
+		c.output.Warn(mark)
+		return nil
+	}
+	call, err := multisig.StartNewRound(getContractAddress(configs.ContractReward))
+	if err != nil {
+		return err
+	}
+	_, err = c.ProposeMultiSig(wallet, call)
+	return err
+}
+
+// StartNewRaiseMultiSig proposes newRaise to the multisig wallet.
+func (c *Console) StartNewRaiseMultiSig(wallet common.Address) error {
+	call, err := multisig.NewRaise(getContractAddress(configs.ContractReward))
+	if err != nil {
+		return err
+	}
+	_, err = c.ProposeMultiSig(wallet, call)
+	return err
+}
+
+// SetPeriodMultiSig proposes setPeriod of the reward contract to the
+// multisig wallet.
+func (c *Console) SetPeriodMultiSig(wallet common.Address, period *big.Int) error {
+	call, err := multisig.SetRewardPeriod(getContractAddress(configs.ContractReward), period)
+	if err != nil {
+		return err
+	}
+	_, err = c.ProposeMultiSig(wallet, call)
+	return err
+}
+
+// ConfirmMultiSig confirms a proposal, which is executed if it has enough
+// confirmations.
+func (c *Console) ConfirmMultiSig(wallet common.Address, id *big.Int) error {
+	instance, err := c.multiSig(wallet)
+	if err != nil {
+		return err
+	}
+	tx, err := instance.ConfirmTransaction(c.buildTransactOpts(big.NewInt(0)), id)
+	if err != nil {
+		return err
+	}
+	receipt, err := c.waitMultiSig(tx)
+	if err != nil {
+		return err
+	}
+	if multisig.Executed(wallet, receipt, id) {
+		c.output.Info("Proposal executed", "id", id)
+	} else {
+		c.output.Info("Proposal confirmed", "id", id)
+	}
+	return nil
+}
+
+// RevokeMultiSig revokes the confirmation of a proposal not yet executed.
+func (c *Console) RevokeMultiSig(wallet common.Address, id *big.Int) error {
+	instance, err := c.multiSig(wallet)
+	if err != nil {
+		return err
+	}
+	tx, err := instance.RevokeConfirmation(c.buildTransactOpts(big.NewInt(0)), id)
+	if err != nil {
+		return err
+	}
+	if _, err := c.waitMultiSig(tx); err != nil {
+		return err
+	}
+	c.output.Info("Confirmation revoked", "id", id)
+	return nil
+}
+
+// ExecuteMultiSig executes a confirmed proposal whose execution failed.
+func (c *Console) ExecuteMultiSig(wallet common.Address, id *big.Int) error {
+	instance, err := c.multiSig(wallet)
+	if err != nil {
+		return err
+	}
+	tx, err := instance.ExecuteTransaction(c.buildTransactOpts(big.NewInt(0)), id)
+	if err != nil {
+		return err
+	}
+	receipt, err := c.waitMultiSig(tx)
+	if err != nil {
+		return err
+	}
+	if !multisig.Executed(wallet, receipt, id) {
+		return errors.New("proposal " + id.String() + " is not executed")
+	}
+	c.output.Info("Proposal executed", "id", id)
+	return nil
+}
+
+// MultiSigStatus shows a proposal and its confirmations.
+func (c *Console) MultiSigStatus(wallet common.Address, id *big.Int) error {
+	instance, err := multisig.NewMultiSigCaller(wallet, c.client)
+	if err != nil {
+		return err
+	}
+	p, err := multisig.GetProposal(instance, nil, id)
+	if err != nil {
+		return err
+	}
+	c.output.Info("Proposal", "id", p.ID, "destination", p.Destination.Hex(), "method", p.Method, "value", p.Value, "executed", p.Executed)
+	c.output.Info("Confirmations", "count", len(p.Confirmations), "required", p.Required)
+	for _, owner := range p.Confirmations {
+		c.output.Info("Confirmed by", "owner", owner.Hex())
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package output
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"strconv"
+	"time"
+
+	"bitbucket.org/cpchain/chain/configs"
+	"bitbucket.org/cpchain/chain/protocols/cpc/rewards"
+)
+
+// StatementHeader is the header row of a statement in CSV
+var StatementHeader = []string{"block", "time", "kind", "amount_wei", "amount_cpc", "round", "tx", "block_hash"}
+
+// WriteStatement writes a reward statement as csv or json
+func WriteStatement(w io.Writer, format string, st *rewards.Statement) error {
+	switch format {
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(st)
+	case "csv":
+		return writeStatementCSV(w, st)
+	default:
+		return fmt.Errorf("unknown statement format %q, csv or json", format)
+	}
+}
+
+// writeStatementCSV writes the entries of a statement, one per row
+func writeStatementCSV(w io.Writer, st *rewards.Statement) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(StatementHeader); err != nil {
+		return err
+	}
+	for _, entry := range st.Entries {
+		var round, tx string
+		if entry.Round > 0 {
+			round = strconv.FormatUint(entry.Round, 10)
+		}
+		if entry.TxHash != nil {
+			tx = entry.TxHash.Hex()
+		}
+		amount := entry.Amount.ToInt()
+		err := cw.Write([]string{
+			strconv.FormatUint(entry.BlockNumber, 10),
+			time.Unix(int64(entry.Time), 0).UTC().Format(time.RFC3339),
+			string(entry.Kind),
+			amount.String(),
+			FormatCpc(amount),
+			round,
+			tx,
+			entry.BlockHash.Hex(),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// FormatCpc formats an amount in wei as cpc, without losing precision
+func FormatCpc(wei *big.Int) string {
+	return new(big.Rat).SetFrac(wei, big.NewInt(configs.Cpc)).FloatString(18)
+}
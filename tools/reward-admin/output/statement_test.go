@@ -0,0 +1,63 @@
+package output
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"bitbucket.org/cpchain/chain/configs"
+	"bitbucket.org/cpchain/chain/protocols/cpc/rewards"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+func TestWriteStatement(t *testing.T) {
+	txHash := common.HexToHash("0x01")
+	reward := new(big.Int).Mul(big.NewInt(1265), big.NewInt(configs.Cpc/100))
+	st := &rewards.Statement{
+		Address:      common.HexToAddress("0x02"),
+		ToBlock:      10,
+		Blocks:       1,
+		BlockRewards: (*hexutil.Big)(reward),
+		Entries: []*rewards.Entry{
+			{Kind: rewards.KindBlockReward, Amount: (*hexutil.Big)(reward), BlockNumber: 3, Time: 1546300800},
+			{Kind: rewards.KindInterest, Amount: (*hexutil.Big)(big.NewInt(1)), BlockNumber: 7, Round: 2, TxHash: &txHash},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteStatement(&buf, "csv", st); err != nil {
+		t.Fatal(err)
+	}
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 3 || len(rows[1]) != len(StatementHeader) {
+		t.Fatalf("csv rows %v", rows)
+	}
+	if rows[1][1] != "2019-01-01T00:00:00Z" || rows[1][2] != "blockReward" || rows[1][4] != "12.650000000000000000" {
+		t.Fatalf("block reward row %v", rows[1])
+	}
+	if rows[2][4] != "0.000000000000000001" || rows[2][5] != "2" || rows[2][6] != txHash.Hex() {
+		t.Fatalf("interest row %v", rows[2])
+	}
+
+	buf.Reset()
+	if err := WriteStatement(&buf, "json", st); err != nil {
+		t.Fatal(err)
+	}
+	decoded := new(rewards.Statement)
+	if err := json.Unmarshal(buf.Bytes(), decoded); err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded.Entries) != 2 || decoded.BlockRewards.ToInt().Cmp(reward) != 0 {
+		t.Fatalf("json statement %+v", decoded)
+	}
+
+	if err := WriteStatement(&buf, "xml", st); err == nil {
+		t.Fatal("wrote statement in unknown format")
+	}
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/urfave/cli"
+)
+
+// MultiSigFlags set the multisig wallet owning the reward contract
+var MultiSigFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:  "multisig",
+		Usage: "Address of the multisig wallet owning the reward contract, owner-only calls are proposed to it",
+	},
+}
+
+var multisigCommand cli.Command
+
+func init() {
+	multisigFlags := append([]cli.Flag(nil), MultiSigFlags...)
+	multisigCommand = cli.Command{
+		Name:  "multisig",
+		Flags: wrapperFlags(multisigFlags),
+		Usage: "Multisig wallet owning the reward contract",
+		Subcommands: []cli.Command{
+			{
+				Name:      "set-period",
+				Flags:     wrapperFlags(multisigFlags),
+				Usage:     "Propose setPeriod of the reward contract",
+				ArgsUsage: "<seconds>",
+				Action:    multisigSetPeriod,
+			},
+			{
+				Name:      "confirm",
+				Flags:     wrapperFlags(multisigFlags),
+				Usage:     "Confirm a proposal, executing it if it has enough confirmations",
+				ArgsUsage: "<id>",
+				Action:    multisigConfirm,
+			},
+			{
+				Name:      "revoke",
+				Flags:     wrapperFlags(multisigFlags),
+				Usage:     "Revoke the confirmation of a proposal not yet executed",
+				ArgsUsage: "<id>",
+				Action:    multisigRevoke,
+			},
+			{
+				Name:      "execute",
+				Flags:     wrapperFlags(multisigFlags),
+				Usage:     "Execute a confirmed proposal whose execution failed",
+				ArgsUsage: "<id>",
+				Action:    multisigExecute,
+			},
+			{
+				Name:      "status",
+				Flags:     wrapperFlags(multisigFlags),
+				Usage:     "Show a proposal and its confirmations",
+				ArgsUsage: "<id>",
+				Action:    multisigStatus,
+			},
+		},
+	}
+}
+
+// multisigAddress returns the multisig wallet set by --multisig, if any.
+func multisigAddress(ctx *cli.Context) (common.Address, bool, error) {
+	addr := ctx.String("multisig")
+	if addr == "" {
+		return common.Address{}, false, nil
+	}
+	if !common.IsHexAddress(addr) {
+		return common.Address{}, false, errors.New("invalid multisig address " + addr)
+	}
+	return common.HexToAddress(addr), true, nil
+}
+
+// multisigArgs returns the wallet and the single numeric argument of a
+// multisig subcommand.
+func multisigArgs(ctx *cli.Context) (common.Address, *big.Int, error) {
+	wallet, ok, err := multisigAddress(ctx)
+	if err != nil {
+		return common.Address{}, nil, err
+	}
+	if !ok {
+		return common.Address{}, nil, errors.New("missing multisig address, set it with --multisig")
+	}
+	if len(ctx.Args()) != 1 {
+		return common.Address{}, nil, errors.New("expected a single argument")
+	}
+	n, ok := new(big.Int).SetString(ctx.Args().First(), 10)
+	if !ok || n.Sign() < 0 {
+		return common.Address{}, nil, errors.New("invalid argument " + ctx.Args().First())
+	}
+	return wallet, n, nil
+}
+
+func multisigSetPeriod(ctx *cli.Context) error {
+	wallet, period, err := multisigArgs(ctx)
+	if err != nil {
+		return err
+	}
+	admin, out, cancel := Build(ctx)
+	defer cancel()
+	if err := admin.SetPeriodMultiSig(wallet, period); err != nil {
+		out.Error(err.Error())
+	}
+	return nil
+}
+
+func multisigConfirm(ctx *cli.Context) error {
+	wallet, id, err := multisigArgs(ctx)
+	if err != nil {
+		return err
+	}
+	admin, out, cancel := Build(ctx)
+	defer cancel()
+	if err := admin.ConfirmMultiSig(wallet, id); err != nil {
+		out.Error(err.Error())
+	}
+	return nil
+}
+
+func multisigRevoke(ctx *cli.Context) error {
+	wallet, id, err := multisigArgs(ctx)
+	if err != nil {
+		return err
+	}
+	admin, out, cancel := Build(ctx)
+	defer cancel()
+	if err := admin.RevokeMultiSig(wallet, id); err != nil {
+		out.Error(err.Error())
+	}
+	return nil
+}
+
+func multisigExecute(ctx *cli.Context) error {
+	wallet, id, err := multisigArgs(ctx)
+	if err != nil {
+		return err
+	}
+	admin, out, cancel := Build(ctx)
+	defer cancel()
+	if err := admin.ExecuteMultiSig(wallet, id); err != nil {
+		out.Error(err.Error())
+	}
+	return nil
+}
+
+func multisigStatus(ctx *cli.Context) error {
+	wallet, id, err := multisigArgs(ctx)
+	if err != nil {
+		return err
+	}
+	admin, out, cancel := Build(ctx)
+	defer cancel()
+	if err := admin.MultiSigStatus(wallet, id); err != nil {
+		out.Error(err.Error())
+	}
+	return nil
+}
@@ -7,7 +7,7 @@ import (
 var startnewraiseCommand cli.Command
 
 func init() {
-	rewardFlags := append([]cli.Flag(nil))
+	rewardFlags := append([]cli.Flag(nil), MultiSigFlags...)
 	startnewraiseCommand = cli.Command{
 		Name:   "start-new-raise",
 		Flags:  wrapperFlags(rewardFlags),
@@ -17,9 +17,17 @@ func init() {
 }
 
 func startnewraise(ctx *cli.Context) error {
+	wallet, viaMultiSig, err := multisigAddress(ctx)
+	if err != nil {
+		return err
+	}
 	admin, out, cancel := Build(ctx)
 	defer cancel()
-	err := admin.StartNewRaise()
+	if viaMultiSig {
+		err = admin.StartNewRaiseMultiSig(wallet)
+	} else {
+		err = admin.StartNewRaise()
+	}
 	if err != nil {
 		out.Error(err.Error())
 	}
@@ -7,7 +7,7 @@ import (
 var startnewroundCommand cli.Command
 
 func init() {
-	rewardFlags := append([]cli.Flag(nil))
+	rewardFlags := append([]cli.Flag(nil), MultiSigFlags...)
 	startnewroundCommand = cli.Command{
 		Name:   "start-new-round",
 		Flags:  wrapperFlags(rewardFlags),
@@ -17,9 +17,17 @@ func init() {
 }
 
 func startnewround(ctx *cli.Context) error {
+	wallet, viaMultiSig, err := multisigAddress(ctx)
+	if err != nil {
+		return err
+	}
 	admin, out, cancel := Build(ctx)
 	defer cancel()
-	err := admin.StartNewRound()
+	if viaMultiSig {
+		err = admin.StartNewRoundMultiSig(wallet)
+	} else {
+		err = admin.StartNewRound()
+	}
 	if err != nil {
 		out.Error(err.Error())
 	}
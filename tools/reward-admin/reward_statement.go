@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"bitbucket.org/cpchain/chain/api/rpc"
+	"bitbucket.org/cpchain/chain/protocols/cpc/rewards"
+	"bitbucket.org/cpchain/chain/tools/reward-admin/output"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/urfave/cli"
+)
+
+var statementCommand cli.Command
+
+func init() {
+	statementFlags := []cli.Flag{
+		cli.StringFlag{
+			Name:  "address",
+			Usage: "Address to export the reward statement of",
+		},
+		cli.Int64Flag{
+			Name:  "from",
+			Usage: "First block of the statement",
+			Value: 0,
+		},
+		cli.Int64Flag{
+			Name:  "to",
+			Usage: "Last block of the statement, -1 for the last block indexed",
+			Value: -1,
+		},
+		cli.StringFlag{
+			Name:  "format",
+			Usage: "Format of the statement, csv or json",
+			Value: "csv",
+		},
+		cli.StringFlag{
+			Name:  "output",
+			Usage: "File to write the statement to, stdout if not set",
+		},
+	}
+	statementCommand = cli.Command{
+		Action: exportStatement,
+		Name:   "statement",
+		Flags:  append(statementFlags, RPCFlags...),
+		Usage:  "Export the reward statement of an address, the node must run with --rewardindex",
+	}
+}
+
+func exportStatement(ctx *cli.Context) error {
+	if !common.IsHexAddress(ctx.String("address")) {
+		return errors.New("a valid --address is required")
+	}
+	addr := common.HexToAddress(ctx.String("address"))
+
+	client, err := rpc.Dial(ctx.String("rpc"))
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	st := new(rewards.Statement)
+	err = client.CallContext(context.Background(), st, "reward_getStatement", addr,
+		blockArg(ctx.Int64("from")), blockArg(ctx.Int64("to")))
+	if err != nil {
+		return err
+	}
+
+	var w io.Writer = os.Stdout
+	if path := ctx.String("output"); path != "" {
+		f, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+	if err := output.WriteStatement(w, ctx.String("format"), st); err != nil {
+		return err
+	}
+
+	if ctx.String("output") != "" {
+		fmt.Printf("Statement of %s from block %d to %d: %d entries\n", st.Address.Hex(), st.FromBlock, st.ToBlock, len(st.Entries))
+		fmt.Printf("Blocks proposed: %d \t Block rewards: %s CPC \t Tx fees: %s CPC\n", st.Blocks, output.FormatCpc(st.BlockRewards.ToInt()), output.FormatCpc(st.TxFees.ToInt()))
+		fmt.Printf("Interest: %s CPC \t Deposits: %s CPC \t Withdrawals: %s CPC\n", output.FormatCpc(st.Interest.ToInt()), output.FormatCpc(st.Deposits.ToInt()), output.FormatCpc(st.Withdrawals.ToInt()))
+	}
+	return nil
+}
+
+// blockArg encodes a block number argument, negative for the latest block
+func blockArg(number int64) string {
+	if number < 0 {
+		return "latest"
+	}
+	return hexutil.EncodeUint64(uint64(number))
+}
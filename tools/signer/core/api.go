@@ -0,0 +1,209 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package core implements the external signer: an RPC API signing with keys
+// from a keystore, subject to per account rules, with every request written to
+// an audit log.
+package core
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"bitbucket.org/cpchain/chain/accounts"
+	"bitbucket.org/cpchain/chain/accounts/keystore"
+	"bitbucket.org/cpchain/chain/consensus"
+	"bitbucket.org/cpchain/chain/consensus/dpor"
+	"bitbucket.org/cpchain/chain/consensus/dpor/backend"
+	"bitbucket.org/cpchain/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// Version is the version of the signer API.
+const Version = "1.0.0"
+
+var errInvalidHash = errors.New("hash must be 32 bytes")
+
+// Namespace is the RPC namespace of the signer API.
+const Namespace = "account"
+
+// SignerAPI is the API of the signer, served in the "account" namespace. It
+// is what accounts/external talks to.
+type SignerAPI struct {
+	ks    *keystore.KeyStore
+	rules *Rules
+	audit *AuditLog
+}
+
+// NewSignerAPI creates the signer API, signing with the unlocked accounts of
+// ks. The audit log may be nil.
+func NewSignerAPI(ks *keystore.KeyStore, rules *Rules, audit *AuditLog) *SignerAPI {
+	return &SignerAPI{ks: ks, rules: rules, audit: audit}
+}
+
+// Version returns the version of the signer API.
+func (api *SignerAPI) Version() string {
+	return Version
+}
+
+// List returns the accounts of the keystore which have rules.
+func (api *SignerAPI) List() []common.Address {
+	addresses := []common.Address{}
+	for _, acc := range api.ks.Accounts() {
+		if _, ok := api.rules.Accounts[acc.Address]; ok {
+			addresses = append(addresses, acc.Address)
+		}
+	}
+	return addresses
+}
+
+// SignHash signs a raw 32 byte hash, the account must be allowed to sign
+// hashes.
+func (api *SignerAPI) SignHash(account common.Address, hash hexutil.Bytes) (hexutil.Bytes, error) {
+	entry := &AuditEntry{Method: "signHash", Account: account, Hash: hash}
+	if len(hash) != common.HashLength {
+		return nil, api.reject(entry, errInvalidHash)
+	}
+	if err := api.rules.CheckHash(account); err != nil {
+		return nil, api.reject(entry, err)
+	}
+	sig, err := api.ks.SignHash(accounts.Account{Address: account}, hash)
+	if err != nil {
+		return nil, api.reject(entry, err)
+	}
+	api.approve(entry)
+	return sig, nil
+}
+
+// SignTransaction signs an RLP encoded transaction and returns it RLP encoded.
+// A nil chain id signs without replay protection.
+func (api *SignerAPI) SignTransaction(account common.Address, raw hexutil.Bytes, chainID *hexutil.Big) (hexutil.Bytes, error) {
+	entry := &AuditEntry{Method: "signTransaction", Account: account}
+
+	tx := new(types.Transaction)
+	if err := rlp.DecodeBytes(raw, tx); err != nil {
+		return nil, api.reject(entry, err)
+	}
+	var signer types.Signer = types.HomesteadSigner{}
+	if chainID != nil {
+		signer = types.NewCep1Signer((*big.Int)(chainID))
+	}
+	nonce := hexutil.Uint64(tx.Nonce())
+	entry.To, entry.Value, entry.Nonce = tx.To(), (*hexutil.Big)(tx.Value()), &nonce
+	entry.Hash = signer.Hash(tx).Bytes()
+	if data := tx.Data(); len(data) >= 4 {
+		entry.Selector = data[:4]
+	}
+
+	if err := api.rules.CheckTx(account, tx); err != nil {
+		return nil, api.reject(entry, err)
+	}
+	signed, err := api.ks.SignTx(accounts.Account{Address: account}, tx, (*big.Int)(chainID))
+	if err != nil {
+		return nil, api.reject(entry, err)
+	}
+	out, err := rlp.EncodeToBytes(signed)
+	if err != nil {
+		return nil, api.reject(entry, err)
+	}
+	api.approve(entry)
+	return out, nil
+}
+
+// SignDporHeader signs an RLP encoded header in a consensus state, the hash
+// is computed from the header so the signer knows which block it signs.
+func (api *SignerAPI) SignDporHeader(account common.Address, raw hexutil.Bytes, state uint8) (hexutil.Bytes, error) {
+	entry := &AuditEntry{Method: "signDporHeader", Account: account, Detail: consensus.State(state).String()}
+
+	header := new(types.Header)
+	if err := rlp.DecodeBytes(raw, header); err != nil {
+		return nil, api.reject(entry, err)
+	}
+	number := hexutil.Uint64(header.Number.Uint64())
+	entry.Number = &number
+
+	hash, err := dpor.HeaderSigHash(header, consensus.State(state))
+	if err != nil {
+		return nil, api.reject(entry, err)
+	}
+	return api.signDpor(entry, hash)
+}
+
+// SignDporMac signs a mac of the Dpor handshake, it must be composed at about
+// the time of the signer.
+func (api *SignerAPI) SignDporMac(account common.Address, mac string) (hexutil.Bytes, error) {
+	entry := &AuditEntry{Method: "signDporMac", Account: account, Detail: mac}
+
+	t, err := backend.ParseMac(mac)
+	if err != nil {
+		return nil, api.reject(entry, backend.ErrInvalidMac)
+	}
+	if !backend.MacTimely(t) {
+		return nil, api.reject(entry, rejectf("mac time %v too far from now", t))
+	}
+	return api.signDpor(entry, backend.MacHash(mac))
+}
+
+// SignDporMaintenance signs a maintenance notice of a proposer skipping its
+// blocks from from to to.
+func (api *SignerAPI) SignDporMaintenance(account common.Address, from, to hexutil.Uint64) (hexutil.Bytes, error) {
+	entry := &AuditEntry{Method: "signDporMaintenance", Account: account, Number: &from, Detail: fmt.Sprintf("%d-%d", from, to)}
+
+	if from > to {
+		return nil, api.reject(entry, rejectf("maintenance from %d after %d", from, to))
+	}
+	notice := &backend.MaintenanceNotice{From: uint64(from), To: uint64(to)}
+	return api.signDpor(entry, notice.SigHash())
+}
+
+func (api *SignerAPI) signDpor(entry *AuditEntry, hash common.Hash) (hexutil.Bytes, error) {
+	entry.Hash = hash.Bytes()
+	if err := api.rules.CheckDpor(entry.Account); err != nil {
+		return nil, api.reject(entry, err)
+	}
+	sig, err := api.ks.SignHash(accounts.Account{Address: entry.Account}, hash.Bytes())
+	if err != nil {
+		return nil, api.reject(entry, err)
+	}
+	api.approve(entry)
+	return sig, nil
+}
+
+// PublicKey returns the uncompressed public key of an account with rules.
+func (api *SignerAPI) PublicKey(account common.Address) (hexutil.Bytes, error) {
+	if _, ok := api.rules.Accounts[account]; !ok {
+		return nil, rejectf("no rules for account %s", account.Hex())
+	}
+	pub, err := api.ks.EcdsaPublicKey(accounts.Account{Address: account})
+	if err != nil {
+		return nil, err
+	}
+	return pub, nil
+}
+
+func (api *SignerAPI) approve(entry *AuditEntry) {
+	entry.Approved = true
+	api.audit.Log(entry)
+}
+
+func (api *SignerAPI) reject(entry *AuditEntry, err error) error {
+	entry.Reason = err.Error()
+	api.audit.Log(entry)
+	return err
+}
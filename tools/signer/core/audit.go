@@ -0,0 +1,70 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+
+	"bitbucket.org/cpchain/chain/commons/log"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// AuditEntry records a signing request and its outcome.
+type AuditEntry struct {
+	Time     time.Time       `json:"time"`
+	Method   string          `json:"method"`
+	Account  common.Address  `json:"account"`
+	To       *common.Address `json:"to,omitempty"`
+	Value    *hexutil.Big    `json:"value,omitempty"`
+	Nonce    *hexutil.Uint64 `json:"nonce,omitempty"`
+	Selector hexutil.Bytes   `json:"selector,omitempty"`
+	Number   *hexutil.Uint64 `json:"number,omitempty"` // the header number or first skipped block of Dpor msgs
+	Detail   string          `json:"detail,omitempty"` // the state of headers, the mac or skipped blocks of Dpor msgs
+	Hash     hexutil.Bytes   `json:"hash"`             // the hash to sign, the signing hash for transactions and Dpor msgs
+	Approved bool            `json:"approved"`
+	Reason   string          `json:"reason,omitempty"`
+}
+
+// AuditLog writes one JSON encoded entry per line for every request.
+type AuditLog struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+// NewAuditLog creates an audit log writing to w.
+func NewAuditLog(w io.Writer) *AuditLog {
+	return &AuditLog{enc: json.NewEncoder(w)}
+}
+
+// Log appends an entry, a nil log discards it.
+func (l *AuditLog) Log(entry *AuditEntry) {
+	if l == nil {
+		return
+	}
+	if entry.Time.IsZero() {
+		entry.Time = time.Now()
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if err := l.enc.Encode(entry); err != nil {
+		log.Error("Failed to write audit log", "err", err)
+	}
+}
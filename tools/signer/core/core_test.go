@@ -0,0 +1,284 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"bitbucket.org/cpchain/chain/accounts"
+	"bitbucket.org/cpchain/chain/accounts/keystore"
+	"bitbucket.org/cpchain/chain/consensus"
+	"bitbucket.org/cpchain/chain/consensus/dpor"
+	"bitbucket.org/cpchain/chain/consensus/dpor/backend"
+	"bitbucket.org/cpchain/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+var (
+	recipient = common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	contract  = common.HexToAddress("0x00000000000000000000000000000000000000bb")
+)
+
+func newTestAPI(t *testing.T) (*SignerAPI, common.Address, *bytes.Buffer, func()) {
+	dir, err := ioutil.TempDir("", "cpchain-signer-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ks := keystore.NewPlaintextKeyStore(dir)
+	acc, err := ks.NewAccount("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ks.Unlock(acc, ""); err != nil {
+		t.Fatal(err)
+	}
+	rules, err := ParseRules([]byte(fmt.Sprintf(`{
+		"accounts": {
+			"%s": {
+				"allowHashSigning": true,
+				"allowDporSigning": true,
+				"allowedRecipients": ["%s", "%s"],
+				"maxValue": "1000",
+				"methods": {"%s": ["transfer(address,uint256)", "0x12345678"]}
+			}
+		}
+	}`, acc.Address.Hex(), recipient.Hex(), contract.Hex(), contract.Hex())))
+	if err != nil {
+		t.Fatal(err)
+	}
+	audit := new(bytes.Buffer)
+	return NewSignerAPI(ks, rules, NewAuditLog(audit)), acc.Address, audit, func() { os.RemoveAll(dir) }
+}
+
+func signTx(api *SignerAPI, account common.Address, tx *types.Transaction) (*types.Transaction, error) {
+	raw, err := rlp.EncodeToBytes(tx)
+	if err != nil {
+		return nil, err
+	}
+	out, err := api.SignTransaction(account, raw, (*hexutil.Big)(big.NewInt(42)))
+	if err != nil {
+		return nil, err
+	}
+	signed := new(types.Transaction)
+	return signed, rlp.DecodeBytes(out, signed)
+}
+
+func TestSignTransactionRules(t *testing.T) {
+	api, account, _, cleanup := newTestAPI(t)
+	defer cleanup()
+
+	transfer := crypto.Keccak256([]byte("transfer(address,uint256)"))[:4]
+	tests := []struct {
+		tx     *types.Transaction
+		reject string
+	}{
+		{types.NewTransaction(0, recipient, big.NewInt(1000), 21000, big.NewInt(1), nil), ""},
+		{types.NewTransaction(0, recipient, big.NewInt(1001), 21000, big.NewInt(1), nil), "exceeds the cap"},
+		{types.NewTransaction(0, common.Address{1}, big.NewInt(1), 21000, big.NewInt(1), nil), "recipient"},
+		{types.NewContractCreation(0, big.NewInt(0), 21000, big.NewInt(1), []byte{1}), "contract creation"},
+		{types.NewTransaction(0, contract, big.NewInt(0), 21000, big.NewInt(1), append(transfer, make([]byte, 64)...)), ""},
+		{types.NewTransaction(0, contract, big.NewInt(0), 21000, big.NewInt(1), []byte{0x12, 0x34, 0x56, 0x78}), ""},
+		{types.NewTransaction(0, contract, big.NewInt(0), 21000, big.NewInt(1), []byte{0xde, 0xad, 0xbe, 0xef}), "method 0xdeadbeef"},
+		{types.NewTransaction(0, recipient, big.NewInt(0), 21000, big.NewInt(1), transfer), "method"},
+		{types.NewTransaction(0, contract, big.NewInt(0), 21000, big.NewInt(1), []byte{1}), "too short"},
+	}
+	for i, test := range tests {
+		signed, err := signTx(api, account, test.tx)
+		if test.reject != "" {
+			if err == nil || !strings.Contains(err.Error(), test.reject) {
+				t.Errorf("test %d: error mismatch: have %v, want %q", i, err, test.reject)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("test %d: unexpected error: %v", i, err)
+			continue
+		}
+		from, err := types.Sender(types.NewCep1Signer(big.NewInt(42)), signed)
+		if err != nil || from != account {
+			t.Errorf("test %d: sender mismatch: have %x (%v), want %x", i, from, err, account)
+		}
+	}
+}
+
+func TestSignHashRules(t *testing.T) {
+	api, account, _, cleanup := newTestAPI(t)
+	defer cleanup()
+
+	hash := crypto.Keccak256([]byte("header"))
+	sig, err := api.SignHash(account, hash)
+	if err != nil {
+		t.Fatalf("failed to sign hash: %v", err)
+	}
+	pub, err := crypto.SigToPub(hash, sig)
+	if err != nil || crypto.PubkeyToAddress(*pub) != account {
+		t.Errorf("signature not made by %x", account)
+	}
+	if _, err := api.SignHash(account, hash[:31]); err != errInvalidHash {
+		t.Errorf("short hash: error mismatch: have %v, want %v", err, errInvalidHash)
+	}
+	if _, err := api.SignHash(common.Address{1}, hash); err == nil {
+		t.Error("signed for an account without rules")
+	}
+
+	api.rules.Accounts[account].AllowHashSigning = false
+	if _, err := api.SignHash(account, hash); err == nil {
+		t.Error("signed a hash without permission")
+	}
+}
+
+func TestSignDporRules(t *testing.T) {
+	api, account, _, cleanup := newTestAPI(t)
+	defer cleanup()
+
+	signedBy := func(hash common.Hash, sig []byte) bool {
+		pub, err := crypto.SigToPub(hash.Bytes(), sig)
+		return err == nil && crypto.PubkeyToAddress(*pub) == account
+	}
+
+	// headers are hashed by the signer with their state
+	header := &types.Header{Number: big.NewInt(7), Time: big.NewInt(1), Extra: make([]byte, 32)}
+	raw, _ := rlp.EncodeToBytes(header)
+	for _, state := range []consensus.State{consensus.Prepare, consensus.Commit} {
+		sig, err := api.SignDporHeader(account, raw, uint8(state))
+		if err != nil {
+			t.Fatalf("failed to sign header in %v: %v", state, err)
+		}
+		hash, _ := dpor.HeaderSigHash(header, state)
+		if !signedBy(hash, sig) {
+			t.Errorf("header in %v: signature not made by %x over its hash", state, account)
+		}
+	}
+	if _, err := api.SignDporHeader(account, raw, uint8(consensus.Idle)); err == nil {
+		t.Error("signed a header in an unknown state")
+	}
+	if _, err := api.SignDporHeader(account, raw[:len(raw)-1], uint8(consensus.Commit)); err == nil {
+		t.Error("signed an invalid header")
+	}
+
+	// macs must be timely
+	mac := backend.NewMac(time.Now())
+	sig, err := api.SignDporMac(account, mac)
+	if err != nil {
+		t.Fatalf("failed to sign mac: %v", err)
+	}
+	if !signedBy(backend.MacHash(mac), sig) {
+		t.Errorf("mac signature not made by %x", account)
+	}
+	if _, err := api.SignDporMac(account, backend.NewMac(time.Now().Add(-time.Hour))); err == nil {
+		t.Error("signed a stale mac")
+	}
+	if _, err := api.SignDporMac(account, "0xdeadbeef"); err != backend.ErrInvalidMac {
+		t.Errorf("invalid mac: error mismatch: have %v, want %v", err, backend.ErrInvalidMac)
+	}
+
+	// maintenance notices must skip some blocks
+	sig, err = api.SignDporMaintenance(account, 10, 12)
+	if err != nil {
+		t.Fatalf("failed to sign maintenance notice: %v", err)
+	}
+	if !signedBy((&backend.MaintenanceNotice{From: 10, To: 12}).SigHash(), sig) {
+		t.Errorf("maintenance signature not made by %x", account)
+	}
+	if _, err := api.SignDporMaintenance(account, 12, 10); err == nil {
+		t.Error("signed an empty maintenance notice")
+	}
+
+	api.rules.Accounts[account].AllowDporSigning = false
+	if _, err := api.SignDporHeader(account, raw, uint8(consensus.Commit)); err == nil {
+		t.Error("signed a header without permission")
+	}
+}
+
+func TestAuditLog(t *testing.T) {
+	api, account, audit, cleanup := newTestAPI(t)
+	defer cleanup()
+
+	signTx(api, account, types.NewTransaction(3, recipient, big.NewInt(1), 21000, big.NewInt(1), nil))
+	signTx(api, account, types.NewTransaction(4, recipient, big.NewInt(2000), 21000, big.NewInt(1), nil))
+	api.SignHash(account, make([]byte, 32))
+
+	lines := strings.Split(strings.TrimSpace(audit.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("audit entry count mismatch: have %d, want 3", len(lines))
+	}
+	entries := make([]AuditEntry, len(lines))
+	for i, line := range lines {
+		if err := json.Unmarshal([]byte(line), &entries[i]); err != nil {
+			t.Fatalf("entry %d: invalid json: %v", i, err)
+		}
+	}
+	if e := entries[0]; !e.Approved || e.Method != "signTransaction" || *e.To != recipient || uint64(*e.Nonce) != 3 {
+		t.Errorf("entry 0 mismatch: %+v", e)
+	}
+	if e := entries[1]; e.Approved || !strings.Contains(e.Reason, "exceeds the cap") || e.Value.ToInt().Int64() != 2000 {
+		t.Errorf("entry 1 mismatch: %+v", e)
+	}
+	if e := entries[2]; !e.Approved || e.Method != "signHash" || e.Account != account {
+		t.Errorf("entry 2 mismatch: %+v", e)
+	}
+}
+
+func TestParseRules(t *testing.T) {
+	tests := []struct {
+		rules string
+		ok    bool
+	}{
+		{`{"accounts": {}}`, true},
+		{`{"accounts": {"0x00000000000000000000000000000000000000aa": {"methods": {"0x00000000000000000000000000000000000000bb": ["0x1234"]}}}}`, false},
+		{`{"accounts": {"0x00000000000000000000000000000000000000aa": {"methods": {"0x00000000000000000000000000000000000000bb": ["transfer"]}}}}`, false},
+		{`{"accounts": {"0x00000000000000000000000000000000000000aa": null}}`, false},
+		{`{"accounts": {"0x00000000000000000000000000000000000000aa": {"maxValue": "0x10"}}}`, true},
+	}
+	for i, test := range tests {
+		if _, err := ParseRules([]byte(test.rules)); (err == nil) != test.ok {
+			t.Errorf("test %d: have error %v, want ok %v", i, err, test.ok)
+		}
+	}
+}
+
+func TestListAndPublicKey(t *testing.T) {
+	api, account, _, cleanup := newTestAPI(t)
+	defer cleanup()
+
+	// an account without rules is not listed
+	if _, err := api.ks.NewAccount(""); err != nil {
+		t.Fatal(err)
+	}
+	if list := api.List(); len(list) != 1 || list[0] != account {
+		t.Errorf("account list mismatch: have %x, want [%x]", list, account)
+	}
+	pub, err := api.PublicKey(account)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, _ := api.ks.EcdsaPublicKey(accounts.Account{Address: account})
+	if !bytes.Equal(pub, want) {
+		t.Errorf("public key mismatch: have %x, want %x", pub, want)
+	}
+}
@@ -0,0 +1,195 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"regexp"
+
+	"bitbucket.org/cpchain/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var (
+	selectorRegex  = regexp.MustCompile(`^0x[0-9a-fA-F]{8}$`)
+	signatureRegex = regexp.MustCompile(`^[a-zA-Z_$][a-zA-Z0-9_$]*\(.*\)$`)
+)
+
+// Rules decide which requests the signer approves. Accounts without rules
+// can't sign anything.
+type Rules struct {
+	Accounts map[common.Address]*AccountRules `json:"accounts"`
+}
+
+// AccountRules are the signing permissions of a single account.
+type AccountRules struct {
+	// AllowHashSigning permits signing raw hashes, whose content the signer
+	// can't inspect. It is required for the Dpor keys sealing headers and
+	// should be off for every other account.
+	AllowHashSigning bool `json:"allowHashSigning"`
+
+	// AllowDporSigning permits signing Dpor headers, handshake macs and
+	// maintenance notices, whose hashes the signer computes itself. It is what
+	// the Dpor keys of validators and proposers need.
+	AllowDporSigning bool `json:"allowDporSigning"`
+
+	// AllowedRecipients restricts the recipients of transactions, any
+	// recipient is allowed if it is empty.
+	AllowedRecipients []common.Address `json:"allowedRecipients,omitempty"`
+
+	// MaxValue caps the value of a single transaction, in wei.
+	MaxValue *math.HexOrDecimal256 `json:"maxValue,omitempty"`
+
+	// AllowContractCreation permits transactions without recipient.
+	AllowContractCreation bool `json:"allowContractCreation"`
+
+	// Methods lists the contract methods that may be called, either as 4 byte
+	// selectors or as signatures like "transfer(address,uint256)". Transactions
+	// with input data are rejected unless the recipient is listed here.
+	Methods map[common.Address][]string `json:"methods,omitempty"`
+
+	selectors map[common.Address]map[string]bool
+}
+
+// RejectedError is returned for requests denied by the rules.
+type RejectedError struct {
+	Reason string
+}
+
+func (e *RejectedError) Error() string {
+	return "request rejected: " + e.Reason
+}
+
+func rejectf(format string, args ...interface{}) error {
+	return &RejectedError{Reason: fmt.Sprintf(format, args...)}
+}
+
+// LoadRules reads the rules from a JSON file.
+func LoadRules(path string) (*Rules, error) {
+	blob, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseRules(blob)
+}
+
+// ParseRules decodes JSON encoded rules and validates the method lists.
+func ParseRules(blob []byte) (*Rules, error) {
+	rules := new(Rules)
+	if err := json.Unmarshal(blob, rules); err != nil {
+		return nil, fmt.Errorf("invalid rules: %v", err)
+	}
+	for addr, r := range rules.Accounts {
+		if r == nil {
+			return nil, fmt.Errorf("invalid rules: account %s has no rules", addr.Hex())
+		}
+		r.selectors = make(map[common.Address]map[string]bool)
+		for contract, methods := range r.Methods {
+			r.selectors[contract] = make(map[string]bool)
+			for _, method := range methods {
+				selector, err := methodSelector(method)
+				if err != nil {
+					return nil, fmt.Errorf("invalid rules for account %s: %v", addr.Hex(), err)
+				}
+				r.selectors[contract][selector] = true
+			}
+		}
+	}
+	return rules, nil
+}
+
+// methodSelector returns the hex encoded 4 byte selector of a method given as
+// selector or signature.
+func methodSelector(method string) (string, error) {
+	switch {
+	case selectorRegex.MatchString(method):
+		return hexutil.Encode(hexutil.MustDecode(method)), nil
+	case signatureRegex.MatchString(method):
+		return hexutil.Encode(crypto.Keccak256([]byte(method))[:4]), nil
+	}
+	return "", fmt.Errorf("invalid method %q, want a 4 byte selector or a signature", method)
+}
+
+// CheckHash decides whether account may sign a raw hash.
+func (r *Rules) CheckHash(account common.Address) error {
+	rules, ok := r.Accounts[account]
+	if !ok {
+		return rejectf("no rules for account %s", account.Hex())
+	}
+	if !rules.AllowHashSigning {
+		return rejectf("hash signing not allowed for account %s", account.Hex())
+	}
+	return nil
+}
+
+// CheckDpor decides whether account may sign Dpor msgs.
+func (r *Rules) CheckDpor(account common.Address) error {
+	rules, ok := r.Accounts[account]
+	if !ok {
+		return rejectf("no rules for account %s", account.Hex())
+	}
+	if !rules.AllowDporSigning {
+		return rejectf("dpor signing not allowed for account %s", account.Hex())
+	}
+	return nil
+}
+
+// CheckTx decides whether account may sign tx.
+func (r *Rules) CheckTx(account common.Address, tx *types.Transaction) error {
+	rules, ok := r.Accounts[account]
+	if !ok {
+		return rejectf("no rules for account %s", account.Hex())
+	}
+	if rules.MaxValue != nil && tx.Value().Cmp((*big.Int)(rules.MaxValue)) > 0 {
+		return rejectf("value %v exceeds the cap of %v", tx.Value(), (*big.Int)(rules.MaxValue))
+	}
+	to := tx.To()
+	if to == nil {
+		if !rules.AllowContractCreation {
+			return rejectf("contract creation not allowed")
+		}
+		return nil
+	}
+	if len(rules.AllowedRecipients) > 0 {
+		allowed := false
+		for _, recipient := range rules.AllowedRecipients {
+			if recipient == *to {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return rejectf("recipient %s not allowed", to.Hex())
+		}
+	}
+	if data := tx.Data(); len(data) > 0 {
+		if len(data) < 4 {
+			return rejectf("input data too short for a method call")
+		}
+		selector := hexutil.Encode(data[:4])
+		if !rules.selectors[*to][selector] {
+			return rejectf("method %s of %s not allowed", selector, to.Hex())
+		}
+	}
+	return nil
+}
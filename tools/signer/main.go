@@ -0,0 +1,194 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+// signer is a standalone daemon holding the keys of a node. The node connects
+// to it with --signer and every signing request is checked against the rules
+// of the signer and written to its audit log.
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"bitbucket.org/cpchain/chain/accounts"
+	"bitbucket.org/cpchain/chain/accounts/keystore"
+	"bitbucket.org/cpchain/chain/api/rpc"
+	"bitbucket.org/cpchain/chain/commons/log"
+	"bitbucket.org/cpchain/chain/configs"
+	"bitbucket.org/cpchain/chain/tools/signer/core"
+	"bitbucket.org/cpchain/chain/tools/utility"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/urfave/cli"
+)
+
+var home, homeErr = utility.Home()
+
+var flags = []cli.Flag{
+	cli.StringFlag{
+		Name:  "keystore",
+		Usage: "Keystore directory",
+		Value: filepath.Join(home, ".cpchain", "keystore"),
+	},
+	cli.StringFlag{
+		Name:  "unlock",
+		Usage: "Comma separated list of accounts to unlock",
+	},
+	cli.StringFlag{
+		Name:  "password",
+		Usage: "Password file, one line per unlocked account",
+	},
+	cli.StringFlag{
+		Name:  "rules",
+		Usage: "JSON file with the signing rules of the accounts",
+	},
+	cli.StringFlag{
+		Name:  "audit",
+		Usage: "File the requests are appended to as JSON lines",
+		Value: "signer-audit.log",
+	},
+	cli.StringFlag{
+		Name:  "ipcpath",
+		Usage: "IPC endpoint to serve the signer API on",
+		Value: filepath.Join(home, ".cpchain", "signer.ipc"),
+	},
+	cli.StringFlag{
+		Name:  "http",
+		Usage: "HTTP endpoint (host:port) to serve the signer API on, disabled if empty",
+	},
+	cli.StringFlag{
+		Name:  "vhosts",
+		Usage: "Comma separated list of virtual hostnames accepted by the HTTP endpoint",
+		Value: "localhost",
+	},
+}
+
+func newApp() *cli.App {
+	app := cli.NewApp()
+	app.Name = filepath.Base(os.Args[0])
+	app.Authors = []cli.Author{
+		{
+			Name:  "The cpchain authors",
+			Email: "info@cpchain.io",
+		},
+	}
+	app.Version = configs.Version
+	app.Copyright = "LGPL"
+	app.Usage = "External signer holding the keys of a cpchain node"
+	app.Flags = flags
+	app.Action = run
+	return app
+}
+
+func run(ctx *cli.Context) error {
+	if !ctx.IsSet("rules") {
+		return errors.New("no rules file specified (--rules)")
+	}
+	rules, err := core.LoadRules(ctx.String("rules"))
+	if err != nil {
+		return err
+	}
+	ks := keystore.NewKeyStore(ctx.String("keystore"), keystore.StandardScryptN, keystore.StandardScryptP)
+	if err := unlock(ks, ctx.String("unlock"), ctx.String("password")); err != nil {
+		return err
+	}
+	audit, err := os.OpenFile(ctx.String("audit"), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer audit.Close()
+
+	api := core.NewSignerAPI(ks, rules, core.NewAuditLog(audit))
+	apis := []rpc.API{{Namespace: core.Namespace, Version: core.Version, Service: api, Public: true}}
+
+	ipc, _, err := rpc.StartIPCEndpoint(ctx.String("ipcpath"), apis)
+	if err != nil {
+		return err
+	}
+	defer ipc.Close()
+	log.Info("Signer IPC endpoint opened", "path", ctx.String("ipcpath"))
+
+	if endpoint := ctx.String("http"); endpoint != "" {
+		if host, _, err := net.SplitHostPort(endpoint); err == nil {
+			if ip := net.ParseIP(host); host != "localhost" && (ip == nil || !ip.IsLoopback()) {
+				log.Warn("Signer HTTP endpoint is reachable from the network, prefer IPC", "endpoint", endpoint)
+			}
+		}
+		vhosts := strings.Split(ctx.String("vhosts"), ",")
+		listener, _, err := rpc.StartHTTPEndpoint(endpoint, apis, []string{core.Namespace}, nil, vhosts, nil, nil, false)
+		if err != nil {
+			return err
+		}
+		defer listener.Close()
+		log.Info("Signer HTTP endpoint opened", "url", fmt.Sprintf("http://%s", endpoint))
+	}
+	log.Info("Signer started", "accounts", len(api.List()))
+
+	sigc := make(chan os.Signal, 1)
+	signal.Notify(sigc, syscall.SIGINT, syscall.SIGTERM)
+	<-sigc
+	log.Info("Signer shutting down")
+	return nil
+}
+
+// unlock unlocks the given accounts with the passwords of the password file,
+// in the same order.
+func unlock(ks *keystore.KeyStore, list string, passwordFile string) error {
+	addresses := strings.FieldsFunc(list, func(c rune) bool { return c == ',' })
+	if len(addresses) == 0 {
+		return nil
+	}
+	if passwordFile == "" {
+		return errors.New("no password file specified (--password)")
+	}
+	text, err := ioutil.ReadFile(passwordFile)
+	if err != nil {
+		return err
+	}
+	passwords := strings.Split(string(text), "\n")
+	for i, addr := range addresses {
+		addr = strings.TrimSpace(addr)
+		if !common.IsHexAddress(addr) {
+			return fmt.Errorf("invalid account address %q", addr)
+		}
+		if i >= len(passwords) {
+			return fmt.Errorf("no password for account %s", addr)
+		}
+		account := accounts.Account{Address: common.HexToAddress(addr)}
+		if err := ks.Unlock(account, strings.TrimRight(passwords[i], "\r")); err != nil {
+			return fmt.Errorf("failed to unlock %s: %v", addr, err)
+		}
+		log.Info("Unlocked account", "address", account.Address.Hex())
+	}
+	return nil
+}
+
+func main() {
+	if homeErr != nil {
+		fmt.Fprintln(os.Stderr, homeErr)
+		os.Exit(1)
+	}
+	if err := newApp().Run(os.Args); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
@@ -93,6 +93,16 @@ func NewReceipt(root []byte, failed bool, cumulativeGasUsed uint64) *Receipt {
 	return r
 }
 
+// NewBlockReceipt creates the receipt of the logs a block emits itself while
+// it is finalized, rather than one of its transactions. It follows the
+// receipts of the transactions, has no transaction hash and uses no gas.
+func NewBlockReceipt(logs []*Log, cumulativeGasUsed uint64) *Receipt {
+	r := NewReceipt(nil, false, cumulativeGasUsed)
+	r.Logs = logs
+	r.Bloom = CreateBloom(Receipts{r})
+	return r
+}
+
 // EncodeRLP implements rlp.Encoder, and flattens the consensus fields of a receipt
 // into an RLP stream. If no post state is present, byzantium fork is assumed.
 func (r *Receipt) EncodeRLP(w io.Writer) error {